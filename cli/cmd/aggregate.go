@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// aggregateCmd represents the aggregate command
+var aggregateCmd = &cobra.Command{
+	Use:   "aggregate",
+	Short: "Inspect event-sourced aggregates",
+	Long:  `Reconstruct an event-sourced aggregate from the events of a topic that share its ID, and fold them into its current state.`,
+}
+
+// AggregateCmd returns the aggregate command for use in subcommands
+func AggregateCmd() *cobra.Command {
+	return aggregateCmd
+}
+
+func init() {
+	rootCmd.AddCommand(aggregateCmd)
+}
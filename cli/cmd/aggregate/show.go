@@ -0,0 +1,125 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/history"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	showIDField   string
+	showID        string
+	showReduce    string
+	showInitial   string
+	showStateOnly bool
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <topic> --id-field <field> --id <id>",
+	Short: "Show an aggregate's event history and current state",
+	Long: `Reconstruct an aggregate's event stream: the events of a topic whose --id-field (any
+--filter field, such as 'payload.orderId') is --id, in the order they were published.
+
+With --reduce the events are folded, oldest first, into the aggregate's current state by
+a jq expression or Go template (as for 'es event replay --transform'). It is given an
+object with the state so far as 'state' and the event (id, timestamp, type, payload) as
+'event', and returns the new state; returning nothing leaves the state as it was. The
+state starts as --initial, {} by default.
+
+The topic is read in full, unless the server filters events itself. The history is
+printed as a table followed by the state; with -o json as an object with 'events' and
+'state'; with -o csv the events only.
+
+Examples:
+  # Show the history of an order
+  es aggregate show orders --id-field payload.orderId --id 123
+
+  # Fold it into the order's current state with jq
+  es aggregate show orders --id-field payload.orderId --id 123 \
+    --reduce '.state + .event.payload + {status: .event.type, version: ((.state.version // 0) + 1)}'
+
+  # The same with a Go template, printing only the state
+  es aggregate show orders --id-field payload.orderId --id 123 --state-only \
+    --reduce '{"status": {{json .event.type}}, "items": {{json .event.payload.items}}}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topic := args[0]
+
+		if showStateOnly && showReduce == "" {
+			return fmt.Errorf("--state-only needs --reduce")
+		}
+		var initial map[string]interface{}
+		if err := json.Unmarshal([]byte(showInitial), &initial); err != nil || initial == nil {
+			return fmt.Errorf("invalid --initial: expected a JSON object")
+		}
+		var reducer *history.Reducer
+		if showReduce != "" {
+			var err error
+			if reducer, err = history.NewReducer(showReduce); err != nil {
+				return err
+			}
+			defer reducer.Close()
+		}
+
+		collector := history.New(topic, showIDField, showID)
+		query := client.EventsQuery{}
+		if apiClient.Supports(client.FeatureEventFilter) {
+			query.Filter = showIDField + ":" + showID
+		}
+		err := apiClient.ScanEventsQuery(topic, query, func(events []client.Event) (bool, error) {
+			collector.Add(events)
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+		h := collector.History()
+		if reducer != nil {
+			if err := reducer.Fold(h, initial); err != nil {
+				return err
+			}
+		}
+
+		if cmd.Quiet() {
+			ids := make([]string, len(h.Events))
+			for i, event := range h.Events {
+				ids[i] = event.ID
+			}
+			output.PrintIDs(ids)
+			return nil
+		}
+		if len(h.Events) == 0 {
+			fmt.Fprintf(os.Stderr, "No events in '%s' have %s %s\n", topic, showIDField, showID)
+		}
+		switch {
+		case showStateOnly:
+			return output.PrintJSON(h.State)
+		case cfg.Output.Format == "json":
+			return output.PrintJSON(h)
+		case cfg.Output.Format == "csv":
+			return output.PrintEventsListCSV(h.Events)
+		default:
+			output.PrintAggregateHistory(h)
+			return nil
+		}
+	},
+}
+
+func init() {
+	cmd.AggregateCmd().AddCommand(showCmd)
+	showCmd.Flags().StringVar(&showIDField, "id-field", "", "Field holding the aggregate ID, e.g. 'payload.orderId' (required)")
+	showCmd.Flags().StringVar(&showID, "id", "", "Aggregate ID (required)")
+	showCmd.Flags().StringVar(&showReduce, "reduce", "", "jq expression or Go template folding each event into the state")
+	showCmd.Flags().StringVar(&showInitial, "initial", "{}", "Initial state, a JSON object")
+	showCmd.Flags().BoolVar(&showStateOnly, "state-only", false, "Only print the state, as JSON")
+	showCmd.MarkFlagRequired("id-field")
+	showCmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// archiveCmd represents the archive command
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Query archived events",
+	Long:  `Query events that have been archived to a topic's cold tier (see 'es topic tier').`,
+}
+
+// ArchiveCmd returns the archive command for use in subcommands
+func ArchiveCmd() *cobra.Command {
+	return archiveCmd
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// assertCmd represents the assert command
+var assertCmd = &cobra.Command{
+	Use:   "assert",
+	Short: "Check the state of the event store, for CI pipelines",
+	Long:  `Check that events were published, delivered to consumers or counted on topics, waiting until the check passes or a timeout, and exit with an error if it fails.`,
+}
+
+// AssertCmd returns the assert command for use in subcommands
+func AssertCmd() *cobra.Command {
+	return assertCmd
+}
+
+func init() {
+	rootCmd.AddCommand(assertCmd)
+	DisablePager(assertCmd)
+}
@@ -0,0 +1,83 @@
+package assert
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/assert"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	receivedConsumer string
+	receivedEventID  string
+	receivedTopic    string
+	receivedFilter   string
+	receivedTimeout  time.Duration
+	receivedInterval time.Duration
+)
+
+var consumerReceivedCmd = &cobra.Command{
+	Use:   "consumer-received",
+	Short: "Check that a consumer has received an event",
+	Long: `Check that a consumer has received an event, given by --event-id or as the first event
+of --topic matching --filter. A consumer has received an event once its position on the
+event's topic, the last event delivered to it, is at or after the event. With --timeout,
+keep checking until it has or the time is up; an event matching --filter that hasn't
+been published yet is looked for again on each check.
+
+--filter joins 'field=value' (or 'field:value') terms with AND or &&; all must match.
+
+Examples:
+  # Wait up to 30 seconds for the shipping service to receive an event
+  es assert consumer-received --consumer 5f1c... --event-id orders-42 --timeout 30s
+
+  # The same for the first order created for customer 7
+  es assert consumer-received --consumer 5f1c... --topic orders \
+    --filter 'type:order.created && payload.customerId:7' --timeout 30s`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if receivedConsumer == "" {
+			return fmt.Errorf("--consumer is required")
+		}
+
+		var filters []*filter.Filter
+		expected := "received " + receivedEventID
+		switch {
+		case receivedEventID != "" && (receivedTopic != "" || receivedFilter != ""):
+			return fmt.Errorf("--event-id can't be used with --topic or --filter")
+		case receivedEventID != "":
+			if _, err := eventid.Parse(receivedEventID); err != nil {
+				return err
+			}
+		case receivedTopic != "" && receivedFilter != "":
+			var err error
+			if filters, err = filter.ParseAll(receivedFilter); err != nil {
+				return err
+			}
+			expected = "received an event matching " + receivedFilter
+		default:
+			return fmt.Errorf("--event-id, or --topic and --filter, are required")
+		}
+
+		outcome := &assert.Outcome{
+			Assertion: "consumer-received",
+			Subject:   "consumer " + receivedConsumer,
+			Expected:  expected,
+		}
+		condition := assert.ConsumerReceived(cmd.NewClient(), receivedConsumer, receivedTopic, receivedEventID, filters)
+		return poll(outcome, receivedTimeout, receivedInterval, condition)
+	},
+}
+
+func init() {
+	cmd.AssertCmd().AddCommand(consumerReceivedCmd)
+	consumerReceivedCmd.Flags().StringVar(&receivedConsumer, "consumer", "", "ID of the consumer to check (required)")
+	consumerReceivedCmd.Flags().StringVar(&receivedEventID, "event-id", "", "Event the consumer must have received")
+	consumerReceivedCmd.Flags().StringVar(&receivedTopic, "topic", "", "Topic of the event to look for with --filter")
+	consumerReceivedCmd.Flags().StringVar(&receivedFilter, "filter", "", "Filters the event must match, e.g. 'type:order.created && payload.id:42'")
+	addPollFlags(consumerReceivedCmd, &receivedTimeout, &receivedInterval)
+}
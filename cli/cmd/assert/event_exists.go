@@ -0,0 +1,111 @@
+package assert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/assert"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	existsTopic       string
+	existsFilter      string
+	existsFromEventID string
+	existsTimeout     time.Duration
+	existsInterval    time.Duration
+)
+
+var eventExistsCmd = &cobra.Command{
+	Use:   "event-exists",
+	Short: "Check that an event matching a filter has been published",
+	Long: `Check that a topic has an event matching a filter. With --timeout, keep checking until
+one is published or the time is up.
+
+--filter joins 'field=value' (or 'field:value') terms with AND or &&; all must match.
+Without --filter any event will do. To check for a number of events, see
+'es event assert'.
+
+Examples:
+  # Wait up to 30 seconds for order 42 to be created
+  es assert event-exists --topic orders --filter 'type:order.created && payload.id:42' --timeout 30s
+
+  # Only events published after a point in the test
+  es assert event-exists --topic payments --filter type:payment.settled --from-event-id payments-1200`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if existsTopic == "" {
+			return fmt.Errorf("--topic is required")
+		}
+		var filters []*filter.Filter
+		if existsFilter != "" {
+			var err error
+			if filters, err = filter.ParseAll(existsFilter); err != nil {
+				return err
+			}
+		}
+		if existsInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		apiClient := cmd.NewClient()
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+		var result *assert.Result
+		var checkErr error
+		group.Go("assert-event-exists", func(ctx context.Context) error {
+			result, checkErr = assert.Check(ctx, apiClient, assert.Options{
+				Topic:    existsTopic,
+				Since:    existsFromEventID,
+				Filters:  filters,
+				Count:    assert.Count{Op: ">=", N: 1},
+				Within:   existsTimeout,
+				Interval: existsInterval,
+			})
+			group.Stop()
+			return nil
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+		if checkErr != nil && !errors.Is(checkErr, context.Canceled) {
+			return checkErr
+		}
+
+		switch cmd.GetConfig().Output.Format {
+		case "json":
+			if err := output.PrintJSON(result); err != nil {
+				return err
+			}
+		case "csv":
+			if err := output.PrintAssertResultCSV(result); err != nil {
+				return err
+			}
+		default:
+			output.PrintAssertResult(result)
+		}
+
+		if checkErr != nil {
+			return fmt.Errorf("assertion interrupted")
+		}
+		if !result.Passed {
+			return cmd.CheckFailed(fmt.Errorf("assertion failed: no event on %s matched %s", existsTopic, result.Filter))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.AssertCmd().AddCommand(eventExistsCmd)
+	eventExistsCmd.Flags().StringVar(&existsTopic, "topic", "", "Topic to check (required)")
+	eventExistsCmd.Flags().StringVar(&existsFilter, "filter", "", "Filters the event must match, e.g. 'type:order.created && payload.id:42'")
+	eventExistsCmd.Flags().StringVar(&existsFromEventID, "from-event-id", "", "Only check events after this event ID")
+	addPollFlags(eventExistsCmd, &existsTimeout, &existsInterval)
+}
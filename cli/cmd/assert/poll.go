@@ -0,0 +1,65 @@
+package assert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/assert"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// addPollFlags adds the flags controlling how long an assertion waits
+func addPollFlags(c *cobra.Command, timeout, interval *time.Duration) {
+	c.Flags().DurationVar(timeout, "timeout", 0, "Keep checking until the assertion passes or this time is up (default: check once)")
+	c.Flags().DurationVar(interval, "interval", time.Second, "How often to check while waiting")
+}
+
+// poll checks a condition until it passes or the timeout, prints the outcome and fails
+// the command if it didn't pass
+func poll(outcome *assert.Outcome, timeout, interval time.Duration, condition assert.Condition) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	group, err := cmd.NewRunner()
+	if err != nil {
+		return err
+	}
+	var checkErr error
+	group.Go("assert-"+outcome.Assertion, func(ctx context.Context) error {
+		checkErr = assert.Poll(ctx, outcome, timeout, interval, condition)
+		group.Stop()
+		return nil
+	})
+	if err := group.Wait(); err != nil {
+		return err
+	}
+	if checkErr != nil && !errors.Is(checkErr, context.Canceled) {
+		return checkErr
+	}
+
+	switch cmd.GetConfig().Output.Format {
+	case "json":
+		if err := output.PrintJSON(outcome); err != nil {
+			return err
+		}
+	case "csv":
+		if err := output.PrintAssertOutcomeCSV(outcome); err != nil {
+			return err
+		}
+	default:
+		output.PrintAssertOutcome(outcome)
+	}
+
+	if checkErr != nil {
+		return fmt.Errorf("assertion interrupted")
+	}
+	if !outcome.Passed {
+		return cmd.CheckFailed(fmt.Errorf("assertion failed: %s: %s (expected %s)", outcome.Subject, outcome.Actual, outcome.Expected))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package assert
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/assert"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sequenceTopic    string
+	sequenceAtLeast  int64
+	sequenceTimeout  time.Duration
+	sequenceInterval time.Duration
+)
+
+var topicSequenceCmd = &cobra.Command{
+	Use:   "topic-sequence-at-least",
+	Short: "Check that a topic has had at least a number of events published",
+	Long: `Check that a topic's sequence, the number of events ever published to it, is at least
+--sequence. With --timeout, keep checking until it is or the time is up.
+
+Examples:
+  # Wait up to a minute for the import to publish 1000 events
+  es assert topic-sequence-at-least --topic orders --sequence 1000 --timeout 1m`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if sequenceTopic == "" {
+			return fmt.Errorf("--topic is required")
+		}
+		if sequenceAtLeast < 0 {
+			return fmt.Errorf("--sequence can't be negative")
+		}
+		outcome := &assert.Outcome{
+			Assertion: "topic-sequence-at-least",
+			Subject:   "topic " + sequenceTopic,
+			Expected:  "sequence >= " + strconv.FormatInt(sequenceAtLeast, 10),
+		}
+		condition := assert.SequenceAtLeast(cmd.NewClient(), sequenceTopic, sequenceAtLeast)
+		return poll(outcome, sequenceTimeout, sequenceInterval, condition)
+	},
+}
+
+func init() {
+	cmd.AssertCmd().AddCommand(topicSequenceCmd)
+	topicSequenceCmd.Flags().StringVar(&sequenceTopic, "topic", "", "Topic to check (required)")
+	topicSequenceCmd.Flags().Int64Var(&sequenceAtLeast, "sequence", 1, "Least sequence the topic must have")
+	addPollFlags(topicSequenceCmd, &sequenceTimeout, &sequenceInterval)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Review the commands that changed event stores",
+	Long:  `Review the audit log of commands that made requests able to change an event store, such as publishing events or deleting a topic, kept in ~/.es/audit.ndjson unless 'audit.file' is configured.`,
+}
+
+// AuditCmd returns the audit command for use in subcommands
+func AuditCmd() *cobra.Command {
+	return auditCmd
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
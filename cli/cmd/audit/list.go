@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/audit"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listSince   string
+	listUntil   string
+	listCommand string
+	listServer  string
+	listUser    string
+	listFailed  bool
+	listLast    int
+)
+
+// listColumns are the columns of the audit log
+var listColumns = []output.Column{
+	{Name: "time", Header: "Time"},
+	{Name: "user", Header: "User"},
+	{Name: "server", Header: "Server"},
+	{Name: "command", Header: "Command"},
+	{Name: "requests", Header: "Requests"},
+	{Name: "result", Header: "Result"},
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the recorded commands",
+	Long: `List the commands recorded in the audit log, oldest first: when each ran, who ran it,
+the server (and --context) it ran against, its command line, the requests it made that
+can change an event store, by route, and how it ended.
+
+Every command that makes requests other than GET, HEAD and OPTIONS is recorded when it
+finishes, whether it succeeded or not, unless 'audit.disable' is set in the config file.
+The values of flags holding secrets, such as tokens, are not recorded.
+
+Examples:
+  # What changed in the last day
+  es audit list --since 24h
+
+  # Failed changes to production
+  es audit list --server prod.example.com --failed
+
+  # The last 20 topic deletions, as JSON
+  es audit list --command 'topic delete' --last 20 -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if listLast < 0 {
+			return fmt.Errorf("--last can't be negative")
+		}
+		timeRange, err := timerange.Parse(listSince, listUntil, time.Now())
+		if err != nil {
+			return err
+		}
+		path, err := cmd.AuditFile()
+		if err != nil {
+			return err
+		}
+		records, err := audit.Read(path)
+		if err != nil {
+			return err
+		}
+
+		var selected []*audit.Record
+		for _, record := range records {
+			if !timeRange.Contains(record.Time.Format(time.RFC3339Nano)) ||
+				!strings.Contains(record.Command, listCommand) ||
+				!strings.Contains(record.Server, listServer) ||
+				(listUser != "" && record.User != listUser) ||
+				(listFailed && record.ExitCode == 0) {
+				continue
+			}
+			selected = append(selected, record)
+		}
+		if listLast > 0 && len(selected) > listLast {
+			selected = selected[len(selected)-listLast:]
+		}
+
+		listing := output.NewListing(listColumns)
+		for _, record := range selected {
+			server := record.Server
+			if record.Context != "" {
+				server = record.Context + " (" + record.Server + ")"
+			}
+			result := "ok"
+			if record.ExitCode != 0 {
+				result = "exit " + strconv.Itoa(record.ExitCode)
+				if record.Error != "" {
+					result += ": " + record.Error
+				}
+			}
+			timestamp := record.Time.Local().Format(time.RFC3339)
+			listing.Add(timestamp, record, timestamp, record.User, server, record.Command, describeRequests(record.Requests), result)
+		}
+		return cmd.PrintListing("records", listing)
+	},
+}
+
+// describeRequests summarises a command's requests, e.g. "POST /topics/{topic}/events x3
+// (1 error)"
+func describeRequests(requests []audit.Request) string {
+	parts := make([]string, len(requests))
+	for i, request := range requests {
+		part := request.Endpoint
+		if request.Count > 1 {
+			part += " x" + strconv.Itoa(request.Count)
+		}
+		var problems []string
+		if request.Errors > 0 {
+			problems = append(problems, fmt.Sprintf("%d error(s)", request.Errors))
+		}
+		if request.Failures > 0 {
+			problems = append(problems, fmt.Sprintf("%d without response", request.Failures))
+		}
+		if len(problems) > 0 {
+			part += " (" + strings.Join(problems, ", ") + ")"
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	cmd.AuditCmd().AddCommand(listCmd)
+	listCmd.Flags().StringVar(&listSince, "since", "", "Only commands run at or after this time: timestamp, date, 'today', 'yesterday' or a duration ago such as '2h' or '7d'")
+	listCmd.Flags().StringVar(&listUntil, "until", "", "Only commands run before this time (same formats as --since)")
+	listCmd.Flags().StringVar(&listCommand, "command", "", "Only commands whose command line contains this, e.g. 'topic delete'")
+	listCmd.Flags().StringVar(&listServer, "server", "", "Only commands run against servers whose URL contains this")
+	listCmd.Flags().StringVar(&listUser, "user", "", "Only commands run by this user")
+	listCmd.Flags().BoolVar(&listFailed, "failed", false, "Only commands that failed")
+	listCmd.Flags().IntVar(&listLast, "last", 0, "Only the last n matching commands")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/audit"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/hooks"
+)
+
+// auditBaseline is the client's requests when the running command started, so the
+// requests it makes can be told apart, or nil before it starts
+var auditBaseline *client.MetricsSnapshot
+
+// startAudit notes the requests made before the running command starts
+func startAudit() {
+	snapshot := metrics.Snapshot()
+	auditBaseline = &snapshot
+}
+
+// recordAudit records the running command in the audit log, and sends the record to the
+// configured endpoint, if it made requests that can change an event store. Failing to
+// record it is a warning: the command has already run.
+func recordAudit(err error, code int) {
+	if auditBaseline == nil || cfg == nil || cfg.Audit.Disable {
+		return
+	}
+	requests := audit.Changes(*auditBaseline, metrics.Snapshot())
+	if len(requests) == 0 {
+		return
+	}
+
+	host, _ := os.Hostname()
+	username := os.Getenv("USER")
+	if current, err := user.Current(); username == "" && err == nil {
+		username = current.Username
+	}
+	record := &audit.Record{
+		Time:     time.Now().UTC(),
+		User:     username,
+		Host:     host,
+		Context:  contextName,
+		Server:   strings.TrimSuffix(cfg.Server.URL, "/"),
+		Command:  "es " + strings.Join(hooks.RedactArgs(commandLine), " "),
+		Requests: requests,
+		ExitCode: code,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	path, fileErr := AuditFile()
+	if fileErr == nil {
+		fileErr = audit.Append(path, record)
+	}
+	if fileErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", fileErr)
+	}
+	if cfg.Audit.Endpoint != "" {
+		if sendErr := audit.Send(cfg.Audit.Endpoint, cfg.Audit.Headers, record); sendErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", sendErr)
+		}
+	}
+}
+
+// AuditFile returns the audit log: audit.file from the config file, or ~/.es/audit.ndjson
+func AuditFile() (string, error) {
+	if cfg.Audit.File != "" {
+		return cfg.Audit.File, nil
+	}
+	return audit.DefaultFile()
+}
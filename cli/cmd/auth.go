@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/event-store/cli/internal/auth"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Log in to event stores behind an identity provider",
+	Long: `Log in to, and out of, event stores configured with an OAuth2 or OpenID Connect identity
+provider under 'auth' in the config file, and show who you are logged in as. Tokens are
+kept in the OS keyring, and access tokens are refreshed as they expire.`,
+}
+
+// AuthCmd returns the auth command for use in subcommands
+func AuthCmd() *cobra.Command {
+	return authCmd
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+}
+
+var (
+	// authSessions are the sessions of servers logged in to, loaded from the keyring
+	// once each, and nil for servers that weren't
+	authSessions   = map[string]*auth.Session{}
+	authSessionsMu sync.Mutex
+)
+
+// ServerAuth returns the server commands run against and how to log in to it
+func ServerAuth() (string, config.AuthConfig, error) {
+	server := strings.TrimSuffix(cfg.Server.URL, "/")
+	authConfig, ok := cfg.Auth(server)
+	if !ok {
+		return "", config.AuthConfig{}, fmt.Errorf("%s has no identity provider configured (set 'auth' for it in the config file)", server)
+	}
+	return server, authConfig, nil
+}
+
+// NewAuthProvider returns the identity provider of a server, reached through the same
+// proxy as the server
+func NewAuthProvider(authConfig config.AuthConfig) *auth.Provider {
+	// The proxy was checked when the config was loaded
+	transport, _ := client.NewHTTPTransport(cfg.HTTP.Proxy)
+	return auth.NewProvider(authConfig, transport)
+}
+
+// SetAuthSession starts or, with a nil session, ends the session of a server for the
+// clients created from now on
+func SetAuthSession(server string, session *auth.Session) {
+	authSessionsMu.Lock()
+	defer authSessionsMu.Unlock()
+	authSessions[strings.TrimSuffix(server, "/")] = session
+}
+
+// useAuth sends the access tokens of a server that was logged in to with its client
+func useAuth(apiClient *client.Client, serverURL string) {
+	server := strings.TrimSuffix(serverURL, "/")
+	authConfig, ok := cfg.Auth(server)
+	if !ok {
+		apiClient.SetAuth(nil)
+		return
+	}
+
+	authSessionsMu.Lock()
+	session, loaded := authSessions[server]
+	if !loaded {
+		token, err := auth.LoadToken(server)
+		switch {
+		case err == nil:
+			session = auth.NewSession(server, NewAuthProvider(authConfig), token)
+		case !auth.IsNotLoggedIn(err):
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		authSessions[server] = session
+	}
+	authSessionsMu.Unlock()
+
+	// A nil *auth.Session isn't a nil client.Authenticator
+	if session == nil {
+		apiClient.SetAuth(nil)
+		return
+	}
+	apiClient.SetAuth(session)
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/auth"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	loginUsername      string
+	loginPasswordStdin bool
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to the event store's identity provider",
+	Long: `Log in to the identity provider of the server (or --context) that commands run against,
+as configured under 'auth' for it in the config file, and keep its tokens in the OS
+keyring. Commands then send the access token with each request, refreshing it with the
+refresh token as it expires, until 'es auth logout'.
+
+By default the device code flow is used: open the URL shown in a browser, on this or any
+other device, and enter the code. With --username, the password is asked for instead,
+or read from stdin with --password-stdin, for providers that allow it.
+
+Examples:
+  # Log in with a browser
+  es auth login --context prod
+
+  # Log in with a username and password
+  es auth login --username alice
+
+  # Log in from a script
+  echo "$ES_PASSWORD" | es auth login --username ci-bot --password-stdin`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if loginPasswordStdin && loginUsername == "" {
+			return fmt.Errorf("--password-stdin needs --username")
+		}
+		server, authConfig, err := cmd.ServerAuth()
+		if err != nil {
+			return err
+		}
+		provider := cmd.NewAuthProvider(authConfig)
+
+		var password string
+		if loginUsername != "" {
+			if password, err = readPassword(); err != nil {
+				return err
+			}
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+		var token *auth.Token
+		var loginErr error
+		group.Go("auth-login", func(ctx context.Context) error {
+			if loginUsername != "" {
+				token, loginErr = provider.PasswordLogin(ctx, loginUsername, password)
+			} else {
+				token, loginErr = provider.DeviceLogin(ctx, showDeviceCode)
+			}
+			group.Stop()
+			return nil
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+		if loginErr != nil {
+			return loginErr
+		}
+
+		if err := auth.SaveToken(server, token); err != nil {
+			return err
+		}
+		cmd.SetAuthSession(server, auth.NewSession(server, provider, token))
+		if !cmd.Quiet() {
+			fmt.Printf("Logged in to %s as %s\n", server, token.Identity(authConfig.ClientID).User)
+		}
+		if auth.Storage() == "file" && os.Getenv("ES_KEYRING") != "file" {
+			fmt.Fprintln(os.Stderr, "Warning: no OS keyring was found, so the tokens are kept unencrypted in ~/.es/tokens.json, readable only by you (set ES_KEYRING=file to keep them there without this warning)")
+		}
+		return nil
+	},
+}
+
+// readPassword reads the password for --username from stdin with --password-stdin, and
+// otherwise asks for it on the terminal
+func readPassword() (string, error) {
+	if loginPasswordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no terminal to ask for the password on; pass it with --password-stdin")
+	}
+	fmt.Fprintf(os.Stderr, "Password for %s: ", loginUsername)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return string(password), nil
+}
+
+// showDeviceCode tells the user where to log in, on stderr so stdout only has the result
+func showDeviceCode(code auth.DeviceCode) {
+	fmt.Fprintf(os.Stderr, "Open %s in a browser and enter the code %s\n", code.VerificationURI, code.UserCode)
+	if code.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "or open %s\n", code.VerificationURIComplete)
+	}
+	fmt.Fprintf(os.Stderr, "Waiting for you to log in (the code expires at %s)...\n", code.Expires.Local().Format("15:04:05"))
+}
+
+func init() {
+	cmd.AuthCmd().AddCommand(loginCmd)
+	cmd.DisablePager(loginCmd)
+	loginCmd.Flags().StringVar(&loginUsername, "username", "", "Log in with this username and a password, rather than in a browser")
+	loginCmd.Flags().BoolVar(&loginPasswordStdin, "password-stdin", false, "Read the password for --username from stdin")
+}
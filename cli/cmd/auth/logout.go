@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Log out of the event store",
+	Long: `Forget the tokens of the server (or --context) that commands run against, removing them
+from the OS keyring. Commands then send no access token to it until 'es auth login'.
+
+Examples:
+  es auth logout --context prod`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		server, _, err := cmd.ServerAuth()
+		if err != nil {
+			return err
+		}
+		err = auth.DeleteToken(server)
+		if auth.IsNotLoggedIn(err) {
+			return fmt.Errorf("not logged in to %s", server)
+		}
+		if err != nil {
+			return err
+		}
+		cmd.SetAuthSession(server, nil)
+		if !cmd.Quiet() {
+			fmt.Printf("Logged out of %s\n", server)
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.AuthCmd().AddCommand(logoutCmd)
+}
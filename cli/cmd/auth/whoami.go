@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/auth"
+	"github.com/event-store/cli/internal/config"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// whoamiColumns are the columns of who you are logged in as
+var whoamiColumns = []output.Column{
+	{Name: "server", Header: "Server"},
+	{Name: "user", Header: "User"},
+	{Name: "roles", Header: "Roles"},
+	{Name: "scopes", Header: "Scopes"},
+	{Name: "expires", Header: "Token Expires"},
+}
+
+// whoami is who you are logged in to a server as
+type whoami struct {
+	Server string `json:"server"`
+	auth.Identity
+}
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show who you are logged in as",
+	Long: `Show who you are logged in to the server (or --context) that commands run against as,
+with the roles and scopes your access token gives you, which the server checks requests
+against, and when the token expires. An expired token is refreshed first.
+
+The roles are read from the token's roles, groups and permissions claims, and from
+Keycloak's realm and client roles.
+
+Examples:
+  # Who am I on production?
+  es auth whoami --context prod
+
+  # Do I have the admin role?
+  es auth whoami -o json | jq '.identities[0].roles | index("admin") != null'`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		server, authConfig, err := cmd.ServerAuth()
+		if err != nil {
+			return err
+		}
+		token, err := currentToken(server, authConfig)
+		if err != nil {
+			return err
+		}
+
+		identity := whoami{Server: server, Identity: token.Identity(authConfig.ClientID)}
+		expires := ""
+		if !identity.Expires.IsZero() {
+			expires = identity.Expires.Local().Format(time.RFC3339)
+		}
+		listing := output.NewListing(whoamiColumns)
+		listing.Add(identity.User, identity, server, identity.User, strings.Join(identity.Roles, ", "), strings.Join(identity.Scopes, " "), expires)
+		return cmd.PrintListing("identities", listing)
+	},
+}
+
+// currentToken returns a server's tokens, refreshed if the access token has expired
+func currentToken(server string, authConfig config.AuthConfig) (*auth.Token, error) {
+	token, err := auth.LoadToken(server)
+	if auth.IsNotLoggedIn(err) {
+		return nil, fmt.Errorf("not logged in to %s; log in with 'es auth login'", server)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewSession(server, cmd.NewAuthProvider(authConfig), token).Current()
+}
+
+func init() {
+	cmd.AuthCmd().AddCommand(whoamiCmd)
+}
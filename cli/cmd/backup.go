@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Check and decrypt backups of events",
+	Long:  `Check exports written by 'es event export' and topic archives written by 'es topic archive' or 'es topic tier push' against their manifests, and decrypt encrypted exports.`,
+}
+
+// BackupCmd returns the backup command for use in subcommands
+func BackupCmd() *cobra.Command {
+	return backupCmd
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,113 @@
+package backup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/seal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	decryptOut            string
+	decryptPassphraseFile string
+	decryptIdentities     []string
+)
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <file>",
+	Short: "Decrypt an encrypted export",
+	Long: `Decrypt a file written by 'es event export --encrypt', with the passphrase it was
+encrypted with or the age identity of one of its recipients, to --out or stdout. The
+file is an age file, so age-encrypted files from elsewhere decrypt the same way.
+
+The file is checked as it is decrypted: decryption fails if it was changed or cut short,
+and --out is removed rather than left with part of the events.
+
+Examples:
+  # Decrypt an export encrypted with a passphrase
+  es backup decrypt orders.ndjson --passphrase-file ~/.es/backup.pass --out orders-plain.ndjson
+
+  # Read an export encrypted for an age recipient
+  es backup decrypt orders.ndjson --identity ~/.age/key.txt | jq .payload`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		keys, err := loadKeys(decryptPassphraseFile, decryptIdentities)
+		if err != nil {
+			return err
+		}
+		if keys == nil {
+			return fmt.Errorf("decrypting needs a --passphrase-file or an --identity")
+		}
+
+		in, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		plain, err := seal.NewReader(in, keys)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		if decryptOut == "" {
+			_, err := io.Copy(os.Stdout, plain)
+			return err
+		}
+
+		out, err := os.OpenFile(decryptOut, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		buffered := bufio.NewWriter(out)
+		_, err = io.Copy(buffered, plain)
+		if err == nil {
+			err = buffered.Flush()
+		}
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(decryptOut)
+			return err
+		}
+		if !cmd.Quiet() {
+			fmt.Fprintf(os.Stderr, "Decrypted %s to %s\n", args[0], decryptOut)
+		}
+		return nil
+	},
+}
+
+// loadKeys loads a passphrase file and age identity files to decrypt with, or returns
+// nil without either
+func loadKeys(passphraseFile string, identityFiles []string) (*seal.Keys, error) {
+	if passphraseFile == "" && len(identityFiles) == 0 {
+		return nil, nil
+	}
+	keys := &seal.Keys{}
+	if passphraseFile != "" {
+		passphrase, err := seal.ReadPassphrase(passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		keys.Passphrases = append(keys.Passphrases, passphrase)
+	}
+	for _, path := range identityFiles {
+		identities, err := seal.ReadIdentities(path)
+		if err != nil {
+			return nil, err
+		}
+		keys.Identities = append(keys.Identities, identities...)
+	}
+	return keys, nil
+}
+
+func init() {
+	cmd.BackupCmd().AddCommand(decryptCmd)
+	decryptCmd.Flags().StringVar(&decryptOut, "out", "", "File to write the decrypted export to (default: stdout)")
+	decryptCmd.Flags().StringVar(&decryptPassphraseFile, "passphrase-file", "", "File whose first line is the passphrase the export was encrypted with")
+	decryptCmd.Flags().StringSliceVar(&decryptIdentities, "identity", nil, "age identity file, as written by age-keygen, of a recipient the export was encrypted for (repeatable)")
+}
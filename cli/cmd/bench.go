@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark the event store",
+	Long:  `Generate load against the event store and report throughput, latency and errors for capacity planning.`,
+}
+
+// BenchCmd returns the bench command for use in subcommands
+func BenchCmd() *cobra.Command {
+	return benchCmd
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	DisablePager(benchCmd)
+}
@@ -0,0 +1,103 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	consumeTopic    string
+	consumeType     string
+	consumeCount    int
+	consumeRate     string
+	consumePort     int
+	consumeCallback string
+	consumeTimeout  time.Duration
+)
+
+var consumeCmd = &cobra.Command{
+	Use:   "consume",
+	Short: "Benchmark end-to-end delivery latency",
+	Long: `Measure publish-to-webhook delivery latency and loss. The benchmark starts an
+embedded listener, registers a temporary consumer pointing at it, publishes timestamped
+probe events and reports how long each probe took to be delivered. The consumer is
+unregistered when the benchmark finishes.
+
+Each probe has the payload {"probeId": <run id>, "seq": <n>, "sentAt": <timestamp>}, so
+the topic needs a schema for the probe event type that accepts it. The event store must be
+able to reach the listener at --callback.
+
+Examples:
+  # Send 200 probes at 20 per second
+  es bench consume --topic bench-events --count 200 --rate 20/s
+
+  # Event store running in Docker
+  es bench consume --topic bench-events --callback http://host.docker.internal:19001/`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		rate, err := bench.ParseRate(consumeRate)
+		if err != nil {
+			return err
+		}
+		if consumeCount < 1 {
+			return fmt.Errorf("count must be at least 1")
+		}
+
+		callback := consumeCallback
+		if callback == "" {
+			callback = fmt.Sprintf("http://localhost:%d/", consumePort)
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		if cfg.Output.Format == "table" {
+			fmt.Fprintf(os.Stderr, "Sending %d probe(s) to '%s', listening at %s...\n", consumeCount, consumeTopic, callback)
+		}
+
+		report, err := bench.RunConsume(group, apiClient, bench.ConsumeOptions{
+			Topic:    consumeTopic,
+			Type:     consumeType,
+			Count:    consumeCount,
+			Rate:     rate,
+			Port:     consumePort,
+			Callback: callback,
+			Timeout:  consumeTimeout,
+		})
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintBenchReportJSON(report)
+		case "csv":
+			return output.PrintBenchReportCSV(report)
+		default:
+			output.PrintBenchReport(report)
+			return nil
+		}
+	},
+}
+
+func init() {
+	cmd.BenchCmd().AddCommand(consumeCmd)
+	consumeCmd.Flags().StringVar(&consumeTopic, "topic", "", "Topic to publish probes to (required)")
+	consumeCmd.Flags().StringVar(&consumeType, "type", "bench.probe", "Event type of the probe events")
+	consumeCmd.Flags().IntVar(&consumeCount, "count", 100, "Number of probes to publish")
+	consumeCmd.Flags().StringVar(&consumeRate, "rate", "10/s", "Probe rate, e.g. '10/s' (0 = unlimited)")
+	consumeCmd.Flags().IntVarP(&consumePort, "port", "p", 19001, "Port for the embedded listener")
+	consumeCmd.Flags().StringVar(&consumeCallback, "callback", "", "Callback URL the event store uses to reach the listener (default: http://localhost:<port>/)")
+	consumeCmd.Flags().DurationVar(&consumeTimeout, "timeout", 30*time.Second, "How long to wait for deliveries after the last probe")
+	consumeCmd.MarkFlagRequired("topic")
+}
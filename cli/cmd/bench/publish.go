@@ -0,0 +1,106 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	publishTopic       string
+	publishType        string
+	publishRate        string
+	publishWorkers     int
+	publishDuration    time.Duration
+	publishCount       int
+	publishPayloadSize string
+	publishBatchSize   int
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Benchmark event publishing",
+	Long: `Publish synthetic events at a configurable rate and concurrency, then report
+throughput, latency percentiles and error rates.
+
+Each event has the payload {"seq": <n>, "sentAt": <timestamp>, "data": <padding>}, so the
+topic needs a schema for the benchmark event type that accepts it. Press Ctrl+C to stop
+early and still get a report.
+
+Examples:
+  # Publish 500 requests per second from 16 workers for 2 minutes
+  es bench publish --topic bench-events --rate 500/s --workers 16 --duration 2m --payload-size 1kb
+
+  # Publish 10000 requests as fast as possible, 10 events per request
+  es bench publish --topic bench-events --count 10000 --batch-size 10 --output json`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		rate, err := bench.ParseRate(publishRate)
+		if err != nil {
+			return err
+		}
+		payloadSize, err := bench.ParseSize(publishPayloadSize)
+		if err != nil {
+			return err
+		}
+		if publishWorkers < 1 {
+			return fmt.Errorf("workers must be at least 1")
+		}
+		if publishDuration <= 0 && publishCount <= 0 {
+			return fmt.Errorf("either --duration or --count must be provided")
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		if cfg.Output.Format == "table" {
+			fmt.Fprintf(os.Stderr, "Publishing to '%s' with %d worker(s)...\n", publishTopic, publishWorkers)
+		}
+
+		report, err := bench.RunPublish(group, apiClient, bench.PublishOptions{
+			Topic:       publishTopic,
+			Type:        publishType,
+			Rate:        rate,
+			Workers:     publishWorkers,
+			Duration:    publishDuration,
+			Count:       publishCount,
+			PayloadSize: payloadSize,
+			BatchSize:   publishBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintBenchReportJSON(report)
+		case "csv":
+			return output.PrintBenchReportCSV(report)
+		default:
+			output.PrintBenchReport(report)
+			return nil
+		}
+	},
+}
+
+func init() {
+	cmd.BenchCmd().AddCommand(publishCmd)
+	publishCmd.Flags().StringVar(&publishTopic, "topic", "", "Topic to publish to (required)")
+	publishCmd.Flags().StringVar(&publishType, "type", "bench.event", "Event type of the synthetic events")
+	publishCmd.Flags().StringVar(&publishRate, "rate", "0", "Target request rate, e.g. '500/s' or '30000/m' (0 = unlimited)")
+	publishCmd.Flags().IntVar(&publishWorkers, "workers", 4, "Number of concurrent publishers")
+	publishCmd.Flags().DurationVar(&publishDuration, "duration", 30*time.Second, "How long to run (0 = until --count is reached)")
+	publishCmd.Flags().IntVar(&publishCount, "count", 0, "Total number of requests to send (0 = no limit)")
+	publishCmd.Flags().StringVar(&publishPayloadSize, "payload-size", "256b", "Size of the padding in each payload, e.g. '512b' or '1kb'")
+	publishCmd.Flags().IntVar(&publishBatchSize, "batch-size", 1, "Number of events per publish request")
+	publishCmd.MarkFlagRequired("topic")
+}
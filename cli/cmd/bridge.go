@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// bridgeCmd represents the bridge command
+var bridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Bridge topics to a message bus",
+	Long:  `Continuously send events from the event store to a message bus such as Kafka or NATS, and ingest the bus's messages as events.`,
+}
+
+// BridgeCmd returns the bridge command for use in subcommands
+func BridgeCmd() *cobra.Command {
+	return bridgeCmd
+}
+
+func init() {
+	rootCmd.AddCommand(bridgeCmd)
+	DisablePager(bridgeCmd)
+}
@@ -0,0 +1,144 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bridge"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bridgeMaps        []string
+	bridgeIngests     []string
+	bridgeKeys        []string
+	bridgeDefaultType string
+	bridgeSkipInvalid bool
+	bridgeStart       string
+	bridgeCheckpoint  string
+	bridgeInterval    time.Duration
+	bridgeBatchSize   int
+	bridgeSilent      bool
+)
+
+// addBridgeFlags adds the flags every bridge has, with a default checkpoint file name
+func addBridgeFlags(c *cobra.Command, checkpoint string) {
+	c.Flags().StringArrayVar(&bridgeMaps, "map", nil, "Send an event store topic to the bus, format 'topic:target' or 'topic' for the same name (repeatable)")
+	c.Flags().StringArrayVar(&bridgeIngests, "ingest", nil, "Publish a bus topic's messages to an event store topic, format 'source:topic' or 'topic' (repeatable)")
+	c.Flags().StringArrayVar(&bridgeKeys, "key", nil, "Payload field giving sent messages' keys, e.g. 'payload.customerId', or 'topic=field' for one topic (repeatable)")
+	c.Flags().StringVar(&bridgeDefaultType, "default-type", "", "Event type of ingested messages that carry none")
+	c.Flags().BoolVar(&bridgeSkipInvalid, "skip-invalid", false, "Skip ingested messages that are not events or that the event store rejects, instead of stopping at them")
+	c.Flags().StringVar(&bridgeStart, "start", "earliest", "Where topics without a checkpoint start: earliest or latest")
+	c.Flags().StringVar(&bridgeCheckpoint, "checkpoint", checkpoint, "File recording how far each topic has been bridged")
+	c.Flags().DurationVar(&bridgeInterval, "interval", 5*time.Second, "How often to check for new events and messages")
+	c.Flags().IntVar(&bridgeBatchSize, "batch-size", 100, "Events or messages per request")
+	c.Flags().BoolVar(&bridgeSilent, "silent", false, "Suppress progress output to stdout")
+}
+
+// runBridge checks the bridge flags, then runs a bridge to the bus open connects to until
+// stopped
+func runBridge(open func() (bridge.Bus, error)) error {
+	if len(bridgeMaps) == 0 && len(bridgeIngests) == 0 {
+		return fmt.Errorf("nothing to bridge (use --map and/or --ingest)")
+	}
+	if bridgeInterval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if bridgeBatchSize < 1 {
+		return fmt.Errorf("batch-size must be at least 1")
+	}
+	if bridgeStart != "earliest" && bridgeStart != "latest" {
+		return fmt.Errorf("invalid --start '%s' (expected earliest or latest)", bridgeStart)
+	}
+	out, err := bridge.ParseMappings("--map", bridgeMaps)
+	if err != nil {
+		return err
+	}
+	in, err := bridge.ParseMappings("--ingest", bridgeIngests)
+	if err != nil {
+		return err
+	}
+	keys := map[string]string{}
+	for _, spec := range bridgeKeys {
+		topic, field, found := strings.Cut(spec, "=")
+		if !found {
+			topic, field = "", spec
+		}
+		if field == "" {
+			return fmt.Errorf("invalid --key '%s' (expected 'field' or 'topic=field')", spec)
+		}
+		keys[topic] = field
+	}
+
+	bus, err := open()
+	if err != nil {
+		return err
+	}
+	defer bus.Close()
+
+	server := strings.TrimSuffix(cmd.GetConfig().Server.URL, "/")
+	checkpoint, err := bridge.LoadCheckpoint(bridgeCheckpoint)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Server != "" && (checkpoint.Server != server || checkpoint.Bus != bus.Name()) {
+		return fmt.Errorf("checkpoint %s is for bridging %s and %s; use another --checkpoint file", bridgeCheckpoint, checkpoint.Server, checkpoint.Bus)
+	}
+	checkpoint.Server, checkpoint.Bus = server, bus.Name()
+
+	b := &bridge.Bridge{
+		Store:          cmd.NewClient(),
+		Server:         server,
+		Bus:            bus,
+		Out:            out,
+		In:             in,
+		Keys:           keys,
+		DefaultType:    bridgeDefaultType,
+		SkipInvalid:    bridgeSkipInvalid,
+		Start:          bridgeStart,
+		BatchSize:      bridgeBatchSize,
+		Checkpoint:     checkpoint,
+		CheckpointPath: bridgeCheckpoint,
+		Report: func(direction string, mapping bridge.Mapping, moved int, err error) {
+			timestamp := time.Now().Format(time.RFC3339)
+			name := mapping.From + " -> " + mapping.To
+			switch {
+			case direction == "skip":
+				fmt.Fprintf(os.Stderr, "[%s] %s: skipped: %v\n", timestamp, name, err)
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "[%s] %s: %v\n", timestamp, name, err)
+			case bridgeSilent:
+			case direction == "out":
+				fmt.Printf("[%s] %s: sent %d event(s)\n", timestamp, name, moved)
+			default:
+				fmt.Printf("[%s] %s: ingested %d event(s)\n", timestamp, name, moved)
+			}
+		},
+	}
+
+	group, err := cmd.NewRunner()
+	if err != nil {
+		return err
+	}
+	if !bridgeSilent {
+		fmt.Printf("Bridging %s and %s every %s\n", server, bus.Name(), bridgeInterval)
+		for _, mapping := range out {
+			fmt.Printf("  %s -> %s\n", mapping.From, mapping.To)
+		}
+		for _, mapping := range in {
+			fmt.Printf("  %s <- %s\n", mapping.To, mapping.From)
+		}
+		fmt.Printf("Checkpoint: %s\n", bridgeCheckpoint)
+		fmt.Println("Press Ctrl+C to stop")
+		fmt.Println()
+	}
+
+	group.Go("bridge", func(ctx context.Context) error {
+		return b.Run(ctx, bridgeInterval)
+	})
+	return group.Wait()
+}
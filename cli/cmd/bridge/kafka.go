@@ -0,0 +1,94 @@
+package bridge
+
+import (
+	"crypto/tls"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bridge"
+	"github.com/event-store/cli/internal/kafka"
+	"github.com/spf13/cobra"
+)
+
+var (
+	kafkaBrokers      []string
+	kafkaClientID     string
+	kafkaTLS          bool
+	kafkaTLSInsecure  bool
+	kafkaSASLUser     string
+	kafkaSASLPassword string
+)
+
+var kafkaCmd = &cobra.Command{
+	Use:   "kafka --brokers <host:port> [--map <topic:kafka-topic>]... [--ingest <kafka-topic:topic>]...",
+	Short: "Bridge topics to and from Kafka",
+	Long: `Send the events of event store topics to Kafka topics with --map, and publish the
+messages of Kafka topics to event store topics with --ingest, until stopped. New events
+and messages are checked for every --interval.
+
+Each event is sent as a JSON object with its id, topic, type, timestamp and payload,
+with es-event-id, es-event-type, es-topic and es-server headers. --key takes the
+message key from a field of the event (as for 'es event list --filter'), so events with
+the same key keep their order in one partition; without it messages have no key.
+
+Ingested messages must be JSON objects. One with a string "type" and an object
+"payload", such as a message sent by a bridge, is published as that event; any other
+object is published as the payload of an event typed by its es-event-type header or
+--default-type. The event store validates events against the topic's schemas as usual.
+A message that can't be published stops its topic until the bridge is restarted, unless
+--skip-invalid is given. Messages sent by a bridge from the same event store are not
+ingested back, so a topic can be bridged both ways.
+
+Progress is recorded in a checkpoint file after every batch, so a restarted bridge
+carries on where it stopped; topics without a checkpoint start from their earliest
+events and messages, or only new ones with --start latest. Delivery is at least once in
+both directions: a batch sent just before a crash can be sent again. Kafka partition
+offsets are kept in the checkpoint file, not committed to a consumer group.
+
+Messages are produced uncompressed, waiting for all in-sync replicas. Fetched messages
+may be uncompressed or gzip-compressed.
+
+The SASL password is read from --sasl-password or the KAFKA_SASL_PASSWORD environment
+variable.
+
+Examples:
+  # Send orders and payments to Kafka, keyed by customer
+  es bridge kafka --brokers localhost:9092 --map orders:shop.orders --map payments:shop.payments --key payload.customerId
+
+  # Ingest a Kafka topic into the event store
+  es bridge kafka --brokers kafka-1:9092,kafka-2:9092 --ingest shop.returns:returns --default-type return.requested
+
+  # Over TLS with SASL/PLAIN
+  KAFKA_SASL_PASSWORD=secret es bridge kafka --brokers broker:9093 --tls --sasl-user es --map orders`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		config := kafka.Config{
+			Brokers:  kafkaBrokers,
+			ClientID: kafkaClientID,
+			Username: kafkaSASLUser,
+			Password: kafkaSASLPassword,
+		}
+		if config.Password == "" {
+			config.Password = os.Getenv("KAFKA_SASL_PASSWORD")
+		}
+		if kafkaTLS || kafkaTLSInsecure {
+			config.TLS = &tls.Config{InsecureSkipVerify: kafkaTLSInsecure}
+		}
+
+		return runBridge(func() (bridge.Bus, error) {
+			return bridge.NewKafka(config, bridgeStart)
+		})
+	},
+}
+
+func init() {
+	cmd.BridgeCmd().AddCommand(kafkaCmd)
+	kafkaCmd.Flags().StringSliceVar(&kafkaBrokers, "brokers", nil, "Kafka bootstrap brokers, host:port (comma-separated or repeatable, required)")
+	kafkaCmd.Flags().StringVar(&kafkaClientID, "client-id", "es", "Client ID sent to the brokers")
+	kafkaCmd.Flags().BoolVar(&kafkaTLS, "tls", false, "Connect to the brokers over TLS")
+	kafkaCmd.Flags().BoolVar(&kafkaTLSInsecure, "tls-insecure", false, "Connect over TLS without verifying the brokers' certificates")
+	kafkaCmd.Flags().StringVar(&kafkaSASLUser, "sasl-user", "", "Authenticate with SASL/PLAIN as this user")
+	kafkaCmd.Flags().StringVar(&kafkaSASLPassword, "sasl-password", "", "SASL/PLAIN password (default: $KAFKA_SASL_PASSWORD)")
+	addBridgeFlags(kafkaCmd, "es-bridge-kafka.json")
+	kafkaCmd.MarkFlagRequired("brokers")
+}
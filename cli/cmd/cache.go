@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// noCache is --no-cache: don't use the topic metadata cache configured under 'cache'
+var noCache bool
+
+var unsafeCacheChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached topic metadata",
+	Long:  `Manage the topic metadata commands share through ~/.es/cache when 'cache.disk' is set in the config file.`,
+}
+
+// CacheCmd returns the cache command for use in subcommands
+func CacheCmd() *cobra.Command {
+	return cacheCmd
+}
+
+// CacheDir returns the directory cached topic metadata is shared through, ~/.es/cache
+func CacheDir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache"), nil
+}
+
+// useTopicCache caches a client's topic metadata as the config file's 'cache' section
+// says, unless --no-cache is given or the command is re-run with --watch, which shows
+// the topics as they are now
+func useTopicCache(apiClient *client.Client, serverURL string) {
+	if noCache || Watching() || cfg.Cache.TTL <= 0 {
+		return
+	}
+	apiClient.SetTopicCache(cfg.Cache.TTL)
+	if !cfg.Cache.Disk {
+		return
+	}
+	dir, err := CacheDir()
+	if err != nil {
+		return
+	}
+	name := serverURL
+	if u, err := url.Parse(serverURL); err == nil && u.Host != "" {
+		name = u.Host
+	}
+	apiClient.SetTopicCacheFile(filepath.Join(dir, "topics-"+unsafeCacheChars.ReplaceAllString(name, "_")+".json"))
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/spf13/cobra"
+)
+
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete cached topic metadata",
+	Long: `Delete the topic metadata cached under ~/.es/cache, so the next commands fetch topics
+from the server. Useful after topics were changed by someone else, rather than waiting
+for 'cache.ttl' to pass.
+
+Examples:
+  es cache clear`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		dir, err := cmd.CacheDir()
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		if !cmd.Quiet() {
+			fmt.Printf("Cleared %s\n", dir)
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.CacheCmd().AddCommand(clearCmd)
+}
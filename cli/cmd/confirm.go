@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrAborted is returned when the user declines to confirm a destructive action
+var ErrAborted = errors.New("aborted")
+
+// ConfirmName guards an action that can't be undone, such as deleting events. With
+// confirmed (the value of a --confirm flag) given it must equal name; otherwise the
+// user is shown what will happen and asked to type name. Without a terminal to ask on,
+// --confirm is required.
+func ConfirmName(what, name, confirmed string) error {
+	if confirmed != "" {
+		if confirmed != name {
+			return fmt.Errorf("--confirm '%s' does not match '%s'", confirmed, name)
+		}
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("this can't be undone; pass --confirm %s to go ahead without a terminal", name)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s. This can't be undone.\nType '%s' to confirm: ", what, name)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return ErrAborted
+	}
+	if strings.TrimSpace(answer) != name {
+		return ErrAborted
+	}
+	return nil
+}
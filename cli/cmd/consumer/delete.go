@@ -6,44 +6,125 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/event-store/cli/cmd"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/consumers"
 	"github.com/event-store/cli/internal/output"
 )
 
+var (
+	deleteAll            bool
+	deleteTopics         []string
+	deleteCallbackPrefix string
+	deleteDryRun         bool
+	deleteWorkers        int
+)
+
 var deleteCmd = &cobra.Command{
-	Use:   "delete <id>",
-	Short: "Unregister a consumer",
-	Long:  `Unregister a consumer. The consumer will stop receiving events.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "delete [id...]",
+	Short: "Unregister consumers",
+	Long: `Unregister consumers. They will stop receiving events.
+
+Consumers are given by ID, or selected with --all, --topic or --callback-prefix.
+--topic and --callback-prefix can be combined, and a consumer must match both. Use
+--dry-run to list what would be removed first. Several consumers are deleted
+concurrently; a failure doesn't stop the others.
+
+Examples:
+  # Unregister one consumer
+  es consumer delete 3f2a9c
+
+  # See which consumers calling an old host would be removed, then remove them
+  es consumer delete --callback-prefix http://old-host --dry-run
+  es consumer delete --callback-prefix http://old-host
+
+  # Unregister every consumer of the orders topic
+  es consumer delete --topic orders`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
+
+		selector := consumers.Selector{All: deleteAll, Topics: deleteTopics, CallbackPrefix: deleteCallbackPrefix}
+		switch {
+		case len(args) > 0 && !selector.IsZero():
+			return fmt.Errorf("give consumer IDs or --all, --topic and --callback-prefix, not both")
+		case len(args) == 0 && selector.IsZero():
+			return fmt.Errorf("give consumer IDs, or select consumers with --all, --topic or --callback-prefix")
+		case deleteAll && (len(deleteTopics) > 0 || deleteCallbackPrefix != ""):
+			return fmt.Errorf("--all can't be combined with --topic or --callback-prefix")
+		case deleteWorkers < 1:
+			return fmt.Errorf("workers must be at least 1")
+		}
 
-		consumerID := args[0]
+		if len(args) == 1 && !deleteDryRun {
+			return deleteConsumer(cfg.Output.Format, apiClient, args[0])
+		}
 
-		if err := apiClient.DeleteConsumer(consumerID); err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
+		var selected []client.Consumer
+		if selector.IsZero() {
+			for _, id := range args {
+				selected = append(selected, client.Consumer{ID: id})
 			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
+		} else {
+			all, err := apiClient.GetConsumers()
+			if err != nil {
+				return err
 			}
-			output.PrintError(err)
-			return err
+			selected = selector.Select(all)
 		}
 
-		message := fmt.Sprintf("Consumer '%s' unregistered", consumerID)
-		switch cfg.Output.Format {
-		case "json":
-			return output.PrintMessageJSON(message)
-		case "csv":
-			return output.PrintMessageCSV(message)
+		result := consumers.Delete(apiClient, selected, deleteWorkers, deleteDryRun)
+		switch {
+		case cmd.Quiet():
+			for _, deletion := range result.Deletions {
+				if deletion.Status != consumers.Failed {
+					output.PrintIDs([]string{deletion.ID})
+				}
+			}
+		case cfg.Output.Format == "json":
+			if err := output.PrintJSON(result); err != nil {
+				return err
+			}
+		case cfg.Output.Format == "csv":
+			if err := output.PrintConsumerDeletionsCSV(result); err != nil {
+				return err
+			}
 		default:
-			output.PrintMessage(message)
-			return nil
+			output.PrintConsumerDeletions(result)
+		}
+
+		if result.Failed > 0 {
+			return cmd.CheckFailed(fmt.Errorf("%d of %d consumer(s) could not be deleted", result.Failed, len(result.Deletions)))
 		}
+		return nil
 	},
 }
 
+// deleteConsumer unregisters a single consumer
+func deleteConsumer(format string, apiClient *client.Client, consumerID string) error {
+	if err := apiClient.DeleteConsumer(consumerID); err != nil {
+		return err
+	}
+
+	if cmd.Quiet() {
+		output.PrintIDs([]string{consumerID})
+		return nil
+	}
+	message := fmt.Sprintf("Consumer '%s' unregistered", consumerID)
+	switch format {
+	case "json":
+		return output.PrintMessageJSON(message)
+	case "csv":
+		return output.PrintMessageCSV(message)
+	default:
+		output.PrintMessage(message)
+		return nil
+	}
+}
+
 func init() {
 	cmd.ConsumerCmd().AddCommand(deleteCmd)
+	deleteCmd.Flags().BoolVar(&deleteAll, "all", false, "Unregister every consumer")
+	deleteCmd.Flags().StringArrayVar(&deleteTopics, "topic", nil, "Unregister consumers subscribed to this topic (repeatable)")
+	deleteCmd.Flags().StringVar(&deleteCallbackPrefix, "callback-prefix", "", "Unregister consumers whose callback URL starts with this prefix")
+	deleteCmd.Flags().BoolVar(&deleteDryRun, "dry-run", false, "List the consumers that would be unregistered without deleting them")
+	deleteCmd.Flags().IntVar(&deleteWorkers, "workers", 8, "Number of consumers to delete concurrently")
 }
@@ -0,0 +1,129 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	deliveriesLimit      int
+	deliveriesFailedOnly bool
+	deliveriesFollow     bool
+	deliveriesInterval   time.Duration
+)
+
+var deliveriesCmd = &cobra.Command{
+	Use:   "deliveries <id>",
+	Short: "Show recent delivery attempts to a consumer (experimental)",
+	Long: `Show the most recent attempts to deliver events to a consumer's callback, oldest
+first: the event, the attempt number, the HTTP status the callback answered with (or
+that it couldn't be reached), the latency and when it happened.
+
+Experimental: the event store server in this repository doesn't keep a delivery log
+yet. This command needs a server that advertises the 'consumer-deliveries' feature (see
+es version --server), and fails without one.
+
+With --follow, new attempts are printed as they happen until Ctrl+C.
+
+Examples:
+  # The last 50 attempts
+  es consumer deliveries 3f2a9c
+
+  # Watch failing deliveries as they happen
+  es consumer deliveries 3f2a9c --failed-only --follow`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		consumerID := args[0]
+
+		if deliveriesInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		if !apiClient.Supports(client.FeatureConsumerDeliveries) {
+			return fmt.Errorf("the event store does not keep a delivery log (feature '%s')", client.FeatureConsumerDeliveries)
+		}
+
+		query := client.DeliveriesQuery{Limit: deliveriesLimit, FailedOnly: deliveriesFailedOnly}
+		deliveries, err := apiClient.GetDeliveries(consumerID, query)
+		if err != nil {
+			return err
+		}
+
+		if !deliveriesFollow {
+			switch cfg.Output.Format {
+			case "json":
+				return output.PrintJSON(client.DeliveriesResponse{Deliveries: deliveries})
+			case "csv":
+				return output.PrintDeliveriesCSV(deliveries)
+			default:
+				output.PrintDeliveries(deliveries)
+				return nil
+			}
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+		stream := output.NewDeliveryStream(cfg.Output.Format)
+		var followErr error
+		group.Go("consumer-deliveries", func(ctx context.Context) error {
+			followErr = followDeliveries(ctx, apiClient, consumerID, query, deliveries, stream)
+			group.Stop()
+			return nil
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+		return followErr
+	},
+}
+
+// followDeliveries prints the attempts already fetched, then polls for newer ones every
+// --interval until ctx is done. Polling errors are reported and retried.
+func followDeliveries(ctx context.Context, apiClient *client.Client, consumerID string, query client.DeliveriesQuery, deliveries []client.Delivery, stream *output.DeliveryStream) error {
+	ticker := time.NewTicker(deliveriesInterval)
+	defer ticker.Stop()
+
+	query.Limit = 0
+	for {
+		for _, delivery := range deliveries {
+			if err := stream.Write(delivery); err != nil {
+				return err
+			}
+			query.After = delivery.ID
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		var err error
+		deliveries, err = apiClient.GetDeliveries(consumerID, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "[%s] %v\n", time.Now().Format(time.RFC3339), err)
+		}
+	}
+}
+
+func init() {
+	cmd.ConsumerCmd().AddCommand(deliveriesCmd)
+	cmd.DisablePager(deliveriesCmd)
+	deliveriesCmd.Flags().IntVar(&deliveriesLimit, "limit", 50, "Number of recent attempts to show")
+	deliveriesCmd.Flags().BoolVar(&deliveriesFailedOnly, "failed-only", false, "Only show failed attempts")
+	deliveriesCmd.Flags().BoolVarP(&deliveriesFollow, "follow", "f", false, "Keep printing new attempts as they happen")
+	deliveriesCmd.Flags().DurationVar(&deliveriesInterval, "interval", 2*time.Second, "How often to poll for new attempts with --follow")
+}
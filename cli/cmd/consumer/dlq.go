@@ -0,0 +1,32 @@
+package consumer
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var dlqCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Inspect and re-drive consumers' dead-letter queues (experimental)",
+	Long: `Inspect and re-drive the events whose delivery to a consumer failed after the
+server's retries.
+
+Experimental: the event store server in this repository doesn't keep dead-letter queues
+yet. These commands need a server that advertises the 'consumer-dlq' feature (see
+es version --server), and fail without one.`,
+}
+
+// requireDLQ returns an error unless the server keeps dead-letter queues
+func requireDLQ(apiClient *client.Client) error {
+	if !apiClient.Supports(client.FeatureConsumerDLQ) {
+		return fmt.Errorf("the event store does not support dead-letter queues (feature '%s')", client.FeatureConsumerDLQ)
+	}
+	return nil
+}
+
+func init() {
+	cmd.ConsumerCmd().AddCommand(dlqCmd)
+}
@@ -0,0 +1,103 @@
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dlqListLimit int
+	dlqListOut   string
+)
+
+var dlqListCmd = &cobra.Command{
+	Use:   "list <consumer-id>",
+	Short: "List a consumer's dead-lettered events (experimental)",
+	Long: `List the events in a consumer's dead-letter queue, oldest first, with how many
+delivery attempts were made and the last error. Experimental: needs a server with the
+'consumer-dlq' feature, which the event store server doesn't implement yet.
+
+--out saves the entries, with their events, to a JSON file instead.
+
+Examples:
+  # List the dead-lettered events of a consumer
+  es consumer dlq list 3f2a9c
+
+  # Export the whole queue
+  es consumer dlq list 3f2a9c --out dlq.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		consumerID := args[0]
+
+		if err := requireDLQ(apiClient); err != nil {
+			return err
+		}
+		entries, err := scanDLQ(apiClient, consumerID, dlqListLimit)
+		if err != nil {
+			return err
+		}
+
+		if dlqListOut != "" {
+			return writeDLQ(dlqListOut, entries)
+		}
+		if cmd.Quiet() {
+			ids := make([]string, len(entries))
+			for i, entry := range entries {
+				ids[i] = entry.ID
+			}
+			output.PrintIDs(ids)
+			return nil
+		}
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(client.DLQResponse{Entries: entries})
+		case "csv":
+			return output.PrintDLQEntriesCSV(entries)
+		default:
+			output.PrintDLQEntries(entries)
+			return nil
+		}
+	},
+}
+
+// scanDLQ returns up to limit entries of a consumer's dead-letter queue (0 for all)
+func scanDLQ(apiClient *client.Client, consumerID string, limit int) ([]client.DLQEntry, error) {
+	entries := []client.DLQEntry{}
+	err := apiClient.ScanDLQ(consumerID, func(page []client.DLQEntry) (bool, error) {
+		for _, entry := range page {
+			if limit > 0 && len(entries) >= limit {
+				return false, nil
+			}
+			entries = append(entries, entry)
+		}
+		return true, nil
+	})
+	return entries, err
+}
+
+// writeDLQ saves entries to a file as a JSON array
+func writeDLQ(path string, entries []client.DLQEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode entries: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Saved %d dead-lettered event(s) to %s\n", len(entries), path)
+	return nil
+}
+
+func init() {
+	dlqCmd.AddCommand(dlqListCmd)
+	dlqListCmd.Flags().IntVar(&dlqListLimit, "limit", 0, "Maximum number of entries to list (0 = no limit)")
+	dlqListCmd.Flags().StringVar(&dlqListOut, "out", "", "Save the entries to this JSON file instead of printing them")
+}
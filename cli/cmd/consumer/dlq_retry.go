@@ -0,0 +1,110 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/consumers"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dlqRetryAll  bool
+	dlqRetryRate string
+)
+
+var dlqRetryCmd = &cobra.Command{
+	Use:   "retry <consumer-id> [entry-id...]",
+	Short: "Re-deliver dead-lettered events (experimental)",
+	Long: `Ask the server to deliver dead-lettered events to their consumer again. Entries
+whose delivery succeeds leave the queue; the others stay with their attempt count raised.
+
+Give entry IDs, or --all to re-drive the whole queue, oldest first. --rate limits how
+many events are retried per second so a recovering consumer isn't flooded. A failed
+retry doesn't stop the others.
+
+Experimental: needs a server with the 'consumer-dlq' feature, which the event store
+server doesn't implement yet.
+
+Examples:
+  # Retry one event
+  es consumer dlq retry 3f2a9c dlq-17
+
+  # Re-drive the whole queue at 20 events per second
+  es consumer dlq retry 3f2a9c --all --rate 20/s`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		consumerID, entryIDs := args[0], args[1:]
+
+		if len(entryIDs) > 0 == dlqRetryAll {
+			return fmt.Errorf("give entry IDs or --all")
+		}
+		rate, err := bench.ParseRate(dlqRetryRate)
+		if err != nil {
+			return err
+		}
+		if err := requireDLQ(apiClient); err != nil {
+			return err
+		}
+
+		var entries []client.DLQEntry
+		if dlqRetryAll {
+			entries, err = scanDLQ(apiClient, consumerID, 0)
+			if err != nil {
+				return err
+			}
+		} else {
+			for _, id := range entryIDs {
+				entries = append(entries, client.DLQEntry{ID: id})
+			}
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+		var result *consumers.RetryResult
+		progress := output.NewProgress("Retrying", len(entries))
+		group.Go("dlq-retry", func(ctx context.Context) error {
+			result = consumers.RetryDLQ(ctx, apiClient, consumerID, entries, rate, progress.Add)
+			group.Stop()
+			return nil
+		})
+		err = group.Wait()
+		progress.Finish()
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			if err := output.PrintJSON(result); err != nil {
+				return err
+			}
+		case "csv":
+			if err := output.PrintRetryResultCSV(result); err != nil {
+				return err
+			}
+		default:
+			output.PrintRetryResult(result)
+		}
+
+		if result.Failed > 0 {
+			return cmd.CheckFailed(fmt.Errorf("%d of %d event(s) could not be retried", result.Failed, len(result.Outcomes)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	dlqCmd.AddCommand(dlqRetryCmd)
+	cmd.DisablePager(dlqRetryCmd)
+	dlqRetryCmd.Flags().BoolVar(&dlqRetryAll, "all", false, "Retry every entry in the queue")
+	dlqRetryCmd.Flags().StringVar(&dlqRetryRate, "rate", "0", "Most events per second to retry, e.g. '20/s' (0 = unlimited)")
+}
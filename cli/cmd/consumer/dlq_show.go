@@ -0,0 +1,46 @@
+package consumer
+
+import (
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var dlqShowCmd = &cobra.Command{
+	Use:   "show <consumer-id> <entry-id>",
+	Short: "Show a dead-lettered event (experimental)",
+	Long: `Show an entry of a consumer's dead-letter queue: the event, its delivery attempts
+and the last error. Experimental: needs a server with the 'consumer-dlq' feature, which
+the event store server doesn't implement yet.
+
+Examples:
+  es consumer dlq show 3f2a9c dlq-17`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		if err := requireDLQ(apiClient); err != nil {
+			return err
+		}
+		entry, err := apiClient.GetDLQEntry(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(entry)
+		case "csv":
+			return output.PrintDLQEntriesCSV([]client.DLQEntry{*entry})
+		default:
+			output.PrintDLQEntry(entry)
+			return nil
+		}
+	},
+}
+
+func init() {
+	dlqCmd.AddCommand(dlqShowCmd)
+}
@@ -1,44 +1,127 @@
 package consumer
 
 import (
+	"strconv"
+
 	"github.com/event-store/cli/cmd"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/labels"
+	"github.com/event-store/cli/internal/monitor"
 	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/watch"
 	"github.com/spf13/cobra"
 )
 
+// consumerColumns are the columns of 'es consumer list'
+var consumerColumns = []output.Column{
+	{Name: "id", Header: "ID"},
+	{Name: "callback", Header: "Callback URL"},
+	{Name: "topics", Header: "Topics"},
+	{Name: "lag", Header: "Lag", Numeric: true, Hidden: true},
+	{Name: "labels", Header: "Labels", Hidden: true},
+	{Name: "description", Header: "Description", Hidden: true},
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all consumers",
-	Long:  `List all registered consumers in the event store.`,
+	Long: `List all registered consumers in the event store.
+
+The lag column (events not yet delivered, summed over the consumer's topics) is only
+shown when selected with --columns, as it needs the topics' sequences too.
+
+Examples:
+  # Most lagging consumers first
+  es consumer list --sort-by lag:desc --columns id,lag,topics
+
+  # Watch consumers being registered and removed
+  es consumer list --watch
+
+  # Consumers of every configured context, e.g. dev, staging and prod
+  es consumer list --all-contexts
+
+  # The payments team's consumers, with their labels
+  es consumer list --selector team=payments --columns id,callback,labels`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
-		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
-		consumers, err := apiClient.GetConsumers()
-		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
+		if cmd.Watching() {
+			header := output.NewListing(consumerColumns)
+			if err := cmd.ApplyListFlags(header); err != nil {
+				return err
+			}
+			return cmd.Watch(cobraCmd, header.Header(), func() ([]watch.Row, error) {
+				listing, err := consumerListing(apiClient)
+				if err != nil {
+					return nil, err
+				}
+				return listing.Rows(), nil
+			})
+		}
+
+		if cmd.AllContexts() {
+			listing, failed := cmd.CombineContextListings(consumerColumns, newConsumerListing)
+			if listing == nil {
+				return failed
 			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
+			if err := cmd.PrintListing("consumers", listing); err != nil {
+				return err
 			}
-			output.PrintError(err)
-			return err
+			return failed
 		}
 
-		switch cfg.Output.Format {
-		case "json":
-			return output.PrintConsumersListJSON(consumers)
-		case "csv":
-			return output.PrintConsumersListCSV(consumers)
-		default:
-			output.PrintConsumersList(consumers)
-			return nil
+		listing, err := consumerListing(apiClient)
+		if err != nil {
+			return err
 		}
+		return cmd.PrintListing("consumers", listing)
 	},
 }
 
+// consumerListing fetches the consumers and builds their listing with --columns and
+// --sort-by applied
+func consumerListing(apiClient *client.Client) (*output.Listing, error) {
+	listing, err := newConsumerListing(apiClient)
+	if err != nil {
+		return nil, err
+	}
+	return listing, cmd.ApplyListFlags(listing)
+}
+
+// newConsumerListing fetches the consumers, and the topics when the lag column is used,
+// and builds their listing
+func newConsumerListing(apiClient *client.Client) (*output.Listing, error) {
+	consumers, err := cmd.ListConsumers(apiClient)
+	if err != nil {
+		return nil, err
+	}
+	var lags map[string]int64
+	if cmd.ListFlagsUse("lag") {
+		topics, err := apiClient.GetTopics()
+		if err != nil {
+			return nil, err
+		}
+		lags = monitor.ConsumerLags(topics, consumers)
+	}
+
+	listing := output.NewListing(consumerColumns)
+	for _, consumer := range consumers {
+		listing.Add(consumer.ID, consumer,
+			consumer.ID,
+			consumer.Callback,
+			output.FormatConsumerTopics(consumer),
+			strconv.FormatInt(lags[consumer.ID], 10),
+			labels.Format(consumer.Labels),
+			consumer.Description,
+		)
+	}
+	return listing, nil
+}
+
 func init() {
 	cmd.ConsumerCmd().AddCommand(listCmd)
+	cmd.AddWatchFlags(listCmd)
+	cmd.AddListFlags(listCmd, consumerColumns)
+	cmd.AddAllContextsFlag(listCmd)
+	cmd.AddSelectorFlag(listCmd)
 }
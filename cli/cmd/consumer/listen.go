@@ -7,9 +7,8 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
 	"path/filepath"
-	"syscall"
+	"sync"
 	"time"
 
 	"github.com/event-store/cli/cmd"
@@ -28,8 +27,10 @@ var listenCmd = &cobra.Command{
 	Long: `Start an HTTP server that listens for POST requests from the event store.
 All received events are logged to stdout and saved to a JSON file for inspection.`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
-		// Only use data file if explicitly provided
+		// Only use data file if explicitly provided. Handlers run concurrently, so
+		// calls is guarded by callsMu.
 		var calls []map[string]interface{}
+		var callsMu sync.Mutex
 		if listenDataFile != "" {
 			// Ensure directory exists
 			if err := os.MkdirAll(filepath.Dir(listenDataFile), 0755); err != nil {
@@ -86,6 +87,8 @@ All received events are logged to stdout and saved to a JSON file for inspection
 				"timestamp": time.Now().Format(time.RFC3339),
 			}
 
+			callsMu.Lock()
+
 			// Add to calls array
 			calls = append(calls, callRecord)
 
@@ -109,6 +112,8 @@ All received events are logged to stdout and saved to a JSON file for inspection
 				fmt.Println()
 			}
 
+			callsMu.Unlock()
+
 			// Return success response
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
@@ -120,17 +125,11 @@ All received events are logged to stdout and saved to a JSON file for inspection
 			Handler: mux,
 		}
 
-		// Handle graceful shutdown
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-		go func() {
-			<-sigChan
-			if !listenSilent {
-				fmt.Println("\nShutting down server...")
-			}
-			server.Close()
-		}()
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+		group.Serve("listen-server", server)
 
 		if !listenSilent {
 			fmt.Printf("Listening for webhook events on port %d\n", listenPort)
@@ -141,10 +140,12 @@ All received events are logged to stdout and saved to a JSON file for inspection
 			fmt.Println()
 		}
 
-		// Start server
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := group.Wait(); err != nil {
 			return fmt.Errorf("server error: %w", err)
 		}
+		if !listenSilent {
+			fmt.Println("\nShutting down server...")
+		}
 
 		return nil
 	},
@@ -152,6 +153,7 @@ All received events are logged to stdout and saved to a JSON file for inspection
 
 func init() {
 	cmd.ConsumerCmd().AddCommand(listenCmd)
+	cmd.DisablePager(listenCmd)
 	listenCmd.Flags().IntVarP(&listenPort, "port", "p", 19000, "Port to listen on")
 	listenCmd.Flags().StringVar(&listenDataFile, "data-file", "", "File to save received events (only saves if this flag is provided)")
 	listenCmd.Flags().BoolVar(&listenSilent, "silent", false, "Suppress output to stdout")
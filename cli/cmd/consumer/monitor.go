@@ -0,0 +1,258 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/monitor"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/watch"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// monitorColumns are the columns of 'es consumer monitor'
+var monitorColumns = []output.Column{
+	{Name: "consumer", Header: "Consumer"},
+	{Name: "topic", Header: "Topic"},
+	{Name: "position", Header: "Last Event ID"},
+	{Name: "lag", Header: "Lag", Numeric: true},
+	{Name: "rate", Header: "Rate/s", Numeric: true},
+	{Name: "last-delivery", Header: "Last Delivery"},
+}
+
+// monitorDeliveriesLimit is how many of each consumer's latest delivery attempts are read
+// for the times of their last deliveries
+const monitorDeliveriesLimit = 100
+
+var (
+	monitorInterval time.Duration
+	monitorWindow   time.Duration
+	monitorCount    int
+	monitorAlertLag int64
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor [consumer-id...]",
+	Short: "Live view of consumers' delivery lag and rate",
+	Long: `Poll the consumers and topics on an interval and show, for each consumer and each
+of its topics, its last delivered event, its lag (events not yet delivered), the rate
+events are being delivered at and when one last was. Every consumer is shown unless
+some are named.
+
+The rate is measured from the consumers' positions over the last --window. The last
+delivery is taken from the delivery log on servers that keep one, and is otherwise when
+a consumer's position was last seen to advance, so it is blank until it does.
+
+Rows are sorted by lag, most lagging first, unless --sort-by says otherwise. With
+--alert-lag, rows with at least that lag are marked with '!' and shown in red.
+
+The table is redrawn in place on a terminal, and printed after every poll otherwise.
+With --output json every poll prints a JSON object per row, one per line; with
+--output csv every poll prints a row per row, with the time of the poll first.
+
+Examples:
+  # Watch every consumer, flagging those 1000 or more events behind
+  es consumer monitor --alert-lag 1000
+
+  # Two consumers, slowest first
+  es consumer monitor 3f2a9c 7b1e04 --sort-by rate
+
+  # Five polls a minute apart as CSV, for a spreadsheet
+  es consumer monitor --interval 1m --count 5 --output csv > lag.csv`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		if monitorInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		if monitorWindow <= 0 {
+			return fmt.Errorf("window must be positive")
+		}
+		if err := cmd.ApplyListFlags(output.NewListing(monitorColumns)); err != nil {
+			return err
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		format := cfg.Output.Format
+		interactive := format == "table" && term.IsTerminal(int(os.Stdout.Fd()))
+		title := fmt.Sprintf("Every %s: consumer monitor", monitorInterval)
+		tracker := monitor.NewDeliveryTracker(monitorWindow)
+		polls := 0
+
+		group.Go("consumer-monitor", func(ctx context.Context) error {
+			return watch.Loop(ctx, monitorInterval, func() error {
+				at := time.Now().Truncate(time.Second)
+				deliveries, err := pollDeliveries(apiClient, tracker, at, args)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "[%s] %v\n", at.Format(time.RFC3339), err)
+					return nil
+				}
+
+				listing := output.NewListing(monitorColumns)
+				alerting := map[string]bool{}
+				for _, delivery := range deliveries {
+					key := delivery.Consumer + "/" + delivery.Topic
+					if monitorAlertLag > 0 && delivery.Lag != nil && *delivery.Lag >= monitorAlertLag {
+						alerting[key] = true
+					}
+					listing.Add(key, delivery,
+						delivery.Consumer,
+						delivery.Topic,
+						valueOrDash(delivery.LastEventID),
+						formatOptionalInt(delivery.Lag),
+						formatRate(delivery.Rate),
+						valueOrDash(delivery.LastDelivery),
+					)
+				}
+				if err := listing.Sort("lag:desc"); err != nil {
+					return err
+				}
+				if err := cmd.ApplyListFlags(listing); err != nil {
+					return err
+				}
+
+				switch format {
+				case "json":
+					err = output.PrintConsumerMonitorJSON(listing)
+				case "csv":
+					err = output.PrintConsumerMonitorCSV(at, listing, polls == 0)
+				default:
+					output.PrintConsumerMonitor(fmt.Sprintf("%s    %s", title, at.Format("15:04:05")), listing, alerting, monitorFooter(len(deliveries), len(alerting)), interactive)
+					if !interactive {
+						fmt.Println()
+					}
+				}
+				if err != nil {
+					return err
+				}
+
+				polls++
+				if monitorCount > 0 && polls >= monitorCount {
+					group.Stop()
+				}
+				return nil
+			})
+		})
+		return group.Wait()
+	},
+}
+
+// pollDeliveries fetches the topics and consumers, only those named if any, and observes
+// their positions with the tracker, along with the times of their last deliveries on
+// servers that keep a delivery log
+func pollDeliveries(apiClient *client.Client, tracker *monitor.DeliveryTracker, at time.Time, consumerIDs []string) ([]monitor.ConsumerDelivery, error) {
+	topics, err := apiClient.GetTopics()
+	if err != nil {
+		return nil, err
+	}
+	consumers, err := apiClient.GetConsumers()
+	if err != nil {
+		return nil, err
+	}
+	if len(consumerIDs) > 0 {
+		named := make(map[string]bool, len(consumerIDs))
+		for _, id := range consumerIDs {
+			named[id] = true
+		}
+		var selected []client.Consumer
+		for _, consumer := range consumers {
+			if named[consumer.ID] {
+				selected = append(selected, consumer)
+			}
+		}
+		consumers = selected
+	}
+
+	var logged map[string]map[string]time.Time
+	if apiClient.Supports(client.FeatureConsumerDeliveries) {
+		logged = make(map[string]map[string]time.Time, len(consumers))
+		for _, consumer := range consumers {
+			deliveries, err := apiClient.GetDeliveries(consumer.ID, client.DeliveriesQuery{Limit: monitorDeliveriesLimit})
+			if err != nil {
+				return nil, err
+			}
+			logged[consumer.ID] = lastDeliveries(deliveries)
+		}
+	}
+
+	status := monitor.BuildStatus("", &client.Health{}, topics, consumers)
+	return tracker.Observe(at, status, logged), nil
+}
+
+// lastDeliveries returns the time of the latest successful delivery of each topic's
+// events among delivery attempts, by topic
+func lastDeliveries(deliveries []client.Delivery) map[string]time.Time {
+	last := map[string]time.Time{}
+	for _, delivery := range deliveries {
+		if !delivery.Success {
+			continue
+		}
+		id, err := eventid.Parse(delivery.EventID)
+		if err != nil {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339Nano, delivery.Timestamp)
+		if err != nil {
+			continue
+		}
+		if at.After(last[id.Topic]) {
+			last[id.Topic] = at
+		}
+	}
+	return last
+}
+
+// monitorFooter summarises a poll: how many consumer topics there are and, with
+// --alert-lag, how many are at or over it
+func monitorFooter(rows, alerting int) string {
+	if monitorAlertLag <= 0 {
+		return fmt.Sprintf("%d consumer topic(s)", rows)
+	}
+	return fmt.Sprintf("%d of %d consumer topic(s) at or over --alert-lag %d", alerting, rows, monitorAlertLag)
+}
+
+// valueOrDash returns s, or "-" when it is empty
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatOptionalInt formats n, or "-" when it is nil
+func formatOptionalInt(n *int64) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.FormatInt(*n, 10)
+}
+
+// formatRate formats a delivery rate to one decimal place, or "-" when it is nil
+func formatRate(rate *float64) string {
+	if rate == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*rate, 'f', 1, 64)
+}
+
+func init() {
+	cmd.ConsumerCmd().AddCommand(monitorCmd)
+	cmd.DisablePager(monitorCmd)
+	cmd.AddListFlags(monitorCmd, monitorColumns)
+	monitorCmd.Flags().DurationVar(&monitorInterval, "interval", 2*time.Second, "How often to poll the consumers and topics")
+	monitorCmd.Flags().DurationVar(&monitorWindow, "window", time.Minute, "Period delivery rates are measured over")
+	monitorCmd.Flags().IntVar(&monitorCount, "count", 0, "Stop after this many polls (0 = until Ctrl+C)")
+	monitorCmd.Flags().Int64Var(&monitorAlertLag, "alert-lag", 0, "Mark and highlight rows with at least this lag (0 = off)")
+}
@@ -0,0 +1,131 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/consumers"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pingAll      bool
+	pingCallback string
+	pingEvent    bool
+	pingTimeout  time.Duration
+	pingWorkers  int
+)
+
+var pingCmd = &cobra.Command{
+	Use:   "ping [id...]",
+	Short: "Check that consumers' callback URLs are reachable",
+	Long: `POST a probe to consumers' callback URLs and report whether they answered, the HTTP
+status, the latency and, for https callbacks, the TLS version and certificate.
+
+The probe is a delivery in the format the event store uses ({"consumerId": ...,
+"events": [...]}) with no events, or with --event one synthetic event of type
+'es.ping'. A callback passes if it answers with a 2xx status. Use --callback to check
+a URL before registering a consumer for it.
+
+Examples:
+  # Probe one consumer
+  es consumer ping 3f2a9c
+
+  # Probe every consumer, sending each a test event
+  es consumer ping --all --event
+
+  # Check a webhook before registering it
+  es consumer ping --callback https://orders.example.com/events`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+
+		sources := 0
+		for _, given := range []bool{len(args) > 0, pingAll, pingCallback != ""} {
+			if given {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("give consumer IDs, --all or --callback")
+		}
+		if pingWorkers < 1 {
+			return fmt.Errorf("workers must be at least 1")
+		}
+
+		var targets []client.Consumer
+		if pingCallback != "" {
+			targets = []client.Consumer{{Callback: pingCallback}}
+		} else {
+			registered, err := cmd.NewClient().GetConsumers()
+			if err != nil {
+				return err
+			}
+			targets, err = selectConsumers(registered, args)
+			if err != nil {
+				return err
+			}
+		}
+
+		pings := consumers.PingAll(context.Background(), targets, consumers.PingOptions{
+			Event:   pingEvent,
+			Timeout: pingTimeout,
+			Workers: pingWorkers,
+		})
+		switch cfg.Output.Format {
+		case "json":
+			if err := output.PrintJSON(map[string]interface{}{"consumers": pings}); err != nil {
+				return err
+			}
+		case "csv":
+			if err := output.PrintConsumerPingsCSV(pings); err != nil {
+				return err
+			}
+		default:
+			output.PrintConsumerPings(pings)
+		}
+
+		failed := 0
+		for _, ping := range pings {
+			if !ping.OK {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return cmd.CheckFailed(fmt.Errorf("%d of %d callback(s) failed", failed, len(pings)))
+		}
+		return nil
+	},
+}
+
+// selectConsumers returns the consumers with the given IDs, or all of them with no IDs
+func selectConsumers(registered []client.Consumer, ids []string) ([]client.Consumer, error) {
+	if len(ids) == 0 {
+		return registered, nil
+	}
+	byID := make(map[string]client.Consumer, len(registered))
+	for _, consumer := range registered {
+		byID[consumer.ID] = consumer
+	}
+	selected := make([]client.Consumer, len(ids))
+	for i, id := range ids {
+		consumer, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("consumer '%s' not found", id)
+		}
+		selected[i] = consumer
+	}
+	return selected, nil
+}
+
+func init() {
+	cmd.ConsumerCmd().AddCommand(pingCmd)
+	pingCmd.Flags().BoolVar(&pingAll, "all", false, "Probe every registered consumer")
+	pingCmd.Flags().StringVar(&pingCallback, "callback", "", "Probe this callback URL instead of a registered consumer's")
+	pingCmd.Flags().BoolVar(&pingEvent, "event", false, "Send a synthetic 'es.ping' event instead of an empty delivery")
+	pingCmd.Flags().DurationVar(&pingTimeout, "timeout", 10*time.Second, "How long to wait for each callback to answer")
+	pingCmd.Flags().IntVar(&pingWorkers, "workers", 8, "Number of callbacks to probe concurrently")
+}
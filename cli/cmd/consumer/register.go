@@ -4,35 +4,92 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/spf13/cobra"
 	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/annotations"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/consumers"
+	"github.com/event-store/cli/internal/labels"
 	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
 )
 
 var (
 	registerCallback string
 	registerTopics   string
+	registerFile     string
+	registerLabels   []string
 )
 
 var registerCmd = &cobra.Command{
 	Use:   "register",
 	Short: "Register a new consumer",
-	Long:  `Register a new consumer that will receive events from specified topics via webhook.`,
+	Long: `Register a new consumer that will receive events from specified topics via webhook.
+
+The consumer is given with --callback and --topics, or declared in a YAML manifest with
+--file:
+
+  callback: https://billing.example.com/events
+  description: Bills customers for shipped orders
+  labels:
+    team: payments
+  topics:
+    - name: orders
+      after: latest   # only the events published from now on
+    - payments        # every event, from the start
+
+A topic's 'after' is the last event the consumer has already handled: an event ID, a
+sequence number, 'latest' or 'latest~<n>'. The manifest is checked before anything is
+registered, and its topics must exist.
+
+Labels given with --label are added to the manifest's, replacing those with the same key.
+The description and labels are kept by event stores with the 'consumer-metadata' feature,
+and otherwise in ~/.es/annotations/<server host>.json, so 'es consumer list --selector'
+can select consumers by them.
+
+Examples:
+  # Register for all of user-events and audit-events after audit-events-5
+  es consumer register --callback https://example.com/webhook --topics "user-events:null,audit-events:audit-events-5"
+
+  # Register the consumer declared in a manifest
+  es consumer register --file consumer.yaml
+
+  # Register a consumer labelled with its team
+  es consumer register --callback https://example.com/webhook --topics orders:null --label team=payments`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
+
+		flagLabels, err := labels.Parse(registerLabels)
+		if err != nil {
+			return err
+		}
+
+		if registerFile != "" {
+			manifest, err := consumers.LoadManifest(registerFile)
+			if err != nil {
+				return err
+			}
+			req, err := manifest.Request(apiClient)
+			if err != nil {
+				return err
+			}
+			consumerID, err := registerConsumer(apiClient, req, flagLabels)
+			if err != nil {
+				return err
+			}
+			return printRegistered(cfg.Output.Format, consumerID)
+		}
 
 		if registerCallback == "" {
-			return fmt.Errorf("callback URL is required (use --callback)")
+			return fmt.Errorf("callback URL is required (use --callback, or --file for a manifest)")
 		}
 
 		if registerTopics == "" {
-			return fmt.Errorf("topics are required (use --topics)")
+			return fmt.Errorf("topics are required (use --topics, or --file for a manifest)")
 		}
 
 		// Parse topics string: "topic1:eventId1,topic2:null"
-		topicsMap := make(map[string]string)
+		topicsMap := make(map[string]*string)
 		topicPairs := strings.Split(registerTopics, ",")
 		for _, pair := range topicPairs {
 			parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
@@ -47,11 +104,11 @@ var registerCmd = &cobra.Command{
 				return fmt.Errorf("topic name cannot be empty")
 			}
 
-			// Convert "null" string to empty string for API
+			// "null" starts from the topic's first event
 			if eventID == "null" || eventID == "" {
-				topicsMap[topic] = ""
+				topicsMap[topic] = nil
 			} else {
-				topicsMap[topic] = eventID
+				topicsMap[topic] = &eventID
 			}
 		}
 
@@ -60,34 +117,76 @@ var registerCmd = &cobra.Command{
 		}
 
 		// Register consumer
-		consumerID, err := apiClient.RegisterConsumer(registerCallback, topicsMap)
+		consumerID, err := registerConsumer(apiClient, client.ConsumerRegistrationRequest{
+			Callback: registerCallback,
+			Topics:   topicsMap,
+		}, flagLabels)
 		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
-			}
-			output.PrintError(err)
 			return err
 		}
+		return printRegistered(cfg.Output.Format, consumerID)
+	},
+}
 
-		switch cfg.Output.Format {
-		case "json":
-			return output.PrintConsumerIDJSON(consumerID)
-		case "csv":
-			return output.PrintConsumerIDCSV(consumerID)
-		default:
-			output.PrintMessage(fmt.Sprintf("Consumer registered with ID: %s", consumerID))
-			return nil
+// registerConsumer registers a consumer with extra labels. Its description and labels
+// are kept in the annotations sidecar when the server can't keep them itself.
+func registerConsumer(apiClient *client.Client, req client.ConsumerRegistrationRequest, extra map[string]string) (string, error) {
+	if len(extra) > 0 {
+		merged := make(map[string]string, len(req.Labels)+len(extra))
+		for key, value := range req.Labels {
+			merged[key] = value
 		}
-	},
+		for key, value := range extra {
+			merged[key] = value
+		}
+		req.Labels = merged
+	}
+
+	var sidecar *annotations.Sidecar
+	kept := annotations.Annotations{Labels: req.Labels, Description: req.Description}
+	if (req.Description != "" || len(req.Labels) > 0) && !apiClient.Supports(client.FeatureConsumerMetadata) {
+		var err error
+		if sidecar, err = annotations.Open(apiClient.BaseURL()); err != nil {
+			return "", err
+		}
+		req.Description, req.Labels = "", nil
+	}
+
+	consumerID, err := apiClient.RegisterConsumerRequest(req)
+	if err != nil {
+		return "", err
+	}
+	if sidecar != nil {
+		sidecar.SetConsumer(consumerID, kept)
+		if err := sidecar.Save(); err != nil {
+			return "", fmt.Errorf("consumer %s was registered, but its description and labels weren't kept: %w", consumerID, err)
+		}
+	}
+	return consumerID, nil
+}
+
+func printRegistered(format, consumerID string) error {
+	if cmd.Quiet() {
+		output.PrintIDs([]string{consumerID})
+		return nil
+	}
+	switch format {
+	case "json":
+		return output.PrintConsumerIDJSON(consumerID)
+	case "csv":
+		return output.PrintConsumerIDCSV(consumerID)
+	default:
+		output.PrintMessage(fmt.Sprintf("Consumer registered with ID: %s", consumerID))
+		return nil
+	}
 }
 
 func init() {
 	cmd.ConsumerCmd().AddCommand(registerCmd)
-	registerCmd.Flags().StringVar(&registerCallback, "callback", "", "Callback URL for webhook delivery (required)")
-	registerCmd.Flags().StringVar(&registerTopics, "topics", "", "Topics mapping in format 'topic1:eventId1,topic2:null' (required)")
-	registerCmd.MarkFlagRequired("callback")
-	registerCmd.MarkFlagRequired("topics")
+	registerCmd.Flags().StringVar(&registerCallback, "callback", "", "Callback URL for webhook delivery (required without --file)")
+	registerCmd.Flags().StringVar(&registerTopics, "topics", "", "Topics mapping in format 'topic1:eventId1,topic2:null' (required without --file)")
+	registerCmd.Flags().StringVar(&registerFile, "file", "", "YAML manifest declaring the consumer's callback, topics, description and labels")
+	cmd.AddLabelFlag(registerCmd, &registerLabels)
+	registerCmd.MarkFlagsMutuallyExclusive("file", "callback")
+	registerCmd.MarkFlagsMutuallyExclusive("file", "topics")
 }
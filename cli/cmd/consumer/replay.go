@@ -0,0 +1,222 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/replay"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayTopic      string
+	replayFrom       string
+	replayTo         string
+	replayRate       string
+	replayBatchSize  int
+	replayClientSide bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <id>",
+	Short: "Re-deliver a range of events to a consumer",
+	Long: `Re-deliver a historical range of a topic's events to a consumer's callback, for
+example after fixing a bug in the consumer. --from and --to are inclusive; without --to
+the range runs to the end of the topic.
+
+When the server supports replays it is asked to re-deliver the events itself. Otherwise
+the CLI fetches the events and POSTs them to the callback in the same format the event
+store uses ({"consumerId": ..., "events": [...]}). --rate and --batch-size only apply to
+CLI-side replays. The consumer's own position in the topic is not changed.
+
+A CLI-side replay stops at the first failed delivery and reports the last event
+delivered, so it can be resumed with --from.
+
+Examples:
+  # Re-deliver part of a topic
+  es consumer replay 3f2a... --topic orders --from orders-100 --to orders-250
+
+  # Re-deliver everything from an event on, 20 events per second in batches of 5
+  es consumer replay 3f2a... --topic orders --from orders-1 --rate 20/s --batch-size 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		consumerID := args[0]
+
+		rate, err := bench.ParseRate(replayRate)
+		if err != nil {
+			return err
+		}
+		if replayBatchSize < 1 {
+			return fmt.Errorf("batch-size must be at least 1")
+		}
+		from, to, err := parseReplayRange(replayTopic, replayFrom, replayTo)
+		if err != nil {
+			return err
+		}
+
+		consumer, err := findConsumer(apiClient, consumerID)
+		if err != nil {
+			return err
+		}
+		if _, subscribed := consumer.Topics[replayTopic]; !subscribed {
+			fmt.Fprintf(os.Stderr, "Warning: consumer '%s' is not subscribed to '%s'\n", consumerID, replayTopic)
+		}
+
+		var result *replay.Result
+		if !replayClientSide && apiClient.Supports(client.FeatureConsumerReplay) {
+			result, err = replayOnServer(apiClient, consumerID)
+		} else {
+			result, err = replayFromCLI(apiClient, consumer, from, to, rate)
+		}
+		if result != nil {
+			result.Topic = replayTopic
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			if result != nil && result.LastEventID != "" {
+				err = fmt.Errorf("%w (last event delivered: %s)", err, result.LastEventID)
+			}
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(result)
+		case "csv":
+			return output.PrintReplayResultCSV(result)
+		default:
+			if err != nil {
+				fmt.Println("Replay interrupted")
+			}
+			output.PrintReplayResult(result)
+			return nil
+		}
+	},
+}
+
+// parseReplayRange checks --from and --to belong to the topic and returns their sequences
+// (to is 0 for the end of the topic)
+func parseReplayRange(topic, fromID, toID string) (from, to int64, err error) {
+	if topic == "" {
+		return 0, 0, fmt.Errorf("--topic is required")
+	}
+	first, err := eventid.Parse(fromID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --from: %w", err)
+	}
+	if first.Topic != topic {
+		return 0, 0, fmt.Errorf("--from '%s' is not an event of topic '%s'", fromID, topic)
+	}
+	if toID == "" {
+		return first.Sequence, 0, nil
+	}
+
+	last, err := eventid.Parse(toID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --to: %w", err)
+	}
+	if last.Topic != topic {
+		return 0, 0, fmt.Errorf("--to '%s' is not an event of topic '%s'", toID, topic)
+	}
+	if last.Sequence < first.Sequence {
+		return 0, 0, fmt.Errorf("--to '%s' is before --from '%s'", toID, fromID)
+	}
+	return first.Sequence, last.Sequence, nil
+}
+
+func findConsumer(apiClient *client.Client, id string) (*client.Consumer, error) {
+	consumers, err := apiClient.GetConsumers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range consumers {
+		if consumers[i].ID == id {
+			return &consumers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("consumer '%s' not found", id)
+}
+
+func replayOnServer(apiClient *client.Client, consumerID string) (*replay.Result, error) {
+	resp, err := apiClient.ReplayConsumer(consumerID, client.ReplayRequest{
+		Topic:       replayTopic,
+		FromEventID: replayFrom,
+		ToEventID:   replayTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &replay.Result{ConsumerID: consumerID, Delivered: resp.Delivered, FirstEventID: replayFrom, ServerSide: true}, nil
+}
+
+// replayFromCLI fetches the range of events and delivers them to the consumer's callback
+func replayFromCLI(apiClient *client.Client, consumer *client.Consumer, from, to int64, rate float64) (*replay.Result, error) {
+	since := ""
+	if from > 1 {
+		since = eventid.ID{Topic: replayTopic, Sequence: from - 1}.String()
+	}
+
+	var events []client.Event
+	err := apiClient.ScanEvents(replayTopic, since, func(page []client.Event) (bool, error) {
+		for _, event := range page {
+			if id, err := eventid.Parse(event.ID); err == nil && to > 0 && id.Sequence > to {
+				return false, nil
+			}
+			events = append(events, event)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return &replay.Result{ConsumerID: consumer.ID}, nil
+	}
+
+	group, err := cmd.NewRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	var result *replay.Result
+	var replayErr error
+	progress := output.NewProgress("Replaying", len(events))
+	group.Go("consumer-replay", func(ctx context.Context) error {
+		result, replayErr = replay.Deliver(ctx, replay.Options{
+			ConsumerID: consumer.ID,
+			Callback:   consumer.Callback,
+			Events:     events,
+			BatchSize:  replayBatchSize,
+			Rate:       rate,
+		}, progress.Add)
+		group.Stop()
+		return nil
+	})
+	err = group.Wait()
+	progress.Finish()
+	if err != nil {
+		return nil, err
+	}
+	return result, replayErr
+}
+
+func init() {
+	cmd.ConsumerCmd().AddCommand(replayCmd)
+	cmd.DisablePager(replayCmd)
+	replayCmd.Flags().StringVar(&replayTopic, "topic", "", "Topic to replay events from (required)")
+	replayCmd.Flags().StringVar(&replayFrom, "from", "", "First event ID to re-deliver (required)")
+	replayCmd.Flags().StringVar(&replayTo, "to", "", "Last event ID to re-deliver (default: the end of the topic)")
+	replayCmd.Flags().StringVar(&replayRate, "rate", "0", "Most events per second to deliver, e.g. '50/s' (0 = unlimited; CLI-side only)")
+	replayCmd.Flags().IntVar(&replayBatchSize, "batch-size", 1, "Events per delivery (CLI-side only)")
+	replayCmd.Flags().BoolVar(&replayClientSide, "client-side", false, "Deliver from the CLI even when the server supports replays")
+	replayCmd.MarkFlagRequired("topic")
+	replayCmd.MarkFlagRequired("from")
+}
@@ -16,20 +16,13 @@ var showCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
 		consumerID := args[0]
 
-		// Get all consumers and find the one we want
-		consumers, err := apiClient.GetConsumers()
+		// Get all consumers, with labels kept in the sidecar, and find the one we want
+		consumers, err := cmd.ListConsumers(apiClient)
 		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
-			}
-			output.PrintError(err)
 			return err
 		}
 
@@ -42,15 +35,7 @@ var showCmd = &cobra.Command{
 		}
 
 		if consumer == nil {
-			err := fmt.Errorf("consumer '%s' not found", consumerID)
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
-			}
-			output.PrintError(err)
-			return err
+			return fmt.Errorf("consumer '%s' not found", consumerID)
 		}
 
 		switch cfg.Output.Format {
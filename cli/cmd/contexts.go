@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var allContexts bool
+
+// AddAllContextsFlag adds --all-contexts to a read command that can query every
+// configured context at once
+func AddAllContextsFlag(c *cobra.Command) {
+	c.Flags().BoolVar(&allContexts, "all-contexts", false, "Query every context configured in the config file concurrently and combine the results, with a context column")
+}
+
+// AllContexts reports whether --all-contexts was given
+func AllContexts() bool {
+	return allContexts
+}
+
+func checkAllContexts() error {
+	if !allContexts {
+		return nil
+	}
+	switch {
+	case Watching():
+		return fmt.Errorf("--all-contexts can't be used with --watch")
+	case contextName != "" || serverURL != "":
+		return fmt.Errorf("--all-contexts can't be used with --context or --server-url")
+	case len(cfg.Contexts) == 0:
+		return fmt.Errorf("--all-contexts needs contexts configured under 'contexts' in the config file")
+	}
+	return nil
+}
+
+// ContextResult is what querying a context returned
+type ContextResult struct {
+	Context string
+	Server  string
+	Value   interface{}
+	Err     error
+}
+
+// QueryContexts calls query with a client for every configured context concurrently,
+// returning the results in context name order
+func QueryContexts(query func(apiClient *client.Client) (interface{}, error)) []ContextResult {
+	names := cfg.ContextNames()
+	results := make([]ContextResult, len(names))
+	// Clients are created up front, as a shell session's clients are kept in a map
+	clients := make([]*client.Client, len(names))
+	for i, name := range names {
+		results[i] = ContextResult{Context: name, Server: cfg.Contexts[name].URL}
+		clients[i] = NewClientFor(results[i].Server)
+	}
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i].Value, results[i].Err = query(clients[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// CombineContextListings queries every configured context for a listing of the given
+// columns and combines them into one, with a first column naming each row's context
+// and --columns and --sort-by applied. Contexts that fail are warned about on stderr
+// and left out; the error returned then sets the exit code once the listing is printed.
+func CombineContextListings(columns []output.Column, listing func(apiClient *client.Client) (*output.Listing, error)) (*output.Listing, error) {
+	results := QueryContexts(func(apiClient *client.Client) (interface{}, error) {
+		return listing(apiClient)
+	})
+
+	var contexts []string
+	var listings []*output.Listing
+	for _, result := range results {
+		if result.Err == nil {
+			contexts = append(contexts, result.Context)
+			listings = append(listings, result.Value.(*output.Listing))
+		}
+	}
+	combined := output.CombineListings(columns, contexts, listings)
+	if err := ApplyListFlags(combined); err != nil {
+		return nil, err
+	}
+	return combined, ContextsError(results)
+}
+
+// ContextsError warns on stderr of each context that couldn't be queried and returns an
+// error if there were any, marked as already reported
+func ContextsError(results []ContextResult) error {
+	var failed int
+	var first error
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Warning: context '%s' (%s): %v\n", result.Context, result.Server, result.Err)
+		if first == nil {
+			first = result.Err
+		}
+		failed++
+	}
+	if failed == 0 {
+		return nil
+	}
+	return CheckFailed(fmt.Errorf("failed to query %d of %d contexts: %w", failed, len(results), first))
+}
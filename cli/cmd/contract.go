@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// contractCmd represents the contract command
+var contractCmd = &cobra.Command{
+	Use:   "contract",
+	Short: "Verify consumers' contracts against topic schemas",
+	Long:  `Check the events consumers expect, given as example payloads per event type, against the current schemas of their topics, so producers can't break consumers unknowingly.`,
+}
+
+// ContractCmd returns the contract command for use in subcommands
+func ContractCmd() *cobra.Command {
+	return contractCmd
+}
+
+func init() {
+	rootCmd.AddCommand(contractCmd)
+}
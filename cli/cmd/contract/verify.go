@@ -0,0 +1,91 @@
+package contract
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/contract"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <contract dir>...",
+	Short: "Verify consumers' example events against topic schemas",
+	Long: `Check each consumer contract, a directory of the events the consumer expects, against
+the current schemas of their topics. A contract has a directory per topic holding, for
+each event type, a '<type>.json' file with an example payload or an array of them, or a
+'<type>' directory of such files:
+
+  contracts/billing/
+    orders/
+      order.placed.json
+      order.shipped/
+        minimal.json
+        with-tracking.json
+
+An example fails if its topic doesn't exist or has no schema for its type, if it has a
+field the schema doesn't declare, such as one the producer has removed or renamed, or if
+a value doesn't match the schema's type, format or enum. Fields an example leaves out are
+fine, even required ones: the consumer doesn't rely on them.
+
+The command fails if any example fails, so a producer's CI can run it against every
+consumer's contract before a schema change is rolled out.
+
+Examples:
+  # Verify the billing service's contract
+  es contract verify contracts/billing
+
+  # Verify every consumer's contract, listing only the failures' examples
+  es contract verify contracts/* -q`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		var examples []contract.Example
+		for _, dir := range args {
+			loaded, err := contract.Load(dir)
+			if err != nil {
+				return err
+			}
+			examples = append(examples, loaded...)
+		}
+		report, err := contract.Verify(apiClient, examples)
+		if err != nil {
+			return err
+		}
+
+		if cmd.Quiet() {
+			var failed []string
+			for _, result := range report.Results {
+				if !result.Passed {
+					failed = append(failed, result.Example)
+				}
+			}
+			output.PrintIDs(failed)
+		} else {
+			switch cfg.Output.Format {
+			case "json":
+				if err := output.PrintJSON(report); err != nil {
+					return err
+				}
+			case "csv":
+				if err := output.PrintContractReportCSV(report); err != nil {
+					return err
+				}
+			default:
+				output.PrintContractReport(report)
+			}
+		}
+
+		if report.Failed > 0 {
+			return cmd.CheckFailed(fmt.Errorf("%d of %d example(s) don't match their topic's schema", report.Failed, len(report.Results)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.ContractCmd().AddCommand(verifyCmd)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare event stores",
+	Long:  `Compare the topics, schemas and consumers of event stores to find where environments have drifted apart.`,
+}
+
+// DiffCmd returns the diff command for use in subcommands
+func DiffCmd() *cobra.Command {
+	return diffCmd
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/drift"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var exitCode bool
+
+var contextsCmd = &cobra.Command{
+	Use:   "contexts <from> <to>",
+	Short: "Compare the topics, schemas and consumers of two contexts",
+	Long: `Compare the topics, schemas and consumers of two contexts configured in the config file
+and report how they have drifted apart: topics and event types only in one of them,
+schema fields added, removed, retyped or made required, and consumers only in one of
+them or subscribed to different topics.
+
+Consumer IDs are generated by each server, so consumers are matched by the path of
+their callback URLs, ignoring the host. A server URL can be given instead of a context.
+
+Examples:
+  # What differs between staging and prod?
+  es diff contexts staging prod
+
+  # Fail a CI job when staging has drifted from prod
+  es diff contexts staging prod --exit-code
+
+  # Compare a context with a server that isn't configured
+  es diff contexts prod http://localhost:8000`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+
+		stores := make([]drift.Store, len(args))
+		servers := make([]string, len(args))
+		for i, arg := range args {
+			stores[i].Name = arg
+			servers[i] = arg
+			if !strings.Contains(arg, "://") {
+				server, err := cfg.Context(arg)
+				if err != nil {
+					return err
+				}
+				servers[i] = server.URL
+			}
+		}
+
+		errs := make([]error, len(args))
+		var wg sync.WaitGroup
+		for i := range stores {
+			apiClient := cmd.NewClientFor(servers[i])
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var err error
+				if stores[i].Topics, err = apiClient.GetTopics(); err != nil {
+					errs[i] = fmt.Errorf("failed to list the topics of %s: %w", stores[i].Name, err)
+					return
+				}
+				if stores[i].Consumers, err = apiClient.GetConsumers(); err != nil {
+					errs[i] = fmt.Errorf("failed to list the consumers of %s: %w", stores[i].Name, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+
+		report := drift.Compare(stores[0], stores[1])
+		switch cfg.Output.Format {
+		case "json":
+			if err := output.PrintJSON(report); err != nil {
+				return err
+			}
+		case "csv":
+			if err := output.PrintDriftCSV(report); err != nil {
+				return err
+			}
+		default:
+			output.PrintDrift(report)
+		}
+
+		if exitCode && len(report.Differences) > 0 {
+			return cmd.CheckFailed(fmt.Errorf("%s and %s differ", report.From, report.To))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.DiffCmd().AddCommand(contextsCmd)
+	contextsCmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 1 if there are differences, for CI checks")
+}
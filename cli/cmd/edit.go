@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/internal/compose"
+	"github.com/event-store/cli/internal/editor"
+	"github.com/event-store/cli/internal/output"
+	"golang.org/x/term"
+)
+
+// EditJSON opens value as JSON in the user's editor ($VISUAL or $EDITOR), for commands'
+// --edit flags, and returns what was saved once check accepts it, the changes have been
+// shown as a diff and the user has answered yes to question. A save that fails check can
+// be edited again. pattern names the file edited, as for os.CreateTemp. It returns nil if
+// nothing was changed, and ErrAborted if the user gives up or answers no.
+func EditJSON(value interface{}, pattern, question string, check func([]byte) error) ([]byte, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("--edit needs a terminal to run the editor on")
+	}
+	original, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	original = append(original, '\n')
+
+	composer := compose.New(os.Stdin, os.Stderr)
+	content := original
+	for {
+		if content, err = editor.Edit(content, pattern); err != nil {
+			return nil, err
+		}
+		if bytes.Equal(bytes.TrimSpace(content), bytes.TrimSpace(original)) {
+			return nil, nil
+		}
+		err := check(content)
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		again, err := composer.Confirm("Edit again?", true)
+		if err != nil {
+			return nil, err
+		}
+		if !again {
+			return nil, ErrAborted
+		}
+	}
+
+	// Compare the JSON as the original is laid out, so only changed values show
+	var edited bytes.Buffer
+	if err := json.Indent(&edited, bytes.TrimSpace(content), "", "  "); err != nil {
+		return nil, err
+	}
+	edited.WriteByte('\n')
+	changes := editor.Diff(string(original), edited.String())
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	fmt.Fprintln(os.Stderr)
+	output.PrintDiff(os.Stderr, changes)
+	fmt.Fprintln(os.Stderr)
+	apply, err := composer.Confirm(question, true)
+	if err != nil {
+		return nil, err
+	}
+	if !apply {
+		return nil, ErrAborted
+	}
+	return content, nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/event-store/cli/internal/auth"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, so scripts can tell a mistake on the command line from a server that is
+// down or a request the server rejected
+const (
+	ExitError       = 1 // anything else
+	ExitUsage       = 2 // unknown command, bad flags or arguments
+	ExitUnreachable = 3 // no response from the server
+	ExitRejected    = 4 // the server answered with a 4xx status
+	ExitServerError = 5 // the server answered with a 5xx status
+)
+
+// started is set once a command's flags and arguments have been checked; errors before
+// then are usage errors
+var started bool
+
+// checkFailed is the error of a command whose output already shows what failed
+type checkFailed struct {
+	error
+}
+
+// CheckFailed marks an error, such as failing tests, that a command's JSON or CSV output
+// already describes: it sets the exit code, but is only printed with table output
+func CheckFailed(err error) error {
+	return checkFailed{err}
+}
+
+// usageChecks runs cobra's flag checks that would otherwise come after PersistentPreRunE,
+// so that their errors are reported as usage errors too
+func usageChecks(c *cobra.Command) error {
+	if err := c.ValidateRequiredFlags(); err != nil {
+		return err
+	}
+	return c.ValidateFlagGroups()
+}
+
+// reportError prints a command's error in the output format, to stdout for JSON and CSV
+// and to stderr for tables, and returns the exit code for it
+func reportError(c *cobra.Command, err error) int {
+	report, code := describeError(c, err)
+
+	format := outputFormat
+	if cfg != nil {
+		format = cfg.Output.Format
+	}
+	if errors.As(err, new(checkFailed)) && (format == "json" || format == "csv") {
+		return code
+	}
+	switch format {
+	case "json":
+		output.PrintErrorJSON(report)
+	case "csv":
+		output.PrintErrorCSV(report)
+	default:
+		output.PrintError(report)
+	}
+	return code
+}
+
+// describeError classifies an error into its report and exit code
+func describeError(c *cobra.Command, err error) (output.ErrorReport, int) {
+	report := output.ErrorReport{Error: err.Error(), Code: "error"}
+
+	var apiErr *client.APIError
+	var expiredErr *auth.ExpiredError
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case !started:
+		report.Code = "usage"
+		if c != nil {
+			report.Hint = fmt.Sprintf("Run '%s --help' for usage", c.CommandPath())
+		}
+		return report, ExitUsage
+	case errors.As(err, &apiErr):
+		status := apiErr.StatusCode
+		report.HTTPStatus = &status
+		report.Code, report.Hint = describeStatus(status)
+		if apiErr.Code != "" {
+			report.Code = apiErr.Code
+		}
+		if status >= 500 {
+			return report, ExitServerError
+		}
+		return report, ExitRejected
+	case errors.As(err, &expiredErr):
+		report.Code = "unauthorized"
+		report.Hint = "Log in again with 'es auth login'"
+		return report, ExitRejected
+	case errors.As(err, &netErr) && netErr.Timeout():
+		report.Code = "timeout"
+		report.Hint = "The server did not answer in time; check its load and try again"
+		return report, ExitUnreachable
+	case errors.As(err, &urlErr) && proxyFailed(urlErr):
+		report.Code = "unreachable"
+		report.Hint = "Check that the proxy is running and reachable, or set --proxy (or 'direct' for none)"
+		return report, ExitUnreachable
+	case errors.As(err, &urlErr):
+		report.Code = "unreachable"
+		report.Hint = fmt.Sprintf("Check that the event store is running at %s, or set --server-url", serverOf(urlErr))
+		return report, ExitUnreachable
+	case errors.As(err, new(checkFailed)):
+		report.Code = "failed"
+	case errors.Is(err, context.Canceled):
+		report.Code = "interrupted"
+	case errors.Is(err, ErrAborted):
+		report.Code = "aborted"
+	}
+	return report, ExitError
+}
+
+// describeStatus returns the code and hint for an HTTP error status
+func describeStatus(status int) (string, string) {
+	switch {
+	case status == 400:
+		return "bad_request", "The server rejected the request; check the command's arguments and any JSON given"
+	case status == 401:
+		return "unauthorized", "The server refused access; log in with 'es auth login', or check the credentials for this server"
+	case status == 403:
+		return "forbidden", "Your roles don't allow this; 'es auth whoami' shows who you are logged in as"
+	case status == 404:
+		return "not_found", "Check the name or ID; 'es topic list' and 'es consumer list' show what exists"
+	case status == 409:
+		return "conflict", "It already exists or was changed at the same time; check its current state"
+	case status == 422:
+		return "invalid", "The server rejected the data; check it against the topic's schemas"
+	case status == 429:
+		return "rate_limited", "The server is throttling requests; wait and try again"
+	case status >= 500:
+		return "server_error", "The server failed to handle the request; check its logs and try again"
+	}
+	return "http_error", ""
+}
+
+// proxyFailed reports whether a request failed because its proxy couldn't be reached or
+// refused to connect to the server. net/http's errors for these have no type of their own.
+func proxyFailed(err *url.Error) bool {
+	message := err.Err.Error()
+	return strings.HasPrefix(message, "proxyconnect ") || strings.HasPrefix(message, "socks connect ")
+}
+
+// serverOf returns the scheme and host of a failed request's URL
+func serverOf(err *url.Error) string {
+	u, parseErr := url.Parse(err.URL)
+	if parseErr != nil || u.Host == "" {
+		return err.URL
+	}
+	return u.Scheme + "://" + u.Host
+}
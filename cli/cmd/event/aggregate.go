@@ -0,0 +1,141 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/aggregate"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	aggregateGroupBy     []string
+	aggregateInterval    time.Duration
+	aggregateFilters     []string
+	aggregateFromEventID string
+	aggregateSince       string
+	aggregateUntil       string
+)
+
+var aggregateCmd = &cobra.Command{
+	Use:   "aggregate <topic>",
+	Short: "Count a topic's events by type, payload field or time",
+	Long: `Count a topic's events grouped by one or more fields, and optionally by time bucket.
+
+--group-by takes the same fields as --filter: 'type', 'id', 'payload.path' or a bare
+payload path; events without the field are counted under '(none)'. With --interval the
+events are also bucketed by timestamp (UTC), e.g. per hour with --interval 1h.
+
+The events are read page by page and counted in the CLI, so only the counts are kept in
+memory. Results are printed as a pivot table: a row per time bucket and a column per
+group with --interval, or a row per value of the first field and a column per value of
+the second when grouping by two fields. With -o json the counts are a series per group,
+aligned with the list of buckets.
+
+Examples:
+  # Count events by type
+  es event aggregate orders
+
+  # Hourly counts of each event type
+  es event aggregate orders --group-by type --interval 1h
+
+  # Orders by region and status
+  es event aggregate orders --group-by payload.region,payload.status --filter type:order.placed
+
+  # Daily series for the last week as JSON, for a dashboard
+  es event aggregate orders --interval 24h --since 7d -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		if len(aggregateGroupBy) == 0 {
+			return fmt.Errorf("--group-by needs at least one field")
+		}
+		for _, field := range aggregateGroupBy {
+			if strings.TrimSpace(field) == "" {
+				return fmt.Errorf("--group-by has an empty field")
+			}
+		}
+		if aggregateInterval < 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		timeRange, err := timerange.Parse(aggregateSince, aggregateUntil, time.Now())
+		if err != nil {
+			return err
+		}
+		filters := make([]*filter.Filter, len(aggregateFilters))
+		for i, expr := range aggregateFilters {
+			f, err := filter.Parse(expr)
+			if err != nil {
+				return err
+			}
+			filters[i] = f
+		}
+
+		topic, err := apiClient.GetTopic(topicName)
+		if err != nil {
+			return err
+		}
+		total := topic.Sequence
+		if aggregateFromEventID != "" {
+			id, err := eventid.Parse(aggregateFromEventID)
+			if err != nil {
+				return fmt.Errorf("invalid --from-event-id: %w", err)
+			}
+			total -= int(id.Sequence)
+		}
+
+		aggregator := aggregate.New(aggregate.Options{GroupBy: aggregateGroupBy, Interval: aggregateInterval})
+		progress := output.NewProgress("Aggregating", total)
+		query := timeRangeQuery(apiClient, aggregateFromEventID, timeRange)
+		err = apiClient.ScanEventsQuery(topicName, query, func(events []client.Event) (bool, error) {
+			for _, event := range events {
+				if timeRange.Past(event.Timestamp) {
+					return false, nil
+				}
+				if timeRange.Contains(event.Timestamp) && filter.MatchAll(filters, event) {
+					aggregator.Add(event)
+				}
+			}
+			progress.Add(len(events))
+			return true, nil
+		})
+		progress.Finish()
+		if err != nil {
+			return err
+		}
+		result, err := aggregator.Result(topicName)
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(result)
+		case "csv":
+			return output.PrintAggregateResultCSV(result)
+		default:
+			output.PrintAggregateResult(result)
+			return nil
+		}
+	},
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(aggregateCmd)
+	aggregateCmd.Flags().StringSliceVar(&aggregateGroupBy, "group-by", []string{"type"}, "Fields to group events by: type, id, payload.<path> or a payload path (comma-separated or repeatable)")
+	aggregateCmd.Flags().DurationVar(&aggregateInterval, "interval", 0, "Also bucket events by timestamp, e.g. 1h or 24h (0 = no time buckets)")
+	aggregateCmd.Flags().StringArrayVar(&aggregateFilters, "filter", nil, "Only count events matching this filter ('field:value', repeatable; all must match)")
+	aggregateCmd.Flags().StringVar(&aggregateFromEventID, "from-event-id", "", "Only count events after this event ID")
+	aggregateCmd.Flags().StringVar(&aggregateSince, "since", "", "Only count events at or after this time: timestamp, date, 'today', 'yesterday' or a duration ago such as '2h' or '7d'")
+	aggregateCmd.Flags().StringVar(&aggregateUntil, "until", "", "Only count events before this time (same formats as --since)")
+}
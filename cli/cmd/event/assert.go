@@ -0,0 +1,123 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/assert"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	assertFilter      string
+	assertCount       string
+	assertWithin      time.Duration
+	assertInterval    time.Duration
+	assertFromEventID string
+)
+
+var assertCmd = &cobra.Command{
+	Use:   "assert <topic>",
+	Short: "Check how many events match a filter, for CI scripts",
+	Long: `Count a topic's events matching a filter and check the count, exiting with an error
+when the check fails. With --within, keep checking until the count is met or the time is
+up; checks such as '>=1' pass as soon as enough events appear, while checks such as '=0'
+or '<3' fail as soon as too many appear.
+
+--filter joins 'field=value' (or 'field:value') terms with AND or &&; all must match.
+Without --filter every event counts. --count takes =, >=, >, <= or < followed by a number; a
+bare number means exactly that many.
+
+Use -o json for a machine-readable result including the first matching event IDs.
+
+Examples:
+  # Wait up to 2 minutes for an order to ship
+  es event assert orders --filter 'type=order.shipped AND payload.orderId=42' --count '>=1' --within 2m
+
+  # Fail if any payment failed after an event
+  es event assert payments --filter type=payment.failed --count 0 --from-event-id payments-1200
+
+  # Machine-readable result
+  es event assert orders --filter type=order.created --count '>=100' -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		count, err := assert.ParseCount(assertCount)
+		if err != nil {
+			return err
+		}
+		var filters []*filter.Filter
+		if assertFilter != "" {
+			if filters, err = filter.ParseAll(assertFilter); err != nil {
+				return err
+			}
+		}
+		if assertInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		var result *assert.Result
+		var checkErr error
+		group.Go("event-assert", func(ctx context.Context) error {
+			result, checkErr = assert.Check(ctx, apiClient, assert.Options{
+				Topic:    args[0],
+				Since:    assertFromEventID,
+				Filters:  filters,
+				Count:    count,
+				Within:   assertWithin,
+				Interval: assertInterval,
+			})
+			group.Stop()
+			return nil
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+		if checkErr != nil && !errors.Is(checkErr, context.Canceled) {
+			return checkErr
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			if err := output.PrintJSON(result); err != nil {
+				return err
+			}
+		case "csv":
+			if err := output.PrintAssertResultCSV(result); err != nil {
+				return err
+			}
+		default:
+			output.PrintAssertResult(result)
+		}
+
+		if checkErr != nil {
+			return fmt.Errorf("assertion interrupted")
+		}
+		if !result.Passed {
+			return cmd.CheckFailed(fmt.Errorf("assertion failed: %d matching event(s), expected %s", result.Matched, result.Count))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(assertCmd)
+	cmd.DisablePager(assertCmd)
+	assertCmd.Flags().StringVar(&assertFilter, "filter", "", "Only count events matching these filters, e.g. 'type=order.shipped AND payload.orderId=42'")
+	assertCmd.Flags().StringVar(&assertCount, "count", ">=1", "Expected number of matching events, e.g. '>=1', '=0' or '<5'")
+	assertCmd.Flags().DurationVar(&assertWithin, "within", 0, "Keep checking until the count is met or this time is up (default: check once)")
+	assertCmd.Flags().DurationVar(&assertInterval, "interval", time.Second, "How often to check while waiting")
+	assertCmd.Flags().StringVar(&assertFromEventID, "from-event-id", "", "Only count events after this event ID")
+}
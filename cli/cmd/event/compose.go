@@ -0,0 +1,160 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/compose"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	composeType string
+	composeOut  string
+)
+
+var composeCmd = &cobra.Command{
+	Use:   "compose <topic>",
+	Short: "Interactively build and publish an event",
+	Long: `Build an event field by field from the topic's schema for an event type, then publish
+it or save it to a file.
+
+Each property is prompted for with its type, format and allowed values, and answers are
+checked against the schema (types, enum, minimum/maximum, minLength/maxLength, pattern,
+and the date-time, date, email, uuid and uri formats) before moving on. Press Enter to
+take a property's default or to skip an optional property. Arrays are entered as a JSON
+array or as comma-separated values.
+
+Several events can be composed in one session. Prompts are written to stderr.
+
+Examples:
+  # Compose an event, choosing the event type from a list
+  es event compose user-events
+
+  # Compose user.created events and save them for 'es event publish'
+  es event compose user-events --type user.created --out new-users.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		topic, err := apiClient.GetTopic(topicName)
+		if err != nil {
+			return err
+		}
+		if len(topic.Schemas) == 0 {
+			return fmt.Errorf("topic '%s' has no schemas to compose events from", topicName)
+		}
+
+		composer := compose.New(os.Stdin, os.Stderr)
+		var events []client.EventPublishRequest
+		for {
+			schema, err := chooseSchema(composer, topic, composeType)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "\nComposing a %s event for '%s'\n", schema.EventType, topicName)
+			payload, err := composer.Payload(schema)
+			if err != nil {
+				return err
+			}
+
+			event := client.EventPublishRequest{Topic: topicName, Type: schema.EventType, Payload: payload}
+			preview, _ := json.MarshalIndent(event, "", "  ")
+			fmt.Fprintf(os.Stderr, "\n%s\n\n", preview)
+
+			keep, err := composer.Confirm("Keep this event?", true)
+			if err != nil {
+				return err
+			}
+			if keep {
+				events = append(events, event)
+			}
+			another, err := composer.Confirm("Compose another event?", false)
+			if err != nil {
+				return err
+			}
+			if !another {
+				break
+			}
+		}
+
+		if len(events) == 0 {
+			fmt.Fprintln(os.Stderr, "No events composed")
+			return nil
+		}
+		if composeOut != "" {
+			return writeGeneratedEvents(composeOut, events)
+		}
+
+		publish, err := composer.Confirm(fmt.Sprintf("Publish %d event(s) to %s?", len(events), cfg.Server.URL), true)
+		if err != nil {
+			return err
+		}
+		if !publish {
+			fmt.Fprintln(os.Stderr, "Not published")
+			return nil
+		}
+
+		eventIDs, err := apiClient.PublishEvents(events)
+		if err != nil {
+			return err
+		}
+
+		if cmd.Quiet() {
+			output.PrintIDs(eventIDs)
+			return nil
+		}
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintEventPublishResponseJSON(eventIDs)
+		case "csv":
+			return output.PrintEventPublishResponseCSV(eventIDs)
+		default:
+			output.PrintEventPublishResponse(eventIDs)
+			return nil
+		}
+	},
+}
+
+// chooseSchema returns the schema for an event type, or with none asks which event type
+// to compose when the topic has more than one
+func chooseSchema(composer *compose.Composer, topic *client.Topic, eventType string) (client.Schema, error) {
+	if eventType != "" {
+		schemas, err := selectSchemas(topic, []string{eventType})
+		if err != nil {
+			return client.Schema{}, err
+		}
+		return schemas[0], nil
+	}
+	if len(topic.Schemas) == 1 {
+		return topic.Schemas[0], nil
+	}
+
+	types := make([]string, len(topic.Schemas))
+	for i, schema := range topic.Schemas {
+		types[i] = schema.EventType
+	}
+	eventType, err := composer.Choose("Event type", types)
+	if err != nil {
+		return client.Schema{}, err
+	}
+	for _, schema := range topic.Schemas {
+		if schema.EventType == eventType {
+			return schema, nil
+		}
+	}
+	return client.Schema{}, fmt.Errorf("topic '%s' has no schema for event type '%s'", topic.Name, eventType)
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(composeCmd)
+	cmd.DisablePager(composeCmd)
+	composeCmd.Flags().StringVar(&composeType, "type", "", "Event type to compose (default: choose from the topic's types)")
+	composeCmd.Flags().StringVar(&composeOut, "out", "", "Save the events to this file ('-' for stdout) in the 'es event publish' format instead of publishing them")
+}
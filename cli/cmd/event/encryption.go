@@ -0,0 +1,101 @@
+package event
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/envelope"
+)
+
+var (
+	publishEncrypt       bool
+	publishEncryptFields []string
+	publishKeyFile       string
+
+	// publishKeys encrypts the events published with --encrypt, or is nil
+	publishKeys *envelope.KeyRing
+)
+
+// loadPublishKeys loads the --key-file of a publish with --encrypt
+func loadPublishKeys() error {
+	if !publishEncrypt {
+		if publishKeyFile != "" || len(publishEncryptFields) > 0 {
+			return fmt.Errorf("--key-file and --encrypt-fields are only used with --encrypt")
+		}
+		return nil
+	}
+	if publishKeyFile == "" {
+		return fmt.Errorf("--encrypt needs a --key-file")
+	}
+	if err := envelope.CheckFields(publishEncryptFields); err != nil {
+		return err
+	}
+	keys, err := envelope.LoadKeyRing(publishKeyFile)
+	if err != nil {
+		return err
+	}
+	if err := keys.CanEncrypt(); err != nil {
+		return err
+	}
+	publishKeys = keys
+	return nil
+}
+
+// encryptEvents encrypts the --encrypt-fields of events' payloads with --encrypt,
+// recording the key and the fields in the events' metadata when the server keeps it
+func encryptEvents(apiClient *client.Client, events []client.EventPublishRequest) error {
+	if publishKeys == nil {
+		return nil
+	}
+	recorded := apiClient.Supports(client.FeatureEventMetadata)
+	for i := range events {
+		fields, err := publishKeys.Encrypt(&events[i], publishEncryptFields)
+		if err != nil {
+			return err
+		}
+		if !recorded || len(fields) == 0 {
+			continue
+		}
+		if events[i].Metadata == nil {
+			events[i].Metadata = map[string]interface{}{}
+		}
+		events[i].Metadata[envelope.MetadataKey] = map[string]interface{}{
+			"keyId":  publishKeys.CurrentKey(),
+			"fields": fields,
+		}
+	}
+	return nil
+}
+
+// loadDecryptKeys loads the key file of a command's --decrypt, or returns nil without it
+func loadDecryptKeys(decrypt bool, keyFile string) (*envelope.KeyRing, error) {
+	switch {
+	case !decrypt && keyFile != "":
+		return nil, fmt.Errorf("--key-file is only used with --decrypt")
+	case !decrypt:
+		return nil, nil
+	case keyFile == "":
+		return nil, fmt.Errorf("--decrypt needs a --key-file")
+	}
+	return envelope.LoadKeyRing(keyFile)
+}
+
+// decryptEvents decrypts the encrypted payload fields of events read from the event store
+// with --decrypt's keys, if any. Fields that can't be decrypted are left encrypted, with
+// a warning.
+func decryptEvents(keys *envelope.KeyRing, events []client.Event) {
+	for i := range events {
+		decryptEvent(keys, &events[i])
+	}
+}
+
+// decryptEvent decrypts an event as decryptEvents does
+func decryptEvent(keys *envelope.KeyRing, event *client.Event) {
+	if keys == nil {
+		return
+	}
+	if _, err := keys.Decrypt(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
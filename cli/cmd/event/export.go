@@ -0,0 +1,622 @@
+package event
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/compress"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/export"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/seal"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOut         string
+	exportFormat      string
+	exportCompression string
+	exportCompress    string
+	exportRawPayload  bool
+	exportFromEventID string
+	exportSince       string
+	exportUntil       string
+	exportVerify      bool
+	exportFilter      string
+	exportMask        []string
+
+	exportEncrypt        bool
+	exportPassphraseFile string
+	exportRecipients     []string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <topic>...",
+	Short: "Export topics' events to NDJSON, Avro or Parquet files",
+	Long: `Export the events of a topic to a file that analytics tools such as Spark, BigQuery
+or DuckDB can load directly.
+
+ndjson writes each event as a JSON line. avro and parquet write a record per event with
+the event's id, type and timestamp, then a column for each top-level payload property
+declared by the topic's JSON schemas, typed from the schemas: strings, integers
+(int64), numbers (double) and booleans keep their types, and objects, arrays and
+properties the schemas disagree on are stored as JSON strings. --raw-payload adds the
+whole payload as a JSON column too, which is also done when the topic has no schemas.
+
+The format is taken from the file's extension (.ndjson, .jsonl, .avro or .parquet)
+unless --format is given. Avro files are compressed with deflate unless
+--compression null, and Parquet files with gzip unless --compression none.
+
+NDJSON files are compressed whole with --compress gzip or zstd as they're written, so
+exports of millions of events stay small without temporary files; the codec is taken
+from a .gz or .zst extension, such as orders.ndjson.gz, unless --compress is given.
+Reading an export back, such as by --verify or 'es backup verify', detects its
+compression from its contents.
+
+A manifest is written next to the file, <file>.manifest.json, with its SHA-256 checksum,
+size and number of events. --verify re-reads the file and checks it against them before
+the export is reported done; 'es backup verify' checks it again later, such as after
+copying it elsewhere.
+
+--filter exports only the events matching a 'field:value' filter, and --mask exports
+fields such as card numbers as '***'; both are recorded in the manifest. They default to
+the topic's filter and mask under 'topics.<topic>.defaults' in the config file, and
+--filter '' or --mask '' exports every event or field. output.mask, which is for
+printed output, isn't applied to exports.
+
+--encrypt encrypts the file as an age file, so exports holding personal data can be
+stored safely: with the passphrase in --passphrase-file, or for each --recipient, an age
+public key (age1...) whose identity can decrypt it. The manifest's checksum is of the
+encrypted file. 'es backup decrypt' or 'age -d' decrypts it again, and 'es backup
+verify' checks it.
+
+Several topics can be exported at once, by name, as a comma-separated list, or with a
+glob pattern such as 'order-*' matched against the server's topics. --out is then a
+directory, created if need be, with a file per topic named after it, such as
+orders.ndjson, in the --format (default ndjson) and --compress given, and its manifest.
+Each topic's default filter and mask apply to its file.
+
+The export runs as a job: if it's stopped part way, such as by Ctrl+C or a lost
+connection, 'es job resume' continues an NDJSON file after the last page of events
+written, and writes an Avro or Parquet file, or a compressed or encrypted file, again
+from the start. An export of several topics skips the files it already wrote.
+
+Examples:
+  # Export a whole topic to Parquet
+  es event export user-events --out user-events.parquet
+
+  # Export last week's events to Avro, keeping the full payloads
+  es event export orders --out orders.avro --since 7d --until today --raw-payload
+
+  # Export a backup, checking it was written intact
+  es event export orders --out backups/orders.ndjson --verify
+
+  # Export a large topic compressed with zstd
+  es event export clicks --out clicks.ndjson.zst
+
+  # Export a topic's cancelled orders, without card numbers
+  es event export orders --out cancelled.ndjson --filter type:order.cancelled --mask payload.card
+
+  # Export an encrypted backup for two age recipients
+  es event export customers --out customers.ndjson --encrypt --recipient age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p --recipient age1lggyhqrw2nlhcxprm67z43rta597azn8gknawjehu9d9dl0jq3yqqvfafg
+
+  # Export every order topic to a directory of Parquet files
+  es event export 'order-*' --out backups/ --format parquet
+
+  # Export uncompressed, to a file without a known extension
+  es event export orders --out orders.bin --format parquet --compression none`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topics, err := cmd.ResolveTopics(apiClient, args)
+		if err != nil {
+			return err
+		}
+		if len(args) > 1 || cmd.IsTopicSelector(args[0]) {
+			return exportTopics(cobraCmd, apiClient, topics)
+		}
+		topicName := topics[0]
+		if err := cmd.ApplyTopicDefaults(cobraCmd, topicName); err != nil {
+			return err
+		}
+		var eventFilter *filter.Filter
+		if exportFilter != "" {
+			if eventFilter, err = filter.Parse(exportFilter); err != nil {
+				return err
+			}
+		}
+		if err := output.CheckMaskFields(exportMask); err != nil {
+			return err
+		}
+		keys, err := loadExportKeys()
+		if err != nil {
+			return err
+		}
+		format, compression, err := exportFileFormat(exportOut)
+		if err != nil {
+			return err
+		}
+		timeRange, err := timerange.Parse(exportSince, exportUntil, time.Now())
+		if err != nil {
+			return err
+		}
+
+		topic, err := apiClient.GetTopic(topicName)
+		if err != nil {
+			return err
+		}
+		total := topic.Sequence
+		if exportFromEventID != "" {
+			id, err := eventid.Parse(exportFromEventID)
+			if err != nil {
+				return fmt.Errorf("invalid --from-event-id: %w", err)
+			}
+			total -= int(id.Sequence)
+		}
+
+		checkpoint := &exportCheckpoint{Now: time.Now()}
+		job, resumed, err := cmd.StartJob("export", total, checkpoint)
+		if err != nil {
+			return err
+		}
+		if resumed {
+			// Relative times are from when the export started
+			if timeRange, err = timerange.Parse(exportSince, exportUntil, checkpoint.Now); err != nil {
+				return job.Finish(err)
+			}
+			if !appendable(format, compression, keys != nil) {
+				checkpoint.restart()
+			}
+		}
+
+		result := &export.Result{
+			Topic:        topicName,
+			File:         exportOut,
+			Format:       format,
+			Compression:  compression,
+			Filter:       exportFilter,
+			Masked:       exportMask,
+			Encrypted:    keys != nil,
+			Events:       checkpoint.Events,
+			FirstEventID: checkpoint.FirstEventID,
+			LastEventID:  checkpoint.LastEventID,
+		}
+		if err := exportFile(apiClient, topic, result, keys, timeRange, eventFilter, total, job, checkpoint); err != nil {
+			return job.Finish(err)
+		}
+		if err := job.Finish(nil); err != nil {
+			return err
+		}
+		if exportVerify {
+			if err := verifyExport(result, keys); err != nil {
+				return fmt.Errorf("the export failed verification: %w", err)
+			}
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(result)
+		case "csv":
+			return output.PrintExportResultCSV(result)
+		default:
+			output.PrintExportResult(result)
+			return nil
+		}
+	},
+}
+
+// exportTopics exports the events of several topics, each to a file in the --out
+// directory named after the topic, with the topic's own default filter and mask, as one
+// job: resuming it skips the topics already exported
+func exportTopics(cobraCmd *cobra.Command, apiClient *client.Client, names []string) error {
+	cfg := cmd.GetConfig()
+	if exportFromEventID != "" {
+		return fmt.Errorf("--from-event-id can't be used exporting several topics")
+	}
+	if info, err := os.Stat(exportOut); err == nil && !info.IsDir() {
+		return fmt.Errorf("--out '%s' must be a directory to export several topics", exportOut)
+	}
+	keys, err := loadExportKeys()
+	if err != nil {
+		return err
+	}
+	// Files are named after their topics, so --format and --compress aren't taken from --out
+	format, compression, err := exportFileFormat("")
+	if err != nil {
+		return err
+	}
+	extension := "." + format + map[string]string{"gzip": ".gz", "zstd": ".zst"}[compression]
+	timeRange, err := timerange.Parse(exportSince, exportUntil, time.Now())
+	if err != nil {
+		return err
+	}
+
+	topics := make([]*client.Topic, len(names))
+	filters := make([]string, len(names))
+	eventFilters := make([]*filter.Filter, len(names))
+	masks := make([][]string, len(names))
+	total := 0
+	for i, name := range names {
+		if topics[i], err = apiClient.GetTopic(name); err != nil {
+			return err
+		}
+		total += topics[i].Sequence
+		if filters[i] = cmd.TopicDefaultFilter(cobraCmd, name); filters[i] == "" {
+			filters[i] = exportFilter
+		}
+		if filters[i] != "" {
+			if eventFilters[i], err = filter.Parse(filters[i]); err != nil {
+				return fmt.Errorf("topic '%s': %w", name, err)
+			}
+		}
+		if masks[i] = cmd.TopicDefaultMask(cobraCmd, name); masks[i] == nil {
+			masks[i] = exportMask
+		}
+		if err := output.CheckMaskFields(masks[i]); err != nil {
+			return fmt.Errorf("topic '%s': %w", name, err)
+		}
+	}
+	if err := os.MkdirAll(exportOut, 0755); err != nil {
+		return err
+	}
+
+	checkpoint := &exportCheckpoint{Now: time.Now()}
+	job, resumed, err := cmd.StartJob("export", total, checkpoint)
+	if err != nil {
+		return err
+	}
+	if resumed {
+		// Relative times are from when the export started
+		if timeRange, err = timerange.Parse(exportSince, exportUntil, checkpoint.Now); err != nil {
+			return job.Finish(err)
+		}
+	}
+
+	results := make([]*export.Result, 0, len(topics))
+	for i, topic := range topics {
+		file := filepath.Join(exportOut, topic.Name+extension)
+		if slices.Contains(checkpoint.Done, topic.Name) {
+			result, err := export.ReadManifest(export.ManifestName(file))
+			if err != nil {
+				return job.Finish(err)
+			}
+			results = append(results, result)
+			continue
+		}
+		// A checkpoint in another topic's file, or in one that can't be continued, is dropped
+		if id, err := eventid.Parse(checkpoint.After); err != nil || id.Topic != topic.Name || !appendable(format, compression, keys != nil) {
+			checkpoint.restart()
+		}
+
+		result := &export.Result{
+			Topic:        topic.Name,
+			File:         file,
+			Format:       format,
+			Compression:  compression,
+			Filter:       filters[i],
+			Masked:       masks[i],
+			Encrypted:    keys != nil,
+			Events:       checkpoint.Events,
+			FirstEventID: checkpoint.FirstEventID,
+			LastEventID:  checkpoint.LastEventID,
+		}
+		if err := exportFile(apiClient, topic, result, keys, timeRange, eventFilters[i], topic.Sequence, job, checkpoint); err != nil {
+			return job.Finish(fmt.Errorf("topic '%s': %w", topic.Name, err))
+		}
+		results = append(results, result)
+
+		checkpoint.Done = append(checkpoint.Done, topic.Name)
+		checkpoint.DoneRead += checkpoint.Read
+		checkpoint.restart()
+		if err := job.Save(checkpoint, checkpoint.DoneRead); err != nil {
+			return job.Finish(err)
+		}
+	}
+	if err := job.Finish(nil); err != nil {
+		return err
+	}
+	if exportVerify {
+		for _, result := range results {
+			if err := verifyExport(result, keys); err != nil {
+				return fmt.Errorf("the export of topic '%s' failed verification: %w", result.Topic, err)
+			}
+		}
+	}
+
+	switch cfg.Output.Format {
+	case "json":
+		return output.PrintJSON(results)
+	case "csv":
+		return output.PrintExportResultCSV(results...)
+	default:
+		output.PrintExportResults(results)
+		return nil
+	}
+}
+
+// exportFileFormat works out the format and compression codec of an export to a file:
+// --format, else the format of the file's extension, else ndjson; and --compression for
+// Avro and Parquet, or --compress for NDJSON, else the codec of a .gz or .zst extension
+func exportFileFormat(file string) (string, string, error) {
+	format := exportFormat
+	if format == "" {
+		if format = export.FormatOf(file); format == "" {
+			format = "ndjson"
+		}
+	}
+	codec := exportCompression
+	if format == "ndjson" {
+		if exportCompression != "" {
+			return "", "", fmt.Errorf("ndjson files are compressed with --compress, not --compression")
+		}
+		if codec = exportCompress; codec == "" {
+			codec = compress.Of(file)
+		}
+	} else if exportCompress != "" {
+		return "", "", fmt.Errorf("--compress is only for ndjson exports; %s files compress their blocks with --compression", format)
+	}
+	compression, err := export.Compression(format, codec)
+	if err != nil {
+		return "", "", err
+	}
+	return format, compression, nil
+}
+
+// appendable reports whether an export's file can be continued by a resumed job: only
+// uncompressed, unencrypted NDJSON files can be appended to
+func appendable(format, compression string, encrypted bool) bool {
+	return format == "ndjson" && compression == "" && !encrypted
+}
+
+// exportFile exports a topic's events to the result's file, continuing it from the
+// checkpoint if there is one, and writes its manifest. A file that can't be continued
+// is removed if the export fails.
+func exportFile(apiClient *client.Client, topic *client.Topic, result *export.Result, keys *seal.Keys, timeRange timerange.Range, eventFilter *filter.Filter, total int, job *jobs.Job, checkpoint *exportCheckpoint) error {
+	var columns []export.Column
+	if result.Format != "ndjson" {
+		columns = export.Columns(topic.Schemas, exportRawPayload)
+		for _, column := range columns {
+			result.Columns = append(result.Columns, column.Name)
+		}
+	}
+
+	var file *os.File
+	var err error
+	if checkpoint.After != "" {
+		file, err = os.OpenFile(result.File, os.O_RDWR, 0)
+	} else {
+		file, err = os.Create(result.File)
+	}
+	if err != nil {
+		return err
+	}
+	if err := exportEvents(apiClient, topic.Name, file, columns, result.Compression, keys, timeRange, eventFilter, total, result, job, checkpoint); err != nil {
+		file.Close()
+		// A partial uncompressed NDJSON file is kept for the job to continue
+		if !appendable(result.Format, result.Compression, result.Encrypted) {
+			os.Remove(result.File)
+		}
+		return err
+	}
+	info, err := file.Stat()
+	if err == nil {
+		result.Bytes = info.Size()
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return export.WriteManifest(result)
+}
+
+// verifyExport re-reads an exported file for --verify, marking the result verified.
+// Exports encrypted only for recipients can't be decrypted to count their events.
+func verifyExport(result *export.Result, keys *seal.Keys) error {
+	var verifyKeys *seal.Keys
+	if keys != nil && len(keys.Passphrases) > 0 {
+		verifyKeys = &seal.Keys{Passphrases: keys.Passphrases}
+	}
+	if err := export.Verify(result, verifyKeys); err != nil {
+		return err
+	}
+	result.Verified = true
+	return nil
+}
+
+// exportCheckpoint is how far an export job got: the topics whose files were written,
+// when exporting several, and the events of the file being written, and the file's size,
+// through the last event read
+type exportCheckpoint struct {
+	Now          time.Time `json:"now"`
+	Done         []string  `json:"done,omitempty"`
+	DoneRead     int       `json:"doneRead,omitempty"` // events read from the topics done
+	After        string    `json:"after,omitempty"`
+	Read         int       `json:"read"`
+	Offset       int64     `json:"offset"`
+	Events       int       `json:"events"`
+	FirstEventID string    `json:"firstEventId,omitempty"`
+	LastEventID  string    `json:"lastEventId,omitempty"`
+}
+
+// restart drops the progress of the file being written, keeping the topics done
+func (c *exportCheckpoint) restart() {
+	*c = exportCheckpoint{Now: c.Now, Done: c.Done, DoneRead: c.DoneRead}
+}
+
+// exportEvents writes the topic's events after --from-event-id in the time range that
+// match the filter, if any, to the file, with the result's masked fields masked,
+// compressed with the compression codec and encrypted for keys if given, counting them
+// and checksumming the file in the result. An uncompressed, unencrypted NDJSON export
+// continues from its checkpoint, if any, and saves it after each page of events.
+func exportEvents(apiClient *client.Client, topicName string, file *os.File, columns []export.Column, compression string, keys *seal.Keys, timeRange timerange.Range, eventFilter *filter.Filter, total int, result *export.Result, job *jobs.Job, checkpoint *exportCheckpoint) error {
+	hash := sha256.New()
+	since := exportFromEventID
+	if checkpoint.After != "" {
+		// Checksum what was written, dropping anything after the checkpoint
+		if _, err := io.Copy(hash, io.NewSectionReader(file, 0, checkpoint.Offset)); err != nil {
+			return fmt.Errorf("failed to read the export to resume: %w", err)
+		}
+		if err := file.Truncate(checkpoint.Offset); err != nil {
+			return err
+		}
+		if _, err := file.Seek(checkpoint.Offset, io.SeekStart); err != nil {
+			return err
+		}
+		since = checkpoint.After
+	}
+	buffered := bufio.NewWriterSize(io.MultiWriter(file, hash), 1<<20)
+	var content io.Writer = buffered
+	var sealed io.WriteCloser
+	if keys != nil {
+		var err error
+		if sealed, err = seal.NewWriter(buffered, keys); err != nil {
+			return err
+		}
+		content = sealed
+	}
+	writer, err := export.NewWriter(result.Format, content, columns, compression)
+	if err != nil {
+		return err
+	}
+
+	progress := output.NewProgress("Exporting", total)
+	progress.Add(checkpoint.Read)
+	query := timeRangeQuery(apiClient, since, timeRange)
+	err = apiClient.ScanEventsQuery(topicName, query, func(events []client.Event) (bool, error) {
+		selected := make([]client.Event, 0, len(events))
+		past := false
+		for _, event := range events {
+			if timeRange.Past(event.Timestamp) {
+				past = true
+				break
+			}
+			if timeRange.Contains(event.Timestamp) && (eventFilter == nil || eventFilter.Match(event)) {
+				selected = append(selected, output.MaskEvent(event, result.Masked))
+			}
+		}
+		if len(selected) > 0 {
+			if err := writer.Write(selected); err != nil {
+				return false, err
+			}
+			if result.FirstEventID == "" {
+				result.FirstEventID = selected[0].ID
+			}
+			result.LastEventID = selected[len(selected)-1].ID
+			result.Events += len(selected)
+		}
+		progress.Add(len(events))
+		if len(events) > 0 {
+			checkpoint.Read += len(events)
+			if err := checkpointExport(job, checkpoint, file, buffered, result, events[len(events)-1].ID); err != nil {
+				return false, err
+			}
+		}
+		return !past, nil
+	})
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if sealed != nil {
+		if err := sealed.Close(); err != nil {
+			return err
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	result.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+// checkpointExport saves an export job's progress after a page of events read through
+// an event ID. An NDJSON file is flushed so the checkpoint can record its size; Avro,
+// Parquet, compressed and encrypted files can't be continued, so only their progress is
+// saved.
+func checkpointExport(job *jobs.Job, checkpoint *exportCheckpoint, file *os.File, buffered *bufio.Writer, result *export.Result, lastRead string) error {
+	if job == nil {
+		return nil
+	}
+	if !appendable(result.Format, result.Compression, result.Encrypted) {
+		return job.Save(nil, checkpoint.DoneRead+checkpoint.Read)
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	checkpoint.After, checkpoint.Offset = lastRead, offset
+	checkpoint.Events, checkpoint.FirstEventID, checkpoint.LastEventID = result.Events, result.FirstEventID, result.LastEventID
+	return job.Save(checkpoint, checkpoint.DoneRead+checkpoint.Read)
+}
+
+// loadExportKeys loads the passphrase and recipients of an export with --encrypt, or
+// returns nil without it
+func loadExportKeys() (*seal.Keys, error) {
+	if !exportEncrypt {
+		if exportPassphraseFile != "" || len(exportRecipients) > 0 {
+			return nil, fmt.Errorf("--passphrase-file and --recipient are only used with --encrypt")
+		}
+		return nil, nil
+	}
+	if exportPassphraseFile == "" && len(exportRecipients) == 0 {
+		return nil, fmt.Errorf("--encrypt needs a --passphrase-file or a --recipient")
+	}
+	if exportPassphraseFile != "" && len(exportRecipients) > 0 {
+		return nil, fmt.Errorf("--passphrase-file can't be combined with --recipient: an age file is encrypted with a passphrase or for recipients")
+	}
+	keys := &seal.Keys{}
+	if exportPassphraseFile != "" {
+		passphrase, err := seal.ReadPassphrase(exportPassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		keys.Passphrases = append(keys.Passphrases, passphrase)
+	}
+	for _, recipient := range exportRecipients {
+		key, err := seal.ParseRecipient(recipient)
+		if err != nil {
+			return nil, err
+		}
+		keys.Recipients = append(keys.Recipients, key)
+	}
+	return keys, nil
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "File to export the events to, or directory of a file per topic when exporting several (required)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Export format: "+strings.Join(export.Formats, ", ")+" (default: from the --out extension, else ndjson)")
+	exportCmd.Flags().StringVar(&exportCompression, "compression", "", "Compression codec: null or deflate for avro (default deflate), none or gzip for parquet (default gzip)")
+	exportCmd.Flags().StringVar(&exportCompress, "compress", "", "Compress an ndjson file as it's written: "+strings.Join(compress.Codecs, ", ")+" (default: from a .gz or .zst --out extension, else none)")
+	exportCmd.Flags().BoolVar(&exportRawPayload, "raw-payload", false, "Also export the whole payload as a JSON column (avro and parquet)")
+	exportCmd.Flags().StringVar(&exportFromEventID, "from-event-id", "", "Only export events after this event ID")
+	exportCmd.Flags().StringVar(&exportSince, "since", "", "Only export events at or after this time: timestamp, date, 'today', 'yesterday' or a duration ago such as '2h' or '7d'")
+	exportCmd.Flags().BoolVar(&exportVerify, "verify", false, "Re-read the exported file and check its checksum and number of events before finishing")
+	exportCmd.Flags().StringVar(&exportUntil, "until", "", "Only export events before this time (same formats as --since)")
+	exportCmd.Flags().StringVar(&exportFilter, "filter", "", "Only export events matching this filter ('field:value', e.g. 'type:order.placed'; default: the topic's filter in the config file)")
+	exportCmd.Flags().StringSliceVar(&exportMask, "mask", nil, "Export these fields as '***', 'payload.<path>' or 'metadata.<path>' (comma-separated; default: the topic's mask in the config file)")
+	exportCmd.Flags().BoolVar(&exportEncrypt, "encrypt", false, "Encrypt the file as an age file, with --passphrase-file or for each --recipient")
+	exportCmd.Flags().StringVar(&exportPassphraseFile, "passphrase-file", "", "File whose first line is the passphrase to encrypt with")
+	exportCmd.Flags().StringSliceVar(&exportRecipients, "recipient", nil, "age public key (age1...) to encrypt for (repeatable)")
+	cmd.AddLimitFlags(exportCmd)
+	exportCmd.MarkFlagRequired("out")
+}
@@ -0,0 +1,204 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/generate"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// generateBatchSize is the number of events per publish request when no rate is set
+const generateBatchSize = 100
+
+var (
+	generateTypes []string
+	generateCount int
+	generateRate  string
+	generateSeed  int64
+	generateOut   string
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate <topic>",
+	Short: "Generate fake events from a topic's schemas",
+	Long: `Generate synthetic events whose payloads conform to the topic's JSON schemas, and
+either publish them directly or write them to a file in the 'es event publish' format.
+
+Values are generated by schema type and format (date-time, email, uuid, uri, ...) and
+honour enum, const, minimum/maximum and minLength/maxLength. Well-known property names
+such as 'email', 'name', 'city' or 'createdAt' get realistic values. Required properties
+are always present; optional ones are included about half the time.
+
+The same --seed always generates the same events. Without --seed a random seed is used
+and printed to stderr so the run can be repeated.
+
+Examples:
+  # Publish 100 random events to a topic
+  es event generate user-events --count 100
+
+  # Publish user.created events at 5 per second
+  es event generate user-events --type user.created --count 300 --rate 5/s
+
+  # Write a reproducible fixture file
+  es event generate user-events --count 20 --seed 42 --out fixtures.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		if generateCount < 1 {
+			return fmt.Errorf("count must be at least 1")
+		}
+		rate, err := bench.ParseRate(generateRate)
+		if err != nil {
+			return err
+		}
+
+		topic, err := apiClient.GetTopic(topicName)
+		if err != nil {
+			return err
+		}
+		schemas, err := selectSchemas(topic, generateTypes)
+		if err != nil {
+			return err
+		}
+
+		seed := generateSeed
+		if !cobraCmd.Flags().Changed("seed") {
+			seed = time.Now().UnixNano()
+			fmt.Fprintf(os.Stderr, "Using seed %d\n", seed)
+		}
+		generator := generate.New(seed)
+
+		events := make([]client.EventPublishRequest, generateCount)
+		for i := range events {
+			events[i] = generator.Event(topicName, schemas[i%len(schemas)])
+		}
+
+		if generateOut != "" {
+			return writeGeneratedEvents(generateOut, events)
+		}
+
+		eventIDs, err := publishGenerated(apiClient, events, rate)
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintEventPublishResponseJSON(eventIDs)
+		case "csv":
+			return output.PrintEventPublishResponseCSV(eventIDs)
+		default:
+			output.PrintEventPublishResponse(eventIDs)
+			return nil
+		}
+	},
+}
+
+// selectSchemas returns the topic's schemas for the given event types, or all of them
+func selectSchemas(topic *client.Topic, types []string) ([]client.Schema, error) {
+	if len(topic.Schemas) == 0 {
+		return nil, fmt.Errorf("topic '%s' has no schemas to generate events from", topic.Name)
+	}
+	if len(types) == 0 {
+		return topic.Schemas, nil
+	}
+
+	byType := make(map[string]client.Schema, len(topic.Schemas))
+	for _, schema := range topic.Schemas {
+		byType[schema.EventType] = schema
+	}
+	schemas := make([]client.Schema, 0, len(types))
+	for _, eventType := range types {
+		schema, ok := byType[eventType]
+		if !ok {
+			return nil, fmt.Errorf("topic '%s' has no schema for event type '%s'", topic.Name, eventType)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// writeGeneratedEvents writes events to path ('-' for stdout) as a JSON array
+func writeGeneratedEvents(path string, events []client.EventPublishRequest) error {
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d event(s) to %s\n", len(events), path)
+	return nil
+}
+
+// publishGenerated publishes events in batches, or one at a time at rate per second.
+// Ctrl+C stops publishing; the IDs of events already published are still returned.
+func publishGenerated(apiClient *client.Client, events []client.EventPublishRequest, rate float64) ([]string, error) {
+	group, err := cmd.NewRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := generateBatchSize
+	if rate > 0 {
+		batchSize = 1
+	}
+	batches := (len(events) + batchSize - 1) / batchSize
+
+	var eventIDs []string
+	var publishErr error
+	jobs := make(chan int, 1)
+	group.Go("generate-scheduler", func(ctx context.Context) error {
+		bench.Schedule(ctx, rate, batches, jobs)
+		return nil
+	})
+	group.Go("generate-publisher", func(ctx context.Context) error {
+		// Stopping the group also stops the scheduler if publishing fails
+		defer group.Stop()
+		for batch := range jobs {
+			end := (batch + 1) * batchSize
+			if end > len(events) {
+				end = len(events)
+			}
+			ids, err := apiClient.PublishEvents(events[batch*batchSize : end])
+			if err != nil {
+				publishErr = err
+				return nil
+			}
+			eventIDs = append(eventIDs, ids...)
+		}
+		return nil
+	})
+
+	if err := group.Wait(); err != nil {
+		return eventIDs, err
+	}
+	return eventIDs, publishErr
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(generateCmd)
+	cmd.DisablePager(generateCmd)
+	generateCmd.Flags().StringArrayVar(&generateTypes, "type", nil, "Event type to generate (repeatable, default: all of the topic's types)")
+	generateCmd.Flags().IntVar(&generateCount, "count", 10, "Number of events to generate")
+	generateCmd.Flags().StringVar(&generateRate, "rate", "0", "Publish rate, e.g. '10/s' or '600/m' (0 = as fast as possible)")
+	generateCmd.Flags().Int64Var(&generateSeed, "seed", 0, "Random seed for reproducible output (default: random)")
+	generateCmd.Flags().StringVar(&generateOut, "out", "", "Write events to this file ('-' for stdout) instead of publishing them")
+}
@@ -0,0 +1,122 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	endCount   int
+	endFields  []string
+	endDecrypt bool
+	endKeyFile string
+)
+
+var headCmd = &cobra.Command{
+	Use:   "head <topic>",
+	Short: "Show the first events of a topic",
+	Long: `Show the first events of a topic, oldest first: 10 unless -n says otherwise.
+
+Examples:
+  # Show the first 10 events of a topic
+  es event head orders
+
+  # Show the first event's ID only
+  es event head orders -n 1 --quiet`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		return runEnd(cobraCmd, args[0], func(apiClient *client.Client, topic string) ([]client.Event, error) {
+			return apiClient.GetEvents(topic, &client.EventsQuery{Limit: endCount})
+		})
+	},
+}
+
+var lastCmd = &cobra.Command{
+	Use:   "last <topic>",
+	Short: "Show the last events of a topic",
+	Long: `Show the last events of a topic, oldest first: 10 unless -n says otherwise.
+
+Only the last events are read, found from the topic's current sequence number, so this
+is quick however long the topic is.
+
+Examples:
+  # Show the last 10 events of a topic
+  es event last orders
+
+  # Show the last 5 events' IDs and order IDs
+  es event last orders -n 5 --fields id,payload.orderId`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		return runEnd(cobraCmd, args[0], lastEvents)
+	},
+}
+
+// runEnd prints the events at one end of a topic, as read by get
+func runEnd(cobraCmd *cobra.Command, topic string, get func(*client.Client, string) ([]client.Event, error)) error {
+	if err := cmd.ApplyTopicDefaults(cobraCmd, topic); err != nil {
+		return err
+	}
+	if endCount < 1 {
+		return fmt.Errorf("-n must be at least 1")
+	}
+	columns, err := output.ParseEventColumns(endFields)
+	if err != nil {
+		return err
+	}
+	keys, err := loadDecryptKeys(endDecrypt, endKeyFile)
+	if err != nil {
+		return err
+	}
+
+	apiClient := cmd.NewClient()
+	events, err := get(apiClient, topic)
+	if err != nil {
+		return err
+	}
+	decryptEvents(keys, events)
+	events = output.MaskEvents(events, cmd.MaskFields())
+	return printEvents(cmd.GetConfig().Output.Format, events, columns)
+}
+
+// lastEvents reads the last endCount events of a topic: those after the event endCount
+// before its current sequence number. Events may have been removed from the topic, such
+// as by truncation, so while that reads fewer it reads from twice as far back, until it
+// has enough or has read the whole topic.
+func lastEvents(apiClient *client.Client, topic string) ([]client.Event, error) {
+	topicInfo, err := apiClient.GetTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+	last := int64(topicInfo.Sequence)
+
+	for back := int64(endCount); ; back *= 2 {
+		query := &client.EventsQuery{}
+		if from := last - back; from > 0 {
+			query.SinceEventID = eventid.ID{Topic: topic, Sequence: from}.String()
+			query.Limit = int(back)
+		}
+		events, err := apiClient.GetEvents(topic, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) >= endCount || query.SinceEventID == "" {
+			return events[max(len(events)-endCount, 0):], nil
+		}
+	}
+}
+
+func init() {
+	for _, endCmd := range []*cobra.Command{headCmd, lastCmd} {
+		cmd.EventCmd().AddCommand(endCmd)
+		endCmd.Flags().IntVarP(&endCount, "count", "n", 10, "Number of events to show")
+		endCmd.Flags().StringSliceVar(&endFields, "fields", nil, "Only show these fields: id, timestamp, type, partition, payload, metadata, payload.<path> or metadata.<path> (comma-separated)")
+		cmd.AddMaskFlag(endCmd)
+		endCmd.Flags().BoolVar(&endDecrypt, "decrypt", false, "Decrypt payload fields published with 'es event publish --encrypt'")
+		endCmd.Flags().StringVar(&endKeyFile, "key-file", "", "JSON file of the AES keys to decrypt with")
+	}
+}
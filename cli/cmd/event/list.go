@@ -3,11 +3,17 @@ package event
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/archive"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/envelope"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
 	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/timerange"
 )
 
 var (
@@ -15,6 +21,17 @@ var (
 	listLimit       int
 	listDate        string
 	listFilter      string
+	listPartitions  []int
+	listIncludeCold bool
+	listSince       string
+	listUntil       string
+	listFields      []string
+	listSortBy      string
+	listDecrypt     bool
+	listKeyFile     string
+	listExpand      bool
+	listFlatten     bool
+	listDepth       int
 )
 
 var listCmd = &cobra.Command{
@@ -22,6 +39,16 @@ var listCmd = &cobra.Command{
 	Short: "List events from a topic",
 	Long: `List events from a topic with optional filtering and pagination.
 
+--since and --until select events by timestamp (since inclusive, until exclusive). Each
+takes an RFC 3339 timestamp, a date (YYYY-MM-DD, UTC), 'now', 'today', 'yesterday' or a
+duration before now such as '30m', '2h' or '7d'. Servers that support time ranges select
+the events themselves; otherwise the CLI reads the topic from --from-event-id and stops
+once events pass --until.
+
+Payloads are shown as compact JSON, truncated to fit the table. --expand shows them whole,
+as indented JSON highlighted when output is to a terminal and NO_COLOR isn't set, with
+--depth and --flatten as for 'es event show'.
+
 Examples:
   # List all events from a topic
   es event list user-events
@@ -35,22 +62,87 @@ Examples:
   # List events from a specific date
   es event list user-events --date 2025-01-15
 
+  # List the last two hours of events, or yesterday's
+  es event list user-events --since 2h
+  es event list user-events --since yesterday --until today
+
   # Filter events by type
   es event list user-events --filter "type:user.created"
 
+  # Filter events by metadata
+  es event list user-events --filter "metadata.tenant:acme"
+
   # Filter events by payload field
-  es event list user-events --filter "payload.email:alice@example.com"`,
+  es event list user-events --filter "payload.email:alice@example.com"
+
+  # List events from partitions 0 and 2 of a partitioned topic, merged by timestamp
+  es event list user-events --partition 0,2
+
+  # Only show some fields, including nested payload fields
+  es event list orders --fields id,timestamp,payload.orderId,payload.amount
+
+  # Show whole payloads, highlighted, with nesting deeper than two levels collapsed
+  es event list orders --limit 5 --expand --depth 2
+
+  # Newest first
+  es event list orders --since 1h --sort-by timestamp:desc
+
+  # Hide passwords and social security numbers, such as when sharing the output
+  es event list users --mask payload.password,payload.ssn
+
+  # Decrypt fields published with 'es event publish --encrypt'
+  es event list customers --decrypt --key-file keys.json
+
+  # Include events moved to the topic's cold tier with 'es topic tier push' or
+  # 'es topic archive'
+  es event list user-events --include-cold`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
 		topic := args[0]
+		if err := cmd.ApplyTopicDefaults(cobraCmd, topic); err != nil {
+			return err
+		}
+		if !listExpand && (listFlatten || listDepth != 0) {
+			return fmt.Errorf("--flatten and --depth need --expand")
+		}
+
+		var eventFilter *filter.Filter
+		if listFilter != "" {
+			var err error
+			eventFilter, err = filter.Parse(listFilter)
+			if err != nil {
+				return err
+			}
+		}
+
+		timeRange, err := timerange.Parse(listSince, listUntil, time.Now())
+		if err != nil {
+			return err
+		}
+		columns, err := output.ParseEventColumns(listFields)
+		if err != nil {
+			return err
+		}
+		keys, err := loadDecryptKeys(listDecrypt, listKeyFile)
+		if err != nil {
+			return err
+		}
+		serverTimeRange := !timeRange.IsZero() && apiClient.Supports(client.FeatureEventTimeRange)
+
+		// Servers that filter events themselves return exactly the requested number; they
+		// only see encrypted fields, so decrypted events are filtered here
+		serverFilter := ""
+		if listFilter != "" && keys == nil && apiClient.Supports(client.FeatureEventFilter) {
+			serverFilter = listFilter
+		}
 
 		// If filtering is enabled, we need to fetch more events to ensure we get
 		// the requested number after filtering. Multiply by a factor to account for filtering.
 		apiLimit := listLimit
-		if listFilter != "" && listLimit > 0 {
+		if listFilter != "" && serverFilter == "" && listLimit > 0 {
 			// Fetch more events when filtering to ensure we get enough after filtering
 			// Use a multiplier (e.g., 5x) to account for filter selectivity
 			apiLimit = listLimit * 5
@@ -61,115 +153,211 @@ Examples:
 			SinceEventID: listFromEventID,
 			Date:         listDate,
 			Limit:        apiLimit,
+			Filter:       serverFilter,
+		}
+		if serverTimeRange {
+			setTimeRange(query, timeRange)
 		}
 
-		// Get events
-		events, err := apiClient.GetEvents(topic, query)
+		// Get events, fanning out across the selected partitions of a partitioned topic
+		var events []client.Event
+		switch {
+		case len(listPartitions) > 0:
+			events, err = getPartitionEvents(apiClient, topic, listPartitions, query)
+			decryptEvents(keys, events)
+		case !timeRange.IsZero() && !serverTimeRange:
+			events, err = scanTimeRange(apiClient, topic, *query, timeRange, keys, eventFilter)
+		default:
+			events, err = apiClient.GetEvents(topic, query)
+			decryptEvents(keys, events)
+		}
+		if err == nil && listIncludeCold {
+			events, err = includeColdEvents(cfg.Server.URL, topic, events, timeRange, keys)
+		}
 		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
-			}
-			output.PrintError(err)
 			return err
 		}
 
 		// Apply filter if provided
-		if listFilter != "" {
-			events = filterEvents(events, listFilter)
+		if eventFilter != nil {
+			events = eventFilter.Apply(events)
+		}
+		if !timeRange.IsZero() {
+			inRange := make([]client.Event, 0, len(events))
+			for _, event := range events {
+				if timeRange.Contains(event.Timestamp) {
+					inRange = append(inRange, event)
+				}
+			}
+			events = inRange
 		}
 
 		// Apply limit after filtering to ensure we get exactly the requested number
 		if listLimit > 0 && len(events) > listLimit {
 			events = events[:listLimit]
 		}
+		if listSortBy != "" {
+			if err := output.SortEvents(events, listSortBy); err != nil {
+				return err
+			}
+		}
+		events = output.MaskEvents(events, cmd.MaskFields())
 
-		switch cfg.Output.Format {
-		case "json":
-			return output.PrintEventsListJSON(events)
-		case "csv":
-			return output.PrintEventsListCSV(events)
-		default:
-			output.PrintEventsList(events)
+		if listExpand && len(columns) == 0 && (cfg.Output.Format == "table" || output.IsMarkup(cfg.Output.Format)) && !cmd.Quiet() {
+			output.PrintExpandedEventsList(events, output.PayloadOptions{Flatten: listFlatten, Depth: listDepth})
 			return nil
 		}
+		return printEvents(cfg.Output.Format, events, columns)
 	},
 }
 
-// filterEvents applies client-side filtering to events
-func filterEvents(events []client.Event, filter string) []client.Event {
-	if filter == "" {
-		return events
+// printEvents prints listed events in the output format, with just their IDs for --quiet
+// and only the columns of --fields if given
+func printEvents(format string, events []client.Event, columns []output.EventColumn) error {
+	if cmd.Quiet() {
+		output.PrintIDs(output.EventIDs(events))
+		return nil
+	}
+	if len(columns) > 0 {
+		switch format {
+		case "json":
+			return output.PrintEventColumnsJSON(events, columns)
+		case "csv":
+			return output.PrintEventColumnsCSV(events, columns)
+		default:
+			output.PrintEventColumns(events, columns)
+			return nil
+		}
 	}
 
-	filtered := make([]client.Event, 0)
+	switch format {
+	case "json":
+		return output.PrintEventsListJSON(events)
+	case "csv":
+		return output.PrintEventsListCSV(events)
+	default:
+		output.PrintEventsList(events)
+		return nil
+	}
+}
 
-	for _, event := range events {
-		if matchesFilter(event, filter) {
-			filtered = append(filtered, event)
+// scanTimeRange pages through the topic for the events in a time range that match the
+// filter, decrypted with keys if given, stopping at --limit matching events or once the
+// topic's events pass the range
+func scanTimeRange(apiClient *client.Client, topic string, query client.EventsQuery, timeRange timerange.Range, keys *envelope.KeyRing, eventFilter *filter.Filter) ([]client.Event, error) {
+	var events []client.Event
+	err := apiClient.ScanEventsQuery(topic, query, func(page []client.Event) (bool, error) {
+		decryptEvents(keys, page)
+		for _, event := range page {
+			if timeRange.Past(event.Timestamp) {
+				return false, nil
+			}
+			if !timeRange.Contains(event.Timestamp) || (eventFilter != nil && !eventFilter.Match(event)) {
+				continue
+			}
+			events = append(events, event)
+			if listLimit > 0 && len(events) >= listLimit {
+				return false, nil
+			}
 		}
+		return true, nil
+	})
+	return events, err
+}
+
+// timeRangeQuery returns a query for the events after sinceEventID, passing the time
+// range to servers that support time ranges; other callers check each event themselves
+func timeRangeQuery(apiClient *client.Client, sinceEventID string, timeRange timerange.Range) client.EventsQuery {
+	query := client.EventsQuery{SinceEventID: sinceEventID}
+	if !timeRange.IsZero() && apiClient.Supports(client.FeatureEventTimeRange) {
+		setTimeRange(&query, timeRange)
 	}
+	return query
+}
 
-	return filtered
+func setTimeRange(query *client.EventsQuery, timeRange timerange.Range) {
+	query.Since = timerange.Format(timeRange.Since)
+	query.Until = timerange.Format(timeRange.Until)
 }
 
-// matchesFilter checks if an event matches the filter criteria
-func matchesFilter(event client.Event, filter string) bool {
-	// Parse filter format: "field:value" or "field.path:value"
-	parts := strings.SplitN(filter, ":", 2)
-	if len(parts) != 2 {
-		return false
-	}
-
-	field := strings.TrimSpace(parts[0])
-	value := strings.TrimSpace(parts[1])
-
-	// Handle different field types
-	switch {
-	case field == "type":
-		return event.Type == value
-	case field == "id":
-		return event.ID == value
-	case strings.HasPrefix(field, "payload."):
-		// Extract payload field path (e.g., "payload.email" -> "email")
-		payloadPath := strings.TrimPrefix(field, "payload.")
-		return matchesPayloadField(event.Payload, payloadPath, value)
-	default:
-		// Try as direct payload field
-		return matchesPayloadField(event.Payload, field, value)
+// getPartitionEvents checks the partitions exist and returns their merged events
+func getPartitionEvents(apiClient *client.Client, topic string, partitions []int, query *client.EventsQuery) ([]client.Event, error) {
+	if info, err := apiClient.GetServerInfo(); err == nil && info.Reported && !info.Supports(client.FeaturePartitions) {
+		return nil, fmt.Errorf("the event store (version %s) does not support partitions", info.Version)
+	}
+	topicInfo, err := apiClient.GetTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+	if topicInfo.Partitions == 0 {
+		return nil, fmt.Errorf("topic '%s' is not partitioned", topic)
+	}
+	for _, partition := range partitions {
+		if partition < 0 || partition >= topicInfo.Partitions {
+			return nil, fmt.Errorf("topic '%s' has no partition %d (partitions: 0-%d)", topic, partition, topicInfo.Partitions-1)
+		}
 	}
+	return apiClient.GetPartitionedEvents(topic, partitions, query)
 }
 
-// matchesPayloadField checks if a payload field matches the value
-func matchesPayloadField(payload map[string]interface{}, path, value string) bool {
-	// Handle nested paths (e.g., "user.email")
-	parts := strings.Split(path, ".")
-	current := payload
+// includeColdEvents prepends the topic's cold tier events that match the listing's
+// --from-event-id, --date, time range and --limit, decrypted with keys if given,
+// skipping hot events that have also been tiered. The tier's index is used to read only the blocks that can match.
+func includeColdEvents(server, topic string, hot []client.Event, timeRange timerange.Range, keys *envelope.KeyRing) ([]client.Event, error) {
+	manifest, err := archive.LoadTierManifest(server, topic)
+	if err != nil || manifest == nil {
+		return hot, err
+	}
 
-	for i, part := range parts {
-		val, ok := current[part]
-		if !ok {
-			return false
+	// --filter is applied afterwards, so --limit can only bound the cold read without it
+	query := archive.Query{}
+	if listFilter == "" {
+		query.Limit = listLimit
+	}
+	if listFromEventID != "" {
+		id, err := eventid.Parse(listFromEventID)
+		if err != nil {
+			return nil, err
 		}
-
-		// If this is the last part, compare the value
-		if i == len(parts)-1 {
-			// Convert to string for comparison
-			valStr := fmt.Sprintf("%v", val)
-			return valStr == value
+		query.AfterSequence = id.Sequence
+	}
+	if listDate != "" {
+		day, err := time.Parse("2006-01-02", listDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --date '%s' (expected YYYY-MM-DD)", listDate)
 		}
+		query.Since, query.Until = day, day.AddDate(0, 0, 1)
+	} else {
+		query.Since, query.Until = timeRange.Since, timeRange.Until
+	}
 
-		// Navigate deeper into nested objects
-		if nested, ok := val.(map[string]interface{}); ok {
-			current = nested
-		} else {
-			return false
-		}
+	cold, _, err := archive.QueryTier(manifest, query)
+	if err != nil {
+		return nil, err
+	}
+	decryptEvents(keys, cold)
+
+	// Tiering copies a topic's events from the start, so everything up to the last
+	// tiered event is in the cold tier
+	var lastTiered int64 = -1
+	if id, err := eventid.Parse(manifest.LastEventID()); err == nil {
+		lastTiered = id.Sequence
 	}
 
-	return false
+	events := make([]client.Event, 0, len(cold)+len(hot))
+	for _, event := range cold {
+		if listDate != "" && !strings.HasPrefix(event.Timestamp, listDate) {
+			continue
+		}
+		events = append(events, event)
+	}
+	for _, event := range hot {
+		if id, err := eventid.Parse(event.ID); err == nil && id.Sequence <= lastTiered {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
 }
 
 func init() {
@@ -177,6 +365,19 @@ func init() {
 	listCmd.Flags().StringVar(&listFromEventID, "from-event-id", "", "Get events after this event ID")
 	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of events to return (0 = no limit)")
 	listCmd.Flags().StringVar(&listDate, "date", "", "Get events from a specific date (YYYY-MM-DD)")
+	listCmd.Flags().StringVar(&listSince, "since", "", "Only events at or after this time: timestamp, date, 'today', 'yesterday' or a duration ago such as '2h' or '7d'")
+	listCmd.Flags().StringVar(&listUntil, "until", "", "Only events before this time (same formats as --since)")
+	listCmd.Flags().IntSliceVar(&listPartitions, "partition", nil, "Only list events from these partitions of a partitioned topic (comma-separated or repeatable)")
+	listCmd.Flags().BoolVar(&listIncludeCold, "include-cold", false, "Also read events from the topic's cold tier (see 'es topic tier')")
+	listCmd.Flags().BoolVar(&listIncludeCold, "include-archive", false, "Same as --include-cold, for topics archived with 'es topic archive'")
+	listCmd.Flags().StringSliceVar(&listFields, "fields", nil, "Only show these fields: id, timestamp, type, partition, payload, metadata, payload.<path> or metadata.<path> (comma-separated)")
+	listCmd.Flags().BoolVar(&listExpand, "expand", false, "Show whole payloads as indented JSON, highlighted on a terminal, rather than truncated (table output)")
+	listCmd.Flags().BoolVar(&listFlatten, "flatten", false, "With --expand, show payloads as a line per value, keyed by its dotted path")
+	listCmd.Flags().IntVar(&listDepth, "depth", 0, "With --expand, collapse objects and arrays nested deeper than this (0 = show all)")
+	listCmd.Flags().StringVar(&listSortBy, "sort-by", "", "Sort the listed events by a field (as for --fields), optionally with ':asc' or ':desc', e.g. 'timestamp:desc'")
+	cmd.AddMaskFlag(listCmd)
+	listCmd.Flags().BoolVar(&listDecrypt, "decrypt", false, "Decrypt payload fields published with 'es event publish --encrypt'")
+	listCmd.Flags().StringVar(&listKeyFile, "key-file", "", "JSON file of the AES keys to decrypt with")
 	listCmd.Flags().StringVar(&listFilter, "filter", "", "Filter events (format: 'field:value', e.g., 'type:user.created' or 'payload.email:alice@example.com')")
 }
 
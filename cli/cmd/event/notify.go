@@ -0,0 +1,149 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/notify"
+	"github.com/event-store/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+// notifyPayloadPreview is the number of payload characters shown in a notification
+const notifyPayloadPreview = 120
+
+var (
+	notifyFilters     []string
+	notifyInterval    time.Duration
+	notifyFromEventID string
+	notifyMax         int
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify <topic>",
+	Short: "Show desktop notifications for new events",
+	Long: `Poll a topic for new events and show a desktop notification for each one that matches
+every --filter, so failures get noticed while working on something else. Each notified
+event is also printed to stdout.
+
+Notifications use osascript on macOS, PowerShell on Windows and notify-send (libnotify)
+on Linux. When more than --max events match in one poll, the first ones are notified
+individually and the rest are summarised in a single notification.
+
+Watching starts at the end of the topic unless --from-event-id is given.
+
+Examples:
+  # Notify about failed payments
+  es event notify payments --filter type:payment.failed
+
+  # Combine filters: every filter has to match
+  es event notify orders --filter type:order.rejected --filter payload.region:eu`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		apiClient := cmd.NewClient()
+		topic := args[0]
+
+		if notifyInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		if notifyMax < 1 {
+			return fmt.Errorf("max must be at least 1")
+		}
+		filters := make([]*filter.Filter, len(notifyFilters))
+		for i, expr := range notifyFilters {
+			f, err := filter.Parse(expr)
+			if err != nil {
+				return err
+			}
+			filters[i] = f
+		}
+		if err := notify.Check(); err != nil {
+			return err
+		}
+
+		since := notifyFromEventID
+		if since == "" {
+			topicInfo, err := apiClient.RefreshTopic(topic)
+			if err != nil {
+				return err
+			}
+			if topicInfo.Sequence > 0 {
+				since = eventid.ID{Topic: topic, Sequence: int64(topicInfo.Sequence)}.String()
+			}
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Watching '%s' for new events every %s (Ctrl+C to stop)\n", topic, notifyInterval)
+		group.Go("event-notify", func(ctx context.Context) error {
+			return watch.Loop(ctx, notifyInterval, func() error {
+				var matched []client.Event
+				err := apiClient.ScanEvents(topic, since, func(events []client.Event) (bool, error) {
+					for _, event := range events {
+						if filter.MatchAll(filters, event) {
+							matched = append(matched, event)
+						}
+					}
+					since = events[len(events)-1].ID
+					return true, nil
+				})
+				if err != nil {
+					// Keep watching through outages; the next poll resumes where this one stopped
+					fmt.Fprintf(os.Stderr, "[%s] %v\n", time.Now().Format(time.RFC3339), err)
+				}
+
+				for i, event := range matched {
+					fmt.Printf("[%s] %s %s\n", event.Timestamp, event.ID, event.Type)
+					if i < notifyMax {
+						sendNotification(fmt.Sprintf("%s: %s", topic, event.Type), notificationMessage(event))
+					}
+				}
+				if len(matched) > notifyMax {
+					rest := len(matched) - notifyMax
+					sendNotification(topic, fmt.Sprintf("%d more matching event(s), up to %s", rest, matched[len(matched)-1].ID))
+				}
+				return nil
+			})
+		})
+		return group.Wait()
+	},
+}
+
+// notificationMessage describes an event by its ID and the start of its payload
+func notificationMessage(event client.Event) string {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return event.ID
+	}
+	preview := []rune(string(payload))
+	if len(preview) > notifyPayloadPreview {
+		preview = append(preview[:notifyPayloadPreview], '…')
+	}
+	return event.ID + "\n" + string(preview)
+}
+
+// sendNotification shows a notification, reporting but not failing on errors
+func sendNotification(title, message string) {
+	if err := notify.Send(title, message); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(notifyCmd)
+	cmd.DisablePager(notifyCmd)
+	notifyCmd.Flags().StringArrayVar(&notifyFilters, "filter", nil, "Only notify about events matching this filter ('field:value', repeatable; all must match)")
+	notifyCmd.Flags().DurationVar(&notifyInterval, "interval", 5*time.Second, "How often to poll for new events")
+	notifyCmd.Flags().StringVar(&notifyFromEventID, "from-event-id", "", "Notify about events after this event ID (default: only events published from now on)")
+	notifyCmd.Flags().IntVar(&notifyMax, "max", 3, "Most notifications per poll; further matches are summarised in one notification")
+}
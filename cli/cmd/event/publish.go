@@ -1,19 +1,40 @@
 package event
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/event-store/cli/cmd"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/ledger"
 	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/spool"
+	"github.com/event-store/cli/internal/timerange"
 	"github.com/spf13/cobra"
 )
 
 var (
-	publishFile string
-	publishJSON string
+	publishFile           string
+	publishJSON           string
+	publishAt             string
+	publishDelay          string
+	publishIdempotencyKey string
+	publishDedupe         bool
+	publishAtomic         bool
+	publishCorrelationID  string
+	publishCausationID    string
+	publishMetadata       []string
 )
 
 var publishCmd = &cobra.Command{
@@ -24,77 +45,734 @@ var publishCmd = &cobra.Command{
 Events can be provided via:
   - A JSON file (--file)
   - Inline JSON string (--json)
+  - Prompts (--interactive): pick a topic and event type, then enter each property of
+    the type's schema, checked as you go as by 'es event compose'; the event is shown
+    and published once you confirm it
+  - An editor (--edit): pick a topic and event type, then fill in an event with a
+    payload skeleton from the type's schema in $VISUAL or $EDITOR (default: vi); the
+    events saved are checked against their schemas, shown as a diff and published once
+    you confirm them
 
 Event format:
   [
     {
       "topic": "topic-name",
       "type": "event.type",
-      "payload": { ... }
+      "payload": { ... },
+      "publishAt": "2025-06-01T09:00:00Z",
+      "idempotencyKey": "order-42-placed",
+      "correlationId": "checkout-7f3a",
+      "causationId": "carts-42",
+      "metadata": {"source": "checkout", "user": "alice", "tenant": "acme", "schemaVersion": "2"}
     }
   ]
 
+The events can also be newline-delimited JSON, an event object per line. Inputs are
+decoded an event at a time, so files larger than memory are published a chunk at a
+time; with --atomic or --idempotency-key, which publish the events as one batch, they
+are read whole. A file published in chunks runs as a job recording the chunks
+published: if it's stopped part way, 'es job resume' publishes the rest, starting with
+the chunk that was being published, so use --dedupe to have its published events
+skipped.
+
+Events can be scheduled for later with --publish-at or --delay, or their own
+"publishAt". Servers that support scheduled publishing hold the events back themselves;
+for other servers the events are kept in a local spool (~/.es/spool) until
+'es spool flush' publishes them when they are due.
+
+An idempotency key makes re-running a publish, such as a batch that failed halfway,
+safe: --idempotency-key names the whole batch, and an event's own "idempotencyKey"
+names that event. --dedupe gives events without a key one made from a hash of their
+topic, type and payload. Keys are sent to servers that deduplicate by them, and are
+also recorded for a week in a local ledger (~/.es/ledger), which skips events already
+published when the server doesn't.
+
+Events carry a correlation ID, shared by every event of one flow, and optionally a
+causation ID, the ID of the event that caused them; 'es event trace' follows them.
+--correlation-id and --causation-id set them for events without their own, and a batch
+without a correlation ID is given a new one, printed to stderr. This needs a server
+that records them.
+
+Events can also carry metadata, such as their source, user, tenant and schema version,
+kept apart from the payload: --metadata key=value adds an entry to events without that
+key, and needs a server that keeps metadata. 'es event list --filter metadata.tenant:acme'
+selects events by it.
+
+With --encrypt, payload fields are encrypted with AES-GCM before they leave, so events
+can be kept on an event store that isn't trusted with them: --encrypt-fields chooses
+them, every top-level field by default. Each becomes a string "enc:v1:<key ID>:..."
+naming its key, so a topic's schema must declare encrypted fields as strings, and the
+key and fields are also recorded in the "encryption" metadata entry when the server
+keeps metadata. The key file holds base64 AES keys of 16, 24 or 32 bytes by ID, and
+names the one to encrypt with: {"current": "k2", "keys": {"k1": "...", "k2": "..."}}.
+'es event list --decrypt' and 'es event show --decrypt' decrypt them with any of the
+file's keys. The checks are of the events before they are encrypted.
+
+Large inputs are published in batches of --batch-size events, --workers batches at a
+time, with a progress bar and a summary of the events published per second. A batch
+that fails for a reason other than its events, such as a timeout or a server error, is
+tried again up to --retries times; if it still fails its events are reported as failed
+and the other batches are published. Batches are published in order with one worker;
+with more they may be published out of order. A retried batch the server received
+before failing may be published twice, unless the events have idempotency keys.
+
+With --atomic the event store publishes all of the events or, if any is rejected, none
+of them. --atomic is experimental: the event store server in this repository can't
+publish atomically yet, so it needs a server that advertises the 'atomic-publish'
+feature (see es version --server) and fails without one. Without it, each event the event store rejects is reported with its position
+and error, and the others are published; a batch rejected as a whole by a server that
+doesn't say which events are at fault is published again an event at a time to find
+them. Either way the command fails if any event wasn't published.
+
 Examples:
   # Publish events from a file
   es event publish --file events.json
 
+  # Build an event from a topic's schema, with prompts
+  es event publish --interactive
+
+  # Write events in an editor, starting from a topic's schema
+  es event publish --edit
+
   # Publish a single event inline
-  es event publish --json '[{"topic":"user-events","type":"user.created","payload":{"id":"1","name":"Alice"}}]'`,
+  es event publish --json '[{"topic":"user-events","type":"user.created","payload":{"id":"1","name":"Alice"}}]'
+
+  # Publish a batch that can safely be re-run
+  es event publish --file orders.json --idempotency-key import-2025-06-01
+
+  # Re-run an import without publishing the same event twice
+  es event publish --file orders.json --dedupe
+
+  # Publish a payment caused by an order, in the order's flow
+  es event publish --file payment.json --correlation-id checkout-7f3a --causation-id orders-42
+
+  # Publish events tagged with their tenant and source
+  es event publish --file orders.json --metadata tenant=acme --metadata source=backfill
+
+  # Publish customers with their email and address encrypted
+  es event publish --file customers.json --encrypt --encrypt-fields payload.email,payload.address --key-file keys.json
+
+  # Import a large file faster, 1000 events per request and 4 requests at a time
+  es event publish --file backfill.json --batch-size 1000 --workers 4 --dedupe
+
+  # Publish an order and its line items together or not at all
+  es event publish --file order.json --atomic
+
+  # Publish a reminder in ten minutes
+  es event publish --file reminder.json --delay 10m`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
-		var events []client.EventPublishRequest
+		if publishIdempotencyKey != "" && publishDedupe {
+			return fmt.Errorf("--idempotency-key and --dedupe can't be used together")
+		}
+		if err := checkBatchFlags(cobraCmd); err != nil {
+			return err
+		}
+		checker, strict, err := newGuard(cobraCmd, apiClient)
+		if err != nil {
+			return err
+		}
+		if err := loadPublishKeys(); err != nil {
+			return err
+		}
 
-		// Read events from file or JSON string
-		if publishFile != "" {
-			data, err := os.ReadFile(publishFile)
+		// Read events from file or JSON string, or build them from prompts or in an editor
+		var reader *eventReader
+		if publishInteractive {
+			event, err := composeEvent(apiClient, strings.TrimSuffix(cfg.Server.URL, "/"))
+			if err != nil || event == nil {
+				return err
+			}
+			// Published as if given with --json
+			data, err := json.Marshal([]client.EventPublishRequest{*event})
+			if err != nil {
+				return err
+			}
+			if reader, err = newEventReader(bytes.NewReader(data)); err != nil {
+				return err
+			}
+		} else if publishEdit {
+			data, err := editEvents(apiClient, strings.TrimSuffix(cfg.Server.URL, "/"))
+			if err != nil || data == nil {
+				return err
+			}
+			if reader, err = newEventReader(bytes.NewReader(data)); err != nil {
+				return fmt.Errorf("failed to parse JSON: %w", err)
+			}
+		} else if publishFile != "" {
+			file, err := os.Open(publishFile)
 			if err != nil {
 				return fmt.Errorf("failed to read file: %w", err)
 			}
-			if err := json.Unmarshal(data, &events); err != nil {
+			defer file.Close()
+			if reader, err = newEventReader(file); err != nil {
 				return fmt.Errorf("failed to parse JSON file: %w", err)
 			}
 		} else if publishJSON != "" {
-			if err := json.Unmarshal([]byte(publishJSON), &events); err != nil {
+			if reader, err = newEventReader(strings.NewReader(publishJSON)); err != nil {
 				return fmt.Errorf("failed to parse JSON: %w", err)
 			}
 		} else {
-			return fmt.Errorf("either --file or --json must be provided")
+			return fmt.Errorf("either --file, --json, --interactive or --edit must be provided")
 		}
 
+		// A batch published all at once or under one key is read whole; other inputs
+		// too large to hold in memory are streamed a chunk at a time
+		chunk := max(streamChunkSize, publishBatchSize*publishWorkers)
+		if publishAtomic || publishIdempotencyKey != "" {
+			chunk = math.MaxInt
+		}
+		events, err := reader.Next(chunk)
+		if err != nil {
+			return err
+		}
 		if len(events) == 0 {
 			return fmt.Errorf("at least one event must be provided")
 		}
+		if !reader.Done() {
+			return publishStream(apiClient, strings.TrimSuffix(cfg.Server.URL, "/"), reader, events, chunk, func(events []client.EventPublishRequest, first int) error {
+				return guardEvents(checker, strict, events, first)
+			})
+		}
+		if err := guardEvents(checker, strict, events, 0); err != nil {
+			return err
+		}
 
-		// Publish events
-		eventIDs, err := apiClient.PublishEvents(events)
-		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
+		eventIDs, skipped, spooled, err := publishEvents(apiClient, strings.TrimSuffix(cfg.Server.URL, "/"), events)
+		var publishErr *client.PublishError
+		if err != nil && !errors.As(err, &publishErr) {
+			return err
+		}
+		if publishErr != nil {
+			due := events[:len(eventIDs)]
+			defer output.PrintPublishFailures(due, 0, publishErr.Failures)
+			if err := printPublished(eventIDs, skipped, publishErr.Failures, spooled); err != nil {
+				return err
 			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
+			if publishErr.Rejected {
+				return fmt.Errorf("%d of %d event(s) failed to publish; no events were published", len(publishErr.Failures), len(eventIDs))
 			}
-			output.PrintError(err)
-			return err
+			return fmt.Errorf("%d of %d event(s) failed to publish; the others were published", len(publishErr.Failures), len(eventIDs))
 		}
+		return printPublished(eventIDs, skipped, nil, spooled)
+	},
+}
 
-		// Output results
-		switch cfg.Output.Format {
-		case "json":
+// publishEvents prepares events read from the input and publishes them: it gives them
+// idempotency keys with --dedupe, metadata and correlation IDs, encrypts them with
+// --encrypt, spools those scheduled
+// for later when the server can't schedule them, and publishes the rest. It returns an
+// ID per published event, which of them were skipped as already published with their
+// idempotency key, and a *client.PublishError if some of them failed. The events
+// published are moved to the front of events, in order, so failures index into them.
+func publishEvents(apiClient *client.Client, server string, events []client.EventPublishRequest) ([]string, []bool, []*spool.Entry, error) {
+	if publishDedupe {
+		for i := range events {
+			if events[i].IdempotencyKey == "" {
+				events[i].IdempotencyKey = contentKey(events[i])
+			}
+		}
+	}
+
+	if err := addMetadata(apiClient, events); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := encryptEvents(apiClient, events); err != nil {
+		return nil, nil, nil, err
+	}
+	correlationID, err := correlate(apiClient, events)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if correlationID != "" {
+		if !cmd.Quiet() {
+			fmt.Fprintf(os.Stderr, "Correlation ID: %s\n", correlationID)
+		}
+		// The rest of a streamed input is in the same flow
+		publishCorrelationID = correlationID
+	}
+
+	scheduled, err := schedule(events, time.Now())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if publishAtomic {
+		if !apiClient.Supports(client.FeatureAtomicPublish) {
+			return nil, nil, nil, fmt.Errorf("the event store does not support atomic publishing (feature '%s')", client.FeatureAtomicPublish)
+		}
+		if scheduled && !apiClient.Supports(client.FeatureScheduledPublish) {
+			return nil, nil, nil, fmt.Errorf("--atomic can't be used with scheduled events, which are spooled because the event store does not schedule them (feature '%s')", client.FeatureScheduledPublish)
+		}
+	}
+	var spooled []*spool.Entry
+	due := events
+	if scheduled && !apiClient.Supports(client.FeatureScheduledPublish) {
+		if due, spooled, err = spoolScheduled(server, events, time.Now()); err != nil {
+			return nil, nil, spooled, err
+		}
+		copy(events, due)
+	}
+
+	// Publish events
+	if len(due) == 0 {
+		return []string{}, nil, spooled, nil
+	}
+	eventIDs, skipped, err := publishOnce(apiClient, server, events[:len(due)], publishIdempotencyKey)
+	return eventIDs, skipped, spooled, err
+}
+
+// printPublished prints the IDs of the published events, "" for those that failed, and
+// the spooled entries. Events skipped as already published are counted apart from those
+// published now.
+func printPublished(eventIDs []string, skipped []bool, failures []client.EventFailure, spooled []*spool.Entry) error {
+	cfg := cmd.GetConfig()
+	alreadyPublished := countSkipped(skipped)
+	if len(failures) > 0 {
+		var published []string
+		var publishedSkipped []bool
+		for i, id := range eventIDs {
+			if id != "" {
+				published = append(published, id)
+				publishedSkipped = append(publishedSkipped, skipped != nil && skipped[i])
+			}
+		}
+		if cfg.Output.Format == "json" && !cmd.Quiet() {
+			result := map[string]interface{}{"eventIds": eventIDs, "failures": failures}
+			if len(spooled) > 0 {
+				result["spooled"] = spooled
+			}
+			if alreadyPublished > 0 {
+				result["published"] = len(published) - alreadyPublished
+				result["skipped"] = alreadyPublished
+			}
+			return output.PrintJSON(result)
+		}
+		eventIDs, skipped = published, publishedSkipped
+	}
+
+	if alreadyPublished > 0 && (cmd.Quiet() || cfg.Output.Format == "csv") {
+		fmt.Fprintf(os.Stderr, "%d event(s) were already published with the same idempotency key and were skipped; their original IDs are shown\n", alreadyPublished)
+	}
+	if cmd.Quiet() {
+		output.PrintIDs(eventIDs)
+		for _, entry := range spooled {
+			fmt.Println(entry.ID)
+		}
+		return nil
+	}
+	switch cfg.Output.Format {
+	case "json":
+		if len(spooled) == 0 && alreadyPublished == 0 {
 			return output.PrintEventPublishResponseJSON(eventIDs)
-		case "csv":
-			return output.PrintEventPublishResponseCSV(eventIDs)
-		default:
+		}
+		result := map[string]interface{}{"eventIds": eventIDs}
+		if len(spooled) > 0 {
+			result["spooled"] = spooled
+		}
+		if alreadyPublished > 0 {
+			result["published"] = len(eventIDs) - alreadyPublished
+			result["skipped"] = alreadyPublished
+		}
+		return output.PrintJSON(result)
+	case "csv":
+		if err := output.PrintEventPublishResponseCSV(eventIDs); err != nil {
+			return err
+		}
+		for _, entry := range spooled {
+			fmt.Fprintf(os.Stderr, "Spooled %d event(s) for %s as %s\n", len(entry.Events), entry.PublishAt.Format(time.RFC3339), entry.ID)
+		}
+		return nil
+	default:
+		switch {
+		case alreadyPublished > 0:
+			output.PrintEventPublishDeduped(eventIDs, skipped)
+		case len(eventIDs) > 0 || len(spooled) == 0:
 			output.PrintEventPublishResponse(eventIDs)
-			return nil
 		}
-	},
+		if len(spooled) > 0 {
+			output.PrintSpooled(spooled)
+		}
+		return nil
+	}
+}
+
+// countSkipped returns how many events were skipped as already published
+func countSkipped(skipped []bool) int {
+	n := 0
+	for _, s := range skipped {
+		if s {
+			n++
+		}
+	}
+	return n
+}
+
+// contentKey returns an idempotency key made from a hash of an event's topic, type and
+// payload, so the same event always gets the same key
+func contentKey(event client.EventPublishRequest) string {
+	data, _ := json.Marshal(client.EventPublishRequest{Topic: event.Topic, Type: event.Type, Payload: event.Payload})
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// publishOnce publishes events under the batch's idempotency key, if any, and the events'
+// own keys, returning the events' IDs and which of them were skipped. Servers that
+// deduplicate by key are left to do so; for others events whose key is in the ledger are
+// skipped, with the IDs they were first published as. Keys are recorded in the ledger
+// either way, for the events that were published.
+func publishOnce(apiClient *client.Client, server string, events []client.EventPublishRequest, key string) ([]string, []bool, error) {
+	keyed := key != ""
+	for _, event := range events {
+		keyed = keyed || event.IdempotencyKey != ""
+	}
+	if !keyed {
+		eventIDs, err := publishBatches(apiClient, events)
+		return eventIDs, nil, err
+	}
+
+	serverSide := apiClient.Supports(client.FeatureIdempotentPublish)
+	published, err := ledger.Open(server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if key != "" {
+		send := events
+		if !serverSide {
+			if eventIDs, ok := published.Lookup(key); ok {
+				skipped := make([]bool, len(eventIDs))
+				for i := range skipped {
+					skipped[i] = true
+				}
+				return eventIDs, skipped, nil
+			}
+			send = withoutKeys(events)
+		}
+		eventIDs, err := publishBatch(apiClient, send, key)
+		if err != nil {
+			// A batch that was partly published can't be skipped as a whole
+			return eventIDs, nil, err
+		}
+		published.Add(key, eventIDs)
+		return eventIDs, nil, published.Save()
+	}
+
+	// Events skipped as already published, including repeats within the batch, take
+	// the ID of the event first published with their key
+	eventIDs := make([]string, len(events))
+	first := map[string]int{} // key -> index in pending
+	var pending []client.EventPublishRequest
+	var pendingIndex []int
+	var repeats [][2]int // index in events, index in pending
+	skipped := make([]bool, len(events))
+	for i, event := range events {
+		if event.IdempotencyKey != "" && !serverSide {
+			if recorded, ok := published.Lookup(event.IdempotencyKey); ok && len(recorded) == 1 {
+				eventIDs[i] = recorded[0]
+				skipped[i] = true
+				continue
+			}
+			if j, ok := first[event.IdempotencyKey]; ok {
+				repeats = append(repeats, [2]int{i, j})
+				skipped[i] = true
+				continue
+			}
+			first[event.IdempotencyKey] = len(pending)
+			event.IdempotencyKey = ""
+		}
+		pending = append(pending, event)
+		pendingIndex = append(pendingIndex, i)
+	}
+
+	var publishErr *client.PublishError
+	if len(pending) > 0 {
+		publishedIDs, err := publishBatches(apiClient, pending)
+		if err != nil && !errors.As(err, &publishErr) {
+			return nil, nil, err
+		}
+		if len(publishedIDs) != len(pending) {
+			return nil, nil, fmt.Errorf("server returned %d event IDs for %d events", len(publishedIDs), len(pending))
+		}
+		for j, i := range pendingIndex {
+			eventIDs[i] = publishedIDs[j]
+			if events[i].IdempotencyKey != "" && publishedIDs[j] != "" {
+				published.Add(events[i].IdempotencyKey, []string{publishedIDs[j]})
+			}
+		}
+	}
+	for _, repeat := range repeats {
+		eventIDs[repeat[0]] = eventIDs[pendingIndex[repeat[1]]]
+		// A repeat of an event that failed wasn't published at all
+		skipped[repeat[0]] = eventIDs[repeat[0]] != ""
+	}
+	if err := published.Save(); err != nil {
+		return nil, nil, err
+	}
+	if publishErr != nil {
+		// Failures are of pending events; report them, and their repeats, by position in
+		// the whole batch
+		failures := make([]client.EventFailure, 0, len(publishErr.Failures))
+		for _, failure := range publishErr.Failures {
+			j := failure.Index
+			failure.Index = pendingIndex[j]
+			failures = append(failures, failure)
+			for _, repeat := range repeats {
+				if repeat[1] == j {
+					failure.Index = repeat[0]
+					failures = append(failures, failure)
+				}
+			}
+		}
+		sort.Slice(failures, func(a, b int) bool { return failures[a].Index < failures[b].Index })
+		return eventIDs, skipped, &client.PublishError{EventIDs: eventIDs, Failures: failures, Rejected: publishErr.Rejected && countSkipped(skipped) == 0}
+	}
+	return eventIDs, skipped, nil
+}
+
+// publishBatch publishes events, all or none of them with --atomic, with key, if not "",
+// as the batch's idempotency key. Without --atomic, a batch of several events the event
+// store rejects as a whole without saying which events are at fault is published again
+// event by event, so the others are published and each failure is reported.
+func publishBatch(apiClient *client.Client, events []client.EventPublishRequest, key string) ([]string, error) {
+	if publishAtomic {
+		return apiClient.PublishEventsAtomic(events, key)
+	}
+	if key != "" {
+		// A keyed batch is published as one, or not at all
+		return apiClient.PublishEventsWithKey(events, key)
+	}
+
+	eventIDs, err := apiClient.PublishEvents(events)
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || !rejectsEvents(apiErr) {
+		return eventIDs, err
+	}
+	eventIDs = make([]string, len(events))
+	if len(events) == 1 {
+		return eventIDs, &client.PublishError{EventIDs: eventIDs, Failures: []client.EventFailure{failureOf(0, apiErr)}, Rejected: true}
+	}
+
+	var failures []client.EventFailure
+	for i, event := range events {
+		ids, err := apiClient.PublishEvents([]client.EventPublishRequest{event})
+		switch {
+		case err == nil && len(ids) == 1:
+			eventIDs[i] = ids[0]
+		case errors.As(err, &apiErr) && rejectsEvents(apiErr):
+			failures = append(failures, failureOf(i, apiErr))
+		case err == nil:
+			return eventIDs, fmt.Errorf("server returned %d event IDs for 1 event", len(ids))
+		default:
+			// The event store can't be reached or failed; the rest aren't tried
+			for j := i; j < len(events); j++ {
+				failures = append(failures, client.EventFailure{Index: j, Error: err.Error()})
+			}
+			return eventIDs, &client.PublishError{EventIDs: eventIDs, Failures: failures, Rejected: i == 0}
+		}
+	}
+	if len(failures) > 0 {
+		return eventIDs, &client.PublishError{EventIDs: eventIDs, Failures: failures, Rejected: len(failures) == len(events)}
+	}
+	return eventIDs, nil
+}
+
+// rejectsEvents reports whether the event store refused a publish for what was in it,
+// rather than failing to handle it
+func rejectsEvents(apiErr *client.APIError) bool {
+	return apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusUnprocessableEntity
+}
+
+// failureOf describes a rejected event
+func failureOf(index int, apiErr *client.APIError) client.EventFailure {
+	if apiErr.Message == "" {
+		return client.EventFailure{Index: index, Error: apiErr.Error()}
+	}
+	return client.EventFailure{Index: index, Error: apiErr.Message, Code: apiErr.Code}
+}
+
+// correlate gives events without their own the correlation and causation IDs from
+// --correlation-id and --causation-id, and those still without a correlation ID a new
+// one, which it returns. Servers that don't record the IDs get none.
+func correlate(apiClient *client.Client, events []client.EventPublishRequest) (string, error) {
+	if !apiClient.Supports(client.FeatureEventCorrelation) {
+		given := publishCorrelationID != "" || publishCausationID != ""
+		for _, event := range events {
+			given = given || event.CorrelationID != "" || event.CausationID != ""
+		}
+		if given {
+			return "", fmt.Errorf("the event store does not record correlation IDs (feature '%s')", client.FeatureEventCorrelation)
+		}
+		return "", nil
+	}
+
+	correlationID, generated := publishCorrelationID, ""
+	for i := range events {
+		if events[i].CorrelationID == "" {
+			if correlationID == "" {
+				correlationID = newCorrelationID()
+				generated = correlationID
+			}
+			events[i].CorrelationID = correlationID
+		}
+		if events[i].CausationID == "" {
+			events[i].CausationID = publishCausationID
+		}
+	}
+	return generated, nil
+}
+
+// addMetadata gives events the --metadata entries they don't have themselves. Servers
+// that don't keep metadata get none.
+func addMetadata(apiClient *client.Client, events []client.EventPublishRequest) error {
+	metadata := make(map[string]string, len(publishMetadata))
+	for _, entry := range publishMetadata {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --metadata '%s' (expected key=value)", entry)
+		}
+		metadata[strings.TrimSpace(key)] = value
+	}
+
+	if !apiClient.Supports(client.FeatureEventMetadata) {
+		given := len(metadata) > 0
+		for _, event := range events {
+			given = given || len(event.Metadata) > 0
+		}
+		if given {
+			return fmt.Errorf("the event store does not keep event metadata (feature '%s')", client.FeatureEventMetadata)
+		}
+		return nil
+	}
+
+	for i := range events {
+		for key, value := range metadata {
+			if _, ok := events[i].Metadata[key]; ok {
+				continue
+			}
+			if events[i].Metadata == nil {
+				events[i].Metadata = map[string]interface{}{}
+			}
+			events[i].Metadata[key] = value
+		}
+	}
+	return nil
+}
+
+// newCorrelationID returns a random UUID
+func newCorrelationID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// withoutKeys returns a copy of events without their idempotency keys, which servers that
+// don't know the field may reject
+func withoutKeys(events []client.EventPublishRequest) []client.EventPublishRequest {
+	stripped := make([]client.EventPublishRequest, len(events))
+	for i, event := range events {
+		event.IdempotencyKey = ""
+		stripped[i] = event
+	}
+	return stripped
+}
+
+// schedule gives events without a publishAt the time from --publish-at or --delay, checks
+// every publishAt, and reports whether any event is scheduled
+func schedule(events []client.EventPublishRequest, now time.Time) (bool, error) {
+	if publishAt != "" && publishDelay != "" {
+		return false, fmt.Errorf("--publish-at and --delay can't be used together")
+	}
+	at := ""
+	if publishAt != "" {
+		t, err := time.Parse(time.RFC3339Nano, publishAt)
+		if err != nil {
+			return false, fmt.Errorf("invalid --publish-at '%s' (expected an RFC 3339 timestamp such as 2025-06-01T09:00:00Z)", publishAt)
+		}
+		at = timerange.Format(t)
+	}
+	if publishDelay != "" {
+		delay, err := timerange.ParseDuration(publishDelay)
+		if err != nil {
+			return false, fmt.Errorf("invalid --delay: %w", err)
+		}
+		at = timerange.Format(now.Add(delay))
+	}
+
+	scheduled := false
+	for i := range events {
+		if events[i].PublishAt == "" {
+			events[i].PublishAt = at
+		}
+		if events[i].PublishAt == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339Nano, events[i].PublishAt); err != nil {
+			return false, fmt.Errorf("event %d: invalid publishAt '%s' (expected an RFC 3339 timestamp)", i+1, events[i].PublishAt)
+		}
+		scheduled = true
+	}
+	return scheduled, nil
+}
+
+// spoolScheduled spools the events due after now, a spool entry per publish time, and
+// returns the events to publish now
+func spoolScheduled(server string, events []client.EventPublishRequest, now time.Time) ([]client.EventPublishRequest, []*spool.Entry, error) {
+	var due []client.EventPublishRequest
+	later := map[time.Time][]client.EventPublishRequest{}
+	for _, event := range events {
+		at, _ := time.Parse(time.RFC3339Nano, event.PublishAt)
+		event.PublishAt = ""
+		if !at.After(now) {
+			due = append(due, event)
+			continue
+		}
+		later[at] = append(later[at], event)
+	}
+
+	times := make([]time.Time, 0, len(later))
+	for at := range later {
+		times = append(times, at)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	entries := make([]*spool.Entry, 0, len(times))
+	for _, at := range times {
+		entry, err := spool.Add(server, at, later[at])
+		if err != nil {
+			return nil, entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return due, entries, nil
 }
 
 func init() {
 	cmd.EventCmd().AddCommand(publishCmd)
 	publishCmd.Flags().StringVar(&publishFile, "file", "", "Path to JSON file containing events")
 	publishCmd.Flags().StringVar(&publishJSON, "json", "", "Inline JSON string containing events")
+	publishCmd.Flags().BoolVar(&publishInteractive, "interactive", false, "Pick a topic and event type, and enter the event's payload at prompts from its schema")
+	publishCmd.Flags().BoolVar(&publishEdit, "edit", false, "Pick a topic and event type, and write events from its schema's payload skeleton in $VISUAL or $EDITOR")
+	publishCmd.MarkFlagsMutuallyExclusive("file", "json", "interactive", "edit")
+	cmd.DisablePagerWith(publishCmd, "interactive", "edit")
+	publishCmd.Flags().StringVar(&publishAt, "publish-at", "", "Publish the events at this RFC 3339 time instead of now")
+	publishCmd.Flags().StringVar(&publishDelay, "delay", "", "Publish the events after this long, e.g. '10m', '2h' or '1d'")
+	publishCmd.Flags().StringVar(&publishIdempotencyKey, "idempotency-key", "", "Publish the batch at most once under this key")
+	publishCmd.Flags().BoolVar(&publishDedupe, "dedupe", false, "Give events without an idempotencyKey one made from a hash of their topic, type and payload")
+	publishCmd.Flags().BoolVar(&publishAtomic, "atomic", false, "Publish all of the events or none of them (experimental; needs a server with the 'atomic-publish' feature)")
+	publishCmd.Flags().StringVar(&publishCorrelationID, "correlation-id", "", "Correlation ID of events without their own (default: a new one for the batch)")
+	publishCmd.Flags().StringVar(&publishCausationID, "causation-id", "", "ID of the event that caused the events, for events without their own")
+	publishCmd.Flags().IntVar(&publishBatchSize, "batch-size", 500, "Number of events per request for large inputs")
+	publishCmd.Flags().IntVar(&publishWorkers, "workers", 1, "Number of batches to publish at a time; batches may be published out of order with more than 1")
+	publishCmd.Flags().IntVar(&publishRetries, "retries", 3, "Times to retry a batch that fails for a reason other than its events")
+	publishCmd.Flags().BoolVar(&publishStrict, "strict", false, "Publish nothing if an event fails the checks, which include payload fields its type's schema doesn't declare")
+	publishCmd.Flags().StringVar(&publishMaxPayloadSize, "max-payload-size", "256kb", "Largest payload the checks allow, e.g. '64kb' or '1mb' (0 = no limit; default: publish.max-payload-size in the config file)")
+	publishCmd.Flags().BoolVar(&publishEncrypt, "encrypt", false, "Encrypt payload fields with the current key of --key-file before publishing")
+	publishCmd.Flags().StringSliceVar(&publishEncryptFields, "encrypt-fields", nil, "Payload fields to encrypt, 'payload.<path>' (comma-separated; default: every top-level field)")
+	publishCmd.Flags().StringVar(&publishKeyFile, "key-file", "", "JSON file of the AES keys to encrypt with")
+	publishCmd.Flags().StringArrayVar(&publishMetadata, "metadata", nil, "Metadata entry 'key=value' for events without their own, e.g. 'tenant=acme' (repeatable)")
+	cmd.AddLimitFlags(publishCmd)
 }
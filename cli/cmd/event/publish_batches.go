@@ -0,0 +1,185 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// retryBackoff is how long to wait before retrying a failed batch the first time; it
+// doubles with each retry
+const retryBackoff = 500 * time.Millisecond
+
+var (
+	publishBatchSize int
+	publishWorkers   int
+	publishRetries   int
+)
+
+// publishBatches publishes events in batches of --batch-size, --workers batches at a
+// time, showing progress. A batch that fails for a reason other than its events, such as
+// a timeout or a server error, is tried again up to --retries times; one that still fails
+// leaves its events unpublished, and the other batches are published. Events of one batch
+// are published in order, but with several workers batches may be published out of order.
+func publishBatches(apiClient *client.Client, events []client.EventPublishRequest) ([]string, error) {
+	if publishAtomic || len(events) <= publishBatchSize {
+		eventIDs, err := publishBatch(apiClient, events, "")
+		if streamProgress != nil {
+			streamProgress.Add(len(events))
+		}
+		return eventIDs, err
+	}
+
+	group, err := cmd.NewRunner()
+	if err != nil {
+		return nil, err
+	}
+	type batch struct{ start, end int }
+	batches := make(chan batch)
+	group.Go("publish-batches", func(ctx context.Context) error {
+		defer close(batches)
+		for start := 0; start < len(events); start += publishBatchSize {
+			select {
+			case batches <- batch{start, min(start+publishBatchSize, len(events))}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		return nil
+	})
+
+	eventIDs := make([]string, len(events))
+	var failures []client.EventFailure
+	var mu sync.Mutex
+	published, failedBatches, batchCount := 0, 0, 0
+	started := time.Now()
+	progress := streamProgress
+	if progress == nil {
+		progress = output.NewProgress("Publishing", len(events))
+	}
+	for worker := 0; worker < publishWorkers; worker++ {
+		group.Go(fmt.Sprintf("publish-worker-%d", worker), func(ctx context.Context) error {
+			for b := range batches {
+				ids, err := publishRetrying(ctx, apiClient, events[b.start:b.end])
+
+				mu.Lock()
+				batchCount++
+				copy(eventIDs[b.start:b.end], ids)
+				var publishErr *client.PublishError
+				switch {
+				case errors.As(err, &publishErr):
+					for _, failure := range publishErr.Failures {
+						failure.Index += b.start
+						failures = append(failures, failure)
+					}
+				case err != nil:
+					failedBatches++
+					for i := b.start; i < b.end; i++ {
+						failures = append(failures, client.EventFailure{Index: i, Error: err.Error()})
+					}
+				}
+				for _, id := range ids {
+					if id != "" {
+						published++
+					}
+				}
+				mu.Unlock()
+				progress.Add(b.end - b.start)
+			}
+			return nil
+		})
+	}
+	err = group.Wait()
+	if streamProgress == nil {
+		progress.Finish()
+	}
+	if err != nil {
+		return eventIDs, err
+	}
+
+	// Batches not started when interrupted
+	attempted := make([]bool, len(events))
+	for i, id := range eventIDs {
+		attempted[i] = id != ""
+	}
+	for _, failure := range failures {
+		attempted[failure.Index] = true
+	}
+	for i := range events {
+		if !attempted[i] {
+			failures = append(failures, client.EventFailure{Index: i, Error: "not published: interrupted"})
+			publishInterrupted = true
+		}
+	}
+
+	// A streamed input's summary is of all of its chunks
+	if !cmd.Quiet() && streamProgress == nil {
+		elapsed := time.Since(started)
+		fmt.Fprintf(os.Stderr, "Published %d of %d event(s) in %d batch(es) in %s (%.1f events/s)\n",
+			published, len(events), batchCount, elapsed.Round(time.Millisecond), float64(published)/elapsed.Seconds())
+	}
+	if failedBatches > 0 && !cmd.Quiet() {
+		fmt.Fprintf(os.Stderr, "%d batch(es) failed as a whole, after retrying those that could be\n", failedBatches)
+	}
+	if len(failures) > 0 {
+		sort.Slice(failures, func(a, b int) bool { return failures[a].Index < failures[b].Index })
+		return eventIDs, &client.PublishError{EventIDs: eventIDs, Failures: failures, Rejected: published == 0}
+	}
+	return eventIDs, nil
+}
+
+// publishRetrying publishes a batch, retrying with a growing backoff when it fails for a
+// reason other than its events
+func publishRetrying(ctx context.Context, apiClient *client.Client, events []client.EventPublishRequest) ([]string, error) {
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		ids, err := publishBatch(apiClient, events, "")
+		if err == nil || attempt == publishRetries || !retryable(err) {
+			return ids, err
+		}
+		select {
+		case <-ctx.Done():
+			return ids, err
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+// retryable reports whether a batch that failed can be published again: it failed as a
+// whole, and not because the event store refused its events
+func retryable(err error) bool {
+	var publishErr *client.PublishError
+	if errors.As(err, &publishErr) {
+		// Some events may have been published
+		return false
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// checkBatchFlags checks --batch-size, --workers and --retries, which only apply to
+// events published without --atomic or a batch --idempotency-key
+func checkBatchFlags(cobraCmd *cobra.Command) error {
+	if publishBatchSize < 1 || publishWorkers < 1 || publishRetries < 0 {
+		return fmt.Errorf("--batch-size and --workers must be at least 1, and --retries can't be negative")
+	}
+	split := cobraCmd.Flags().Changed("batch-size") || cobraCmd.Flags().Changed("workers")
+	if split && (publishAtomic || publishIdempotencyKey != "") {
+		return fmt.Errorf("--batch-size and --workers can't be used with --atomic or --idempotency-key, which publish the events as one batch")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package event
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/guard"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	publishStrict         bool
+	publishMaxPayloadSize string
+)
+
+// newGuard returns the checker of events to be published, set by --strict and
+// --max-payload-size or else the config file's 'publish' section
+func newGuard(cobraCmd *cobra.Command, apiClient *client.Client) (*guard.Checker, bool, error) {
+	cfg := cmd.GetConfig()
+	size := cfg.Publish.MaxPayloadSize
+	if cobraCmd.Flags().Changed("max-payload-size") {
+		size = publishMaxPayloadSize
+	}
+	maxBytes := 0
+	if size != "" {
+		var err error
+		if maxBytes, err = bench.ParseSize(size); err != nil {
+			return nil, false, fmt.Errorf("invalid max payload size: %w", err)
+		}
+	}
+	strict := publishStrict || cfg.Publish.Strict
+	return guard.NewChecker(apiClient, guard.Options{MaxPayloadBytes: maxBytes, Strict: strict}), strict, nil
+}
+
+// guardEvents checks events before they are published; first is the number of events of
+// the input before them. Problems are warnings, unless strict, when they stop the publish.
+func guardEvents(checker *guard.Checker, strict bool, events []client.EventPublishRequest, first int) error {
+	problems, err := checker.Check(events)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if !strict {
+		fmt.Fprintf(os.Stderr, "Warning: %d event(s) may be wrong:\n", len(problems))
+		output.PrintPublishFailures(events, first, problems)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "%d event(s) failed the checks made with --strict:\n", len(problems))
+	output.PrintPublishFailures(events, first, problems)
+	if first > 0 {
+		return fmt.Errorf("%d event(s) failed the publish checks; publishing stopped after the first %d event(s)", len(problems), first)
+	}
+	return fmt.Errorf("%d event(s) failed the publish checks; no events were published", len(problems))
+}
@@ -0,0 +1,146 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/compose"
+	"github.com/event-store/cli/internal/generate"
+)
+
+var (
+	// publishInteractive builds the event to publish from prompts instead of --file or --json
+	publishInteractive bool
+	// publishEdit edits the events to publish, starting from a payload skeleton, in an editor
+	publishEdit bool
+)
+
+// composeEvent builds an event for 'es event publish --interactive': the user picks a
+// topic and one of its event types, and is prompted for each property of the type's
+// schema, as by 'es event compose'. The event is shown and, once confirmed, returned; nil
+// is returned if the user declines to publish it.
+func composeEvent(apiClient *client.Client, server string) (*client.EventPublishRequest, error) {
+	composer := compose.New(os.Stdin, os.Stderr)
+	topicName, schema, _, err := chooseEventType(apiClient, composer)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "\nComposing a %s event for '%s'\n", schema.EventType, topicName)
+	payload, err := composer.Payload(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	event := client.EventPublishRequest{Topic: topicName, Type: schema.EventType, Payload: payload}
+	preview, _ := json.MarshalIndent(event, "", "  ")
+	fmt.Fprintf(os.Stderr, "\n%s\n\n", preview)
+	publish, err := composer.Confirm(fmt.Sprintf("Publish this event to %s?", server), true)
+	if err != nil {
+		return nil, err
+	}
+	if !publish {
+		fmt.Fprintln(os.Stderr, "Not published")
+		return nil, nil
+	}
+	return &event, nil
+}
+
+// editEvents builds the events for 'es event publish --edit': the user picks a topic and
+// one of its event types, and edits an event with a payload skeleton from the type's
+// schema in an editor, adding more events if they like. The events are checked against
+// their schemas when saved and, once the user confirms them, returned as JSON; nil is
+// returned if they were left unchanged.
+func editEvents(apiClient *client.Client, server string) ([]byte, error) {
+	composer := compose.New(os.Stdin, os.Stderr)
+	topicName, schema, topics, err := chooseEventType(apiClient, composer)
+	if err != nil {
+		return nil, err
+	}
+
+	skeleton := []client.EventPublishRequest{{Topic: topicName, Type: schema.EventType, Payload: generate.Skeleton(schema)}}
+	data, err := cmd.EditJSON(skeleton, schema.EventType+"-*.json", fmt.Sprintf("Publish these events to %s?", server), func(data []byte) error {
+		return checkEditedEvents(topics, data)
+	})
+	if errors.Is(err, cmd.ErrAborted) {
+		fmt.Fprintln(os.Stderr, "Not published")
+		return nil, nil
+	}
+	if err == nil && data == nil {
+		fmt.Fprintln(os.Stderr, "Not published, as the event wasn't changed")
+	}
+	return data, err
+}
+
+// chooseEventType asks which of the topics with schemas, and which of its event types, to
+// build an event of, returning the topics with schemas by name too
+func chooseEventType(apiClient *client.Client, composer *compose.Composer) (string, client.Schema, map[string]*client.Topic, error) {
+	topics, err := apiClient.GetTopics()
+	if err != nil {
+		return "", client.Schema{}, nil, err
+	}
+	var names []string
+	schemas := map[string]*client.Topic{}
+	for i := range topics {
+		if len(topics[i].Schemas) > 0 {
+			names = append(names, topics[i].Name)
+			schemas[topics[i].Name] = &topics[i]
+		}
+	}
+	if len(names) == 0 {
+		return "", client.Schema{}, nil, fmt.Errorf("no topics have schemas to build events from; publish with --file or --json")
+	}
+	sort.Strings(names)
+
+	topicName, err := composer.Choose("Topic", names)
+	if err != nil {
+		return "", client.Schema{}, nil, err
+	}
+	schema, err := chooseSchema(composer, schemas[topicName], "")
+	if err != nil {
+		return "", client.Schema{}, nil, err
+	}
+	return topicName, schema, schemas, nil
+}
+
+// checkEditedEvents checks the events saved in the editor: a JSON array of events, each
+// with a topic that has a schema for its type and a payload that the schema accepts
+func checkEditedEvents(topics map[string]*client.Topic, data []byte) error {
+	var events []client.EventPublishRequest
+	if err := json.Unmarshal(data, &events); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no events")
+	}
+
+	var problems []string
+	for i, event := range events {
+		topic, ok := topics[event.Topic]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("event %d: topic '%s' has no schemas", i+1, event.Topic))
+			continue
+		}
+		schemas, err := selectSchemas(topic, []string{event.Type})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("event %d: %v", i+1, err))
+			continue
+		}
+		if event.Payload == nil {
+			problems = append(problems, fmt.Sprintf("event %d: payload must be an object", i+1))
+			continue
+		}
+		for _, problem := range compose.Check(schemas[0], event.Payload) {
+			problems = append(problems, fmt.Sprintf("event %d: payload.%s", i+1, problem))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problem(s) found:\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
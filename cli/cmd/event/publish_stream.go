@@ -0,0 +1,240 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/spool"
+)
+
+// streamChunkSize is the least number of events of a large input held in memory at once
+const streamChunkSize = 10000
+
+// streamProgress is the progress bar of a streamed input, which the batches of every
+// chunk add to, or nil
+var streamProgress *output.Progress
+
+// publishInterrupted is set when Ctrl+C stopped batches being published, so a streamed
+// input stops after the chunk being published
+var publishInterrupted bool
+
+// publishCheckpoint is how far the publish job of a streamed file got: the number of
+// events of the file in the chunks published
+type publishCheckpoint struct {
+	Events int `json:"events"`
+}
+
+// eventReader decodes events one at a time from a JSON array of events, or from JSON
+// objects one after the other such as newline-delimited JSON, so inputs larger than
+// memory can be published
+type eventReader struct {
+	decoder *json.Decoder
+	array   bool
+	done    bool
+	read    int
+}
+
+func newEventReader(r io.Reader) (*eventReader, error) {
+	buffered := bufio.NewReader(r)
+	reader := &eventReader{decoder: json.NewDecoder(buffered)}
+	for {
+		b, err := buffered.ReadByte()
+		if errors.Is(err, io.EOF) {
+			reader.done = true
+			return reader, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b == ' ' || b == '\t' || b == '\r' || b == '\n' {
+			continue
+		}
+		buffered.UnreadByte()
+		reader.array = b == '['
+		break
+	}
+	if reader.array {
+		// The array's opening bracket
+		if _, err := reader.decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return reader, nil
+}
+
+// Next returns up to n more events, none once all have been read
+func (r *eventReader) Next(n int) ([]client.EventPublishRequest, error) {
+	var events []client.EventPublishRequest
+	for !r.done && len(events) < n {
+		if !r.decoder.More() {
+			if err := r.finish(); err != nil {
+				return nil, err
+			}
+			break
+		}
+		var event client.EventPublishRequest
+		if err := r.decoder.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to parse event %d: %w", r.read+1, err)
+		}
+		events = append(events, event)
+		r.read++
+	}
+	if !r.done && !r.decoder.More() {
+		if err := r.finish(); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// Done reports whether every event has been read
+func (r *eventReader) Done() bool {
+	return r.done
+}
+
+// finish reads the end of the input, which must have nothing after the events
+func (r *eventReader) finish() error {
+	r.done = true
+	if r.array {
+		if _, err := r.decoder.Token(); err != nil {
+			return fmt.Errorf("failed to parse the end of the events: %w", err)
+		}
+	}
+	if _, err := r.decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("unexpected content after event %d", r.read)
+	}
+	return nil
+}
+
+// publishStream publishes an input too large to hold in memory a chunk at a time, each
+// prepared and published as a smaller input is, and reports failures as each chunk is
+// published, numbering events across the whole input. Each chunk is checked before it's
+// published. Only the events' IDs are kept. A --file input is published as a job that
+// records the chunks published, so a resumed job skips them; Ctrl+C stops it after the
+// chunk being published.
+func publishStream(apiClient *client.Client, server string, reader *eventReader, events []client.EventPublishRequest, chunk int, check func(events []client.EventPublishRequest, first int) error) error {
+	checkpoint := &publishCheckpoint{}
+	var job *jobs.Job
+	if publishFile != "" {
+		var resumed bool
+		var err error
+		if job, resumed, err = cmd.StartJob("publish", 0, checkpoint); err != nil {
+			return err
+		}
+		if resumed {
+			if events, err = skipEvents(reader, events, chunk, checkpoint.Events); err != nil {
+				return job.Finish(err)
+			}
+		}
+	}
+	// Events are numbered from the start of the input
+	skipped := checkpoint.Events
+
+	streamProgress = output.NewProgress("Publishing", -1)
+	defer func() { streamProgress = nil }()
+	publishInterrupted = false
+	started := time.Now()
+
+	eventIDs := []string{}
+	var deduped []bool // events skipped as already published
+	var failures []client.EventFailure
+	var spooled []*spool.Entry
+	for len(events) > 0 {
+		if err := check(events, skipped+len(eventIDs)); err != nil {
+			streamProgress.Finish()
+			return job.Finish(err)
+		}
+		ids, idsSkipped, entries, err := publishEvents(apiClient, server, events)
+		spooled = append(spooled, entries...)
+		var publishErr *client.PublishError
+		if err != nil && !errors.As(err, &publishErr) {
+			streamProgress.Finish()
+			if len(eventIDs) > 0 {
+				fmt.Fprintf(os.Stderr, "%d event(s) were published before the error\n", len(eventIDs))
+			}
+			return job.Finish(err)
+		}
+		if publishErr != nil {
+			output.PrintPublishFailures(events[:len(ids)], skipped+len(eventIDs), publishErr.Failures)
+			for _, failure := range publishErr.Failures {
+				failure.Index += len(eventIDs)
+				failures = append(failures, failure)
+			}
+		}
+		eventIDs = append(eventIDs, ids...)
+		if idsSkipped == nil {
+			idsSkipped = make([]bool, len(ids))
+		}
+		deduped = append(deduped, idsSkipped...)
+		if publishInterrupted {
+			break
+		}
+		checkpoint.Events += len(events)
+		if err := job.Save(checkpoint, checkpoint.Events); err != nil {
+			streamProgress.Finish()
+			return err
+		}
+
+		if events, err = reader.Next(chunk); err != nil {
+			streamProgress.Finish()
+			return job.Finish(err)
+		}
+	}
+	streamProgress.Finish()
+	if publishInterrupted {
+		job.Finish(context.Canceled)
+	} else if err := job.Finish(nil); err != nil {
+		return err
+	}
+
+	alreadyPublished := countSkipped(deduped)
+	published := len(eventIDs) - len(failures) - alreadyPublished
+	if !cmd.Quiet() {
+		elapsed := time.Since(started)
+		var skippedNote string
+		if alreadyPublished > 0 {
+			skippedNote = fmt.Sprintf(", skipping %d already published,", alreadyPublished)
+		}
+		fmt.Fprintf(os.Stderr, "Published %d of %d event(s)%s in %s (%.1f events/s)\n",
+			published, len(eventIDs), skippedNote, elapsed.Round(time.Millisecond), float64(published)/elapsed.Seconds())
+		if publishInterrupted && job != nil {
+			fmt.Fprintf(os.Stderr, "Interrupted; publish the rest of the file with 'es job resume %s'\n", job.ID)
+		}
+	}
+	if err := printPublished(eventIDs, deduped, failures, spooled); err != nil {
+		return err
+	}
+	switch {
+	case len(failures) > 0 && published == 0:
+		return fmt.Errorf("%d of %d event(s) failed to publish; no events were published", len(failures), len(eventIDs))
+	case len(failures) > 0:
+		return fmt.Errorf("%d of %d event(s) failed to publish; the others were published", len(failures), len(eventIDs))
+	}
+	return nil
+}
+
+// skipEvents drops the first n events of the input, those a resumed job published,
+// returning the events of the chunk after them
+func skipEvents(reader *eventReader, events []client.EventPublishRequest, chunk, n int) ([]client.EventPublishRequest, error) {
+	for n > 0 && len(events) > 0 {
+		skip := min(n, len(events))
+		events, n = events[skip:], n-skip
+		if len(events) == 0 {
+			var err error
+			if events, err = reader.Next(chunk); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,211 @@
+package event
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+var (
+	redactFilters   []string
+	redactFields    []string
+	redactMask      string
+	redactDryRun    bool
+	redactConfirm   string
+	redactAuditFile string
+)
+
+// redactColumns are the columns of the report of events to redact
+var redactColumns = []output.Column{
+	{Name: "id", Header: "Event ID"},
+	{Name: "type", Header: "Type"},
+	{Name: "timestamp", Header: "Timestamp"},
+	{Name: "fields", Header: "Fields"},
+}
+
+var redactCmd = &cobra.Command{
+	Use:   "redact <topic> --filter <field:value> --fields <field,...>",
+	Short: "Remove personal data from published events (experimental)",
+	Long: `Remove or mask fields of a topic's published events, such as to erase a person's data
+for a GDPR request.
+
+Experimental: the event store server in this repository can't change published events
+yet. Redacting needs a server that advertises the 'event-redaction' feature (see
+es version --server) and fails without one; --dry-run works with any server.
+
+The events matching every --filter that have any of --fields are always listed first,
+with the fields each has. Use --dry-run to only list them. Otherwise the redaction has
+to be confirmed by typing the topic name, or with --confirm <topic> in scripts.
+
+Fields are dotted paths under 'payload.' or 'metadata.'. They are removed, or with
+--mask replaced by a value. Events are redacted in batches, as a job: if it's stopped
+part way, 'es job resume' continues after the last batch redacted. --audit-file appends
+a JSON record of the redaction, or of the dry run, to a file: who ran it, when, against
+which server, and the events changed.
+
+Examples:
+  # See which events hold a customer's email and name
+  es event redact users --filter payload.email:alice@example.com --fields payload.email,payload.name --dry-run
+
+  # Erase them, recording the erasure
+  es event redact users --filter payload.email:alice@example.com --fields payload.email,payload.name \
+    --audit-file erasures.ndjson --confirm users`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		if len(redactFilters) == 0 {
+			return fmt.Errorf("--filter is required, so only the events of the data's subject are redacted")
+		}
+		var filters []*filter.Filter
+		for _, expr := range redactFilters {
+			parsed, err := filter.ParseAll(expr)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, parsed...)
+		}
+		if err := redact.CheckFields(redactFields); err != nil {
+			return err
+		}
+
+		matches, err := redact.Find(apiClient, topicName, filters, redactFields)
+		if err != nil {
+			return err
+		}
+		record := &redact.Record{
+			Time:   time.Now().UTC(),
+			User:   os.Getenv("USER"),
+			Server: cfg.Server.URL,
+			Topic:  topicName,
+			Filter: strings.Join(redactFilters, " AND "),
+			Fields: redactFields,
+			Mask:   redactMask,
+			DryRun: redactDryRun,
+			Events: matches,
+		}
+
+		listing := output.NewListing(redactColumns)
+		for _, match := range matches {
+			listing.Add(match.EventID, match, match.EventID, match.Type, match.Timestamp, strings.Join(match.Fields, ", "))
+		}
+		table := cfg.Output.Format == "table" && !cmd.Quiet()
+		if table {
+			if err := cmd.PrintListing("events", listing); err != nil {
+				return err
+			}
+		}
+
+		if !redactDryRun && len(matches) > 0 {
+			if !apiClient.Supports(client.FeatureEventRedaction) {
+				return fmt.Errorf("the event store does not support redacting events (feature '%s'); use --dry-run to only list them", client.FeatureEventRedaction)
+			}
+			what := fmt.Sprintf("%d event(s) of '%s' will have %s %s", len(matches), topicName, strings.Join(redactFields, ", "), redactAction())
+			if err := cmd.ConfirmName(what, topicName, redactConfirm); err != nil {
+				return err
+			}
+			job, remaining, err := startRedactJob(matches)
+			if err != nil {
+				return err
+			}
+			record.Redacted, err = redact.Apply(apiClient, topicName, remaining, redactFields, redactMask, func(redacted int, lastEventID string) error {
+				return job.Save(&redactCheckpoint{After: lastEventID}, len(matches)-len(remaining)+redacted)
+			})
+			record.Redacted += len(matches) - len(remaining)
+			job.Finish(err)
+			if err != nil {
+				// Record the events that were redacted before the failure
+				record.Time = time.Now().UTC()
+				if auditErr := appendRedactAudit(record); auditErr != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", auditErr)
+				}
+				return fmt.Errorf("%d of %d event(s) were redacted before an error (resume with 'es job resume %s'): %w", record.Redacted, len(matches), job.ID, err)
+			}
+			record.Time = time.Now().UTC()
+		}
+		if err := appendRedactAudit(record); err != nil {
+			return err
+		}
+
+		switch {
+		case table:
+			verb := "Redacted"
+			count := record.Redacted
+			if redactDryRun {
+				verb, count = "Would redact", len(matches)
+			}
+			fmt.Printf("%s %d event(s) of '%s'\n", verb, count, topicName)
+			return nil
+		case cfg.Output.Format == "json" && !cmd.Quiet():
+			return output.PrintJSON(record)
+		default:
+			return cmd.PrintListing("events", listing)
+		}
+	},
+}
+
+// redactCheckpoint is how far a redact job got: the last event of the last batch redacted
+type redactCheckpoint struct {
+	After string `json:"after"`
+}
+
+// startRedactJob starts the redaction of matched events as a job, returning the matches
+// left to redact: those after the checkpoint of a resumed job, else all of them
+func startRedactJob(matches []redact.Match) (*jobs.Job, []redact.Match, error) {
+	var checkpoint redactCheckpoint
+	job, resumed, err := cmd.StartJob("redact", len(matches), &checkpoint)
+	if err != nil || !resumed || checkpoint.After == "" {
+		return job, matches, err
+	}
+	after, err := eventid.Parse(checkpoint.After)
+	if err != nil {
+		return nil, nil, job.Finish(err)
+	}
+	for i, match := range matches {
+		if id, err := eventid.Parse(match.EventID); err == nil && id.Sequence > after.Sequence {
+			return job, matches[i:], nil
+		}
+	}
+	return job, nil, nil
+}
+
+// redactAction describes what happens to the fields redacted
+func redactAction() string {
+	if redactMask != "" {
+		return fmt.Sprintf("replaced by '%s'", redactMask)
+	}
+	return "removed"
+}
+
+// appendRedactAudit appends the record of a redaction to --audit-file, if given
+func appendRedactAudit(record *redact.Record) error {
+	if redactAuditFile == "" {
+		return nil
+	}
+	return redact.AppendAudit(redactAuditFile, record)
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(redactCmd)
+	// The report is shown before asking for confirmation
+	cmd.DisablePager(redactCmd)
+	redactCmd.Flags().StringArrayVar(&redactFilters, "filter", nil, "Only redact events matching this filter ('field:value', repeatable; all must match)")
+	redactCmd.Flags().StringSliceVar(&redactFields, "fields", nil, "Fields to redact, 'payload.<path>' or 'metadata.<path>' (comma-separated, required)")
+	redactCmd.Flags().StringVar(&redactMask, "mask", "", "Replace the fields with this value instead of removing them, e.g. '[redacted]'")
+	redactCmd.Flags().BoolVar(&redactDryRun, "dry-run", false, "Only list the events that would be redacted")
+	redactCmd.Flags().StringVar(&redactConfirm, "confirm", "", "Confirm without being asked, by giving the topic name")
+	redactCmd.Flags().StringVar(&redactAuditFile, "audit-file", "", "Append a JSON record of the redaction to this file")
+	redactCmd.MarkFlagRequired("fields")
+}
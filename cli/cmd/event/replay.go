@@ -0,0 +1,263 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/copier"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayDestServer  string
+	replayFromEventID string
+	replayToEventID   string
+	replayFilters     []string
+	replayRenameTypes []string
+	replayTransform   string
+	replayDropFields  []string
+	replayBatchSize   int
+	replayDryRun      bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <source-topic> <dest-topic>",
+	Short: "Copy events from one topic to another",
+	Long: `Read a topic's events in order and republish them to another topic, on this server or
+on another one (--dest-server). Use it to migrate topics between servers, reprocess
+events into new schemas or seed an environment.
+
+Events can be filtered (--filter, all must match) and transformed: --rename-type renames
+event types, --drop-field removes payload fields and --transform rewrites payloads with a
+jq expression (run by jq, which must be on the PATH) or a Go template. A template sees
+the payload as '.', has a 'json' function and must render a JSON object. A transform
+that produces nothing (jq 'empty', or empty template output) skips the event. The
+destination topic must exist and have schemas for the published event types.
+Republished events get new IDs and timestamps.
+
+The copy covers the events in the source topic when it starts, so copying a topic into
+itself cannot run forever. It runs as a job that records the last source event copied
+after each publish, so when it stops at the first failed publish, or is interrupted, it
+can be continued with 'es job resume'.
+
+Examples:
+  # Copy a topic to another server
+  es event replay orders orders --dest-server http://staging:8000
+
+  # Reprocess a range of events into a new topic, renaming a type and dropping a field
+  es event replay orders orders-v2 --from-event-id orders-1000 \
+    --rename-type order.created=order.placed --drop-field customer.phone
+
+  # Migrate payloads to a new schema with jq
+  es event replay orders orders-v2 --transform '.total |= tonumber | .currency //= "EUR"'
+
+  # The same with a Go template
+  es event replay orders orders-v2 \
+    --transform '{"orderId": {{json .orderId}}, "total": {{.total}}, "currency": "EUR"}'
+
+  # See how many events would be copied
+  es event replay user-events seed-users --filter type:user.created --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		source := cmd.NewClient()
+		sourceTopic, destTopic := args[0], args[1]
+
+		if replayBatchSize < 1 {
+			return fmt.Errorf("batch-size must be at least 1")
+		}
+		destServer := strings.TrimSuffix(replayDestServer, "/")
+		sameServer := destServer == "" || destServer == strings.TrimSuffix(cfg.Server.URL, "/")
+		if sameServer && sourceTopic == destTopic {
+			return fmt.Errorf("source and destination are the same topic; use --dest-server to copy to another server")
+		}
+
+		filters := make([]*filter.Filter, len(replayFilters))
+		for i, expr := range replayFilters {
+			f, err := filter.Parse(expr)
+			if err != nil {
+				return err
+			}
+			filters[i] = f
+		}
+		typeMap, err := copier.ParseTypeMap(replayRenameTypes)
+		if err != nil {
+			return err
+		}
+		var transformer transform.Transformer
+		if replayTransform != "" {
+			if transformer, err = transform.New(replayTransform); err != nil {
+				return err
+			}
+			defer transformer.Close()
+		}
+		after, through, err := parseCopyRange(sourceTopic, replayFromEventID, replayToEventID)
+		if err != nil {
+			return err
+		}
+
+		dest := source
+		if !sameServer {
+			dest = cmd.NewClientFor(destServer)
+		}
+		topic, err := source.GetTopic(sourceTopic)
+		if err != nil {
+			return err
+		}
+		if _, err := dest.GetTopic(destTopic); err != nil {
+			return fmt.Errorf("destination topic '%s': %w", destTopic, err)
+		}
+		if through == 0 || through > int64(topic.Sequence) {
+			through = int64(topic.Sequence)
+		}
+
+		// A resumed replay copies the rest of the events it started with
+		checkpoint := &replayCheckpoint{Start: after, Through: through}
+		var job *jobs.Job
+		if !replayDryRun {
+			var resumed bool
+			if job, resumed, err = cmd.StartJob("replay", int(max(through-after, 0)), checkpoint); err != nil {
+				return err
+			}
+			if resumed {
+				through = checkpoint.Through
+				if checkpoint.After != "" {
+					id, err := eventid.Parse(checkpoint.After)
+					if err != nil {
+						return job.Finish(err)
+					}
+					after = id.Sequence
+				}
+			}
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return job.Finish(err)
+		}
+
+		var result *copier.Result
+		var copyErr error
+		total := 0
+		if through > after {
+			total = int(through - after)
+		}
+		progress := output.NewProgress("Copying", total)
+		group.Go("event-replay", func(ctx context.Context) error {
+			result, copyErr = copier.Copy(ctx, copier.Options{
+				Source:      source,
+				Destination: dest,
+				SourceTopic: sourceTopic,
+				DestTopic:   destTopic,
+				After:       after,
+				Through:     through,
+				Filters:     filters,
+				TypeMap:     typeMap,
+				DropFields:  replayDropFields,
+				Transform:   transformer,
+				BatchSize:   replayBatchSize,
+				DryRun:      replayDryRun,
+				Checkpoint: func(lastEventID string) error {
+					id, err := eventid.Parse(lastEventID)
+					if err != nil {
+						return err
+					}
+					checkpoint.After = lastEventID
+					return job.Save(checkpoint, int(id.Sequence-checkpoint.Start))
+				},
+			}, progress.Add)
+			group.Stop()
+			return nil
+		})
+		err = group.Wait()
+		progress.Finish()
+		if err != nil {
+			return job.Finish(err)
+		}
+		if !sameServer {
+			result.DestServer = destServer
+		}
+		job.Finish(copyErr)
+		if copyErr != nil && !errors.Is(copyErr, context.Canceled) {
+			if job != nil {
+				copyErr = fmt.Errorf("%w (resume with 'es job resume %s')", copyErr, job.ID)
+			} else if result.LastEventID != "" {
+				copyErr = fmt.Errorf("%w (resume with --from-event-id %s)", copyErr, result.LastEventID)
+			}
+			return copyErr
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(result)
+		case "csv":
+			return output.PrintCopyResultCSV(result)
+		default:
+			if copyErr != nil {
+				fmt.Println("Copy interrupted")
+			}
+			output.PrintCopyResult(result)
+			return nil
+		}
+	},
+}
+
+// replayCheckpoint is how far a replay job got: the last source event copied, of those
+// after Start through Through
+type replayCheckpoint struct {
+	Start   int64  `json:"start"`
+	Through int64  `json:"through"`
+	After   string `json:"after,omitempty"`
+}
+
+// parseCopyRange checks --from-event-id and --to-event-id belong to the topic and returns
+// the sequence to copy after and the last sequence to copy (0 for the end of the topic)
+func parseCopyRange(topic, fromID, toID string) (after, through int64, err error) {
+	if fromID != "" {
+		id, err := eventid.Parse(fromID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid --from-event-id: %w", err)
+		}
+		if id.Topic != topic {
+			return 0, 0, fmt.Errorf("--from-event-id '%s' is not an event of topic '%s'", fromID, topic)
+		}
+		after = id.Sequence
+	}
+	if toID != "" {
+		id, err := eventid.Parse(toID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid --to-event-id: %w", err)
+		}
+		if id.Topic != topic {
+			return 0, 0, fmt.Errorf("--to-event-id '%s' is not an event of topic '%s'", toID, topic)
+		}
+		if id.Sequence <= after {
+			return 0, 0, fmt.Errorf("--to-event-id '%s' is not after --from-event-id '%s'", toID, fromID)
+		}
+		through = id.Sequence
+	}
+	return after, through, nil
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(replayCmd)
+	cmd.DisablePager(replayCmd)
+	replayCmd.Flags().StringVar(&replayDestServer, "dest-server", "", "Server to publish to (default: the source server)")
+	replayCmd.Flags().StringVar(&replayFromEventID, "from-event-id", "", "Copy events after this event ID (default: from the start of the topic)")
+	replayCmd.Flags().StringVar(&replayToEventID, "to-event-id", "", "Copy events up to and including this event ID (default: to the end of the topic)")
+	replayCmd.Flags().StringArrayVar(&replayFilters, "filter", nil, "Only copy events matching this filter ('field:value', repeatable; all must match)")
+	replayCmd.Flags().StringArrayVar(&replayRenameTypes, "rename-type", nil, "Rename an event type, format 'old=new' (repeatable)")
+	replayCmd.Flags().StringArrayVar(&replayDropFields, "drop-field", nil, "Remove a payload field (dotted path, repeatable)")
+	replayCmd.Flags().StringVar(&replayTransform, "transform", "", "Rewrite each payload with a jq expression or a Go template ('{{ ... }}')")
+	replayCmd.Flags().IntVar(&replayBatchSize, "batch-size", 100, "Events per publish request")
+	replayCmd.Flags().BoolVar(&replayDryRun, "dry-run", false, "Read and transform the events without publishing them")
+	cmd.AddLimitFlags(replayCmd)
+}
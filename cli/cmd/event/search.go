@@ -0,0 +1,139 @@
+package event
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/search"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchRegex       bool
+	searchIgnoreCase  bool
+	searchFields      []string
+	searchFilters     []string
+	searchFromEventID string
+	searchSince       string
+	searchUntil       string
+	searchLimit       int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <topic> <query>",
+	Short: "Search a topic's event payloads for text",
+	Long: `Search every value in a topic's event payloads for a substring, or a regular
+expression with --regex. Numbers, booleans and nulls are searched as their JSON text.
+
+--fields limits the search to some fields: 'type', 'id', or payload paths such as
+'payload.customer.email' (the 'payload.' prefix is optional, and everything inside an
+object or array field is searched). Array elements are addressed by index, e.g.
+'payload.items.0.sku'.
+
+The topic is read a page at a time and each matching event is printed as soon as it is
+found, with its matching fields and the matches highlighted. Long values are shortened
+to the text around the first match. With -o json each matching event is printed as a
+JSON object per line with its matches; with -o csv there is a row per matching field.
+A count of matching events is written to stderr at the end.
+
+Examples:
+  # Find the events that mention a customer
+  es event search user-events alice@example.com
+
+  # Case-insensitive regular expression over two fields
+  es event search orders --regex --ignore-case 'refund(ed)?' --fields payload.note,payload.reason
+
+  # First 10 matches among yesterday's failed payments, as NDJSON
+  es event search payments 'card_declined' --filter type:payment.failed --since yesterday --until today --limit 10 -o json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topic := args[0]
+
+		matcher, err := search.New(search.Options{
+			Query:      args[1],
+			Regex:      searchRegex,
+			IgnoreCase: searchIgnoreCase,
+			Fields:     searchFields,
+		})
+		if err != nil {
+			return err
+		}
+		if searchLimit < 0 {
+			return fmt.Errorf("limit cannot be negative")
+		}
+		timeRange, err := timerange.Parse(searchSince, searchUntil, time.Now())
+		if err != nil {
+			return err
+		}
+		filters := make([]*filter.Filter, len(searchFilters))
+		for i, expr := range searchFilters {
+			f, err := filter.Parse(expr)
+			if err != nil {
+				return err
+			}
+			filters[i] = f
+		}
+
+		started := time.Now()
+		summary := &search.Summary{Topic: topic}
+		stream := output.NewSearchStream(cfg.Output.Format)
+		query := timeRangeQuery(apiClient, searchFromEventID, timeRange)
+		err = apiClient.ScanEventsQuery(topic, query, func(events []client.Event) (bool, error) {
+			for _, event := range events {
+				if timeRange.Past(event.Timestamp) {
+					return false, nil
+				}
+				summary.Scanned++
+				if !timeRange.Contains(event.Timestamp) || !filter.MatchAll(filters, event) {
+					continue
+				}
+				matches := matcher.Search(event)
+				if len(matches) == 0 {
+					continue
+				}
+				summary.Matched++
+				if cmd.Quiet() {
+					output.PrintIDs([]string{event.ID})
+				} else if err := stream.Write(event, matches); err != nil {
+					return false, err
+				}
+				if searchLimit > 0 && summary.Matched >= searchLimit {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		summary.Seconds = time.Since(started).Seconds()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case cmd.Quiet():
+			return nil
+		case cfg.Output.Format == "json":
+			return output.PrintSearchSummaryJSON(summary)
+		}
+		output.PrintSearchSummary(summary)
+		return nil
+	},
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(searchCmd)
+	searchCmd.Flags().BoolVar(&searchRegex, "regex", false, "Treat the query as a regular expression")
+	searchCmd.Flags().BoolVarP(&searchIgnoreCase, "ignore-case", "i", false, "Match regardless of case")
+	searchCmd.Flags().StringSliceVar(&searchFields, "fields", nil, "Only search these fields: type, id or payload paths (comma-separated; default: the whole payload)")
+	searchCmd.Flags().StringArrayVar(&searchFilters, "filter", nil, "Only search events matching this filter ('field:value', repeatable; all must match)")
+	searchCmd.Flags().StringVar(&searchFromEventID, "from-event-id", "", "Search the events after this event ID")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Only search events at or after this time: timestamp, date, 'today', 'yesterday' or a duration ago such as '2h' or '7d'")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Only search events before this time (same formats as --since)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 0, "Stop after this many matching events (0 = no limit)")
+}
@@ -6,43 +6,80 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/event-store/cli/cmd"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
 	"github.com/event-store/cli/internal/output"
 )
 
+var (
+	showDecrypt bool
+	showKeyFile string
+	showFlatten bool
+	showDepth   int
+)
+
 var showCmd = &cobra.Command{
-	Use:   "show <topic> <event-id>",
+	Use:   "show <topic> <event-id | sequence | latest[~n]>",
 	Short: "Show detailed information about an event",
 	Long: `Show detailed information about a specific event, including the full payload without truncation.
 
+In table output the payload and metadata are shown as indented JSON, highlighted when
+output is to a terminal and NO_COLOR isn't set. --depth collapses objects and arrays
+nested deeper than it to a summary such as {…3 keys}, and --flatten shows a line per
+value, keyed by its dotted path, e.g. 'address.city = "Paris"'.
+
+The event can be given by its ID, by its sequence number in the topic, or relative to the
+topic's latest event: 'latest' is the last event published, and 'latest~n' the event n
+before it.
+
 Examples:
   # Show an event by ID
   es event show user-events user-events-10
 
+  # Show the same event by its sequence number
+  es event show user-events 10
+
+  # Show the last event published to a topic, and the one five before it
+  es event show user-events latest
+  es event show user-events latest~5
+
+  # Show the latest event's payload as dotted keys
+  es event show user-events latest --flatten
+
   # Show an event in JSON format
-  es event show user-events user-events-10 --output json`,
+  es event show user-events user-events-10 --output json
+
+  # Show an event with the fields published with 'es event publish --encrypt' decrypted
+  es event show customers customers-3 --decrypt --key-file keys.json`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
+		keys, err := loadDecryptKeys(showDecrypt, showKeyFile)
+		if err != nil {
+			return err
+		}
 
 		topic := args[0]
-		eventID := args[1]
+		id, err := eventid.Resolve(topic, args[1], func() (int64, error) {
+			topicInfo, err := apiClient.GetTopic(topic)
+			if err != nil {
+				return 0, err
+			}
+			return int64(topicInfo.Sequence), nil
+		})
+		if err != nil {
+			return err
+		}
+		eventID := id.String()
 
-		// Get events starting from the event before the requested one
-		// We'll fetch a small batch and find the specific event
-		query := &client.EventsQuery{
-			Limit: 100, // Fetch a reasonable batch to find the event
+		// Get the event by asking for the one after the event before it
+		query := &client.EventsQuery{Limit: 1}
+		if id.Sequence > 1 {
+			query.SinceEventID = eventid.ID{Topic: id.Topic, Sequence: id.Sequence - 1}.String()
 		}
 
 		events, err := apiClient.GetEvents(topic, query)
 		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
-			}
-			output.PrintError(err)
 			return err
 		}
 
@@ -56,22 +93,12 @@ Examples:
 		}
 
 		if foundEvent == nil {
-			// Try fetching more events or using sinceEventId
-			// Extract sequence from event ID (format: topic-sequence)
-			// For now, let's try a different approach - fetch from the beginning
+			// Servers that don't page by event ID are searched from the beginning,
 			// with a larger limit
-			query.Limit = 10000
+			query = &client.EventsQuery{Limit: 10000}
 			allEvents, err := apiClient.GetEvents(topic, query)
 			if err != nil {
-				err := fmt.Errorf("event '%s' not found in topic '%s'", eventID, topic)
-				if cfg.Output.Format == "json" {
-					return output.PrintErrorJSON(err)
-				}
-				if cfg.Output.Format == "csv" {
-					return output.PrintErrorCSV(err)
-				}
-				output.PrintError(err)
-				return err
+				return fmt.Errorf("event '%s' not found in topic '%s'", eventID, topic)
 			}
 
 			for i := range allEvents {
@@ -82,25 +109,21 @@ Examples:
 			}
 
 			if foundEvent == nil {
-				err := fmt.Errorf("event '%s' not found in topic '%s'", eventID, topic)
-				if cfg.Output.Format == "json" {
-					return output.PrintErrorJSON(err)
-				}
-				if cfg.Output.Format == "csv" {
-					return output.PrintErrorCSV(err)
-				}
-				output.PrintError(err)
-				return err
+				return fmt.Errorf("event '%s' not found in topic '%s'", eventID, topic)
 			}
 		}
 
+		decryptEvent(keys, foundEvent)
+		masked := output.MaskEvent(*foundEvent, cmd.MaskFields())
+		foundEvent = &masked
+
 		switch cfg.Output.Format {
 		case "json":
 			return output.PrintEventDetailsJSON(foundEvent)
 		case "csv":
 			return output.PrintEventDetailsCSV(foundEvent)
 		default:
-			output.PrintEventDetails(foundEvent)
+			output.PrintEventDetails(foundEvent, output.PayloadOptions{Flatten: showFlatten, Depth: showDepth})
 			return nil
 		}
 	},
@@ -108,5 +131,10 @@ Examples:
 
 func init() {
 	cmd.EventCmd().AddCommand(showCmd)
+	cmd.AddMaskFlag(showCmd)
+	showCmd.Flags().BoolVar(&showDecrypt, "decrypt", false, "Decrypt payload fields published with 'es event publish --encrypt'")
+	showCmd.Flags().StringVar(&showKeyFile, "key-file", "", "JSON file of the AES keys to decrypt with")
+	showCmd.Flags().BoolVar(&showFlatten, "flatten", false, "Show the payload and metadata as a line per value, keyed by its dotted path")
+	showCmd.Flags().IntVar(&showDepth, "depth", 0, "Collapse objects and arrays nested deeper than this in the payload and metadata (0 = show all)")
 }
 
@@ -0,0 +1,19 @@
+package event
+
+import (
+	"github.com/event-store/cli/cmd"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync topics into a database to query them with SQL",
+	Long: `Copy the events of topics into a database, a table per event type with a column per
+payload field, and keep it up to date with --follow. The database keeps a checkpoint
+for each topic, written with its events, so a sync carries on where the last one
+stopped.`,
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(syncCmd)
+}
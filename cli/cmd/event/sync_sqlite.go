@@ -0,0 +1,61 @@
+package event
+
+import (
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/eventsync"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncSQLiteDatabase string
+	syncSQLitePrefix   string
+)
+
+var syncSQLiteCmd = &cobra.Command{
+	Use:   "sqlite <topic>... --database <file>",
+	Short: "Sync topics into a local SQLite database",
+	Long: `Copy the events of topics into a SQLite database, creating it if need be, so their
+history can be queried with SQL. Needs the sqlite3 command-line shell on the PATH.
+
+Each event type has a table, named after it with characters other than letters, digits
+and underscores replaced by underscores (order.placed is order_placed), and --prefix
+before it. A row is an event: its _topic, _id, _timestamp and _payload (as JSON), and a
+column per payload field. Fields in the event type's schema get a column typed from it
+when the table is made; other fields get an untyped column when they first appear.
+Nested objects and arrays are stored as JSON, for SQLite's json functions.
+
+The _es_sync table holds the last event synced from each topic, written in the same
+transaction as its events, so each run carries on exactly where the last one stopped.
+With --follow the topics are polled every --interval until stopped. A database belongs
+to the server it was synced from; --reset syncs topics from the start again, replacing
+their rows.
+
+Examples:
+  # Sync a topic, then query it
+  es event sync sqlite orders --database events.db
+  sqlite3 events.db "SELECT customerId, count(*) FROM order_placed GROUP BY customerId"
+
+  # Keep two topics in sync
+  es event sync sqlite orders payments --database events.db --follow`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		target, err := eventsync.OpenSQLite(syncSQLiteDatabase, strings.TrimSuffix(cfg.Server.URL, "/"), syncSQLitePrefix)
+		if err != nil {
+			return err
+		}
+		defer target.Close()
+		return cmd.Sync(target, args)
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncSQLiteCmd)
+	cmd.DisablePager(syncSQLiteCmd)
+	syncSQLiteCmd.Flags().StringVar(&syncSQLiteDatabase, "database", "", "SQLite database file (required)")
+	syncSQLiteCmd.Flags().StringVar(&syncSQLitePrefix, "prefix", "", "Prefix of the tables' names")
+	cmd.AddSyncFlags(syncSQLiteCmd)
+	syncSQLiteCmd.MarkFlagRequired("database")
+}
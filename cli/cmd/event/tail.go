@@ -0,0 +1,239 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/tail"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tailFilters     []string
+	tailInterval    time.Duration
+	tailReorder     time.Duration
+	tailFromEventID string
+	tailUntil       string
+	tailMaxEvents   int
+	tailMaxDuration time.Duration
+	tailFields      []string
+	tailDecrypt     bool
+	tailKeyFile     string
+)
+
+var tailCmd = &cobra.Command{
+	Use:   "tail <topic>...",
+	Short: "Follow topics' new events",
+	Long: `Print a topic's events as they are published, polling every --interval. Following
+starts at the end of the topic unless --from-event-id is given.
+
+Several topics can be followed at once, by name, as a comma-separated list such as
+'orders,payments', or with a glob pattern such as 'order-*' matched against the
+server's topics. Their events are merged into one stream in timestamp order, each
+printed with its topic. An event is held for --reorder-window after it's read, so that
+events of other topics with earlier timestamps, read on a later poll or stamped by a
+clock that's behind, are printed before it; a longer window tolerates more skew, and
+delays every event by as much. --from-event-id starts its own topic there, and the
+others at their end. Each topic's default filter and mask apply to its events.
+
+Tail runs until Ctrl+C, or until a stop condition is met so scripts can capture exactly
+the slice of activity they need:
+  --until         stop after printing an event matching the filter expression
+  --max-events    stop after printing this many events
+  --max-duration  stop after this long
+
+On stopping, a summary (events printed, first and last event ID, duration and what
+stopped the tail) is written to stderr, as JSON with -o json. Events are printed one
+per line: '[timestamp] id type payload', a JSON object per line with -o json, or CSV
+rows with -o csv. --fields prints only some fields, e.g. 'id,payload.orderId'.
+--mask prints fields such as passwords as '***'. --decrypt decrypts fields published
+with 'es event publish --encrypt' before the events are matched and printed.
+
+Examples:
+  # Follow a topic
+  es event tail orders
+
+  # Capture a batch run, then exit
+  es event tail jobs --until 'type=batch.completed' --max-duration 10m -o json > batch.ndjson
+
+  # Print the next 5 failed payments
+  es event tail payments --filter type:payment.failed --max-events 5
+
+  # Follow two topics together, tolerating 5s of clock skew between their producers
+  es event tail orders,payments --reorder-window 5s`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topics, err := cmd.ResolveTopics(apiClient, args)
+		if err != nil {
+			return err
+		}
+		multi := len(args) > 1 || cmd.IsTopicSelector(args[0])
+		// Each of several topics keeps its own default filter and mask
+		topicFilters := map[string][]*filter.Filter{}
+		topicMasks := map[string][]string{}
+		if !multi {
+			if err := cmd.ApplyTopicDefaults(cobraCmd, topics[0]); err != nil {
+				return err
+			}
+		} else {
+			for _, topic := range topics {
+				if expr := cmd.TopicDefaultFilter(cobraCmd, topic); expr != "" {
+					f, err := filter.Parse(expr)
+					if err != nil {
+						return fmt.Errorf("topic '%s': %w", topic, err)
+					}
+					topicFilters[topic] = []*filter.Filter{f}
+				}
+				if mask := cmd.TopicDefaultMask(cobraCmd, topic); mask != nil {
+					if err := output.CheckMaskFields(mask); err != nil {
+						return fmt.Errorf("topic '%s': %w", topic, err)
+					}
+					topicMasks[topic] = mask
+				}
+			}
+		}
+
+		if tailInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		if tailReorder < 0 {
+			return fmt.Errorf("--reorder-window can't be negative")
+		}
+		filters := make([]*filter.Filter, len(tailFilters))
+		for i, expr := range tailFilters {
+			f, err := filter.Parse(expr)
+			if err != nil {
+				return err
+			}
+			filters[i] = f
+		}
+		columns, err := output.ParseEventColumns(tailFields)
+		if err != nil {
+			return err
+		}
+		var until []*filter.Filter
+		if tailUntil != "" {
+			if until, err = filter.ParseAll(tailUntil); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+		}
+
+		keys, err := loadDecryptKeys(tailDecrypt, tailKeyFile)
+		if err != nil {
+			return err
+		}
+		var decode func(*client.Event)
+		if keys != nil {
+			decode = func(event *client.Event) { decryptEvent(keys, event) }
+		}
+
+		since := map[string]string{}
+		if tailFromEventID != "" {
+			from := topics[0]
+			if multi {
+				id, err := eventid.Parse(tailFromEventID)
+				if err != nil {
+					return fmt.Errorf("invalid --from-event-id: %w", err)
+				}
+				if !slices.Contains(topics, id.Topic) {
+					return fmt.Errorf("--from-event-id '%s' is not an event of the topics tailed", tailFromEventID)
+				}
+				from = id.Topic
+			}
+			since[from] = tailFromEventID
+		}
+		for _, topic := range topics {
+			if _, ok := since[topic]; ok {
+				continue
+			}
+			topicInfo, err := apiClient.RefreshTopic(topic)
+			if err != nil {
+				return err
+			}
+			if topicInfo.Sequence > 0 {
+				since[topic] = eventid.ID{Topic: topic, Sequence: int64(topicInfo.Sequence)}.String()
+			}
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		format := cfg.Output.Format
+		if cmd.Quiet() {
+			format, columns = "table", []output.EventColumn{{Field: "id"}}
+		}
+		stream := output.NewEventStream(format, columns)
+		if multi && !cmd.Quiet() {
+			stream.WithTopic()
+		}
+		var summary *tail.Summary
+		var tailErr error
+		group.Go("event-tail", func(ctx context.Context) error {
+			summary, tailErr = tail.Follow(ctx, apiClient, tail.Options{
+				Topics:        topics,
+				Since:         since,
+				Interval:      tailInterval,
+				Filters:       filters,
+				TopicFilters:  topicFilters,
+				Decode:        decode,
+				ReorderWindow: tailReorder,
+				Until:         until,
+				MaxEvents:     tailMaxEvents,
+				MaxDuration:   tailMaxDuration,
+			}, func(event client.Event) error {
+				mask, ok := topicMasks[output.TopicOf(event)]
+				if !ok {
+					mask = cmd.MaskFields()
+				}
+				return stream.Write(output.MaskEvent(event, mask))
+			}, func(err error) {
+				fmt.Fprintf(os.Stderr, "[%s] %v\n", time.Now().Format(time.RFC3339), err)
+			})
+			group.Stop()
+			return nil
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+		if tailErr != nil {
+			return tailErr
+		}
+
+		switch {
+		case cmd.Quiet():
+			return nil
+		case cfg.Output.Format == "json":
+			return output.PrintTailSummaryJSON(summary)
+		}
+		output.PrintTailSummary(summary)
+		return nil
+	},
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(tailCmd)
+	cmd.DisablePager(tailCmd)
+	tailCmd.Flags().StringArrayVar(&tailFilters, "filter", nil, "Only print events matching this filter ('field:value', repeatable; all must match)")
+	tailCmd.Flags().DurationVar(&tailInterval, "interval", time.Second, "How often to poll for new events")
+	tailCmd.Flags().DurationVar(&tailReorder, "reorder-window", 2*time.Second, "With several topics, how long to hold each event so events of other topics with earlier timestamps are printed first")
+	tailCmd.Flags().StringVar(&tailFromEventID, "from-event-id", "", "Follow events after this event ID (default: only events published from now on)")
+	tailCmd.Flags().StringVar(&tailUntil, "until", "", "Stop after printing an event matching this expression, e.g. 'type=batch.completed'")
+	tailCmd.Flags().IntVar(&tailMaxEvents, "max-events", 0, "Stop after printing this many events (0 = no limit)")
+	tailCmd.Flags().StringSliceVar(&tailFields, "fields", nil, "Only print these fields: topic, id, timestamp, type, partition, payload, metadata, payload.<path> or metadata.<path> (comma-separated)")
+	cmd.AddMaskFlag(tailCmd)
+	tailCmd.Flags().BoolVar(&tailDecrypt, "decrypt", false, "Decrypt payload fields published with 'es event publish --encrypt'")
+	tailCmd.Flags().StringVar(&tailKeyFile, "key-file", "", "JSON file of the AES keys to decrypt with")
+	tailCmd.Flags().DurationVar(&tailMaxDuration, "max-duration", 0, "Stop after this long (0 = no limit)")
+}
@@ -0,0 +1,116 @@
+package event
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/event-store/cli/internal/trace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	traceTopics []string
+	traceSince  string
+	traceUntil  string
+)
+
+var traceCmd = &cobra.Command{
+	Use:   "trace <correlation-id>",
+	Short: "Show the events of a flow as a tree of what caused what",
+	Long: `Find the events of every topic, or of the topics given with --topic, that share a
+correlation ID, and print them as a tree: each event below the event its causation ID
+names, oldest first. Events whose cause isn't among them are shown at the top level,
+with the causation ID that wasn't found.
+
+Correlation and causation IDs are set when publishing ('es event publish
+--correlation-id --causation-id'), and need a server that records them. Topics are read
+in full unless --since and --until narrow the search.
+
+With -o json the tree is printed as nested objects, each event's 'caused' events inside
+it; with -o csv there is a row per event in tree order, with its depth.
+
+Examples:
+  # Trace a checkout flow across all topics
+  es event trace 3f2b9c1e-8d4a-4b6e-9f0a-1c2d3e4f5a6b
+
+  # Only look in two topics, at today's events
+  es event trace checkout-7f3a --topic orders --topic payments --since today`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		correlationID := args[0]
+
+		if info, err := apiClient.GetServerInfo(); err == nil && info.Reported && !info.Supports(client.FeatureEventCorrelation) {
+			return fmt.Errorf("the event store does not record correlation IDs (feature '%s')", client.FeatureEventCorrelation)
+		}
+		timeRange, err := timerange.Parse(traceSince, traceUntil, time.Now())
+		if err != nil {
+			return err
+		}
+
+		topics := traceTopics
+		if len(topics) == 0 {
+			all, err := apiClient.GetTopics()
+			if err != nil {
+				return err
+			}
+			for _, topic := range all {
+				topics = append(topics, topic.Name)
+			}
+		}
+
+		flow := trace.New(correlationID)
+		for _, topic := range topics {
+			query := timeRangeQuery(apiClient, "", timeRange)
+			if apiClient.Supports(client.FeatureEventFilter) {
+				query.Filter = "correlationId:" + correlationID
+			}
+			err := apiClient.ScanEventsQuery(topic, query, func(events []client.Event) (bool, error) {
+				for _, event := range events {
+					if timeRange.Past(event.Timestamp) {
+						return false, nil
+					}
+					if timeRange.Contains(event.Timestamp) {
+						flow.Add(topic, event)
+					}
+				}
+				return true, nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to read topic '%s': %w", topic, err)
+			}
+		}
+		roots := flow.Roots()
+
+		if cmd.Quiet() {
+			trace.Walk(roots, func(node *trace.Node, last []bool) {
+				output.PrintIDs([]string{node.Event.ID})
+			})
+			return nil
+		}
+		switch cfg.Output.Format {
+		case "json":
+			if roots == nil {
+				roots = []*trace.Node{}
+			}
+			return output.PrintJSON(map[string]interface{}{"correlationId": correlationID, "events": roots})
+		case "csv":
+			return output.PrintTraceCSV(roots)
+		default:
+			output.PrintTrace(roots)
+			return nil
+		}
+	},
+}
+
+func init() {
+	cmd.EventCmd().AddCommand(traceCmd)
+	traceCmd.Flags().StringArrayVar(&traceTopics, "topic", nil, "Only look in this topic (repeatable; default: all topics)")
+	traceCmd.Flags().StringVar(&traceSince, "since", "", "Only look at events at or after this time: timestamp, date, 'today', 'yesterday' or a duration ago such as '2h' or '7d'")
+	traceCmd.Flags().StringVar(&traceUntil, "until", "", "Only look at events before this time (same formats as --since)")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/event-store/cli/internal/gateway"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gatewayPort         int
+	gatewayConnect      bool
+	gatewayAllowOrigin  string
+	gatewayPollInterval time.Duration
+	gatewaySilent       bool
+)
+
+// gatewayCmd represents the gateway command
+var gatewayCmd = &cobra.Command{
+	Use:   "gateway",
+	Short: "Serve the event store API to browser tools",
+	Long: `Start a thin gateway that exposes the event store API over the Connect, gRPC and
+gRPC-Web protocols, including a server-streaming TailEvents procedure.
+
+The service is described by proto/eventstore/v1/eventstore.proto; browser tools can
+generate a client from it with @connectrpc/connect-web.
+
+Examples:
+  # Serve the Connect API on the default port
+  es gateway --connect
+
+  # Allow a local admin UI to call the gateway
+  es gateway --connect --port 8081 --allow-origin http://localhost:8000`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if !gatewayConnect {
+			return fmt.Errorf("no protocol enabled (use --connect)")
+		}
+		if gatewayPollInterval <= 0 {
+			return fmt.Errorf("poll interval must be positive")
+		}
+
+		handler := gateway.New(NewClient(), gatewayPollInterval, gatewayAllowOrigin)
+
+		// gRPC needs HTTP/2, which clients speak without TLS to a local gateway
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		server := &http.Server{
+			Addr:      fmt.Sprintf(":%d", gatewayPort),
+			Handler:   handler,
+			Protocols: protocols,
+		}
+
+		group, err := NewRunner()
+		if err != nil {
+			return err
+		}
+		group.Serve("gateway-server", server)
+
+		if !gatewaySilent {
+			fmt.Printf("Connect gateway listening on port %d\n", gatewayPort)
+			for _, procedure := range handler.Procedures() {
+				fmt.Printf("  POST %s\n", procedure)
+			}
+			fmt.Println("Press Ctrl+C to stop")
+			fmt.Println()
+		}
+
+		if err := group.Wait(); err != nil {
+			return fmt.Errorf("server error: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gatewayCmd)
+	DisablePager(gatewayCmd)
+	gatewayCmd.Flags().IntVarP(&gatewayPort, "port", "p", 19200, "Port to listen on")
+	gatewayCmd.Flags().BoolVar(&gatewayConnect, "connect", false, "Serve the API over the Connect, gRPC and gRPC-Web protocols")
+	gatewayCmd.Flags().StringVar(&gatewayAllowOrigin, "allow-origin", "", "Allowed CORS origin for browser clients (e.g. '*')")
+	gatewayCmd.Flags().DurationVar(&gatewayPollInterval, "poll-interval", time.Second, "How often TailEvents polls for new events")
+	gatewayCmd.Flags().BoolVar(&gatewaySilent, "silent", false, "Suppress output to stdout")
+}
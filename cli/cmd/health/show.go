@@ -1,29 +1,62 @@
 package health
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/event-store/cli/cmd"
 	"github.com/event-store/cli/internal/client"
 	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/watch"
 	"github.com/spf13/cobra"
 )
 
+// healthColumns are the columns of 'es health show --all-contexts', after the context
+var healthColumns = []output.Column{
+	{Name: "status", Header: "Status"},
+	{Name: "consumers", Header: "Consumers", Numeric: true},
+	{Name: "dispatchers", Header: "Running Dispatchers"},
+}
+
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show health status",
-	Long:  `Show the current health status of the event store server.`,
+	Long: `Show the current health status of the event store server.
+
+Examples:
+  # Watch the health status and dispatchers change
+  es health show --watch --interval 5s
+
+  # Health of every configured context, e.g. dev, staging and prod
+  es health show --all-contexts`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
+
+		if cmd.AllContexts() {
+			return showAllContexts(cfg.Output.Format)
+		}
+
+		if cmd.Watching() {
+			return cmd.Watch(cobraCmd, []string{"Status", "Consumers", "Running Dispatchers"}, func() ([]watch.Row, error) {
+				health, err := apiClient.GetHealth()
+				if err != nil {
+					return nil, err
+				}
+				dispatchers := "None"
+				if len(health.RunningDispatchers) > 0 {
+					dispatchers = strings.Join(health.RunningDispatchers, ", ")
+				}
+				return []watch.Row{{
+					Key:    "health",
+					Cells:  []string{health.Status, strconv.Itoa(health.Consumers), dispatchers},
+					Object: health,
+				}}, nil
+			})
+		}
 
 		health, err := apiClient.GetHealth()
 		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
-			}
-			output.PrintError(err)
 			return err
 		}
 
@@ -39,6 +72,48 @@ var showCmd = &cobra.Command{
 	},
 }
 
+// showAllContexts shows the health of every configured context in one table, with an error
+// status for those that can't be queried
+func showAllContexts(format string) error {
+	results := cmd.QueryContexts(func(apiClient *client.Client) (interface{}, error) {
+		return apiClient.GetHealth()
+	})
+
+	contexts := make([]string, len(results))
+	listings := make([]*output.Listing, len(results))
+	for i, result := range results {
+		contexts[i] = result.Context
+		listings[i] = output.NewListing(healthColumns)
+		if result.Err != nil {
+			listings[i].Add("health", map[string]string{"status": "error", "error": result.Err.Error()}, "error", "", "")
+			continue
+		}
+		health := result.Value.(*client.Health)
+		dispatchers := "None"
+		if len(health.RunningDispatchers) > 0 {
+			dispatchers = strings.Join(health.RunningDispatchers, ", ")
+		}
+		listings[i].Add("health", health, health.Status, strconv.Itoa(health.Consumers), dispatchers)
+	}
+	listing := output.CombineListings(healthColumns, contexts, listings)
+
+	var err error
+	switch format {
+	case "json":
+		err = output.PrintListingJSON("health", listing)
+	case "csv":
+		err = output.PrintListingCSV(listing)
+	default:
+		output.PrintListing(listing)
+	}
+	if err != nil {
+		return err
+	}
+	return cmd.ContextsError(results)
+}
+
 func init() {
 	cmd.HealthCmd().AddCommand(showCmd)
+	cmd.AddWatchFlags(showCmd)
+	cmd.AddAllContextsFlag(showCmd)
 }
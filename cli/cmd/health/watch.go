@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/monitor"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval       time.Duration
+	watchCount          int
+	watchMinDispatchers int
+	watchOnDegraded     string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Monitor health and alert when it degrades",
+	Long: `Poll the event store's health on an interval and print each status transition with
+a timestamp. The event store counts as degraded when its status is not 'healthy', when
+it cannot be reached, when the number of running dispatchers drops, or when it falls
+below --min-dispatchers.
+
+Without --on-degraded the command exits with status 1 as soon as the event store is
+degraded. With --on-degraded the command is run through 'sh -c' each time the event
+store becomes degraded, and watching continues. The hook receives ES_HEALTH_STATUS,
+ES_HEALTH_REASON, ES_HEALTH_CONSUMERS, ES_HEALTH_DISPATCHERS and ES_SERVER_URL.
+
+Examples:
+  # Watch until something goes wrong
+  es health watch
+
+  # Single check for cron: exits 1 if degraded
+  es health watch --count 1 --min-dispatchers 2
+
+  # Alert through a script and keep watching
+  es health watch --interval 30s --on-degraded './notify.sh "$ES_HEALTH_REASON"'`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		if watchInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		healthMonitor := monitor.NewHealthMonitor(monitor.HealthThresholds{MinDispatchers: watchMinDispatchers})
+		wasDegraded := false
+		checks := 0
+		first := true
+		var degradedErr error
+
+		group.Go("health-watch", func(ctx context.Context) error {
+			return watch.Loop(ctx, watchInterval, func() error {
+				health, err := apiClient.GetHealth()
+				check, transition := healthMonitor.Observe(time.Now().Truncate(time.Second), health, err)
+
+				if transition {
+					var printErr error
+					switch cfg.Output.Format {
+					case "json":
+						printErr = output.PrintHealthCheckJSON(check)
+					case "csv":
+						printErr = output.PrintHealthCheckCSV(check, first)
+					default:
+						output.PrintHealthCheck(check)
+					}
+					first = false
+					if printErr != nil {
+						return printErr
+					}
+				}
+
+				if check.Degraded && !wasDegraded {
+					if watchOnDegraded == "" {
+						degradedErr = fmt.Errorf("event store is degraded: %s", check.Reason)
+						group.Stop()
+						return nil
+					}
+					runDegradedHook(cfg.Server.URL, check)
+				}
+				wasDegraded = check.Degraded
+
+				checks++
+				if watchCount > 0 && checks >= watchCount {
+					group.Stop()
+				}
+				return nil
+			})
+		})
+
+		if err := group.Wait(); err != nil {
+			return err
+		}
+		return degradedErr
+	},
+}
+
+// runDegradedHook runs the --on-degraded command, reporting but not failing on errors
+func runDegradedHook(serverURL string, check monitor.HealthCheck) {
+	hook := exec.Command("sh", "-c", watchOnDegraded)
+	hook.Stdout = os.Stderr
+	hook.Stderr = os.Stderr
+	hook.Env = append(os.Environ(),
+		"ES_HEALTH_STATUS="+check.Status,
+		"ES_HEALTH_REASON="+check.Reason,
+		"ES_HEALTH_CONSUMERS="+strconv.Itoa(check.Consumers),
+		"ES_HEALTH_DISPATCHERS="+strconv.Itoa(check.Dispatchers),
+		"ES_SERVER_URL="+serverURL,
+	)
+	if err := hook.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: --on-degraded command failed: %v\n", err)
+	}
+}
+
+func init() {
+	cmd.HealthCmd().AddCommand(watchCmd)
+	cmd.DisablePager(watchCmd)
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 10*time.Second, "How often to poll health")
+	watchCmd.Flags().IntVar(&watchCount, "count", 0, "Stop after this many checks (0 = until Ctrl+C)")
+	watchCmd.Flags().IntVar(&watchMinDispatchers, "min-dispatchers", 0, "Treat fewer running dispatchers than this as degraded")
+	watchCmd.Flags().StringVar(&watchOnDegraded, "on-degraded", "", "Command to run through 'sh -c' when the event store becomes degraded, instead of exiting")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/event-store/cli/internal/hooks"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// invocation is the running command as its hooks see it, set once its before hooks
+// have let it run
+var invocation *hooks.Invocation
+
+// runBeforeHooks runs the configured before hooks of a command, returning an error to
+// refuse the command if one fails
+func runBeforeHooks(c *cobra.Command, args []string) error {
+	if len(cfg.Hooks) == 0 {
+		return nil
+	}
+	if err := hooks.Validate(cfg.Hooks); err != nil {
+		return err
+	}
+	inv := hooks.Invocation{
+		Hook:    hooks.Before,
+		Command: strings.TrimPrefix(c.CommandPath(), c.Root().Name()+" "),
+		Args:    args,
+		Flags:   map[string]string{},
+		Server:  strings.TrimSuffix(cfg.Server.URL, "/"),
+		Output:  cfg.Output.Format,
+	}
+	if inv.Args == nil {
+		inv.Args = []string{}
+	}
+	c.Flags().Visit(func(f *pflag.Flag) {
+		value := f.Value.String()
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			value = strings.Join(slice.GetSlice(), ",")
+		}
+		inv.Flags[f.Name] = hooks.Redact(f.Name, value)
+	})
+	if err := hooks.Run(cfg.Hooks, inv); err != nil {
+		return fmt.Errorf("%w: %v", ErrAborted, err)
+	}
+	invocation = &inv
+	return nil
+}
+
+// runAfterHooks runs the configured after hooks of a command that ran, with its exit
+// code and error
+func runAfterHooks(err error, code int) {
+	if invocation == nil {
+		return
+	}
+	inv := *invocation
+	inv.Hook = hooks.After
+	inv.ExitCode = &code
+	if err != nil {
+		inv.Error = err.Error()
+	}
+	hooks.Run(cfg.Hooks, inv)
+}
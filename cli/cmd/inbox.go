@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/inbox"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inboxPort      int
+	inboxRoutes    []string
+	inboxSecretEnv []string
+	inboxSilent    bool
+	inboxInsecure  bool
+)
+
+// inboxCmd represents the inbox command
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Receive third-party webhooks and publish them as events",
+	Long: `Start an HTTP server that accepts webhooks from third-party services (GitHub, Stripe, ...),
+verifies their signatures and publishes each one as an event to a configured topic.
+
+Routes are given as '/path=provider:topic', each with a path of its own. Signing
+secrets are read from environment variables named with --secret-env 'provider=ENV_VAR'.
+Every route's provider needs a secret unless --insecure is given, when routes without
+one accept unsigned webhooks. Bodies larger than 10 MiB are rejected with 413.
+
+Providers:
+  github   X-Hub-Signature-256, event type 'github.<X-GitHub-Event>'
+  stripe   Stripe-Signature, event type 'stripe.<payload type>'
+  generic  X-Signature (hex HMAC-SHA256), event type from X-Event-Type or 'webhook.received'
+
+Examples:
+  # Publish GitHub webhooks to the github-events topic
+  es inbox --route /github=github:github-events --secret-env github=GITHUB_WEBHOOK_SECRET
+
+  # Accept both GitHub and Stripe webhooks on port 8080
+  es inbox --port 8080 \
+    --route /github=github:github-events \
+    --route /stripe=stripe:payments \
+    --secret-env github=GITHUB_WEBHOOK_SECRET \
+    --secret-env stripe=STRIPE_WEBHOOK_SECRET`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if len(inboxRoutes) == 0 {
+			return fmt.Errorf("at least one route is required (use --route)")
+		}
+
+		secrets := make(map[string]string)
+		for _, spec := range inboxSecretEnv {
+			parts := strings.SplitN(spec, "=", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid secret mapping: %s (expected 'provider=ENV_VAR')", spec)
+			}
+			secret := os.Getenv(parts[1])
+			if secret == "" {
+				return fmt.Errorf("environment variable %s is not set", parts[1])
+			}
+			secrets[parts[0]] = secret
+		}
+
+		routes := make([]*inbox.Route, 0, len(inboxRoutes))
+		for _, spec := range inboxRoutes {
+			route, err := inbox.ParseRoute(spec)
+			if err != nil {
+				return err
+			}
+			route.Secret = secrets[route.Provider.Name()]
+			if route.Secret == "" && !inboxInsecure {
+				return fmt.Errorf("route %s has no signing secret (give one with --secret-env %s=ENV_VAR, or accept unsigned webhooks with --insecure)", route.Path, route.Provider.Name())
+			}
+			routes = append(routes, route)
+		}
+
+		handler, err := inbox.NewHandler(routes, NewClient(), func(result inbox.Result) {
+			if inboxSilent {
+				return
+			}
+			timestamp := time.Now().Format(time.RFC3339)
+			if result.Err != nil {
+				fmt.Fprintf(os.Stderr, "[%s] %s rejected: %v\n", timestamp, result.Route.Path, result.Err)
+				return
+			}
+			fmt.Printf("[%s] %s -> %s (%s): %s\n", timestamp, result.Route.Path, result.Route.Topic, result.EventType, strings.Join(result.EventIDs, ", "))
+		})
+		if err != nil {
+			return err
+		}
+
+		server := &http.Server{
+			Addr:    fmt.Sprintf(":%d", inboxPort),
+			Handler: handler,
+		}
+
+		group, err := NewRunner()
+		if err != nil {
+			return err
+		}
+		group.Serve("inbox-server", server)
+
+		for _, route := range routes {
+			if route.Secret == "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s accepts unsigned webhooks, which anyone can send\n", route.Path)
+			}
+		}
+		if !inboxSilent {
+			fmt.Printf("Inbox listening on port %d\n", inboxPort)
+			for _, route := range routes {
+				signed := "signed"
+				if route.Secret == "" {
+					signed = "UNSIGNED"
+				}
+				fmt.Printf("  POST %s -> %s (%s, %s)\n", route.Path, route.Topic, route.Provider.Name(), signed)
+			}
+			fmt.Println("Press Ctrl+C to stop")
+			fmt.Println()
+		}
+
+		if err := group.Wait(); err != nil {
+			return fmt.Errorf("server error: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inboxCmd)
+	DisablePager(inboxCmd)
+	inboxCmd.Flags().IntVarP(&inboxPort, "port", "p", 19100, "Port to listen on")
+	inboxCmd.Flags().StringArrayVar(&inboxRoutes, "route", nil, "Route in format '/path=provider:topic' (repeatable)")
+	inboxCmd.Flags().StringArrayVar(&inboxSecretEnv, "secret-env", nil, "Signing secret source in format 'provider=ENV_VAR' (repeatable)")
+	inboxCmd.Flags().BoolVar(&inboxSilent, "silent", false, "Suppress output to stdout")
+	inboxCmd.Flags().BoolVar(&inboxInsecure, "insecure", false, "Accept unsigned webhooks on routes whose provider has no secret")
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/ingest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ingestPort   int
+	ingestRules  string
+	ingestSilent bool
+)
+
+// ingestCmd represents the ingest command
+var ingestCmd = &cobra.Command{
+	Use:   "ingest --rules <file>",
+	Short: "Publish inbound webhooks as events by configurable rules",
+	Long: `Start an HTTP server that accepts webhooks from any service and publishes them as
+events, mapped by the rules in a YAML file: the request path picks the topic, the event
+type comes from a body field, a header or the rule, and the payload can be rewritten
+with a jq expression or Go template (see 'es event replay --transform').
+
+Rules file:
+  rules:
+    - name: github pull requests
+      path: /github               # request path
+      topic: pull-requests        # topic events are published to
+      when: action=opened         # optional filter on body fields, as for --filter
+      type-header: X-GitHub-Event # or type-path: a dotted body field
+      type-prefix: github.        # prepended to a type-path or type-header value
+      type: github.unknown        # the type, or the fallback when the above is missing
+      payload: '{"number": {{json .number}}, "title": {{json .pull_request.title}}}'
+      provider: github            # verify signatures as 'es inbox' does
+      secret-env: GITHUB_WEBHOOK_SECRET
+
+The rules of a path are tried in order and the first whose 'when' matches is used;
+webhooks no rule matches are answered with status "ignored" and not published. Rules
+for one path must verify signatures the same way. JSON and form-encoded bodies are
+accepted. Webhooks failing verification are rejected with 401, events the event store
+rejects (for example against the topic's schema) with 422, and other publish failures
+with 502.
+
+Examples:
+  # Serve the rules in ingest.yaml
+  es ingest --rules ingest.yaml
+
+  # On port 8080, without progress output
+  es ingest --rules ingest.yaml --port 8080 --silent`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		file, err := ingest.Load(ingestRules)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		handler := ingest.NewHandler(file, NewClient(), func(result ingest.Result) {
+			if ingestSilent {
+				return
+			}
+			timestamp := time.Now().Format(time.RFC3339)
+			switch {
+			case result.Err != nil:
+				fmt.Fprintf(os.Stderr, "[%s] %s rejected: %v\n", timestamp, result.Path, result.Err)
+			case result.Ignored != "":
+				fmt.Printf("[%s] %s ignored: %s\n", timestamp, result.Path, result.Ignored)
+			default:
+				fmt.Printf("[%s] %s -> %s (%s, %s): %s\n", timestamp, result.Path, result.Rule.Topic, result.Rule.Name, result.EventType, strings.Join(result.EventIDs, ", "))
+			}
+		})
+
+		server := &http.Server{
+			Addr:    fmt.Sprintf(":%d", ingestPort),
+			Handler: handler,
+		}
+
+		group, err := NewRunner()
+		if err != nil {
+			return err
+		}
+		group.Serve("ingest-server", server)
+
+		if !ingestSilent {
+			fmt.Printf("Ingest gateway listening on port %d\n", ingestPort)
+			for _, rule := range file.Rules {
+				signed := "signed"
+				if !rule.Signed() {
+					signed = "UNSIGNED"
+				}
+				fmt.Printf("  POST %s -> %s (%s, %s)\n", rule.Path, rule.Topic, rule.Name, signed)
+			}
+			fmt.Println("Press Ctrl+C to stop")
+			fmt.Println()
+		}
+
+		if err := group.Wait(); err != nil {
+			return fmt.Errorf("server error: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ingestCmd)
+	DisablePager(ingestCmd)
+	ingestCmd.Flags().IntVarP(&ingestPort, "port", "p", 19400, "Port to listen on")
+	ingestCmd.Flags().StringVar(&ingestRules, "rules", "", "YAML file of rules mapping webhooks to events (required)")
+	ingestCmd.Flags().BoolVar(&ingestSilent, "silent", false, "Suppress output to stdout")
+	ingestCmd.MarkFlagRequired("rules")
+}
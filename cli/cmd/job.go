@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// jobCmd represents the job command
+var jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Manage resumable long-running commands",
+	Long:  `List, resume and remove the jobs kept in ~/.es/jobs by long-running commands (event export, publish and replay of large inputs, event redact and mirror), so one stopped by a crash or Ctrl+C can continue from its last checkpoint.`,
+}
+
+// JobCmd returns the job command for use in subcommands
+func JobCmd() *cobra.Command {
+	return jobCmd
+}
+
+func init() {
+	rootCmd.AddCommand(jobCmd)
+}
@@ -0,0 +1,63 @@
+package job
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// listColumns are the columns of the list of jobs
+var listColumns = []output.Column{
+	{Name: "id", Header: "Job ID"},
+	{Name: "kind", Header: "Kind"},
+	{Name: "status", Header: "Status"},
+	{Name: "progress", Header: "Progress", Numeric: true},
+	{Name: "updated", Header: "Updated"},
+	{Name: "command", Header: "Command"},
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List running and interrupted jobs",
+	Long: `List the jobs of long-running commands that haven't completed, most recent first.
+
+A job is 'running' while its command runs, 'interrupted' once it was stopped by Ctrl+C
+or its process ended without finishing, such as in a crash, and 'failed' when its command
+stopped on an error. Interrupted and failed jobs can be continued with 'es job resume'.
+Completed jobs are removed.
+
+Examples:
+  # List jobs
+  es job list
+
+  # The IDs of jobs to resume
+  es job list -o json | jq -r '.jobs[] | select(.status != "running") | .id'`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		list, err := jobs.List()
+		if err != nil {
+			return err
+		}
+		listing := output.NewListing(listColumns)
+		for _, job := range list {
+			progress := strconv.Itoa(job.Progress)
+			if job.Total > 0 {
+				progress += "/" + strconv.Itoa(job.Total)
+			}
+			status := job.Status
+			if job.Error != "" {
+				status += ": " + job.Error
+			}
+			listing.Add(job.ID, job, job.ID, job.Kind, status, progress, job.Updated.Local().Format(time.RFC3339), job.Command())
+		}
+		return cmd.PrintListing("jobs", listing)
+	},
+}
+
+func init() {
+	cmd.JobCmd().AddCommand(listCmd)
+}
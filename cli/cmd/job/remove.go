@@ -0,0 +1,43 @@
+package job
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/spf13/cobra"
+)
+
+var removeCmd = &cobra.Command{
+	Use:   "remove <job-id>...",
+	Short: "Forget interrupted or failed jobs",
+	Long: `Remove jobs that won't be resumed. What their commands already did, such as a partial
+export file, is left as it is. Job IDs are listed by 'es job list'.
+
+Examples:
+  # Give up on an export
+  es job remove export-3f2a9c1d`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		for _, id := range args {
+			job, err := jobs.Load(id)
+			if err != nil {
+				return err
+			}
+			if job.Status == jobs.Running {
+				return fmt.Errorf("job '%s' is still running (pid %d)", id, job.PID)
+			}
+			if err := jobs.Remove(id); err != nil {
+				return err
+			}
+			if !cmd.Quiet() {
+				fmt.Printf("Removed job %s\n", id)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.JobCmd().AddCommand(removeCmd)
+}
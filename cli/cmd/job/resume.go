@@ -0,0 +1,43 @@
+package job
+
+import (
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <job-id>",
+	Short: "Continue an interrupted or failed job",
+	Long: `Run an interrupted or failed job's command again, with the same flags and arguments and
+in the directory it was started in, continuing from the job's last checkpoint:
+
+  - event export continues an NDJSON file after the last event written; Avro and
+    Parquet files are written again from the start
+  - event publish skips the events of the input already published, a chunk of 10000
+    events at a time, so events of the chunk that was being published are published
+    again unless --dedupe or idempotency keys let the server skip them
+  - event replay continues after the last source event copied
+  - event redact continues after the last batch of events redacted
+  - mirror continues from its --checkpoint file, as it always does
+
+Job IDs are listed by 'es job list'. A job that is still running can't be resumed.
+
+Examples:
+  # Continue an export stopped by Ctrl+C
+  es job resume export-3f2a9c1d`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		job, err := jobs.Load(args[0])
+		if err != nil {
+			return err
+		}
+		return cmd.ResumeJob(job)
+	},
+}
+
+func init() {
+	cmd.JobCmd().AddCommand(resumeCmd)
+	// The job's command may stream progress or prompt for confirmation
+	cmd.DisablePager(resumeCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/internal/jobs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// commandLine is the running command's line, without the program name, as a job it
+// starts is run again by 'es job resume'
+var commandLine []string
+
+// resumingJob is the job 'es job resume' is running again, until its command starts it
+var resumingJob *jobs.Job
+
+// recordCommandLine records the command line of a command about to run: its command
+// path, the flags given and its arguments
+func recordCommandLine(c *cobra.Command, args []string) {
+	line := strings.Fields(c.CommandPath())[1:]
+	c.Flags().Visit(func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			for _, value := range slice.GetSlice() {
+				line = append(line, "--"+f.Name+"="+value)
+			}
+			return
+		}
+		line = append(line, "--"+f.Name+"="+f.Value.String())
+	})
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			line = append(line, "--")
+			break
+		}
+	}
+	commandLine = append(line, args...)
+}
+
+// StartJob records the running command as a job of a kind in ~/.es/jobs, of total events
+// if known, so it can be resumed after a crash or Ctrl+C. The command saves its progress
+// with the job's Save, and ends it with Finish. When 'es job resume' is running the
+// command again, its job is returned instead, with its checkpoint read into checkpoint
+// and the total it started with, and resumed is true.
+func StartJob(kind string, total int, checkpoint interface{}) (job *jobs.Job, resumed bool, err error) {
+	if job := resumingJob; job != nil {
+		resumingJob = nil
+		if job.Kind != kind {
+			return nil, false, fmt.Errorf("job '%s' is not a %s job", job.ID, kind)
+		}
+		if err := job.Resume(checkpoint); err != nil {
+			return nil, false, err
+		}
+		return job, true, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, false, err
+	}
+	job, err = jobs.New(kind, commandLine, dir, strings.TrimSuffix(cfg.Server.URL, "/"), total)
+	if err != nil {
+		return nil, false, err
+	}
+	// Shown with the progress bar, so the job can be found if the command is interrupted
+	if !quiet && term.IsTerminal(int(os.Stderr.Fd())) {
+		fmt.Fprintf(os.Stderr, "Job %s (if interrupted, continue it with 'es job resume %s')\n", job.ID, job.ID)
+	}
+	return job, false, nil
+}
+
+// ResumeJob runs an interrupted or failed job's command again, in the directory it was
+// started in, so that it continues from its last checkpoint
+func ResumeJob(job *jobs.Job) error {
+	if job.Status == jobs.Running {
+		return fmt.Errorf("job '%s' is still running (pid %d)", job.ID, job.PID)
+	}
+	if job.Dir != "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(job.Dir); err != nil {
+			return fmt.Errorf("can't resume job '%s' in its directory: %w", job.ID, err)
+		}
+		defer os.Chdir(dir)
+	}
+
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Resuming job %s: %s\n", job.ID, job.Command())
+	}
+	resumingJob = job
+	defer func() { resumingJob = nil }()
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(job.Args)
+	if run() != 0 {
+		return CheckFailed(fmt.Errorf("job '%s' did not complete", job.ID))
+	}
+	return nil
+}
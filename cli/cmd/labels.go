@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"github.com/event-store/cli/internal/annotations"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/labels"
+	"github.com/spf13/cobra"
+)
+
+var labelSelector string
+
+// AddSelectorFlag adds --selector to a command listing topics or consumers
+func AddSelectorFlag(c *cobra.Command) {
+	c.Flags().StringVarP(&labelSelector, "selector", "l", "", "Only list those whose labels match, e.g. 'team=payments,env=prod' ('key!=value', 'key' and '!key' too)")
+}
+
+// AddLabelFlag adds --label to a command creating a topic or consumer
+func AddLabelFlag(c *cobra.Command, into *[]string) {
+	c.Flags().StringArrayVar(into, "label", nil, "Label as key=value, e.g. 'team=payments' (repeatable)")
+}
+
+// ListTopics lists the server's topics with their labels and owners, those kept in the
+// annotations sidecar included, and only those matching --selector. The server does the
+// selecting when it keeps topic labels itself and has FeatureLabelSelector.
+func ListTopics(apiClient *client.Client) ([]client.Topic, error) {
+	selector, err := labels.ParseSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	var topics []client.Topic
+	if len(selector) > 0 && apiClient.Supports(client.FeatureTopicLabels) && apiClient.Supports(client.FeatureLabelSelector) {
+		topics, err = apiClient.GetTopicsMatching(selector.String())
+		selector = nil
+	} else {
+		topics, err = apiClient.GetTopics()
+	}
+	if err != nil {
+		return nil, err
+	}
+	sidecar, err := annotations.Open(apiClient.BaseURL())
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]client.Topic, 0, len(topics))
+	for _, topic := range topics {
+		kept := sidecar.Topics[topic.Name]
+		topic.Labels = annotations.MergeLabels(topic.Labels, kept.Labels)
+		if topic.Owner == nil {
+			topic.Owner = kept.Owner
+		}
+		if selector.Matches(topic.Labels) {
+			selected = append(selected, topic)
+		}
+	}
+	return selected, nil
+}
+
+// ListConsumers lists the server's consumers with their labels and descriptions, those
+// kept in the annotations sidecar included, and only those matching --selector. The
+// server does the selecting when it keeps consumer labels itself and has
+// FeatureLabelSelector.
+func ListConsumers(apiClient *client.Client) ([]client.Consumer, error) {
+	selector, err := labels.ParseSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	if len(selector) > 0 && apiClient.Supports(client.FeatureConsumerMetadata) && apiClient.Supports(client.FeatureLabelSelector) {
+		return apiClient.GetConsumersMatching(selector.String())
+	}
+
+	consumers, err := apiClient.GetConsumers()
+	if err != nil {
+		return nil, err
+	}
+	sidecar, err := annotations.Open(apiClient.BaseURL())
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]client.Consumer, 0, len(consumers))
+	for _, consumer := range consumers {
+		kept := sidecar.Consumers[consumer.ID]
+		consumer.Labels = annotations.MergeLabels(consumer.Labels, kept.Labels)
+		if consumer.Description == "" {
+			consumer.Description = kept.Description
+		}
+		if selector.Matches(consumer.Labels) {
+			selected = append(selected, consumer)
+		}
+	}
+	return selected, nil
+}
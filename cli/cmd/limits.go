@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rateLimit        string
+	concurrencyLimit int
+	limits           client.Limits
+)
+
+// AddLimitFlags adds --rate and --concurrency to a bulk command, capping the requests it
+// makes to each event store it uses so it doesn't overwhelm a production server
+func AddLimitFlags(c *cobra.Command) {
+	c.Flags().StringVar(&rateLimit, "rate", "0", "Most requests per second to each event store, e.g. '20/s' or '600/m' (0 = unlimited)")
+	c.Flags().IntVar(&concurrencyLimit, "concurrency", 0, "Most requests in flight at once to each event store (0 = unlimited)")
+}
+
+// checkLimits parses --rate and --concurrency into the limits of the command's clients
+func checkLimits() error {
+	rate := 0.0
+	if rateLimit != "" {
+		var err error
+		if rate, err = bench.ParseRate(rateLimit); err != nil {
+			return fmt.Errorf("invalid --rate: %w", err)
+		}
+	}
+	if concurrencyLimit < 0 {
+		return fmt.Errorf("--concurrency can't be negative")
+	}
+	limits = client.Limits{Rate: rate, Concurrency: concurrencyLimit}
+	return nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint <events-file>",
+	Short: "Check an events file before publishing",
+	Long: `Check that a file of events is well-formed for 'es event publish' without
+contacting the server. Each event must have a topic, a type and an object payload.
+
+Examples:
+  # Lint an events file
+  es lint events.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+
+		var events []map[string]interface{}
+		if err := json.Unmarshal(data, &events); err != nil {
+			return fmt.Errorf("failed to parse JSON file: %w", err)
+		}
+
+		problems := lintEvents(events)
+		for _, problem := range problems {
+			output.PrintMessage(fmt.Sprintf("%s: %s", args[0], problem))
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%d problem(s) found", len(problems))
+		}
+
+		output.PrintMessage(fmt.Sprintf("%s: %d event(s) OK", args[0], len(events)))
+		return nil
+	},
+}
+
+// lintEvents returns a description of each structural problem in events
+func lintEvents(events []map[string]interface{}) []string {
+	var problems []string
+	if len(events) == 0 {
+		problems = append(problems, "no events")
+	}
+
+	for i, event := range events {
+		for _, field := range []string{"topic", "type"} {
+			if value, ok := event[field].(string); !ok || value == "" {
+				problems = append(problems, fmt.Sprintf("event %d: missing '%s'", i, field))
+			}
+		}
+		if _, ok := event["payload"].(map[string]interface{}); !ok {
+			problems = append(problems, fmt.Sprintf("event %d: 'payload' must be an object", i))
+		}
+	}
+
+	return problems
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
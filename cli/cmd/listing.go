@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listColumns []string
+	listSortBy  string
+)
+
+// AddListFlags adds --columns and --sort-by to a command that lists resources with the
+// given columns
+func AddListFlags(c *cobra.Command, columns []output.Column) {
+	names := output.ColumnNames(columns)
+	c.Flags().StringSliceVar(&listColumns, "columns", nil, "Columns to show in table and CSV output, in order (comma-separated): "+names)
+	c.Flags().StringVar(&listSortBy, "sort-by", "", "Sort by a column, optionally with ':asc' or ':desc' (e.g. 'name:desc'): "+names)
+}
+
+// ApplyListFlags selects a listing's columns with --columns and sorts its rows with
+// --sort-by
+func ApplyListFlags(l *output.Listing) error {
+	if err := l.Select(listColumns); err != nil {
+		return err
+	}
+	return l.Sort(listSortBy)
+}
+
+// ListFlagsUse reports whether --columns or --sort-by name a column, for columns that
+// are only worth computing when asked for
+func ListFlagsUse(name string) bool {
+	for _, column := range listColumns {
+		if strings.EqualFold(strings.TrimSpace(column), name) {
+			return true
+		}
+	}
+	sortName, _, _ := output.ParseSortSpec(listSortBy)
+	return strings.EqualFold(sortName, name)
+}
+
+// PrintListing prints a listing in the output format, or only its keys with --quiet.
+// JSON output has the listing's resources under key.
+func PrintListing(key string, l *output.Listing) error {
+	if quiet {
+		output.PrintIDs(l.Keys())
+		return nil
+	}
+	switch cfg.Output.Format {
+	case "json":
+		return output.PrintListingJSON(key, l)
+	case "csv":
+		return output.PrintListingCSV(l)
+	default:
+		output.PrintListing(l)
+		return nil
+	}
+}
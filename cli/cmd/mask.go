@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	maskFlag   []string
+	maskFields []string
+)
+
+// AddMaskFlag adds --mask to a command that prints events, so sensitive fields can be
+// hidden when its output is shown or shared
+func AddMaskFlag(c *cobra.Command) {
+	c.Flags().StringSliceVar(&maskFlag, "mask", nil, "Print these fields as '***', 'payload.<path>' or 'metadata.<path>' (comma-separated; default: output.mask in the config file; '' for none)")
+}
+
+// MaskFields returns the fields of events to print masked: --mask if given, or else the
+// config file's output.mask
+func MaskFields() []string {
+	return maskFields
+}
+
+// logMaskFields returns the fields of events that logged request and response bodies
+// mask: those of --mask and the config file's output.mask
+func logMaskFields() []string {
+	return append(slices.Clone(maskFields), cfg.Output.Mask...)
+}
+
+// checkMask works out the fields to mask for a command with --mask
+func checkMask(c *cobra.Command) error {
+	maskFields = nil
+	flag := c.Flags().Lookup("mask")
+	if flag == nil {
+		return nil
+	}
+	maskFields = cfg.Output.Mask
+	if flag.Changed {
+		maskFields = maskFlag
+	}
+	return output.CheckMaskFields(maskFields)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/copier"
+	"github.com/event-store/cli/internal/mirror"
+	"github.com/event-store/cli/internal/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mirrorDest       string
+	mirrorTopics     []string
+	mirrorCheckpoint string
+	mirrorInterval   time.Duration
+	mirrorBatchSize  int
+	mirrorRenames    []string
+	mirrorTransform  string
+	mirrorStatusPort int
+	mirrorSilent     bool
+)
+
+// mirrorCmd represents the mirror command
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror --dest <url>",
+	Short: "Continuously replicate topics to another event store",
+	Long: `Replicate topics from the event store (--server-url) to another one (--dest) until
+stopped. New events are polled for every --interval and republished to topics of the
+same name, which are created with the source topic's schemas when missing. Without
+--topic every source topic is mirrored, including topics created later.
+
+Events can be transformed on the way, as with 'es event replay': --rename-type renames
+event types and --transform rewrites payloads with a jq expression or a Go template. A
+failing transform stops the topic at that event until the mirror is restarted with a
+fixed transform.
+
+Progress is recorded in a checkpoint file after every published batch, so a restarted
+mirror carries on where it stopped. The mirror runs as a job, so once stopped it can be
+restarted with the same flags by 'es job resume'. Delivery is at least once: a batch published just
+before a crash can be published again. Mirrored events get new IDs and timestamps on the
+destination.
+
+When a server cannot be reached the mirror keeps retrying, waiting longer after each
+failure up to a minute. With --status-port, each topic's position and lag are served as
+JSON at /status.
+
+Examples:
+  # Mirror two topics from production to staging
+  es mirror -s http://prod:8000 --dest http://staging:8000 --topic orders --topic payments
+
+  # Mirror everything, serving progress on port 19300
+  es mirror --dest http://backup:8000 --checkpoint /var/lib/es/backup-mirror.json --status-port 19300`,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if mirrorDest == "" {
+			return fmt.Errorf("--dest is required")
+		}
+		if mirrorInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		if mirrorBatchSize < 1 {
+			return fmt.Errorf("batch-size must be at least 1")
+		}
+		source := strings.TrimSuffix(cfg.Server.URL, "/")
+		dest := strings.TrimSuffix(mirrorDest, "/")
+		if source == dest {
+			return fmt.Errorf("source and destination are the same server")
+		}
+
+		typeMap, err := copier.ParseTypeMap(mirrorRenames)
+		if err != nil {
+			return err
+		}
+		var transformer transform.Transformer
+		if mirrorTransform != "" {
+			if transformer, err = transform.New(mirrorTransform); err != nil {
+				return err
+			}
+			defer transformer.Close()
+		}
+
+		checkpoint, err := mirror.LoadCheckpoint(mirrorCheckpoint)
+		if err != nil {
+			return err
+		}
+		if checkpoint.Source != "" && (checkpoint.Source != source || checkpoint.Destination != dest) {
+			return fmt.Errorf("checkpoint %s is for mirroring %s to %s; use another --checkpoint file", mirrorCheckpoint, checkpoint.Source, checkpoint.Destination)
+		}
+		checkpoint.Source, checkpoint.Destination = source, dest
+
+		// The checkpoint file holds the mirror's position; the job records that it was
+		// stopped, and the events it mirrored
+		job, _, err := StartJob("mirror", 0, nil)
+		if err != nil {
+			return err
+		}
+		mirrored := job.Progress
+
+		m := &mirror.Mirror{
+			Source:         NewClient(),
+			Destination:    NewClientFor(dest),
+			Topics:         mirrorTopics,
+			Checkpoint:     checkpoint,
+			CheckpointPath: mirrorCheckpoint,
+			BatchSize:      mirrorBatchSize,
+			TypeMap:        typeMap,
+			Transform:      transformer,
+			Report: func(status mirror.TopicStatus, published int, err error) {
+				timestamp := time.Now().Format(time.RFC3339)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "[%s] %s: %v\n", timestamp, status.Topic, err)
+					return
+				}
+				if published > 0 {
+					mirrored += published
+					if err := job.Save(nil, mirrored); err != nil {
+						fmt.Fprintf(os.Stderr, "[%s] Warning: %v\n", timestamp, err)
+					}
+				}
+				if !mirrorSilent {
+					fmt.Printf("[%s] %s: mirrored %d event(s) up to %s (lag %d)\n", timestamp, status.Topic, published, status.LastEventID, status.Lag)
+				}
+			},
+		}
+
+		group, err := NewRunner()
+		if err != nil {
+			return job.Finish(err)
+		}
+		if mirrorStatusPort > 0 {
+			mux := http.NewServeMux()
+			mux.Handle("/status", m)
+			group.Serve("mirror-status", &http.Server{Addr: fmt.Sprintf(":%d", mirrorStatusPort), Handler: mux})
+		}
+
+		if !mirrorSilent {
+			topics := "all topics"
+			if len(mirrorTopics) > 0 {
+				topics = strings.Join(mirrorTopics, ", ")
+			}
+			fmt.Printf("Mirroring %s from %s to %s every %s\n", topics, source, dest, mirrorInterval)
+			fmt.Printf("Checkpoint: %s\n", mirrorCheckpoint)
+			if mirrorStatusPort > 0 {
+				fmt.Printf("Status: http://localhost:%d/status\n", mirrorStatusPort)
+			}
+			fmt.Println("Press Ctrl+C to stop")
+			fmt.Println()
+		}
+
+		group.Go("mirror", func(ctx context.Context) error {
+			return m.Run(ctx, mirrorInterval)
+		})
+		if err := group.Wait(); err != nil {
+			return job.Finish(err)
+		}
+		// A mirror runs until it's stopped
+		job.Finish(context.Canceled)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mirrorCmd)
+	DisablePager(mirrorCmd)
+	mirrorCmd.Flags().StringVar(&mirrorDest, "dest", "", "Event store server URL to mirror to (required)")
+	mirrorCmd.Flags().StringArrayVar(&mirrorTopics, "topic", nil, "Topic to mirror (repeatable; default: every topic)")
+	mirrorCmd.Flags().StringVar(&mirrorCheckpoint, "checkpoint", "es-mirror.json", "File recording how far each topic has been mirrored")
+	mirrorCmd.Flags().DurationVar(&mirrorInterval, "interval", 5*time.Second, "How often to poll for new events")
+	mirrorCmd.Flags().IntVar(&mirrorBatchSize, "batch-size", 100, "Events per publish request")
+	mirrorCmd.Flags().StringArrayVar(&mirrorRenames, "rename-type", nil, "Rename an event type, format 'old=new' (repeatable)")
+	mirrorCmd.Flags().StringVar(&mirrorTransform, "transform", "", "Rewrite each payload with a jq expression or a Go template ('{{ ... }}')")
+	mirrorCmd.Flags().IntVar(&mirrorStatusPort, "status-port", 0, "Serve each topic's progress and lag as JSON at /status on this port")
+	mirrorCmd.Flags().BoolVar(&mirrorSilent, "silent", false, "Suppress progress output to stdout")
+	AddLimitFlags(mirrorCmd)
+}
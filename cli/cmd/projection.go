@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// projectionCmd represents the projection command
+var projectionCmd = &cobra.Command{
+	Use:   "projection",
+	Short: "Build read models from events",
+	Long:  `Define projections, which turn the events of some topics into records for stdout, a file, a SQLite table or a webhook, and run them to keep those read models up to date.`,
+}
+
+// ProjectionCmd returns the projection command for use in subcommands
+func ProjectionCmd() *cobra.Command {
+	return projectionCmd
+}
+
+func init() {
+	rootCmd.AddCommand(projectionCmd)
+}
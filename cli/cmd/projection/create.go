@@ -0,0 +1,127 @@
+package projection
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/projection"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createTopics    []string
+	createFilter    string
+	createTransform string
+	createSink      string
+	createTable     string
+	createKey       string
+	createOut       string
+	createForce     bool
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create <name> --topic <topic> --sink <sink>",
+	Short: "Write a projection's config file",
+	Long: `Write the config file of a projection, <name>.yaml by default, for 'es projection run'.
+The file can be edited afterwards, or written by hand:
+
+  name: order-totals
+  topics: [orders, payments]
+  filter: type=order.placed AND payload.region=eu   # as for --filter, joined by AND
+  transform: '{id: .payload.orderId, total: .payload.total, status: .type}'
+  sink:
+    type: sqlite            # stdout, file, sqlite or webhook
+    database: read.db       # sqlite: database file
+    table: orders           # sqlite: table (default: the projection's name)
+    key: id                 # sqlite: field whose row a record replaces
+    # path: orders.ndjson   # file: NDJSON file records are appended to
+    # url: https://...      # webhook: URL each record is POSTed to
+    # headers: {Authorization: "Bearer $TOKEN"}
+  checkpoint: order-totals.checkpoint.json
+
+The transform is a jq expression or Go template (as for 'es event replay --transform')
+given the event with its topic (topic, id, timestamp, type, payload) and returning the
+record; no output skips the event. Without one the record is the event itself.
+
+--sink takes 'stdout', 'file:<path>', 'sqlite:<database>' or a webhook's http:// or
+https:// URL.
+
+Examples:
+  # Keep a SQLite table of orders, a row per order
+  es projection create order-totals --topic orders --sink sqlite:read.db --key id \
+    --transform '{id: .payload.orderId, total: .payload.total, status: .type}'
+
+  # Forward failed payments to a webhook
+  es projection create failed-payments --topic payments --filter type=payment.failed \
+    --sink https://hooks.example.com/payments`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		def := &projection.Definition{
+			Name:      args[0],
+			Topics:    createTopics,
+			Filter:    createFilter,
+			Transform: createTransform,
+		}
+		sink, err := parseSink(createSink)
+		if err != nil {
+			return err
+		}
+		if (createTable != "" || createKey != "") && sink.Type != "sqlite" {
+			return fmt.Errorf("--table and --key are for sqlite sinks")
+		}
+		sink.Table = createTable
+		sink.Key = createKey
+		def.Sink = sink
+		if err := def.Validate(); err != nil {
+			return err
+		}
+
+		out := createOut
+		if out == "" {
+			out = args[0] + ".yaml"
+		}
+		if _, err := os.Stat(out); err == nil && !createForce {
+			return fmt.Errorf("%s already exists (use --force to replace it)", out)
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := def.Save(out); err != nil {
+			return err
+		}
+		if !cmd.Quiet() {
+			fmt.Printf("Wrote projection '%s' to %s; run it with 'es projection run %s'\n", def.Name, out, out)
+		}
+		return nil
+	},
+}
+
+// parseSink parses --sink
+func parseSink(spec string) (projection.Sink, error) {
+	switch {
+	case spec == "stdout":
+		return projection.Sink{Type: "stdout"}, nil
+	case strings.HasPrefix(spec, "file:"):
+		return projection.Sink{Type: "file", Path: strings.TrimPrefix(spec, "file:")}, nil
+	case strings.HasPrefix(spec, "sqlite:"):
+		return projection.Sink{Type: "sqlite", Database: strings.TrimPrefix(spec, "sqlite:")}, nil
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return projection.Sink{Type: "webhook", URL: spec}, nil
+	}
+	return projection.Sink{}, fmt.Errorf("invalid --sink '%s' (expected stdout, file:<path>, sqlite:<database> or an http(s):// URL)", spec)
+}
+
+func init() {
+	cmd.ProjectionCmd().AddCommand(createCmd)
+	createCmd.Flags().StringArrayVar(&createTopics, "topic", nil, "Topic to project (repeatable; required)")
+	createCmd.Flags().StringVar(&createFilter, "filter", "", "Only project events matching these filters, e.g. 'type=order.placed AND payload.region=eu'")
+	createCmd.Flags().StringVar(&createTransform, "transform", "", "jq expression or Go template turning an event into a record")
+	createCmd.Flags().StringVar(&createSink, "sink", "stdout", "Where records go: stdout, file:<path>, sqlite:<database> or an http(s):// URL")
+	createCmd.Flags().StringVar(&createTable, "table", "", "Table of a sqlite sink (default: the projection's name)")
+	createCmd.Flags().StringVar(&createKey, "key", "", "Record field whose row in a sqlite sink a record replaces")
+	createCmd.Flags().StringVar(&createOut, "out", "", "Config file to write (default: <name>.yaml)")
+	createCmd.Flags().BoolVar(&createForce, "force", false, "Replace an existing config file")
+	createCmd.MarkFlagRequired("topic")
+}
@@ -0,0 +1,115 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/projection"
+	"github.com/event-store/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runFollow   bool
+	runInterval time.Duration
+	runReset    bool
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <config-file>",
+	Short: "Run a projection, materializing its read model",
+	Long: `Run the projection in a config file (see 'es projection create'): read the events of its
+topics published since its checkpoint, keep those matching its filter, transform them
+into records and write them to its sink. The checkpoint is saved after each page of
+events, so a stopped run carries on where it left off; a page whose records were
+written just before a crash is projected again. With --follow the topics are polled
+every --interval until stopped.
+
+A SQLite sink is a table with a column per record field, added as fields appear, and
+needs the sqlite3 command-line shell on the PATH. With a key a record replaces the row
+with the same key, so the table holds each key's latest state; without one every record
+is a new row. A webhook sink POSTs each record as JSON, expanding $VARIABLES in its
+headers; a failed request stops the run, to be retried from the checkpoint.
+
+Progress is written to stderr, so a stdout sink's records can be piped.
+
+Examples:
+  # Bring a projection up to date
+  es projection run order-totals.yaml
+
+  # Keep it up to date
+  es projection run order-totals.yaml --follow
+
+  # Rebuild it from the start of its topics (clear the sink first)
+  es projection run order-totals.yaml --reset`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		server := strings.TrimSuffix(cfg.Server.URL, "/")
+
+		def, err := projection.Load(args[0])
+		if err != nil {
+			return err
+		}
+		if runReset {
+			if err := os.Remove(def.Checkpoint); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to reset checkpoint: %w", err)
+			}
+		}
+		checkpoint, err := projection.LoadCheckpoint(def, server)
+		if err != nil {
+			return err
+		}
+		p, err := projection.Open(def)
+		if err != nil {
+			return err
+		}
+		defer p.Close()
+
+		report := func(stats projection.Stats) {
+			if !cmd.Quiet() {
+				fmt.Fprintf(os.Stderr, "[%s] %s: %d event(s) read, %d record(s) written\n", time.Now().Format(time.RFC3339), def.Name, stats.Events, stats.Records)
+			}
+		}
+
+		if !runFollow {
+			stats, err := p.Run(context.Background(), apiClient, checkpoint)
+			report(stats)
+			return err
+		}
+
+		if runInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+		group.Go("projection", func(ctx context.Context) error {
+			return watch.Loop(ctx, runInterval, func() error {
+				stats, err := p.Run(ctx, apiClient, checkpoint)
+				if stats.Events > 0 {
+					report(stats)
+				}
+				if err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "[%s] %s: %v\n", time.Now().Format(time.RFC3339), def.Name, err)
+				}
+				return nil
+			})
+		})
+		return group.Wait()
+	},
+}
+
+func init() {
+	cmd.ProjectionCmd().AddCommand(runCmd)
+	cmd.DisablePager(runCmd)
+	runCmd.Flags().BoolVarP(&runFollow, "follow", "f", false, "Keep projecting new events until stopped")
+	runCmd.Flags().DurationVar(&runInterval, "interval", 5*time.Second, "How often --follow polls the topics")
+	runCmd.Flags().BoolVar(&runReset, "reset", false, "Remove the checkpoint and project from the start of the topics")
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/monitor"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/report"
+	"github.com/event-store/cli/internal/stats"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	reportTopics     []string
+	reportSince      string
+	reportUntil      string
+	reportFormat     string
+	reportOut        string
+	reportNoSnapshot bool
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write an operational report of the event store",
+	Long: `Write an operational report of the event store for a period, for weekly ops reviews:
+the events published to each topic, per day and by event type, the consumers' lag in
+those topics and the event store's health.
+
+Every topic is reported unless --topics names some. The period is the last 7 days unless
+--since and --until say otherwise. The topics' events in the period are read to count
+them.
+
+Each report records a snapshot of the event store's health and its consumers' lag in
+~/.es/reports/, kept for 90 days, so later reports can show how they changed over their
+period: run it on a schedule for trends. --no-snapshot doesn't record one.
+
+The report is written as Markdown (md), HTML or PDF, as --format says or --out's
+extension suggests, to stdout unless --out names a file.
+
+Examples:
+  # This week's report as Markdown
+  es report
+
+  # A weekly report of two topics as an HTML page
+  es report --topics orders,payments --since 7d --format html --out weekly.html
+
+  # Last month's report as a PDF
+  es report --since 2025-05-01 --until 2025-06-01 --out may.pdf`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		format, err := reportFileFormat()
+		if err != nil {
+			return err
+		}
+		if format == "pdf" && reportOut == "" && term.IsTerminal(int(os.Stdout.Fd())) {
+			return fmt.Errorf("a PDF report needs --out, or stdout redirected to a file")
+		}
+		now := time.Now()
+		timeRange, err := timerange.Parse(reportSince, reportUntil, now)
+		if err != nil {
+			return err
+		}
+		if timeRange.Since.IsZero() {
+			return fmt.Errorf("--since is required: the report needs a period")
+		}
+
+		apiClient := NewClient()
+		health, err := apiClient.GetHealth()
+		if err != nil {
+			return err
+		}
+		topics, err := apiClient.GetTopics()
+		if err != nil {
+			return err
+		}
+		consumers, err := apiClient.GetConsumers()
+		if err != nil {
+			return err
+		}
+		reported, err := reportedTopics(topics)
+		if err != nil {
+			return err
+		}
+
+		var topicStats []*client.TopicStats
+		for _, topic := range reported {
+			topicStat, err := reportTopicStats(apiClient, topic, timeRange)
+			if err != nil {
+				return err
+			}
+			topicStats = append(topicStats, topicStat)
+		}
+
+		history, err := report.OpenHistory(cfg.Server.URL)
+		if err != nil {
+			return err
+		}
+		current := reportSnapshot(now, health, topics, consumers)
+		r := report.New(cfg.Server.URL, timeRange.Since, timeRange.Until, topicStats, history.Between(timeRange.Since, timeRange.Until), current)
+		if !reportNoSnapshot {
+			history.Add(current)
+			if err := history.Save(); err != nil {
+				return err
+			}
+		}
+
+		var b bytes.Buffer
+		if err := r.Document().Render(&b, format); err != nil {
+			return err
+		}
+		if reportOut == "" {
+			_, err := os.Stdout.Write(b.Bytes())
+			return err
+		}
+		if err := os.WriteFile(reportOut, b.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote the report of %d topic(s) to %s\n", len(topicStats), reportOut)
+		return nil
+	},
+}
+
+// reportFileFormat returns the format of the report: --format, or the format --out's
+// extension suggests, or Markdown
+func reportFileFormat() (string, error) {
+	format := strings.ToLower(reportFormat)
+	switch format {
+	case "markdown":
+		format = "md"
+	case "":
+		format = "md"
+		switch strings.ToLower(filepath.Ext(reportOut)) {
+		case ".html", ".htm":
+			format = "html"
+		case ".pdf":
+			format = "pdf"
+		}
+	}
+	for _, known := range report.Formats {
+		if format == known {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("invalid --format '%s' (expected %s)", reportFormat, strings.Join(report.Formats, ", "))
+}
+
+// reportedTopics returns the topics named by --topics, in order, or every topic by name
+func reportedTopics(topics []client.Topic) ([]string, error) {
+	names := make([]string, 0, len(topics))
+	exists := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		names = append(names, topic.Name)
+		exists[topic.Name] = true
+	}
+	if len(reportTopics) == 0 {
+		sort.Strings(names)
+		return names, nil
+	}
+
+	var reported []string
+	seen := map[string]bool{}
+	for _, name := range reportTopics {
+		name = strings.TrimSpace(name)
+		if !exists[name] {
+			return nil, fmt.Errorf("topic '%s' not found", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			reported = append(reported, name)
+		}
+	}
+	return reported, nil
+}
+
+// reportTopicStats reads the events of a topic in a time range, passing the range to
+// servers that support time ranges, and returns their statistics
+func reportTopicStats(apiClient *client.Client, topic string, timeRange timerange.Range) (*client.TopicStats, error) {
+	info, err := apiClient.GetTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+	query := client.EventsQuery{}
+	if apiClient.Supports(client.FeatureEventTimeRange) {
+		query.Since = timerange.Format(timeRange.Since)
+		query.Until = timerange.Format(timeRange.Until)
+	}
+
+	collector := stats.NewCollector(topic)
+	progress := output.NewProgress("Reading "+topic, info.Sequence)
+	err = apiClient.ScanEventsQuery(topic, query, func(events []client.Event) (bool, error) {
+		for _, event := range events {
+			if timeRange.Past(event.Timestamp) {
+				return false, nil
+			}
+			if timeRange.Contains(event.Timestamp) {
+				collector.Add(event)
+			}
+		}
+		progress.Add(len(events))
+		return true, nil
+	})
+	progress.Finish()
+	if err != nil {
+		return nil, err
+	}
+	return collector.Stats(), nil
+}
+
+// reportSnapshot records the event store's health and each consumer's lag in each of its
+// topics, as in 'es status'
+func reportSnapshot(now time.Time, health *client.Health, topics []client.Topic, consumers []client.Consumer) report.Snapshot {
+	status := monitor.BuildStatus(cfg.Server.URL, health, topics, consumers)
+	snapshot := report.Snapshot{
+		Time:        now.UTC(),
+		Health:      health.Status,
+		Consumers:   health.Consumers,
+		Dispatchers: health.RunningDispatchers,
+		Lags:        make(map[string]map[string]int64, len(status.Consumers)),
+	}
+	for _, consumer := range status.Consumers {
+		lags := map[string]int64{}
+		for _, position := range consumer.Topics {
+			if position.Lag != nil {
+				lags[position.Topic] = *position.Lag
+			}
+		}
+		snapshot.Lags[consumer.ID] = lags
+	}
+	return snapshot
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringSliceVar(&reportTopics, "topics", nil, "Topics to report (comma-separated; default: every topic)")
+	reportCmd.Flags().StringVar(&reportSince, "since", "7d", "Start of the period: timestamp, date, 'today', 'yesterday' or a duration ago such as '7d'")
+	reportCmd.Flags().StringVar(&reportUntil, "until", "", "End of the period (same formats as --since; default: now)")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "", "Report format: md, html or pdf (default: from --out's extension, or md)")
+	reportCmd.Flags().StringVar(&reportOut, "out", "", "File to write the report to (default: stdout)")
+	reportCmd.Flags().BoolVar(&reportNoSnapshot, "no-snapshot", false, "Don't record this report's snapshot of health and consumer lag for the trends of later reports")
+}
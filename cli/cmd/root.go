@@ -1,19 +1,38 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/event-store/cli/internal/client"
 	"github.com/event-store/cli/internal/config"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/runner"
+	"github.com/event-store/cli/internal/tracing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
 	serverURL    string
+	contextName  string
 	outputFormat string
 	configPath   string
+	verbosity    int
+	debugAddr    string
+	showStats    bool
+	noPager      bool
+	wide         bool
+	quiet        bool
+	timeout      time.Duration
+	proxy        string
 	cfg          *config.Config
+	pager        *output.Pager
+	tracer       *tracing.Tracer
+	metrics      = client.NewMetrics()
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -23,7 +42,15 @@ var rootCmd = &cobra.Command{
 	Long: `Event Store CLI is a command-line tool for managing an event store instance.
 It provides commands for managing topics and consumers with support for
 table, JSON, and CSV output formats.`,
+	// Errors are reported by run, in the output format
+	SilenceErrors: true,
+	SilenceUsage:  true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := usageChecks(cmd); err != nil {
+			return err
+		}
+		started = true
+
 		// Load configuration
 		var err error
 		cfg, err = config.LoadConfig(configPath)
@@ -32,42 +59,266 @@ table, JSON, and CSV output formats.`,
 		}
 
 		// Override with command-line flags if provided
-		if serverURL != "" {
-			cfg.Server.URL = serverURL
+		if contextName != "" {
+			server, err := cfg.Context(contextName)
+			if err != nil {
+				return err
+			}
+			cfg.Server = server
+		}
+		if serverURL != "" && serverURL != cfg.Server.URL {
+			// The default server's auth is only for its own URL
+			cfg.Server = config.ServerConfig{URL: serverURL}
 		}
 		if outputFormat != "" {
 			cfg.Output.Format = outputFormat
 		}
+		if timeout != 0 {
+			cfg.HTTP.Timeout = timeout
+		}
+		output.SetWide(wide)
+		if output.IsMarkup(cfg.Output.Format) {
+			output.SetMarkup(cfg.Output.Format)
+		}
+		if proxy != "" {
+			cfg.HTTP.Proxy = proxy
+		}
 
 		// Validate output format
-		if cfg.Output.Format != "table" && cfg.Output.Format != "json" && cfg.Output.Format != "csv" {
-			return fmt.Errorf("invalid output format: %s (must be 'table', 'json', or 'csv')", cfg.Output.Format)
+		switch cfg.Output.Format {
+		case "table", "json", "csv", "markdown", "html":
+		default:
+			return fmt.Errorf("invalid output format: %s (must be 'table', 'json', 'csv', 'markdown' or 'html')", cfg.Output.Format)
+		}
+
+		if cfg.HTTP.Timeout < 0 {
+			return fmt.Errorf("invalid timeout: %s (must be positive)", cfg.HTTP.Timeout)
+		}
+		if err := client.CheckHTTP2Mode(cfg.HTTP.HTTP2); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		if _, err := client.ProxyFunc(cfg.HTTP.Proxy); err != nil {
+			return err
+		}
+
+		if quiet && Watching() {
+			return fmt.Errorf("--quiet can't be used with --watch")
+		}
+		if output.IsMarkup(cfg.Output.Format) && Watching() {
+			return fmt.Errorf("--output %s can't be used with --watch", cfg.Output.Format)
+		}
+		if err := checkAllContexts(); err != nil {
+			return err
+		}
+		if err := checkLimits(); err != nil {
+			return err
+		}
+		if err := checkMask(cmd); err != nil {
+			return err
+		}
+
+		recordCommandLine(cmd, args)
+		if err := runBeforeHooks(cmd, args); err != nil {
+			return err
+		}
+		startAudit()
+
+		// Page long output on a terminal, except for commands that stream or prompt
+		if !noPager && sessionClients == nil && !Watching() && !pagerDisabled(cmd) {
+			pager = output.StartPager(output.PagerProgram())
 		}
 
 		return nil
 	},
 }
 
+// pagerAnnotation marks commands whose output must not go through a pager
+const pagerAnnotation = "no-pager"
+
+// DisablePager stops a command's output, and its subcommands', going through a pager:
+// for commands that stream until interrupted or prompt for input
+func DisablePager(c *cobra.Command) {
+	if c.Annotations == nil {
+		c.Annotations = map[string]string{}
+	}
+	c.Annotations[pagerAnnotation] = "true"
+}
+
+// pagerFlagsAnnotation lists the flags, comma-separated, that stop a command's output
+// going through a pager when given
+const pagerFlagsAnnotation = "no-pager-flags"
+
+// DisablePagerWith stops a command's output going through a pager when any of the flags
+// is given, such as one that makes it prompt for input
+func DisablePagerWith(c *cobra.Command, flags ...string) {
+	if c.Annotations == nil {
+		c.Annotations = map[string]string{}
+	}
+	c.Annotations[pagerFlagsAnnotation] = strings.Join(flags, ",")
+}
+
+func pagerDisabled(c *cobra.Command) bool {
+	if flags := c.Annotations[pagerFlagsAnnotation]; flags != "" {
+		for _, flag := range strings.Split(flags, ",") {
+			if c.Flags().Changed(flag) {
+				return true
+			}
+		}
+	}
+	for ; c != nil; c = c.Parent() {
+		if c.Annotations[pagerAnnotation] != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	err := rootCmd.Execute()
+	// Trace API calls when an OTLP endpoint is configured via OTEL_* variables. The one
+	// tracer serves every command of a shell session, and is shut down on exit.
+	tracer = tracing.FromEnv()
+	code := run()
+	if showStats {
+		output.PrintClientStats(os.Stderr, metrics.Snapshot())
+	}
+	if shutdownErr := tracer.Shutdown(); shutdownErr != nil && verbosity > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", shutdownErr)
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+}
+
+// run executes the command line, reports any error in the output format and returns
+// the exit code
+func run() int {
+	// A command run from a shell session is checked afresh, and doesn't leave the
+	// session's own errors looking like usage errors
+	outerStarted := started
+	started = false
+	defer func() { started = outerStarted }()
+	// Commands run from a shell session have their own hooks
+	outer := invocation
+	invocation = nil
+	defer func() { invocation = outer }()
+	// The requests of a command run from another are audited with it, not the other
+	outerAudit, outerLine := auditBaseline, commandLine
+	auditBaseline = nil
+	defer func() {
+		if commandLine = outerLine; outerAudit != nil {
+			startAudit()
+		}
+	}()
+
+	c, err := rootCmd.ExecuteC()
+	pager.Close()
+	pager = nil
+	code := 0
 	if err != nil {
-		os.Exit(1)
+		code = reportError(c, err)
 	}
+	runAfterHooks(err, code)
+	recordAudit(err, code)
+	return code
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVarP(&serverURL, "server-url", "s", "", "Event store server URL (default: http://localhost:8000)")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format: table, json, or csv (default: table)")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "Use the server of a context configured under 'contexts' in the config file")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format: table, json, csv, markdown or html (default: table)")
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file path (default: ~/.es/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&debugAddr, "debug-goroutines", "", "Serve goroutine dumps and client metrics for long-running commands on this address (e.g. localhost:6060)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print only identifiers (topic names, consumer IDs, event IDs), one per line")
+	rootCmd.PersistentFlags().BoolVar(&showStats, "stats", false, "Print client request, connection and timing statistics to stderr on exit")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Fetch topic metadata from the server rather than the cache configured under 'cache'")
+	rootCmd.PersistentFlags().BoolVar(&wide, "wide", false, "Show table cells whole, rather than cutting them short or wrapping them to fit the terminal")
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "Don't pipe long output through $ES_PAGER, $PAGER or less")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "How long each request to the event store may take, for long exports (default: 30s); commands that wait have their own --timeout")
+	rootCmd.PersistentFlags().StringVar(&proxy, "proxy", "", "Reach the event store through this proxy, e.g. socks5://localhost:1080 for an SSH tunnel, or 'direct' for none (default: $HTTPS_PROXY, $HTTP_PROXY, except $NO_PROXY hosts)")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Log HTTP requests to stderr (-v: requests, -vv: headers, -vvv: bodies in full, except secret and masked fields)")
 
 	// Bind flags to viper for config file support
 	viper.BindPFlag("server.url", rootCmd.PersistentFlags().Lookup("server-url"))
 	viper.BindPFlag("output.format", rootCmd.PersistentFlags().Lookup("output"))
 }
 
+// Quiet reports whether --quiet was given: commands that list or create resources print
+// only their identifiers, one per line, whatever the output format
+func Quiet() bool {
+	return quiet
+}
+
 // GetConfig returns the loaded configuration
 func GetConfig() *config.Config {
 	return cfg
 }
+
+// NewClient returns an API client configured from the loaded configuration and global flags
+func NewClient() *client.Client {
+	return NewClientFor(cfg.Server.URL)
+}
+
+// NewClientFor returns an API client for another server, configured from the global flags.
+// In an 'es shell' session every command gets the same client for a server, so
+// connections and cached topic metadata are reused.
+func NewClientFor(serverURL string) *client.Client {
+	apiClient, shared := sessionClients[serverURL]
+	if !shared {
+		apiClient = client.NewClient(serverURL)
+		// Checked when the config was loaded
+		_ = apiClient.SetTransport(transportOptions())
+		if sessionClients != nil {
+			apiClient.SetTopicCache(sessionTopicCache)
+			sessionClients[serverURL] = apiClient
+		} else {
+			useTopicCache(apiClient, serverURL)
+		}
+	}
+	if shared {
+		apiClient.SetTimeout(cfg.HTTP.Timeout)
+	}
+	if verbosity > 0 || shared {
+		apiClient.SetVerbosity(verbosity, os.Stderr)
+		apiClient.SetLogMask(logMaskFields())
+	}
+	if tracer != nil || shared {
+		apiClient.SetTracer(tracer)
+	}
+	useAuth(apiClient, serverURL)
+	apiClient.SetLimits(limits)
+	apiClient.SetMetrics(metrics)
+	return apiClient
+}
+
+// transportOptions returns the client's connection settings from the config file's
+// 'http' section and --timeout
+func transportOptions() client.TransportOptions {
+	return client.TransportOptions{
+		Timeout:          cfg.HTTP.Timeout,
+		MaxIdleConns:     cfg.HTTP.MaxIdleConns,
+		IdleConnTimeout:  cfg.HTTP.IdleConnTimeout,
+		DisableKeepAlive: cfg.HTTP.DisableKeepAlive,
+		DisableGzip:      cfg.HTTP.DisableGzip,
+		CompressRequests: cfg.HTTP.CompressRequests,
+		HTTP2:            cfg.HTTP.HTTP2,
+		Proxy:            cfg.HTTP.Proxy,
+	}
+}
+
+// NewRunner returns a goroutine group for a long-running command, stopped by Ctrl+C or
+// SIGTERM, serving goroutine dumps and Prometheus client metrics if --debug-goroutines is set
+func NewRunner() (*runner.Group, error) {
+	group := runner.New(context.Background())
+	if debugAddr != "" {
+		group.HandleDebug("/metrics", metrics.Handler())
+		if err := group.ServeDebug(debugAddr); err != nil {
+			group.Wait()
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Goroutine dumps available at http://%s/debug/goroutines\n", debugAddr)
+		fmt.Fprintf(os.Stderr, "Client metrics available at http://%s/metrics\n", debugAddr)
+	}
+	return group, nil
+}
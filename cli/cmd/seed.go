@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// seedCmd represents the seed command
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Provision an event store from fixture files",
+	Long:  `Provision an event store for local development or CI from a directory of declarative fixture files listing topics, schemas, consumers and events.`,
+}
+
+// SeedCmd returns the seed command for use in subcommands
+func SeedCmd() *cobra.Command {
+	return seedCmd
+}
+
+func init() {
+	rootCmd.AddCommand(seedCmd)
+}
@@ -0,0 +1,70 @@
+package seed
+
+import (
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/seed"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <dir|file>",
+	Short: "Provision topics, consumers and events from fixture files",
+	Long: `Provision an event store from a directory of fixture files (*.yaml, *.yml and *.json,
+read in name order) or a single fixture file. A fixture file lists any of:
+
+  topics:
+    - name: orders
+      schemasFile: schemas/orders.json  # or 'schemas:' inline, as for 'es topic create'
+  consumers:
+    - callback: http://localhost:9000/orders
+      topics: [orders]
+  events:
+    - topic: orders
+      type: order.placed
+      payload: {orderId: "o-1", total: 42.5}
+
+Topics are created first, then consumers registered, then events published in the
+order of the files and of the events in them.
+
+Applying is idempotent: topics that exist get only missing or changed schemas, which
+schema updates must allow; consumers with the same callback and topics are kept; and
+events already in their topic, with the same type and payload, aren't published again.
+A consumer whose topics changed is deleted and registered again.
+
+Examples:
+  # Provision a local event store
+  es seed apply fixtures/
+
+  # In CI, against a fresh event store
+  es seed apply fixtures/ --server-url http://event-store:8000`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		fixtures, err := seed.Load(args[0])
+		if err != nil {
+			return err
+		}
+		result, err := seed.Apply(cmd.NewClient(), cmd.GetConfig().Server.URL, fixtures)
+		if printErr := printResult(result); printErr != nil {
+			return printErr
+		}
+		return err
+	},
+}
+
+// printResult prints what applying or resetting fixtures did, so far if it failed
+func printResult(result *seed.Result) error {
+	switch cmd.GetConfig().Output.Format {
+	case "json":
+		return output.PrintJSON(result)
+	case "csv":
+		return output.PrintSeedResultCSV(result)
+	default:
+		output.PrintSeedResult(result)
+		return nil
+	}
+}
+
+func init() {
+	cmd.SeedCmd().AddCommand(applyCmd)
+}
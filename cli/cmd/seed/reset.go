@@ -0,0 +1,64 @@
+package seed
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/seed"
+	"github.com/spf13/cobra"
+)
+
+var resetConfirm string
+
+var resetCmd = &cobra.Command{
+	Use:   "reset <dir|file>",
+	Short: "Delete what fixture files provisioned and apply them again",
+	Long: `Delete the events of the fixtures' topics and the consumers with the fixtures'
+callbacks, then apply the fixtures again, to get back to a known state between test
+runs. Topics can't be deleted, so they are kept and their schemas applied again.
+
+Deleting events is experimental: the event store server in this repository can't delete
+events yet, so resetting a topic that has events needs a server that advertises the
+'topic-truncate' feature (see es version --server). A fixture topic that doesn't exist
+yet is skipped, and any other failure to read a topic stops the reset.
+
+This can't be undone, so it has to be confirmed by typing the server URL, or with
+--confirm <server-url> in scripts.
+
+Examples:
+  # Start a local event store over from the fixtures
+  es seed reset fixtures/
+
+  # The same in CI
+  es seed reset fixtures/ --server-url http://event-store:8000 --confirm http://event-store:8000`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		server := cmd.GetConfig().Server.URL
+		fixtures, err := seed.Load(args[0])
+		if err != nil {
+			return err
+		}
+		what := fmt.Sprintf("The events of %d topic(s) and %d consumer(s) on %s will be deleted", len(fixtures.Topics), len(fixtures.Consumers), server)
+		if err := cmd.ConfirmName(what, server, resetConfirm); err != nil {
+			return err
+		}
+
+		apiClient := cmd.NewClient()
+		result, err := seed.Reset(apiClient, server, fixtures)
+		if err == nil {
+			var applied *seed.Result
+			applied, err = seed.Apply(apiClient, server, fixtures)
+			result.Actions = append(result.Actions, applied.Actions...)
+		}
+		if printErr := printResult(result); printErr != nil {
+			return printErr
+		}
+		return err
+	},
+}
+
+func init() {
+	cmd.SeedCmd().AddCommand(resetCmd)
+	resetCmd.Flags().StringVar(&resetConfirm, "confirm", "", "Confirm without being asked, by giving the server URL")
+	cmd.DisablePager(resetCmd)
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/shell"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+var (
+	shellFile       string
+	shellKeepGoing  bool
+	shellTopicCache time.Duration
+)
+
+// sessionClients holds one client per server URL while an 'es shell' session runs
+var sessionClients map[string]*client.Client
+
+// sessionTopicCache is how long shared clients cache topic metadata
+var sessionTopicCache time.Duration
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run es commands interactively or from a script with a shared client",
+	Long: `Run es commands one per line, without the 'es' prefix, reusing one client per server
+for the whole session. Connections are kept alive between commands and topic metadata
+is cached for --topic-cache, so interactive and batch sessions avoid reconnecting and
+refetching the same topics. A topic's cached metadata is dropped when the session
+changes or publishes to it; changes made by others show up once it expires.
+
+Global flags given to 'es shell' (such as --server-url and --output) apply to every
+command; a command can override them. Lines starting with '#' are comments. Words are
+split as in a POSIX shell, so quote JSON and filter expressions.
+
+Commands are read from --file, or from stdin. On a terminal a prompt is shown and
+failed commands are reported without ending the session; exit with 'exit' or Ctrl+D.
+When reading a script, the session stops at the first failed command unless
+--keep-going is given.
+
+Examples:
+  # Interactive session against a staging server
+  es shell -s http://staging:8000
+
+  # Run a script of commands
+  es shell --file seed.es
+
+  # Pipe commands in
+  printf 'topic list\nconsumer list\n' | es shell -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		var in io.Reader = os.Stdin
+		interactive := term.IsTerminal(int(os.Stdin.Fd()))
+		if shellFile != "" {
+			file, err := os.Open(shellFile)
+			if err != nil {
+				return fmt.Errorf("failed to open script: %w", err)
+			}
+			defer file.Close()
+			in = file
+			interactive = false
+		}
+
+		keepGoing := shellKeepGoing
+		globals := sessionFlags()
+		sessionClients = map[string]*client.Client{}
+		sessionTopicCache = shellTopicCache
+		defer func() { sessionClients = nil }()
+
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		failed := 0
+		for lineNumber := 1; ; lineNumber++ {
+			if interactive {
+				fmt.Fprint(os.Stderr, "es> ")
+			}
+			if !scanner.Scan() {
+				break
+			}
+
+			words, err := shell.Split(scanner.Text())
+			if err == nil && len(words) > 0 && (words[0] == "shell" || words[0] == "es") {
+				err = fmt.Errorf("'%s' cannot be run inside a session; enter commands without the 'es' prefix", words[0])
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: line %d: %v\n", lineNumber, err)
+				failed++
+				if !interactive && !keepGoing {
+					return fmt.Errorf("script stopped at line %d", lineNumber)
+				}
+				continue
+			}
+			if len(words) == 0 {
+				continue
+			}
+			if words[0] == "exit" || words[0] == "quit" {
+				break
+			}
+
+			resetFlags(rootCmd)
+			rootCmd.SetArgs(append(append([]string{}, globals...), words...))
+			if run() != 0 {
+				failed++
+				if !interactive && !keepGoing {
+					return CheckFailed(fmt.Errorf("script stopped at line %d", lineNumber))
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read commands: %w", err)
+		}
+		if interactive {
+			fmt.Fprintln(os.Stderr)
+		}
+
+		if failed > 0 && !interactive {
+			return CheckFailed(fmt.Errorf("%d command(s) failed", failed))
+		}
+		return nil
+	},
+}
+
+// sessionFlags returns the global flags set on the shell command, to pass to every
+// command of the session
+func sessionFlags() []string {
+	var globals []string
+	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		// Flags set from a topic's defaults aren't marked changed
+		if !f.Changed && f.Value.String() == f.DefValue {
+			return
+		}
+		if f.Value.Type() == "count" {
+			for i := 0; i < verbosity; i++ {
+				globals = append(globals, "--"+f.Name)
+			}
+			return
+		}
+		globals = append(globals, "--"+f.Name+"="+f.Value.String())
+	})
+	return globals
+}
+
+// resetFlags returns every flag of c and its subcommands to its default, so a value
+// given to one command of a session does not leak into the next
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		// Flags set from a topic's defaults aren't marked changed
+		if !f.Changed && f.Value.String() == f.DefValue {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok && f.DefValue == "[]" {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	c.PersistentFlags().VisitAll(reset)
+	c.Flags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+	DisablePager(shellCmd)
+	shellCmd.Flags().StringVar(&shellFile, "file", "", "Read commands from this script instead of stdin")
+	shellCmd.Flags().BoolVar(&shellKeepGoing, "keep-going", false, "Keep running a script after a command fails")
+	shellCmd.Flags().DurationVar(&shellTopicCache, "topic-cache", 5*time.Second, "How long to cache topic metadata (0 to disable)")
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// sinkCmd represents the sink command
+var sinkCmd = &cobra.Command{
+	Use:   "sink",
+	Short: "Stream events into external databases",
+	Long:  `Stream the events of topics into external databases for reporting and search, keeping a checkpoint there so each run carries on where the last one stopped.`,
+}
+
+// SinkCmd returns the sink command for use in subcommands
+func SinkCmd() *cobra.Command {
+	return sinkCmd
+}
+
+func init() {
+	rootCmd.AddCommand(sinkCmd)
+}
@@ -0,0 +1,122 @@
+package sink
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/elasticsearch"
+	"github.com/event-store/cli/internal/eventsync"
+	"github.com/spf13/cobra"
+)
+
+var (
+	elasticsearchURL         string
+	elasticsearchTopics      []string
+	elasticsearchIndex       string
+	elasticsearchUser        string
+	elasticsearchPassword    string
+	elasticsearchAPIKey      string
+	elasticsearchTLSInsecure bool
+	elasticsearchBatchSize   int
+	elasticsearchMaxRetries  int
+	elasticsearchNoTemplate  bool
+)
+
+var elasticsearchCmd = &cobra.Command{
+	Use:     "elasticsearch --url <url> --topic <topic>",
+	Aliases: []string{"opensearch"},
+	Short:   "Index events into Elasticsearch or OpenSearch",
+	Long: `Index the events of topics into Elasticsearch or OpenSearch for full-text search and
+dashboards. Each event is a document, with the event's ID as its ID:
+
+  {"topic": ..., "id": ..., "timestamp": ..., "type": ..., "payload": {...},
+   "correlationId": ..., "causationId": ..., "metadata": {...}}
+
+--index names the index of each event, with {topic}, {type}, {date} (the event's day,
+YYYY.MM.DD) and {month} (YYYY.MM) filled in from the event: events-{topic}-{month}
+gives an index per topic and month. Names are lower-cased.
+
+Before indexing a topic an index template is created for its indexes, es-sink-<pattern>,
+mapping the document's fields, and the payload fields in the topic's schemas: strings
+as text with a keyword subfield (dates for the date-time format), integers as longs,
+numbers as doubles, booleans as booleans. Fields the schemas type differently, and
+fields without schemas, are mapped by the cluster as they appear. --no-template leaves
+mappings to the cluster entirely. A template applies to indexes made after it, so
+reindex existing ones to take it up.
+
+Events are sent in bulk requests of --batch-size. Requests and documents the cluster
+rejects for backpressure (HTTP 429, or 502, 503 or 504 for requests) are retried up to
+--max-retries times with exponential backoff; a document rejected otherwise, such as
+for a mapping conflict, stops the sink.
+
+The es-sink-checkpoints index holds the last event indexed from each topic into each
+index pattern, saved after its events are indexed, so each run carries on where the
+last one stopped; events indexed just before a failure are indexed again, replacing the
+same documents. With --follow the topics are polled every --interval until stopped.
+--reset indexes topics from the start again.
+
+The password is read from --password or the ELASTICSEARCH_PASSWORD environment
+variable, and the API key from --api-key or ELASTICSEARCH_API_KEY.
+
+Examples:
+  # Index orders, an index per month
+  es sink elasticsearch --url http://localhost:9200 --topic orders --index 'events-{topic}-{month}'
+
+  # Keep two topics indexed in a secured cluster
+  ELASTICSEARCH_API_KEY=... es sink elasticsearch --url https://search:9200 \
+    --topic orders --topic payments --follow`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		if elasticsearchBatchSize < 1 {
+			return fmt.Errorf("batch size must be positive")
+		}
+		if !strings.HasPrefix(elasticsearchURL, "http://") && !strings.HasPrefix(elasticsearchURL, "https://") {
+			return fmt.Errorf("invalid --url '%s' (expected http:// or https://)", elasticsearchURL)
+		}
+		config := elasticsearch.Config{
+			URL:        elasticsearchURL,
+			Username:   elasticsearchUser,
+			Password:   elasticsearchPassword,
+			APIKey:     elasticsearchAPIKey,
+			MaxRetries: elasticsearchMaxRetries,
+		}
+		if config.Password == "" {
+			config.Password = os.Getenv("ELASTICSEARCH_PASSWORD")
+		}
+		if config.APIKey == "" {
+			config.APIKey = os.Getenv("ELASTICSEARCH_API_KEY")
+		}
+		if elasticsearchTLSInsecure {
+			config.TLS = &tls.Config{InsecureSkipVerify: true}
+		}
+
+		target, err := eventsync.OpenElasticsearch(elasticsearch.New(config), elasticsearchIndex, strings.TrimSuffix(cfg.Server.URL, "/"), elasticsearchBatchSize, !elasticsearchNoTemplate)
+		if err != nil {
+			return err
+		}
+		defer target.Close()
+		return cmd.Sync(target, elasticsearchTopics)
+	},
+}
+
+func init() {
+	cmd.SinkCmd().AddCommand(elasticsearchCmd)
+	cmd.DisablePager(elasticsearchCmd)
+	elasticsearchCmd.Flags().StringVar(&elasticsearchURL, "url", "", "Cluster URL, e.g. http://localhost:9200 (required)")
+	elasticsearchCmd.Flags().StringArrayVar(&elasticsearchTopics, "topic", nil, "Topic to index (repeatable; required)")
+	elasticsearchCmd.Flags().StringVar(&elasticsearchIndex, "index", "events-{topic}", "Index name, with {topic}, {type}, {date} or {month} filled in from each event")
+	elasticsearchCmd.Flags().StringVar(&elasticsearchUser, "username", "", "Authenticate with this user")
+	elasticsearchCmd.Flags().StringVar(&elasticsearchPassword, "password", "", "Password of --username (default: $ELASTICSEARCH_PASSWORD)")
+	elasticsearchCmd.Flags().StringVar(&elasticsearchAPIKey, "api-key", "", "Authenticate with this base64 API key (default: $ELASTICSEARCH_API_KEY)")
+	elasticsearchCmd.Flags().BoolVar(&elasticsearchTLSInsecure, "tls-insecure", false, "Don't verify the cluster's TLS certificate")
+	elasticsearchCmd.Flags().IntVar(&elasticsearchBatchSize, "batch-size", 500, "Events per bulk request")
+	elasticsearchCmd.Flags().IntVar(&elasticsearchMaxRetries, "max-retries", 5, "Retries of requests and documents rejected for backpressure")
+	elasticsearchCmd.Flags().BoolVar(&elasticsearchNoTemplate, "no-template", false, "Don't create index templates from the topics' schemas")
+	cmd.AddSyncFlags(elasticsearchCmd)
+	elasticsearchCmd.MarkFlagRequired("url")
+	elasticsearchCmd.MarkFlagRequired("topic")
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// spoolCmd represents the spool command
+var spoolCmd = &cobra.Command{
+	Use:   "spool",
+	Short: "Manage events scheduled for later publishing",
+	Long:  `List, publish and cancel the events 'es event publish --publish-at' or '--delay' kept in the local spool (~/.es/spool) for servers that can't schedule events themselves.`,
+}
+
+// SpoolCmd returns the spool command for use in subcommands
+func SpoolCmd() *cobra.Command {
+	return spoolCmd
+}
+
+func init() {
+	rootCmd.AddCommand(spoolCmd)
+}
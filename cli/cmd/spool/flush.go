@@ -0,0 +1,100 @@
+package spool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/spool"
+	"github.com/event-store/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flushAllServers bool
+	flushFollow     bool
+	flushInterval   time.Duration
+)
+
+var flushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Publish spooled events that are due",
+	Long: `Publish the spooled events that are due, each entry to the server it was spooled for,
+and remove them from the spool. With --follow the spool is checked every --interval
+until stopped, so events are published close to their time; run it as a service next to
+anything that schedules events.
+
+An entry that fails to publish stays spooled, and its server's later entries wait for
+it. Without --follow the command then exits with an error; with it the entry is retried
+at the next check.
+
+Examples:
+  # Publish what is due now
+  es spool flush
+
+  # Keep publishing events as they become due, for every server
+  es spool flush --follow --all-servers`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		server := spoolServer(flushAllServers)
+		clientFor := func(server string) *client.Client { return cmd.NewClientFor(server) }
+
+		if !flushFollow {
+			flushed, err := spool.Flush(context.Background(), server, time.Now(), clientFor)
+			if cfg.Output.Format == "json" {
+				if flushed == nil {
+					flushed = []*spool.Entry{}
+				}
+				if printErr := output.PrintJSON(flushed); printErr != nil {
+					return printErr
+				}
+			} else {
+				printFlushed(flushed)
+				if len(flushed) == 0 && err == nil {
+					fmt.Println("No spooled events are due")
+				}
+			}
+			return err
+		}
+
+		if flushInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+		group.Go("spool-flush", func(ctx context.Context) error {
+			return watch.Loop(ctx, flushInterval, func() error {
+				flushed, err := spool.Flush(ctx, server, time.Now(), clientFor)
+				printFlushed(flushed)
+				if err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "[%s] %v\n", time.Now().Format(time.RFC3339), err)
+				}
+				return nil
+			})
+		})
+		return group.Wait()
+	},
+}
+
+// printFlushed prints a line per published spool entry
+func printFlushed(entries []*spool.Entry) {
+	for _, entry := range entries {
+		fmt.Printf("[%s] Published %d event(s) to %s (%s) from %s, due %s\n", time.Now().Format(time.RFC3339), len(entry.Events), entry.Server, strings.Join(entry.Topics(), ", "), entry.ID, entry.PublishAt.Format(time.RFC3339))
+	}
+}
+
+func init() {
+	cmd.SpoolCmd().AddCommand(flushCmd)
+	cmd.DisablePager(flushCmd)
+	flushCmd.Flags().BoolVar(&flushAllServers, "all-servers", false, "Publish the due entries of every server, not just the event store's")
+	flushCmd.Flags().BoolVarP(&flushFollow, "follow", "f", false, "Keep publishing events as they become due until stopped")
+	flushCmd.Flags().DurationVar(&flushInterval, "interval", 10*time.Second, "How often --follow checks the spool")
+}
@@ -0,0 +1,69 @@
+package spool
+
+import (
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/spool"
+	"github.com/spf13/cobra"
+)
+
+var listAllServers bool
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List spooled events",
+	Long: `List the spool entries waiting to be published to the event store, soonest due first.
+Each entry holds the events of one 'es event publish' due at the same time.
+
+Examples:
+  # List the events spooled for the event store
+  es spool list
+
+  # For every server
+  es spool list --all-servers`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		entries, err := spool.List(spoolServer(listAllServers))
+		if err != nil {
+			return err
+		}
+		if entries == nil {
+			entries = []*spool.Entry{}
+		}
+
+		if cmd.Quiet() {
+			ids := make([]string, len(entries))
+			for i, entry := range entries {
+				ids[i] = entry.ID
+			}
+			output.PrintIDs(ids)
+			return nil
+		}
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(entries)
+		case "csv":
+			return output.PrintSpoolEntriesCSV(entries)
+		default:
+			output.PrintSpoolEntries(entries)
+			return nil
+		}
+	},
+}
+
+// spoolServer returns the server whose entries a command works on: the event store's
+// URL, or "" for every server
+func spoolServer(allServers bool) string {
+	if allServers {
+		return ""
+	}
+	return strings.TrimSuffix(cmd.GetConfig().Server.URL, "/")
+}
+
+func init() {
+	cmd.SpoolCmd().AddCommand(listCmd)
+	listCmd.Flags().BoolVar(&listAllServers, "all-servers", false, "List the entries of every server, not just the event store's")
+}
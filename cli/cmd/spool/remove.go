@@ -0,0 +1,36 @@
+package spool
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/spool"
+	"github.com/spf13/cobra"
+)
+
+var removeCmd = &cobra.Command{
+	Use:   "remove <entry-id>...",
+	Short: "Cancel spooled events",
+	Long: `Remove entries from the spool, so their events are never published. Entry IDs are
+listed by 'es spool list'.
+
+Examples:
+  # Cancel a scheduled publish
+  es spool remove 20250601T090000Z-3f2a9c1d`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		for _, id := range args {
+			if err := spool.Remove(id); err != nil {
+				return err
+			}
+			if !cmd.Quiet() {
+				fmt.Printf("Removed spool entry %s\n", id)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.SpoolCmd().AddCommand(removeCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"github.com/event-store/cli/internal/monitor"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show an overview of the event store",
+	Long: `Show the event store's health, its topics and their sequences, and its consumers with
+how many events each has yet to receive, in one view.
+
+A consumer's lag in a topic is the number of events published after the last event
+delivered to it. The consumers are listed with the most lagging first.
+
+Examples:
+  # Overview of the configured server
+  es status
+
+  # Machine-readable overview
+  es status --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		apiClient := NewClient()
+
+		health, err := apiClient.GetHealth()
+		if err != nil {
+			return err
+		}
+		topics, err := apiClient.GetTopics()
+		if err != nil {
+			return err
+		}
+		consumers, err := apiClient.GetConsumers()
+		if err != nil {
+			return err
+		}
+
+		status := monitor.BuildStatus(cfg.Server.URL, health, topics, consumers)
+		if info, err := apiClient.GetServerInfo(); err == nil {
+			status.ServerVersion = info.Version
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintStatusJSON(status)
+		case "csv":
+			return output.PrintStatusCSV(status)
+		default:
+			output.PrintStatus(status)
+			return nil
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/eventsync"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncFilter   string
+	syncFollow   bool
+	syncInterval time.Duration
+	syncReset    bool
+)
+
+// AddSyncFlags adds --filter, --follow, --interval and --reset to a command that syncs
+// topics into a database
+func AddSyncFlags(c *cobra.Command) {
+	c.Flags().StringVar(&syncFilter, "filter", "", "Only sync events matching these filters, e.g. 'type=order.placed AND payload.region=eu'")
+	c.Flags().BoolVarP(&syncFollow, "follow", "f", false, "Keep syncing new events until stopped")
+	c.Flags().DurationVar(&syncInterval, "interval", 5*time.Second, "How often --follow polls the topics")
+	c.Flags().BoolVar(&syncReset, "reset", false, "Forget the topics' checkpoints and sync them from the start, replacing their rows")
+}
+
+// Sync syncs topics into a target until they have caught up, or with --follow until
+// stopped, reporting progress on stderr. Errors while following are reported and
+// retried at the next poll.
+func Sync(target eventsync.Target, topics []string) error {
+	cfg := GetConfig()
+	apiClient := NewClient()
+
+	var filters []*filter.Filter
+	if syncFilter != "" {
+		parsed, err := filter.ParseAll(syncFilter)
+		if err != nil {
+			return err
+		}
+		filters = parsed
+	}
+	if syncReset {
+		for _, topic := range topics {
+			if err := target.Reset(topic); err != nil {
+				return err
+			}
+		}
+	}
+
+	report := func(topic string, stats eventsync.Stats) {
+		if !Quiet() {
+			fmt.Fprintf(os.Stderr, "[%s] %s: %d event(s) read, %d written\n", time.Now().Format(time.RFC3339), topic, stats.Events, stats.Rows)
+		}
+	}
+	pass := func(ctx context.Context) error {
+		for _, topic := range topics {
+			stats, err := eventsync.Sync(ctx, apiClient, target, topic, filters)
+			if stats.Events > 0 || (err == nil && !syncFollow) {
+				report(topic, stats)
+			}
+			if err != nil {
+				return fmt.Errorf("topic '%s': %w", topic, err)
+			}
+		}
+		return nil
+	}
+
+	if !syncFollow {
+		return pass(context.Background())
+	}
+	if syncInterval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	group, err := NewRunner()
+	if err != nil {
+		return err
+	}
+	server := strings.TrimSuffix(cfg.Server.URL, "/")
+	group.Go("sync", func(ctx context.Context) error {
+		return watch.Loop(ctx, syncInterval, func() error {
+			if err := pass(ctx); err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "[%s] %s: %v\n", time.Now().Format(time.RFC3339), server, err)
+			}
+			return nil
+		})
+	})
+	return group.Wait()
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run integration tests against the event store",
+	Long:  `Run given/when/then specs that seed events, trigger an action and check which events it causes.`,
+}
+
+// TestCmd returns the test command for use in subcommands
+func TestCmd() *cobra.Command {
+	return testCmd
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	DisablePager(testCmd)
+}
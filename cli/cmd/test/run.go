@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+var runPattern string
+
+var runCmd = &cobra.Command{
+	Use:   "run <spec.yaml>...",
+	Short: "Run given/when/then event specs",
+	Long: `Run the tests in one or more YAML spec files. Each test:
+
+  given  publishes seed events
+  when   runs a shell command (exec) or calls an HTTP endpoint (webhook)
+  then   waits for the expected events to appear on their topics
+
+Only events published after the 'given' events count towards an expectation, so specs
+can be run repeatedly against the same server. An expectation passes once at least
+'count' matching events (default 1) appear within its 'within' time (default: the file's
+'timeout', or 10s). Exec actions get the server URL in ES_SERVER_URL.
+
+Spec format:
+  timeout: 30s
+  tests:
+    - name: order is confirmed
+      given:
+        events:
+          - topic: orders
+            type: order.placed
+            payload: {orderId: "o-1", total: 42}
+      when:
+        webhook:
+          url: http://localhost:8080/orders/o-1/confirm
+          body: {by: "test"}
+      then:
+        - topic: orders
+          type: order.confirmed
+          match: {orderId: "o-1"}
+          within: 5s
+
+The command fails if any test fails.
+
+Examples:
+  # Run a spec file
+  es test run specs/orders.yaml
+
+  # Run only the tests whose names match a pattern
+  es test run specs/*.yaml --run 'confirm'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+
+		var pattern *regexp.Regexp
+		if runPattern != "" {
+			var err error
+			if pattern, err = regexp.Compile(runPattern); err != nil {
+				return fmt.Errorf("invalid --run pattern: %w", err)
+			}
+		}
+
+		files := make([]*spec.File, len(args))
+		for i, path := range args {
+			file, err := spec.Load(path)
+			if err != nil {
+				return err
+			}
+			files[i] = file
+		}
+
+		group, err := cmd.NewRunner()
+		if err != nil {
+			return err
+		}
+
+		runner := &spec.Runner{Client: cmd.NewClient(), ServerURL: cfg.Server.URL}
+		var results []spec.Result
+		group.Go("test-run", func(ctx context.Context) error {
+			defer group.Stop()
+			for i, file := range files {
+				for _, test := range file.Tests {
+					if pattern != nil && !pattern.MatchString(test.Name) {
+						continue
+					}
+					if ctx.Err() != nil {
+						return nil
+					}
+					result := runner.Run(ctx, test)
+					result.Spec = args[i]
+					results = append(results, result)
+					if cfg.Output.Format != "json" && cfg.Output.Format != "csv" {
+						output.PrintTestResult(result)
+					}
+				}
+			}
+			return nil
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, result := range results {
+			if !result.Passed {
+				failed++
+			}
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			if results == nil {
+				results = []spec.Result{}
+			}
+			if err := output.PrintJSON(results); err != nil {
+				return err
+			}
+		case "csv":
+			if err := output.PrintTestResultsCSV(results); err != nil {
+				return err
+			}
+		default:
+			fmt.Printf("\n%d passed, %d failed\n", len(results)-failed, failed)
+		}
+
+		if len(results) == 0 {
+			return fmt.Errorf("no tests matched")
+		}
+		if failed > 0 {
+			return cmd.CheckFailed(fmt.Errorf("%d of %d test(s) failed", failed, len(results)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.TestCmd().AddCommand(runCmd)
+	runCmd.Flags().StringVar(&runPattern, "run", "", "Only run tests whose names match this regular expression")
+}
@@ -0,0 +1,182 @@
+package topic
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/archive"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveBefore   string
+	archiveDest     string
+	archiveTruncate bool
+	archiveConfirm  string
+	archiveSSE      string
+	archiveSSEKey   string
+	unarchiveFrom   string
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive <name>",
+	Short: "Move a topic's old events to cold storage",
+	Long: `Archive the events of a topic older than --before to compressed segment files with
+an index and a manifest, in the topic's cold tier, and with --truncate delete them from
+the event store afterwards. This is 'es topic tier push' followed, optionally, by
+'es topic truncate' of everything archived so far.
+
+--before is a date (YYYY-MM-DD), an RFC 3339 timestamp or an event ID. --dest is a
+directory, a file:// URL or an object storage URL (s3://, gs:// or azblob://) and only
+has to be given the first time. Archived events can still be listed with
+'es event list --include-archive'.
+
+Truncating has to be confirmed by typing the topic name, or with --confirm <name> in
+scripts. Needs a server that advertises the 'topic-truncate' feature.
+
+Examples:
+  # Archive everything before 2024 to a local directory
+  es topic archive user-events --before 2024-01-01 --dest /mnt/archive
+
+  # Archive to S3, encrypted with a KMS key
+  es topic archive user-events --before 2024-01-01 --dest s3://archives/events --sse aws:kms --sse-key alias/archives
+
+  # Archive and then delete the archived events, from a script
+  es topic archive user-events --before 2024-01-01 --truncate --confirm user-events`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		before, err := parseTierCutoff(archiveBefore)
+		if err != nil {
+			return err
+		}
+		if archiveTruncate {
+			if err := requireFeature(apiClient, client.FeatureTopicTruncate, "truncating topics"); err != nil {
+				return err
+			}
+		}
+
+		manifest, events, err := pushTier(apiClient, cfg.Server.URL, topicName, before, archiveDest, encryptionFlags(archiveSSE, archiveSSEKey))
+		if err != nil {
+			return err
+		}
+
+		var truncated *client.TruncateResult
+		if archiveTruncate && manifest.LastEventID() != "" {
+			if truncated, err = truncateArchived(apiClient, manifest); err != nil {
+				return err
+			}
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(map[string]interface{}{"tier": manifest, "truncated": truncated})
+		case "csv":
+			return output.PrintTierManifestCSV(manifest)
+		default:
+			if len(events) == 0 {
+				fmt.Println("No new events to archive")
+			} else {
+				fmt.Printf("Archived %d event(s) of '%s' (%s to %s)\n", len(events), topicName, events[0].ID, events[len(events)-1].ID)
+			}
+			if truncated != nil {
+				output.PrintTruncateResult(truncated)
+			}
+			fmt.Println()
+			output.PrintTierManifest(manifest)
+			return nil
+		}
+	},
+}
+
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive <name>",
+	Short: "Attach a topic's archive so its events can be read back",
+	Long: `Attach an archive written by 'es topic archive' or 'es topic tier push', such as
+one copied from another machine, by reading the manifest stored with its segments.
+'es event list --include-archive' then reads the archived events together with those
+still in the event store.
+
+Examples:
+  es topic unarchive user-events --from /mnt/archive
+  es event list user-events --include-archive --until 2024-01-01`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		topicName := args[0]
+
+		store, err := archive.Open(unarchiveFrom)
+		if err != nil {
+			return err
+		}
+		manifest, err := archive.ReadStoreManifest(store, topicName)
+		if err != nil {
+			return err
+		}
+		manifest.Server = cfg.Server.URL
+		manifest.Location = store.URL()
+		if manifest.Encryption != nil {
+			// Write the manifest back as the archive's segments were written
+			if store, err = archive.OpenEncrypted(unarchiveFrom, manifest.Encryption); err != nil {
+				return err
+			}
+		}
+		if err := archive.SaveTierManifest(store, manifest); err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintTierManifestJSON(manifest)
+		case "csv":
+			return output.PrintTierManifestCSV(manifest)
+		default:
+			fmt.Printf("Attached the archive of '%s' (%d event(s))\n\n", topicName, manifest.Count())
+			output.PrintTierManifest(manifest)
+			return nil
+		}
+	},
+}
+
+// truncateArchived deletes the events of a topic up to the last one archived, once the
+// user has confirmed how many that is
+func truncateArchived(apiClient *client.Client, manifest *archive.TierManifest) (*client.TruncateResult, error) {
+	last, err := eventid.Parse(manifest.LastEventID())
+	if err != nil {
+		return nil, err
+	}
+	last.Sequence++
+	req := client.TruncateRequest{BeforeEventID: last.String(), DryRun: true}
+
+	result, err := apiClient.TruncateTopic(manifest.Topic, req)
+	if err != nil || result.Deleted == 0 {
+		return result, err
+	}
+	what := fmt.Sprintf("%d archived event(s) of '%s' will be deleted from the event store", result.Deleted, manifest.Topic)
+	if err := cmd.ConfirmName(what, manifest.Topic, archiveConfirm); err != nil {
+		return nil, err
+	}
+	req.DryRun = false
+	return apiClient.TruncateTopic(manifest.Topic, req)
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(archiveCmd)
+	cmd.TopicCmd().AddCommand(unarchiveCmd)
+	archiveCmd.Flags().StringVar(&archiveBefore, "before", "", "Archive events before this date (YYYY-MM-DD), timestamp or event ID (required)")
+	archiveCmd.Flags().StringVar(&archiveDest, "dest", "", "Archive location: a directory, file:// URL or s3://, gs:// or azblob:// URL (default: the location of earlier archives)")
+	archiveCmd.Flags().StringVar(&archiveSSE, "sse", "", "S3 server-side encryption: AES256 or aws:kms (default: as for earlier archives)")
+	archiveCmd.Flags().StringVar(&archiveSSEKey, "sse-key", "", "KMS key for S3 or GCS, or encryption scope for Azure")
+	archiveCmd.Flags().BoolVar(&archiveTruncate, "truncate", false, "Delete the archived events from the event store afterwards")
+	archiveCmd.Flags().StringVar(&archiveConfirm, "confirm", "", "Confirm --truncate without being asked, by giving the topic name")
+	archiveCmd.MarkFlagRequired("before")
+	cmd.DisablePager(archiveCmd)
+	unarchiveCmd.Flags().StringVar(&unarchiveFrom, "from", "", "Archive location: a directory, file:// URL or s3://, gs:// or azblob:// URL (required)")
+	unarchiveCmd.MarkFlagRequired("from")
+}
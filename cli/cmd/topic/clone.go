@@ -0,0 +1,195 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/copier"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cloneToContext  string
+	cloneToServer   string
+	cloneAs         string
+	cloneWithEvents bool
+	cloneLast       int
+	cloneDropFields []string
+	cloneUpdate     bool
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <name>",
+	Short: "Recreate a topic on another server",
+	Long: `Recreate a topic's schemas on another server, a configured context (--to-context) or a
+server URL (--to-server), and optionally copy a sample of its most recent events, for
+reproducing production issues locally.
+
+The topic is created on the destination, or with --update has its schemas replaced if
+it already exists there. With --with-events the last --last events are republished to
+it in order; they get new IDs and timestamps. --drop-field removes payload fields, such
+as personal data, from the copied events. Partitions aren't cloned.
+
+Examples:
+  # Recreate the orders topic of prod on staging
+  es --context prod topic clone orders --to-context staging
+
+  # With its last 1000 events, on a local event store
+  es --context prod topic clone orders --to-server http://localhost:8000 --with-events
+
+  # A smaller sample under another name, without customers' emails
+  es --context prod topic clone orders --to-context dev --as orders-repro \
+    --with-events --last 200 --drop-field customer.email`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		name := args[0]
+		destName := name
+		if cloneAs != "" {
+			destName = cloneAs
+		}
+
+		var destServer string
+		switch {
+		case cloneToContext != "" && cloneToServer != "":
+			return fmt.Errorf("--to-context and --to-server can't be used together")
+		case cloneToContext != "":
+			server, err := cfg.Context(cloneToContext)
+			if err != nil {
+				return err
+			}
+			destServer = server.URL
+		case cloneToServer != "":
+			destServer = cloneToServer
+		default:
+			return fmt.Errorf("--to-context or --to-server is required")
+		}
+		if cloneLast < 1 {
+			return fmt.Errorf("--last must be at least 1")
+		}
+		if strings.TrimSuffix(destServer, "/") == strings.TrimSuffix(cfg.Server.URL, "/") && destName == name {
+			return fmt.Errorf("the destination is the topic itself; use --as to clone it under another name")
+		}
+
+		source := cmd.NewClient()
+		dest := cmd.NewClientFor(destServer)
+		topic, err := source.GetTopic(name)
+		if err != nil {
+			return err
+		}
+		if topic.Partitions > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: '%s' has %d partitions; the clone is created unpartitioned\n", name, topic.Partitions)
+		}
+
+		_, err = dest.GetTopic(destName)
+		var apiErr *client.APIError
+		created := errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+		switch {
+		case created:
+			if err := dest.CreateTopic(destName, topic.Schemas); err != nil {
+				return fmt.Errorf("failed to create '%s' on %s: %w", destName, destServer, err)
+			}
+		case err != nil:
+			return fmt.Errorf("destination topic '%s': %w", destName, err)
+		case !cloneUpdate:
+			return fmt.Errorf("topic '%s' already exists on %s; use --update to replace its schemas", destName, destServer)
+		default:
+			if err := dest.UpdateTopicSchemas(destName, topic.Schemas); err != nil {
+				return fmt.Errorf("failed to update the schemas of '%s' on %s: %w", destName, destServer, err)
+			}
+		}
+
+		var result *copier.Result
+		if cloneWithEvents {
+			if result, err = cloneEvents(source, dest, topic, destName); err != nil {
+				return err
+			}
+			result.DestServer = destServer
+		}
+
+		action := "Created"
+		if !created {
+			action = "Updated"
+		}
+		message := fmt.Sprintf("%s topic '%s' on %s with %d schema(s)", action, destName, destServer, len(topic.Schemas))
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(map[string]interface{}{
+				"topic":   destName,
+				"server":  destServer,
+				"created": created,
+				"schemas": len(topic.Schemas),
+				"events":  result,
+			})
+		case "csv":
+			if result != nil {
+				return output.PrintCopyResultCSV(result)
+			}
+			return output.PrintMessageCSV(message)
+		default:
+			output.PrintMessage(message)
+			if result != nil {
+				output.PrintCopyResult(result)
+			}
+			return nil
+		}
+	},
+}
+
+// cloneEvents republishes the last --last events of a topic to its clone
+func cloneEvents(source, dest *client.Client, topic *client.Topic, destName string) (*copier.Result, error) {
+	through := int64(topic.Sequence)
+	after := max(through-int64(cloneLast), 0)
+
+	group, err := cmd.NewRunner()
+	if err != nil {
+		return nil, err
+	}
+	var result *copier.Result
+	var copyErr error
+	progress := output.NewProgress("Copying", int(through-after))
+	group.Go("topic-clone", func(ctx context.Context) error {
+		result, copyErr = copier.Copy(ctx, copier.Options{
+			Source:      source,
+			Destination: dest,
+			SourceTopic: topic.Name,
+			DestTopic:   destName,
+			After:       after,
+			Through:     through,
+			DropFields:  cloneDropFields,
+			BatchSize:   100,
+		}, progress.Add)
+		group.Stop()
+		return nil
+	})
+	err = group.Wait()
+	progress.Finish()
+	if err != nil {
+		return nil, err
+	}
+	if copyErr != nil {
+		if errors.Is(copyErr, context.Canceled) {
+			return nil, fmt.Errorf("copying events interrupted after %d event(s)", result.Copied)
+		}
+		return nil, fmt.Errorf("the topic was cloned, but copying its events failed after %d event(s): %w", result.Copied, copyErr)
+	}
+	return result, nil
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(cloneCmd)
+	cloneCmd.Flags().StringVar(&cloneToContext, "to-context", "", "Context to clone the topic to, as configured in the config file")
+	cloneCmd.Flags().StringVar(&cloneToServer, "to-server", "", "Server URL to clone the topic to, instead of a context")
+	cloneCmd.Flags().StringVar(&cloneAs, "as", "", "Name of the clone (default: the topic's name)")
+	cloneCmd.Flags().BoolVar(&cloneWithEvents, "with-events", false, "Also copy the topic's most recent events")
+	cloneCmd.Flags().IntVar(&cloneLast, "last", 1000, "Number of recent events to copy with --with-events")
+	cloneCmd.Flags().StringSliceVar(&cloneDropFields, "drop-field", nil, "Payload field (dotted path) to remove from copied events (repeatable)")
+	cloneCmd.Flags().BoolVar(&cloneUpdate, "update", false, "Replace the schemas of the topic if it already exists on the destination")
+}
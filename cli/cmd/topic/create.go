@@ -5,24 +5,31 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/spf13/cobra"
 	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/annotations"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/labels"
 	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
 )
 
 var (
-	createName       string
+	createName        string
 	createSchemasFile string
+	createLabels      []string
 )
 
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new topic",
-	Long:  `Create a new topic with schemas. Schemas define the structure of events for the topic.`,
+	Long: `Create a new topic with schemas. Schemas define the structure of events for the topic.
+
+Labels given with --label are kept by event stores with the 'topic-labels' feature, and
+otherwise in ~/.es/annotations/<server host>.json, so 'es topic list --selector' can
+select topics by them.`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
 		if createName == "" {
 			return fmt.Errorf("topic name is required (use --name)")
@@ -48,18 +55,37 @@ var createCmd = &cobra.Command{
 			return fmt.Errorf("at least one schema is required")
 		}
 
-		// Create topic
-		if err := apiClient.CreateTopic(createName, schemas); err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
+		topicLabels, err := labels.Parse(createLabels)
+		if err != nil {
+			return err
+		}
+
+		// Create topic, keeping its labels in the sidecar if the server can't
+		req := client.TopicCreationRequest{Name: createName, Schemas: schemas}
+		var sidecar *annotations.Sidecar
+		switch {
+		case len(topicLabels) == 0:
+		case apiClient.Supports(client.FeatureTopicLabels):
+			req.Labels = topicLabels
+		default:
+			if sidecar, err = annotations.Open(apiClient.BaseURL()); err != nil {
+				return err
 			}
-			output.PrintError(err)
+		}
+		if err := apiClient.CreateTopicRequest(req); err != nil {
 			return err
 		}
+		if sidecar != nil {
+			sidecar.SetTopic(createName, annotations.Annotations{Labels: topicLabels})
+			if err := sidecar.Save(); err != nil {
+				return fmt.Errorf("topic '%s' was created, but its labels weren't kept: %w", createName, err)
+			}
+		}
 
+		if cmd.Quiet() {
+			output.PrintIDs([]string{createName})
+			return nil
+		}
 		message := fmt.Sprintf("Topic '%s' created successfully", createName)
 		switch cfg.Output.Format {
 		case "json":
@@ -77,6 +103,7 @@ func init() {
 	cmd.TopicCmd().AddCommand(createCmd)
 	createCmd.Flags().StringVar(&createName, "name", "", "Topic name (required)")
 	createCmd.Flags().StringVar(&createSchemasFile, "schemas-file", "", "Path to JSON file containing schemas array (required)")
+	cmd.AddLabelFlag(createCmd, &createLabels)
 	createCmd.MarkFlagRequired("name")
 	createCmd.MarkFlagRequired("schemas-file")
 }
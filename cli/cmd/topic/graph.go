@@ -0,0 +1,119 @@
+package topic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/stats"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphInterval time.Duration
+	graphSince    string
+	graphUntil    string
+	graphStyle    string
+	graphHeight   int
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph <topic>",
+	Short: "Chart a topic's events over time",
+	Long: `Chart the number of events published to a topic in each interval of a period, in the
+terminal: as bars, or as a line of braille dots with --style line.
+
+The period is the last 24 hours unless --since and --until say otherwise, and intervals
+are aligned in UTC: 1h intervals start on the hour. The topic's events in the period are
+read to count them. Adjacent intervals are added together when there are more than fit
+the terminal, unless --wide.
+
+With --output json the series is printed as JSON, the count of every interval, for
+other tooling; with --output csv there is a row per interval.
+
+Examples:
+  # Events per hour over the last day
+  es topic graph orders
+
+  # Events per day over the last month, as a line
+  es topic graph orders --interval 24h --since 30d --style line
+
+  # The series, for other tooling
+  es topic graph orders --interval 5m --since 6h --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		topicName := args[0]
+		if graphStyle != output.ChartBar && graphStyle != output.ChartLine {
+			return fmt.Errorf("invalid --style '%s' (expected bar or line)", graphStyle)
+		}
+		if graphHeight < 2 {
+			return fmt.Errorf("--height must be at least 2")
+		}
+		now := time.Now()
+		timeRange, err := timerange.Parse(graphSince, graphUntil, now)
+		if err != nil {
+			return err
+		}
+		if timeRange.Since.IsZero() {
+			return fmt.Errorf("--since is required: the graph needs a period")
+		}
+		until := timeRange.Until
+		if until.IsZero() {
+			until = now
+		}
+		collector, err := stats.NewSeriesCollector(topicName, timeRange.Since, until, graphInterval)
+		if err != nil {
+			return err
+		}
+
+		apiClient := cmd.NewClient()
+		topic, err := apiClient.GetTopic(topicName)
+		if err != nil {
+			return err
+		}
+		query := client.EventsQuery{}
+		if apiClient.Supports(client.FeatureEventTimeRange) {
+			query.Since = timerange.Format(timeRange.Since)
+			query.Until = timerange.Format(timeRange.Until)
+		}
+		progress := output.NewProgress("Counting", topic.Sequence)
+		err = apiClient.ScanEventsQuery(topicName, query, func(events []client.Event) (bool, error) {
+			for _, event := range events {
+				if timeRange.Past(event.Timestamp) {
+					return false, nil
+				}
+				collector.Add(event)
+			}
+			progress.Add(len(events))
+			return true, nil
+		})
+		progress.Finish()
+		if err != nil {
+			return err
+		}
+		series := collector.Series()
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(series)
+		case "csv":
+			return output.PrintVolumeSeriesCSV(series)
+		default:
+			output.PrintVolumeChart(series, graphStyle, graphHeight)
+			return nil
+		}
+	},
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(graphCmd)
+	graphCmd.Flags().DurationVar(&graphInterval, "interval", time.Hour, "Length of each interval, e.g. 5m, 1h or 24h")
+	graphCmd.Flags().StringVar(&graphSince, "since", "24h", "Start of the period: timestamp, date, 'today', 'yesterday' or a duration ago such as '24h' or '7d'")
+	graphCmd.Flags().StringVar(&graphUntil, "until", "", "End of the period (same formats as --since; default: now)")
+	graphCmd.Flags().StringVar(&graphStyle, "style", output.ChartBar, "Chart style: bar or line (braille)")
+	graphCmd.Flags().IntVar(&graphHeight, "height", 10, "Height of the chart in lines")
+}
@@ -1,44 +1,133 @@
 package topic
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/event-store/cli/cmd"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/labels"
 	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/watch"
 	"github.com/spf13/cobra"
 )
 
+// topicColumns are the columns of 'es topic list'
+var topicColumns = []output.Column{
+	{Name: "name", Header: "Name"},
+	{Name: "sequence", Header: "Sequence", Numeric: true},
+	{Name: "schemas", Header: "Schema Count", Numeric: true},
+	{Name: "types", Header: "Event Types", Hidden: true},
+	{Name: "labels", Header: "Labels", Hidden: true},
+	{Name: "owner", Header: "Owner", Hidden: true},
+	{Name: "team", Header: "Team", Hidden: true},
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all topics",
-	Long:  `List all topics in the event store.`,
+	Long: `List all topics in the event store.
+
+Examples:
+  # Busiest topics first
+  es topic list --sort-by sequence:desc
+
+  # Only names and event types
+  es topic list --columns name,types
+
+  # Watch topics, highlighting new topics and sequence increments
+  es topic list --watch
+
+  # Topics of every configured context, e.g. dev, staging and prod
+  es topic list --all-contexts
+
+  # The payments team's production topics, with their labels
+  es topic list --selector team=payments,env=prod --columns name,sequence,labels`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
-		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
-		topics, err := apiClient.GetTopics()
-		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
+		if cmd.Watching() {
+			header := output.NewListing(topicColumns)
+			if err := cmd.ApplyListFlags(header); err != nil {
+				return err
 			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
+			return cmd.Watch(cobraCmd, header.Header(), func() ([]watch.Row, error) {
+				topics, err := cmd.ListTopics(apiClient)
+				if err != nil {
+					return nil, err
+				}
+				listing, err := topicListing(topics)
+				if err != nil {
+					return nil, err
+				}
+				return listing.Rows(), nil
+			})
+		}
+
+		if cmd.AllContexts() {
+			listing, failed := cmd.CombineContextListings(topicColumns, func(apiClient *client.Client) (*output.Listing, error) {
+				topics, err := cmd.ListTopics(apiClient)
+				if err != nil {
+					return nil, err
+				}
+				return newTopicListing(topics), nil
+			})
+			if listing == nil {
+				return failed
 			}
-			output.PrintError(err)
-			return err
+			if err := cmd.PrintListing("topics", listing); err != nil {
+				return err
+			}
+			return failed
 		}
 
-		switch cfg.Output.Format {
-		case "json":
-			return output.PrintTopicsListJSON(topics)
-		case "csv":
-			return output.PrintTopicsListCSV(topics)
-		default:
-			output.PrintTopicsList(topics)
-			return nil
+		topics, err := cmd.ListTopics(apiClient)
+		if err != nil {
+			return err
+		}
+		listing, err := topicListing(topics)
+		if err != nil {
+			return err
 		}
+		return cmd.PrintListing("topics", listing)
 	},
 }
 
+// topicListing builds the listing of topics with --columns and --sort-by applied
+func topicListing(topics []client.Topic) (*output.Listing, error) {
+	listing := newTopicListing(topics)
+	return listing, cmd.ApplyListFlags(listing)
+}
+
+// newTopicListing builds the listing of topics
+func newTopicListing(topics []client.Topic) *output.Listing {
+	listing := output.NewListing(topicColumns)
+	for _, topic := range topics {
+		types := make([]string, len(topic.Schemas))
+		for i, schema := range topic.Schemas {
+			types[i] = schema.EventType
+		}
+		var owner client.Owner
+		if topic.Owner != nil {
+			owner = *topic.Owner
+		}
+		listing.Add(topic.Name, topic,
+			topic.Name,
+			strconv.Itoa(topic.Sequence),
+			strconv.Itoa(len(topic.Schemas)),
+			strings.Join(types, ", "),
+			labels.Format(topic.Labels),
+			owner.Owner,
+			owner.Team,
+		)
+	}
+	return listing
+}
+
 func init() {
 	cmd.TopicCmd().AddCommand(listCmd)
+	cmd.AddWatchFlags(listCmd)
+	cmd.AddListFlags(listCmd, topicColumns)
+	cmd.AddAllContextsFlag(listCmd)
+	cmd.AddSelectorFlag(listCmd)
 }
@@ -0,0 +1,207 @@
+package topic
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/annotations"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/owners"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ownerName    string
+	ownerTeam    string
+	ownerContact string
+)
+
+var ownerCmd = &cobra.Command{
+	Use:   "owner",
+	Short: "Record who owns topics",
+	Long: `Set and clear who owns topics: the person accountable for each, their team and how to
+reach them. Event stores with the 'topic-owner' feature keep owners themselves;
+otherwise they are kept in ~/.es/annotations/<server host>.json. 'es topic owners'
+reports them.`,
+}
+
+var ownerSetCmd = &cobra.Command{
+	Use:   "set <topic>...",
+	Short: "Set who owns topics",
+	Long: `Set the owner, team and contact of topics. What isn't given is left as it is, and ''
+clears it. Topics can be given as lists or patterns, such as 'payment-*'.
+
+Examples:
+  # Record the payments team as the owner of its topics
+  es topic owner set 'payment-*' --team payments --owner "Ada Lovelace" --contact "#payments-oncall"
+
+  # Hand a topic to another owner in the same team
+  es topic owner set refunds --owner "Grace Hopper"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		flags := cobraCmd.Flags()
+		if !flags.Changed("owner") && !flags.Changed("team") && !flags.Changed("contact") {
+			return fmt.Errorf("give --owner, --team, --contact or a combination")
+		}
+		names, err := cmd.ResolveTopics(apiClient, args)
+		if err != nil {
+			return err
+		}
+		store, err := openOwnerStore(apiClient)
+		if err != nil {
+			return err
+		}
+
+		topics := make([]client.Topic, 0, len(names))
+		for _, name := range names {
+			owner, err := store.get(name)
+			if err != nil {
+				return err
+			}
+			if flags.Changed("owner") {
+				owner.Owner = ownerName
+			}
+			if flags.Changed("team") {
+				owner.Team = ownerTeam
+			}
+			if flags.Changed("contact") {
+				owner.Contact = ownerContact
+			}
+			if err := store.set(name, owner); err != nil {
+				return err
+			}
+			topics = append(topics, client.Topic{Name: name, Owner: &owner})
+		}
+		if err := store.save(); err != nil {
+			return err
+		}
+		return printOwners(cfg.Output.Format, owners.Build(topics, owners.Options{}))
+	},
+}
+
+var ownerClearCmd = &cobra.Command{
+	Use:   "clear <topic>...",
+	Short: "Clear who owns topics",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		names, err := cmd.ResolveTopics(apiClient, args)
+		if err != nil {
+			return err
+		}
+		store, err := openOwnerStore(apiClient)
+		if err != nil {
+			return err
+		}
+		topics := make([]client.Topic, 0, len(names))
+		for _, name := range names {
+			// Read first, so a topic that doesn't exist is an error
+			if _, err := store.get(name); err != nil {
+				return err
+			}
+			if err := store.set(name, client.Owner{}); err != nil {
+				return err
+			}
+			topics = append(topics, client.Topic{Name: name})
+		}
+		if err := store.save(); err != nil {
+			return err
+		}
+		return printOwners(cfg.Output.Format, owners.Build(topics, owners.Options{}))
+	},
+}
+
+// ownerStore reads and writes topics' owners on the server, or in the annotations
+// sidecar when the server can't keep them
+type ownerStore struct {
+	apiClient *client.Client
+	sidecar   *annotations.Sidecar // nil when the server has FeatureTopicOwner
+}
+
+func openOwnerStore(apiClient *client.Client) (*ownerStore, error) {
+	store := &ownerStore{apiClient: apiClient}
+	if apiClient.Supports(client.FeatureTopicOwner) {
+		return store, nil
+	}
+	sidecar, err := annotations.Open(apiClient.BaseURL())
+	if err != nil {
+		return nil, err
+	}
+	store.sidecar = sidecar
+	return store, nil
+}
+
+// get returns a topic's owner, failing if the topic doesn't exist
+func (s *ownerStore) get(topic string) (client.Owner, error) {
+	if s.sidecar == nil {
+		owner, err := s.apiClient.GetTopicOwner(topic)
+		if err != nil {
+			return client.Owner{}, err
+		}
+		return *owner, nil
+	}
+	if _, err := s.apiClient.GetTopic(topic); err != nil {
+		return client.Owner{}, err
+	}
+	if owner := s.sidecar.Topics[topic].Owner; owner != nil {
+		return *owner, nil
+	}
+	return client.Owner{}, nil
+}
+
+// set replaces a topic's owner; an empty owner clears it. Owners kept in the sidecar
+// are written by save.
+func (s *ownerStore) set(topic string, owner client.Owner) error {
+	if s.sidecar == nil {
+		return s.apiClient.SetTopicOwner(topic, owner)
+	}
+	kept := s.sidecar.Topics[topic]
+	kept.Owner = nil
+	if owner != (client.Owner{}) {
+		kept.Owner = &owner
+	}
+	s.sidecar.SetTopic(topic, kept)
+	return nil
+}
+
+func (s *ownerStore) save() error {
+	if s.sidecar == nil {
+		return nil
+	}
+	return s.sidecar.Save()
+}
+
+func printOwners(format string, report *owners.Report) error {
+	if cmd.Quiet() {
+		names := make([]string, len(report.Topics))
+		for i, entry := range report.Topics {
+			names[i] = entry.Topic
+		}
+		output.PrintIDs(names)
+		return nil
+	}
+	switch format {
+	case "json":
+		return output.PrintJSON(report)
+	case "csv":
+		return output.PrintOwnersReportCSV(report)
+	default:
+		output.PrintOwnersReport(report)
+		return nil
+	}
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(ownerCmd)
+	ownerCmd.AddCommand(ownerSetCmd)
+	ownerCmd.AddCommand(ownerClearCmd)
+	ownerSetCmd.Flags().StringVar(&ownerName, "owner", "", "The person accountable for the topics")
+	ownerSetCmd.Flags().StringVar(&ownerTeam, "team", "", "The team that owns the topics")
+	ownerSetCmd.Flags().StringVar(&ownerContact, "contact", "", "How to reach the owners, such as an email address or chat channel")
+}
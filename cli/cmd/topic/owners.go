@@ -0,0 +1,66 @@
+package topic
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/owners"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ownersOwner   string
+	ownersTeam    string
+	ownersUnowned bool
+)
+
+var ownersCmd = &cobra.Command{
+	Use:   "owners",
+	Short: "Report who owns each topic",
+	Long: `List topics by owner: sorted by team, then owner, then name, with the topics that have
+no owner, neither a person nor a team, flagged and listed last. Owners are recorded with
+'es topic owner set'.
+
+With --unowned only the topics with no owner are listed, and the command exits with
+status 1 if there are any, so CI can insist that every topic has an owner.
+
+Examples:
+  # Every topic and its owner
+  es topic owners
+
+  # The payments team's topics
+  es topic owners --team payments
+
+  # Fail if a production topic has no owner
+  es topic owners --unowned --selector env=prod`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		topics, err := cmd.ListTopics(apiClient)
+		if err != nil {
+			return err
+		}
+		report := owners.Build(topics, owners.Options{
+			Owner:   ownersOwner,
+			Team:    ownersTeam,
+			Unowned: ownersUnowned,
+		})
+		if err := printOwners(cfg.Output.Format, report); err != nil {
+			return err
+		}
+		if ownersUnowned && report.Unowned > 0 {
+			return cmd.CheckFailed(fmt.Errorf("%d topic(s) have no owner", report.Unowned))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(ownersCmd)
+	ownersCmd.Flags().StringVar(&ownersOwner, "owner", "", "Only topics of this owner")
+	ownersCmd.Flags().StringVar(&ownersTeam, "team", "", "Only topics of this team")
+	ownersCmd.Flags().BoolVar(&ownersUnowned, "unowned", false, "Only topics with no owner, exiting with status 1 if there are any")
+	cmd.AddSelectorFlag(ownersCmd)
+}
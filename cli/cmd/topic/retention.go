@@ -0,0 +1,195 @@
+package topic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	retentionMaxAge    string
+	retentionMaxEvents string
+	retentionConfirm   string
+)
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Manage how long a topic keeps its events (experimental)",
+	Long: `Show and set a topic's retention limits. The server deletes events older than the
+maximum age and the oldest events beyond the maximum count.
+
+Experimental: the event store server in this repository doesn't enforce retention limits
+yet. These commands need a server that advertises the 'topic-retention' feature (see
+es version --server), and fail without one.`,
+}
+
+var retentionShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a topic's retention limits (experimental)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+
+		if err := requireFeature(apiClient, client.FeatureTopicRetention, "retention limits"); err != nil {
+			return err
+		}
+		retention, err := apiClient.GetTopicRetention(args[0])
+		if err != nil {
+			return err
+		}
+		return printRetention(cfg.Output.Format, retention)
+	},
+}
+
+var retentionSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Set a topic's retention limits (experimental)",
+	Long: `Set a topic's retention limits. A limit that isn't given is left as it is; 'none' or
+0 removes it.
+
+--max-age is a duration such as '12h', '30d' or '2w'. --max-events is a count such as
+'500000', '500k' or '1M'.
+
+Tightening a limit makes the server delete the events already beyond it, so it has to
+be confirmed by typing the topic name, or with --confirm <name> in scripts.
+
+Experimental: needs a server with the 'topic-retention' feature, which the event store
+server doesn't implement yet.
+
+Examples:
+  # Keep 30 days of events, and at most a million
+  es topic retention set user-events --max-age 30d --max-events 1M
+
+  # Keep events for ever again
+  es topic retention set user-events --max-age none --max-events none`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		if !cobraCmd.Flags().Changed("max-age") && !cobraCmd.Flags().Changed("max-events") {
+			return fmt.Errorf("give --max-age, --max-events or both")
+		}
+		var maxAge, maxEvents int64 = -1, -1
+		if cobraCmd.Flags().Changed("max-age") {
+			seconds, err := parseRetentionAge(retentionMaxAge)
+			if err != nil {
+				return err
+			}
+			maxAge = seconds
+		}
+		if cobraCmd.Flags().Changed("max-events") {
+			count, err := parseEventCount(retentionMaxEvents)
+			if err != nil {
+				return err
+			}
+			maxEvents = count
+		}
+
+		if err := requireFeature(apiClient, client.FeatureTopicRetention, "retention limits"); err != nil {
+			return err
+		}
+		current, err := apiClient.GetTopicRetention(topicName)
+		if err != nil {
+			return err
+		}
+		updated := *current
+		if maxAge >= 0 {
+			updated.MaxAgeSeconds = maxAge
+		}
+		if maxEvents >= 0 {
+			updated.MaxEvents = maxEvents
+		}
+
+		if tightens(current.MaxAgeSeconds, updated.MaxAgeSeconds) || tightens(current.MaxEvents, updated.MaxEvents) {
+			what := fmt.Sprintf("Events of '%s' beyond a max age of %s and max events of %s will be deleted",
+				topicName, output.FormatRetentionAge(updated.MaxAgeSeconds), output.FormatRetentionEvents(updated.MaxEvents))
+			if err := cmd.ConfirmName(what, topicName, retentionConfirm); err != nil {
+				return err
+			}
+		}
+
+		if err := apiClient.SetTopicRetention(topicName, updated); err != nil {
+			return err
+		}
+		return printRetention(cfg.Output.Format, &updated)
+	},
+}
+
+func printRetention(format string, retention *client.Retention) error {
+	switch format {
+	case "json":
+		return output.PrintJSON(retention)
+	case "csv":
+		return output.PrintRetentionCSV(retention)
+	default:
+		output.PrintRetention(retention)
+		return nil
+	}
+}
+
+// requireFeature fails unless the server advertises a feature
+func requireFeature(apiClient *client.Client, feature, what string) error {
+	if !apiClient.Supports(feature) {
+		return fmt.Errorf("the event store does not support %s (feature '%s')", what, feature)
+	}
+	return nil
+}
+
+// tightens reports whether changing a limit from current to updated (0 for no limit)
+// makes it stricter
+func tightens(current, updated int64) bool {
+	return updated != 0 && (current == 0 || updated < current)
+}
+
+// parseRetentionAge parses --max-age into seconds, with 0 for 'none'
+func parseRetentionAge(value string) (int64, error) {
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return 0, nil
+	}
+	age, err := timerange.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --max-age: %w", err)
+	}
+	return int64(age.Seconds()), nil
+}
+
+// parseEventCount parses --max-events such as '500000', '500k', '1M' or '2G', with 0
+// for 'none'
+func parseEventCount(value string) (int64, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	if s == "none" {
+		return 0, nil
+	}
+	multiplier := 1.0
+	for suffix, m := range map[string]float64{"k": 1e3, "m": 1e6, "g": 1e9} {
+		if strings.HasSuffix(s, suffix) {
+			multiplier = m
+			s = strings.TrimSuffix(s, suffix)
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 || n*multiplier != float64(int64(n*multiplier)) {
+		return 0, fmt.Errorf("invalid --max-events '%s' (expected a count such as 500000, 500k or 1M)", value)
+	}
+	return int64(n * multiplier), nil
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(retentionCmd)
+	retentionCmd.AddCommand(retentionShowCmd)
+	retentionCmd.AddCommand(retentionSetCmd)
+	retentionSetCmd.Flags().StringVar(&retentionMaxAge, "max-age", "", "Delete events older than this, e.g. 30d ('none' for no limit)")
+	retentionSetCmd.Flags().StringVar(&retentionMaxEvents, "max-events", "", "Keep at most this many events, e.g. 1M ('none' for no limit)")
+	retentionSetCmd.Flags().StringVar(&retentionConfirm, "confirm", "", "Confirm a tighter limit without being asked, by giving the topic name")
+	cmd.DisablePager(retentionSetCmd)
+}
@@ -1,40 +1,79 @@
 package topic
 
 import (
+	"fmt"
+
 	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/annotations"
 	"github.com/event-store/cli/internal/client"
 	"github.com/event-store/cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var showCmd = &cobra.Command{
-	Use:   "show <name>",
-	Short: "Show detailed information about a topic",
-	Long:  `Show detailed information about a specific topic, including its schemas.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "show <name>...",
+	Short: "Show detailed information about topics",
+	Long: `Show detailed information about a specific topic, including its schemas.
+
+Several topics can be shown at once, by name, as a comma-separated list, or with a glob
+pattern such as 'user-*' matched against the server's topics. With -o json they are
+printed as an array.
+
+Examples:
+  # Show a topic
+  es topic show user-events
+
+  # Show every topic whose name starts with user-
+  es topic show 'user-*'
+
+  # Two topics, as JSON
+  es topic show orders,payments -o json`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
-		topic, err := apiClient.GetTopic(args[0])
+		names, err := cmd.ResolveTopics(apiClient, args)
 		if err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
+			return err
+		}
+		sidecar, err := annotations.Open(apiClient.BaseURL())
+		if err != nil {
+			return err
+		}
+		topics := make([]*client.Topic, 0, len(names))
+		for _, name := range names {
+			topic, err := apiClient.GetTopic(name)
+			if err != nil {
+				return err
 			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
+			// A copy, as the topic may be cached, with the labels and owner kept in the sidecar
+			shown := *topic
+			kept := sidecar.Topics[name]
+			shown.Labels = annotations.MergeLabels(topic.Labels, kept.Labels)
+			if shown.Owner == nil {
+				shown.Owner = kept.Owner
 			}
-			output.PrintError(err)
-			return err
+			topics = append(topics, &shown)
 		}
+		// A single topic named as such is printed as an object, as it always was
+		single := len(args) == 1 && !cmd.IsTopicSelector(args[0])
 
 		switch cfg.Output.Format {
 		case "json":
-			return output.PrintTopicDetailsJSON(topic)
+			if single {
+				return output.PrintTopicDetailsJSON(topics[0])
+			}
+			return output.PrintJSON(topics)
 		case "csv":
-			return output.PrintTopicDetailsCSV(topic)
+			return output.PrintTopicDetailsCSV(topics...)
 		default:
-			output.PrintTopicDetails(topic)
+			for i, topic := range topics {
+				if i > 0 {
+					fmt.Println()
+				}
+				output.PrintTopicDetails(topic)
+			}
 			return nil
 		}
 	},
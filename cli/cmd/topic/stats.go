@@ -0,0 +1,114 @@
+package topic
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsChart bool
+	statsSince string
+	statsScan  bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats <topic>",
+	Short: "Show statistics about a topic's events",
+	Long: `Show statistics about a topic's events: events per day (UTC), counts by event type,
+the distribution of payload sizes (bytes of compact JSON) and the first and last events.
+
+When the server supports topic statistics they are fetched from it. Otherwise the CLI
+reads every event of the topic to compute them, which can take a while for large topics;
+--from-event-id limits the statistics to the events after an event ID.
+
+With --chart, events per day are drawn as a sparkline and the event types and payload
+sizes as bar charts.
+
+Examples:
+  # Summarise a topic
+  es topic stats user-events
+
+  # Draw the topic's activity in the terminal
+  es topic stats user-events --chart
+
+  # Statistics for recent events only, as JSON
+  es topic stats user-events --from-event-id user-events-50000 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		if statsSince != "" {
+			id, err := eventid.Parse(statsSince)
+			if err != nil {
+				return fmt.Errorf("invalid --from-event-id: %w", err)
+			}
+			if id.Topic != topicName {
+				return fmt.Errorf("--from-event-id '%s' is not an event of topic '%s'", statsSince, topicName)
+			}
+		}
+
+		var topicStats *client.TopicStats
+		var err error
+		if !statsScan && statsSince == "" && apiClient.Supports(client.FeatureTopicStats) {
+			topicStats, err = apiClient.GetTopicStats(topicName)
+		} else {
+			topicStats, err = scanTopicStats(apiClient, topicName)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(topicStats)
+		case "csv":
+			return output.PrintTopicStatsCSV(topicStats)
+		default:
+			output.PrintTopicStats(topicStats, statsChart)
+			return nil
+		}
+	},
+}
+
+// scanTopicStats computes a topic's statistics by reading its events after --from-event-id
+func scanTopicStats(apiClient *client.Client, topicName string) (*client.TopicStats, error) {
+	topic, err := apiClient.RefreshTopic(topicName)
+	if err != nil {
+		return nil, err
+	}
+	total := topic.Sequence
+	if statsSince != "" {
+		id, _ := eventid.Parse(statsSince)
+		total -= int(id.Sequence)
+	}
+
+	collector := stats.NewCollector(topicName)
+	progress := output.NewProgress("Scanning", total)
+	err = apiClient.ScanEvents(topicName, statsSince, func(events []client.Event) (bool, error) {
+		for _, event := range events {
+			collector.Add(event)
+		}
+		progress.Add(len(events))
+		return true, nil
+	})
+	progress.Finish()
+	if err != nil {
+		return nil, err
+	}
+	return collector.Stats(), nil
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsChart, "chart", false, "Draw events per day as a sparkline and types and payload sizes as bars")
+	statsCmd.Flags().StringVar(&statsSince, "from-event-id", "", "Only include events after this event ID (computed by the CLI)")
+	statsCmd.Flags().BoolVar(&statsScan, "scan", false, "Compute the statistics from the events even when the server can provide them")
+}
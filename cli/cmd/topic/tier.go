@@ -0,0 +1,260 @@
+package topic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/archive"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tierTo     string
+	tierBefore string
+	tierSSE    string
+	tierSSEKey string
+)
+
+var tierCmd = &cobra.Command{
+	Use:   "tier",
+	Short: "Manage a topic's cold storage tier",
+	Long: `Copy old events of a topic to a cold storage tier and inspect what has been tiered.
+
+Tiered events are written to archive segments (gzipped newline-delimited JSON) with a
+manifest that records which events each segment holds. Each segment has an index file
+of its blocks by event ID, timestamp and type, so 'es archive query' and
+'es event list --include-cold' only read the blocks that can match. The manifest is kept under
+~/.es/tiers so the CLI can read through to the cold tier.`,
+}
+
+var tierPushCmd = &cobra.Command{
+	Use:   "push <topic>",
+	Short: "Copy events older than a cutoff to the cold tier",
+	Long: `Copy the events of a topic that are older than --before to the cold tier, continuing
+after the last event tiered so far. Each push writes one new segment.
+
+--before is a date (YYYY-MM-DD), an RFC 3339 timestamp or an event ID; events strictly
+before it are tiered. The cold tier location is a directory, a file:// URL or an object
+storage URL (s3://bucket/path, gs://bucket/path or azblob://container/path), and only
+has to be given on the first push. Object storage credentials come from the usual
+environment variables and config files of each cloud; --sse and --sse-key ask it to
+encrypt the segments at rest.
+
+Tiered events remain in the hot tier as well, until they are removed with
+'es topic truncate'.
+
+Examples:
+  # Tier everything before 2025 to a local archive directory
+  es topic tier push user-events --before 2025-01-01 --to /mnt/archive
+
+  # Later, tier up to an event ID using the recorded location
+  es topic tier push user-events --before user-events-50000`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topic := args[0]
+
+		before, err := parseTierCutoff(tierBefore)
+		if err != nil {
+			return err
+		}
+		manifest, events, err := pushTier(apiClient, cfg.Server.URL, topic, before, tierTo, encryptionFlags(tierSSE, tierSSEKey))
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintTierManifestJSON(manifest)
+		case "csv":
+			return output.PrintTierManifestCSV(manifest)
+		default:
+			if len(events) == 0 {
+				fmt.Println("No new events to tier")
+			} else {
+				fmt.Printf("Tiered %d event(s) of '%s' (%s to %s)\n\n", len(events), topic, events[0].ID, events[len(events)-1].ID)
+			}
+			output.PrintTierManifest(manifest)
+			return nil
+		}
+	},
+}
+
+var tierShowCmd = &cobra.Command{
+	Use:   "show <topic>",
+	Short: "Show a topic's cold tier",
+	Long:  `Show where a topic's cold tier is stored and which events each segment holds.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		topic := args[0]
+
+		manifest, err := archive.LoadTierManifest(cfg.Server.URL, topic)
+		if err == nil && manifest == nil {
+			err = fmt.Errorf("topic '%s' has no cold tier", topic)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintTierManifestJSON(manifest)
+		case "csv":
+			return output.PrintTierManifestCSV(manifest)
+		default:
+			output.PrintTierManifest(manifest)
+			return nil
+		}
+	},
+}
+
+// pushTier copies the events of a topic before a cutoff, after those tiered so far, to a
+// new segment of its cold tier, returning the updated manifest and the events copied.
+// to sets the tier's location; it may only be empty once the topic has a cold tier.
+// encryption, when not nil, replaces the tier's server-side encryption options.
+func pushTier(apiClient *client.Client, server, topic string, before func(client.Event) bool, to string, encryption *archive.Encryption) (*archive.TierManifest, []client.Event, error) {
+	manifest, err := archive.LoadTierManifest(server, topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	if manifest == nil {
+		if to == "" {
+			return nil, nil, fmt.Errorf("topic '%s' has no cold tier yet, so its location is required", topic)
+		}
+		manifest = &archive.TierManifest{Topic: topic, Server: server}
+	}
+	if to != "" {
+		manifest.Location = to
+	}
+	if encryption != nil {
+		manifest.Encryption = encryption
+	}
+
+	store, err := archive.OpenEncrypted(manifest.Location, manifest.Encryption)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest.Location = store.URL()
+
+	var events []client.Event
+	err = apiClient.ScanEvents(topic, manifest.LastEventID(), func(page []client.Event) (bool, error) {
+		for _, event := range page {
+			if !before(event) {
+				return false, nil
+			}
+			events = append(events, event)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(events) > 0 {
+		segment, err := writeTierSegment(store, topic, events)
+		if err != nil {
+			return nil, nil, err
+		}
+		manifest.Segments = append(manifest.Segments, *segment)
+		if err := archive.SaveTierManifest(store, manifest); err != nil {
+			return nil, nil, err
+		}
+	}
+	return manifest, events, nil
+}
+
+// encryptionFlags returns the server-side encryption given by --sse and --sse-key, or
+// nil if neither was
+func encryptionFlags(mode, keyID string) *archive.Encryption {
+	if mode == "" && keyID == "" {
+		return nil
+	}
+	return &archive.Encryption{Mode: mode, KeyID: keyID}
+}
+
+// parseTierCutoff returns a predicate reporting whether an event is before the cutoff,
+// given as a date, an RFC 3339 timestamp or an event ID
+func parseTierCutoff(value string) (func(client.Event) bool, error) {
+	if value == "" {
+		return nil, fmt.Errorf("--before is required")
+	}
+
+	if cutoff, err := time.Parse("2006-01-02", value); err == nil {
+		return beforeTime(cutoff), nil
+	}
+	if cutoff, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return beforeTime(cutoff), nil
+	}
+	if cutoff, err := eventid.Parse(value); err == nil {
+		return func(event client.Event) bool {
+			id, err := eventid.Parse(event.ID)
+			return err == nil && id.Sequence < cutoff.Sequence
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid --before '%s' (expected YYYY-MM-DD, an RFC 3339 timestamp or an event ID)", value)
+}
+
+func beforeTime(cutoff time.Time) func(client.Event) bool {
+	return func(event client.Event) bool {
+		timestamp, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+		return err == nil && timestamp.Before(cutoff)
+	}
+}
+
+// writeTierSegment archives events as a new segment named after their sequence range
+func writeTierSegment(store archive.Store, topic string, events []client.Event) (*archive.Segment, error) {
+	data, checksum, index, err := archive.EncodeSegment(events)
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := events[0], events[len(events)-1]
+	firstID, err := eventid.Parse(first.ID)
+	if err != nil {
+		return nil, err
+	}
+	lastID, err := eventid.Parse(last.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s/%012d-%012d.ndjson.gz", topic, firstID.Sequence, lastID.Sequence)
+	if err := store.Put(name, data); err != nil {
+		return nil, err
+	}
+	indexData, err := archive.EncodeIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Put(archive.IndexName(name), indexData); err != nil {
+		return nil, err
+	}
+
+	return &archive.Segment{
+		File:           name,
+		FirstEventID:   first.ID,
+		LastEventID:    last.ID,
+		Count:          len(events),
+		FirstTimestamp: first.Timestamp,
+		LastTimestamp:  last.Timestamp,
+		SHA256:         checksum,
+		Index:          archive.IndexName(name),
+		CreatedAt:      time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(tierCmd)
+	tierCmd.AddCommand(tierPushCmd)
+	tierCmd.AddCommand(tierShowCmd)
+	tierPushCmd.Flags().StringVar(&tierTo, "to", "", "Cold tier location: a directory, file:// URL or s3://, gs:// or azblob:// URL (default: the location of earlier pushes)")
+	tierPushCmd.Flags().StringVar(&tierSSE, "sse", "", "S3 server-side encryption: AES256 or aws:kms (default: as for earlier pushes)")
+	tierPushCmd.Flags().StringVar(&tierSSEKey, "sse-key", "", "KMS key for S3 or GCS, or encryption scope for Azure")
+	tierPushCmd.Flags().StringVar(&tierBefore, "before", "", "Tier events before this date (YYYY-MM-DD), timestamp or event ID (required)")
+}
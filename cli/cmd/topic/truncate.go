@@ -0,0 +1,107 @@
+package topic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	truncateBefore  string
+	truncateDryRun  bool
+	truncateConfirm string
+)
+
+var truncateCmd = &cobra.Command{
+	Use:   "truncate <name>",
+	Short: "Delete a topic's oldest events (experimental)",
+	Long: `Delete the events of a topic before a cutoff.
+
+Experimental: the event store server in this repository can't delete events yet. This
+command needs a server that advertises the 'topic-truncate' feature (see
+es version --server), and fails without one.
+
+--before is an event ID of the topic, whose earlier events are deleted, or a point in
+time: a date (YYYY-MM-DD), an RFC 3339 timestamp or a duration ago such as '90d'.
+
+The number of events that will be deleted is shown first and has to be confirmed by
+typing the topic name, or with --confirm <name> in scripts. Use --dry-run to only count
+them. Consider 'es topic tier push' to keep a copy first.
+
+Examples:
+  # See how many events before 2025 there are
+  es topic truncate user-events --before 2025-01-01 --dry-run
+
+  # Delete everything before an event, from a script
+  es topic truncate user-events --before user-events-50000 --confirm user-events`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		req, err := parseTruncateCutoff(topicName, truncateBefore)
+		if err != nil {
+			return err
+		}
+		if err := requireFeature(apiClient, client.FeatureTopicTruncate, "truncating topics"); err != nil {
+			return err
+		}
+
+		req.DryRun = true
+		result, err := apiClient.TruncateTopic(topicName, req)
+		if err != nil {
+			return err
+		}
+		if !truncateDryRun && result.Deleted > 0 {
+			what := fmt.Sprintf("%d event(s) of '%s' before %s will be deleted", result.Deleted, topicName, truncateBefore)
+			if err := cmd.ConfirmName(what, topicName, truncateConfirm); err != nil {
+				return err
+			}
+			req.DryRun = false
+			if result, err = apiClient.TruncateTopic(topicName, req); err != nil {
+				return err
+			}
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintJSON(result)
+		case "csv":
+			return output.PrintTruncateResultCSV(result)
+		default:
+			output.PrintTruncateResult(result)
+			return nil
+		}
+	},
+}
+
+// parseTruncateCutoff parses --before as an event ID of the topic or a point in time
+func parseTruncateCutoff(topicName, value string) (client.TruncateRequest, error) {
+	if value == "" {
+		return client.TruncateRequest{}, fmt.Errorf("--before is required")
+	}
+	if id, err := eventid.Parse(value); err == nil && id.Topic == topicName {
+		return client.TruncateRequest{BeforeEventID: value}, nil
+	}
+	cutoff, err := timerange.ParseTime(value, time.Now())
+	if err != nil {
+		return client.TruncateRequest{}, fmt.Errorf("invalid --before: expected an event ID of '%s' or %w", topicName, err)
+	}
+	return client.TruncateRequest{Before: timerange.Format(cutoff)}, nil
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(truncateCmd)
+	truncateCmd.Flags().StringVar(&truncateBefore, "before", "", "Delete events before this event ID, date, timestamp or duration ago (required)")
+	truncateCmd.Flags().BoolVar(&truncateDryRun, "dry-run", false, "Only count the events that would be deleted")
+	truncateCmd.Flags().StringVar(&truncateConfirm, "confirm", "", "Confirm without being asked, by giving the topic name")
+	truncateCmd.MarkFlagRequired("before")
+	cmd.DisablePager(truncateCmd)
+}
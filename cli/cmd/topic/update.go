@@ -11,48 +11,76 @@ import (
 	"github.com/event-store/cli/internal/output"
 )
 
-var updateSchemasFile string
+var (
+	updateSchemasFile string
+	updateEdit        bool
+)
 
 var updateCmd = &cobra.Command{
 	Use:   "update <name>",
 	Short: "Update topic schemas",
-	Long:  `Update schemas for an existing topic. Schema updates are additive only - you can add new schemas or update existing ones, but cannot remove schemas.`,
+	Long: `Update schemas for an existing topic. Schema updates are additive only - you can add new schemas or update existing ones, but cannot remove schemas.
+
+With --edit the topic's current schemas are opened in $VISUAL or $EDITOR (default: vi).
+When the file is saved and the editor closed, the schemas are checked, the changes are
+shown as a diff and, once confirmed, applied. Schemas that fail the checks can be edited
+again.
+
+Examples:
+  # Update schemas from a file
+  es topic update user-events --schemas-file schemas.json
+
+  # Edit the schemas in an editor
+  es topic update user-events --edit`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		cfg := cmd.GetConfig()
-		apiClient := client.NewClient(cfg.Server.URL)
+		apiClient := cmd.NewClient()
 
 		topicName := args[0]
 
-		if updateSchemasFile == "" {
-			return fmt.Errorf("schemas file is required (use --schemas-file)")
-		}
+		var schemas []client.Schema
+		if updateEdit {
+			topic, err := apiClient.GetTopic(topicName)
+			if err != nil {
+				return err
+			}
+			edited, err := cmd.EditJSON(topic.Schemas, topicName+"-schemas-*.json", "Apply these changes?", func(data []byte) error {
+				return checkSchemas(topic.Schemas, data)
+			})
+			if err != nil {
+				return err
+			}
+			if edited == nil {
+				output.PrintMessage(fmt.Sprintf("Topic '%s' schemas not changed", topicName))
+				return nil
+			}
+			if err := json.Unmarshal(edited, &schemas); err != nil {
+				return fmt.Errorf("failed to parse schemas JSON: %w", err)
+			}
+		} else {
+			if updateSchemasFile == "" {
+				return fmt.Errorf("schemas file is required (use --schemas-file, or --edit)")
+			}
 
-		// Read schemas from file
-		schemaData, err := os.ReadFile(updateSchemasFile)
-		if err != nil {
-			return fmt.Errorf("failed to read schemas file: %w", err)
-		}
+			// Read schemas from file
+			schemaData, err := os.ReadFile(updateSchemasFile)
+			if err != nil {
+				return fmt.Errorf("failed to read schemas file: %w", err)
+			}
 
-		var schemas []client.Schema
-		if err := json.Unmarshal(schemaData, &schemas); err != nil {
-			return fmt.Errorf("failed to parse schemas JSON: %w", err)
-		}
+			if err := json.Unmarshal(schemaData, &schemas); err != nil {
+				return fmt.Errorf("failed to parse schemas JSON: %w", err)
+			}
 
-		// Validate schemas
-		if len(schemas) == 0 {
-			return fmt.Errorf("at least one schema is required")
+			// Validate schemas
+			if len(schemas) == 0 {
+				return fmt.Errorf("at least one schema is required")
+			}
 		}
 
 		// Update topic schemas
 		if err := apiClient.UpdateTopicSchemas(topicName, schemas); err != nil {
-			if cfg.Output.Format == "json" {
-				return output.PrintErrorJSON(err)
-			}
-			if cfg.Output.Format == "csv" {
-				return output.PrintErrorCSV(err)
-			}
-			output.PrintError(err)
 			return err
 		}
 
@@ -71,6 +99,46 @@ var updateCmd = &cobra.Command{
 
 func init() {
 	cmd.TopicCmd().AddCommand(updateCmd)
-	updateCmd.Flags().StringVar(&updateSchemasFile, "schemas-file", "", "Path to JSON file containing schemas array (required)")
-	updateCmd.MarkFlagRequired("schemas-file")
+	updateCmd.Flags().StringVar(&updateSchemasFile, "schemas-file", "", "Path to JSON file containing schemas array (required without --edit)")
+	updateCmd.Flags().BoolVar(&updateEdit, "edit", false, "Edit the topic's current schemas in $VISUAL or $EDITOR")
+	updateCmd.MarkFlagsMutuallyExclusive("schemas-file", "edit")
+	updateCmd.MarkFlagsOneRequired("schemas-file", "edit")
+	cmd.DisablePagerWith(updateCmd, "edit")
+}
+
+// checkSchemas checks the schemas saved in the editor: a JSON array of schemas, each with
+// an event type of its own and an object type, that keeps the current schemas' event
+// types, as schemas can't be removed
+func checkSchemas(current []client.Schema, data []byte) error {
+	var schemas []client.Schema
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return fmt.Errorf("failed to parse schemas JSON: %w", err)
+	}
+	if len(schemas) == 0 {
+		return fmt.Errorf("at least one schema is required")
+	}
+
+	types := map[string]bool{}
+	for i, schema := range schemas {
+		switch {
+		case schema.EventType == "":
+			return fmt.Errorf("schema %d has no eventType", i+1)
+		case types[schema.EventType]:
+			return fmt.Errorf("event type '%s' has more than one schema", schema.EventType)
+		case schema.Type != "object":
+			return fmt.Errorf("schema of '%s' must have type 'object', as payloads are objects", schema.EventType)
+		}
+		types[schema.EventType] = true
+		for name, property := range schema.Properties {
+			if _, ok := property.(map[string]interface{}); !ok {
+				return fmt.Errorf("property '%s' of '%s' must be a schema object", name, schema.EventType)
+			}
+		}
+	}
+	for _, schema := range current {
+		if !types[schema.EventType] {
+			return fmt.Errorf("schema of '%s' can't be removed, as schema updates are additive only", schema.EventType)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,102 @@
+package topic
+
+import (
+	"fmt"
+
+	"github.com/event-store/cli/cmd"
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/verify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifySince          string
+	verifyAllowTruncated bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <topic>",
+	Short: "Check a topic's events for gaps and ordering anomalies",
+	Long: `Read every event of a topic and check that it is consistent, such as after a migration
+or a restore: that the events' sequences run from 1 to the topic's sequence without
+gaps, duplicates or going back, and that their timestamps never go back. Each anomaly
+is reported with the event it was found at; the first 1000 are listed.
+
+Events missing before the first are reported too, unless --allow-truncated says the
+oldest events were removed by truncation or retention. --from-event-id verifies only
+the events after an event ID.
+
+The command exits with status 1 when it finds anomalies.
+
+Examples:
+  # Verify a restored topic
+  es topic verify orders
+
+  # A topic with retention, whose oldest events are gone
+  es topic verify audit-log --allow-truncated
+
+  # Only the events after an event ID, as JSON
+  es topic verify orders --from-event-id orders-50000 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		cfg := cmd.GetConfig()
+		apiClient := cmd.NewClient()
+		topicName := args[0]
+
+		options := verify.Options{AllowTruncated: verifyAllowTruncated}
+		if verifySince != "" {
+			id, err := eventid.Parse(verifySince)
+			if err != nil {
+				return fmt.Errorf("invalid --from-event-id: %w", err)
+			}
+			if id.Topic != topicName {
+				return fmt.Errorf("--from-event-id '%s' is not an event of topic '%s'", verifySince, topicName)
+			}
+			options.After = id.Sequence
+		}
+
+		topic, err := apiClient.RefreshTopic(topicName)
+		if err != nil {
+			return err
+		}
+		verifier := verify.NewVerifier(topicName, int64(topic.Sequence), options)
+		progress := output.NewProgress("Verifying", topic.Sequence-int(options.After))
+		err = apiClient.ScanEvents(topicName, verifySince, func(events []client.Event) (bool, error) {
+			for _, event := range events {
+				verifier.Add(event)
+			}
+			progress.Add(len(events))
+			return true, nil
+		})
+		progress.Finish()
+		if err != nil {
+			return err
+		}
+		result := verifier.Result()
+
+		switch cfg.Output.Format {
+		case "json":
+			err = output.PrintJSON(result)
+		case "csv":
+			err = output.PrintVerifyResultCSV(result)
+		default:
+			output.PrintVerifyResult(result)
+		}
+		if err != nil {
+			return err
+		}
+
+		if result.Count() > 0 {
+			return cmd.CheckFailed(fmt.Errorf("found %s", result.Summary()))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.TopicCmd().AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVar(&verifySince, "from-event-id", "", "Only verify the events after this event ID")
+	verifyCmd.Flags().BoolVar(&verifyAllowTruncated, "allow-truncated", false, "Don't report events missing before the first, as after truncation or retention")
+}
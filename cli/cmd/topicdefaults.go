@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ApplyTopicDefaults sets the flags of an event command that weren't given on the
+// command line from the topic's defaults under 'topics' in the config file: --limit,
+// --fields, --filter and --mask, for the commands that have them
+func ApplyTopicDefaults(c *cobra.Command, topic string) error {
+	defaults := cfg.Topics[topic].Defaults
+	if defaults.Limit != 0 {
+		if err := setDefault(c, "limit", strconv.Itoa(defaults.Limit)); err != nil {
+			return err
+		}
+	}
+	if len(defaults.Fields) > 0 {
+		if err := setDefault(c, "fields", defaults.Fields...); err != nil {
+			return err
+		}
+	}
+	if defaults.Filter != "" {
+		if err := setDefault(c, "filter", defaults.Filter); err != nil {
+			return err
+		}
+	}
+	if defaults.Mask != nil {
+		if err := output.CheckMaskFields(defaults.Mask); err != nil {
+			return err
+		}
+		if flag := c.Flags().Lookup("mask"); flag != nil && !flag.Changed {
+			// The topic's mask replaces output.mask
+			maskFields = defaults.Mask
+			if err := setDefault(c, "mask", defaults.Mask...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setDefault sets a flag that wasn't given on the command line, if the command has it,
+// to a default value; a repeatable or comma-separated flag is set to all the values
+func setDefault(c *cobra.Command, name string, values ...string) error {
+	flag := c.Flags().Lookup(name)
+	if flag == nil || flag.Changed {
+		return nil
+	}
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		return slice.Replace(values)
+	}
+	return flag.Value.Set(values[0])
+}
+
+// TopicDefaultFilter returns the default --filter of one of several topics a command
+// reads, or "" when --filter was given on the command line or the topic has none. The
+// defaults of a single topic are set on the command's flags by ApplyTopicDefaults.
+func TopicDefaultFilter(c *cobra.Command, topic string) string {
+	if flag := c.Flags().Lookup("filter"); flag == nil || flag.Changed {
+		return ""
+	}
+	return cfg.Topics[topic].Defaults.Filter
+}
+
+// TopicDefaultMask returns the default --mask of one of several topics a command reads,
+// or nil when --mask was given on the command line or the topic has none
+func TopicDefaultMask(c *cobra.Command, topic string) []string {
+	if flag := c.Flags().Lookup("mask"); flag == nil || flag.Changed {
+		return nil
+	}
+	return cfg.Topics[topic].Defaults.Mask
+}
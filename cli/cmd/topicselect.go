@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// IsTopicSelector reports whether a topic argument can select several topics: a
+// comma-separated list or a glob pattern
+func IsTopicSelector(arg string) bool {
+	return strings.ContainsAny(arg, ",*?[")
+}
+
+// ResolveTopics resolves the topic arguments of a command to topic names. Each argument
+// is a topic's name, a comma-separated list of them, or a glob pattern such as 'order-*'
+// ('*', '?' and '[...]' as in shell patterns) matched against the server's topics. Names
+// are returned in the order given, each pattern's matches sorted, without duplicates. A
+// pattern matching no topic is an error; names aren't checked against the server.
+func ResolveTopics(apiClient *client.Client, args []string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	var all []string
+	for _, arg := range args {
+		for _, selector := range strings.Split(arg, ",") {
+			selector = strings.TrimSpace(selector)
+			if selector == "" {
+				continue
+			}
+			if !strings.ContainsAny(selector, "*?[") {
+				add(selector)
+				continue
+			}
+			if _, err := path.Match(selector, ""); err != nil {
+				return nil, fmt.Errorf("invalid topic pattern '%s'", selector)
+			}
+			if all == nil {
+				topics, err := apiClient.GetTopics()
+				if err != nil {
+					return nil, err
+				}
+				all = make([]string, 0, len(topics))
+				for _, topic := range topics {
+					all = append(all, topic.Name)
+				}
+				sort.Strings(all)
+			}
+			matched := false
+			for _, name := range all {
+				if ok, _ := path.Match(selector, name); ok {
+					add(name)
+					matched = true
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("no topics match '%s'", selector)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no topics given")
+	}
+	return names, nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Version is the CLI version, set at build time with
+// -ldflags "-X github.com/event-store/cli/cmd.Version=<version>"
+var Version = "dev"
+
+var versionServer bool
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the CLI version",
+	Long: `Show the CLI version and, with --server, the event store's version, API version and
+the optional features it supports.
+
+Commands use the server's features to decide how to do their work. For example,
+'es event list --filter' filters on the server when it supports that. A server without a
+/version or /info endpoint is treated as supporting no optional features.
+
+Examples:
+  # CLI version only
+  es version
+
+  # Compare the CLI with the server
+  es version --server`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		var serverInfo *client.ServerInfo
+		if versionServer {
+			var err error
+			serverInfo, err = NewClient().GetServerInfo()
+			if err != nil {
+				return err
+			}
+		}
+
+		switch cfg.Output.Format {
+		case "json":
+			return output.PrintVersionJSON(Version, cfg.Server.URL, serverInfo)
+		case "csv":
+			return output.PrintVersionCSV(Version, cfg.Server.URL, serverInfo)
+		default:
+			output.PrintVersion(Version, cfg.Server.URL, serverInfo)
+			return nil
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionServer, "server", false, "Also show the server's version, API version and features")
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waitFor      []string
+	waitTimeout  time.Duration
+	waitInterval time.Duration
+)
+
+// waitColumns are the columns of wait's output, a row per condition
+var waitColumns = []output.Column{
+	{Name: "for", Header: "For"},
+	{Name: "ready", Header: "Ready"},
+	{Name: "seconds", Header: "Seconds", Numeric: true},
+	{Name: "detail", Header: "Detail"},
+}
+
+// waitCondition is something wait blocks until: the server being healthy, or a topic or
+// consumer existing
+type waitCondition struct {
+	For     string  `json:"for"`
+	Ready   bool    `json:"ready"`
+	Seconds float64 `json:"seconds"`
+	Detail  string  `json:"detail,omitempty"` // why it isn't ready, as of the last check
+
+	check func(apiClient *client.Client) error
+}
+
+// waitCmd represents the wait command
+var waitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait until the event store is ready",
+	Long: `Block until the event store is healthy, or a topic or consumer exists, for ordering
+startup in docker-compose and CI. Exits with an error if --timeout passes first.
+
+--for takes 'health', 'topic=<name>' or 'consumer=<id>', and can be repeated to wait for
+all of them, in order. Without --for, wait for the server to be healthy.
+
+Examples:
+  # Wait for the server to come up before running tests
+  es wait --timeout 60s
+
+  # Wait for another service to create its topic and register its consumer
+  es wait --for topic=orders --for consumer=5f1c... --timeout 2m`,
+	Args: cobra.NoArgs,
+	RunE: func(cobraCmd *cobra.Command, args []string) error {
+		if waitInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		if len(waitFor) == 0 {
+			waitFor = []string{"health"}
+		}
+		conditions := make([]*waitCondition, len(waitFor))
+		for i, spec := range waitFor {
+			condition, err := parseWaitCondition(spec)
+			if err != nil {
+				return err
+			}
+			conditions[i] = condition
+		}
+
+		apiClient := NewClient()
+		group, err := NewRunner()
+		if err != nil {
+			return err
+		}
+		interrupted := false
+		group.Go("wait", func(ctx context.Context) error {
+			defer group.Stop()
+			started := time.Now()
+			deadline := started.Add(waitTimeout)
+			for _, condition := range conditions {
+				for {
+					err := condition.check(apiClient)
+					condition.Seconds = time.Since(started).Seconds()
+					if err == nil {
+						condition.Ready, condition.Detail = true, ""
+						break
+					}
+					condition.Detail = err.Error()
+					if !time.Now().Before(deadline) {
+						return nil
+					}
+					select {
+					case <-ctx.Done():
+						interrupted = true
+						return nil
+					case <-time.After(min(waitInterval, time.Until(deadline))):
+					}
+				}
+			}
+			return nil
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+
+		listing := output.NewListing(waitColumns)
+		var notReady []string
+		for _, condition := range conditions {
+			ready := "yes"
+			if !condition.Ready {
+				ready = "no"
+				notReady = append(notReady, condition.For)
+				if condition.Detail == "" {
+					// Still waiting for an earlier condition when the time was up
+					condition.Detail = "not checked"
+				}
+			}
+			listing.Add(condition.For, condition, condition.For, ready, fmt.Sprintf("%.1f", condition.Seconds), condition.Detail)
+		}
+		if !quiet {
+			if err := PrintListing("conditions", listing); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case interrupted:
+			return fmt.Errorf("interrupted while waiting for %s", strings.Join(notReady, ", "))
+		case len(notReady) > 0:
+			return CheckFailed(fmt.Errorf("timed out after %s waiting for %s", waitTimeout, strings.Join(notReady, ", ")))
+		}
+		return nil
+	},
+}
+
+// parseWaitCondition parses a --for value: health, topic=<name> or consumer=<id>
+func parseWaitCondition(spec string) (*waitCondition, error) {
+	kind, name, _ := strings.Cut(spec, "=")
+	condition := &waitCondition{For: spec}
+	switch {
+	case kind == "health" && name == "":
+		condition.check = func(apiClient *client.Client) error {
+			health, err := apiClient.GetHealth()
+			if err != nil {
+				return err
+			}
+			if health.Status != "healthy" {
+				return fmt.Errorf("status %s", health.Status)
+			}
+			return nil
+		}
+	case kind == "topic" && name != "":
+		condition.check = func(apiClient *client.Client) error {
+			_, err := apiClient.GetTopic(name)
+			return err
+		}
+	case kind == "consumer" && name != "":
+		condition.check = func(apiClient *client.Client) error {
+			consumers, err := apiClient.GetConsumers()
+			if err != nil {
+				return err
+			}
+			for _, consumer := range consumers {
+				if consumer.ID == name {
+					return nil
+				}
+			}
+			return fmt.Errorf("consumer '%s' not found", name)
+		}
+	default:
+		return nil, fmt.Errorf("invalid --for '%s' (expected 'health', 'topic=<name>' or 'consumer=<id>')", spec)
+	}
+	return condition, nil
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+	DisablePager(waitCmd)
+	waitCmd.Flags().StringArrayVar(&waitFor, "for", nil, "What to wait for: 'health', 'topic=<name>' or 'consumer=<id>' (repeatable; default: health)")
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", 60*time.Second, "How long to wait before giving up")
+	waitCmd.Flags().DurationVar(&waitInterval, "interval", time.Second, "How often to check while waiting")
+}
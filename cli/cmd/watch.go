@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/output"
+	"github.com/event-store/cli/internal/watch"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	watchEnabled  bool
+	watchInterval time.Duration
+)
+
+// AddWatchFlags adds --watch and --interval to a command that lists resources
+func AddWatchFlags(c *cobra.Command) {
+	c.Flags().BoolVarP(&watchEnabled, "watch", "w", false, "Refresh the output on an interval and highlight changes")
+	c.Flags().DurationVar(&watchInterval, "interval", 2*time.Second, "Refresh interval for --watch")
+}
+
+// Watching reports whether --watch was given
+func Watching() bool {
+	return watchEnabled
+}
+
+// Watch calls fetch every --interval until Ctrl+C and shows what changed. Tables are
+// redrawn in place on a terminal with changes highlighted, and reprinted only when
+// something changed otherwise. JSON and CSV output stream one record per added,
+// modified or deleted row. Fetch errors are reported and retried on the next refresh.
+func Watch(cobraCmd *cobra.Command, header []string, fetch func() ([]watch.Row, error)) error {
+	if watchInterval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	group, err := NewRunner()
+	if err != nil {
+		return err
+	}
+
+	format := cfg.Output.Format
+	interactive := format == "table" && term.IsTerminal(int(os.Stdout.Fd()))
+	title := fmt.Sprintf("Every %s: %s", watchInterval, strings.TrimPrefix(cobraCmd.CommandPath(), rootCmd.Name()+" "))
+
+	var previous []watch.Row
+	first := true
+	csvHeader := true
+	refresh := func() error {
+		rows, err := fetch()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %v\n", time.Now().Format(time.RFC3339), err)
+			return nil
+		}
+
+		changes := watch.Diff(previous, rows)
+		if first && format == "table" {
+			// Nothing has changed yet; don't mark every row as added
+			changes = watch.Diff(rows, rows)
+		}
+		changed := first || watch.Changed(changes)
+		first = false
+		previous = rows
+
+		switch {
+		case format == "json":
+			return output.PrintWatchChangesJSON(changes)
+		case format == "csv":
+			err := output.PrintWatchChangesCSV(header, changes, csvHeader)
+			csvHeader = false
+			return err
+		case interactive || changed:
+			output.PrintWatchTable(fmt.Sprintf("%s    %s", title, time.Now().Format("15:04:05")), header, changes, interactive)
+			if !interactive {
+				fmt.Println()
+			}
+		}
+		return nil
+	}
+
+	group.Go("watch", func(ctx context.Context) error {
+		return watch.Loop(ctx, watchInterval, refresh)
+	})
+	return group.Wait()
+}
@@ -0,0 +1,163 @@
+package aggregate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// Placeholders for values an event doesn't have
+const (
+	MissingValue     = "(none)"
+	UnknownTimestamp = "(unknown)"
+)
+
+// MaxBuckets is the most time buckets a result can have
+const MaxBuckets = 10000
+
+// Options configures an aggregation
+type Options struct {
+	GroupBy  []string      // filter fields: 'type', 'id', 'payload.path' or a bare payload path
+	Interval time.Duration // also bucket events by timestamp when positive
+}
+
+// Result is the event counts of each group. With an interval every series also has its
+// counts per time bucket, aligned with Buckets.
+type Result struct {
+	Topic    string   `json:"topic"`
+	GroupBy  []string `json:"groupBy"`
+	Interval string   `json:"interval,omitempty"`
+	Events   int64    `json:"events"`
+	Buckets  []string `json:"buckets,omitempty"` // bucket start times, oldest first
+	Series   []Series `json:"series"`            // largest count first
+}
+
+// Series is the count of the events with one combination of group values
+type Series struct {
+	Key    []string `json:"key"` // one value per GroupBy field
+	Count  int64    `json:"count"`
+	Counts []int64  `json:"counts,omitempty"` // per bucket
+}
+
+// Aggregator counts events one at a time
+type Aggregator struct {
+	opts    Options
+	events  int64
+	series  map[string]*Series
+	buckets map[string]map[string]int64 // series key -> bucket -> count
+	times   map[string]bool
+}
+
+// New creates an aggregator
+func New(opts Options) *Aggregator {
+	return &Aggregator{
+		opts:    opts,
+		series:  make(map[string]*Series),
+		buckets: make(map[string]map[string]int64),
+		times:   make(map[string]bool),
+	}
+}
+
+// Add counts an event in its group
+func (a *Aggregator) Add(event client.Event) {
+	a.events++
+	key := make([]string, len(a.opts.GroupBy))
+	for i, field := range a.opts.GroupBy {
+		value, ok := filter.Value(event, field)
+		if !ok {
+			value = MissingValue
+		}
+		key[i] = value
+	}
+	id := strings.Join(key, "\x00")
+
+	series, ok := a.series[id]
+	if !ok {
+		series = &Series{Key: key}
+		a.series[id] = series
+		a.buckets[id] = make(map[string]int64)
+	}
+	series.Count++
+
+	if a.opts.Interval > 0 {
+		bucket := a.bucket(event.Timestamp)
+		a.buckets[id][bucket]++
+		a.times[bucket] = true
+	}
+}
+
+// bucket returns the start of the UTC time bucket an event timestamp falls in
+func (a *Aggregator) bucket(timestamp string) string {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return UnknownTimestamp
+	}
+	return t.UTC().Truncate(a.opts.Interval).Format(time.RFC3339)
+}
+
+// Result returns the counts of the events added so far. It fails if the events span
+// more than MaxBuckets intervals.
+func (a *Aggregator) Result(topic string) (*Result, error) {
+	result := &Result{Topic: topic, GroupBy: a.opts.GroupBy, Events: a.events, Series: []Series{}}
+	if a.opts.Interval > 0 {
+		result.Interval = a.opts.Interval.String()
+		buckets, err := a.bucketRange()
+		if err != nil {
+			return nil, err
+		}
+		result.Buckets = buckets
+	}
+
+	for id, series := range a.series {
+		if a.opts.Interval > 0 {
+			series.Counts = make([]int64, len(result.Buckets))
+			for i, bucket := range result.Buckets {
+				series.Counts[i] = a.buckets[id][bucket]
+			}
+		}
+		result.Series = append(result.Series, *series)
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		if result.Series[i].Count != result.Series[j].Count {
+			return result.Series[i].Count > result.Series[j].Count
+		}
+		return strings.Join(result.Series[i].Key, "\x00") < strings.Join(result.Series[j].Key, "\x00")
+	})
+	return result, nil
+}
+
+// bucketRange returns every bucket from the earliest to the latest seen, so quiet
+// periods show up as zeros, followed by UnknownTimestamp if any event had one
+func (a *Aggregator) bucketRange() ([]string, error) {
+	var first, last time.Time
+	for bucket := range a.times {
+		t, err := time.Parse(time.RFC3339, bucket)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	var buckets []string
+	if !first.IsZero() {
+		if n := last.Sub(first)/a.opts.Interval + 1; n > MaxBuckets {
+			return nil, fmt.Errorf("the events span %d intervals of %s (at most %d); use a longer interval", n, a.opts.Interval, MaxBuckets)
+		}
+		for t := first; !t.After(last); t = t.Add(a.opts.Interval) {
+			buckets = append(buckets, t.Format(time.RFC3339))
+		}
+	}
+	if a.times[UnknownTimestamp] {
+		buckets = append(buckets, UnknownTimestamp)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,133 @@
+// Package annotations keeps the labels, descriptions and owners of a server's topics and
+// consumers that the event store has no room for itself, in a sidecar file per server,
+// ~/.es/annotations/<server host>.json. They are only seen by CLIs sharing the file.
+package annotations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/config"
+)
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Annotations are what is kept of a topic or consumer
+type Annotations struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Owner       *client.Owner     `json:"owner,omitempty"` // topics only
+}
+
+func (a Annotations) empty() bool {
+	return len(a.Labels) == 0 && a.Description == "" && a.Owner == nil
+}
+
+// Sidecar is the annotations kept of a server's topics, by name, and consumers, by ID
+type Sidecar struct {
+	Server    string                 `json:"server"`
+	Topics    map[string]Annotations `json:"topics,omitempty"`
+	Consumers map[string]Annotations `json:"consumers,omitempty"`
+
+	path string
+}
+
+// Open reads the sidecar of a server, which is empty if nothing was kept yet
+func Open(server string) (*Sidecar, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	s := &Sidecar{
+		Server: server,
+		path:   filepath.Join(dir, "annotations", unsafePathChars.ReplaceAllString(host, "_")+".json"),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid annotations %s: %w", s.path, err)
+	}
+	return s, nil
+}
+
+// Path returns the sidecar's file
+func (s *Sidecar) Path() string {
+	return s.path
+}
+
+// SetTopic keeps a topic's annotations, replacing any kept before
+func (s *Sidecar) SetTopic(name string, a Annotations) {
+	if a.empty() {
+		delete(s.Topics, name)
+		return
+	}
+	if s.Topics == nil {
+		s.Topics = map[string]Annotations{}
+	}
+	s.Topics[name] = a
+}
+
+// SetConsumer keeps a consumer's annotations, replacing any kept before
+func (s *Sidecar) SetConsumer(id string, a Annotations) {
+	if a.empty() {
+		delete(s.Consumers, id)
+		return
+	}
+	if s.Consumers == nil {
+		s.Consumers = map[string]Annotations{}
+	}
+	s.Consumers[id] = a
+}
+
+// Save writes the sidecar, replacing the file in one step so a crash never leaves a
+// partial file
+func (s *Sidecar) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	return nil
+}
+
+// MergeLabels returns the labels kept by the server with those of the sidecar added;
+// the server's win where both have a key
+func MergeLabels(server, kept map[string]string) map[string]string {
+	if len(kept) == 0 {
+		return server
+	}
+	merged := make(map[string]string, len(server)+len(kept))
+	for key, value := range kept {
+		merged[key] = value
+	}
+	for key, value := range server {
+		merged[key] = value
+	}
+	return merged
+}
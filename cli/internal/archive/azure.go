@@ -0,0 +1,247 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// azureVersion is the Blob service REST API version requests are made with
+const azureVersion = "2021-08-06"
+
+// azureStore stores files as block blobs in an Azure Storage container. The account and
+// its credentials come from AZURE_STORAGE_CONNECTION_STRING, or AZURE_STORAGE_ACCOUNT with
+// AZURE_STORAGE_KEY or AZURE_STORAGE_SAS_TOKEN.
+type azureStore struct {
+	account    string
+	key        []byte     // shared key; nil when a SAS token is used
+	sas        url.Values // SAS token query parameters
+	endpoint   string     // e.g. https://<account>.blob.core.windows.net
+	container  string
+	prefix     string
+	encryption *Encryption
+}
+
+func newAzureStore(u *url.URL, encryption *Encryption) (*azureStore, error) {
+	container, prefix, err := splitBucket(u.Host, u.Path)
+	if err != nil {
+		return nil, err
+	}
+	if encryption != nil && encryption.Mode != "" {
+		return nil, fmt.Errorf("Azure archives take an encryption scope, not an encryption mode")
+	}
+
+	settings := map[string]string{
+		"AccountName":           os.Getenv("AZURE_STORAGE_ACCOUNT"),
+		"AccountKey":            os.Getenv("AZURE_STORAGE_KEY"),
+		"SharedAccessSignature": os.Getenv("AZURE_STORAGE_SAS_TOKEN"),
+	}
+	if connection := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); connection != "" {
+		for _, part := range strings.Split(connection, ";") {
+			if name, value, ok := strings.Cut(part, "="); ok {
+				settings[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	store := &azureStore{account: settings["AccountName"], container: container, prefix: prefix, encryption: encryption}
+	if store.account == "" {
+		return nil, fmt.Errorf("no Azure storage account: set AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_CONNECTION_STRING")
+	}
+	store.endpoint = strings.TrimSuffix(settings["BlobEndpoint"], "/")
+	if store.endpoint == "" {
+		store.endpoint = "https://" + store.account + ".blob.core.windows.net"
+	}
+
+	switch {
+	case settings["SharedAccessSignature"] != "":
+		store.sas, err = url.ParseQuery(strings.TrimPrefix(settings["SharedAccessSignature"], "?"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Azure SAS token: %w", err)
+		}
+	case settings["AccountKey"] != "":
+		store.key, err = base64.StdEncoding.DecodeString(settings["AccountKey"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid Azure storage account key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("no Azure credentials: set AZURE_STORAGE_KEY or AZURE_STORAGE_SAS_TOKEN, or use AZURE_STORAGE_CONNECTION_STRING")
+	}
+	return store, nil
+}
+
+// newRequest builds a request for a blob, authorized when it is sent
+func (s *azureStore) newRequest(method, name string, query url.Values, body []byte) (*http.Request, error) {
+	path := "/" + s.container + "/" + objectKey(s.prefix, name)
+	if query == nil {
+		query = url.Values{}
+	}
+	for key, values := range s.sas {
+		query[key] = values
+	}
+	target := s.endpoint + (&url.URL{Path: path}).EscapedPath()
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", azureVersion)
+	if s.encryption != nil && s.encryption.KeyID != "" && method == "PUT" {
+		req.Header.Set("x-ms-encryption-scope", s.encryption.KeyID)
+	}
+	return req, nil
+}
+
+// send authorizes and sends a request
+func (s *azureStore) send(req *http.Request, action, name string) (*http.Response, error) {
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	if s.key != nil {
+		s.sign(req)
+	}
+	return doObject(req, action, name)
+}
+
+func (s *azureStore) Put(name string, data []byte) error {
+	if len(data) > partSize {
+		return s.putBlocks(name, data)
+	}
+
+	req, err := s.newRequest("PUT", name, nil, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	resp, err := s.send(req, "write", name)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// putBlocks uploads a large blob as blocks and then commits the list of them
+func (s *azureStore) putBlocks(name string, data []byte) error {
+	var blockList struct {
+		XMLName xml.Name `xml:"BlockList"`
+		Latest  []string `xml:"Latest"`
+	}
+	for i, part := range parts(data) {
+		id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%06d", i)))
+		req, err := s.newRequest("PUT", name, url.Values{"comp": {"block"}, "blockid": {id}}, part)
+		if err != nil {
+			return err
+		}
+		resp, err := s.send(req, "upload block of", name)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		blockList.Latest = append(blockList.Latest, id)
+	}
+
+	body, err := xml.Marshal(blockList)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest("PUT", name, url.Values{"comp": {"blocklist"}}, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	resp, err := s.send(req, "complete upload of", name)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *azureStore) Get(name string) ([]byte, error) {
+	req, err := s.newRequest("GET", name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.send(req, "read", name)
+	if err != nil {
+		return nil, err
+	}
+	return readObject(resp, name)
+}
+
+func (s *azureStore) GetRange(name string, offset, length int64) ([]byte, error) {
+	req, err := s.newRequest("GET", name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-range", rangeHeader(offset, length))
+	resp, err := s.send(req, "read", name)
+	if err != nil {
+		return nil, err
+	}
+	return readObject(resp, name)
+}
+
+func (s *azureStore) URL() string {
+	return (&url.URL{Scheme: "azblob", Host: s.container, Path: "/" + s.prefix}).String()
+}
+
+// sign adds a Shared Key Authorization header to a request
+func (s *azureStore) sign(req *http.Request) {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	var headers []string
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-ms-") {
+			headers = append(headers, lower)
+		}
+	}
+	sort.Strings(headers)
+	var canonicalHeaders strings.Builder
+	for _, name := range headers {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+
+	resource := "/" + s.account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	var params []string
+	for name := range query {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	for _, name := range params {
+		values := query[name]
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(name) + ":" + strings.Join(values, ",")
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, sent as x-ms-date instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalHeaders.String() + resource,
+	}, "\n")
+	signature := base64.StdEncoding.EncodeToString(hmacSHA256(s.key, stringToSign))
+	req.Header.Set("Authorization", "SharedKey "+s.account+":"+signature)
+}
@@ -0,0 +1,78 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// TestAzureSign signs requests as the Azure SDK for Go (azblob v1.6.2) signed them, with
+// the Azurite development account's well-known key
+func TestAzureSign(t *testing.T) {
+	key, _ := base64.StdEncoding.DecodeString("Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw==")
+	store := &azureStore{account: "devstoreaccount1", key: key}
+	blob := "https://devstoreaccount1.blob.core.windows.net/archive/orders/00000001.seg"
+	tests := []struct {
+		name          string
+		method        string
+		url           string
+		body          string
+		header        http.Header
+		authorization string
+	}{
+		{
+			name:          "Get Blob range",
+			method:        "GET",
+			url:           blob,
+			header:        http.Header{"X-Ms-Range": {"bytes=10-29"}},
+			authorization: "SharedKey devstoreaccount1:9I3+51pRaMAArU2c5CMrAmnkiJgAYyUy3vuCrgraANY=",
+		},
+		{
+			name:          "Put Blob",
+			method:        "PUT",
+			url:           blob,
+			body:          "hello",
+			header:        http.Header{"Content-Type": {"application/octet-stream"}, "X-Ms-Blob-Type": {"BlockBlob"}},
+			authorization: "SharedKey devstoreaccount1:f5gxk2oPhGFt9B0IjOzGs2MsWuLb7RcbNj6cEmD+png=",
+		},
+		{
+			name:          "Put Block",
+			method:        "PUT",
+			url:           blob + "?blockid=YmxvY2stMDAwMDAw&comp=block",
+			body:          "hello",
+			header:        http.Header{"Content-Type": {"application/octet-stream"}},
+			authorization: "SharedKey devstoreaccount1:4yc4p7sUR9fqWSwy3SgreYcJdZ0hCnPHD/8ZcqwS8iI=",
+		},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, bytes.NewReader([]byte(tt.body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, values := range tt.header {
+			req.Header[name] = values
+		}
+		req.Header.Set("x-ms-version", "2025-11-05")
+		req.Header.Set("x-ms-date", "Fri, 16 Oct 2026 12:42:49 GMT")
+		store.sign(req)
+
+		if got := req.Header.Get("Authorization"); got != tt.authorization {
+			t.Errorf("%s: Authorization = %s, want %s", tt.name, got, tt.authorization)
+		}
+	}
+}
+
+func TestAzureSASRequest(t *testing.T) {
+	store := &azureStore{account: "acct", endpoint: "https://acct.blob.core.windows.net", container: "archive", prefix: "prod",
+		sas: url.Values{"sv": {"2021-08-06"}, "sig": {"abc/def="}}}
+	req, err := store.newRequest("GET", "orders/00000001.seg", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://acct.blob.core.windows.net/archive/prod/orders/00000001.seg?sig=abc%2Fdef%3D&sv=2021-08-06"
+	if req.URL.String() != want {
+		t.Errorf("URL = %s, want %s", req.URL, want)
+	}
+}
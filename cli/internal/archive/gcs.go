@@ -0,0 +1,349 @@
+package archive
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gcsStore stores files as objects in a Google Cloud Storage bucket, through the JSON
+// API. STORAGE_EMULATOR_HOST points it at an emulator instead.
+type gcsStore struct {
+	bucket     string
+	prefix     string
+	endpoint   string
+	tokens     *gcsTokenSource
+	encryption *Encryption
+}
+
+func newGCSStore(u *url.URL, encryption *Encryption) (*gcsStore, error) {
+	bucket, prefix, err := splitBucket(u.Host, u.Path)
+	if err != nil {
+		return nil, err
+	}
+	if encryption != nil && encryption.Mode != "" {
+		return nil, fmt.Errorf("GCS archives take a KMS key name, not an encryption mode")
+	}
+
+	store := &gcsStore{bucket: bucket, prefix: prefix, endpoint: "https://storage.googleapis.com", encryption: encryption}
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		store.endpoint = strings.TrimSuffix(host, "/")
+		if !strings.Contains(store.endpoint, "://") {
+			store.endpoint = "http://" + store.endpoint
+		}
+		store.tokens = &gcsTokenSource{static: true, token: "emulator"}
+		return store, nil
+	}
+
+	tokens, err := newGCSTokenSource()
+	if err != nil {
+		return nil, err
+	}
+	store.tokens = tokens
+	return store, nil
+}
+
+// send authorizes and sends a request
+func (s *gcsStore) send(req *http.Request, action, name string) (*http.Response, error) {
+	token, err := s.tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return doObject(req, action, name)
+}
+
+// uploadURL returns the URL that starts an upload of an object
+func (s *gcsStore) uploadURL(key, uploadType string) string {
+	query := url.Values{"uploadType": {uploadType}, "name": {key}}
+	if s.encryption != nil && s.encryption.KeyID != "" {
+		query.Set("kmsKeyName", s.encryption.KeyID)
+	}
+	return s.endpoint + "/upload/storage/v1/b/" + url.PathEscape(s.bucket) + "/o?" + query.Encode()
+}
+
+func (s *gcsStore) Put(name string, data []byte) error {
+	key := objectKey(s.prefix, name)
+	if len(data) > partSize {
+		return s.putResumable(key, name, data)
+	}
+
+	req, err := http.NewRequest("POST", s.uploadURL(key, "media"), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := s.send(req, "write", name)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// putResumable uploads a large object in chunks through a resumable upload session
+func (s *gcsStore) putResumable(key, name string, data []byte) error {
+	req, err := http.NewRequest("POST", s.uploadURL(key, "resumable"), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Upload-Content-Type", "application/octet-stream")
+	resp, err := s.send(req, "start upload of", name)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return fmt.Errorf("failed to start upload of %s: no upload session", name)
+	}
+
+	var offset int64
+	total := int64(len(data))
+	for _, part := range parts(data) {
+		req, err := http.NewRequest("PUT", session, bytes.NewReader(part))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(part))-1, total))
+		token, err := s.tokens.Token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := objectHTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to upload part of %s: %w", name, err)
+		}
+		// 308 Resume Incomplete acknowledges a chunk before the last
+		if resp.StatusCode != http.StatusPermanentRedirect && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+			err := objectError("upload part of", name, resp)
+			resp.Body.Close()
+			return err
+		}
+		resp.Body.Close()
+		offset += int64(len(part))
+	}
+	return nil
+}
+
+// objectURL returns the URL that reads an object's data
+func (s *gcsStore) objectURL(name string) string {
+	return s.endpoint + "/storage/v1/b/" + url.PathEscape(s.bucket) + "/o/" + url.PathEscape(objectKey(s.prefix, name)) + "?alt=media"
+}
+
+func (s *gcsStore) Get(name string) ([]byte, error) {
+	req, err := http.NewRequest("GET", s.objectURL(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.send(req, "read", name)
+	if err != nil {
+		return nil, err
+	}
+	return readObject(resp, name)
+}
+
+func (s *gcsStore) GetRange(name string, offset, length int64) ([]byte, error) {
+	req, err := http.NewRequest("GET", s.objectURL(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", rangeHeader(offset, length))
+	resp, err := s.send(req, "read", name)
+	if err != nil {
+		return nil, err
+	}
+	return readObject(resp, name)
+}
+
+func (s *gcsStore) URL() string {
+	return (&url.URL{Scheme: "gs", Host: s.bucket, Path: "/" + s.prefix}).String()
+}
+
+// gcsTokenSource provides OAuth access tokens for Cloud Storage from, in order:
+// GOOGLE_OAUTH_ACCESS_TOKEN, the credentials file in GOOGLE_APPLICATION_CREDENTIALS or
+// gcloud's application default credentials (a service account key or a user's refresh
+// token), or the metadata server of a Google Cloud VM
+type gcsTokenSource struct {
+	mu          sync.Mutex
+	credentials *gcsCredentials // nil for the metadata server
+	static      bool            // a token given in the environment, used as it is
+	token       string
+	expiry      time.Time
+}
+
+// gcsCredentials is a credentials file, of type "service_account" or "authorized_user"
+type gcsCredentials struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+const (
+	gcsScope           = "https://www.googleapis.com/auth/devstorage.read_write"
+	googleTokenURL     = "https://oauth2.googleapis.com/token"
+	gcsMetadataToken   = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	gcsTokenEarlyRenew = time.Minute
+)
+
+func newGCSTokenSource() (*gcsTokenSource, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return &gcsTokenSource{static: true, token: token}, nil
+	}
+
+	path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			candidate := filepath.Join(dir, "gcloud", "application_default_credentials.json")
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+			}
+		}
+	}
+	if path == "" {
+		return &gcsTokenSource{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Google credentials: %w", err)
+	}
+	var credentials gcsCredentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse Google credentials %s: %w", path, err)
+	}
+	if credentials.Type != "service_account" && credentials.Type != "authorized_user" {
+		return nil, fmt.Errorf("unsupported Google credentials type '%s' in %s", credentials.Type, path)
+	}
+	return &gcsTokenSource{credentials: &credentials}, nil
+}
+
+// Token returns a current access token, fetching a new one when it is about to expire
+func (t *gcsTokenSource) Token() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.static || t.token != "" && time.Now().Before(t.expiry.Add(-gcsTokenEarlyRenew)) {
+		return t.token, nil
+	}
+
+	var req *http.Request
+	var err error
+	switch {
+	case t.credentials == nil:
+		req, err = http.NewRequest("GET", gcsMetadataToken, nil)
+		if err == nil {
+			req.Header.Set("Metadata-Flavor", "Google")
+		}
+	case t.credentials.Type == "service_account":
+		req, err = t.serviceAccountRequest()
+	default:
+		req, err = t.refreshTokenRequest()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := doObject(req, "get", "a Google access token")
+	if err != nil {
+		if t.credentials == nil {
+			return "", fmt.Errorf("no Google credentials: set GOOGLE_APPLICATION_CREDENTIALS or run 'gcloud auth application-default login' (%w)", err)
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to parse Google access token: %w", err)
+	}
+	t.token = token.AccessToken
+	t.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return t.token, nil
+}
+
+// serviceAccountRequest exchanges a JWT signed with the service account's key for an
+// access token
+func (t *gcsTokenSource) serviceAccountRequest() (*http.Request, error) {
+	block, _ := pem.Decode([]byte(t.credentials.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key in Google credentials")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in Google credentials: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key in Google credentials is not an RSA key")
+	}
+
+	tokenURL := t.credentials.TokenURI
+	if tokenURL == "" {
+		tokenURL = googleTokenURL
+	}
+	now := time.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   t.credentials.ClientEmail,
+		"scope": gcsScope,
+		"aud":   tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign Google token request: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)},
+	}
+	return formRequest(tokenURL, form)
+}
+
+// refreshTokenRequest exchanges a user's refresh token for an access token
+func (t *gcsTokenSource) refreshTokenRequest() (*http.Request, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {t.credentials.ClientID},
+		"client_secret": {t.credentials.ClientSecret},
+		"refresh_token": {t.credentials.RefreshToken},
+	}
+	return formRequest(googleTokenURL, form)
+}
+
+func formRequest(target string, form url.Values) (*http.Request, error) {
+	req, err := http.NewRequest("POST", target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
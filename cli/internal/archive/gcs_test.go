@@ -0,0 +1,103 @@
+package archive
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGCSServiceAccountToken exchanges a service account's signed JWT for an access
+// token, as in "Using OAuth 2.0 for Server to Server Applications", and checks the JWT
+// verifies with the account's public key
+func TestGCSServiceAccountToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exchanges := 0
+	var tokenURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("token request form = %v, %v", r.Form, err)
+		}
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("assertion has %d parts", len(parts))
+		}
+		header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+		if string(header) != `{"alg":"RS256","typ":"JWT"}` {
+			t.Errorf("JWT header = %s", header)
+		}
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			t.Errorf("JWT signature: %v", err)
+		}
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims struct {
+			Iss, Scope, Aud string
+			Iat, Exp        int64
+		}
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			t.Fatal(err)
+		}
+		if claims.Iss != "archiver@project.iam.gserviceaccount.com" || claims.Scope != gcsScope || claims.Aud != tokenURL || claims.Exp-claims.Iat != 3600 {
+			t.Errorf("JWT claims = %+v", claims)
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600,"token_type":"Bearer"}`, exchanges)
+	}))
+	defer server.Close()
+	tokenURL = server.URL + "/token"
+
+	tokens := &gcsTokenSource{credentials: &gcsCredentials{
+		Type:        "service_account",
+		ClientEmail: "archiver@project.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:    tokenURL,
+	}}
+	for i := 0; i < 2; i++ {
+		if token, err := tokens.Token(); err != nil || token != "token-1" {
+			t.Errorf("Token = %q, %v, want token-1", token, err)
+		}
+	}
+
+	// A token about to expire is replaced
+	tokens.expiry = time.Now().Add(30 * time.Second)
+	if token, err := tokens.Token(); err != nil || token != "token-2" {
+		t.Errorf("Token after expiry = %q, %v, want token-2", token, err)
+	}
+}
+
+func TestGCSServiceAccountKeyErrors(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tests := []struct {
+		key   string
+		error string
+	}{
+		{"not a key", "invalid private key"},
+		{string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})), "invalid private key"},
+	}
+	for _, tt := range tests {
+		tokens := &gcsTokenSource{credentials: &gcsCredentials{Type: "service_account", PrivateKey: tt.key}}
+		if _, err := tokens.serviceAccountRequest(); err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("serviceAccountRequest error = %v, want one containing %q", err, tt.error)
+		}
+	}
+}
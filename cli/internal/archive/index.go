@@ -0,0 +1,110 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+)
+
+// indexVersion is the version of the index file format written by EncodeSegment
+const indexVersion = 1
+
+// SegmentIndex lists the blocks of a segment with the range of event IDs, timestamps and
+// the event types each one holds, so queries can read only the blocks that can match
+type SegmentIndex struct {
+	Version int          `json:"version"`
+	Blocks  []IndexBlock `json:"blocks"`
+}
+
+// IndexBlock describes one independently compressed block of a segment
+type IndexBlock struct {
+	Offset         int64    `json:"offset"`
+	Length         int64    `json:"length"`
+	Count          int      `json:"count"`
+	FirstSequence  int64    `json:"firstSequence"`
+	LastSequence   int64    `json:"lastSequence"`
+	FirstTimestamp string   `json:"firstTimestamp"`
+	LastTimestamp  string   `json:"lastTimestamp"`
+	Types          []string `json:"types"`
+	SHA256         string   `json:"sha256"`
+}
+
+func newIndexBlock(events []client.Event, offset, length int64, checksum string) IndexBlock {
+	block := IndexBlock{
+		Offset:         offset,
+		Length:         length,
+		Count:          len(events),
+		FirstSequence:  sequenceOf(events[0].ID),
+		LastSequence:   sequenceOf(events[len(events)-1].ID),
+		FirstTimestamp: events[0].Timestamp,
+		LastTimestamp:  events[len(events)-1].Timestamp,
+		SHA256:         checksum,
+	}
+
+	types := make(map[string]bool)
+	for _, event := range events {
+		if !types[event.Type] {
+			types[event.Type] = true
+			block.Types = append(block.Types, event.Type)
+		}
+	}
+	sort.Strings(block.Types)
+	return block
+}
+
+// IndexName returns the name of the index file of a segment file
+func IndexName(segmentFile string) string {
+	return segmentFile + ".idx.json"
+}
+
+// EncodeIndex encodes a segment index as JSON
+func EncodeIndex(index *SegmentIndex) ([]byte, error) {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode segment index: %w", err)
+	}
+	return data, nil
+}
+
+func decodeIndex(data []byte) (*SegmentIndex, error) {
+	var index SegmentIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse segment index: %w", err)
+	}
+	if index.Version != indexVersion {
+		return nil, fmt.Errorf("unsupported segment index version %d", index.Version)
+	}
+	return &index, nil
+}
+
+// readBlock reads and verifies one block of a segment
+func readBlock(store Store, file string, block IndexBlock) ([]client.Event, error) {
+	data, err := store.GetRange(file, block.Offset, block.Length)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != block.SHA256 {
+		return nil, fmt.Errorf("checksum mismatch in block at offset %d", block.Offset)
+	}
+	return DecodeSegment(data, "")
+}
+
+func sequenceOf(id string) int64 {
+	parsed, err := eventid.Parse(id)
+	if err != nil {
+		return 0
+	}
+	return parsed.Sequence
+}
+
+func parseTimestamp(value string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	return t, err == nil
+}
@@ -0,0 +1,90 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Encryption asks object storage to encrypt archived files at rest. Mode applies to S3
+// ("AES256" or "aws:kms"); KeyID is the KMS key for S3 and GCS, or the encryption scope
+// for Azure.
+type Encryption struct {
+	Mode  string `json:"mode,omitempty"`
+	KeyID string `json:"keyId,omitempty"`
+}
+
+// partSize is the size of the parts that larger files are uploaded to object storage
+// in. It is a multiple of 256 KiB, as GCS requires, and above S3's 5 MiB minimum.
+const partSize = 8 * 1024 * 1024
+
+var objectHTTPClient = &http.Client{Timeout: 5 * time.Minute}
+
+// objectKey joins a store's prefix and a file name into an object name
+func objectKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
+
+// splitBucket splits the host and path of an object storage URL into the bucket or
+// container and the prefix of the archive's objects
+func splitBucket(host, path string) (string, string, error) {
+	if host == "" {
+		return "", "", fmt.Errorf("archive location has no bucket")
+	}
+	return host, strings.Trim(path, "/"), nil
+}
+
+// objectError describes a failed object storage request, with the start of the body the
+// service sent to explain it
+func objectError(action, name string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		return fmt.Errorf("failed to %s %s: HTTP %d", action, name, resp.StatusCode)
+	}
+	return fmt.Errorf("failed to %s %s: HTTP %d: %s", action, name, resp.StatusCode, message)
+}
+
+// doObject sends a request to object storage, returning the response of a 2xx status
+// and an error describing any other. The caller closes the body.
+func doObject(req *http.Request, action, name string) (*http.Response, error) {
+	resp, err := objectHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s %s: %w", action, name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, objectError(action, name, resp)
+	}
+	return resp, nil
+}
+
+// readObject reads the body of a successful response
+func readObject(resp *http.Response, name string) ([]byte, error) {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// rangeHeader formats an HTTP Range header value for length bytes from offset
+func rangeHeader(offset, length int64) string {
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+// parts splits data into upload parts of partSize bytes
+func parts(data []byte) [][]byte {
+	var chunks [][]byte
+	for len(data) > partSize {
+		chunks = append(chunks, data[:partSize])
+		data = data[partSize:]
+	}
+	return append(chunks, data)
+}
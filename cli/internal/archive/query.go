@@ -0,0 +1,162 @@
+package archive
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Query selects events from a cold tier. Zero values leave a bound open.
+type Query struct {
+	AfterSequence int64     // only events with a sequence greater than this
+	ToSequence    int64     // only events with a sequence up to and including this
+	Since         time.Time // only events at or after this time
+	Until         time.Time // only events before this time
+	Types         []string  // only events of these types
+	Limit         int       // stop after this many events
+}
+
+// QueryStats reports how much of a cold tier a query had to read
+type QueryStats struct {
+	Segments       int `json:"segments"`
+	SegmentsRead   int `json:"segmentsRead"`
+	Blocks         int `json:"blocks"`
+	BlocksRead     int `json:"blocksRead"`
+	EventsScanned  int `json:"eventsScanned"`
+	UnindexedReads int `json:"unindexedReads"`
+}
+
+// QueryTier returns the tiered events matching a query in order. Segments and blocks
+// whose index shows they cannot match are skipped without being read; segments tiered
+// before indexing are read in full.
+func QueryTier(manifest *TierManifest, q Query) ([]client.Event, QueryStats, error) {
+	stats := QueryStats{Segments: len(manifest.Segments)}
+	store, err := Open(manifest.Location)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	types := make(map[string]bool, len(q.Types))
+	for _, t := range q.Types {
+		types[t] = true
+	}
+
+	var events []client.Event
+	collect := func(candidates []client.Event) bool {
+		for _, event := range candidates {
+			stats.EventsScanned++
+			if q.matches(event, types) {
+				events = append(events, event)
+				if q.Limit > 0 && len(events) >= q.Limit {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for _, segment := range manifest.Segments {
+		if !q.overlaps(sequenceOf(segment.FirstEventID), sequenceOf(segment.LastEventID), segment.FirstTimestamp, segment.LastTimestamp) {
+			continue
+		}
+		stats.SegmentsRead++
+
+		if segment.Index == "" {
+			data, err := store.Get(segment.File)
+			if err != nil {
+				return nil, stats, err
+			}
+			segmentEvents, err := DecodeSegment(data, segment.SHA256)
+			if err != nil {
+				return nil, stats, fmt.Errorf("cold tier segment %s: %w", segment.File, err)
+			}
+			stats.UnindexedReads++
+			if !collect(segmentEvents) {
+				return events, stats, nil
+			}
+			continue
+		}
+
+		data, err := store.Get(segment.Index)
+		if err != nil {
+			return nil, stats, err
+		}
+		index, err := decodeIndex(data)
+		if err != nil {
+			return nil, stats, fmt.Errorf("cold tier index %s: %w", segment.Index, err)
+		}
+
+		stats.Blocks += len(index.Blocks)
+		for _, block := range index.Blocks {
+			if !q.overlaps(block.FirstSequence, block.LastSequence, block.FirstTimestamp, block.LastTimestamp) || !q.hasType(block.Types, types) {
+				continue
+			}
+			stats.BlocksRead++
+			blockEvents, err := readBlock(store, segment.File, block)
+			if err != nil {
+				return nil, stats, fmt.Errorf("cold tier segment %s: %w", segment.File, err)
+			}
+			if !collect(blockEvents) {
+				return events, stats, nil
+			}
+		}
+	}
+	return events, stats, nil
+}
+
+// overlaps reports whether a range of events, given by its first and last event, could
+// hold a match. Events are tiered in publish order, so timestamps increase through it.
+func (q Query) overlaps(firstSequence, lastSequence int64, firstTimestamp, lastTimestamp string) bool {
+	if q.AfterSequence > 0 && lastSequence <= q.AfterSequence {
+		return false
+	}
+	if q.ToSequence > 0 && firstSequence > q.ToSequence {
+		return false
+	}
+	if last, ok := parseTimestamp(lastTimestamp); ok && !q.Since.IsZero() && last.Before(q.Since) {
+		return false
+	}
+	if first, ok := parseTimestamp(firstTimestamp); ok && !q.Until.IsZero() && !first.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+func (q Query) hasType(blockTypes []string, types map[string]bool) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range blockTypes {
+		if types[t] {
+			return true
+		}
+	}
+	return false
+}
+
+func (q Query) matches(event client.Event, types map[string]bool) bool {
+	sequence := sequenceOf(event.ID)
+	if q.AfterSequence > 0 && sequence <= q.AfterSequence {
+		return false
+	}
+	if q.ToSequence > 0 && sequence > q.ToSequence {
+		return false
+	}
+	if len(types) > 0 && !types[event.Type] {
+		return false
+	}
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		timestamp, ok := parseTimestamp(event.Timestamp)
+		if !ok {
+			return false
+		}
+		if !q.Since.IsZero() && timestamp.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && !timestamp.Before(q.Until) {
+			return false
+		}
+	}
+	return true
+}
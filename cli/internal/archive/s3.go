@@ -0,0 +1,394 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3Store stores files as objects in an S3 bucket, or a bucket of an S3-compatible
+// service given by AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL
+type s3Store struct {
+	bucket     string
+	prefix     string
+	region     string
+	endpoint   *url.URL // custom endpoint, addressed path-style; nil for AWS
+	creds      awsCredentials
+	encryption *Encryption
+}
+
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+func newS3Store(u *url.URL, encryption *Encryption) (*s3Store, error) {
+	bucket, prefix, err := splitBucket(u.Host, u.Path)
+	if err != nil {
+		return nil, err
+	}
+	if encryption != nil && encryption.Mode != "" && encryption.Mode != "AES256" && encryption.Mode != "aws:kms" {
+		return nil, fmt.Errorf("invalid S3 encryption '%s' (use AES256 or aws:kms)", encryption.Mode)
+	}
+
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+	creds, err := loadAWSCredentials(profile)
+	if err != nil {
+		return nil, err
+	}
+	store := &s3Store{bucket: bucket, prefix: prefix, region: awsRegion(profile), creds: *creds, encryption: encryption}
+
+	for _, name := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if value := os.Getenv(name); value != "" {
+			endpoint, err := url.Parse(value)
+			if err != nil || endpoint.Host == "" {
+				return nil, fmt.Errorf("invalid %s '%s'", name, value)
+			}
+			store.endpoint = endpoint
+			break
+		}
+	}
+	return store, nil
+}
+
+// loadAWSCredentials reads credentials from the environment, or else from a profile of
+// the shared credentials file
+func loadAWSCredentials(profile string) (*awsCredentials, error) {
+	if id := os.Getenv("AWS_ACCESS_KEY_ID"); id != "" {
+		return &awsCredentials{
+			AccessKeyID:     id,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	values, err := readINISection(path, profile)
+	if err != nil {
+		return nil, err
+	}
+	if values["aws_access_key_id"] == "" {
+		return nil, fmt.Errorf("no AWS credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or add profile '%s' to %s", profile, path)
+	}
+	return &awsCredentials{
+		AccessKeyID:     values["aws_access_key_id"],
+		SecretAccessKey: values["aws_secret_access_key"],
+		SessionToken:    values["aws_session_token"],
+	}, nil
+}
+
+// awsRegion returns the region from the environment or the profile's config, defaulting
+// to us-east-1
+func awsRegion(profile string) string {
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(name); region != "" {
+			return region
+		}
+	}
+	path := os.Getenv("AWS_CONFIG_FILE")
+	if path == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".aws", "config")
+		}
+	}
+	section := "profile " + profile
+	if profile == "default" {
+		section = "default"
+	}
+	if values, err := readINISection(path, section); err == nil && values["region"] != "" {
+		return values["region"]
+	}
+	return "us-east-1"
+}
+
+// readINISection returns the keys of a section of an INI file such as ~/.aws/credentials.
+// A missing file has no sections.
+func readINISection(path, section string) (map[string]string, error) {
+	values := map[string]string{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	current := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = strings.TrimSpace(line[1 : len(line)-1])
+		case current == section:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return values, scanner.Err()
+}
+
+// objectURL returns the URL of an object, with an optional query
+func (s *s3Store) objectURL(key string, query url.Values) *url.URL {
+	u := &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.region), Path: "/" + key}
+	if s.endpoint != nil {
+		u = &url.URL{Scheme: s.endpoint.Scheme, Host: s.endpoint.Host, Path: strings.TrimSuffix(s.endpoint.Path, "/") + "/" + s.bucket + "/" + key}
+	}
+	u.RawPath = awsEscapePath(u.Path)
+	u.RawQuery = query.Encode()
+	return u
+}
+
+// newRequest builds a request for an object, signed when it is sent
+func (s *s3Store) newRequest(method, key string, query url.Values, body []byte) (*http.Request, error) {
+	return http.NewRequest(method, s.objectURL(key, query).String(), bytes.NewReader(body))
+}
+
+// encrypt adds the server-side encryption headers to a request that creates an object
+func (s *s3Store) encrypt(req *http.Request) {
+	if s.encryption == nil || s.encryption.Mode == "" {
+		return
+	}
+	req.Header.Set("x-amz-server-side-encryption", s.encryption.Mode)
+	if s.encryption.KeyID != "" {
+		req.Header.Set("x-amz-server-side-encryption-aws-kms-key-id", s.encryption.KeyID)
+	}
+}
+
+// send signs and sends a request
+func (s *s3Store) send(req *http.Request, body []byte, action, name string) (*http.Response, error) {
+	s.sign(req, body, time.Now().UTC())
+	return doObject(req, action, name)
+}
+
+func (s *s3Store) Put(name string, data []byte) error {
+	key := objectKey(s.prefix, name)
+	if len(data) > partSize {
+		return s.putMultipart(key, name, data)
+	}
+
+	req, err := s.newRequest("PUT", key, nil, data)
+	if err != nil {
+		return err
+	}
+	s.encrypt(req)
+	resp, err := s.send(req, data, "write", name)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// putMultipart uploads a large object in parts, aborting the upload if a part fails
+func (s *s3Store) putMultipart(key, name string, data []byte) error {
+	req, err := s.newRequest("POST", key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	s.encrypt(req)
+	resp, err := s.send(req, nil, "start upload of", name)
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start upload of %s: %w", name, err)
+	}
+
+	if err := s.uploadParts(key, name, initiated.UploadID, data); err != nil {
+		if req, reqErr := s.newRequest("DELETE", key, url.Values{"uploadId": {initiated.UploadID}}, nil); reqErr == nil {
+			if resp, abortErr := s.send(req, nil, "abort upload of", name); abortErr == nil {
+				resp.Body.Close()
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (s *s3Store) uploadParts(key, name, uploadID string, data []byte) error {
+	var completed struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}
+	for i, part := range parts(data) {
+		query := url.Values{"partNumber": {strconv.Itoa(i + 1)}, "uploadId": {uploadID}}
+		req, err := s.newRequest("PUT", key, query, part)
+		if err != nil {
+			return err
+		}
+		resp, err := s.send(req, part, "upload part of", name)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		completed.Parts = append(completed.Parts, completedPart{PartNumber: i + 1, ETag: resp.Header.Get("ETag")})
+	}
+
+	body, err := xml.Marshal(completed)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest("POST", key, url.Values{"uploadId": {uploadID}}, body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.send(req, body, "complete upload of", name)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 can report a failure to complete in the body of a 200 response
+	var result struct {
+		XMLName xml.Name
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err == nil && result.XMLName.Local == "Error" {
+		return fmt.Errorf("failed to complete upload of %s: %s", name, result.Message)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(name string) ([]byte, error) {
+	req, err := s.newRequest("GET", objectKey(s.prefix, name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.send(req, nil, "read", name)
+	if err != nil {
+		return nil, err
+	}
+	return readObject(resp, name)
+}
+
+func (s *s3Store) GetRange(name string, offset, length int64) ([]byte, error) {
+	req, err := s.newRequest("GET", objectKey(s.prefix, name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", rangeHeader(offset, length))
+	resp, err := s.send(req, nil, "read", name)
+	if err != nil {
+		return nil, err
+	}
+	return readObject(resp, name)
+}
+
+func (s *s3Store) URL() string {
+	return (&url.URL{Scheme: "s3", Host: s.bucket, Path: "/" + s.prefix}).String()
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to a request, signing its
+// host, its range and its x-amz- headers
+func (s *s3Store) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if s.creds.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.creds.SessionToken)
+	}
+
+	signed := []string{"host"}
+	canonicalHeaders := "host:" + req.URL.Host + "\n"
+	var names []string
+	for name := range req.Header {
+		if lower := strings.ToLower(name); lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		signed = append(signed, name)
+		canonicalHeaders += name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n"
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.creds.SecretAccessKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes a query with sorted keys and %20 for spaces, as SigV4 expects
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// awsEscapePath percent-encodes every byte of a path except unreserved characters and
+// slashes, as SigV4 expects
+func awsEscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,124 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Segment describes one archived file of consecutive events
+type Segment struct {
+	File           string `json:"file"`
+	FirstEventID   string `json:"firstEventId"`
+	LastEventID    string `json:"lastEventId"`
+	Count          int    `json:"count"`
+	FirstTimestamp string `json:"firstTimestamp"`
+	LastTimestamp  string `json:"lastTimestamp"`
+	SHA256         string `json:"sha256"`
+	Index          string `json:"index,omitempty"` // block index file, absent for segments written before indexing
+	CreatedAt      string `json:"createdAt"`
+}
+
+// blockSize is the number of events per independently compressed block of a segment
+const blockSize = 256
+
+// EncodeSegment encodes events as gzipped newline-delimited JSON, returning the data, its
+// SHA-256 checksum and an index of its blocks. Every blockSize events are compressed as
+// a separate gzip member, so the file is still a valid gzip stream but a block can be
+// decompressed on its own after seeking to it.
+func EncodeSegment(events []client.Event) ([]byte, string, *SegmentIndex, error) {
+	var buf bytes.Buffer
+	index := &SegmentIndex{Version: indexVersion}
+
+	for start := 0; start < len(events); start += blockSize {
+		end := start + blockSize
+		if end > len(events) {
+			end = len(events)
+		}
+		block := events[start:end]
+
+		offset := buf.Len()
+		gz := gzip.NewWriter(&buf)
+		encoder := json.NewEncoder(gz)
+		for _, event := range block {
+			if err := encoder.Encode(event); err != nil {
+				return nil, "", nil, fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+			}
+		}
+		if err := gz.Close(); err != nil {
+			return nil, "", nil, fmt.Errorf("failed to compress segment: %w", err)
+		}
+
+		blockSum := sha256.Sum256(buf.Bytes()[offset:])
+		index.Blocks = append(index.Blocks, newIndexBlock(block, int64(offset), int64(buf.Len()-offset), hex.EncodeToString(blockSum[:])))
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), hex.EncodeToString(sum[:]), index, nil
+}
+
+// DecodeSegment decodes a segment, verifying it against the checksum if one is given
+func DecodeSegment(data []byte, checksum string) ([]client.Event, error) {
+	if checksum != "" {
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != checksum {
+			return nil, fmt.Errorf("checksum mismatch")
+		}
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress segment: %w", err)
+	}
+	defer gz.Close()
+
+	var events []client.Event
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var event client.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("failed to parse event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read segment: %w", err)
+	}
+	return events, nil
+}
+
+// VerifySegment re-reads an archived segment, and its index if it has one, checking the
+// segment against its checksum and number of events
+func VerifySegment(store Store, segment Segment) error {
+	data, err := store.Get(segment.File)
+	if err != nil {
+		return err
+	}
+	events, err := DecodeSegment(data, segment.SHA256)
+	if err != nil {
+		return err
+	}
+	if len(events) != segment.Count {
+		return fmt.Errorf("holds %d event(s), expected %d", len(events), segment.Count)
+	}
+	if segment.Index != "" {
+		data, err := store.Get(segment.Index)
+		if err != nil {
+			return err
+		}
+		if _, err := decodeIndex(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package archive
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store is a location that archived files are written to and read back from
+type Store interface {
+	// Put writes a file, replacing any existing file of the same name
+	Put(name string, data []byte) error
+	// Get reads a file
+	Get(name string) ([]byte, error)
+	// GetRange reads length bytes of a file starting at offset
+	GetRange(name string, offset, length int64) ([]byte, error)
+	// URL returns the location of the store
+	URL() string
+}
+
+// Open returns the store for a location: a directory path, a file:// URL, or an object
+// storage URL (s3://bucket/path, gs://bucket/path or azblob://container/path)
+func Open(location string) (Store, error) {
+	return OpenEncrypted(location, nil)
+}
+
+// OpenEncrypted is Open with server-side encryption of the files written to object
+// storage; encryption may be nil
+func OpenEncrypted(location string, encryption *Encryption) (Store, error) {
+	if location == "" {
+		return nil, fmt.Errorf("archive location is required")
+	}
+
+	u, err := url.Parse(location)
+	local := err != nil || u.Scheme == "" || len(u.Scheme) == 1 || u.Scheme == "file"
+	if local && encryption != nil && *encryption != (Encryption{}) {
+		return nil, fmt.Errorf("encryption options only apply to object storage archives")
+	}
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// Plain paths, including Windows drive letters
+		return newDirStore(location)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return newDirStore(u.Path)
+	case "s3":
+		return newS3Store(u, encryption)
+	case "gs":
+		return newGCSStore(u, encryption)
+	case "az", "azblob":
+		return newAzureStore(u, encryption)
+	default:
+		return nil, fmt.Errorf("unsupported archive location '%s'", location)
+	}
+}
+
+// dirStore stores files in a local directory
+type dirStore struct {
+	root string
+}
+
+func newDirStore(path string) (*dirStore, error) {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive directory '%s': %w", path, err)
+	}
+	return &dirStore{root: root}, nil
+}
+
+func (s *dirStore) path(name string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(name))
+	if !strings.HasPrefix(path, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid archive file name '%s'", name)
+	}
+	return path, nil
+}
+
+func (s *dirStore) Put(name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	// Write to a temporary file first so a failed write never leaves a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *dirStore) Get(name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func (s *dirStore) GetRange(name string, offset, length int64) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer file.Close()
+
+	data := make([]byte, length)
+	if _, err := file.ReadAt(data, offset); err != nil {
+		return nil, fmt.Errorf("failed to read %s at offset %d: %w", name, offset, err)
+	}
+	return data, nil
+}
+
+func (s *dirStore) URL() string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(s.root)}).String()
+}
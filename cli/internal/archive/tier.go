@@ -0,0 +1,147 @@
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/config"
+)
+
+// TierManifest points at the cold tier of a topic: where its archived segments live and
+// which events each one holds. A copy is kept locally so the CLI can read through to
+// the cold tier, and another is written next to the segments.
+type TierManifest struct {
+	Topic      string      `json:"topic"`
+	Server     string      `json:"server"`
+	Location   string      `json:"location"`
+	Encryption *Encryption `json:"encryption,omitempty"` // applied to segments written to object storage
+	Segments   []Segment   `json:"segments"`
+}
+
+// LastEventID returns the ID of the newest tiered event, or "" if nothing is tiered
+func (m *TierManifest) LastEventID() string {
+	if len(m.Segments) == 0 {
+		return ""
+	}
+	return m.Segments[len(m.Segments)-1].LastEventID
+}
+
+// Count returns the number of tiered events
+func (m *TierManifest) Count() int {
+	count := 0
+	for _, segment := range m.Segments {
+		count += segment.Count
+	}
+	return count
+}
+
+// ManifestName is the name of the manifest copy stored with a topic's segments
+func ManifestName(topic string) string {
+	return topic + "/manifest.json"
+}
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// tierManifestPath returns ~/.es/tiers/<server host>/<topic>.json
+func tierManifestPath(server, topic string) (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return filepath.Join(dir, "tiers", unsafePathChars.ReplaceAllString(host, "_"), unsafePathChars.ReplaceAllString(topic, "_")+".json"), nil
+}
+
+// LoadTierManifest returns the local tier manifest of a topic, or nil if the topic has
+// no cold tier
+func LoadTierManifest(server, topic string) (*TierManifest, error) {
+	path, err := tierManifestPath(server, topic)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tier manifest: %w", err)
+	}
+
+	var manifest TierManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse tier manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+// ReadStoreManifest reads the manifest copy stored with a topic's segments, so a cold
+// tier written elsewhere can be attached
+func ReadStoreManifest(store Store, topic string) (*TierManifest, error) {
+	data, err := store.Get(ManifestName(topic))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest TierManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse tier manifest %s: %w", ManifestName(topic), err)
+	}
+	if manifest.Topic != topic {
+		return nil, fmt.Errorf("tier manifest %s is for topic '%s'", ManifestName(topic), manifest.Topic)
+	}
+	return &manifest, nil
+}
+
+// SaveTierManifest writes the manifest locally and to the cold tier itself
+func SaveTierManifest(store Store, manifest *TierManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tier manifest: %w", err)
+	}
+	if err := store.Put(ManifestName(manifest.Topic), data); err != nil {
+		return err
+	}
+
+	path, err := tierManifestPath(manifest.Server, manifest.Topic)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create tier directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write tier manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadTier reads every tiered event of a topic in order
+func ReadTier(manifest *TierManifest) ([]client.Event, error) {
+	store, err := Open(manifest.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []client.Event
+	for _, segment := range manifest.Segments {
+		data, err := store.Get(segment.File)
+		if err != nil {
+			return nil, err
+		}
+		segmentEvents, err := DecodeSegment(data, segment.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("cold tier segment %s: %w", segment.File, err)
+		}
+		events = append(events, segmentEvents...)
+	}
+	return events, nil
+}
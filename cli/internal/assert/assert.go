@@ -0,0 +1,138 @@
+package assert
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// maxMatchedIDs is how many matching event IDs a result lists
+const maxMatchedIDs = 10
+
+// Count is a condition on a number of events, such as '>=1' or '=0'
+type Count struct {
+	Op string // one of =, >=, >, <=, <
+	N  int
+}
+
+// ParseCount parses a count condition; a bare number means exactly that many
+func ParseCount(s string) (Count, error) {
+	s = strings.TrimSpace(s)
+	for _, op := range []string{">=", "<=", "==", "=", ">", "<"} {
+		if strings.HasPrefix(s, op) {
+			n, err := strconv.Atoi(strings.TrimSpace(s[len(op):]))
+			if err != nil || n < 0 {
+				return Count{}, fmt.Errorf("invalid count '%s' (expected e.g. '>=1', '=0' or '<5')", s)
+			}
+			if op == "==" {
+				op = "="
+			}
+			return Count{Op: op, N: n}, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return Count{}, fmt.Errorf("invalid count '%s' (expected e.g. '>=1', '=0' or '<5')", s)
+	}
+	return Count{Op: "=", N: n}, nil
+}
+
+// String formats the count as it is written, e.g. '>=1'
+func (c Count) String() string {
+	return c.Op + strconv.Itoa(c.N)
+}
+
+// Satisfied reports whether n events meet the condition
+func (c Count) Satisfied(n int) bool {
+	switch c.Op {
+	case ">=":
+		return n >= c.N
+	case ">":
+		return n > c.N
+	case "<=":
+		return n <= c.N
+	case "<":
+		return n < c.N
+	default:
+		return n == c.N
+	}
+}
+
+// settled reports whether more events could no longer change whether n events meet the
+// condition, so there is no need to keep waiting
+func (c Count) settled(n int) bool {
+	if c.Op == ">=" || c.Op == ">" {
+		return c.Satisfied(n)
+	}
+	return !c.Satisfied(n)
+}
+
+// Options configures an assertion on a topic's events
+type Options struct {
+	Topic    string
+	Since    string // only count events after this event ID
+	Filters  []*filter.Filter
+	Count    Count
+	Within   time.Duration // how long to wait for the count to be met, 0 to check once
+	Interval time.Duration // how often to check while waiting
+}
+
+// Result is the outcome of an assertion
+type Result struct {
+	Topic       string   `json:"topic"`
+	Filter      string   `json:"filter"`
+	Count       string   `json:"count"`
+	Matched     int      `json:"matched"`
+	Passed      bool     `json:"passed"`
+	MatchedIDs  []string `json:"matchedEventIds"`       // the first matching events
+	LastEventID string   `json:"lastEventId,omitempty"` // last event checked
+	Seconds     float64  `json:"durationSeconds"`
+}
+
+// Check counts the topic's events matching the filters, checking again every interval
+// until the count's outcome cannot change or the time is up
+func Check(ctx context.Context, apiClient *client.Client, opts Options) (*Result, error) {
+	terms := make([]string, len(opts.Filters))
+	for i, f := range opts.Filters {
+		terms[i] = f.String()
+	}
+	result := &Result{Topic: opts.Topic, Filter: strings.Join(terms, " AND "), Count: opts.Count.String(), MatchedIDs: []string{}}
+	started := time.Now()
+	defer func() { result.Seconds = time.Since(started).Seconds() }()
+
+	deadline := started.Add(opts.Within)
+	since := opts.Since
+	for {
+		err := apiClient.ScanEvents(opts.Topic, since, func(events []client.Event) (bool, error) {
+			for _, event := range events {
+				if filter.MatchAll(opts.Filters, event) {
+					result.Matched++
+					if len(result.MatchedIDs) < maxMatchedIDs {
+						result.MatchedIDs = append(result.MatchedIDs, event.ID)
+					}
+				}
+			}
+			since = events[len(events)-1].ID
+			return true, nil
+		})
+		if err != nil {
+			return result, err
+		}
+		result.LastEventID = since
+		result.Passed = opts.Count.Satisfied(result.Matched)
+
+		if opts.Count.settled(result.Matched) || !time.Now().Before(deadline) {
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(opts.Interval):
+		}
+	}
+}
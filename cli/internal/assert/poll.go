@@ -0,0 +1,125 @@
+package assert
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// Outcome is the outcome of an assertion on the state of a topic or consumer
+type Outcome struct {
+	Assertion string  `json:"assertion"` // e.g. topic-sequence-at-least
+	Subject   string  `json:"subject"`   // the topic or consumer checked
+	Expected  string  `json:"expected"`
+	Actual    string  `json:"actual"`
+	Passed    bool    `json:"passed"`
+	Checks    int     `json:"checks"`
+	Seconds   float64 `json:"durationSeconds"`
+}
+
+// Condition checks the state an assertion is on once, recording it in the outcome
+type Condition func(outcome *Outcome) error
+
+// Poll checks a condition every interval until it passes or the time is up; with no
+// time it checks once
+func Poll(ctx context.Context, outcome *Outcome, within, interval time.Duration, condition Condition) error {
+	started := time.Now()
+	defer func() { outcome.Seconds = time.Since(started).Seconds() }()
+
+	deadline := started.Add(within)
+	for {
+		outcome.Checks++
+		if err := condition(outcome); err != nil {
+			return err
+		}
+		if outcome.Passed || !time.Now().Before(deadline) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// SequenceAtLeast checks that a topic has had at least n events published
+func SequenceAtLeast(apiClient *client.Client, topic string, n int64) Condition {
+	return func(outcome *Outcome) error {
+		current, err := apiClient.RefreshTopic(topic)
+		if err != nil {
+			return err
+		}
+		outcome.Actual = "sequence " + strconv.FormatInt(int64(current.Sequence), 10)
+		outcome.Passed = int64(current.Sequence) >= n
+		return nil
+	}
+}
+
+// ConsumerReceived checks that a consumer has received an event of a topic: the event
+// with an ID, or else the first event matching the filters, looked for again each check
+// until one is published
+func ConsumerReceived(apiClient *client.Client, consumerID, topic, eventID string, filters []*filter.Filter) Condition {
+	var target eventid.ID
+	since := ""
+	if eventID != "" {
+		target, _ = eventid.Parse(eventID)
+	}
+	return func(outcome *Outcome) error {
+		if target.Topic == "" {
+			err := apiClient.ScanEvents(topic, since, func(events []client.Event) (bool, error) {
+				for _, event := range events {
+					if filter.MatchAll(filters, event) {
+						id, err := eventid.Parse(event.ID)
+						if err != nil {
+							return false, err
+						}
+						target = id
+						return false, nil
+					}
+				}
+				since = events[len(events)-1].ID
+				return true, nil
+			})
+			if err != nil {
+				return err
+			}
+			if target.Topic == "" {
+				outcome.Actual = "no matching event published"
+				return nil
+			}
+			outcome.Expected = "received " + target.String()
+		}
+
+		consumers, err := apiClient.GetConsumers()
+		if err != nil {
+			return err
+		}
+		for _, consumer := range consumers {
+			if consumer.ID != consumerID {
+				continue
+			}
+			position, ok := consumer.Topics[target.Topic]
+			if !ok {
+				return fmt.Errorf("consumer '%s' is not subscribed to topic '%s'", consumerID, target.Topic)
+			}
+			if position == "" {
+				outcome.Actual = "nothing received"
+				return nil
+			}
+			outcome.Actual = "received up to " + position
+			last, err := eventid.Parse(position)
+			if err != nil {
+				return err
+			}
+			outcome.Passed = last.Sequence >= target.Sequence
+			return nil
+		}
+		return fmt.Errorf("consumer '%s' not found", consumerID)
+	}
+}
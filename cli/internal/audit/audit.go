@@ -0,0 +1,164 @@
+// Package audit records the commands that change an event store, such as publishing
+// events or deleting a topic, for change tracking: who ran each one, when, against which
+// server, the requests it made and how it ended. Records are appended to a local log as
+// a line of JSON each, and can also be sent to a remote endpoint.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/config"
+)
+
+// sendTimeout bounds how long sending a record to a remote endpoint may take
+const sendTimeout = 5 * time.Second
+
+// Record is a command that changed an event store
+type Record struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Host     string    `json:"host"`
+	Context  string    `json:"context,omitempty"`
+	Server   string    `json:"server"`
+	Command  string    `json:"command"`
+	Requests []Request `json:"requests"`
+	ExitCode int       `json:"exitCode"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Request counts the changing requests a command made to one route, such as
+// "POST /topics/{topic}/events"
+type Request struct {
+	Endpoint string `json:"endpoint"`
+	Count    int    `json:"count"`
+	Errors   int    `json:"errors,omitempty"`   // non-2xx responses
+	Failures int    `json:"failures,omitempty"` // requests that got no response
+}
+
+// DefaultFile returns the audit log used unless another is configured, ~/.es/audit.ndjson
+func DefaultFile() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.ndjson"), nil
+}
+
+// Changes returns the requests made between two snapshots of a client's metrics that
+// can change an event store: those other than GET, HEAD and OPTIONS
+func Changes(before, after client.MetricsSnapshot) []Request {
+	counted := map[string]client.EndpointStats{}
+	for _, stats := range before.Endpoints {
+		counted[stats.Endpoint] = stats
+	}
+	var requests []Request
+	for _, stats := range after.Endpoints {
+		if !changes(stats.Endpoint) {
+			continue
+		}
+		earlier := counted[stats.Endpoint]
+		if stats.Requests == earlier.Requests {
+			continue
+		}
+		requests = append(requests, Request{
+			Endpoint: stats.Endpoint,
+			Count:    stats.Requests - earlier.Requests,
+			Errors:   stats.Errors - earlier.Errors,
+			Failures: stats.Failures - earlier.Failures,
+		})
+	}
+	return requests
+}
+
+// changes reports whether requests to an endpoint, "METHOD /route", can change the
+// event store
+func changes(endpoint string) bool {
+	method, _, _ := strings.Cut(endpoint, " ")
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+}
+
+// Append appends a record to an audit log as a line of JSON
+func Append(path string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// Read returns the records of an audit log, oldest first, or none if it doesn't exist
+func Read(path string) ([]*Record, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid audit record at %s:%d: %w", path, line, err)
+		}
+		records = append(records, &record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return records, nil
+}
+
+// Send POSTs a record as JSON to a remote audit endpoint, with extra headers such as
+// for authentication; $VAR and ${VAR} in their values are replaced from the environment
+func Send(endpoint string, headers map[string]string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid audit endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+	resp, err := (&http.Client{Timeout: sendTimeout}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send audit record: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit endpoint answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+// Package auth logs in to event stores behind OAuth2 and OpenID Connect identity
+// providers, with the device code flow, for a browser on any device, or a username and
+// password. A server's tokens are kept in the OS keyring, and its access token is
+// refreshed as it expires.
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/config"
+)
+
+// requestTimeout bounds each request to the identity provider
+const requestTimeout = 30 * time.Second
+
+// expiryMargin is how long before it expires an access token is refreshed, so it
+// doesn't expire on the way to the server
+const expiryMargin = 30 * time.Second
+
+// defaultScopes are asked for unless the config gives others; offline_access asks
+// for a refresh token
+var defaultScopes = []string{"openid", "profile", "offline_access"}
+
+// Token is what logging in to a server gave
+type Token struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	IDToken      string    `json:"idToken,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"` // zero if the provider didn't say
+}
+
+// Valid reports whether the access token can still be used
+func (t *Token) Valid() bool {
+	return t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(expiryMargin).Before(t.Expiry))
+}
+
+// Provider is the identity provider of a server
+type Provider struct {
+	cfg        config.AuthConfig
+	httpClient *http.Client
+	tokenURL   string
+	deviceURL  string
+	pollUnit   time.Duration // the unit of the device code flow's poll interval
+}
+
+// NewProvider returns the identity provider configured for a server, reached with a
+// transport, such as one going through the server's proxy
+func NewProvider(cfg config.AuthConfig, transport http.RoundTripper) *Provider {
+	return &Provider{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: requestTimeout, Transport: transport},
+		tokenURL:   cfg.TokenURL,
+		deviceURL:  cfg.DeviceAuthURL,
+		pollUnit:   time.Second,
+	}
+}
+
+// discover reads the provider's endpoints that aren't configured from its OpenID
+// Connect discovery document
+func (p *Provider) discover() error {
+	if p.tokenURL != "" && p.deviceURL != "" {
+		return nil
+	}
+	if p.cfg.Issuer == "" {
+		if p.tokenURL == "" {
+			return fmt.Errorf("auth needs an issuer or a token-url")
+		}
+		return nil
+	}
+
+	resp, err := p.httpClient.Get(strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return fmt.Errorf("failed to discover the identity provider: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to discover the identity provider: %s", resp.Status)
+	}
+	var discovery struct {
+		TokenEndpoint  string `json:"token_endpoint"`
+		DeviceEndpoint string `json:"device_authorization_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return fmt.Errorf("invalid discovery document from %s: %w", p.cfg.Issuer, err)
+	}
+	if p.tokenURL == "" {
+		p.tokenURL = discovery.TokenEndpoint
+	}
+	if p.deviceURL == "" {
+		p.deviceURL = discovery.DeviceEndpoint
+	}
+	if p.tokenURL == "" {
+		return fmt.Errorf("identity provider %s has no token endpoint", p.cfg.Issuer)
+	}
+	return nil
+}
+
+// DeviceCode is what the user is shown to log in with the device code flow
+type DeviceCode struct {
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string // with the code filled in, if the provider gives one
+	Expires                 time.Time
+}
+
+// DeviceLogin logs in with the device code flow: the user opens a URL on any device
+// and enters the code given to show, while DeviceLogin waits for them to finish, until
+// the code expires or ctx is cancelled
+func (p *Provider) DeviceLogin(ctx context.Context, show func(code DeviceCode)) (*Token, error) {
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+	if p.deviceURL == "" {
+		return nil, fmt.Errorf("the identity provider doesn't support the device code flow; log in with --username, or configure its device-auth-url")
+	}
+
+	var device struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURL         string `json:"verification_url"` // as Google names it
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	form := p.form()
+	form.Set("scope", p.scopes())
+	if err := p.post(ctx, p.deviceURL, form, &device); err != nil {
+		return nil, err
+	}
+	if device.VerificationURI == "" {
+		device.VerificationURI = device.VerificationURL
+	}
+	expires := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+	show(DeviceCode{
+		UserCode:                device.UserCode,
+		VerificationURI:         device.VerificationURI,
+		VerificationURIComplete: device.VerificationURIComplete,
+		Expires:                 expires,
+	})
+
+	interval := time.Duration(max(device.Interval, 5)) * p.pollUnit
+	form = p.form()
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	form.Set("device_code", device.DeviceCode)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		token, err := p.requestToken(ctx, form)
+		var oauthErr *Error
+		if !errors.As(err, &oauthErr) {
+			return token, err
+		}
+		switch oauthErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * p.pollUnit
+		case "access_denied":
+			return nil, fmt.Errorf("login was denied")
+		case "expired_token":
+			return nil, fmt.Errorf("the login code expired; run 'es auth login' again")
+		default:
+			return nil, err
+		}
+	}
+}
+
+// PasswordLogin logs in with a username and password, for providers that allow the
+// resource owner password grant
+func (p *Provider) PasswordLogin(ctx context.Context, username, password string) (*Token, error) {
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+	form := p.form()
+	form.Set("grant_type", "password")
+	form.Set("username", username)
+	form.Set("password", password)
+	form.Set("scope", p.scopes())
+	return p.requestToken(ctx, form)
+}
+
+// Refresh returns a new token for a token's refresh token. The refresh token is kept if
+// the provider doesn't rotate it.
+func (p *Provider) Refresh(ctx context.Context, token *Token) (*Token, error) {
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("no refresh token")
+	}
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+	form := p.form()
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", token.RefreshToken)
+	refreshed, err := p.requestToken(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+	if refreshed.IDToken == "" {
+		refreshed.IDToken = token.IDToken
+	}
+	return refreshed, nil
+}
+
+// Error is an error response from the provider's token or device endpoints
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("identity provider: %s (%s)", e.Description, e.Code)
+	}
+	return "identity provider: " + e.Code
+}
+
+// requestToken asks the token endpoint for a token
+func (p *Provider) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	var response struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		IDToken      string `json:"id_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := p.post(ctx, p.tokenURL, form, &response); err != nil {
+		return nil, err
+	}
+	if response.AccessToken == "" {
+		return nil, fmt.Errorf("identity provider gave no access token")
+	}
+	token := &Token{AccessToken: response.AccessToken, RefreshToken: response.RefreshToken, IDToken: response.IDToken}
+	if response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second).UTC()
+	}
+	return token, nil
+}
+
+// post posts a form to one of the provider's endpoints and reads its JSON response
+// into v, returning an *Error if the provider answers with one
+func (p *Provider) post(ctx context.Context, endpoint string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("invalid identity provider endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("identity provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read identity provider response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var oauthErr Error
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Code != "" {
+			return &oauthErr
+		}
+		return fmt.Errorf("identity provider answered %s", resp.Status)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid identity provider response: %w", err)
+	}
+	return nil
+}
+
+// form returns the parameters every request to the provider has: the client's
+// credentials and the audience
+func (p *Provider) form() url.Values {
+	form := url.Values{}
+	form.Set("client_id", p.cfg.ClientID)
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", os.ExpandEnv(p.cfg.ClientSecret))
+	}
+	if p.cfg.Audience != "" {
+		form.Set("audience", p.cfg.Audience)
+	}
+	return form
+}
+
+func (p *Provider) scopes() string {
+	if len(p.cfg.Scopes) > 0 {
+		return strings.Join(p.cfg.Scopes, " ")
+	}
+	return strings.Join(defaultScopes, " ")
+}
+
+// Claims returns the claims of a JWT, such as the subject and roles, without verifying
+// its signature: they're only shown, the server checks the token itself. A token that
+// isn't a JWT, such as an opaque access token, has none.
+func Claims(jwt string) map[string]interface{} {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil
+	}
+	var claims map[string]interface{}
+	if json.Unmarshal(payload, &claims) != nil {
+		return nil
+	}
+	return claims
+}
+
+// Identity is who a token says the user is, and what they may do
+type Identity struct {
+	User    string    `json:"user"`
+	Subject string    `json:"subject,omitempty"`
+	Issuer  string    `json:"issuer,omitempty"`
+	Roles   []string  `json:"roles"`
+	Scopes  []string  `json:"scopes"`
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// Identity reads the user's identity from a token's claims: their name from the ID
+// token, and their roles and scopes from the access token, where providers such as
+// Keycloak, Auth0 and Entra ID put them, including the roles for clientID
+func (t *Token) Identity(clientID string) Identity {
+	claims := Claims(t.AccessToken)
+	if claims == nil {
+		claims = map[string]interface{}{}
+	}
+	// The ID token's audience is the CLI, not the server
+	for name, value := range Claims(t.IDToken) {
+		if name != "aud" {
+			claims[name] = value
+		}
+	}
+
+	identity := Identity{Expires: t.Expiry}
+	for _, name := range []string{"preferred_username", "email", "name", "sub"} {
+		if user, ok := claims[name].(string); ok && user != "" {
+			identity.User = user
+			break
+		}
+	}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Issuer, _ = claims["iss"].(string)
+
+	identity.Roles = appendStrings(identity.Roles, claims["roles"])
+	identity.Roles = appendStrings(identity.Roles, claims["groups"])
+	if realm, ok := claims["realm_access"].(map[string]interface{}); ok {
+		identity.Roles = appendStrings(identity.Roles, realm["roles"])
+	}
+	if resources, ok := claims["resource_access"].(map[string]interface{}); ok {
+		if client, ok := resources[clientID].(map[string]interface{}); ok {
+			identity.Roles = appendStrings(identity.Roles, client["roles"])
+		}
+	}
+	identity.Roles = appendStrings(identity.Roles, claims["permissions"])
+	if scope, ok := claims["scope"].(string); ok {
+		identity.Scopes = strings.Fields(scope)
+	}
+	identity.Scopes = appendStrings(identity.Scopes, claims["scp"])
+	return identity
+}
+
+// appendStrings appends a claim's strings, given as a list or space-separated, that
+// aren't there already
+func appendStrings(values []string, claim interface{}) []string {
+	var found []string
+	switch claim := claim.(type) {
+	case string:
+		found = strings.Fields(claim)
+	case []interface{}:
+		for _, value := range claim {
+			if s, ok := value.(string); ok {
+				found = append(found, s)
+			}
+		}
+	}
+	for _, value := range found {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
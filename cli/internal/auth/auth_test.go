@@ -0,0 +1,353 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/event-store/cli/internal/config"
+)
+
+// identityProvider is a fake identity provider whose token endpoint answers with the
+// responses given, in turn, recording the forms posted to it and when
+type identityProvider struct {
+	*httptest.Server
+	mu        sync.Mutex
+	responses []string // a JSON body, with an "error" for a 400 response
+	forms     []map[string]string
+	times     []time.Time
+}
+
+func newIdentityProvider(t *testing.T, responses ...string) *identityProvider {
+	idp := &identityProvider{responses: responses}
+	idp.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                        idp.URL,
+				"token_endpoint":                idp.URL + "/token",
+				"device_authorization_endpoint": idp.URL + "/device",
+			})
+		case "/device", "/token":
+			r.ParseForm()
+			form := map[string]string{"path": r.URL.Path}
+			for name := range r.PostForm {
+				form[name] = r.PostForm.Get(name)
+			}
+			idp.forms = append(idp.forms, form)
+			idp.times = append(idp.times, time.Now())
+			if r.URL.Path == "/device" {
+				io.WriteString(w, `{"device_code": "device-1", "user_code": "ABCD-EFGH", "verification_url": "`+idp.URL+`/activate", "expires_in": 600, "interval": 1}`)
+				return
+			}
+			if len(idp.responses) == 0 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			response := idp.responses[0]
+			idp.responses = idp.responses[1:]
+			if strings.Contains(response, `"error"`) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			io.WriteString(w, response)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// tokenForms returns the forms posted to the token endpoint
+func (idp *identityProvider) tokenForms() []map[string]string {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	var forms []map[string]string
+	for _, form := range idp.forms {
+		if form["path"] == "/token" {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
+// jwt returns an unsigned JWT with claims
+func jwt(claims map[string]interface{}) string {
+	payload, _ := json.Marshal(claims)
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+}
+
+func TestDiscover(t *testing.T) {
+	idp := newIdentityProvider(t)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/invalid/.well-known/openid-configuration":
+			io.WriteString(w, `{"token_endpoint":`)
+		case "/empty/.well-known/openid-configuration":
+			io.WriteString(w, `{"issuer": "https://example.com"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer broken.Close()
+
+	tests := []struct {
+		name             string
+		cfg              config.AuthConfig
+		tokenURL, device string
+		error            string
+	}{
+		{name: "discovered", cfg: config.AuthConfig{Issuer: idp.URL + "/"}, tokenURL: idp.URL + "/token", device: idp.URL + "/device"},
+		{name: "configured token URL", cfg: config.AuthConfig{Issuer: idp.URL, TokenURL: "https://example.com/token"}, tokenURL: "https://example.com/token", device: idp.URL + "/device"},
+		{name: "configured", cfg: config.AuthConfig{Issuer: broken.URL, TokenURL: "https://example.com/token", DeviceAuthURL: "https://example.com/device"}, tokenURL: "https://example.com/token", device: "https://example.com/device"},
+		{name: "token URL without issuer", cfg: config.AuthConfig{TokenURL: "https://example.com/token"}, tokenURL: "https://example.com/token"},
+		{name: "nothing", cfg: config.AuthConfig{ClientID: "es"}, error: "auth needs an issuer or a token-url"},
+		{name: "not found", cfg: config.AuthConfig{Issuer: broken.URL}, error: "failed to discover the identity provider: 404 Not Found"},
+		{name: "invalid", cfg: config.AuthConfig{Issuer: broken.URL + "/invalid"}, error: "invalid discovery document from " + broken.URL + "/invalid"},
+		{name: "no token endpoint", cfg: config.AuthConfig{Issuer: broken.URL + "/empty"}, error: "has no token endpoint"},
+	}
+	for _, tt := range tests {
+		p := NewProvider(tt.cfg, nil)
+		err := p.discover()
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("%s: discover error = %v, want one containing %q", tt.name, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || p.tokenURL != tt.tokenURL || p.deviceURL != tt.device {
+			t.Errorf("%s: discover = %q, %q, %v, want %q, %q", tt.name, p.tokenURL, p.deviceURL, err, tt.tokenURL, tt.device)
+		}
+	}
+}
+
+func TestDeviceLogin(t *testing.T) {
+	t.Setenv("ES_TEST_SECRET", "s3cret")
+	idp := newIdentityProvider(t,
+		`{"error": "authorization_pending"}`,
+		`{"error": "slow_down"}`,
+		`{"error": "authorization_pending"}`,
+		`{"access_token": "access-1", "refresh_token": "refresh-1", "id_token": "id-1", "expires_in": 3600}`,
+	)
+	p := NewProvider(config.AuthConfig{Issuer: idp.URL, ClientID: "es", ClientSecret: "$ES_TEST_SECRET", Audience: "https://es.example.com", Scopes: []string{"openid", "events"}}, nil)
+	p.pollUnit = 5 * time.Millisecond
+
+	var shown DeviceCode
+	token, err := p.DeviceLogin(context.Background(), func(code DeviceCode) { shown = code })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.IDToken != "id-1" {
+		t.Errorf("DeviceLogin = %+v", token)
+	}
+	if until := time.Until(token.Expiry); until < 59*time.Minute || until > time.Hour {
+		t.Errorf("token expires in %v, want an hour", until)
+	}
+	if shown.UserCode != "ABCD-EFGH" || shown.VerificationURI != idp.URL+"/activate" || time.Until(shown.Expires) < 9*time.Minute {
+		t.Errorf("shown %+v", shown)
+	}
+
+	device := idp.forms[0]
+	if device["path"] != "/device" || device["client_id"] != "es" || device["client_secret"] != "s3cret" || device["audience"] != "https://es.example.com" || device["scope"] != "openid events" {
+		t.Errorf("device authorization request = %v", device)
+	}
+	polls := idp.tokenForms()
+	if len(polls) != 4 {
+		t.Fatalf("polled %d times, want 4", len(polls))
+	}
+	for _, form := range polls {
+		if form["grant_type"] != "urn:ietf:params:oauth:grant-type:device_code" || form["device_code"] != "device-1" || form["client_secret"] != "s3cret" {
+			t.Errorf("token request = %v", form)
+		}
+	}
+	// The provider's 1 second interval is raised to 5, and slow_down adds 5 to it for
+	// the polls after
+	for i, want := range []int{5, 5, 10, 10} {
+		if gap := idp.times[i+1].Sub(idp.times[i]); gap < time.Duration(want)*p.pollUnit {
+			t.Errorf("poll %d came %v after the last request, want at least %v", i+1, gap, time.Duration(want)*p.pollUnit)
+		}
+	}
+}
+
+func TestDeviceLoginErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		error    string
+	}{
+		{"expired", `{"error": "expired_token"}`, "the login code expired; run 'es auth login' again"},
+		{"denied", `{"error": "access_denied"}`, "login was denied"},
+		{"other", `{"error": "invalid_client", "error_description": "unknown client"}`, "identity provider: unknown client (invalid_client)"},
+		{"no access token", `{"token_type": "Bearer"}`, "identity provider gave no access token"},
+		{"not JSON", `<html>`, "invalid identity provider response"},
+		{"server error", "", "identity provider answered 500 Internal Server Error"},
+	}
+	for _, tt := range tests {
+		var responses []string
+		if tt.response != "" {
+			responses = []string{`{"error": "authorization_pending"}`, tt.response}
+		}
+		idp := newIdentityProvider(t, responses...)
+		p := NewProvider(config.AuthConfig{Issuer: idp.URL, ClientID: "es"}, nil)
+		p.pollUnit = time.Millisecond
+		token, err := p.DeviceLogin(context.Background(), func(DeviceCode) {})
+		if err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: DeviceLogin = %+v, %v, want an error containing %q", tt.name, token, err, tt.error)
+		}
+	}
+
+	// Without a device authorization endpoint
+	p := NewProvider(config.AuthConfig{TokenURL: "https://example.com/token", ClientID: "es"}, nil)
+	if _, err := p.DeviceLogin(context.Background(), func(DeviceCode) {}); err == nil || !strings.Contains(err.Error(), "doesn't support the device code flow") {
+		t.Errorf("DeviceLogin without a device endpoint error = %v", err)
+	}
+
+	// Stopped while waiting for the user
+	idp := newIdentityProvider(t)
+	p = NewProvider(config.AuthConfig{Issuer: idp.URL, ClientID: "es"}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := p.DeviceLogin(ctx, func(DeviceCode) { cancel() }); !errors.Is(err, context.Canceled) {
+		t.Errorf("DeviceLogin cancelled error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	idp := newIdentityProvider(t,
+		`{"access_token": "access-2", "refresh_token": "refresh-2", "expires_in": 300}`,
+		`{"access_token": "access-3"}`,
+		`{"error": "invalid_grant", "error_description": "token revoked"}`,
+	)
+	p := NewProvider(config.AuthConfig{TokenURL: idp.URL + "/token", ClientID: "es"}, nil)
+	old := &Token{AccessToken: "access-1", RefreshToken: "refresh-1", IDToken: "id-1"}
+
+	// A provider that rotates refresh tokens gives a new one
+	rotated, err := p.Refresh(context.Background(), old)
+	if err != nil || rotated.AccessToken != "access-2" || rotated.RefreshToken != "refresh-2" || rotated.IDToken != "id-1" || rotated.Expiry.IsZero() {
+		t.Errorf("Refresh = %+v, %v, want access-2 and refresh-2, keeping the ID token", rotated, err)
+	}
+	// Otherwise the old one is kept
+	kept, err := p.Refresh(context.Background(), rotated)
+	if err != nil || kept.AccessToken != "access-3" || kept.RefreshToken != "refresh-2" || !kept.Expiry.IsZero() {
+		t.Errorf("Refresh = %+v, %v, want access-3 keeping refresh-2", kept, err)
+	}
+	forms := idp.tokenForms()
+	if len(forms) != 2 || forms[0]["grant_type"] != "refresh_token" || forms[0]["refresh_token"] != "refresh-1" || forms[1]["refresh_token"] != "refresh-2" || forms[0]["client_id"] != "es" {
+		t.Errorf("refresh requests = %v", forms)
+	}
+
+	var oauthErr *Error
+	if _, err := p.Refresh(context.Background(), kept); !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" {
+		t.Errorf("Refresh with a revoked token error = %v, want invalid_grant", err)
+	}
+	if _, err := p.Refresh(context.Background(), &Token{AccessToken: "access"}); err == nil || err.Error() != "no refresh token" {
+		t.Errorf("Refresh without a refresh token error = %v", err)
+	}
+}
+
+// TestSession checks that a session refreshes an expired access token once, and stores
+// the rotated refresh token
+func TestSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("ES_KEYRING", "file")
+	idp := newIdentityProvider(t,
+		`{"access_token": "access-2", "refresh_token": "refresh-2", "expires_in": 300}`,
+		`{"error": "invalid_grant"}`,
+	)
+	p := NewProvider(config.AuthConfig{TokenURL: idp.URL + "/token", ClientID: "es"}, nil)
+	expired := &Token{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: time.Now().Add(-time.Minute)}
+	session := NewSession("https://es.example.com/", p, expired)
+
+	if token, err := session.Token(); err != nil || token != "access-2" {
+		t.Fatalf("Token = %q, %v, want access-2", token, err)
+	}
+	saved, err := LoadToken("https://es.example.com")
+	if err != nil || saved.AccessToken != "access-2" || saved.RefreshToken != "refresh-2" {
+		t.Errorf("saved token = %+v, %v, want access-2 and refresh-2", saved, err)
+	}
+	// A request rejected with the old token doesn't refresh again
+	if token, err := session.Refresh("access-1"); err != nil || token != "access-2" {
+		t.Errorf("Refresh(access-1) = %q, %v, want access-2", token, err)
+	}
+	if len(idp.tokenForms()) != 1 {
+		t.Errorf("refreshed %d times, want 1", len(idp.tokenForms()))
+	}
+
+	var expiredErr *ExpiredError
+	if _, err := session.Refresh("access-2"); !errors.As(err, &expiredErr) || expiredErr.Server != "https://es.example.com" {
+		t.Errorf("Refresh with a revoked refresh token error = %v, want an ExpiredError", err)
+	}
+}
+
+func TestClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"42","roles":["admin"]}`))
+	tests := []struct {
+		name string
+		jwt  string
+		want map[string]interface{}
+	}{
+		{"JWT", "header." + payload + ".signature", map[string]interface{}{"sub": "42", "roles": []interface{}{"admin"}}},
+		{"padded", "header." + base64.URLEncoding.EncodeToString([]byte(`{"sub":"4"}`)) + ".signature", map[string]interface{}{"sub": "4"}},
+		{"unsigned", "header." + payload + ".", map[string]interface{}{"sub": "42", "roles": []interface{}{"admin"}}},
+		{"opaque", "2YotnFZFEjr1zCsicMWpAA", nil},
+		{"two parts", "header." + payload, nil},
+		{"four parts", "header." + payload + ".signature.extra", nil},
+		{"not base64", "header.not*base64.signature", nil},
+		{"standard base64", "header." + base64.StdEncoding.EncodeToString([]byte(`{"a":"??>"}`)) + ".signature", nil},
+		{"not JSON", "header." + base64.RawURLEncoding.EncodeToString([]byte("sub=42")) + ".signature", nil},
+		{"not an object", "header." + base64.RawURLEncoding.EncodeToString([]byte(`["sub"]`)) + ".signature", nil},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		if got := Claims(tt.jwt); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Claims(%q) = %v, want %v", tt.name, tt.jwt, got, tt.want)
+		}
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	expiry := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	token := &Token{
+		AccessToken: jwt(map[string]interface{}{
+			"sub":             "user-1",
+			"iss":             "https://idp.example.com",
+			"aud":             "es-server",
+			"roles":           []interface{}{"reader"},
+			"groups":          "ops reader",
+			"realm_access":    map[string]interface{}{"roles": []interface{}{"admin", "reader"}},
+			"resource_access": map[string]interface{}{"es": map[string]interface{}{"roles": []interface{}{"publisher"}}, "other": map[string]interface{}{"roles": []interface{}{"ignored"}}},
+			"permissions":     []interface{}{"read:events", 7},
+			"scope":           "openid events:read",
+			"scp":             []interface{}{"events:read", "events:write"},
+		}),
+		IDToken: jwt(map[string]interface{}{"aud": "es", "email": "ada@example.com", "name": "Ada"}),
+		Expiry:  expiry,
+	}
+	want := Identity{
+		User:    "ada@example.com",
+		Subject: "user-1",
+		Issuer:  "https://idp.example.com",
+		Roles:   []string{"reader", "ops", "admin", "publisher", "read:events"},
+		Scopes:  []string{"openid", "events:read", "events:write"},
+		Expires: expiry,
+	}
+	if got := token.Identity("es"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Identity = %+v, want %+v", got, want)
+	}
+
+	// An opaque access token has no claims
+	if got := (&Token{AccessToken: "opaque"}).Identity("es"); !reflect.DeepEqual(got, Identity{}) {
+		t.Errorf("Identity of an opaque token = %+v", got)
+	}
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/event-store/cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService names the CLI's entries in the OS keyring, one per server URL
+const keyringService = "es-cli"
+
+// errNotFound is returned when no tokens are stored for a server
+var errNotFound = errors.New("not logged in")
+
+// Storage returns where tokens are kept: "keychain" on macOS, "credential-manager" on
+// Windows, "secret-service" with secret-tool on Linux (GNOME Keyring or KWallet), or
+// "file", ~/.es/tokens.json readable only by the user, where there's no OS keyring or
+// when ES_KEYRING=file, as for CI machines. The keychain and Credential Manager are used
+// through go-keyring, which hands macOS's security tool the tokens on its stdin rather
+// than its command line, where other users' ps would show them.
+func Storage() string {
+	if os.Getenv("ES_KEYRING") == "file" {
+		return "file"
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return "keychain"
+		}
+	case "windows":
+		return "credential-manager"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return "secret-service"
+		}
+	}
+	return "file"
+}
+
+// SaveToken stores a server's tokens
+func SaveToken(server string, token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	server = strings.TrimSuffix(server, "/")
+	switch Storage() {
+	case "keychain", "credential-manager":
+		if err = keyring.Set(keyringService, server, string(data)); errors.Is(err, keyring.ErrSetDataTooBig) {
+			err = fmt.Errorf("the tokens are too large for the OS keyring")
+		}
+	case "secret-service":
+		_, err = secretTool(data, "store", "--label", "es "+server, "service", keyringService, "account", server)
+	default:
+		err = updateTokenFile(func(tokens map[string]*Token) { tokens[server] = token })
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store tokens: %w (set ES_KEYRING=file to keep them in ~/.es/tokens.json)", err)
+	}
+	return nil
+}
+
+// LoadToken returns a server's stored tokens, or errNotFound
+func LoadToken(server string) (*Token, error) {
+	server = strings.TrimSuffix(server, "/")
+	var data []byte
+	var err error
+	switch Storage() {
+	case "keychain", "credential-manager":
+		var secret string
+		secret, err = keyring.Get(keyringService, server)
+		if errors.Is(err, keyring.ErrNotFound) {
+			err = errNotFound
+		}
+		data = []byte(secret)
+	case "secret-service":
+		data, err = secretTool(nil, "lookup", "service", keyringService, "account", server)
+	default:
+		var tokens map[string]*Token
+		if tokens, err = readTokenFile(); err == nil {
+			if token, ok := tokens[server]; ok {
+				return token, nil
+			}
+			err = errNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var token Token
+	if err := json.Unmarshal(bytes.TrimSpace(data), &token); err != nil {
+		return nil, fmt.Errorf("invalid stored tokens for %s: %w", server, err)
+	}
+	return &token, nil
+}
+
+// DeleteToken forgets a server's tokens, returning errNotFound if there were none
+func DeleteToken(server string) error {
+	server = strings.TrimSuffix(server, "/")
+	if _, err := LoadToken(server); err != nil {
+		return err
+	}
+	switch Storage() {
+	case "keychain", "credential-manager":
+		return keyring.Delete(keyringService, server)
+	case "secret-service":
+		_, err := secretTool(nil, "clear", "service", keyringService, "account", server)
+		return err
+	default:
+		return updateTokenFile(func(tokens map[string]*Token) { delete(tokens, server) })
+	}
+}
+
+// IsNotLoggedIn reports whether an error is that no tokens are stored for a server
+func IsNotLoggedIn(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
+// secretTool runs secret-tool, with input on its stdin, and returns its output. It fails
+// without saying why when it didn't find the entry.
+func secretTool(input []byte, args ...string) ([]byte, error) {
+	command := exec.Command("secret-tool", args...)
+	command.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+	out, err := command.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			return nil, errNotFound
+		}
+		return nil, fmt.Errorf("secret-tool: %s", message)
+	}
+	return out, nil
+}
+
+// tokenFile returns the file tokens are kept in without an OS keyring, ~/.es/tokens.json
+func tokenFile() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tokens.json"), nil
+}
+
+func readTokenFile() (map[string]*Token, error) {
+	path, err := tokenFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*Token{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tokens := map[string]*Token{}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return tokens, nil
+}
+
+// updateTokenFile changes the tokens in the token file, replacing it whole so a crash
+// can't leave it half written
+func updateTokenFile(update func(tokens map[string]*Token)) error {
+	tokens, err := readTokenFile()
+	if err != nil {
+		return err
+	}
+	update(tokens)
+	path, err := tokenFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
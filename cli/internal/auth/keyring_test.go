@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ES_KEYRING", "file")
+	if Storage() != "file" {
+		t.Fatalf("Storage = %s with ES_KEYRING=file", Storage())
+	}
+
+	if _, err := LoadToken("https://es.example.com"); !IsNotLoggedIn(err) {
+		t.Errorf("LoadToken before login error = %v", err)
+	}
+	token := &Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	if err := SaveToken("https://es.example.com/", token); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadToken("https://es.example.com")
+	if err != nil || *got != *token {
+		t.Errorf("LoadToken = %+v, %v, want %+v", got, err, token)
+	}
+
+	info, err := os.Stat(filepath.Join(home, ".es", "tokens.json"))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("tokens.json = %v, %v, want mode 0600", info, err)
+	}
+
+	if err := DeleteToken("https://es.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteToken("https://es.example.com"); !IsNotLoggedIn(err) {
+		t.Errorf("DeleteToken after logout error = %v", err)
+	}
+}
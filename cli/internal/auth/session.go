@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Session supplies the access tokens of a server that was logged in to, refreshing
+// them with the stored refresh token as they expire and storing the new ones. It's a
+// client.Authenticator, safe for concurrent requests.
+type Session struct {
+	server   string
+	provider *Provider
+	mu       sync.Mutex
+	token    *Token
+}
+
+// NewSession returns the session of a server that was logged in to with 'es auth login'
+func NewSession(server string, provider *Provider, token *Token) *Session {
+	return &Session{server: strings.TrimSuffix(server, "/"), provider: provider, token: token}
+}
+
+// Current returns the session's tokens, refreshing the stored ones if the access token
+// has expired
+func (s *Session) Current() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	return s.refresh()
+}
+
+// Token returns a current access token, refreshing the stored one if it has expired
+func (s *Session) Token() (string, error) {
+	token, err := s.Current()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// Refresh returns a new access token, for one the server rejected. Requests rejected at
+// the same time share one refresh, as a provider may only accept a refresh token once.
+func (s *Session) Refresh(rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.AccessToken != rejected {
+		return s.token.AccessToken, nil
+	}
+	token, err := s.refresh()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+func (s *Session) refresh() (*Token, error) {
+	token, err := s.provider.Refresh(context.Background(), s.token)
+	if err != nil {
+		return nil, &ExpiredError{Server: s.server, Err: err}
+	}
+	if err := SaveToken(s.server, token); err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// ExpiredError is returned when a session's access token expired and couldn't be
+// refreshed, as its refresh token expired or was revoked
+type ExpiredError struct {
+	Server string
+	Err    error
+}
+
+// Error implements the error interface
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("the session for %s has expired: %v", e.Server, e.Err)
+}
+
+// Unwrap returns the error refreshing the session
+func (e *ExpiredError) Unwrap() error {
+	return e.Err
+}
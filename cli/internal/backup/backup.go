@@ -0,0 +1,117 @@
+// Package backup verifies backups of events kept in a directory: exports written by
+// 'es event export', each with a manifest next to it, and topic archives written by
+// 'es topic archive' or 'es topic tier push', each topic's segments with a manifest.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/event-store/cli/internal/archive"
+	"github.com/event-store/cli/internal/export"
+	"github.com/event-store/cli/internal/seal"
+)
+
+// Kinds of backup files
+const (
+	KindExport  = "export"
+	KindArchive = "archive"
+)
+
+// archiveManifest is the file name of a topic archive's manifest (see archive.ManifestName)
+const archiveManifest = "manifest.json"
+
+// Check is the verification of one file of a backup
+type Check struct {
+	File      string `json:"file"`
+	Kind      string `json:"kind"`
+	Topic     string `json:"topic"`
+	Events    int    `json:"events"`
+	Encrypted bool   `json:"encrypted,omitempty"` // an encrypted export
+	Decrypted bool   `json:"decrypted,omitempty"` // an encrypted export decrypted to count its events, rather than checked by size and checksum only
+	Error     string `json:"error,omitempty"`     // empty if the file is intact
+}
+
+// OK reports whether the file is intact
+func (c Check) OK() bool {
+	return c.Error == ""
+}
+
+// Verify checks every export and archive segment under a directory against its
+// manifest, returning a check per file, in order of path. Encrypted exports are
+// decrypted to count their events when keys decrypt them. It fails if the directory
+// can't be read or has no manifests.
+func Verify(dir string, keys *seal.Keys) ([]Check, error) {
+	var checks []Check
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		switch {
+		case export.IsManifest(entry.Name()):
+			checks = append(checks, verifyExport(path, keys))
+		case entry.Name() == archiveManifest:
+			archiveChecks, err := verifyArchive(path)
+			if err != nil {
+				return err
+			}
+			checks = append(checks, archiveChecks...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("no export or archive manifests found in '%s'", dir)
+	}
+	sort.SliceStable(checks, func(a, b int) bool { return checks[a].File < checks[b].File })
+	return checks, nil
+}
+
+// verifyExport checks an export against its manifest
+func verifyExport(manifestPath string, keys *seal.Keys) Check {
+	result, err := export.ReadManifest(manifestPath)
+	if err != nil {
+		return Check{File: manifestPath, Kind: KindExport, Error: err.Error()}
+	}
+	check := Check{File: result.File, Kind: KindExport, Topic: result.Topic, Events: result.Events, Encrypted: result.Encrypted}
+	err = export.Verify(result, keys)
+	switch {
+	case errors.Is(err, seal.ErrNoKey):
+		// Checked by size and checksum only
+	case err != nil:
+		check.Error = err.Error()
+	default:
+		check.Decrypted = result.Encrypted && keys != nil
+	}
+	return check
+}
+
+// verifyArchive checks the segments of a topic's archive against its manifest, which is
+// <root>/<topic>/manifest.json with the segments named relative to root
+func verifyArchive(manifestPath string) ([]Check, error) {
+	topicDir := filepath.Dir(manifestPath)
+	root := filepath.Dir(topicDir)
+	store, err := archive.Open(root)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := archive.ReadStoreManifest(store, filepath.Base(topicDir))
+	if err != nil {
+		return []Check{{File: manifestPath, Kind: KindArchive, Error: err.Error()}}, nil
+	}
+
+	checks := make([]Check, 0, len(manifest.Segments))
+	for _, segment := range manifest.Segments {
+		check := Check{File: filepath.Join(root, filepath.FromSlash(segment.File)), Kind: KindArchive, Topic: manifest.Topic, Events: segment.Count}
+		if err := archive.VerifySegment(store, segment); err != nil {
+			check.Error = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
@@ -0,0 +1,187 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Report summarises a benchmark run
+type Report struct {
+	Name       string           `json:"name"`
+	Duration   time.Duration    `json:"-"`
+	Seconds    float64          `json:"durationSeconds"`
+	Sent       int              `json:"sent"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	Throughput float64          `json:"throughputPerSecond"`
+	ErrorRate  float64          `json:"errorRate"`
+	Latency    LatencySummary   `json:"latency"`
+	Errors     map[string]int   `json:"errors,omitempty"`
+	Extra      []ReportProperty `json:"extra,omitempty"`
+}
+
+// ReportProperty is an additional, benchmark-specific line in a report
+type ReportProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// LatencySummary holds latency percentiles in milliseconds
+type LatencySummary struct {
+	Min  float64 `json:"minMs"`
+	Mean float64 `json:"meanMs"`
+	P50  float64 `json:"p50Ms"`
+	P90  float64 `json:"p90Ms"`
+	P95  float64 `json:"p95Ms"`
+	P99  float64 `json:"p99Ms"`
+	Max  float64 `json:"maxMs"`
+}
+
+// Recorder collects latencies and outcomes from concurrent workers
+type Recorder struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	sent      int
+	failed    int
+	errors    map[string]int
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{errors: make(map[string]int)}
+}
+
+// Record adds the outcome of a single operation; latency is only recorded on success
+func (r *Recorder) Record(latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sent++
+	if err != nil {
+		r.failed++
+		r.errors[err.Error()]++
+		return
+	}
+	r.latencies = append(r.latencies, latency)
+}
+
+// Report builds a report for a run that took elapsed
+func (r *Recorder) Report(name string, elapsed time.Duration) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Report{
+		Name:      name,
+		Duration:  elapsed,
+		Seconds:   elapsed.Seconds(),
+		Sent:      r.sent,
+		Succeeded: r.sent - r.failed,
+		Failed:    r.failed,
+		Latency:   Summarise(r.latencies),
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(report.Succeeded) / elapsed.Seconds()
+	}
+	if r.sent > 0 {
+		report.ErrorRate = float64(r.failed) / float64(r.sent)
+	}
+	if len(r.errors) > 0 {
+		report.Errors = make(map[string]int, len(r.errors))
+		for k, v := range r.errors {
+			report.Errors[k] = v
+		}
+	}
+	return report
+}
+
+// Summarise computes latency percentiles
+func Summarise(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+
+	return LatencySummary{
+		Min:  millis(sorted[0]),
+		Mean: millis(total / time.Duration(len(sorted))),
+		P50:  millis(percentile(sorted, 50)),
+		P90:  millis(percentile(sorted, 90)),
+		P95:  millis(percentile(sorted, 95)),
+		P99:  millis(percentile(sorted, 99)),
+		Max:  millis(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+}
+
+// ParseRate parses a rate such as "500/s", "30000/m" or "500" (per second) into
+// operations per second. A rate of 0 means unlimited.
+func ParseRate(s string) (float64, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	unit := time.Second
+	if i := strings.Index(s, "/"); i >= 0 {
+		switch s[i+1:] {
+		case "s", "sec", "second":
+			unit = time.Second
+		case "m", "min", "minute":
+			unit = time.Minute
+		case "h", "hour":
+			unit = time.Hour
+		default:
+			return 0, fmt.Errorf("invalid rate unit in '%s' (use /s, /m or /h)", s)
+		}
+		s = s[:i]
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid rate '%s'", s)
+	}
+	return n / unit.Seconds(), nil
+}
+
+// ParseSize parses a size such as "512", "512b", "1kb" or "2mb" into bytes
+func ParseSize(s string) (int, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	multiplier := 1
+	for _, suffix := range []struct {
+		unit  string
+		bytes int
+	}{{"kb", 1024}, {"mb", 1024 * 1024}, {"k", 1024}, {"m", 1024 * 1024}, {"b", 1}} {
+		if strings.HasSuffix(s, suffix.unit) {
+			multiplier = suffix.bytes
+			s = strings.TrimSuffix(s, suffix.unit)
+			break
+		}
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", s)
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,209 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/runner"
+)
+
+// ConsumeClient is the subset of the API client used by the consume benchmark
+type ConsumeClient interface {
+	Publisher
+	GetTopic(name string) (*client.Topic, error)
+	RegisterConsumer(callback string, topics map[string]string) (string, error)
+	DeleteConsumer(id string) error
+}
+
+// ConsumeOptions configures an end-to-end delivery benchmark
+type ConsumeOptions struct {
+	Topic    string
+	Type     string
+	Count    int
+	Rate     float64 // probes per second, 0 for unlimited
+	Port     int
+	Callback string        // URL at which the event store can reach the embedded listener
+	Timeout  time.Duration // how long to wait for deliveries after the last publish
+}
+
+// probeTracker records when each probe was delivered
+type probeTracker struct {
+	mu         sync.Mutex
+	runID      string
+	latencies  []time.Duration
+	seen       map[int]bool
+	duplicates int
+	delivered  chan struct{}
+}
+
+// observe records every probe belonging to this run in a delivery
+func (p *probeTracker) observe(events []client.Event) {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, event := range events {
+		if event.Payload["probeId"] != p.runID {
+			continue
+		}
+		seq, ok := event.Payload["seq"].(float64)
+		sentAt, _ := event.Payload["sentAt"].(string)
+		sent, err := time.Parse(time.RFC3339Nano, sentAt)
+		if !ok || err != nil {
+			continue
+		}
+
+		if p.seen[int(seq)] {
+			p.duplicates++
+			continue
+		}
+		p.seen[int(seq)] = true
+		p.latencies = append(p.latencies, now.Sub(sent))
+
+		select {
+		case p.delivered <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *probeTracker) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.seen)
+}
+
+// RunConsume registers a temporary consumer pointing at an embedded listener, publishes
+// timestamped probes and measures publish-to-webhook latency and loss. The consumer is
+// always unregistered before returning.
+func RunConsume(group *runner.Group, apiClient ConsumeClient, opts ConsumeOptions) (*Report, error) {
+	topic, err := apiClient.GetTopic(opts.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := &probeTracker{
+		runID:     fmt.Sprintf("bench-%d", time.Now().UnixNano()),
+		seen:      make(map[int]bool),
+		delivered: make(chan struct{}, 1),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var payload client.DeliveryPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		tracker.observe(payload.Events)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	})
+	group.Serve("bench-listener", &http.Server{
+		Addr:    fmt.Sprintf(":%d", opts.Port),
+		Handler: mux,
+	})
+
+	// Start after the current end of the topic so only probes are delivered
+	startAfter := ""
+	if topic.Sequence > 0 {
+		startAfter = eventid.ID{Topic: opts.Topic, Sequence: int64(topic.Sequence)}.String()
+	}
+	consumerID, err := apiClient.RegisterConsumer(opts.Callback, map[string]string{opts.Topic: startAfter})
+	if err != nil {
+		group.Stop()
+		group.Wait()
+		return nil, err
+	}
+
+	published := 0
+	publishErrors := 0
+	var lastPublish time.Time
+	start := time.Now()
+
+	jobs := make(chan int, 1)
+	group.Go("bench-scheduler", func(ctx context.Context) error {
+		Schedule(ctx, opts.Rate, opts.Count, jobs)
+		return nil
+	})
+
+	group.Go("bench-prober", func(ctx context.Context) error {
+		// Stopping the group shuts down the listener once probing is over
+		defer group.Stop()
+		defer apiClient.DeleteConsumer(consumerID)
+
+		for seq := range jobs {
+			_, err := apiClient.PublishEvents([]client.EventPublishRequest{{
+				Topic: opts.Topic,
+				Type:  opts.Type,
+				Payload: map[string]interface{}{
+					"probeId": tracker.runID,
+					"seq":     seq,
+					"sentAt":  time.Now().UTC().Format(time.RFC3339Nano),
+				},
+			}})
+			if err != nil {
+				publishErrors++
+				continue
+			}
+			published++
+		}
+		lastPublish = time.Now()
+
+		// Wait for outstanding deliveries, then stop the listener
+		deadline := time.NewTimer(opts.Timeout)
+		defer deadline.Stop()
+		for tracker.count() < published {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-deadline.C:
+				return nil
+			case <-tracker.delivered:
+			}
+		}
+		return nil
+	})
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	if lastPublish.IsZero() {
+		lastPublish = time.Now()
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	delivered := len(tracker.seen)
+	report := &Report{
+		Name:      "consume",
+		Duration:  lastPublish.Sub(start),
+		Seconds:   lastPublish.Sub(start).Seconds(),
+		Sent:      published,
+		Succeeded: delivered,
+		Failed:    published - delivered,
+		Latency:   Summarise(tracker.latencies),
+	}
+	if report.Seconds > 0 {
+		report.Throughput = float64(published) / report.Seconds
+	}
+	if published > 0 {
+		report.ErrorRate = float64(report.Failed) / float64(published)
+	}
+	report.Extra = []ReportProperty{
+		{Name: "Topic", Value: opts.Topic},
+		{Name: "Consumer", Value: consumerID},
+		{Name: "Probes Delivered", Value: fmt.Sprintf("%d", delivered)},
+		{Name: "Probes Lost", Value: fmt.Sprintf("%d", published-delivered)},
+		{Name: "Duplicates", Value: fmt.Sprintf("%d", tracker.duplicates)},
+		{Name: "Publish Errors", Value: fmt.Sprintf("%d", publishErrors)},
+	}
+	return report, nil
+}
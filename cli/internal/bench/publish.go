@@ -0,0 +1,120 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/runner"
+)
+
+// Publisher publishes events to the event store
+type Publisher interface {
+	PublishEvents(events []client.EventPublishRequest) ([]string, error)
+}
+
+// PublishOptions configures a publish benchmark
+type PublishOptions struct {
+	Topic       string
+	Type        string
+	Rate        float64 // requests per second, 0 for unlimited
+	Workers     int
+	Duration    time.Duration
+	Count       int // total requests, 0 for no limit
+	PayloadSize int
+	BatchSize   int
+}
+
+// RunPublish publishes synthetic events on group until the duration elapses, the count
+// is reached or the group is cancelled, and reports per-request latency.
+func RunPublish(group *runner.Group, publisher Publisher, opts PublishOptions) (*Report, error) {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1
+	}
+
+	padding := strings.Repeat("x", opts.PayloadSize)
+	recorder := NewRecorder()
+	jobs := make(chan int, opts.Workers)
+
+	start := time.Now()
+	group.Go("bench-scheduler", func(ctx context.Context) error {
+		if opts.Duration > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+			defer cancel()
+		}
+		Schedule(ctx, opts.Rate, opts.Count, jobs)
+		return nil
+	})
+
+	for w := 0; w < opts.Workers; w++ {
+		group.Go("bench-worker", func(ctx context.Context) error {
+			for seq := range jobs {
+				events := make([]client.EventPublishRequest, opts.BatchSize)
+				for i := range events {
+					events[i] = client.EventPublishRequest{
+						Topic: opts.Topic,
+						Type:  opts.Type,
+						Payload: map[string]interface{}{
+							"seq":    seq*opts.BatchSize + i,
+							"sentAt": time.Now().UTC().Format(time.RFC3339Nano),
+							"data":   padding,
+						},
+					}
+				}
+
+				requestStart := time.Now()
+				_, err := publisher.PublishEvents(events)
+				recorder.Record(time.Since(requestStart), err)
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	report := recorder.Report("publish", time.Since(start))
+	report.Extra = []ReportProperty{
+		{Name: "Topic", Value: opts.Topic},
+		{Name: "Workers", Value: fmt.Sprintf("%d", opts.Workers)},
+		{Name: "Batch Size", Value: fmt.Sprintf("%d", opts.BatchSize)},
+		{Name: "Payload Size", Value: fmt.Sprintf("%d bytes", opts.PayloadSize)},
+		{Name: "Events Published", Value: fmt.Sprintf("%d", report.Succeeded*opts.BatchSize)},
+	}
+	return report, nil
+}
+
+// Schedule sends sequence numbers to jobs at the given rate (per second, 0 for
+// unlimited) until count jobs have been sent (0 for no limit) or ctx is done, then
+// closes jobs.
+func Schedule(ctx context.Context, rate float64, count int, jobs chan<- int) {
+	defer close(jobs)
+
+	var tick <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for seq := 0; count == 0 || seq < count; seq++ {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- seq:
+		}
+	}
+}
@@ -0,0 +1,362 @@
+// Package bridge connects event store topics to the topics or subjects of a message bus
+// such as Kafka or NATS: events are sent to the bus as they are published, and messages
+// published on the bus are ingested as events.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// maxBackoff is the longest a bridge waits between syncs after repeated failures
+const maxBackoff = time.Minute
+
+// Bus is a message bus the event store is bridged to
+type Bus interface {
+	// Name identifies the bus in the checkpoint file, e.g. "kafka://broker:9092"
+	Name() string
+	// Send publishes messages to a bus topic or subject, returning once the bus has
+	// stored them
+	Send(ctx context.Context, target string, messages []Message) error
+	// Receive returns the next messages of a bus topic or subject from positions, which
+	// hold the offset to read next by partition or stream. Partitions missing from
+	// positions are added at the offset reading starts from, and positions the bus no
+	// longer has are moved to the earliest it has. Receive returns no messages once every
+	// partition has been read to its end.
+	Receive(ctx context.Context, target string, positions map[string]int64) ([]Message, error)
+	// Close releases the bus connection
+	Close() error
+}
+
+// Message is a message sent to or received from a bus
+type Message struct {
+	Key      []byte
+	Value    []byte
+	Headers  map[string]string
+	Position string // partition or stream a received message was read from
+	Offset   int64  // offset of a received message within its position
+}
+
+// Mapping connects an event store topic and a bus topic or subject
+type Mapping struct {
+	From string
+	To   string
+}
+
+// ParseMappings parses 'from:to' mappings, or a bare name for the same name on both
+// sides. flag names the option the mappings came from in errors. Each name can be mapped
+// from once, as the checkpoint records one position for it.
+func ParseMappings(flag string, specs []string) ([]Mapping, error) {
+	mappings := make([]Mapping, 0, len(specs))
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		from, to, found := strings.Cut(spec, ":")
+		if !found {
+			to = from
+		}
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("invalid %s '%s' (expected 'from:to' or a name)", flag, spec)
+		}
+		if seen[from] {
+			return nil, fmt.Errorf("'%s' is given to %s more than once (bridge it to another target with its own --checkpoint)", from, flag)
+		}
+		seen[from] = true
+		mappings = append(mappings, Mapping{From: from, To: to})
+	}
+	return mappings, nil
+}
+
+// Bridge moves events between the event store and a bus. Events of the Out topics are
+// sent to the bus, and messages of the In topics are published to the event store, each
+// from where the checkpoint says the last sync stopped. Delivery is at least once in both
+// directions: a batch sent just before a crash can be sent again on restart.
+type Bridge struct {
+	Store          *client.Client
+	Server         string // event store URL, stamped on sent messages
+	Bus            Bus
+	Out            []Mapping         // event store topic -> bus topic
+	In             []Mapping         // bus topic -> event store topic
+	Keys           map[string]string // event store topic -> field giving a sent message's key; "" for every topic
+	DefaultType    string            // event type of ingested messages that carry none
+	SkipInvalid    bool              // skip messages that are not events, or that the event store rejects
+	Start          string            // where topics with no checkpoint start: "earliest" or "latest"
+	BatchSize      int
+	Checkpoint     *Checkpoint
+	CheckpointPath string
+	// Report, if set, is called after each mapping sync that moved messages or failed,
+	// and for each skipped message
+	Report func(direction string, mapping Mapping, moved int, err error)
+
+	mu sync.Mutex
+}
+
+// Run syncs every interval until ctx is done. After a failed sync it waits longer each
+// time, up to a minute, so an unreachable server or bus is not hammered.
+func (b *Bridge) Run(ctx context.Context, interval time.Duration) error {
+	wait := interval
+	for {
+		if err := b.Sync(ctx); err != nil {
+			wait *= 2
+			if wait > maxBackoff {
+				wait = maxBackoff
+			}
+		} else {
+			wait = interval
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Sync sends and ingests everything new once, returning the first error. A failing
+// mapping does not stop the others.
+func (b *Bridge) Sync(ctx context.Context) error {
+	var firstErr error
+	note := func(err error) {
+		if err != nil && firstErr == nil && !errors.Is(err, context.Canceled) {
+			firstErr = err
+		}
+	}
+	for _, mapping := range b.Out {
+		if ctx.Err() != nil {
+			return nil
+		}
+		sent, err := b.send(ctx, mapping)
+		b.report("out", mapping, sent, err)
+		note(err)
+	}
+	for _, mapping := range b.In {
+		if ctx.Err() != nil {
+			return nil
+		}
+		ingested, err := b.ingest(ctx, mapping)
+		b.report("in", mapping, ingested, err)
+		note(err)
+	}
+	return firstErr
+}
+
+// send sends a topic's events after the checkpoint to the bus, a batch at a time
+func (b *Bridge) send(ctx context.Context, mapping Mapping) (int, error) {
+	after, ok := b.lastEventID(mapping.From)
+	if !ok && b.Start == "latest" {
+		topic, err := b.Store.GetTopic(mapping.From)
+		if err != nil {
+			return 0, err
+		}
+		if topic.Sequence > 0 {
+			after = eventid.ID{Topic: mapping.From, Sequence: int64(topic.Sequence)}.String()
+		}
+		if err := b.advanceOut(mapping.From, after); err != nil {
+			return 0, err
+		}
+	}
+
+	sent := 0
+	err := b.Store.ScanEvents(mapping.From, after, func(events []client.Event) (bool, error) {
+		for start := 0; start < len(events); start += b.BatchSize {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			end := start + b.BatchSize
+			if end > len(events) {
+				end = len(events)
+			}
+			batch := events[start:end]
+			messages := make([]Message, len(batch))
+			for i, event := range batch {
+				message, err := b.encode(mapping.From, event)
+				if err != nil {
+					return false, err
+				}
+				messages[i] = message
+			}
+			if err := b.Bus.Send(ctx, mapping.To, messages); err != nil {
+				return false, err
+			}
+			sent += len(batch)
+			if err := b.advanceOut(mapping.From, batch[len(batch)-1].ID); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	return sent, err
+}
+
+// ingest publishes the bus topic's messages after the checkpoint to the event store
+func (b *Bridge) ingest(ctx context.Context, mapping Mapping) (int, error) {
+	ingested := 0
+	for ctx.Err() == nil {
+		positions := b.positions(mapping.From)
+		messages, err := b.Bus.Receive(ctx, mapping.From, positions)
+		if saveErr := b.advanceIn(mapping.From, positions, nil); err == nil {
+			err = saveErr
+		}
+		if err != nil || len(messages) == 0 {
+			return ingested, err
+		}
+
+		for start := 0; start < len(messages); start += b.BatchSize {
+			end := start + b.BatchSize
+			if end > len(messages) {
+				end = len(messages)
+			}
+			batch := messages[start:end]
+			published, err := b.publish(mapping, batch)
+			ingested += published
+			if err != nil {
+				return ingested, err
+			}
+			if err := b.advanceIn(mapping.From, nil, batch); err != nil {
+				return ingested, err
+			}
+		}
+	}
+	return ingested, nil
+}
+
+// publish publishes received messages as events, returning how many were published.
+// Messages sent by this event store are skipped, so bridging a topic both ways does not
+// loop. With SkipInvalid, messages that aren't events are skipped, and a batch the event
+// store rejects is published an event at a time to skip only the rejected ones.
+func (b *Bridge) publish(mapping Mapping, messages []Message) (int, error) {
+	events := make([]client.EventPublishRequest, 0, len(messages))
+	for _, message := range messages {
+		if message.Headers[HeaderServer] == b.Server {
+			continue
+		}
+		event, err := b.decode(mapping.To, message)
+		if err != nil {
+			err = fmt.Errorf("message %s/%s@%d: %w", mapping.From, message.Position, message.Offset, err)
+			if !b.SkipInvalid {
+				return 0, err
+			}
+			b.report("skip", mapping, 0, err)
+			continue
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	_, err := b.Store.PublishEvents(events)
+	if err == nil {
+		return len(events), nil
+	}
+	if !b.SkipInvalid || !rejected(err) {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		if _, err := b.Store.PublishEvents([]client.EventPublishRequest{event}); err != nil {
+			if !rejected(err) {
+				return published, err
+			}
+			b.report("skip", mapping, 0, fmt.Errorf("event of type '%s': %w", event.Type, err))
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+// rejected reports whether the event store refused to publish events because of the
+// events themselves, rather than failing or being busy
+func rejected(err error) bool {
+	var apiErr *client.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// key returns the key of a topic's event: the value of the topic's key field, or of the
+// key field for every topic, or nil
+func (b *Bridge) key(topic string, event client.Event) []byte {
+	field, ok := b.Keys[topic]
+	if !ok {
+		field = b.Keys[""]
+	}
+	if field == "" {
+		return nil
+	}
+	if value, ok := filter.Value(event, field); ok {
+		return []byte(value)
+	}
+	return nil
+}
+
+func (b *Bridge) report(direction string, mapping Mapping, moved int, err error) {
+	if b.Report != nil && (moved > 0 || err != nil) && !errors.Is(err, context.Canceled) {
+		b.Report(direction, mapping, moved, err)
+	}
+}
+
+func (b *Bridge) lastEventID(topic string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id, ok := b.Checkpoint.Out[topic]
+	return id, ok
+}
+
+// positions returns a copy of a bus topic's checkpointed positions
+func (b *Bridge) positions(target string) map[string]int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	positions := map[string]int64{}
+	for position, offset := range b.Checkpoint.In[target] {
+		positions[position] = offset
+	}
+	return positions
+}
+
+// advanceOut records a topic's last sent event in the checkpoint file
+func (b *Bridge) advanceOut(topic, lastEventID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Checkpoint.Out[topic] = lastEventID
+	return b.Checkpoint.Save(b.CheckpointPath)
+}
+
+// advanceIn records the positions a bus topic is being read from, as Receive left them,
+// and the positions after ingested messages, in the checkpoint file
+func (b *Bridge) advanceIn(target string, reading map[string]int64, ingested []Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	positions := b.Checkpoint.In[target]
+	if positions == nil {
+		positions = map[string]int64{}
+		b.Checkpoint.In[target] = positions
+	}
+	changed := false
+	for position, offset := range reading {
+		if current, ok := positions[position]; !ok || current != offset {
+			positions[position] = offset
+			changed = true
+		}
+	}
+	for _, message := range ingested {
+		if message.Offset+1 > positions[message.Position] {
+			positions[message.Position] = message.Offset + 1
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return b.Checkpoint.Save(b.CheckpointPath)
+}
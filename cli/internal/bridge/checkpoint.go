@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Checkpoint records how far each mapping has been bridged, so a restarted bridge carries
+// on where it stopped
+type Checkpoint struct {
+	Server  string                      `json:"server"`
+	Bus     string                      `json:"bus"`
+	Out     map[string]string           `json:"out"` // event store topic -> last event ID sent
+	In      map[string]map[string]int64 `json:"in"`  // bus topic -> partition -> next offset to ingest
+	Updated time.Time                   `json:"updated"`
+}
+
+// LoadCheckpoint reads a checkpoint file, returning an empty checkpoint if it does not
+// exist yet
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	checkpoint := Checkpoint{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+		}
+	}
+	if checkpoint.Out == nil {
+		checkpoint.Out = map[string]string{}
+	}
+	if checkpoint.In == nil {
+		checkpoint.In = map[string]map[string]int64{}
+	}
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint file, replacing it in one step so a crash never leaves a
+// partial file
+func (c *Checkpoint) Save(path string) error {
+	c.Updated = time.Now().UTC()
+	// Without HTML escaping, NATS wildcards such as events.> are kept as they are
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Headers set on messages sent to the bus
+const (
+	HeaderEventID   = "es-event-id"
+	HeaderEventType = "es-event-type"
+	HeaderTopic     = "es-topic"
+	HeaderServer    = "es-server"
+)
+
+// Envelope is the JSON value of a message sent to the bus
+type Envelope struct {
+	ID        string                 `json:"id"`
+	Topic     string                 `json:"topic"`
+	Type      string                 `json:"type"`
+	Timestamp string                 `json:"timestamp"`
+	Payload   map[string]interface{} `json:"payload"`
+}
+
+// encode turns an event into a bus message: an Envelope with the event's ID, type, topic
+// and server also in headers
+func (b *Bridge) encode(topic string, event client.Event) (Message, error) {
+	value, err := json.Marshal(Envelope{
+		ID:        event.ID,
+		Topic:     topic,
+		Type:      event.Type,
+		Timestamp: event.Timestamp,
+		Payload:   event.Payload,
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("event %s: %w", event.ID, err)
+	}
+	return Message{
+		Key:   b.key(topic, event),
+		Value: value,
+		Headers: map[string]string{
+			HeaderEventID:   event.ID,
+			HeaderEventType: event.Type,
+			HeaderTopic:     topic,
+			HeaderServer:    b.Server,
+		},
+	}, nil
+}
+
+// decode turns a bus message into an event to publish. A message whose value is a JSON
+// object with a string "type" and an object "payload", such as an Envelope, is published
+// as that type and payload. Any other JSON object is the payload of an event whose type
+// is the es-event-type header, or DefaultType.
+func (b *Bridge) decode(topic string, message Message) (client.EventPublishRequest, error) {
+	var value map[string]interface{}
+	if err := json.Unmarshal(message.Value, &value); err != nil || value == nil {
+		return client.EventPublishRequest{}, fmt.Errorf("not a JSON object")
+	}
+
+	eventType, _ := value["type"].(string)
+	payload, isEnvelope := value["payload"].(map[string]interface{})
+	if eventType == "" || !isEnvelope {
+		payload = value
+		eventType = message.Headers[HeaderEventType]
+		if eventType == "" {
+			eventType = b.DefaultType
+		}
+	}
+	if eventType == "" {
+		return client.EventPublishRequest{}, fmt.Errorf("no event type (set the %s header or a default type)", HeaderEventType)
+	}
+	return client.EventPublishRequest{Topic: topic, Type: eventType, Payload: payload}, nil
+}
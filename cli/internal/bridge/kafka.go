@@ -0,0 +1,105 @@
+package bridge
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/event-store/cli/internal/kafka"
+)
+
+// kafkaFetchBytes is the most a Kafka partition is read at a time
+const kafkaFetchBytes = 1 << 20
+
+// KafkaBus bridges to Kafka topics. Received positions are partition numbers.
+type KafkaBus struct {
+	client  *kafka.Client
+	brokers []string
+	latest  bool // start partitions without a position at their end rather than their start
+}
+
+// NewKafka returns a bus for a Kafka cluster. start is "earliest" or "latest", for where
+// reading partitions without a checkpointed position starts.
+func NewKafka(config kafka.Config, start string) (*KafkaBus, error) {
+	kafkaClient, err := kafka.New(config)
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaBus{client: kafkaClient, brokers: config.Brokers, latest: start == "latest"}, nil
+}
+
+// Name returns the bootstrap brokers as a kafka:// URL
+func (k *KafkaBus) Name() string {
+	return "kafka://" + strings.Join(k.brokers, ",")
+}
+
+// Send produces messages to a Kafka topic with their headers, sorted by name
+func (k *KafkaBus) Send(ctx context.Context, topic string, messages []Message) error {
+	records := make([]kafka.Message, len(messages))
+	for i, message := range messages {
+		names := make([]string, 0, len(message.Headers))
+		for name := range message.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		records[i] = kafka.Message{Key: message.Key, Value: message.Value}
+		for _, name := range names {
+			records[i].Headers = append(records[i].Headers, kafka.Header{Key: name, Value: []byte(message.Headers[name])})
+		}
+	}
+	return k.client.Produce(ctx, topic, records)
+}
+
+// Receive fetches the next messages of every partition of a Kafka topic
+func (k *KafkaBus) Receive(ctx context.Context, topic string, positions map[string]int64) ([]Message, error) {
+	partitions, err := k.client.Partitions(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	for _, partition := range partitions {
+		position := strconv.Itoa(int(partition))
+		offset, ok := positions[position]
+		if !ok {
+			if offset, err = k.client.Offset(ctx, topic, partition, k.latest); err != nil {
+				return nil, err
+			}
+			positions[position] = offset
+		}
+
+		records, err := k.client.Fetch(ctx, topic, partition, offset, 0, kafkaFetchBytes)
+		if kafka.IsOffsetOutOfRange(err) {
+			// The messages from the position have been deleted by the topic's retention
+			if offset, err = k.client.Offset(ctx, topic, partition, false); err != nil {
+				return nil, err
+			}
+			positions[position] = offset
+			records, err = k.client.Fetch(ctx, topic, partition, offset, 0, kafkaFetchBytes)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			message := Message{
+				Key:      record.Key,
+				Value:    record.Value,
+				Headers:  map[string]string{},
+				Position: position,
+				Offset:   record.Offset,
+			}
+			for _, header := range record.Headers {
+				message.Headers[header.Key] = string(header.Value)
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// Close closes the connections to the brokers
+func (k *KafkaBus) Close() error {
+	return k.client.Close()
+}
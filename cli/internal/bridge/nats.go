@@ -0,0 +1,259 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsReceiveLimit is the most messages read from a stream at a time
+const natsReceiveLimit = 1000
+
+// natsConfirmTimeout bounds how long Send waits for the server to confirm it has the
+// messages sent
+const natsConfirmTimeout = 30 * time.Second
+
+// NatsBus bridges to NATS subjects. Messages are published to JetStream when a stream
+// captures their subject, and received from the streams capturing a subject. Received
+// positions are stream names, and offsets stream sequences.
+type NatsBus struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	name    string
+	latest  bool                        // start streams without a position at their end rather than their start
+	streams map[string]bool             // whether a stream captures a subject, by subject
+	read    map[string]jetstream.Stream // the streams read from, by name
+}
+
+// NewNats returns a bus for the NATS servers of a comma-separated list of URLs, tried in
+// order. start is "earliest" or "latest", for where reading streams without a
+// checkpointed position starts.
+func NewNats(urls string, opts []nats.Option, start string) (*NatsBus, error) {
+	conn, err := nats.Connect(urls, append([]nats.Option{nats.DontRandomize()}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &NatsBus{conn: conn, js: js, name: natsName(urls), latest: start == "latest",
+		streams: map[string]bool{}, read: map[string]jetstream.Stream{}}, nil
+}
+
+// natsName returns a server list as a nats:// URL without credentials
+func natsName(urls string) string {
+	var hosts []string
+	for _, rawURL := range strings.Split(urls, ",") {
+		rawURL = strings.TrimSpace(rawURL)
+		if !strings.Contains(rawURL, "://") {
+			rawURL = "nats://" + rawURL
+		}
+		if u, err := url.Parse(rawURL); err == nil {
+			hosts = append(hosts, u.Host)
+		}
+	}
+	return "nats://" + strings.Join(hosts, ",")
+}
+
+// Name returns the servers as a nats:// URL
+func (n *NatsBus) Name() string {
+	return n.name
+}
+
+// subject returns the subject a message is published on: the mapped subject, with the
+// message's key as a last token when it has one
+func subject(target string, message Message) string {
+	if len(message.Key) == 0 {
+		return target
+	}
+	key := strings.Map(func(r rune) rune {
+		if r == '.' || r == '*' || r == '>' || r <= ' ' {
+			return '_'
+		}
+		return r
+	}, string(message.Key))
+	return target + "." + key
+}
+
+// validSubject reports whether a subject can be published to: non-empty tokens
+// separated by dots, without spaces, control characters or wildcards
+func validSubject(subject string) bool {
+	if subject == "" || strings.ContainsAny(subject, "*>") {
+		return false
+	}
+	for _, token := range strings.Split(subject, ".") {
+		if token == "" || strings.IndexFunc(token, func(r rune) bool { return r <= ' ' || r == 0x7f }) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// streamsFor returns the names of the streams capturing messages published on a
+// subject, which may contain wildcards. Without JetStream nothing answers the API's
+// requests, and it returns nats.ErrNoResponders.
+func (n *NatsBus) streamsFor(ctx context.Context, subject string) ([]string, error) {
+	lister := n.js.StreamNames(ctx, jetstream.WithStreamListSubject(subject))
+	var names []string
+	for name := range lister.Name() {
+		names = append(names, name)
+	}
+	return names, lister.Err()
+}
+
+// captured reports whether a JetStream stream captures a subject
+func (n *NatsBus) captured(ctx context.Context, subject string) (bool, error) {
+	if captured, ok := n.streams[subject]; ok {
+		return captured, nil
+	}
+	streams, err := n.streamsFor(ctx, subject)
+	if errors.Is(err, nats.ErrNoResponders) {
+		err = nil
+	}
+	if err != nil {
+		return false, err
+	}
+	n.streams[subject] = len(streams) > 0
+	return n.streams[subject], nil
+}
+
+// Send publishes messages to a subject. Messages on subjects captured by a stream are
+// sent to JetStream with the event ID as their message ID, so JetStream drops messages
+// sent again within its duplicate window, and Send waits for every acknowledgement.
+// Others are published to core NATS, and Send waits for the server to have received
+// them.
+func (n *NatsBus) Send(ctx context.Context, target string, messages []Message) error {
+	if !validSubject(target) {
+		return fmt.Errorf("invalid NATS subject '%s'", target)
+	}
+
+	var acks []jetstream.PubAckFuture
+	for _, message := range messages {
+		subject := subject(target, message)
+		captured, err := n.captured(ctx, subject)
+		if err != nil {
+			return err
+		}
+		msg := &nats.Msg{Subject: subject, Header: natsHeader(message.Headers), Data: message.Value}
+		if !captured {
+			if err := n.conn.PublishMsg(msg); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ack, err := n.js.PublishMsgAsync(msg, jetstream.WithMsgID(message.Headers[HeaderEventID]))
+		if err != nil {
+			return err
+		}
+		acks = append(acks, ack)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, natsConfirmTimeout)
+	defer cancel()
+	for _, ack := range acks {
+		select {
+		case <-ack.Ok():
+		case err := <-ack.Err():
+			if errors.Is(err, nats.ErrNoResponders) {
+				return fmt.Errorf("nats: no stream captures '%s'", ack.Msg().Subject)
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return n.conn.FlushWithContext(ctx)
+}
+
+// natsHeader returns message headers as NATS headers. Names keep their case, and the
+// client drops names and replaces line breaks in values that would break the protocol.
+func natsHeader(headers map[string]string) nats.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	header := nats.Header{}
+	for name, value := range headers {
+		header[name] = []string{value}
+	}
+	return header
+}
+
+// messageHeaders returns the first value of each NATS header
+func messageHeaders(header nats.Header) map[string]string {
+	headers := map[string]string{}
+	for name, values := range header {
+		if len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+	return headers
+}
+
+// Receive reads the next messages on a subject, which may contain wildcards, from every
+// stream capturing it. Core NATS keeps no messages to read later, so a subject must be
+// captured by a stream to be ingested.
+func (n *NatsBus) Receive(ctx context.Context, target string, positions map[string]int64) ([]Message, error) {
+	streams, err := n.streamsFor(ctx, target)
+	if errors.Is(err, nats.ErrNoResponders) {
+		return nil, fmt.Errorf("ingesting '%s' needs a JetStream stream capturing it, but JetStream is not enabled", target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("no JetStream stream captures '%s'; ingesting needs one to read messages from", target)
+	}
+
+	var messages []Message
+	for _, name := range streams {
+		stream, ok := n.read[name]
+		if !ok {
+			if stream, err = n.js.Stream(ctx, name); err != nil {
+				return nil, err
+			}
+			n.read[name] = stream
+		}
+		sequence, ok := positions[name]
+		if !ok {
+			sequence = 1
+			if n.latest {
+				sequence = int64(stream.CachedInfo().State.LastSeq) + 1
+			}
+			positions[name] = sequence
+		}
+
+		// Reading from a sequence the stream has deleted carries on from its first message
+		for read := 0; read < natsReceiveLimit; read++ {
+			msg, err := stream.GetMsg(ctx, uint64(sequence), jetstream.WithGetMsgSubject(target))
+			if errors.Is(err, jetstream.ErrMsgNotFound) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, Message{
+				Value:    msg.Data,
+				Headers:  messageHeaders(msg.Header),
+				Position: name,
+				Offset:   int64(msg.Sequence),
+			})
+			sequence = int64(msg.Sequence) + 1
+		}
+	}
+	return messages, nil
+}
+
+// Close closes the connection to the server
+func (n *NatsBus) Close() error {
+	n.conn.Close()
+	return nil
+}
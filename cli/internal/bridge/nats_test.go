@@ -0,0 +1,385 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+)
+
+// fakeMsg is a message published to the fake server
+type fakeMsg struct {
+	Subject string
+	Header  nats.Header
+	Data    []byte
+	raw     []byte // the header block as sent
+}
+
+// fakeStream is a JetStream stream of the fake server
+type fakeStream struct {
+	name     string
+	subjects []string
+	msgs     []fakeMsg // the message with sequence n is msgs[n-1]
+	ids      map[string]bool
+}
+
+// fakeNats is a NATS server speaking enough of the protocol for a NatsBus: INFO,
+// CONNECT, PING/PONG, SUB, PUB/HPUB and MSG/HMSG, with the JetStream API's stream
+// names, info and message get requests, and acknowledgements of published messages
+type fakeNats struct {
+	t         *testing.T
+	listener  net.Listener
+	jetstream bool
+
+	mu      sync.Mutex
+	streams []*fakeStream
+	core    []fakeMsg // messages on subjects no stream captures
+	lines   []string  // protocol lines received
+	subs    map[string]string
+	writer  *bufio.Writer
+}
+
+func newFakeNats(t *testing.T, jetstream bool, streams ...*fakeStream) *fakeNats {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeNats{t: t, listener: listener, jetstream: jetstream, streams: streams, subs: map[string]string{}}
+	for _, stream := range streams {
+		stream.ids = map[string]bool{}
+	}
+	go f.serve()
+	t.Cleanup(func() { listener.Close() })
+	return f
+}
+
+func (f *fakeNats) URL() string {
+	return "nats://" + f.listener.Addr().String()
+}
+
+func (f *fakeNats) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	f.mu.Lock()
+	f.writer = bufio.NewWriter(conn)
+	f.writer.WriteString(`INFO {"server_id":"fake","version":"2.10.0","proto":1,"headers":true,"max_payload":1048576}` + "\r\n")
+	f.writer.Flush()
+	f.mu.Unlock()
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		f.mu.Lock()
+		f.lines = append(f.lines, line)
+		f.mu.Unlock()
+		args := strings.Fields(line)
+		switch args[0] {
+		case "PING":
+			f.write("PONG\r\n")
+		case "SUB":
+			f.mu.Lock()
+			f.subs[args[1]] = args[len(args)-1]
+			f.mu.Unlock()
+		case "PUB", "HPUB":
+			msg := fakeMsg{Subject: args[1]}
+			reply := ""
+			if args[0] == "PUB" && len(args) == 4 || args[0] == "HPUB" && len(args) == 5 {
+				reply = args[2]
+			}
+			total, _ := strconv.Atoi(args[len(args)-1])
+			body := make([]byte, total+2)
+			if _, err := io.ReadFull(reader, body); err != nil {
+				return
+			}
+			body = body[:total]
+			if args[0] == "HPUB" {
+				size, _ := strconv.Atoi(args[len(args)-2])
+				msg.raw = body[:size]
+				if msg.Header, err = nats.DecodeHeadersMsg(msg.raw); err != nil {
+					f.t.Errorf("invalid header block %q: %v", msg.raw, err)
+				}
+				body = body[size:]
+			}
+			msg.Data = body
+			f.handle(msg, reply)
+		}
+	}
+}
+
+func (f *fakeNats) write(data string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writer.WriteString(data)
+	f.writer.Flush()
+}
+
+// reply sends a message to the subscription matching a reply subject, or a 503 status
+// without data for no responders
+func (f *fakeNats) reply(subject string, data interface{}) {
+	f.mu.Lock()
+	sid := ""
+	for pattern, id := range f.subs {
+		if subjectMatches(pattern, subject) {
+			sid = id
+		}
+	}
+	f.mu.Unlock()
+	if data == nil {
+		header := "NATS/1.0 503\r\n\r\n"
+		f.write(fmt.Sprintf("HMSG %s %s %d %d\r\n%s\r\n", subject, sid, len(header), len(header), header))
+		return
+	}
+	body, _ := json.Marshal(data)
+	f.write(fmt.Sprintf("MSG %s %s %d\r\n%s\r\n", subject, sid, len(body), body))
+}
+
+// subjectMatches reports whether a subject matches a pattern with * and > wildcards
+func subjectMatches(pattern, subject string) bool {
+	patterns, tokens := strings.Split(pattern, "."), strings.Split(subject, ".")
+	for i, p := range patterns {
+		if p == ">" {
+			return len(tokens) > i
+		}
+		if i >= len(tokens) || p != "*" && p != tokens[i] {
+			return false
+		}
+	}
+	return len(patterns) == len(tokens)
+}
+
+func (f *fakeNats) stream(name string) *fakeStream {
+	for _, stream := range f.streams {
+		if stream.name == name {
+			return stream
+		}
+	}
+	return nil
+}
+
+// handle answers a published message: a JetStream API request, a message captured by a
+// stream, or one for core subscribers
+func (f *fakeNats) handle(msg fakeMsg, reply string) {
+	if strings.HasPrefix(msg.Subject, "$JS.API.") {
+		if !f.jetstream {
+			f.reply(reply, nil)
+			return
+		}
+		f.reply(reply, f.api(strings.TrimPrefix(msg.Subject, "$JS.API."), msg.Data))
+		return
+	}
+	f.mu.Lock()
+	for _, stream := range f.streams {
+		for _, pattern := range stream.subjects {
+			if !f.jetstream || !subjectMatches(pattern, msg.Subject) {
+				continue
+			}
+			id := msg.Header.Get(nats.MsgIdHdr)
+			duplicate := id != "" && stream.ids[id]
+			if !duplicate {
+				stream.msgs = append(stream.msgs, msg)
+				stream.ids[id] = true
+			}
+			seq := len(stream.msgs)
+			f.mu.Unlock()
+			f.reply(reply, map[string]interface{}{"stream": stream.name, "seq": seq, "duplicate": duplicate})
+			return
+		}
+	}
+	f.core = append(f.core, msg)
+	f.mu.Unlock()
+	if reply != "" {
+		f.reply(reply, nil)
+	}
+}
+
+// api answers a JetStream API request
+func (f *fakeNats) api(endpoint string, data []byte) interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var request struct {
+		Subject    string `json:"subject"`
+		Seq        int    `json:"seq"`
+		NextBySubj string `json:"next_by_subj"`
+	}
+	json.Unmarshal(data, &request)
+	notFound := map[string]interface{}{"error": map[string]interface{}{"code": 404, "err_code": 10059, "description": "stream not found"}}
+
+	switch {
+	case endpoint == "STREAM.NAMES":
+		names := []string{}
+		for _, stream := range f.streams {
+			for _, pattern := range stream.subjects {
+				if subjectMatches(pattern, request.Subject) || subjectMatches(request.Subject, pattern) || pattern == request.Subject {
+					names = append(names, stream.name)
+					break
+				}
+			}
+		}
+		return map[string]interface{}{"total": len(names), "offset": 0, "limit": 1024, "streams": names}
+	case strings.HasPrefix(endpoint, "STREAM.INFO."):
+		stream := f.stream(strings.TrimPrefix(endpoint, "STREAM.INFO."))
+		if stream == nil {
+			return notFound
+		}
+		return map[string]interface{}{
+			"config": map[string]interface{}{"name": stream.name, "subjects": stream.subjects},
+			"state":  map[string]interface{}{"messages": len(stream.msgs), "first_seq": 1, "last_seq": len(stream.msgs)},
+		}
+	case strings.HasPrefix(endpoint, "STREAM.MSG.GET."):
+		stream := f.stream(strings.TrimPrefix(endpoint, "STREAM.MSG.GET."))
+		if stream == nil {
+			return notFound
+		}
+		for seq := max(request.Seq, 1); seq <= len(stream.msgs); seq++ {
+			msg := stream.msgs[seq-1]
+			if request.NextBySubj == "" || subjectMatches(request.NextBySubj, msg.Subject) {
+				return map[string]interface{}{"message": map[string]interface{}{
+					"subject": msg.Subject, "seq": seq, "hdrs": msg.raw, "data": msg.Data,
+				}}
+			}
+		}
+		return map[string]interface{}{"error": map[string]interface{}{"code": 404, "err_code": 10037, "description": "no message found"}}
+	}
+	return notFound
+}
+
+func newTestNats(t *testing.T, server *fakeNats, start string) *NatsBus {
+	bus, err := NewNats(server.URL(), nil, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+func TestNatsSend(t *testing.T) {
+	orders := &fakeStream{name: "ORDERS", subjects: []string{"events.orders.>"}}
+	server := newFakeNats(t, true, orders)
+	bus := newTestNats(t, server, "earliest")
+
+	event := func(id, key string) Message {
+		return Message{
+			Key:     []byte(key),
+			Value:   []byte(`{"id":"` + id + `"}`),
+			Headers: map[string]string{HeaderEventID: id, HeaderEventType: "order.created"},
+		}
+	}
+	if err := bus.Send(t.Context(), "events.orders", []Message{event("orders-1", "c 42"), event("orders-2", "c.7")}); err != nil {
+		t.Fatal(err)
+	}
+	// Sent again, as after a restart, JetStream drops the duplicate
+	if err := bus.Send(t.Context(), "events.orders", []Message{event("orders-2", "c.7")}); err != nil {
+		t.Fatal(err)
+	}
+	// A subject no stream captures goes to core NATS, and a header value can't add
+	// protocol lines
+	audit := event("orders-3", "")
+	audit.Headers[HeaderEventType] = "order.created\r\nPUB injected 0\r\n"
+	if err := bus.Send(t.Context(), "audit", []Message{audit}); err != nil {
+		t.Fatal(err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(orders.msgs) != 2 {
+		t.Fatalf("stream has %d messages, want 2", len(orders.msgs))
+	}
+	for i, want := range []string{"events.orders.c_42", "events.orders.c_7"} {
+		msg := orders.msgs[i]
+		id := fmt.Sprintf("orders-%d", i+1)
+		if msg.Subject != want || msg.Header.Get(nats.MsgIdHdr) != id || msg.Header[HeaderEventID][0] != id {
+			t.Errorf("stream message %d = %s with headers %v, want %s with Nats-Msg-Id and %s %s", i+1, msg.Subject, msg.Header, want, HeaderEventID, id)
+		}
+	}
+	if len(server.core) != 1 || server.core[0].Subject != "audit" {
+		t.Fatalf("core messages = %v, want one on audit", server.core)
+	}
+	if got := server.core[0].Header[HeaderEventType]; len(got) != 1 || strings.ContainsAny(got[0], "\r\n") {
+		t.Errorf("header %s = %q, want one line", HeaderEventType, got)
+	}
+	if server.core[0].Header.Get(nats.MsgIdHdr) != "" {
+		t.Error("a core NATS message has a Nats-Msg-Id header")
+	}
+	for _, line := range server.lines {
+		if strings.Contains(line, "injected") {
+			t.Errorf("server received the protocol line %q", line)
+		}
+	}
+}
+
+func TestNatsSendInvalidSubject(t *testing.T) {
+	server := newFakeNats(t, true)
+	bus := newTestNats(t, server, "earliest")
+	for _, subject := range []string{"", "events.*", "events.>", "events..orders", "events orders", "events\r\nPUB x 0"} {
+		if err := bus.Send(t.Context(), subject, []Message{{Value: []byte("{}")}}); err == nil {
+			t.Errorf("Send(%q) succeeded, want an invalid subject error", subject)
+		}
+	}
+}
+
+func TestNatsReceive(t *testing.T) {
+	returns := &fakeStream{name: "RETURNS", subjects: []string{"events.returns.>"}}
+	server := newFakeNats(t, true, returns)
+	header := "NATS/1.0\r\nes-event-type: return.requested\r\n\r\n"
+	for i, subject := range []string{"events.returns.a", "events.returns.b", "events.returns.a"} {
+		returns.msgs = append(returns.msgs, fakeMsg{Subject: subject, Data: []byte(strconv.Itoa(i + 1)), raw: []byte(header)})
+	}
+
+	bus := newTestNats(t, server, "earliest")
+	positions := map[string]int64{}
+	messages, err := bus.Receive(t.Context(), "events.returns.a", positions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || string(messages[0].Value) != "1" || messages[1].Offset != 3 || messages[1].Position != "RETURNS" {
+		t.Fatalf("Receive = %+v, want messages 1 and 3 of RETURNS", messages)
+	}
+	if messages[0].Headers["es-event-type"] != "return.requested" {
+		t.Errorf("headers = %v", messages[0].Headers)
+	}
+	if positions["RETURNS"] != 1 {
+		t.Errorf("position = %d, want 1", positions["RETURNS"])
+	}
+
+	// Reading carries on from a position, and a stream without one starts at its end
+	// with --start latest
+	if messages, err = bus.Receive(t.Context(), "events.returns.>", map[string]int64{"RETURNS": 3}); err != nil || len(messages) != 1 {
+		t.Errorf("Receive from 3 = %d messages, %v, want 1", len(messages), err)
+	}
+	latest := newTestNats(t, newFakeNats(t, true, returns), "latest")
+	positions = map[string]int64{}
+	if messages, err = latest.Receive(t.Context(), "events.returns.>", positions); err != nil || len(messages) != 0 || positions["RETURNS"] != 4 {
+		t.Errorf("Receive from latest = %d messages at %d, %v, want none at 4", len(messages), positions["RETURNS"], err)
+	}
+}
+
+func TestNatsReceiveWithoutStream(t *testing.T) {
+	tests := []struct {
+		jetstream bool
+		want      string
+	}{
+		{jetstream: true, want: "no JetStream stream captures"},
+		{jetstream: false, want: "JetStream is not enabled"},
+	}
+	for _, test := range tests {
+		bus := newTestNats(t, newFakeNats(t, test.jetstream), "earliest")
+		_, err := bus.Receive(t.Context(), "events.returns.>", map[string]int64{})
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Receive with JetStream %t = %v, want %q", test.jetstream, err, test.want)
+		}
+	}
+}
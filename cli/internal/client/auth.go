@@ -0,0 +1,74 @@
+package client
+
+import (
+	"net/http"
+)
+
+// Authenticator supplies the access tokens of a server behind an identity provider
+type Authenticator interface {
+	// Token returns a current access token, refreshing one that has expired
+	Token() (string, error)
+	// Refresh returns a new access token, for when the server rejected the one given
+	Refresh(rejected string) (string, error)
+}
+
+// SetAuth sends auth's access tokens with every request as bearer tokens. A request
+// the server answers with 401 Unauthorized is sent once more with a refreshed token, as
+// the token may have been revoked or expired early. A nil auth sends no tokens.
+func (c *Client) SetAuth(auth Authenticator) {
+	c.auth = auth
+	base := c.httpClient.Transport
+	if wrapped, ok := base.(*authTransport); ok {
+		base = wrapped.base
+	}
+	c.httpClient.Transport = withAuth(base, auth)
+}
+
+// withAuth wraps a transport to send auth's tokens, if there is an auth
+func withAuth(base http.RoundTripper, auth Authenticator) http.RoundTripper {
+	if auth == nil {
+		return base
+	}
+	return &authTransport{base: base, auth: auth}
+}
+
+// authTransport sets the Authorization header of each request
+type authTransport struct {
+	base http.RoundTripper
+	auth Authenticator
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.auth.Token()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(withToken(req, token))
+	// A request whose body can't be read again can't be retried
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	token, err = t.auth.Refresh(token)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	retry := withToken(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// withToken returns a copy of a request with a bearer token, as a RoundTripper mustn't
+// change the request it's given
+func withToken(req *http.Request, token string) *http.Request {
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+	return authorized
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// topicCache keeps topic metadata for a short time, so a session running many commands
+// against one server does not fetch the same topics over and over. Entries are dropped
+// when this client changes a topic or publishes to it; changes made by others show up
+// once an entry expires. With a file, the cache is shared by every command run against
+// the server, so a script of commands doesn't refetch the same topics either.
+type topicCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	topics  map[string]cachedTopic
+	list    []Topic
+	listAge time.Time
+
+	server string
+	path   string // the file the cache is shared through, or empty
+}
+
+type cachedTopic struct {
+	Topic   Topic     `json:"topic"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// topicCacheFile is the on-disk form of a topic cache
+type topicCacheFile struct {
+	Server  string                 `json:"server"`
+	List    []Topic                `json:"list,omitempty"`
+	ListAge time.Time              `json:"listFetched"`
+	Topics  map[string]cachedTopic `json:"topics"`
+}
+
+// SetTopicCache caches topic metadata for ttl; 0 turns the cache off
+func (c *Client) SetTopicCache(ttl time.Duration) {
+	if ttl <= 0 {
+		c.topicCache = nil
+		return
+	}
+	c.topicCache = &topicCache{ttl: ttl, topics: make(map[string]cachedTopic)}
+}
+
+// SetTopicCacheFile shares the client's topic cache, set by SetTopicCache, with other
+// commands through a file, reading the entries they cached that haven't expired. A file
+// that can't be read is treated as empty, and one that can't be written is left as it
+// is: the cache only saves requests.
+func (c *Client) SetTopicCacheFile(path string) {
+	tc := c.topicCache
+	if tc == nil {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.server, tc.path = c.baseURL, path
+
+	file := tc.readFile()
+	for name, entry := range file.Topics {
+		tc.topics[name] = entry
+	}
+	if file.List != nil {
+		tc.list, tc.listAge = file.List, file.ListAge
+	}
+}
+
+func (tc *topicCache) get(name string) (*Topic, bool) {
+	if tc == nil {
+		return nil, false
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	entry, ok := tc.topics[name]
+	if !ok || time.Since(entry.Fetched) > tc.ttl {
+		return nil, false
+	}
+	topic := entry.Topic
+	return &topic, true
+}
+
+func (tc *topicCache) put(topic Topic) {
+	if tc == nil {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	entry := cachedTopic{Topic: topic, Fetched: time.Now()}
+	tc.topics[topic.Name] = entry
+	tc.updateFile(func(file *topicCacheFile) bool {
+		file.Topics[topic.Name] = entry
+		return true
+	})
+}
+
+func (tc *topicCache) getList() ([]Topic, bool) {
+	if tc == nil {
+		return nil, false
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.list == nil || time.Since(tc.listAge) > tc.ttl {
+		return nil, false
+	}
+	return append([]Topic(nil), tc.list...), true
+}
+
+func (tc *topicCache) putList(topics []Topic) {
+	if tc == nil {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.list = append([]Topic(nil), topics...)
+	tc.listAge = time.Now()
+	for _, topic := range topics {
+		tc.topics[topic.Name] = cachedTopic{Topic: topic, Fetched: tc.listAge}
+	}
+	tc.updateFile(func(file *topicCacheFile) bool {
+		file.List, file.ListAge = tc.list, tc.listAge
+		for _, topic := range topics {
+			file.Topics[topic.Name] = tc.topics[topic.Name]
+		}
+		return true
+	})
+}
+
+// invalidate drops the named topics and the topic list
+func (tc *topicCache) invalidate(names ...string) {
+	if tc == nil {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.list = nil
+	for _, name := range names {
+		delete(tc.topics, name)
+	}
+	tc.updateFile(func(file *topicCacheFile) bool {
+		changed := file.List != nil
+		file.List = nil
+		for _, name := range names {
+			if _, ok := file.Topics[name]; ok {
+				delete(file.Topics, name)
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// readFile reads the entries of the cache's file that haven't expired
+func (tc *topicCache) readFile() *topicCacheFile {
+	file := &topicCacheFile{}
+	if data, err := os.ReadFile(tc.path); err == nil {
+		json.Unmarshal(data, file)
+	}
+	if file.Server != tc.server {
+		file = &topicCacheFile{}
+	}
+	file.Server = tc.server
+	if file.Topics == nil {
+		file.Topics = map[string]cachedTopic{}
+	}
+	for name, entry := range file.Topics {
+		if time.Since(entry.Fetched) > tc.ttl {
+			delete(file.Topics, name)
+		}
+	}
+	if time.Since(file.ListAge) > tc.ttl {
+		file.List = nil
+	}
+	return file
+}
+
+// updateFile applies a change to the cache's file, reading it afresh so the entries
+// other commands have cached or dropped since are kept. change reports whether it
+// changed anything.
+func (tc *topicCache) updateFile(change func(file *topicCacheFile) bool) {
+	if tc.path == "" {
+		return
+	}
+	file := tc.readFile()
+	if !change(file) {
+		return
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return
+	}
+	dir := filepath.Dir(tc.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(tc.path)+".*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), tc.path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -3,40 +3,84 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/event-store/cli/internal/tracing"
 )
 
 // Client represents an HTTP client for the event store API
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	verbosity  int
+	logOut     io.Writer
+	logMask    []string // event fields logged bodies show as '***'
+	tracer     *tracing.Tracer
+	metrics    *Metrics
+	serverInfo *ServerInfo
+	infoMu     sync.Mutex
+	topicCache *topicCache
+	limiter    *limiter
+	auth       Authenticator
+
+	gzipResponses    bool // ask for gzip-compressed responses
+	compressRequests bool // gzip large request bodies, if the server accepts them
 }
 
-// NewClient creates a new event store API client
+// NewClient creates a new event store API client, with the default TransportOptions
 func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
+	c := &Client{baseURL: baseURL}
+	c.SetTransport(TransportOptions{})
+	return c
+}
+
+// BaseURL returns the URL of the server the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
 }
 
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
-	Error string `json:"error"`
-	Code  string `json:"code,omitempty"`
+	Error    string         `json:"error"`
+	Code     string         `json:"code,omitempty"`
+	Failures []EventFailure `json:"failures,omitempty"` // the events that caused a rejected publish
+}
+
+// APIError is returned when the event store responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Message    string
+	Code       string
+	Failures   []EventFailure
+	Body       string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message != "" && e.Code != "" {
+		return fmt.Sprintf("API error: %s (code: %s)", e.Message, e.Code)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("API error: %s", e.Message)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
 }
 
 // Topic represents a topic in the event store
 type Topic struct {
-	Name     string   `json:"name"`
-	Sequence int      `json:"sequence"`
-	Schemas  []Schema `json:"schemas"`
+	Name       string            `json:"name"`
+	Sequence   int               `json:"sequence"`
+	Schemas    []Schema          `json:"schemas"`
+	Partitions int               `json:"partitions,omitempty"` // 0 when the topic is not partitioned
+	Labels     map[string]string `json:"labels,omitempty"`     // FeatureTopicLabels
+	Owner      *Owner            `json:"owner,omitempty"`      // FeatureTopicOwner
 }
 
 // Schema represents a JSON schema for an event type
@@ -55,8 +99,9 @@ type TopicsResponse struct {
 
 // TopicCreationRequest represents a request to create a topic
 type TopicCreationRequest struct {
-	Name    string   `json:"name"`
-	Schemas []Schema `json:"schemas"`
+	Name    string            `json:"name"`
+	Schemas []Schema          `json:"schemas"`
+	Labels  map[string]string `json:"labels,omitempty"` // FeatureTopicLabels
 }
 
 // TopicUpdateRequest represents a request to update a topic
@@ -71,9 +116,11 @@ type MessageResponse struct {
 
 // Consumer represents a consumer in the event store
 type Consumer struct {
-	ID       string            `json:"id"`
-	Callback string            `json:"callback"`
-	Topics   map[string]string `json:"topics"` // topic -> lastEventId (or null)
+	ID          string            `json:"id"`
+	Callback    string            `json:"callback"`
+	Topics      map[string]string `json:"topics"`                // topic -> lastEventId (or null)
+	Description string            `json:"description,omitempty"` // FeatureConsumerMetadata
+	Labels      map[string]string `json:"labels,omitempty"`      // FeatureConsumerMetadata
 }
 
 // ConsumersResponse represents the response from GET /consumers
@@ -83,8 +130,10 @@ type ConsumersResponse struct {
 
 // ConsumerRegistrationRequest represents a request to register a consumer
 type ConsumerRegistrationRequest struct {
-	Callback string             `json:"callback"`
-	Topics   map[string]*string `json:"topics"` // topic -> lastEventId (nil for null, pointer to string for value)
+	Callback    string             `json:"callback"`
+	Topics      map[string]*string `json:"topics"`                // topic -> lastEventId (nil for null, pointer to string for value)
+	Description string             `json:"description,omitempty"` // FeatureConsumerMetadata
+	Labels      map[string]string  `json:"labels,omitempty"`      // FeatureConsumerMetadata
 }
 
 // ConsumerRegistrationResponse represents the response from POST /consumers/register
@@ -92,12 +141,35 @@ type ConsumerRegistrationResponse struct {
 	ConsumerID string `json:"consumerId"`
 }
 
+// DeliveryPayload is the body the event store POSTs to a consumer's callback URL
+type DeliveryPayload struct {
+	ConsumerID string  `json:"consumerId"`
+	Events     []Event `json:"events"`
+}
+
+// ReplayRequest represents a request to POST /consumers/{id}/replay, for servers with
+// FeatureConsumerReplay
+type ReplayRequest struct {
+	Topic       string `json:"topic"`
+	FromEventID string `json:"fromEventId"`         // inclusive
+	ToEventID   string `json:"toEventId,omitempty"` // inclusive; empty for the end of the topic
+}
+
+// ReplayResponse represents the response from POST /consumers/{id}/replay
+type ReplayResponse struct {
+	Delivered int `json:"delivered"`
+}
+
 // Event represents an event in the event store
 type Event struct {
-	ID        string                 `json:"id"`
-	Timestamp string                 `json:"timestamp"`
-	Type      string                 `json:"type"`
-	Payload   map[string]interface{} `json:"payload"`
+	ID            string                 `json:"id"`
+	Timestamp     string                 `json:"timestamp"`
+	Type          string                 `json:"type"`
+	Payload       map[string]interface{} `json:"payload"`
+	Partition     *int                   `json:"partition,omitempty"`     // nil for unpartitioned topics
+	CorrelationID string                 `json:"correlationId,omitempty"` // for servers with FeatureEventCorrelation
+	CausationID   string                 `json:"causationId,omitempty"`   // ID of the event that caused this one
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`      // for servers with FeatureEventMetadata
 }
 
 // Health represents the health status of the event store
@@ -117,17 +189,37 @@ type EventsQuery struct {
 	SinceEventID string
 	Date         string
 	Limit        int
+	Partition    *int   // restrict to one partition of a partitioned topic
+	Filter       string // server-side 'field:value' filter, for servers with FeatureEventFilter
+	Since        string // RFC 3339; only events at or after this time, for servers with FeatureEventTimeRange
+	Until        string // RFC 3339; only events before this time, for servers with FeatureEventTimeRange
 }
 
 // request performs an HTTP request and returns the response body
 func (c *Client) request(method, endpoint string, body interface{}) ([]byte, error) {
+	return c.requestWithHeaders(method, endpoint, body, nil)
+}
+
+// requestWithHeaders performs an HTTP request with extra headers and returns the
+// response body
+func (c *Client) requestWithHeaders(method, endpoint string, body interface{}, headers map[string]string) (respBody []byte, err error) {
+	span := c.startSpan(method, endpoint)
+	defer func() { span.End(err) }()
+
 	var reqBody io.Reader
+	var jsonData []byte
+	compressed := false
 	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if (err) != nil {
+		jsonData, err = json.Marshal(body)
+		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		var sent []byte
+		sent, compressed, err = c.compressBody(jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress request body: %w", err)
+		}
+		reqBody = bytes.NewReader(sent)
 	}
 
 	req, err := http.NewRequest(method, c.baseURL+endpoint, reqBody)
@@ -136,24 +228,55 @@ func (c *Client) request(method, endpoint string, body interface{}) ([]byte, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if c.gzipResponses {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if traceparent := span.Traceparent(); traceparent != "" {
+		req.Header.Set("traceparent", traceparent)
+	}
+
+	release := c.limiter.acquire()
+	defer release()
+
+	path, _, _ := strings.Cut(endpoint, "?")
+	metrics, req := c.metrics.start(req, method, path)
+
+	c.logRequest(req, jsonData)
+	start := time.Now()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		metrics.done(0, time.Since(start))
+		c.logFailure(err, time.Since(start))
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+
+	respBody, err = readBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	metrics.done(resp.StatusCode, time.Since(start))
+	c.logResponse(resp, respBody, time.Since(start))
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 		var errResp ErrorResponse
 		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("API error: %s (code: %s)", errResp.Error, errResp.Code)
+			apiErr.Message = errResp.Error
+			apiErr.Code = errResp.Code
+			apiErr.Failures = errResp.Failures
 		}
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+		return nil, apiErr
 	}
 
 	return respBody, nil
@@ -161,6 +284,9 @@ func (c *Client) request(method, endpoint string, body interface{}) ([]byte, err
 
 // GetTopics lists all topics
 func (c *Client) GetTopics() ([]Topic, error) {
+	if topics, ok := c.topicCache.getList(); ok {
+		return topics, nil
+	}
 	respBody, err := c.request("GET", "/topics", nil)
 	if err != nil {
 		return nil, err
@@ -171,11 +297,36 @@ func (c *Client) GetTopics() ([]Topic, error) {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	c.topicCache.putList(resp.Topics)
+	return resp.Topics, nil
+}
+
+// GetTopicsMatching lists the topics whose labels match a label selector, for servers
+// with FeatureLabelSelector
+func (c *Client) GetTopicsMatching(selector string) ([]Topic, error) {
+	respBody, err := c.request("GET", "/topics?selector="+url.QueryEscape(selector), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TopicsResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
 	return resp.Topics, nil
 }
 
 // GetTopic gets detailed information about a specific topic
 func (c *Client) GetTopic(name string) (*Topic, error) {
+	if topic, ok := c.topicCache.get(name); ok {
+		return topic, nil
+	}
+	return c.RefreshTopic(name)
+}
+
+// RefreshTopic gets a topic from the server, whether or not it's cached, for callers
+// that need its current sequence
+func (c *Client) RefreshTopic(name string) (*Topic, error) {
 	endpoint := "/topics/" + url.PathEscape(name)
 	respBody, err := c.request("GET", endpoint, nil)
 	if err != nil {
@@ -187,17 +338,23 @@ func (c *Client) GetTopic(name string) (*Topic, error) {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	c.topicCache.put(topic)
 	return &topic, nil
 }
 
 // CreateTopic creates a new topic with schemas
 func (c *Client) CreateTopic(name string, schemas []Schema) error {
-	req := TopicCreationRequest{
+	return c.CreateTopicRequest(TopicCreationRequest{
 		Name:    name,
 		Schemas: schemas,
-	}
+	})
+}
 
+// CreateTopicRequest creates a topic described by a full creation request, such as one
+// with labels for servers with FeatureTopicLabels
+func (c *Client) CreateTopicRequest(req TopicCreationRequest) error {
 	_, err := c.request("POST", "/topics", req)
+	c.topicCache.invalidate(req.Name)
 	return err
 }
 
@@ -209,6 +366,7 @@ func (c *Client) UpdateTopicSchemas(name string, schemas []Schema) error {
 
 	endpoint := "/topics/" + url.PathEscape(name)
 	_, err := c.request("PUT", endpoint, req)
+	c.topicCache.invalidate(name)
 	return err
 }
 
@@ -227,6 +385,21 @@ func (c *Client) GetConsumers() ([]Consumer, error) {
 	return resp.Consumers, nil
 }
 
+// GetConsumersMatching lists the consumers whose labels match a label selector, for
+// servers with FeatureLabelSelector
+func (c *Client) GetConsumersMatching(selector string) ([]Consumer, error) {
+	respBody, err := c.request("GET", "/consumers?selector="+url.QueryEscape(selector), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ConsumersResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp.Consumers, nil
+}
+
 // RegisterConsumer registers a new consumer
 // topics map: empty string or "null" means null (start from beginning), otherwise the event ID
 func (c *Client) RegisterConsumer(callback string, topics map[string]string) (string, error) {
@@ -243,11 +416,16 @@ func (c *Client) RegisterConsumer(callback string, topics map[string]string) (st
 		}
 	}
 
-	req := ConsumerRegistrationRequest{
+	return c.RegisterConsumerRequest(ConsumerRegistrationRequest{
 		Callback: callback,
 		Topics:   topicsWithNull,
-	}
+	})
+}
 
+// RegisterConsumerRequest registers a new consumer described by a full registration
+// request, such as one with a description and labels for servers with
+// FeatureConsumerMetadata
+func (c *Client) RegisterConsumerRequest(req ConsumerRegistrationRequest) (string, error) {
 	respBody, err := c.request("POST", "/consumers/register", req)
 	if err != nil {
 		return "", err
@@ -268,6 +446,21 @@ func (c *Client) DeleteConsumer(id string) error {
 	return err
 }
 
+// ReplayConsumer asks the server to re-deliver a range of a topic's events to a consumer
+func (c *Client) ReplayConsumer(id string, req ReplayRequest) (*ReplayResponse, error) {
+	endpoint := "/consumers/" + url.PathEscape(id) + "/replay"
+	respBody, err := c.request("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ReplayResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &resp, nil
+}
+
 // GetEvents retrieves events from a topic
 func (c *Client) GetEvents(topic string, query *EventsQuery) ([]Event, error) {
 	endpoint := "/topics/" + url.PathEscape(topic) + "/events"
@@ -284,6 +477,18 @@ func (c *Client) GetEvents(topic string, query *EventsQuery) ([]Event, error) {
 		if query.Limit > 0 {
 			params.Add("limit", fmt.Sprintf("%d", query.Limit))
 		}
+		if query.Partition != nil {
+			params.Add("partition", fmt.Sprintf("%d", *query.Partition))
+		}
+		if query.Filter != "" {
+			params.Add("filter", query.Filter)
+		}
+		if query.Since != "" {
+			params.Add("since", query.Since)
+		}
+		if query.Until != "" {
+			params.Add("until", query.Until)
+		}
 	}
 
 	if len(params) > 0 {
@@ -303,6 +508,39 @@ func (c *Client) GetEvents(topic string, query *EventsQuery) ([]Event, error) {
 	return resp.Events, nil
 }
 
+// scanPageSize is the number of events requested per page by ScanEvents
+const scanPageSize = 1000
+
+// ScanEvents pages through a topic's events after sinceEventID (empty for the start of
+// the topic), calling fn with each page until fn returns false or the topic is exhausted
+func (c *Client) ScanEvents(topic, sinceEventID string, fn func(events []Event) (bool, error)) error {
+	return c.ScanEventsQuery(topic, EventsQuery{SinceEventID: sinceEventID}, fn)
+}
+
+// ScanEventsQuery is ScanEvents for the events selected by a query; the query's limit
+// is replaced by the page size
+func (c *Client) ScanEventsQuery(topic string, query EventsQuery, fn func(events []Event) (bool, error)) error {
+	query.Limit = scanPageSize
+	for {
+		events, err := c.GetEvents(topic, &query)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		more, err := fn(events)
+		if err != nil || !more {
+			return err
+		}
+		if len(events) < scanPageSize {
+			return nil
+		}
+		query.SinceEventID = events[len(events)-1].ID
+	}
+}
+
 // GetHealth retrieves the health status of the event store
 func (c *Client) GetHealth() (*Health, error) {
 	respBody, err := c.request("GET", "/health", nil)
@@ -320,20 +558,94 @@ func (c *Client) GetHealth() (*Health, error) {
 
 // EventPublishRequest represents a request to publish an event
 type EventPublishRequest struct {
-	Topic   string                 `json:"topic"`
-	Type    string                 `json:"type"`
-	Payload map[string]interface{} `json:"payload"`
+	Topic          string                 `json:"topic"`
+	Type           string                 `json:"type"`
+	Payload        map[string]interface{} `json:"payload"`
+	PublishAt      string                 `json:"publishAt,omitempty"`      // RFC 3339, for servers with FeatureScheduledPublish
+	IdempotencyKey string                 `json:"idempotencyKey,omitempty"` // for servers with FeatureIdempotentPublish
+	CorrelationID  string                 `json:"correlationId,omitempty"`  // for servers with FeatureEventCorrelation
+	CausationID    string                 `json:"causationId,omitempty"`    // for servers with FeatureEventCorrelation
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`       // for servers with FeatureEventMetadata
 }
 
-// EventPublishResponse represents the response from POST /events
+// EventPublishResponse represents the response from POST /events. A partly published
+// batch has an ID per event, "" for those that failed, and the failures.
 type EventPublishResponse struct {
-	EventIDs []string `json:"eventIds"`
+	EventIDs []string       `json:"eventIds"`
+	Failures []EventFailure `json:"failures,omitempty"`
+}
+
+// EventFailure is why one event of a batch wasn't published
+type EventFailure struct {
+	Index int    `json:"index"` // position of the event in the batch, from 0
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// PublishError is returned when some of a batch's events weren't published. EventIDs has
+// an ID per event of the batch, "" for those that weren't published, which is all of
+// them when the batch was rejected as a whole.
+type PublishError struct {
+	EventIDs []string
+	Failures []EventFailure
+	Rejected bool // no events were published
+}
+
+// Error implements the error interface
+func (e *PublishError) Error() string {
+	if len(e.Failures) == 0 {
+		return "failed to publish events"
+	}
+	first := e.Failures[0]
+	msg := fmt.Sprintf("%d of %d event(s) failed to publish: event %d: %s", len(e.Failures), len(e.EventIDs), first.Index+1, first.Error)
+	if len(e.Failures) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Failures)-1)
+	}
+	if e.Rejected {
+		msg += "; no events were published"
+	}
+	return msg
 }
 
 // PublishEvents publishes one or more events
 func (c *Client) PublishEvents(events []EventPublishRequest) ([]string, error) {
-	respBody, err := c.request("POST", "/events", events)
+	return c.publish("/events", events, nil)
+}
+
+// PublishEventsWithKey publishes events with an Idempotency-Key header. Servers with
+// FeatureIdempotentPublish publish a batch once per key, answering a repeated key with
+// the event IDs of the first publish.
+func (c *Client) PublishEventsWithKey(events []EventPublishRequest, key string) ([]string, error) {
+	return c.publish("/events", events, map[string]string{"Idempotency-Key": key})
+}
+
+// PublishEventsAtomic publishes all of a batch's events or none of them, on servers with
+// FeatureAtomicPublish. A rejected batch returns a *PublishError naming the events at
+// fault. key, if not "", is sent as the batch's Idempotency-Key.
+func (c *Client) PublishEventsAtomic(events []EventPublishRequest, key string) ([]string, error) {
+	var headers map[string]string
+	if key != "" {
+		headers = map[string]string{"Idempotency-Key": key}
+	}
+	return c.publish("/events/atomic", events, headers)
+}
+
+// publish posts events to an endpoint. Failures the server reports per event, whether
+// the batch was rejected or partly published, are returned as a *PublishError.
+func (c *Client) publish(endpoint string, events []EventPublishRequest, headers map[string]string) ([]string, error) {
+	if c.topicCache != nil {
+		topics := make([]string, len(events))
+		for i, event := range events {
+			topics[i] = event.Topic
+		}
+		defer c.topicCache.invalidate(topics...)
+	}
+	respBody, err := c.requestWithHeaders("POST", endpoint, events, headers)
 	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && len(apiErr.Failures) > 0 {
+			return nil, &PublishError{EventIDs: make([]string, len(events)), Failures: apiErr.Failures, Rejected: true}
+		}
 		return nil, err
 	}
 
@@ -341,6 +653,9 @@ func (c *Client) PublishEvents(events []EventPublishRequest) ([]string, error) {
 	if err := json.Unmarshal(respBody, &resp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	if len(resp.Failures) > 0 {
+		return resp.EventIDs, &PublishError{EventIDs: resp.EventIDs, Failures: resp.Failures}
+	}
 
 	return resp.EventIDs, nil
 }
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Delivery is one attempt to deliver an event to a consumer's callback, from the
+// server's delivery log for servers with FeatureConsumerDeliveries
+type Delivery struct {
+	ID         string  `json:"id"`
+	ConsumerID string  `json:"consumerId"`
+	EventID    string  `json:"eventId"`
+	Attempt    int     `json:"attempt"`
+	Status     int     `json:"status,omitempty"` // 0 when the callback could not be reached
+	LatencyMs  float64 `json:"latencyMs"`
+	Timestamp  string  `json:"timestamp"`
+	Success    bool    `json:"success"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// DeliveriesResponse represents the response from GET /consumers/{id}/deliveries
+type DeliveriesResponse struct {
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// DeliveriesQuery selects delivery attempts. Without After the most recent Limit
+// attempts are returned; with it, the attempts after that one. Either way they are
+// oldest first.
+type DeliveriesQuery struct {
+	After      string // delivery ID
+	Limit      int
+	FailedOnly bool
+}
+
+// GetDeliveries retrieves delivery attempts to a consumer's callback
+func (c *Client) GetDeliveries(consumerID string, query DeliveriesQuery) ([]Delivery, error) {
+	endpoint := "/consumers/" + url.PathEscape(consumerID) + "/deliveries"
+	params := url.Values{}
+	if query.After != "" {
+		params.Set("after", query.After)
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.FailedOnly {
+		params.Set("outcome", "failed")
+	}
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	respBody, err := c.request("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DeliveriesResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp.Deliveries, nil
+}
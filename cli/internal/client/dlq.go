@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// DLQEntry is an event whose delivery to a consumer failed after the server's retries,
+// kept in the consumer's dead-letter queue for servers with FeatureConsumerDLQ
+type DLQEntry struct {
+	ID            string `json:"id"`
+	ConsumerID    string `json:"consumerId"`
+	Topic         string `json:"topic"`
+	Event         Event  `json:"event"`
+	Attempts      int    `json:"attempts"`
+	LastStatus    int    `json:"lastStatus,omitempty"` // 0 when the callback could not be reached
+	LastError     string `json:"lastError"`
+	FirstFailedAt string `json:"firstFailedAt"`
+	LastFailedAt  string `json:"lastFailedAt"`
+}
+
+// DLQResponse represents the response from GET /consumers/{id}/dlq
+type DLQResponse struct {
+	Entries []DLQEntry `json:"entries"`
+}
+
+// DLQQuery selects a page of a dead-letter queue
+type DLQQuery struct {
+	After string // only entries after this entry ID
+	Limit int
+}
+
+// GetDLQ retrieves a page of a consumer's dead-letter queue, oldest first
+func (c *Client) GetDLQ(consumerID string, query DLQQuery) ([]DLQEntry, error) {
+	endpoint := "/consumers/" + url.PathEscape(consumerID) + "/dlq"
+	params := url.Values{}
+	if query.After != "" {
+		params.Set("after", query.After)
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	respBody, err := c.request("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DLQResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp.Entries, nil
+}
+
+// ScanDLQ pages through a consumer's dead-letter queue, calling fn with each page until
+// it returns false or the queue ends
+func (c *Client) ScanDLQ(consumerID string, fn func(entries []DLQEntry) (bool, error)) error {
+	query := DLQQuery{Limit: scanPageSize}
+	for {
+		entries, err := c.GetDLQ(consumerID, query)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		more, err := fn(entries)
+		if err != nil || !more {
+			return err
+		}
+		if len(entries) < scanPageSize {
+			return nil
+		}
+		query.After = entries[len(entries)-1].ID
+	}
+}
+
+// GetDLQEntry retrieves one entry of a consumer's dead-letter queue
+func (c *Client) GetDLQEntry(consumerID, entryID string) (*DLQEntry, error) {
+	endpoint := "/consumers/" + url.PathEscape(consumerID) + "/dlq/" + url.PathEscape(entryID)
+	respBody, err := c.request("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry DLQEntry
+	if err := json.Unmarshal(respBody, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &entry, nil
+}
+
+// RetryDLQEntry asks the server to deliver a dead-lettered event to its consumer again.
+// The entry leaves the queue when the delivery succeeds.
+func (c *Client) RetryDLQEntry(consumerID, entryID string) error {
+	endpoint := "/consumers/" + url.PathEscape(consumerID) + "/dlq/" + url.PathEscape(entryID) + "/retry"
+	_, err := c.request("POST", endpoint, nil)
+	return err
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Optional server features, as listed in ServerInfo.Features
+const (
+	// FeatureEventFilter: GET /topics/{topic}/events accepts a 'filter' parameter with
+	// the same 'field:value' syntax as the CLI's --filter
+	FeatureEventFilter = "event-filter"
+	// FeatureEventTimeRange: GET /topics/{topic}/events accepts 'since' and 'until'
+	// RFC 3339 timestamps (since inclusive, until exclusive)
+	FeatureEventTimeRange = "event-time-range"
+	// FeaturePartitions: topics can be partitioned
+	FeaturePartitions = "partitions"
+	// FeatureConsumerReplay: POST /consumers/{id}/replay re-delivers a range of events
+	FeatureConsumerReplay = "consumer-replay"
+	// FeatureTopicStats: GET /topics/{topic}/stats returns a topic's TopicStats
+	FeatureTopicStats = "topic-stats"
+	// FeatureConsumerDLQ: GET /consumers/{id}/dlq lists events whose delivery failed
+	// after retries, and POST /consumers/{id}/dlq/{entry}/retry re-delivers one
+	FeatureConsumerDLQ = "consumer-dlq"
+	// FeatureConsumerDeliveries: GET /consumers/{id}/deliveries lists recent attempts to
+	// deliver events to a consumer's callback
+	FeatureConsumerDeliveries = "consumer-deliveries"
+	// FeatureTopicRetention: GET and PUT /topics/{topic}/retention read and set how long
+	// and how many events a topic keeps
+	FeatureTopicRetention = "topic-retention"
+	// FeatureTopicTruncate: POST /topics/{topic}/truncate deletes a topic's oldest events
+	FeatureTopicTruncate = "topic-truncate"
+	// FeatureScheduledPublish: POST /events accepts a 'publishAt' RFC 3339 timestamp per
+	// event, and holds the event back until then
+	FeatureScheduledPublish = "scheduled-publish"
+	// FeatureIdempotentPublish: POST /events publishes a batch once per Idempotency-Key
+	// header and each event once per 'idempotencyKey', answering repeats with the IDs of
+	// the events first published
+	FeatureIdempotentPublish = "idempotent-publish"
+	// FeatureAtomicPublish: POST /events/atomic publishes all of a batch's events or none,
+	// and a rejected batch is answered with 'failures' giving the index and error of each
+	// event at fault
+	FeatureAtomicPublish = "atomic-publish"
+	// FeatureEventCorrelation: POST /events accepts a 'correlationId' and 'causationId'
+	// per event, and events are returned with them
+	FeatureEventCorrelation = "event-correlation"
+	// FeatureEventMetadata: POST /events accepts a 'metadata' object per event, such as
+	// its source, user, tenant and schema version, and events are returned with it
+	FeatureEventMetadata = "event-metadata"
+	// FeatureGzipRequests: POST and PUT accept gzip-compressed bodies sent with
+	// 'Content-Encoding: gzip'
+	FeatureGzipRequests = "gzip-requests"
+	// FeatureEventRedaction: POST /topics/{topic}/redact removes or masks fields of
+	// published events, for erasing personal data
+	FeatureEventRedaction = "event-redaction"
+	// FeatureConsumerMetadata: POST /consumers/register accepts a 'description' and
+	// 'labels' for the consumer, and consumers are returned with them
+	FeatureConsumerMetadata = "consumer-metadata"
+	// FeatureTopicLabels: POST /topics accepts 'labels' for the topic, and topics are
+	// returned with them
+	FeatureTopicLabels = "topic-labels"
+	// FeatureLabelSelector: GET /topics and GET /consumers accept a 'selector' parameter
+	// with the same syntax as the CLI's --selector, and return only the matching ones
+	FeatureLabelSelector = "label-selector"
+	// FeatureTopicOwner: GET and PUT /topics/{topic}/owner read and set who owns a topic,
+	// its owner, team and contact, and topics are returned with an 'owner'
+	FeatureTopicOwner = "topic-owner"
+)
+
+// infoEndpoints are tried in order; the first one the server has is used
+var infoEndpoints = []string{"/version", "/info"}
+
+// ServerInfo describes the event store's version and the optional features it supports
+type ServerInfo struct {
+	Version    string   `json:"version"`
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Features   []string `json:"features"`
+	Reported   bool     `json:"reported"` // false when the server has no version endpoint
+}
+
+// Supports reports whether the server has an optional feature. Servers that don't
+// report their version are assumed to have none.
+func (i *ServerInfo) Supports(feature string) bool {
+	for _, f := range i.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// GetServerInfo retrieves the server's version and features from /version or /info. A
+// server with neither endpoint is not an error: the result has Reported false. The
+// result is cached, so commands can check capabilities without repeating the request.
+func (c *Client) GetServerInfo() (*ServerInfo, error) {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+	if c.serverInfo != nil {
+		return c.serverInfo, nil
+	}
+
+	info := &ServerInfo{}
+	for _, endpoint := range infoEndpoints {
+		respBody, err := c.request("GET", endpoint, nil)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(respBody, info); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		info.Reported = true
+		break
+	}
+
+	if info.Features == nil {
+		info.Features = []string{}
+	}
+	c.serverInfo = info
+	return info, nil
+}
+
+// Supports reports whether the server has an optional feature, treating a failure to
+// discover the server's features as the feature being unavailable
+func (c *Client) Supports(feature string) bool {
+	info, err := c.GetServerInfo()
+	return err == nil && info.Supports(feature)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// Limits cap how hard a client works the event store, so bulk operations don't
+// overwhelm a production server
+type Limits struct {
+	Rate        float64 // most requests per second; 0 for no limit
+	Concurrency int     // most requests in flight at once; 0 for no limit
+}
+
+// limiter enforces Limits with a token bucket holding one token, refilled every 1/Rate
+// seconds, so requests are spaced evenly, and a semaphore of Concurrency slots. It is
+// shared by every goroutine using the client.
+type limiter struct {
+	interval time.Duration
+	slots    chan struct{}
+
+	mu   sync.Mutex
+	next time.Time // when the next token is available
+}
+
+// SetLimits caps the requests the client makes; zero Limits remove the caps
+func (c *Client) SetLimits(limits Limits) {
+	if limits.Rate <= 0 && limits.Concurrency <= 0 {
+		c.limiter = nil
+		return
+	}
+	l := &limiter{}
+	if limits.Rate > 0 {
+		l.interval = time.Duration(float64(time.Second) / limits.Rate)
+	}
+	if limits.Concurrency > 0 {
+		l.slots = make(chan struct{}, limits.Concurrency)
+	}
+	c.limiter = l
+}
+
+// acquire waits until a request may be made, returning the function to call once it's done
+func (l *limiter) acquire() func() {
+	if l == nil {
+		return func() {}
+	}
+	if l.slots != nil {
+		l.slots <- struct{}{}
+	}
+	if l.interval > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		start := l.next
+		if start.Before(now) {
+			start = now
+		}
+		l.next = start.Add(l.interval)
+		l.mu.Unlock()
+		time.Sleep(time.Until(start))
+	}
+	return func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Verbosity levels for request/response debug logging
+const (
+	VerbosityOff     = 0
+	VerbosityBasic   = 1 // method, URL, status and latency
+	VerbosityHeaders = 2 // adds request and response headers
+	VerbosityBodies  = 3 // adds request and response bodies
+)
+
+// maxLoggedBody caps how much of a body is written to the debug log
+const maxLoggedBody = 4096
+
+// redactedHeaders lists headers whose values must never appear in logs
+var redactedHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+}
+
+// redactedFields lists the endings of JSON field names, lower-cased without '-' and '_',
+// whose values must never appear in logged bodies, such as accessToken or client_secret
+var redactedFields = []string{"password", "passwd", "secret", "token", "apikey", "authorization", "credentials", "privatekey"}
+
+// maskedValue replaces the value of a masked event field in logged bodies, as it does
+// in output
+const maskedValue = "***"
+
+// SetVerbosity enables debug logging of requests and responses to w
+func (c *Client) SetVerbosity(level int, w io.Writer) {
+	c.verbosity = level
+	c.logOut = w
+}
+
+// SetLogMask sets the fields of events, such as payload.password, that logged bodies
+// show as '***'
+func (c *Client) SetLogMask(fields []string) {
+	c.logMask = fields
+}
+
+// logRequest writes the request line and, at higher verbosity, headers and body
+func (c *Client) logRequest(req *http.Request, body []byte) {
+	if c.verbosity < VerbosityBasic || c.logOut == nil {
+		return
+	}
+
+	fmt.Fprintf(c.logOut, "> %s %s\n", req.Method, req.URL.String())
+	if c.verbosity >= VerbosityHeaders {
+		logHeaders(c.logOut, ">", req.Header)
+	}
+	if c.verbosity >= VerbosityBodies && len(body) > 0 {
+		logBody(c.logOut, ">", body, c.logMask)
+	}
+}
+
+// logResponse writes the response status and latency and, at higher verbosity, headers and body
+func (c *Client) logResponse(resp *http.Response, body []byte, latency time.Duration) {
+	if c.verbosity < VerbosityBasic || c.logOut == nil {
+		return
+	}
+
+	fmt.Fprintf(c.logOut, "< %s (%s)\n", resp.Status, latency.Round(time.Millisecond))
+	if c.verbosity >= VerbosityHeaders {
+		logHeaders(c.logOut, "<", resp.Header)
+	}
+	if c.verbosity >= VerbosityBodies && len(body) > 0 {
+		logBody(c.logOut, "<", body, c.logMask)
+	}
+}
+
+// logFailure writes a transport-level failure with its latency
+func (c *Client) logFailure(err error, latency time.Duration) {
+	if c.verbosity < VerbosityBasic || c.logOut == nil {
+		return
+	}
+
+	fmt.Fprintf(c.logOut, "< failed after %s: %v\n", latency.Round(time.Millisecond), err)
+}
+
+// logHeaders writes headers in a stable order with secrets redacted
+func logHeaders(w io.Writer, prefix string, headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(headers[name], ", ")
+		if redactedHeaders[strings.ToLower(name)] {
+			value = "[REDACTED]"
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", prefix, name, value)
+	}
+}
+
+// logBody writes a body, truncated to maxLoggedBody bytes. A JSON body has its secret
+// fields redacted and the event fields of mask shown as '***'.
+func logBody(w io.Writer, prefix string, body []byte, mask []string) {
+	text := string(redactBody(body, mask))
+	if len(text) > maxLoggedBody {
+		text = text[:maxLoggedBody] + fmt.Sprintf("... (%d bytes truncated)", len(body)-maxLoggedBody)
+	}
+	fmt.Fprintf(w, "%s\n%s\n", prefix, text)
+}
+
+// redactBody returns a JSON body with the values of its secret fields redacted and the
+// event fields of mask masked, or the body as it is if it has neither or isn't JSON
+func redactBody(body []byte, mask []string) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if decoder.Decode(&value) != nil || decoder.More() {
+		return body
+	}
+	if !redactValue(value, mask) {
+		return body
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue redacts a decoded JSON value in place, reporting whether it changed it.
+// The payload and metadata of any object, such as an event, are masked.
+func redactValue(value interface{}, mask []string) bool {
+	changed := false
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if field != nil && isSecretField(key) {
+				value[key] = "[REDACTED]"
+				changed = true
+			} else if redactValue(field, mask) {
+				changed = true
+			}
+		}
+		for _, field := range mask {
+			root, path, ok := strings.Cut(field, ".")
+			object, isObject := value[root].(map[string]interface{})
+			if ok && isObject && (root == "payload" || root == "metadata") && maskPath(object, strings.Split(path, ".")) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if redactValue(item, mask) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// maskPath replaces the value at a path of an object with '***', reporting whether the
+// object has the path
+func maskPath(object map[string]interface{}, path []string) bool {
+	value, ok := object[path[0]]
+	if !ok {
+		return false
+	}
+	if len(path) == 1 {
+		object[path[0]] = maskedValue
+		return true
+	}
+	nested, isObject := value.(map[string]interface{})
+	return isObject && maskPath(nested, path[1:])
+}
+
+// isSecretField reports whether a JSON field's value must never be logged
+func isSecretField(name string) bool {
+	name = strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	for _, suffix := range redactedFields {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	mask := []string{"payload.card.number", "metadata.ip", "payload.missing.field"}
+	tests := []struct {
+		name, body, want string
+	}{
+		{
+			name: "secret fields",
+			body: `{"client_secret": "s3cret", "accessToken": "abc", "nested": [{"Password": "hunter2", "user": "ada"}], "idempotencyKey": "k1", "refresh-token": null}`,
+			want: `{"accessToken":"[REDACTED]","client_secret":"[REDACTED]","idempotencyKey":"k1","nested":[{"Password":"[REDACTED]","user":"ada"}],"refresh-token":null}`,
+		},
+		{
+			name: "masked events",
+			body: `[{"topic": "orders", "payload": {"card": {"number": "4111", "expiry": "12/30"}, "total": 9.50}, "metadata": {"ip": "10.0.0.1"}}]`,
+			want: `[{"metadata":{"ip":"***"},"payload":{"card":{"expiry":"12/30","number":"***"},"total":9.50},"topic":"orders"}]`,
+		},
+		{
+			name: "masked events of a response",
+			body: `{"events": [{"id": "orders-1", "payload": {"card": "4111"}}, {"id": "orders-2", "payload": {"card": {"number": 4111}}}]}`,
+			want: `{"events":[{"id":"orders-1","payload":{"card":"4111"}},{"id":"orders-2","payload":{"card":{"number":"***"}}}]}`,
+		},
+		{
+			name: "nothing to redact",
+			body: "{\n  \"topic\": \"orders\",\n  \"payload\": {\"total\": 1}\n}",
+			want: "{\n  \"topic\": \"orders\",\n  \"payload\": {\"total\": 1}\n}",
+		},
+		{name: "not JSON", body: `password=hunter2`, want: `password=hunter2`},
+		{name: "JSON lines", body: `{"token": "a"}` + "\n" + `{"token": "b"}`, want: `{"token": "a"}` + "\n" + `{"token": "b"}`},
+	}
+	for _, tt := range tests {
+		if got := string(redactBody([]byte(tt.body), mask)); got != tt.want {
+			t.Errorf("%s: redactBody(%s) = %s, want %s", tt.name, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestLogBodies(t *testing.T) {
+	var log bytes.Buffer
+	c := NewClient("http://localhost:8000")
+	c.SetVerbosity(VerbosityBodies, &log)
+	c.SetLogMask([]string{"payload.ssn"})
+
+	// Redacted before it's truncated
+	body := `{"token": "abc", "payload": {"ssn": "123-45-6789"}, "unread": "` + strings.Repeat("x", maxLoggedBody) + `"}`
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost:8000/events", nil)
+	c.logRequest(req, []byte(body))
+	text := log.String()
+	for _, secret := range []string{"abc", "123-45-6789"} {
+		if strings.Contains(text, secret) {
+			t.Errorf("logged body has %q:\n%s", secret, text)
+		}
+	}
+	if !strings.Contains(text, `"ssn":"***"`) || !strings.Contains(text, `"token":"[REDACTED]"`) || !strings.Contains(text, "bytes truncated)") {
+		t.Errorf("logged body isn't masked, redacted and truncated:\n%.200s", text)
+	}
+}
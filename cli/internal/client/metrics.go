@@ -0,0 +1,280 @@
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics collects the client's own request, connection and timing statistics so
+// slowness can be attributed to the client, the network or the server
+type Metrics struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointStats
+
+	newConns    int
+	reusedConns int
+	idleConns   int
+	dns         TimingStats
+	connect     TimingStats
+	tls         TimingStats
+	firstByte   TimingStats
+}
+
+// EndpointStats counts requests made to one route, e.g. "GET /topics/{topic}"
+type EndpointStats struct {
+	Endpoint string
+	Requests int
+	Errors   int // non-2xx responses
+	Failures int // requests that got no response
+	Total    time.Duration
+}
+
+// TimingStats accumulates a duration observed once per request or connection
+type TimingStats struct {
+	Count int
+	Total time.Duration
+	Max   time.Duration
+}
+
+func (t *TimingStats) observe(d time.Duration) {
+	t.Count++
+	t.Total += d
+	if d > t.Max {
+		t.Max = d
+	}
+}
+
+// Mean returns the average observed duration
+func (t TimingStats) Mean() time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Count)
+}
+
+// MetricsSnapshot is a point-in-time copy of Metrics
+type MetricsSnapshot struct {
+	Endpoints   []EndpointStats
+	NewConns    int
+	ReusedConns int
+	IdleConns   int // reused connections that came from the idle pool
+	DNS         TimingStats
+	Connect     TimingStats
+	TLS         TimingStats
+	FirstByte   TimingStats // request written to first response byte, i.e. server time
+}
+
+// NewMetrics creates an empty metrics collector
+func NewMetrics() *Metrics {
+	return &Metrics{endpoints: make(map[string]*EndpointStats)}
+}
+
+// SetMetrics records statistics for every API call in m
+func (c *Client) SetMetrics(m *Metrics) {
+	c.metrics = m
+}
+
+// requestMetrics tracks the phases of a single request
+type requestMetrics struct {
+	m         *Metrics
+	endpoint  string
+	dnsStart  time.Time
+	connStart time.Time
+	tlsStart  time.Time
+	wroteAt   time.Time // guarded by m.mu, written and read on different transport goroutines
+}
+
+// start begins tracking a request to the given route, attaching an httptrace to req.
+// It returns nil when metrics are disabled.
+func (m *Metrics) start(req *http.Request, method, path string) (*requestMetrics, *http.Request) {
+	if m == nil {
+		return nil, req
+	}
+
+	route, _ := routeTemplate(path)
+	rm := &requestMetrics{m: m, endpoint: method + " " + route}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { rm.dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			m.observe(&m.dns, time.Since(rm.dnsStart))
+		},
+		ConnectStart: func(string, string) { rm.connStart = time.Now() },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				m.observe(&m.connect, time.Since(rm.connStart))
+			}
+		},
+		TLSHandshakeStart: func() { rm.tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				m.observe(&m.tls, time.Since(rm.tlsStart))
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if info.Reused {
+				m.reusedConns++
+				if info.WasIdle {
+					m.idleConns++
+				}
+			} else {
+				m.newConns++
+			}
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			rm.wroteAt = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if !rm.wroteAt.IsZero() {
+				m.firstByte.observe(time.Since(rm.wroteAt))
+			}
+		},
+	}
+	return rm, req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+func (m *Metrics) observe(t *TimingStats, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t.observe(d)
+}
+
+// done records the outcome of a request; status is 0 when no response was received
+func (rm *requestMetrics) done(status int, elapsed time.Duration) {
+	if rm == nil {
+		return
+	}
+
+	m := rm.m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.endpoints[rm.endpoint]
+	if !ok {
+		stats = &EndpointStats{Endpoint: rm.endpoint}
+		m.endpoints[rm.endpoint] = stats
+	}
+	stats.Requests++
+	stats.Total += elapsed
+	switch {
+	case status == 0:
+		stats.Failures++
+	case status < 200 || status >= 300:
+		stats.Errors++
+	}
+}
+
+// Snapshot returns a copy of the statistics collected so far, endpoints sorted by name
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Endpoints:   make([]EndpointStats, 0, len(m.endpoints)),
+		NewConns:    m.newConns,
+		ReusedConns: m.reusedConns,
+		IdleConns:   m.idleConns,
+		DNS:         m.dns,
+		Connect:     m.connect,
+		TLS:         m.tls,
+		FirstByte:   m.firstByte,
+	}
+	for _, stats := range m.endpoints {
+		snapshot.Endpoints = append(snapshot.Endpoints, *stats)
+	}
+	sort.Slice(snapshot.Endpoints, func(i, j int) bool {
+		return snapshot.Endpoints[i].Endpoint < snapshot.Endpoints[j].Endpoint
+	})
+	return snapshot
+}
+
+// Requests returns the total number of requests across all endpoints
+func (s MetricsSnapshot) Requests() int {
+	total := 0
+	for _, endpoint := range s.Endpoints {
+		total += endpoint.Requests
+	}
+	return total
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) {
+	s := m.Snapshot()
+
+	fmt.Fprintln(w, "# HELP es_client_requests_total API requests made by the CLI, by endpoint and outcome.")
+	fmt.Fprintln(w, "# TYPE es_client_requests_total counter")
+	for _, e := range s.Endpoints {
+		method, route := splitEndpoint(e.Endpoint)
+		ok := e.Requests - e.Errors - e.Failures
+		fmt.Fprintf(w, "es_client_requests_total{method=%q,route=%q,outcome=\"ok\"} %d\n", method, route, ok)
+		fmt.Fprintf(w, "es_client_requests_total{method=%q,route=%q,outcome=\"error\"} %d\n", method, route, e.Errors)
+		fmt.Fprintf(w, "es_client_requests_total{method=%q,route=%q,outcome=\"failure\"} %d\n", method, route, e.Failures)
+	}
+
+	fmt.Fprintln(w, "# HELP es_client_request_seconds_total Total time spent in API requests, by endpoint.")
+	fmt.Fprintln(w, "# TYPE es_client_request_seconds_total counter")
+	for _, e := range s.Endpoints {
+		method, route := splitEndpoint(e.Endpoint)
+		fmt.Fprintf(w, "es_client_request_seconds_total{method=%q,route=%q} %g\n", method, route, e.Total.Seconds())
+	}
+
+	fmt.Fprintln(w, "# HELP es_client_connections_total Connections obtained from the pool, by whether they were reused.")
+	fmt.Fprintln(w, "# TYPE es_client_connections_total counter")
+	fmt.Fprintf(w, "es_client_connections_total{reused=\"false\"} %d\n", s.NewConns)
+	fmt.Fprintf(w, "es_client_connections_total{reused=\"true\"} %d\n", s.ReusedConns)
+
+	fmt.Fprintln(w, "# HELP es_client_phase_seconds_total Total time spent in each request phase.")
+	fmt.Fprintln(w, "# TYPE es_client_phase_seconds_total counter")
+	for _, phase := range s.Phases() {
+		fmt.Fprintf(w, "es_client_phase_seconds_total{phase=%q} %g\n", phase.Name, phase.Stats.Total.Seconds())
+	}
+
+	fmt.Fprintln(w, "# HELP es_client_phase_observations_total Number of times each request phase was observed.")
+	fmt.Fprintln(w, "# TYPE es_client_phase_observations_total counter")
+	for _, phase := range s.Phases() {
+		fmt.Fprintf(w, "es_client_phase_observations_total{phase=%q} %d\n", phase.Name, phase.Stats.Count)
+	}
+}
+
+// Handler serves the metrics in the Prometheus text exposition format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WritePrometheus(w)
+	})
+}
+
+// Phase names a request phase and its timings
+type Phase struct {
+	Name  string // metric label, e.g. "dns"
+	Label string // human-readable name, e.g. "DNS Lookup"
+	Stats TimingStats
+}
+
+// Phases returns the DNS, connect, TLS and server (time to first byte) timings
+func (s MetricsSnapshot) Phases() []Phase {
+	return []Phase{
+		{Name: "dns", Label: "DNS Lookup", Stats: s.DNS},
+		{Name: "connect", Label: "TCP Connect", Stats: s.Connect},
+		{Name: "tls", Label: "TLS Handshake", Stats: s.TLS},
+		{Name: "server", Label: "Server (to first byte)", Stats: s.FirstByte},
+	}
+}
+
+func splitEndpoint(endpoint string) (string, string) {
+	method, route, _ := strings.Cut(endpoint, " ")
+	return method, route
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Owner records who is responsible for a topic, for servers with FeatureTopicOwner
+type Owner struct {
+	Owner   string `json:"owner,omitempty"`   // the person accountable for the topic
+	Team    string `json:"team,omitempty"`    // the team that owns it
+	Contact string `json:"contact,omitempty"` // how to reach them, such as an email address or chat channel
+}
+
+// Owned reports whether the owner names a person or team; a contact alone isn't one
+func (o *Owner) Owned() bool {
+	return o != nil && (o.Owner != "" || o.Team != "")
+}
+
+// GetTopicOwner retrieves who owns a topic (FeatureTopicOwner)
+func (c *Client) GetTopicOwner(topic string) (*Owner, error) {
+	respBody, err := c.request("GET", "/topics/"+url.PathEscape(topic)+"/owner", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner Owner
+	if err := json.Unmarshal(respBody, &owner); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &owner, nil
+}
+
+// SetTopicOwner replaces who owns a topic (FeatureTopicOwner); an empty owner clears it
+func (c *Client) SetTopicOwner(topic string, owner Owner) error {
+	_, err := c.request("PUT", "/topics/"+url.PathEscape(topic)+"/owner", owner)
+	c.topicCache.invalidate(topic)
+	return err
+}
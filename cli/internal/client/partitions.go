@@ -0,0 +1,135 @@
+package client
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/event-store/cli/internal/eventid"
+)
+
+// GetPartitionedEvents fetches events from each of the given partitions of a topic
+// concurrently and merges them into a single stream ordered by timestamp. Each event
+// is tagged with the partition it came from. query.Limit applies per partition; the
+// caller trims the merged result.
+func (c *Client) GetPartitionedEvents(topic string, partitions []int, query *EventsQuery) ([]Event, error) {
+	streams := make([][]Event, len(partitions))
+	errs := make([]error, len(partitions))
+
+	var wg sync.WaitGroup
+	for i, partition := range partitions {
+		wg.Add(1)
+		go func(i, partition int) {
+			defer wg.Done()
+
+			partitionQuery := EventsQuery{}
+			if query != nil {
+				partitionQuery = *query
+			}
+			partitionQuery.Partition = &partition
+
+			events, err := c.GetEvents(topic, &partitionQuery)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for j := range events {
+				if events[j].Partition == nil {
+					events[j].Partition = &partition
+				}
+			}
+			streams[i] = events
+		}(i, partition)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return MergeEvents(streams...), nil
+}
+
+// MergeEvents merges streams that are each already in order into one stream ordered by
+// timestamp, breaking ties by partition and then event sequence
+func MergeEvents(streams ...[]Event) []Event {
+	total := 0
+	h := &eventHeap{}
+	for _, stream := range streams {
+		total += len(stream)
+		if len(stream) > 0 {
+			h.cursors = append(h.cursors, &streamCursor{events: stream, key: orderKey(stream[0])})
+		}
+	}
+	heap.Init(h)
+
+	merged := make([]Event, 0, total)
+	for h.Len() > 0 {
+		cursor := h.cursors[0]
+		merged = append(merged, cursor.events[cursor.next])
+		cursor.next++
+		if cursor.next == len(cursor.events) {
+			heap.Pop(h)
+			continue
+		}
+		cursor.key = orderKey(cursor.events[cursor.next])
+		heap.Fix(h, 0)
+	}
+	return merged
+}
+
+type eventKey struct {
+	timestamp time.Time
+	raw       string // timestamp as received, compared when it cannot be parsed
+	partition int
+	sequence  int64
+}
+
+func orderKey(event Event) eventKey {
+	key := eventKey{raw: event.Timestamp, partition: -1}
+	if t, err := time.Parse(time.RFC3339Nano, event.Timestamp); err == nil {
+		key.timestamp = t
+	}
+	if event.Partition != nil {
+		key.partition = *event.Partition
+	}
+	if id, err := eventid.Parse(event.ID); err == nil {
+		key.sequence = id.Sequence
+	}
+	return key
+}
+
+func (a eventKey) less(b eventKey) bool {
+	if !a.timestamp.Equal(b.timestamp) {
+		return a.timestamp.Before(b.timestamp)
+	}
+	if a.timestamp.IsZero() && a.raw != b.raw {
+		return a.raw < b.raw
+	}
+	if a.partition != b.partition {
+		return a.partition < b.partition
+	}
+	return a.sequence < b.sequence
+}
+
+type streamCursor struct {
+	events []Event
+	next   int
+	key    eventKey
+}
+
+// eventHeap orders stream cursors by the key of their next event
+type eventHeap struct {
+	cursors []*streamCursor
+}
+
+func (h *eventHeap) Len() int           { return len(h.cursors) }
+func (h *eventHeap) Less(i, j int) bool { return h.cursors[i].key.less(h.cursors[j].key) }
+func (h *eventHeap) Swap(i, j int)      { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+func (h *eventHeap) Push(x interface{}) { h.cursors = append(h.cursors, x.(*streamCursor)) }
+func (h *eventHeap) Pop() interface{} {
+	last := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return last
+}
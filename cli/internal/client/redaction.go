@@ -0,0 +1,34 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// RedactRequest represents the body of POST /topics/{topic}/redact, for servers with
+// FeatureEventRedaction. The fields of the events are removed, or replaced by Mask.
+type RedactRequest struct {
+	EventIDs []string `json:"eventIds"`
+	Fields   []string `json:"fields"`         // dotted paths such as payload.email or metadata.user
+	Mask     string   `json:"mask,omitempty"` // empty to remove the fields
+}
+
+// RedactResult represents the response from POST /topics/{topic}/redact
+type RedactResult struct {
+	Redacted int `json:"redacted"` // events changed
+}
+
+// RedactEvents removes or masks fields of a topic's events (FeatureEventRedaction)
+func (c *Client) RedactEvents(topic string, req RedactRequest) (*RedactResult, error) {
+	respBody, err := c.request("POST", "/topics/"+url.PathEscape(topic)+"/redact", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result RedactResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
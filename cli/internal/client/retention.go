@@ -0,0 +1,75 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Retention limits what a topic keeps, for servers with FeatureTopicRetention. The
+// server deletes events older than MaxAgeSeconds and the oldest events beyond
+// MaxEvents; zero means no limit.
+type Retention struct {
+	Topic         string `json:"topic,omitempty"`
+	MaxAgeSeconds int64  `json:"maxAgeSeconds"`
+	MaxEvents     int64  `json:"maxEvents"`
+}
+
+// TruncateRequest represents the body of POST /topics/{topic}/truncate. Events before
+// BeforeEventID, or with timestamps before Before (RFC 3339), are deleted.
+type TruncateRequest struct {
+	BeforeEventID string `json:"beforeEventId,omitempty"`
+	Before        string `json:"before,omitempty"`
+	DryRun        bool   `json:"dryRun,omitempty"` // only count the events that would be deleted
+}
+
+// TruncateResult represents the response from POST /topics/{topic}/truncate
+type TruncateResult struct {
+	Topic        string `json:"topic"`
+	Deleted      int64  `json:"deleted"`
+	FirstEventID string `json:"firstEventId,omitempty"` // the oldest event left, if any
+	DryRun       bool   `json:"dryRun"`
+}
+
+// GetTopicRetention retrieves a topic's retention limits (FeatureTopicRetention)
+func (c *Client) GetTopicRetention(topic string) (*Retention, error) {
+	respBody, err := c.request("GET", "/topics/"+url.PathEscape(topic)+"/retention", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var retention Retention
+	if err := json.Unmarshal(respBody, &retention); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	retention.Topic = topic
+	return &retention, nil
+}
+
+// SetTopicRetention replaces a topic's retention limits (FeatureTopicRetention). The
+// server applies them from then on, deleting events that are already beyond them.
+func (c *Client) SetTopicRetention(topic string, retention Retention) error {
+	_, err := c.request("PUT", "/topics/"+url.PathEscape(topic)+"/retention", retention)
+	c.topicCache.invalidate(topic)
+	return err
+}
+
+// TruncateTopic deletes a topic's events before a cutoff (FeatureTopicTruncate), or with
+// DryRun set counts them
+func (c *Client) TruncateTopic(topic string, req TruncateRequest) (*TruncateResult, error) {
+	respBody, err := c.request("POST", "/topics/"+url.PathEscape(topic)+"/truncate", req)
+	if !req.DryRun {
+		c.topicCache.invalidate(topic)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result TruncateResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	result.Topic = topic
+	result.DryRun = req.DryRun
+	return &result, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// TopicStats summarises a topic's events, as returned by GET /topics/{topic}/stats
+// (FeatureTopicStats) or computed by the CLI from the events themselves
+type TopicStats struct {
+	Topic          string       `json:"topic"`
+	Events         int64        `json:"events"`
+	FirstEventID   string       `json:"firstEventId,omitempty"`
+	LastEventID    string       `json:"lastEventId,omitempty"`
+	FirstTimestamp string       `json:"firstTimestamp,omitempty"`
+	LastTimestamp  string       `json:"lastTimestamp,omitempty"`
+	PerDay         []DayCount   `json:"perDay"`  // oldest first, including days without events
+	PerType        []TypeCount  `json:"perType"` // most frequent first
+	PayloadBytes   PayloadSizes `json:"payloadBytes"`
+	Source         string       `json:"source"` // "server" or "scan"
+}
+
+// DayCount is the number of events with timestamps on a UTC day (YYYY-MM-DD)
+type DayCount struct {
+	Date   string `json:"date"`
+	Events int64  `json:"events"`
+}
+
+// TypeCount is the number of events of an event type
+type TypeCount struct {
+	Type   string `json:"type"`
+	Events int64  `json:"events"`
+}
+
+// PayloadSizes describes the distribution of payload sizes, in bytes of compact JSON
+type PayloadSizes struct {
+	Total   int64        `json:"total"`
+	Min     int          `json:"min"`
+	Max     int          `json:"max"`
+	Mean    float64      `json:"mean"`
+	P50     int          `json:"p50"`
+	P90     int          `json:"p90"`
+	P99     int          `json:"p99"`
+	Buckets []SizeBucket `json:"buckets"`
+}
+
+// SizeBucket counts the payloads of at least Min and less than Max bytes (Max 0 for no
+// upper bound)
+type SizeBucket struct {
+	Min    int   `json:"min"`
+	Max    int   `json:"max,omitempty"`
+	Events int64 `json:"events"`
+}
+
+// GetTopicStats retrieves a topic's statistics from the server (FeatureTopicStats)
+func (c *Client) GetTopicStats(topic string) (*TopicStats, error) {
+	endpoint := "/topics/" + url.PathEscape(topic) + "/stats"
+	respBody, err := c.request("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats TopicStats
+	if err := json.Unmarshal(respBody, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	stats.Source = "server"
+	return &stats, nil
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/event-store/cli/internal/tracing"
+)
+
+// SetTracer enables an OpenTelemetry span for every API call
+func (c *Client) SetTracer(tracer *tracing.Tracer) {
+	c.tracer = tracer
+}
+
+// startSpan starts a span for a request, named after the route template of the endpoint
+func (c *Client) startSpan(method, endpoint string) *tracing.Span {
+	if c.tracer == nil {
+		return nil
+	}
+
+	path := endpoint
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+
+	route, attrs := routeTemplate(path)
+	span := c.tracer.Start(method + " " + route)
+	span.SetAttribute("http.request.method", method)
+	span.SetAttribute("http.route", route)
+	span.SetAttribute("url.full", c.baseURL+endpoint)
+	if u, err := url.Parse(c.baseURL); err == nil {
+		span.SetAttribute("server.address", u.Hostname())
+	}
+	for k, v := range attrs {
+		span.SetAttribute(k, v)
+	}
+	return span
+}
+
+// routeTemplate replaces topic and consumer identifiers in a path with placeholders,
+// returning them as span attributes
+func routeTemplate(path string) (string, map[string]string) {
+	attrs := make(map[string]string)
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	if len(segments) >= 2 {
+		value, err := url.PathUnescape(segments[1])
+		if err != nil {
+			value = segments[1]
+		}
+		switch {
+		case segments[0] == "topics":
+			attrs["eventstore.topic"] = value
+			segments[1] = "{topic}"
+		case segments[0] == "consumers" && segments[1] != "register":
+			attrs["eventstore.consumer_id"] = value
+			segments[1] = "{id}"
+		}
+	}
+
+	return "/" + strings.Join(segments, "/"), attrs
+}
@@ -0,0 +1,135 @@
+package compose
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/generate"
+)
+
+// Check checks a payload written by hand against an event type's schema, with the same
+// checks as the answers to the prompts: required properties, types, allowed values,
+// ranges and string lengths, patterns and formats, nested objects and array items
+// included. It returns a problem per property, e.g. "address.city: a value is required".
+// Properties the schema doesn't declare are left to the publish checks.
+func Check(schema client.Schema, payload map[string]interface{}) []string {
+	return checkObject("", payload, schema.Properties, schema.Required, false)
+}
+
+// CheckPartial checks a payload like Check, except that properties it leaves out are
+// never a problem, even required ones: for payloads with only the properties someone
+// relies on, such as a consumer's examples of the events it expects
+func CheckPartial(schema client.Schema, payload map[string]interface{}) []string {
+	return checkObject("", payload, schema.Properties, schema.Required, true)
+}
+
+func checkObject(prefix string, object map[string]interface{}, properties map[string]interface{}, required []string, partial bool) []string {
+	var problems []string
+	for _, name := range required {
+		if _, ok := object[name]; !ok && !partial {
+			problems = append(problems, fmt.Sprintf("%s%s: a value is required", prefix, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if schema, ok := properties[name].(map[string]interface{}); ok {
+			problems = append(problems, checkValue(prefix+name, object[name], schema, partial)...)
+		}
+	}
+	return problems
+}
+
+// checkValue returns the problems with a value of a property, or of an array item
+func checkValue(path string, value interface{}, schema map[string]interface{}, partial bool) []string {
+	problem := func(err error) []string {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+	if allowed, ok := schema["const"]; ok && formatValue(allowed) != formatValue(value) {
+		return problem(fmt.Errorf("must be %s", formatValue(allowed)))
+	}
+	if values, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range values {
+			found = found || formatValue(allowed) == formatValue(value)
+		}
+		if !found {
+			return problem(fmt.Errorf("'%s' is not one of the allowed values", formatValue(value)))
+		}
+	}
+	if value == nil && allowsNull(schema) {
+		return nil
+	}
+	// A schema without a type allows any value
+	if _, typed := schema["type"]; !typed {
+		if _, ok := schema["properties"]; !ok {
+			return nil
+		}
+	}
+
+	switch kind := generate.SchemaType(schema); kind {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return problem(fmt.Errorf("must be an object"))
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		return checkObject(path+".", object, properties, stringList(schema["required"]), partial)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return problem(fmt.Errorf("must be an array"))
+		}
+		itemSchema, _ := schema["items"].(map[string]interface{})
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, checkValue(fmt.Sprintf("%s[%d]", path, i), item, itemSchema, partial)...)
+		}
+		return problems
+	case "integer", "number":
+		n, ok := value.(float64)
+		if kind == "integer" && (!ok || n != math.Trunc(n)) {
+			return problem(fmt.Errorf("must be an integer"))
+		}
+		if !ok {
+			return problem(fmt.Errorf("must be a number"))
+		}
+		if err := checkRange(schema, n); err != nil {
+			return problem(err)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return problem(fmt.Errorf("must be true or false"))
+		}
+	case "null":
+		if value != nil {
+			return problem(fmt.Errorf("must be null"))
+		}
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return problem(fmt.Errorf("must be a string"))
+		}
+		if err := checkString(schema, s); err != nil {
+			return problem(err)
+		}
+	}
+	return nil
+}
+
+// allowsNull reports whether a schema's type list includes null
+func allowsNull(schema map[string]interface{}) bool {
+	types, _ := schema["type"].([]interface{})
+	for _, t := range types {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
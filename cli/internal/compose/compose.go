@@ -0,0 +1,360 @@
+package compose
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/generate"
+)
+
+// ErrInputEnded is returned when input ends before a value has been entered
+var ErrInputEnded = errors.New("input ended")
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Composer prompts for the fields of an event payload one at a time, validating each
+// answer against its JSON schema and asking again until it is valid
+type Composer struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// New creates a composer reading answers from in and writing prompts to out
+func New(in io.Reader, out io.Writer) *Composer {
+	return &Composer{in: bufio.NewReader(in), out: out}
+}
+
+// Choose asks for one of the options, by number or by name
+func (c *Composer) Choose(label string, options []string) (string, error) {
+	fmt.Fprintf(c.out, "%s:\n", label)
+	for i, option := range options {
+		fmt.Fprintf(c.out, "  %d) %s\n", i+1, option)
+	}
+	for {
+		answer, err := c.ask("Choose [1-" + strconv.Itoa(len(options)) + "]: ")
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		for _, option := range options {
+			if option == answer {
+				return option, nil
+			}
+		}
+		fmt.Fprintf(c.out, "  '%s' is not one of the options\n", answer)
+	}
+}
+
+// Confirm asks a yes/no question, returning fallback for an empty answer
+func (c *Composer) Confirm(question string, fallback bool) (bool, error) {
+	hint := "[y/N]"
+	if fallback {
+		hint = "[Y/n]"
+	}
+	for {
+		answer, err := c.ask(question + " " + hint + " ")
+		if err != nil {
+			return false, err
+		}
+		if answer == "" {
+			return fallback, nil
+		}
+		if value, ok := parseBool(answer); ok {
+			return value, nil
+		}
+		fmt.Fprintln(c.out, "  please answer y or n")
+	}
+}
+
+// Payload prompts for each property of an event type's schema, required properties
+// first. Optional properties without a default are left out when skipped.
+func (c *Composer) Payload(schema client.Schema) (map[string]interface{}, error) {
+	return c.object("", schema.Properties, schema.Required)
+}
+
+func (c *Composer) object(prefix string, properties map[string]interface{}, required []string) (map[string]interface{}, error) {
+	isRequired := make(map[string]bool, len(required))
+	for _, name := range required {
+		isRequired[name] = true
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if isRequired[names[i]] != isRequired[names[j]] {
+			return isRequired[names[i]]
+		}
+		return names[i] < names[j]
+	})
+
+	result := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		schema, _ := properties[name].(map[string]interface{})
+		value, present, err := c.field(prefix+name, schema, isRequired[name])
+		if err != nil {
+			return nil, err
+		}
+		if present {
+			result[name] = value
+		}
+	}
+	return result, nil
+}
+
+// field prompts for one property, returning whether a value was given
+func (c *Composer) field(path string, schema map[string]interface{}, required bool) (interface{}, bool, error) {
+	if value, ok := schema["const"]; ok {
+		fmt.Fprintf(c.out, "%s = %s (constant)\n", path, formatValue(value))
+		return value, true, nil
+	}
+
+	kind := generate.SchemaType(schema)
+	if kind == "object" {
+		if !required {
+			include, err := c.Confirm(fmt.Sprintf("Include %s (object)?", path), false)
+			if err != nil || !include {
+				return nil, false, err
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		value, err := c.object(path+".", properties, stringList(schema["required"]))
+		return value, err == nil, err
+	}
+
+	prompt := c.describe(path, kind, schema, required)
+	defaultValue, hasDefault := schema["default"]
+	for {
+		answer, err := c.ask(prompt)
+		if err != nil {
+			return nil, false, err
+		}
+		if answer == "" {
+			switch {
+			case hasDefault:
+				return defaultValue, true, nil
+			case !required:
+				return nil, false, nil
+			}
+			fmt.Fprintln(c.out, "  a value is required")
+			continue
+		}
+
+		value, err := parse(kind, schema, answer)
+		if err != nil {
+			fmt.Fprintf(c.out, "  %v\n", err)
+			continue
+		}
+		return value, true, nil
+	}
+}
+
+// describe builds the prompt for a property, e.g. "email (string, email, required): "
+func (c *Composer) describe(path, kind string, schema map[string]interface{}, required bool) string {
+	details := []string{kind}
+	if format, ok := schema["format"].(string); ok {
+		details = append(details, format)
+	}
+	if values, ok := schema["enum"].([]interface{}); ok {
+		options := make([]string, len(values))
+		for i, value := range values {
+			options[i] = formatValue(value)
+		}
+		details = append(details, "one of "+strings.Join(options, "|"))
+	}
+	if kind == "array" {
+		details = append(details, "JSON array or comma-separated")
+	}
+	if required {
+		details = append(details, "required")
+	}
+
+	prompt := fmt.Sprintf("%s (%s)", path, strings.Join(details, ", "))
+	if value, ok := schema["default"]; ok {
+		prompt += fmt.Sprintf(" [%s]", formatValue(value))
+	}
+	return prompt + ": "
+}
+
+func (c *Composer) ask(prompt string) (string, error) {
+	fmt.Fprint(c.out, prompt)
+	line, err := c.in.ReadString('\n')
+	if err != nil && (line == "" || err != io.EOF) {
+		fmt.Fprintln(c.out)
+		return "", ErrInputEnded
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// parse converts an answer to a value of the schema's type and checks its constraints
+func parse(kind string, schema map[string]interface{}, answer string) (interface{}, error) {
+	var value interface{}
+	switch kind {
+	case "integer":
+		n, err := strconv.ParseInt(answer, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not an integer", answer)
+		}
+		if err := checkRange(schema, float64(n)); err != nil {
+			return nil, err
+		}
+		value = n
+	case "number":
+		n, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", answer)
+		}
+		if err := checkRange(schema, n); err != nil {
+			return nil, err
+		}
+		value = n
+	case "boolean":
+		b, ok := parseBool(answer)
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not a boolean (use true/false or y/n)", answer)
+		}
+		value = b
+	case "array":
+		items, err := parseArray(schema, answer)
+		if err != nil {
+			return nil, err
+		}
+		value = items
+	default:
+		if err := checkString(schema, answer); err != nil {
+			return nil, err
+		}
+		value = answer
+	}
+
+	if values, ok := schema["enum"].([]interface{}); ok {
+		for _, allowed := range values {
+			if formatValue(allowed) == formatValue(value) {
+				return allowed, nil
+			}
+		}
+		return nil, fmt.Errorf("'%s' is not one of the allowed values", answer)
+	}
+	return value, nil
+}
+
+func parseArray(schema map[string]interface{}, answer string) ([]interface{}, error) {
+	var items []interface{}
+	if strings.HasPrefix(answer, "[") {
+		if err := json.Unmarshal([]byte(answer), &items); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %v", err)
+		}
+		return items, nil
+	}
+
+	itemSchema, _ := schema["items"].(map[string]interface{})
+	itemKind := generate.SchemaType(itemSchema)
+	for _, part := range strings.Split(answer, ",") {
+		item, err := parse(itemKind, itemSchema, strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func checkRange(schema map[string]interface{}, n float64) error {
+	if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+		return fmt.Errorf("must be at least %v", minimum)
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+		return fmt.Errorf("must be at most %v", maximum)
+	}
+	if minimum, ok := schema["exclusiveMinimum"].(float64); ok && n <= minimum {
+		return fmt.Errorf("must be greater than %v", minimum)
+	}
+	if maximum, ok := schema["exclusiveMaximum"].(float64); ok && n >= maximum {
+		return fmt.Errorf("must be less than %v", maximum)
+	}
+	return nil
+}
+
+func checkString(schema map[string]interface{}, s string) error {
+	length := len([]rune(s))
+	if minLength, ok := schema["minLength"].(float64); ok && length < int(minLength) {
+		return fmt.Errorf("must be at least %d characters", int(minLength))
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && length > int(maxLength) {
+		return fmt.Errorf("must be at most %d characters", int(maxLength))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+			return fmt.Errorf("must match the pattern %s", pattern)
+		}
+	}
+
+	format, _ := schema["format"].(string)
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return fmt.Errorf("'%s' is not an RFC 3339 date-time, e.g. %s", s, time.Now().UTC().Format(time.RFC3339))
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return fmt.Errorf("'%s' is not a date (YYYY-MM-DD)", s)
+		}
+	case "email":
+		if at := strings.Index(s, "@"); at < 1 || at == len(s)-1 {
+			return fmt.Errorf("'%s' is not an email address", s)
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(s) {
+			return fmt.Errorf("'%s' is not a UUID", s)
+		}
+	case "uri", "url":
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" {
+			return fmt.Errorf("'%s' is not an absolute URI", s)
+		}
+	}
+	return nil
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "y", "yes", "true":
+		return true, true
+	case "n", "no", "false":
+		return false, true
+	}
+	return false, false
+}
+
+func formatValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+// Package compress compresses and decompresses files as they are streamed, such as
+// exports of millions of events, without temporary files.
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codecs lists the codecs files can be compressed with
+var Codecs = []string{"gzip", "zstd", "none"}
+
+// Magic numbers starting compressed files, to detect their codec
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Of guesses a codec from a file name's extension (.gz, .zst), or returns ""
+func Of(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz", ".gzip":
+		return "gzip"
+	case ".zst", ".zstd":
+		return "zstd"
+	}
+	return ""
+}
+
+// Trim removes a codec's extension from a file name, so orders.ndjson.gz is orders.ndjson
+func Trim(path string) string {
+	if Of(path) == "" {
+		return path
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
+
+// Check checks a codec, returning "" for none
+func Check(codec string) (string, error) {
+	switch codec {
+	case "", "none":
+		return "", nil
+	case "gzip", "zstd":
+		return codec, nil
+	}
+	return "", fmt.Errorf("unsupported compression '%s' (expected %s)", codec, strings.Join(Codecs, ", "))
+}
+
+// NewWriter returns a writer that compresses what is written to it with a codec into w,
+// or w itself for none. Close must be called to finish the compressed stream; it doesn't
+// close w.
+func NewWriter(w io.Writer, codec string) (io.WriteCloser, error) {
+	switch codec {
+	case "", "none":
+		return nopCloser{w}, nil
+	case "gzip":
+		return gzip.NewWriter(w), nil
+	case "zstd":
+		return zstd.NewWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported compression '%s' (expected %s)", codec, strings.Join(Codecs, ", "))
+}
+
+// NewReader returns a reader of r decompressed with the codec its contents start with,
+// gzip or zstd, or of r as it is when it isn't compressed. Reads fail if the compressed
+// stream is corrupt or cut short.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(head, zstdMagic):
+		decoder, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zstdReader{decoder}, nil
+	}
+	return io.NopCloser(br), nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// zstdReader gives a zstd decoder the Close of an io.ReadCloser
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+func (z zstdReader) Close() error {
+	z.Decoder.Close()
+	return nil
+}
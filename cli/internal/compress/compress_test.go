@@ -0,0 +1,71 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data := strings.Repeat(`{"id":"orders-1","type":"order.placed","payload":{"total":42}}`+"\n", 1000)
+	for _, codec := range Codecs {
+		var compressed bytes.Buffer
+		w, err := NewWriter(&compressed, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if codec != "none" && compressed.Len() >= len(data) {
+			t.Errorf("%s: compressed %d bytes to %d", codec, len(data), compressed.Len())
+		}
+
+		r, err := NewReader(&compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil || string(got) != data {
+			t.Errorf("%s: read back %d bytes, %v, want %d", codec, len(got), err, len(data))
+		}
+	}
+}
+
+// TestTruncated checks that a compressed stream cut short fails to read rather than
+// reading as a shorter file
+func TestTruncated(t *testing.T) {
+	data := strings.Repeat("event\n", 10000)
+	for _, codec := range []string{"gzip", "zstd"} {
+		var compressed bytes.Buffer
+		w, _ := NewWriter(&compressed, codec)
+		io.WriteString(w, data)
+		w.Close()
+
+		r, err := NewReader(bytes.NewReader(compressed.Bytes()[:compressed.Len()-8]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadAll(r); err == nil {
+			t.Errorf("%s: reading a truncated stream succeeded", codec)
+		}
+		r.Close()
+	}
+}
+
+func TestOf(t *testing.T) {
+	tests := map[string]string{"orders.ndjson.gz": "gzip", "orders.ndjson.ZST": "zstd", "orders.ndjson": ""}
+	for path, want := range tests {
+		if got := Of(path); got != want {
+			t.Errorf("Of(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if got := Trim("orders.ndjson.gz"); got != "orders.ndjson" {
+		t.Errorf("Trim = %q", got)
+	}
+}
@@ -4,24 +4,148 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
 // Config represents the CLI configuration
 type Config struct {
-	Server ServerConfig `mapstructure:"server"`
-	Output OutputConfig  `mapstructure:"output"`
+	Server   ServerConfig            `mapstructure:"server"`
+	Output   OutputConfig            `mapstructure:"output"`
+	HTTP     HTTPConfig              `mapstructure:"http"`
+	Cache    CacheConfig             `mapstructure:"cache"`
+	Publish  PublishConfig           `mapstructure:"publish"`
+	Hooks    []Hook                  `mapstructure:"hooks"`
+	Audit    AuditConfig             `mapstructure:"audit"`
+	Contexts map[string]ServerConfig `mapstructure:"contexts"` // named servers, such as dev, staging and prod
+	Topics   map[string]TopicConfig  `mapstructure:"-"`        // settings by topic name, read by readTopics
 }
 
 // ServerConfig contains server connection settings
 type ServerConfig struct {
-	URL string `mapstructure:"url"`
+	URL  string     `mapstructure:"url"`
+	Auth AuthConfig `mapstructure:"auth"` // for servers behind an identity provider
+}
+
+// AuthConfig is how 'es auth login' logs in to a server behind an OAuth2 or OpenID
+// Connect identity provider
+type AuthConfig struct {
+	Issuer        string   `mapstructure:"issuer"`          // the provider's endpoints are discovered from its /.well-known/openid-configuration
+	ClientID      string   `mapstructure:"client-id"`       // the CLI's client registered with the provider
+	ClientSecret  string   `mapstructure:"client-secret"`   // for confidential clients, with $VAR expanded
+	Scopes        []string `mapstructure:"scopes"`          // default: openid, profile, offline_access
+	Audience      string   `mapstructure:"audience"`        // the API to ask tokens for, for providers that need it
+	TokenURL      string   `mapstructure:"token-url"`       // instead of the discovered token endpoint
+	DeviceAuthURL string   `mapstructure:"device-auth-url"` // instead of the discovered device authorization endpoint
+}
+
+// Configured reports whether a server is behind an identity provider
+func (a AuthConfig) Configured() bool {
+	return a.ClientID != ""
 }
 
 // OutputConfig contains output format settings
 type OutputConfig struct {
-	Format string `mapstructure:"format"`
+	Format string   `mapstructure:"format"`
+	Mask   []string `mapstructure:"mask"` // fields event commands print as '***', e.g. payload.password
+}
+
+// HTTPConfig tunes the client's connections to the event store
+type HTTPConfig struct {
+	Timeout          time.Duration `mapstructure:"timeout"`           // per request; 0 for 30s
+	MaxIdleConns     int           `mapstructure:"max-idle-conns"`    // idle keep-alive connections kept per server
+	IdleConnTimeout  time.Duration `mapstructure:"idle-conn-timeout"` // how long an idle connection is kept
+	DisableKeepAlive bool          `mapstructure:"disable-keep-alive"`
+	DisableGzip      bool          `mapstructure:"disable-gzip"`      // don't ask for gzip-compressed responses
+	CompressRequests bool          `mapstructure:"compress-requests"` // gzip large request bodies, if the server accepts them
+	HTTP2            string        `mapstructure:"http2"`             // auto, off or h2c
+	Proxy            string        `mapstructure:"proxy"`             // http(s):// or socks5:// URL, or direct; default: $HTTPS_PROXY, $HTTP_PROXY
+}
+
+// CacheConfig controls caching of topic metadata, such as schemas, between requests
+type CacheConfig struct {
+	TTL  time.Duration `mapstructure:"ttl"`  // how long topic metadata is cached; 0 for no caching
+	Disk bool          `mapstructure:"disk"` // share cached metadata between commands under ~/.es/cache
+}
+
+// PublishConfig contains the checks made on events before they are published
+type PublishConfig struct {
+	MaxPayloadSize string `mapstructure:"max-payload-size"` // e.g. 256kb; 0 for no limit
+	Strict         bool   `mapstructure:"strict"`           // as --strict for every publish
+}
+
+// AuditConfig controls the log of commands that change an event store
+type AuditConfig struct {
+	Disable  bool              `mapstructure:"disable"`  // don't record commands
+	File     string            `mapstructure:"file"`     // the log; default ~/.es/audit.ndjson
+	Endpoint string            `mapstructure:"endpoint"` // also POST each record as JSON to this URL
+	Headers  map[string]string `mapstructure:"headers"`  // sent to the endpoint, with $VAR expanded
+}
+
+// TopicConfig holds the settings of a topic. Its tags are yaml's, as the topics
+// section is read by readTopics rather than viper.
+type TopicConfig struct {
+	Defaults TopicDefaults `yaml:"defaults"`
+}
+
+// TopicDefaults are the flags event commands take for a topic when they aren't given on
+// the command line
+type TopicDefaults struct {
+	Limit  int      `yaml:"limit"`  // event list --limit
+	Fields []string `yaml:"fields"` // event list and tail --fields
+	Filter string   `yaml:"filter"` // event list, tail and export --filter
+	Mask   []string `yaml:"mask"`   // event list, tail and export --mask, instead of output.mask
+}
+
+// Hook is a shell command run before or after commands
+type Hook struct {
+	Command string `mapstructure:"command"` // command path such as 'event publish', a group such as 'topic', or '*'
+	When    string `mapstructure:"when"`    // before or after
+	Server  string `mapstructure:"server"`  // only for server URLs matching this pattern, where * matches anything
+	Run     string `mapstructure:"run"`     // run through 'sh -c'
+}
+
+// ContextNames returns the names of the configured contexts, sorted
+func (c *Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Context returns the server of a named context. Names are case-insensitive, as the
+// config file's keys are.
+func (c *Config) Context(name string) (ServerConfig, error) {
+	server, ok := c.Contexts[strings.ToLower(name)]
+	if !ok {
+		if len(c.Contexts) == 0 {
+			return ServerConfig{}, fmt.Errorf("unknown context '%s' (no contexts are configured)", name)
+		}
+		return ServerConfig{}, fmt.Errorf("unknown context '%s' (configured: %s)", name, strings.Join(c.ContextNames(), ", "))
+	}
+	return server, nil
+}
+
+// Auth returns how to log in to a server by URL: the auth of the default server or a
+// context with that URL
+func (c *Config) Auth(url string) (AuthConfig, bool) {
+	url = strings.TrimSuffix(url, "/")
+	if strings.TrimSuffix(c.Server.URL, "/") == url && c.Server.Auth.Configured() {
+		return c.Server.Auth, true
+	}
+	for _, name := range c.ContextNames() {
+		server := c.Contexts[name]
+		if strings.TrimSuffix(server.URL, "/") == url && server.Auth.Configured() {
+			return server.Auth, true
+		}
+	}
+	return AuthConfig{}, false
 }
 
 // DefaultConfig returns a configuration with default values
@@ -33,9 +157,21 @@ func DefaultConfig() *Config {
 		Output: OutputConfig{
 			Format: "table",
 		},
+		Publish: PublishConfig{
+			MaxPayloadSize: "256kb",
+		},
 	}
 }
 
+// Dir returns the CLI's configuration directory, ~/.es
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".es"), nil
+}
+
 // LoadConfig loads configuration from file or returns defaults
 func LoadConfig(configPath string) (*Config, error) {
 	cfg := DefaultConfig()
@@ -64,10 +200,31 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := viper.Unmarshal(cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	topics, err := readTopics(configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Topics = topics
 
 	return cfg, nil
 }
 
+// readTopics reads the topics section of a config file. Topic names may contain dots,
+// which viper would take as nested keys, so the section is read with yaml directly.
+func readTopics(configPath string) (map[string]TopicConfig, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var file struct {
+		Topics map[string]TopicConfig `yaml:"topics"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config topics: %w", err)
+	}
+	return file.Topics, nil
+}
+
 // SaveConfig saves configuration to file
 func SaveConfig(cfg *Config, configPath string) error {
 	if configPath == "" {
@@ -0,0 +1,121 @@
+package consumers
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Deletion outcomes
+const (
+	Deleted     = "deleted"
+	WouldDelete = "would delete"
+	Failed      = "failed"
+)
+
+// Selector picks consumers to delete. Topics and CallbackPrefix must both match when
+// both are set.
+type Selector struct {
+	All            bool
+	Topics         []string // subscribed to any of these topics
+	CallbackPrefix string
+}
+
+// IsZero reports whether the selector selects nothing
+func (s Selector) IsZero() bool {
+	return !s.All && len(s.Topics) == 0 && s.CallbackPrefix == ""
+}
+
+// Select returns the consumers the selector matches, in the order given
+func (s Selector) Select(consumers []client.Consumer) []client.Consumer {
+	var selected []client.Consumer
+	for _, consumer := range consumers {
+		if s.matches(consumer) {
+			selected = append(selected, consumer)
+		}
+	}
+	return selected
+}
+
+func (s Selector) matches(consumer client.Consumer) bool {
+	if s.All {
+		return true
+	}
+	if s.CallbackPrefix != "" && !strings.HasPrefix(consumer.Callback, s.CallbackPrefix) {
+		return false
+	}
+	if len(s.Topics) > 0 {
+		for _, topic := range s.Topics {
+			if _, ok := consumer.Topics[topic]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Deletion is the outcome of deleting one consumer
+type Deletion struct {
+	ID       string `json:"id"`
+	Callback string `json:"callback"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Result is the outcome of deleting a set of consumers
+type Result struct {
+	DryRun    bool       `json:"dryRun"`
+	Deleted   int        `json:"deleted"`
+	Failed    int        `json:"failed"`
+	Deletions []Deletion `json:"consumers"`
+}
+
+// Delete unregisters consumers with up to workers requests at a time, carrying on past
+// failures. With dryRun nothing is deleted and every consumer is reported as one that
+// would be.
+func Delete(apiClient *client.Client, consumers []client.Consumer, workers int, dryRun bool) *Result {
+	result := &Result{DryRun: dryRun, Deletions: make([]Deletion, len(consumers))}
+	for i, consumer := range consumers {
+		result.Deletions[i] = Deletion{ID: consumer.ID, Callback: consumer.Callback, Status: WouldDelete}
+	}
+	if dryRun {
+		return result
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				deletion := &result.Deletions[i]
+				if err := apiClient.DeleteConsumer(deletion.ID); err != nil {
+					deletion.Status = Failed
+					deletion.Error = err.Error()
+					continue
+				}
+				deletion.Status = Deleted
+			}
+		}()
+	}
+	for i := range consumers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, deletion := range result.Deletions {
+		if deletion.Status == Deleted {
+			result.Deleted++
+		} else {
+			result.Failed++
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package consumers
+
+import (
+	"context"
+	"time"
+
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/client"
+)
+
+// Retry outcomes
+const (
+	Retried     = "retried"
+	RetryFailed = "failed"
+)
+
+// RetryOutcome is the outcome of retrying one dead-lettered event
+type RetryOutcome struct {
+	EntryID string `json:"entryId"`
+	EventID string `json:"eventId,omitempty"` // unknown when retried by entry ID
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RetryResult summarises a retry of dead-lettered events
+type RetryResult struct {
+	ConsumerID string         `json:"consumerId"`
+	Retried    int            `json:"retried"`
+	Failed     int            `json:"failed"`
+	Seconds    float64        `json:"durationSeconds"`
+	Outcomes   []RetryOutcome `json:"entries"`
+}
+
+// RetryDLQ asks the server to re-deliver dead-lettered events one at a time, at most
+// rate per second (0 for unlimited), carrying on past failures and calling progress
+// after each. It stops early when ctx is done; the result covers the entries tried.
+func RetryDLQ(ctx context.Context, apiClient *client.Client, consumerID string, entries []client.DLQEntry, rate float64, progress func(n int)) *RetryResult {
+	result := &RetryResult{ConsumerID: consumerID, Outcomes: []RetryOutcome{}}
+	started := time.Now()
+	defer func() { result.Seconds = time.Since(started).Seconds() }()
+
+	jobs := make(chan int)
+	go bench.Schedule(ctx, rate, len(entries), jobs)
+	for i := range jobs {
+		entry := entries[i]
+		outcome := RetryOutcome{EntryID: entry.ID, EventID: entry.Event.ID, Status: Retried}
+		if err := apiClient.RetryDLQEntry(consumerID, entry.ID); err != nil {
+			outcome.Status = RetryFailed
+			outcome.Error = err.Error()
+			result.Failed++
+		} else {
+			result.Retried++
+		}
+		result.Outcomes = append(result.Outcomes, outcome)
+		if progress != nil {
+			progress(1)
+		}
+	}
+	return result
+}
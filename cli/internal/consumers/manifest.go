@@ -0,0 +1,273 @@
+package consumers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/labels"
+	"go.yaml.in/yaml/v3"
+)
+
+// Manifest is a consumer declared in a YAML file, for 'es consumer register --file':
+//
+//	callback: https://billing.example.com/events
+//	description: Bills customers for shipped orders
+//	labels:
+//	  team: payments
+//	topics:
+//	  - name: orders
+//	    after: latest   # only the events published from now on
+//	  - payments        # every event, from the start
+type Manifest struct {
+	Path        string
+	Callback    string
+	Description string
+	Labels      map[string]string
+	Topics      []ManifestTopic
+}
+
+// ManifestTopic is a topic a manifest's consumer receives events of. After is the
+// last event the consumer has already handled, so delivery starts with the next one: an
+// event ID, a sequence number, "latest" or "latest~n", or empty for the topic's first
+// event.
+type ManifestTopic struct {
+	Name  string
+	After string
+	line  int
+}
+
+// manifestFields and manifestTopicFields are the fields manifests may have, in the
+// order they are listed in errors
+var (
+	manifestFields      = []string{"callback", "description", "labels", "topics"}
+	manifestTopicFields = []string{"name", "after"}
+)
+
+// LoadManifest reads a consumer manifest and checks it. Errors give the file and line at
+// fault.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return parseManifest(path, data)
+}
+
+// parseManifest checks a manifest read from path
+func parseManifest(path string, data []byte) (*Manifest, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("%s is empty (expected a consumer with a callback and topics)", path)
+	}
+
+	m := &Manifest{Path: path}
+	if err := m.parse(root.Content[0]); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parse reads the manifest's fields from its document's top-level mapping
+func (m *Manifest) parse(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return m.errorf(node, "expected a mapping with callback and topics")
+	}
+	var callback, topics *yaml.Node
+	if err := m.eachField(node, manifestFields, "the manifest", func(key, value *yaml.Node) error {
+		switch key.Value {
+		case "callback":
+			callback = value
+			return m.scalar(value, "callback", &m.Callback)
+		case "description":
+			return m.scalar(value, "description", &m.Description)
+		case "labels":
+			return m.parseLabels(value)
+		case "topics":
+			topics = value
+			return m.parseTopics(value)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if callback == nil || m.Callback == "" {
+		return m.errorf(node, "the consumer needs a callback, the URL events are delivered to")
+	}
+	if u, err := url.Parse(m.Callback); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return m.errorf(callback, "callback '%s' isn't an http or https URL", m.Callback)
+	}
+	if topics == nil || len(m.Topics) == 0 {
+		return m.errorf(node, "the consumer needs topics to receive events of")
+	}
+	return nil
+}
+
+func (m *Manifest) parseLabels(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return m.errorf(node, "labels must be a mapping of keys to values, such as 'team: payments'")
+	}
+	m.Labels = make(map[string]string, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if _, ok := m.Labels[key.Value]; ok {
+			return m.errorf(key, "label '%s' is given more than once", key.Value)
+		}
+		var text string
+		if err := m.scalar(value, fmt.Sprintf("label '%s'", key.Value), &text); err != nil {
+			return err
+		}
+		if err := labels.Check(key.Value, text); err != nil {
+			return m.errorf(key, "%s", err)
+		}
+		m.Labels[key.Value] = text
+	}
+	return nil
+}
+
+func (m *Manifest) parseTopics(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return m.errorf(node, "topics must be a list of topic names, or of topics with a name and where to start after")
+	}
+	seen := map[string]int{}
+	for _, item := range node.Content {
+		topic := ManifestTopic{line: item.Line}
+		switch item.Kind {
+		case yaml.ScalarNode:
+			topic.Name = item.Value
+		case yaml.MappingNode:
+			if err := m.eachField(item, manifestTopicFields, "a topic", func(key, value *yaml.Node) error {
+				switch key.Value {
+				case "name":
+					return m.scalar(value, "a topic's name", &topic.Name)
+				case "after":
+					return m.scalar(value, "after", &topic.After)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		default:
+			return m.errorf(item, "expected a topic name, or a topic with a name and where to start after")
+		}
+
+		if topic.Name == "" {
+			return m.errorf(item, "the topic needs a name")
+		}
+		if line, ok := seen[topic.Name]; ok {
+			return m.errorf(item, "topic '%s' is already listed on line %d", topic.Name, line)
+		}
+		seen[topic.Name] = item.Line
+		if err := checkAfter(topic); err != nil {
+			return m.errorf(item, "%s", err)
+		}
+		m.Topics = append(m.Topics, topic)
+	}
+	return nil
+}
+
+// checkAfter checks a topic's After without the server: a full event ID must be of the
+// topic, and the rest is checked when it is resolved
+func checkAfter(topic ManifestTopic) error {
+	if topic.After == "" || topic.After == "latest" || strings.HasPrefix(topic.After, "latest~") {
+		return nil
+	}
+	id, err := eventid.Resolve(topic.Name, topic.After, nil)
+	if err != nil {
+		return err
+	}
+	if id.Topic != topic.Name {
+		return fmt.Errorf("event '%s' isn't an event of topic '%s'", topic.After, topic.Name)
+	}
+	return nil
+}
+
+// scalar reads a field's string value; null is empty
+func (m *Manifest) scalar(node *yaml.Node, field string, into *string) error {
+	if node.Kind != yaml.ScalarNode {
+		return m.errorf(node, "%s must be a single value, not a list or mapping", field)
+	}
+	if node.Tag != "!!null" {
+		*into = node.Value
+	}
+	return nil
+}
+
+func (m *Manifest) errorf(node *yaml.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", m.Path, node.Line, fmt.Sprintf(format, args...))
+}
+
+// eachField calls fn with the key and value of each of a mapping's fields, failing on
+// fields that aren't among those expected or are given twice
+func (m *Manifest) eachField(node *yaml.Node, expected []string, what string, fn func(key, value *yaml.Node) error) error {
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		known := false
+		for _, field := range expected {
+			known = known || key.Value == field
+		}
+		if !known {
+			return m.errorf(key, "unknown field '%s' in %s (expected %s)", key.Value, what, strings.Join(expected, ", "))
+		}
+		if seen[key.Value] {
+			return m.errorf(key, "field '%s' is given more than once", key.Value)
+		}
+		seen[key.Value] = true
+		if err := fn(key, node.Content[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Request resolves the manifest's topics against the server into a registration
+// request. Each topic must exist, and each After must be one of its events, except
+// that "latest" of a topic with no events yet starts the consumer at its first event.
+func (m *Manifest) Request(apiClient *client.Client) (client.ConsumerRegistrationRequest, error) {
+	req := client.ConsumerRegistrationRequest{
+		Callback:    m.Callback,
+		Topics:      make(map[string]*string, len(m.Topics)),
+		Description: m.Description,
+		Labels:      m.Labels,
+	}
+	for _, topic := range m.Topics {
+		info, err := apiClient.GetTopic(topic.Name)
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return req, fmt.Errorf("%s:%d: topic '%s' doesn't exist", m.Path, topic.line, topic.Name)
+		}
+		if err != nil {
+			return req, err
+		}
+		if topic.After == "" {
+			req.Topics[topic.Name] = nil
+			continue
+		}
+
+		last := int64(info.Sequence)
+		if topic.After == "latest" && last == 0 {
+			req.Topics[topic.Name] = nil
+			continue
+		}
+		id, err := eventid.Resolve(topic.Name, topic.After, func() (int64, error) { return last, nil })
+		if err != nil {
+			return req, fmt.Errorf("%s:%d: %w", m.Path, topic.line, err)
+		}
+		if id.Sequence > last {
+			return req, fmt.Errorf("%s:%d: topic '%s' has only %d event(s), so there is no event '%s'", m.Path, topic.line, topic.Name, last, topic.After)
+		}
+		after := id.String()
+		req.Topics[topic.Name] = &after
+	}
+	return req, nil
+}
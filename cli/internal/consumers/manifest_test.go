@@ -0,0 +1,175 @@
+package consumers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/labels"
+)
+
+func TestParseManifest(t *testing.T) {
+	m, err := parseManifest("billing.yaml", []byte(`
+callback: https://billing.example.com/events
+description: Bills customers for shipped orders
+labels:
+  team: payments
+topics:
+  - name: orders
+    after: latest
+  - payments
+  - name: refunds
+    after: refunds-12
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Callback != "https://billing.example.com/events" || m.Description != "Bills customers for shipped orders" {
+		t.Errorf("callback, description = %q, %q", m.Callback, m.Description)
+	}
+	if len(m.Labels) != 1 || m.Labels["team"] != "payments" {
+		t.Errorf("labels = %v", m.Labels)
+	}
+	want := []ManifestTopic{{Name: "orders", After: "latest"}, {Name: "payments"}, {Name: "refunds", After: "refunds-12"}}
+	if len(m.Topics) != len(want) {
+		t.Fatalf("topics = %+v, want %+v", m.Topics, want)
+	}
+	for i, topic := range m.Topics {
+		if topic.Name != want[i].Name || topic.After != want[i].After {
+			t.Errorf("topic %d = %+v, want %+v", i, topic, want[i])
+		}
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		manifest string
+		error    string
+	}{
+		{"", "billing.yaml is empty"},
+		{"- a\n- b\n", "billing.yaml:1: expected a mapping"},
+		{"topics: [orders]\n", "billing.yaml:1: the consumer needs a callback"},
+		{"callback: ftp://x/y\ntopics: [orders]\n", "billing.yaml:1: callback 'ftp://x/y' isn't an http or https URL"},
+		{"callback: http://x/y\n", "billing.yaml:1: the consumer needs topics"},
+		{"callback: http://x/y\ntopics: []\n", "billing.yaml:1: the consumer needs topics"},
+		{"callback: http://x/y\ncolour: red\ntopics: [orders]\n", "billing.yaml:2: unknown field 'colour' in the manifest"},
+		{"callback: http://x/y\ncallback: http://x/z\ntopics: [orders]\n", "billing.yaml:2: field 'callback' is given more than once"},
+		{"callback: [http://x/y]\ntopics: [orders]\n", "billing.yaml:1: callback must be a single value"},
+		{"callback: http://x/y\ntopics:\n  - orders\n  - orders\n", "billing.yaml:4: topic 'orders' is already listed on line 3"},
+		{"callback: http://x/y\ntopics:\n  - name: orders\n    from: 3\n", "billing.yaml:4: unknown field 'from' in a topic (expected name, after)"},
+		{"callback: http://x/y\ntopics:\n  - after: latest\n", "billing.yaml:3: the topic needs a name"},
+		{"callback: http://x/y\ntopics:\n  - name: orders\n    after: payments-3\n", "billing.yaml:3: event 'payments-3' isn't an event of topic 'orders'"},
+		{"callback: http://x/y\ntopics:\n  - name: orders\n    after: nonsense\n", "billing.yaml:3: invalid event reference 'nonsense'"},
+		{"callback: http://x/y\nlabels: [a]\ntopics: [orders]\n", "billing.yaml:2: labels must be a mapping"},
+		{"callback: http://x/y\nlabels:\n  Team!: x\ntopics: [orders]\n", "billing.yaml:3: "},
+		{"callback: http://x/y\ntopics: orders\n", "billing.yaml:2: topics must be a list"},
+	}
+	for _, tt := range tests {
+		_, err := parseManifest("billing.yaml", []byte(tt.manifest))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.error) {
+			t.Errorf("parseManifest(%q) error = %v, want one starting %q", tt.manifest, err, tt.error)
+		}
+	}
+}
+
+func TestRequest(t *testing.T) {
+	// orders has 10 events, payments 5, users none, and refunds doesn't exist
+	sequences := map[string]int{"orders": 10, "payments": 5, "users": 0}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/topics/")
+		sequence, ok := sequences[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error":"Topic '%s' not found"}`, name)
+			return
+		}
+		fmt.Fprintf(w, `{"name":%q,"sequence":%d,"schemas":[]}`, name, sequence)
+	}))
+	defer server.Close()
+
+	request := func(topics string) (map[string]*string, error) {
+		m, err := parseManifest("billing.yaml", []byte("callback: http://x/y\ntopics:\n"+topics))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := m.Request(client.NewClient(server.URL))
+		return req.Topics, err
+	}
+
+	topics, err := request("  - name: orders\n    after: latest~2\n  - name: users\n    after: latest\n  - name: payments\n" +
+		"    after: payments-3\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topics["orders"] == nil || *topics["orders"] != "orders-8" {
+		t.Errorf("orders after = %v, want orders-8", topics["orders"])
+	}
+	if after, ok := topics["users"]; !ok || after != nil {
+		t.Errorf("users after = %v, want nil: 'latest' of a topic with no events", after)
+	}
+	if topics["payments"] == nil || *topics["payments"] != "payments-3" {
+		t.Errorf("payments after = %v, want payments-3", topics["payments"])
+	}
+
+	tests := []struct {
+		topics string
+		error  string
+	}{
+		{"  - refunds\n", "billing.yaml:3: topic 'refunds' doesn't exist"},
+		{"  - name: users\n    after: latest~1\n", "billing.yaml:3: topic 'users' has no events"},
+		{"  - name: orders\n    after: latest~10\n", "billing.yaml:3: topic 'orders' has only 10 event(s)"},
+		{"  - name: orders\n    after: 11\n", "billing.yaml:3: topic 'orders' has only 10 event(s), so there is no event '11'"},
+	}
+	for _, tt := range tests {
+		if _, err := request(tt.topics); err == nil || !strings.HasPrefix(err.Error(), tt.error) {
+			t.Errorf("Request(%q) error = %v, want one starting %q", tt.topics, err, tt.error)
+		}
+	}
+}
+
+// FuzzParseManifest checks that parsing never panics, and that every manifest it accepts
+// has an http or https callback and at least one topic, each named once, with valid
+// labels
+func FuzzParseManifest(f *testing.F) {
+	for _, seed := range []string{
+		"callback: https://x/y\ntopics: [orders]\n",
+		"callback: http://x/y\nlabels: {team: payments}\ntopics:\n  - name: orders\n    after: latest~2\n  - payments\n",
+		"callback: http://x/y\ntopics:\n  - name: orders\n    after: orders-3\n",
+		"topics: [a, a]\n",
+		"callback: ~\ntopics: ~\n",
+		"[",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, manifest string) {
+		m, err := parseManifest("fuzz.yaml", []byte(manifest))
+		if err != nil {
+			if !strings.HasPrefix(err.Error(), "fuzz.yaml") && !strings.HasPrefix(err.Error(), "failed to parse fuzz.yaml") {
+				t.Fatalf("error %q doesn't name the manifest", err)
+			}
+			return
+		}
+		if u, err := url.Parse(m.Callback); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			t.Fatalf("accepted callback %q", m.Callback)
+		}
+		if len(m.Topics) == 0 {
+			t.Fatalf("accepted a manifest with no topics")
+		}
+		seen := map[string]bool{}
+		for _, topic := range m.Topics {
+			if topic.Name == "" || seen[topic.Name] {
+				t.Fatalf("accepted topics %+v", m.Topics)
+			}
+			seen[topic.Name] = true
+		}
+		for key, value := range m.Labels {
+			if err := labels.Check(key, value); err != nil {
+				t.Fatalf("accepted label %s=%s: %v", key, value, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package consumers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// PingEventType is the type of the synthetic event sent by a ping with an event
+const PingEventType = "es.ping"
+
+// Ping is the outcome of probing one consumer's callback
+type Ping struct {
+	ID        string   `json:"id"`
+	Callback  string   `json:"callback"`
+	Reachable bool     `json:"reachable"`
+	OK        bool     `json:"ok"` // reachable and answered with a 2xx status
+	Status    int      `json:"httpStatus,omitempty"`
+	LatencyMs float64  `json:"latencyMs,omitempty"`
+	TLS       *TLSInfo `json:"tls,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// TLSInfo describes the TLS connection to an https callback
+type TLSInfo struct {
+	Version  string    `json:"version"`
+	Cipher   string    `json:"cipher"`
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"notAfter"`
+	DaysLeft int       `json:"daysLeft"`
+}
+
+// PingOptions configures probes of consumer callbacks
+type PingOptions struct {
+	Event   bool // send a synthetic event rather than an empty delivery
+	Timeout time.Duration
+	Workers int
+}
+
+// PingAll probes every consumer's callback, up to opts.Workers at a time, returning the
+// outcomes in the order given
+func PingAll(ctx context.Context, consumers []client.Consumer, opts PingOptions) []Ping {
+	pings := make([]Ping, len(consumers))
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				pings[i] = ping(ctx, consumers[i], opts)
+			}
+		}()
+	}
+	for i := range consumers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return pings
+}
+
+// ping POSTs a delivery to a consumer's callback, in the format the event store uses:
+// either with no events or with one event of type PingEventType
+func ping(ctx context.Context, consumer client.Consumer, opts PingOptions) Ping {
+	result := Ping{ID: consumer.ID, Callback: consumer.Callback}
+
+	payload := client.DeliveryPayload{ConsumerID: consumer.ID, Events: []client.Event{}}
+	if opts.Event {
+		payload.Events = append(payload.Events, pingEvent(consumer))
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, consumer.Callback, bytes.NewReader(body))
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid callback URL: %v", err)
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := &http.Client{Timeout: opts.Timeout}
+	started := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	result.Reachable = true
+	result.Status = resp.StatusCode
+	result.LatencyMs = float64(time.Since(started).Microseconds()) / 1000
+	result.OK = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if resp.TLS != nil {
+		result.TLS = tlsInfo(resp.TLS)
+	}
+	if !result.OK {
+		result.Error = fmt.Sprintf("callback returned HTTP %d", resp.StatusCode)
+	}
+	return result
+}
+
+// pingEvent is the synthetic event sent to a consumer, on the first of its topics
+func pingEvent(consumer client.Consumer) client.Event {
+	topic := ""
+	for name := range consumer.Topics {
+		if topic == "" || name < topic {
+			topic = name
+		}
+	}
+	now := time.Now().UTC()
+	id := fmt.Sprintf("ping-%d", now.UnixNano())
+	if topic != "" {
+		id = topic + "-" + id
+	}
+	return client.Event{
+		ID:        id,
+		Type:      PingEventType,
+		Timestamp: now.Format(time.RFC3339Nano),
+		Payload:   map[string]interface{}{"ping": true},
+	}
+}
+
+func tlsInfo(state *tls.ConnectionState) *TLSInfo {
+	info := &TLSInfo{
+		Version: tls.VersionName(state.Version),
+		Cipher:  tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		info.Subject = cert.Subject.CommonName
+		info.Issuer = cert.Issuer.CommonName
+		info.NotAfter = cert.NotAfter
+		info.DaysLeft = int(time.Until(cert.NotAfter).Hours() / 24)
+	}
+	return info
+}
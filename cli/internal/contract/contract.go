@@ -0,0 +1,230 @@
+// Package contract verifies consumers' contracts: examples of the events a consumer
+// expects, checked against the current schemas of their topics, so a producer can't
+// change a schema in a way that breaks a consumer without knowing
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/compose"
+	"github.com/event-store/cli/internal/guard"
+)
+
+// Example is a payload a consumer expects events of a type on a topic to look like
+type Example struct {
+	Contract string
+	Topic    string
+	Type     string
+	Source   string // the file, with the index of the payload if the file has several
+	Payload  map[string]interface{}
+}
+
+// Result is the outcome of checking an example against its topic's schema
+type Result struct {
+	Contract string   `json:"contract"`
+	Topic    string   `json:"topic"`
+	Type     string   `json:"type"`
+	Example  string   `json:"example"`
+	Passed   bool     `json:"passed"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Report is the outcome of verifying contracts
+type Report struct {
+	Results []Result `json:"results"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+}
+
+// Load reads a contract: a directory with a directory per topic, holding the examples of
+// each event type in '<type>.json', a payload or an array of them, or in any number of
+// such files in a '<type>' directory:
+//
+//	billing/
+//	  orders/
+//	    order.placed.json
+//	    order.shipped/
+//	      minimal.json
+//	      with-tracking.json
+//
+// The contract is named after its directory. Files other than *.json are ignored.
+func Load(dir string) ([]Example, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("contract %s is not a directory (expected a directory per topic)", dir)
+	}
+	name := filepath.Base(filepath.Clean(dir))
+
+	topics, err := readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var examples []Example
+	for _, topic := range topics {
+		if !topic.IsDir() {
+			continue
+		}
+		topicDir := filepath.Join(dir, topic.Name())
+		entries, err := readDir(topicDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(topicDir, entry.Name())
+			if !entry.IsDir() {
+				if eventType, ok := strings.CutSuffix(entry.Name(), ".json"); ok {
+					found, err := loadFile(path)
+					if err != nil {
+						return nil, err
+					}
+					examples = append(examples, withSource(found, name, topic.Name(), eventType)...)
+				}
+				continue
+			}
+			files, err := readDir(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, file := range files {
+				if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+					continue
+				}
+				found, err := loadFile(filepath.Join(path, file.Name()))
+				if err != nil {
+					return nil, err
+				}
+				examples = append(examples, withSource(found, name, topic.Name(), entry.Name())...)
+			}
+		}
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("contract %s has no examples (expected <topic>/<event type>.json files)", dir)
+	}
+	return examples, nil
+}
+
+// readDir lists a directory's entries in name order, without hidden ones
+func readDir(dir string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	visible := entries[:0]
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") {
+			visible = append(visible, entry)
+		}
+	}
+	return visible, nil
+}
+
+// loadFile reads the example payloads of a file: an object, or an array of objects
+func loadFile(path string) ([]Example, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid example %s: %w", path, err)
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return []Example{{Source: path, Payload: value}}, nil
+	case []interface{}:
+		examples := make([]Example, len(value))
+		for i, item := range value {
+			payload, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid example %s: item %d is not a JSON object", path, i+1)
+			}
+			examples[i] = Example{Source: fmt.Sprintf("%s[%d]", path, i), Payload: payload}
+		}
+		return examples, nil
+	}
+	return nil, fmt.Errorf("invalid example %s: expected a JSON object or an array of them", path)
+}
+
+func withSource(examples []Example, contract, topic, eventType string) []Example {
+	for i := range examples {
+		examples[i].Contract, examples[i].Topic, examples[i].Type = contract, topic, eventType
+	}
+	return examples
+}
+
+// Verify checks each example against the current schema of its event type. An example
+// fails if its topic doesn't exist or has no schema for its type, if it has fields the
+// schema doesn't declare, such as fields the producer has removed, or if its values
+// don't match the schema. Fields the example leaves out are never a problem, even
+// required ones: they are fields the consumer doesn't rely on.
+func Verify(apiClient *client.Client, examples []Example) (*Report, error) {
+	report := &Report{Results: make([]Result, 0, len(examples))}
+	topics := map[string]*client.Topic{} // nil for topics that don't exist
+	for _, example := range examples {
+		topic, ok := topics[example.Topic]
+		if !ok {
+			var err error
+			topic, err = apiClient.GetTopic(example.Topic)
+			var apiErr *client.APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+				topic, err = nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			topics[example.Topic] = topic
+		}
+
+		result := Result{
+			Contract: example.Contract,
+			Topic:    example.Topic,
+			Type:     example.Type,
+			Example:  example.Source,
+			Problems: check(topic, example),
+		}
+		result.Passed = len(result.Problems) == 0
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// check returns the problems with an example of a topic's events
+func check(topic *client.Topic, example Example) []string {
+	if topic == nil {
+		return []string{fmt.Sprintf("topic '%s' doesn't exist", example.Topic)}
+	}
+	var schema *client.Schema
+	for i := range topic.Schemas {
+		if topic.Schemas[i].EventType == example.Type {
+			schema = &topic.Schemas[i]
+		}
+	}
+	if schema == nil {
+		return []string{fmt.Sprintf("topic '%s' has no schema for type '%s'", example.Topic, example.Type)}
+	}
+
+	var problems []string
+	for _, field := range guard.UnknownFields("", example.Payload, schema.Properties) {
+		problems = append(problems, fmt.Sprintf("%s: not in the schema", field))
+	}
+	problems = append(problems, compose.CheckPartial(*schema, example.Payload)...)
+	sort.Strings(problems)
+	return problems
+}
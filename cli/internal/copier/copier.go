@@ -0,0 +1,189 @@
+package copier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/transform"
+)
+
+// Options configures a copy of events from one topic to another
+type Options struct {
+	Source      *client.Client
+	Destination *client.Client
+	SourceTopic string
+	DestTopic   string
+	After       int64 // copy events after this sequence
+	Through     int64 // up to and including this sequence
+	Filters     []*filter.Filter
+	TypeMap     map[string]string     // source event type -> destination event type
+	DropFields  []string              // payload fields (dotted paths) to remove
+	Transform   transform.Transformer // applied to payloads last; may drop events
+	BatchSize   int                   // events per publish request
+	DryRun      bool
+	// Checkpoint, if set, is called with the last source event copied or skipped after
+	// each publish, so a long copy can record how far it got
+	Checkpoint func(lastEventID string) error
+}
+
+// Result summarises a copy
+type Result struct {
+	SourceTopic  string  `json:"sourceTopic"`
+	DestTopic    string  `json:"destTopic"`
+	DestServer   string  `json:"destServer,omitempty"`
+	Read         int     `json:"read"`
+	Copied       int     `json:"copied"`
+	Skipped      int     `json:"skipped"`
+	FirstEventID string  `json:"firstEventId,omitempty"` // first source event read
+	LastEventID  string  `json:"lastEventId,omitempty"`  // last source event copied or skipped
+	Seconds      float64 `json:"durationSeconds"`
+	DryRun       bool    `json:"dryRun"`
+}
+
+// ParseTypeMap parses 'old=new' event type renames
+func ParseTypeMap(mappings []string) (map[string]string, error) {
+	typeMap := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		from, to, ok := strings.Cut(mapping, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid type rename '%s' (expected 'old=new')", mapping)
+		}
+		typeMap[from] = to
+	}
+	return typeMap, nil
+}
+
+// Copy reads the source topic's events in order, skips those not matching the filters or
+// dropped by the transform, converts the rest and publishes them to the destination
+// topic in batches, calling progress with the number of events read. It stops at the
+// first failed transform or publish or when ctx is done; the result records how far it
+// got, so the copy can be resumed after LastEventID.
+func Copy(ctx context.Context, opts Options, progress func(read int)) (*Result, error) {
+	result := &Result{SourceTopic: opts.SourceTopic, DestTopic: opts.DestTopic, DryRun: opts.DryRun}
+	started := time.Now()
+	defer func() { result.Seconds = time.Since(started).Seconds() }()
+
+	batchSize := opts.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	var batch []client.EventPublishRequest
+	pending := "" // last source event read, done once batch is published
+	flush := func() error {
+		if len(batch) > 0 && !opts.DryRun {
+			if _, err := opts.Destination.PublishEvents(batch); err != nil {
+				return fmt.Errorf("publishing to '%s' failed: %w", opts.DestTopic, err)
+			}
+		}
+		result.Copied += len(batch)
+		published := len(batch) > 0
+		if pending != "" {
+			result.LastEventID = pending
+		}
+		batch = batch[:0]
+		pending = ""
+		if published && opts.Checkpoint != nil {
+			return opts.Checkpoint(result.LastEventID)
+		}
+		return nil
+	}
+
+	since := ""
+	if opts.After > 0 {
+		since = eventid.ID{Topic: opts.SourceTopic, Sequence: opts.After}.String()
+	}
+	err := opts.Source.ScanEvents(opts.SourceTopic, since, func(events []client.Event) (bool, error) {
+		for _, event := range events {
+			if id, err := eventid.Parse(event.ID); err == nil && opts.Through > 0 && id.Sequence > opts.Through {
+				return false, nil
+			}
+			if result.FirstEventID == "" {
+				result.FirstEventID = event.ID
+			}
+			result.Read++
+
+			pending = event.ID
+			request, keep := client.EventPublishRequest{}, filter.MatchAll(opts.Filters, event)
+			if keep {
+				var err error
+				if request, keep, err = convert(event, opts); err != nil {
+					return false, fmt.Errorf("%s: %w", event.ID, err)
+				}
+			}
+			if keep {
+				batch = append(batch, request)
+			} else {
+				result.Skipped++
+			}
+			if len(batch) == 0 || len(batch) >= batchSize {
+				if err := flush(); err != nil {
+					return false, err
+				}
+			}
+		}
+		if progress != nil {
+			progress(len(events))
+		}
+		return ctx.Err() == nil, nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return result, err
+	}
+	return result, ctx.Err()
+}
+
+// convert turns a source event into a publish request for the destination topic,
+// returning false when the transform drops it
+func convert(event client.Event, opts Options) (client.EventPublishRequest, bool, error) {
+	eventType := event.Type
+	if renamed, ok := opts.TypeMap[eventType]; ok {
+		eventType = renamed
+	}
+
+	payload := event.Payload
+	if len(opts.DropFields) > 0 {
+		payload = copyMap(payload)
+		for _, field := range opts.DropFields {
+			dropField(payload, strings.Split(field, "."))
+		}
+	}
+	if opts.Transform != nil {
+		var keep bool
+		var err error
+		if payload, keep, err = opts.Transform.Transform(payload); err != nil || !keep {
+			return client.EventPublishRequest{}, false, err
+		}
+	}
+	return client.EventPublishRequest{Topic: opts.DestTopic, Type: eventType, Payload: payload}, true, nil
+}
+
+func dropField(payload map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(payload, path[0])
+		return
+	}
+	if nested, ok := payload[path[0]].(map[string]interface{}); ok {
+		dropField(nested, path[1:])
+	}
+}
+
+// copyMap deep-copies nested objects so dropping fields leaves the source event intact
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = copyMap(nested)
+		}
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,285 @@
+// Package drift compares the topics and consumers of two event stores, such as staging
+// and prod, and reports where they differ
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Kinds of differences
+const (
+	KindTopic    = "topic"
+	KindSchema   = "schema"
+	KindConsumer = "consumer"
+)
+
+// Difference is one way two event stores differ
+type Difference struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"` // the topic, topic/event type, or consumer callback path
+	Detail string `json:"detail"`
+}
+
+// Store is what is compared of an event store
+type Store struct {
+	Name      string // e.g. the context, used in the differences' details
+	Topics    []client.Topic
+	Consumers []client.Consumer
+}
+
+// Report is the outcome of comparing two event stores
+type Report struct {
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Differences []Difference `json:"differences"`
+}
+
+// Compare returns the differences between two event stores: topics, event types and
+// consumers only in one of them, schemas whose fields differ, and consumers subscribed
+// to different topics. Consumer IDs are generated by each server, so consumers are
+// matched by the path of their callback URLs, whose hosts usually differ between
+// environments.
+func Compare(a, b Store) *Report {
+	report := &Report{From: a.Name, To: b.Name, Differences: []Difference{}}
+	report.Differences = append(report.Differences, compareTopics(a, b)...)
+	report.Differences = append(report.Differences, compareConsumers(a, b)...)
+	return report
+}
+
+func compareTopics(a, b Store) []Difference {
+	topicsA, topicsB := topicsByName(a.Topics), topicsByName(b.Topics)
+	var diffs, schemaDiffs []Difference
+	for _, name := range unionKeys(topicsA, topicsB) {
+		topicA, inA := topicsA[name]
+		topicB, inB := topicsB[name]
+		switch {
+		case !inB:
+			diffs = append(diffs, Difference{KindTopic, name, "only in " + a.Name})
+			continue
+		case !inA:
+			diffs = append(diffs, Difference{KindTopic, name, "only in " + b.Name})
+			continue
+		}
+		if topicA.Partitions != topicB.Partitions {
+			diffs = append(diffs, Difference{KindTopic, name, fmt.Sprintf("%d partition(s) in %s, %d in %s", topicA.Partitions, a.Name, topicB.Partitions, b.Name)})
+		}
+
+		schemasA, schemasB := schemasByType(topicA.Schemas), schemasByType(topicB.Schemas)
+		for _, eventType := range unionKeys(schemasA, schemasB) {
+			schemaA, inA := schemasA[eventType]
+			schemaB, inB := schemasB[eventType]
+			schemaName := name + "/" + eventType
+			switch {
+			case !inB:
+				schemaDiffs = append(schemaDiffs, Difference{KindSchema, schemaName, "only in " + a.Name})
+			case !inA:
+				schemaDiffs = append(schemaDiffs, Difference{KindSchema, schemaName, "only in " + b.Name})
+			default:
+				for _, detail := range compareFields("", schemaA.Properties, schemaB.Properties, schemaA.Required, schemaB.Required, a.Name, b.Name) {
+					schemaDiffs = append(schemaDiffs, Difference{KindSchema, schemaName, detail})
+				}
+			}
+		}
+	}
+	return append(diffs, schemaDiffs...)
+}
+
+// compareFields describes how two schemas' properties differ, recursing into the
+// properties of objects; prefix is the path of the object compared, e.g. "customer."
+func compareFields(prefix string, propsA, propsB map[string]interface{}, requiredA, requiredB []string, nameA, nameB string) []string {
+	var details []string
+	for _, field := range unionKeys(propsA, propsB) {
+		path := prefix + field
+		propA, inA := propsA[field]
+		propB, inB := propsB[field]
+		switch {
+		case !inB:
+			details = append(details, fmt.Sprintf("field '%s' only in %s", path, nameA))
+			continue
+		case !inA:
+			details = append(details, fmt.Sprintf("field '%s' only in %s", path, nameB))
+			continue
+		}
+
+		objectA, _ := propA.(map[string]interface{})
+		objectB, _ := propB.(map[string]interface{})
+		typeA, typeB := fieldType(objectA), fieldType(objectB)
+		switch {
+		case typeA != typeB:
+			details = append(details, fmt.Sprintf("field '%s' is %s in %s, %s in %s", path, typeA, nameA, typeB, nameB))
+		case typeA == "object" && (objectA["properties"] != nil || objectB["properties"] != nil):
+			nestedA, _ := objectA["properties"].(map[string]interface{})
+			nestedB, _ := objectB["properties"].(map[string]interface{})
+			details = append(details, compareFields(path+".", nestedA, nestedB, stringList(objectA["required"]), stringList(objectB["required"]), nameA, nameB)...)
+		case canonical(propA) != canonical(propB):
+			details = append(details, fmt.Sprintf("field '%s' is defined differently: %s in %s, %s in %s", path, canonical(propA), nameA, canonical(propB), nameB))
+		}
+	}
+
+	onlyA, onlyB := difference(requiredA, requiredB), difference(requiredB, requiredA)
+	for _, field := range onlyA {
+		details = append(details, fmt.Sprintf("field '%s%s' is required in %s only", prefix, field, nameA))
+	}
+	for _, field := range onlyB {
+		details = append(details, fmt.Sprintf("field '%s%s' is required in %s only", prefix, field, nameB))
+	}
+	return details
+}
+
+// fieldType describes a JSON schema property's type, with its format if it has one,
+// e.g. string (date-time)
+func fieldType(property map[string]interface{}) string {
+	var fieldType string
+	switch t := property["type"].(type) {
+	case nil:
+		fieldType = "untyped"
+	case string:
+		fieldType = t
+	default:
+		fieldType = canonical(t) // e.g. ["string","null"]
+	}
+	if format, ok := property["format"].(string); ok {
+		fieldType += " (" + format + ")"
+	}
+	return fieldType
+}
+
+func compareConsumers(a, b Store) []Difference {
+	consumersA, consumersB := consumersByPath(a.Consumers), consumersByPath(b.Consumers)
+	var diffs []Difference
+	for _, path := range unionKeys(consumersA, consumersB) {
+		listA, listB := consumersA[path], consumersB[path]
+		switch {
+		case len(listB) == 0:
+			diffs = append(diffs, Difference{KindConsumer, path, "only in " + a.Name})
+			continue
+		case len(listA) == 0:
+			diffs = append(diffs, Difference{KindConsumer, path, "only in " + b.Name})
+			continue
+		case len(listA) != len(listB):
+			diffs = append(diffs, Difference{KindConsumer, path, fmt.Sprintf("%d consumer(s) in %s, %d in %s", len(listA), a.Name, len(listB), b.Name)})
+		}
+
+		topicsA, topicsB := subscribedTopics(listA), subscribedTopics(listB)
+		for _, topic := range difference(topicsA, topicsB) {
+			diffs = append(diffs, Difference{KindConsumer, path, fmt.Sprintf("subscribed to '%s' in %s only", topic, a.Name)})
+		}
+		for _, topic := range difference(topicsB, topicsA) {
+			diffs = append(diffs, Difference{KindConsumer, path, fmt.Sprintf("subscribed to '%s' in %s only", topic, b.Name)})
+		}
+	}
+	return diffs
+}
+
+// callbackPath is the part of a callback URL that identifies a consumer across
+// environments: its path and query, without the scheme and host
+func callbackPath(callback string) string {
+	u, err := url.Parse(callback)
+	if err != nil || u.Host == "" {
+		return callback
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+func topicsByName(topics []client.Topic) map[string]client.Topic {
+	byName := make(map[string]client.Topic, len(topics))
+	for _, topic := range topics {
+		byName[topic.Name] = topic
+	}
+	return byName
+}
+
+func schemasByType(schemas []client.Schema) map[string]client.Schema {
+	byType := make(map[string]client.Schema, len(schemas))
+	for _, schema := range schemas {
+		byType[schema.EventType] = schema
+	}
+	return byType
+}
+
+func consumersByPath(consumers []client.Consumer) map[string][]client.Consumer {
+	byPath := map[string][]client.Consumer{}
+	for _, consumer := range consumers {
+		path := callbackPath(consumer.Callback)
+		byPath[path] = append(byPath[path], consumer)
+	}
+	return byPath
+}
+
+// subscribedTopics returns the topics any of the consumers are subscribed to, sorted
+func subscribedTopics(consumers []client.Consumer) []string {
+	set := map[string]bool{}
+	for _, consumer := range consumers {
+		for topic := range consumer.Topics {
+			set[topic] = true
+		}
+	}
+	return unionKeys(set, nil)
+}
+
+// unionKeys returns the keys of two maps, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		set[key] = true
+	}
+	for key := range b {
+		set[key] = true
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// difference returns the strings of a that aren't in b, sorted
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	var only []string
+	for _, s := range a {
+		if !inB[s] {
+			only = append(only, s)
+		}
+	}
+	sort.Strings(only)
+	return only
+}
+
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// canonical returns JSON for a value with object keys sorted, for comparing schema
+// definitions
+func canonical(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,105 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around each change
+const context = 3
+
+// Diff compares two texts line by line and returns the differences in unified diff
+// format, without the file headers: hunks headed "@@ -l,n +l,n @@" with lines starting
+// with "-" for those removed, "+" for those added and " " for unchanged lines around
+// them. Texts that are the same have no differences.
+func Diff(before, after string) []string {
+	a, b := splitLines(before), splitLines(after)
+
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+		i, j int // the line's index in a and b, or where it would be
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lengths[i+1][j] >= lengths[i][j+1]):
+			lines = append(lines, line{'-', a[i], i, j})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	var result []string
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+		// A hunk runs from the context before a change to the context after the last
+		// change that isn't more than twice the context from the one before it
+		first := max(start-context, 0)
+		end := start
+		for k := start; k < len(lines) && k <= end+2*context; k++ {
+			if lines[k].op != ' ' {
+				end = k
+			}
+		}
+		last := min(end+context, len(lines)-1)
+
+		removed, added := 0, 0
+		var hunk []string
+		for _, l := range lines[first : last+1] {
+			if l.op != '+' {
+				removed++
+			}
+			if l.op != '-' {
+				added++
+			}
+			hunk = append(hunk, string(l.op)+l.text)
+		}
+		result = append(result, fmt.Sprintf("@@ -%s +%s @@", hunkRange(lines[first].i, removed), hunkRange(lines[first].j, added)))
+		result = append(result, hunk...)
+		start = last + 1
+	}
+	return result
+}
+
+// hunkRange formats the lines a hunk covers in one of the texts: its first line,
+// numbered from 1, and its number of lines
+func hunkRange(index, count int) string {
+	if count == 0 {
+		// An empty range is given as the line before it
+		return fmt.Sprintf("%d,0", index)
+	}
+	return fmt.Sprintf("%d,%d", index+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,54 @@
+// Package editor edits text in the user's editor, as set by $VISUAL or $EDITOR, and
+// compares what was edited with what it started as.
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command returns the editor to run: $VISUAL, then $EDITOR, then vi
+func Command() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// Edit writes content to a temporary file named after pattern, as for os.CreateTemp, so
+// editors can tell its format by the extension, and opens it in the editor on the
+// terminal. Once the editor exits the file is read back and removed.
+func Edit(content []byte, pattern string) ([]byte, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file to edit: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write file to edit: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write file to edit: %w", err)
+	}
+
+	// The editor may be given with arguments, e.g. "code --wait"
+	args := strings.Fields(Command())
+	editor := exec.Command(args[0], append(args[1:], file.Name())...)
+	editor.Stdin = os.Stdin
+	editor.Stdout = os.Stdout
+	editor.Stderr = os.Stderr
+	if err := editor.Run(); err != nil {
+		return nil, fmt.Errorf("editor '%s' failed: %w", args[0], err)
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return edited, nil
+}
@@ -0,0 +1,271 @@
+// Package elasticsearch is a small client for the Elasticsearch and OpenSearch REST APIs,
+// covering what 'es sink elasticsearch' needs: bulk indexing, index templates and
+// reading and writing single documents. Requests the cluster pushes back on, with HTTP
+// 429 or a 502, 503 or 504, are retried with exponential backoff.
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config configures a client
+type Config struct {
+	URL        string // e.g. https://localhost:9200
+	Username   string // for basic authentication
+	Password   string
+	APIKey     string // base64 API key, sent instead of basic authentication
+	TLS        *tls.Config
+	MaxRetries int // retries of a request pushed back on
+	Timeout    time.Duration
+}
+
+// Client talks to a cluster
+type Client struct {
+	config Config
+	http   *http.Client
+	// backoff is the wait before the first retry, doubled for each retry after
+	backoff time.Duration
+}
+
+// New creates a client
+func New(config Config) *Client {
+	if config.Timeout == 0 {
+		config.Timeout = time.Minute
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.TLS != nil {
+		transport.TLSClientConfig = config.TLS
+	}
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	return &Client{config: config, http: &http.Client{Timeout: config.Timeout, Transport: transport}, backoff: 500 * time.Millisecond}
+}
+
+// Error is an error response from the cluster
+type Error struct {
+	StatusCode int
+	Type       string
+	Reason     string
+}
+
+func (e *Error) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("elasticsearch: HTTP %d: %s: %s", e.StatusCode, e.Type, e.Reason)
+	}
+	if e.Reason != "" {
+		return fmt.Sprintf("elasticsearch: HTTP %d: %s", e.StatusCode, e.Reason)
+	}
+	return fmt.Sprintf("elasticsearch: HTTP %d", e.StatusCode)
+}
+
+// errorBody is the error of a response or bulk item
+type errorBody struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// BulkItem is an action of a bulk request: the document to index with an ID
+type BulkItem struct {
+	Index    string
+	ID       string
+	Document interface{}
+}
+
+// Bulk indexes documents, replacing documents with the same IDs. Items the cluster
+// rejects for backpressure are retried; an item that fails otherwise is an error.
+func (c *Client) Bulk(ctx context.Context, items []BulkItem) error {
+	pending := items
+	for attempt := 0; ; attempt++ {
+		var body bytes.Buffer
+		encoder := json.NewEncoder(&body)
+		encoder.SetEscapeHTML(false)
+		for _, item := range pending {
+			action := map[string]interface{}{"index": map[string]string{"_index": item.Index, "_id": item.ID}}
+			if err := encoder.Encode(action); err != nil {
+				return err
+			}
+			if err := encoder.Encode(item.Document); err != nil {
+				return err
+			}
+		}
+
+		respBody, err := c.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body.Bytes())
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Errors bool `json:"errors"`
+			Items  []map[string]struct {
+				Status int        `json:"status"`
+				Error  *errorBody `json:"error"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return fmt.Errorf("elasticsearch: invalid bulk response: %w", err)
+		}
+		if !resp.Errors {
+			return nil
+		}
+
+		var retry []BulkItem
+		for i, result := range resp.Items {
+			for _, outcome := range result {
+				if outcome.Error == nil || i >= len(pending) {
+					continue
+				}
+				if outcome.Status == http.StatusTooManyRequests {
+					retry = append(retry, pending[i])
+					continue
+				}
+				return fmt.Errorf("elasticsearch: failed to index %s: %s: %s", pending[i].ID, outcome.Error.Type, outcome.Error.Reason)
+			}
+		}
+		if len(retry) == 0 {
+			return nil
+		}
+		if attempt >= c.config.MaxRetries {
+			return fmt.Errorf("elasticsearch: %d document(s) still rejected after %d retries (HTTP 429)", len(retry), attempt)
+		}
+		if err := c.wait(ctx, attempt, 0); err != nil {
+			return err
+		}
+		pending = retry
+	}
+}
+
+// PutIndexTemplate creates or replaces a composable index template
+func (c *Client) PutIndexTemplate(ctx context.Context, name string, template interface{}) error {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPut, "/_index_template/"+url.PathEscape(name), "application/json", data)
+	return err
+}
+
+// GetDocument reads the source of a document into v, reporting false if it doesn't
+// exist
+func (c *Client) GetDocument(ctx context.Context, index, id string, v interface{}) (bool, error) {
+	respBody, err := c.do(ctx, http.MethodGet, "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), "", nil)
+	if e, ok := err.(*Error); ok && e.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var resp struct {
+		Found  bool            `json:"found"`
+		Source json.RawMessage `json:"_source"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return false, fmt.Errorf("elasticsearch: invalid document response: %w", err)
+	}
+	if !resp.Found {
+		return false, nil
+	}
+	return true, json.Unmarshal(resp.Source, v)
+}
+
+// PutDocument creates or replaces a document
+func (c *Client) PutDocument(ctx context.Context, index, id string, document interface{}) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPut, "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), "application/json", data)
+	return err
+}
+
+// DeleteDocument deletes a document, if it exists
+func (c *Client) DeleteDocument(ctx context.Context, index, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), "", nil)
+	if e, ok := err.(*Error); ok && e.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// do sends a request, retrying it while the cluster pushes back, and returns the body
+// of a successful response
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.config.URL+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if c.config.APIKey != "" {
+			req.Header.Set("Authorization", "ApiKey "+c.config.APIKey)
+		} else if c.config.Username != "" {
+			req.SetBasicAuth(c.config.Username, c.config.Password)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("elasticsearch: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("elasticsearch: %w", err)
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return respBody, nil
+		}
+
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			if attempt < c.config.MaxRetries {
+				retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+				if err := c.wait(ctx, attempt, time.Duration(retryAfter)*time.Second); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		return nil, responseError(resp.StatusCode, respBody)
+	}
+}
+
+// wait sleeps before a retry: the server's Retry-After if given, else the backoff
+// doubled for each attempt, up to 30s
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = min(c.backoff<<attempt, 30*time.Second)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+func responseError(status int, body []byte) error {
+	var resp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	e := &Error{StatusCode: status}
+	if json.Unmarshal(body, &resp) == nil && len(resp.Error) > 0 {
+		var detail errorBody
+		if json.Unmarshal(resp.Error, &detail) == nil && detail.Type != "" {
+			e.Type, e.Reason = detail.Type, detail.Reason
+		} else {
+			// Some errors are a plain string
+			json.Unmarshal(resp.Error, &e.Reason)
+		}
+	}
+	return e
+}
@@ -0,0 +1,232 @@
+// Package envelope encrypts payload fields of events before they are published, and
+// decrypts them when they are read, so sensitive events can be kept on an event store
+// that isn't trusted with them. Each field is encrypted on its own with AES-GCM and
+// replaced by a string naming the key it was encrypted with, so events encrypted with
+// an old key can still be read once a new one is in use.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// prefix starts the value of an encrypted field: "enc:v1:<key ID>:<base64 of the nonce
+// followed by the ciphertext>"
+const prefix = "enc:v1:"
+
+// MetadataKey is the metadata entry recording how an event's payload was encrypted, for
+// servers that keep metadata: {"keyId": "...", "fields": ["payload.email", ...]}
+const MetadataKey = "encryption"
+
+// KeyRing holds the keys fields are encrypted and decrypted with, by ID
+type KeyRing struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// keyFile is the JSON of a key file: the ID of the key to encrypt with, and every key
+// by ID, base64-encoded, 16, 24 or 32 bytes for AES-128, AES-192 or AES-256
+type keyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LoadKeyRing reads a key file
+func LoadKeyRing(path string) (*KeyRing, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+	if len(file.Keys) == 0 {
+		return nil, fmt.Errorf("key file '%s' has no keys", path)
+	}
+
+	ring := &KeyRing{current: file.Current, keys: make(map[string]cipher.AEAD, len(file.Keys))}
+	for id, encoded := range file.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key ID '%s' in key file (must be non-empty, without ':')", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key '%s' is not base64: %w", id, err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key '%s' is not an AES key: %w", id, err)
+		}
+		if ring.keys[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	if ring.current == "" && len(ring.keys) == 1 {
+		for id := range ring.keys {
+			ring.current = id
+		}
+	}
+	return ring, nil
+}
+
+// CanEncrypt checks the key ring has a current key to encrypt with
+func (r *KeyRing) CanEncrypt() error {
+	if r.current == "" {
+		return fmt.Errorf("the key file must name the key to encrypt with as 'current'")
+	}
+	if _, ok := r.keys[r.current]; !ok {
+		return fmt.Errorf("the key file's current key '%s' is not one of its keys", r.current)
+	}
+	return nil
+}
+
+// CheckFields checks the fields to encrypt: dotted paths under payload
+func CheckFields(fields []string) error {
+	for _, field := range fields {
+		if path, ok := strings.CutPrefix(field, "payload."); !ok || path == "" {
+			return fmt.Errorf("invalid field '%s' (expected 'payload.<path>')", field)
+		}
+	}
+	return nil
+}
+
+// Encrypt encrypts fields of an event's payload with the current key, every top-level
+// field if fields is empty, and returns those it encrypted, sorted. Fields the payload
+// doesn't have, and those already encrypted, are left alone.
+func (r *KeyRing) Encrypt(event *client.EventPublishRequest, fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		for name := range event.Payload {
+			fields = append(fields, "payload."+name)
+		}
+	}
+	aead := r.keys[r.current]
+	var encrypted []string
+	for _, field := range fields {
+		parent, name := lookup(event.Payload, strings.TrimPrefix(field, "payload."))
+		value, ok := parent[name]
+		if !ok || isEncrypted(value) {
+			continue
+		}
+		plaintext, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		sealed := aead.Seal(nonce, nonce, plaintext, additionalData(event.Type, field))
+		parent[name] = prefix + r.current + ":" + base64.StdEncoding.EncodeToString(sealed)
+		encrypted = append(encrypted, field)
+	}
+	sort.Strings(encrypted)
+	return encrypted, nil
+}
+
+// Decrypt decrypts the encrypted fields of an event's payload in place, returning those
+// it decrypted. A field that can't be decrypted, such as with a key the ring doesn't
+// have, is left encrypted and reported in the error, after the others are decrypted.
+func (r *KeyRing) Decrypt(event *client.Event) ([]string, error) {
+	var decrypted, failed []string
+	var firstErr error
+	r.decrypt(event.Type, "payload", event.Payload, func(field string, err error) {
+		if err == nil {
+			decrypted = append(decrypted, field)
+			return
+		}
+		failed = append(failed, field)
+		if firstErr == nil {
+			firstErr = err
+		}
+	})
+	sort.Strings(decrypted)
+	if firstErr != nil {
+		sort.Strings(failed)
+		return decrypted, fmt.Errorf("can't decrypt %s of event '%s': %w", strings.Join(failed, ", "), event.ID, firstErr)
+	}
+	return decrypted, nil
+}
+
+// decrypt decrypts the encrypted fields of an object under path, calling done for each
+func (r *KeyRing) decrypt(eventType, path string, object map[string]interface{}, done func(field string, err error)) {
+	for name, value := range object {
+		field := path + "." + name
+		switch value := value.(type) {
+		case map[string]interface{}:
+			r.decrypt(eventType, field, value, done)
+		case string:
+			if !strings.HasPrefix(value, prefix) {
+				continue
+			}
+			plain, err := r.open(eventType, field, value)
+			if err == nil {
+				object[name] = plain
+			}
+			done(field, err)
+		}
+	}
+}
+
+// open decrypts the value of an encrypted field
+func (r *KeyRing) open(eventType, field, value string) (interface{}, error) {
+	id, sealed, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+	aead, ok := r.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("no key '%s' in the key file", id)
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData(eventType, field))
+	if err != nil {
+		return nil, fmt.Errorf("key '%s' doesn't decrypt it, or it was altered", id)
+	}
+	var plain interface{}
+	if err := json.Unmarshal(plaintext, &plain); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+// CurrentKey returns the ID of the key fields are encrypted with
+func (r *KeyRing) CurrentKey() string {
+	return r.current
+}
+
+// additionalData binds an encrypted value to its event type and field, so it can't be
+// moved to another field or event type and still decrypt
+func additionalData(eventType, field string) []byte {
+	return []byte(eventType + "\x00" + field)
+}
+
+// lookup returns the object holding a dotted path of a payload and the path's last name;
+// the object is nil if the path's parents aren't objects
+func lookup(payload map[string]interface{}, path string) (map[string]interface{}, string) {
+	names := strings.Split(path, ".")
+	object := payload
+	for _, name := range names[:len(names)-1] {
+		object, _ = object[name].(map[string]interface{})
+	}
+	return object, names[len(names)-1]
+}
+
+// isEncrypted reports whether a field's value is already encrypted
+func isEncrypted(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, prefix)
+}
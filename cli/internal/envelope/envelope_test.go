@@ -0,0 +1,252 @@
+package envelope
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// testKey is the AES-256 key 00 01 02 ... 1f
+const testKey = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+
+// knownEmail is "alice@example.com" as payload.email of a user.created event, encrypted
+// with testKey as k1 and the nonce a0 a1 ... ab. Events stored in this format must stay
+// readable.
+const knownEmail = "enc:v1:k1:oKGio6SlpqeoqaqrxHkQRCauQtoaBOqjax/uvR/Be57uAEOVz8HptGktrbT/yZw="
+
+func writeKeyFile(t *testing.T, current string, keys map[string]string) *KeyRing {
+	data, err := json.Marshal(keyFile{Current: current, Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ring, err := LoadKeyRing(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ring
+}
+
+// otherKey returns an AES-256 key other than testKey
+func otherKey(seed byte) string {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = seed + byte(i*7)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// roundTrip encrypts fields of a payload and returns the event as read back
+func roundTrip(t *testing.T, ring *KeyRing, payload map[string]interface{}, fields []string) (*client.Event, []string) {
+	request := &client.EventPublishRequest{Type: "user.created", Payload: payload}
+	encrypted, err := ring.Encrypt(request, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Through JSON, as the event is stored and read
+	data, _ := json.Marshal(request.Payload)
+	event := &client.Event{ID: "users-1", Type: request.Type}
+	if err := json.Unmarshal(data, &event.Payload); err != nil {
+		t.Fatal(err)
+	}
+	return event, encrypted
+}
+
+func TestRoundTrip(t *testing.T) {
+	ring := writeKeyFile(t, "", map[string]string{"k1": testKey})
+	payload := func() map[string]interface{} {
+		return map[string]interface{}{
+			"email":   "alice@example.com",
+			"age":     float64(42),
+			"vip":     true,
+			"nothing": nil,
+			"tags":    []interface{}{"a", "b"},
+			"address": map[string]interface{}{"city": "Cape Town", "zip": "8001"},
+		}
+	}
+
+	tests := []struct {
+		fields []string
+		want   []string
+	}{
+		{[]string{"payload.email", "payload.address.city", "payload.missing", "payload.address.missing.deeper"}, []string{"payload.address.city", "payload.email"}},
+		{nil, []string{"payload.address", "payload.age", "payload.email", "payload.nothing", "payload.tags", "payload.vip"}},
+	}
+	for _, test := range tests {
+		event, encrypted := roundTrip(t, ring, payload(), test.fields)
+		if !reflect.DeepEqual(encrypted, test.want) {
+			t.Errorf("Encrypt(%v) encrypted %v, want %v", test.fields, encrypted, test.want)
+		}
+		for _, field := range encrypted {
+			parent, name := lookup(event.Payload, strings.TrimPrefix(field, "payload."))
+			if value, _ := parent[name].(string); !strings.HasPrefix(value, "enc:v1:k1:") {
+				t.Errorf("Encrypt(%v): %s = %v, want it encrypted", test.fields, field, parent[name])
+			}
+		}
+		decrypted, err := ring.Decrypt(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decrypted, test.want) || !reflect.DeepEqual(event.Payload, payload()) {
+			t.Errorf("Decrypt(Encrypt(%v)) = %v, %v", test.fields, decrypted, event.Payload)
+		}
+	}
+}
+
+func TestEncryptLeavesEncryptedFields(t *testing.T) {
+	ring := writeKeyFile(t, "", map[string]string{"k1": testKey})
+	request := &client.EventPublishRequest{Type: "user.created", Payload: map[string]interface{}{"email": knownEmail}}
+	encrypted, err := ring.Encrypt(request, nil)
+	if err != nil || len(encrypted) != 0 || request.Payload["email"] != knownEmail {
+		t.Errorf("Encrypt of an encrypted field = %v, %v, payload %v", encrypted, err, request.Payload)
+	}
+}
+
+func TestKnownAnswer(t *testing.T) {
+	ring := writeKeyFile(t, "", map[string]string{"k1": testKey})
+	event := &client.Event{ID: "users-1", Type: "user.created", Payload: map[string]interface{}{"email": knownEmail}}
+	if _, err := ring.Decrypt(event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Payload["email"] != "alice@example.com" {
+		t.Errorf("Decrypt = %v, want alice@example.com", event.Payload["email"])
+	}
+}
+
+// TestDecryptRejects checks that a value doesn't decrypt once altered, moved to another
+// field or event type, or opened with another key
+func TestDecryptRejects(t *testing.T) {
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(knownEmail, "enc:v1:k1:"))
+	flip := func(i int) string {
+		altered := append([]byte(nil), sealed...)
+		altered[i] ^= 1
+		return "enc:v1:k1:" + base64.StdEncoding.EncodeToString(altered)
+	}
+
+	tests := []struct {
+		name      string
+		keys      map[string]string
+		eventType string
+		field     string
+		value     string
+		want      string
+	}{
+		{"nonce altered", nil, "user.created", "email", flip(0), "altered"},
+		{"ciphertext altered", nil, "user.created", "email", flip(14), "altered"},
+		{"tag altered", nil, "user.created", "email", flip(len(sealed) - 1), "altered"},
+		{"truncated", nil, "user.created", "email", "enc:v1:k1:" + base64.StdEncoding.EncodeToString(sealed[:8]), "malformed"},
+		{"not base64", nil, "user.created", "email", "enc:v1:k1:!!", "malformed"},
+		{"no key ID", nil, "user.created", "email", "enc:v1:nokey", "malformed"},
+		{"moved to another field", nil, "user.created", "name", knownEmail, "altered"},
+		{"moved to another event type", nil, "user.deleted", "email", knownEmail, "altered"},
+		{"wrong key", map[string]string{"k1": otherKey(9)}, "user.created", "email", knownEmail, "altered"},
+		{"unknown key", map[string]string{"k2": testKey}, "user.created", "email", knownEmail, "no key 'k1'"},
+	}
+	for _, test := range tests {
+		keys := test.keys
+		if keys == nil {
+			keys = map[string]string{"k1": testKey}
+		}
+		ring := writeKeyFile(t, "", keys)
+		event := &client.Event{ID: "users-1", Type: test.eventType, Payload: map[string]interface{}{
+			test.field: test.value,
+			"plain":    "kept",
+		}}
+		decrypted, err := ring.Decrypt(event)
+		if err == nil || !strings.Contains(err.Error(), test.want) || !strings.Contains(err.Error(), "payload."+test.field) {
+			t.Errorf("%s: Decrypt = %v, want an error for payload.%s with %q", test.name, err, test.field, test.want)
+		}
+		if len(decrypted) != 0 || event.Payload[test.field] != test.value || event.Payload["plain"] != "kept" {
+			t.Errorf("%s: Decrypt changed the payload to %v", test.name, event.Payload)
+		}
+	}
+}
+
+// TestDecryptPartially checks that fields that decrypt are decrypted when others don't
+func TestDecryptPartially(t *testing.T) {
+	ring := writeKeyFile(t, "", map[string]string{"k1": testKey})
+	event := &client.Event{ID: "users-1", Type: "user.created", Payload: map[string]interface{}{
+		"email": knownEmail,
+		"name":  knownEmail,
+	}}
+	decrypted, err := ring.Decrypt(event)
+	if err == nil || !reflect.DeepEqual(decrypted, []string{"payload.email"}) || event.Payload["email"] != "alice@example.com" || event.Payload["name"] != knownEmail {
+		t.Errorf("Decrypt = %v, %v, payload %v", decrypted, err, event.Payload)
+	}
+}
+
+func TestNonceUnique(t *testing.T) {
+	ring := writeKeyFile(t, "", map[string]string{"k1": testKey})
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		request := &client.EventPublishRequest{Type: "user.created", Payload: map[string]interface{}{"email": "alice@example.com"}}
+		if _, err := ring.Encrypt(request, nil); err != nil {
+			t.Fatal(err)
+		}
+		sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(request.Payload["email"].(string), "enc:v1:k1:"))
+		nonce := string(sealed[:12])
+		if seen[nonce] {
+			t.Fatalf("nonce %x was used twice", nonce)
+		}
+		seen[nonce] = true
+	}
+}
+
+// TestKeyRotation checks that events encrypted with an old key still decrypt once a new
+// key is current
+func TestKeyRotation(t *testing.T) {
+	newKey := otherKey(3)
+	ring := writeKeyFile(t, "k2", map[string]string{"k1": testKey, "k2": newKey})
+	if err := ring.CanEncrypt(); err != nil {
+		t.Fatal(err)
+	}
+	event, _ := roundTrip(t, ring, map[string]interface{}{"name": "Alice"}, nil)
+	if !strings.HasPrefix(event.Payload["name"].(string), "enc:v1:k2:") {
+		t.Errorf("encrypted with %v, want k2", event.Payload["name"])
+	}
+	event.Payload["email"] = knownEmail
+	if _, err := ring.Decrypt(event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Payload["name"] != "Alice" || event.Payload["email"] != "alice@example.com" {
+		t.Errorf("Decrypt = %v", event.Payload)
+	}
+}
+
+func TestLoadKeyRingErrors(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{`{"keys": {}}`, "has no keys"},
+		{`{"keys": {"a:b": "` + testKey + `"}}`, "invalid key ID"},
+		{`{"keys": {"k1": "not base64!"}}`, "not base64"},
+		{`{"keys": {"k1": "AAAA"}}`, "not an AES key"},
+		{`not json`, "failed to parse"},
+	}
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		os.WriteFile(path, []byte(test.file), 0600)
+		if _, err := LoadKeyRing(path); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("LoadKeyRing(%s) = %v, want %q", test.file, err, test.want)
+		}
+	}
+
+	ring := writeKeyFile(t, "", map[string]string{"k1": testKey, "k2": testKey})
+	if err := ring.CanEncrypt(); err == nil {
+		t.Error("CanEncrypt without a current key of two succeeded")
+	}
+	ring = writeKeyFile(t, "k3", map[string]string{"k1": testKey})
+	if err := ring.CanEncrypt(); err == nil {
+		t.Error("CanEncrypt with a current key not in the file succeeded")
+	}
+}
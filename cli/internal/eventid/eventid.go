@@ -0,0 +1,82 @@
+package eventid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ID is a parsed event ID of the form <topic>-<sequence>, e.g. "user-events-42"
+type ID struct {
+	Topic    string
+	Sequence int64
+}
+
+// Parse parses an event ID. Topics may themselves contain dashes, so the
+// sequence is taken from after the last dash.
+func Parse(s string) (ID, error) {
+	i := strings.LastIndex(s, "-")
+	if i <= 0 || i == len(s)-1 {
+		return ID{}, fmt.Errorf("invalid event ID '%s' (expected '<topic>-<sequence>')", s)
+	}
+
+	digits := s[i+1:]
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return ID{}, fmt.Errorf("invalid event ID '%s' (sequence must be numeric)", s)
+		}
+	}
+
+	sequence, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return ID{}, fmt.Errorf("invalid event ID '%s' (sequence out of range)", s)
+	}
+
+	return ID{Topic: s[:i], Sequence: sequence}, nil
+}
+
+// String formats the ID as <topic>-<sequence>
+func (id ID) String() string {
+	return fmt.Sprintf("%s-%d", id.Topic, id.Sequence)
+}
+
+// Resolve turns a reference to an event of a topic into the event's ID. The reference
+// may be the ID itself, the event's sequence number, e.g. "42", "latest" for the topic's
+// last event, or "latest~n" for the event n before that. latest returns the sequence
+// number of the topic's last event, and is only called for references to it.
+func Resolve(topic, ref string, latest func() (int64, error)) (ID, error) {
+	if sequence, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		if sequence < 1 {
+			return ID{}, fmt.Errorf("invalid sequence number %d (sequences start at 1)", sequence)
+		}
+		return ID{Topic: topic, Sequence: sequence}, nil
+	}
+
+	if rest, ok := strings.CutPrefix(ref, "latest"); ok && (rest == "" || rest[0] == '~') {
+		var back int64
+		if rest != "" {
+			n, err := strconv.ParseInt(rest[1:], 10, 64)
+			if err != nil || n < 0 {
+				return ID{}, fmt.Errorf("invalid event reference '%s' (expected 'latest~<n>')", ref)
+			}
+			back = n
+		}
+		last, err := latest()
+		if err != nil {
+			return ID{}, err
+		}
+		if last < 1 {
+			return ID{}, fmt.Errorf("topic '%s' has no events", topic)
+		}
+		if back >= last {
+			return ID{}, fmt.Errorf("topic '%s' has only %d event(s), so '%s' doesn't exist", topic, last, ref)
+		}
+		return ID{Topic: topic, Sequence: last - back}, nil
+	}
+
+	id, err := Parse(ref)
+	if err != nil {
+		return ID{}, fmt.Errorf("invalid event reference '%s' (expected an event ID, a sequence number, 'latest' or 'latest~<n>')", ref)
+	}
+	return id, nil
+}
@@ -0,0 +1,158 @@
+package eventid
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		id    string
+		want  ID
+		error string
+	}{
+		{id: "orders-42", want: ID{Topic: "orders", Sequence: 42}},
+		{id: "user-events-7", want: ID{Topic: "user-events", Sequence: 7}},
+		{id: "orders-0", want: ID{Topic: "orders", Sequence: 0}},
+		{id: "orders", error: "expected '<topic>-<sequence>'"},
+		{id: "-42", error: "expected '<topic>-<sequence>'"},
+		{id: "orders-", error: "expected '<topic>-<sequence>'"},
+		{id: "orders-4a", error: "sequence must be numeric"},
+		{id: "orders-+4", error: "sequence must be numeric"},
+		{id: "orders-99999999999999999999", error: "sequence out of range"},
+	}
+	for _, tt := range tests {
+		id, err := Parse(tt.id)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Parse(%q) error = %v, want one containing %q", tt.id, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || id != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.id, id, err, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	latest := func(last int64) func() (int64, error) {
+		return func() (int64, error) { return last, nil }
+	}
+	tests := []struct {
+		ref   string
+		last  int64
+		want  ID
+		error string
+	}{
+		{ref: "42", last: 50, want: ID{"orders", 42}},
+		{ref: "orders-42", last: 50, want: ID{"orders", 42}},
+		{ref: "payments-3", last: 50, want: ID{"payments", 3}},
+		{ref: "latest", last: 50, want: ID{"orders", 50}},
+		{ref: "latest~0", last: 50, want: ID{"orders", 50}},
+		{ref: "latest~49", last: 50, want: ID{"orders", 1}},
+		{ref: "latest~50", last: 50, error: "has only 50 event(s)"},
+		{ref: "latest", last: 0, error: "has no events"},
+		{ref: "latest~x", last: 50, error: "expected 'latest~<n>'"},
+		{ref: "latest~-1", last: 50, error: "expected 'latest~<n>'"},
+		{ref: "0", last: 50, error: "sequences start at 1"},
+		{ref: "latestx", last: 50, error: "expected an event ID"},
+	}
+	for _, tt := range tests {
+		id, err := Resolve("orders", tt.ref, latest(tt.last))
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Resolve(%q) error = %v, want one containing %q", tt.ref, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || id != tt.want {
+			t.Errorf("Resolve(%q) = %+v, %v, want %+v", tt.ref, id, err, tt.want)
+		}
+	}
+}
+
+func TestResolveOnlyAsksForLatest(t *testing.T) {
+	asked := errors.New("latest was called")
+	for _, ref := range []string{"42", "orders-42"} {
+		if _, err := Resolve("orders", ref, func() (int64, error) { return 0, asked }); err != nil {
+			t.Errorf("Resolve(%q) error = %v", ref, err)
+		}
+	}
+	if _, err := Resolve("orders", "latest", func() (int64, error) { return 0, asked }); !errors.Is(err, asked) {
+		t.Errorf("Resolve(latest) error = %v, want %v", err, asked)
+	}
+}
+
+// FuzzParse checks that Parse never panics, and that a parsed ID formats to a string
+// that parses back to the same ID
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"orders-42", "user-events-7", "-1", "a-", "a--1", "a-9223372036854775808", "é-0"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		id, err := Parse(s)
+		if err != nil {
+			return
+		}
+		again, err := Parse(id.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", id.String(), err)
+		}
+		if again != id {
+			t.Fatalf("Parse(%q) = %+v, want %+v", id.String(), again, id)
+		}
+	})
+}
+
+// FuzzResolve checks that Resolve never panics, and never resolves a reference to an
+// event after the topic's last one or before its first
+func FuzzResolve(f *testing.F) {
+	for _, seed := range []string{"42", "latest", "latest~3", "latest~", "orders-1", "0", "-5"} {
+		f.Add(seed, int64(10))
+	}
+	f.Fuzz(func(t *testing.T, ref string, last int64) {
+		id, err := Resolve("orders", ref, func() (int64, error) { return last, nil })
+		if err != nil || id.Topic != "orders" {
+			return
+		}
+		if strings.HasPrefix(ref, "latest") && (id.Sequence < 1 || id.Sequence > last) {
+			t.Fatalf("Resolve(%q) with %d events = %+v", ref, last, id)
+		}
+	})
+}
+
+// topic is a topic name, which may contain dashes and digits
+type topic string
+
+func (topic) Generate(r *rand.Rand, size int) reflect.Value {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789_"
+	parts := make([]string, 1+r.Intn(3))
+	for i := range parts {
+		b := make([]byte, 1+r.Intn(8))
+		for j := range b {
+			b[j] = chars[r.Intn(len(chars))]
+		}
+		parts[i] = string(b)
+	}
+	return reflect.ValueOf(topic(strings.Join(parts, "-")))
+}
+
+// TestRoundTripProperty checks that every ID formats to a string that parses back to it
+func TestRoundTripProperty(t *testing.T) {
+	property := func(name topic, sequence int64) bool {
+		if sequence < 0 {
+			sequence = -(sequence + 1)
+		}
+		id := ID{Topic: string(name), Sequence: sequence}
+		parsed, err := Parse(id.String())
+		return err == nil && parsed == id
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
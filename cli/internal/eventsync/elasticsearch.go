@@ -0,0 +1,255 @@
+package eventsync
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/elasticsearch"
+)
+
+const elasticsearchCheckpoints = "es-sink-checkpoints"
+
+// Elasticsearch is an Elasticsearch or OpenSearch cluster events are indexed into, a
+// document per event with the event's ID as the document's
+type Elasticsearch struct {
+	client    *elasticsearch.Client
+	server    string
+	index     string // index name pattern, e.g. events-{topic}-{date}
+	batchSize int
+	templates map[string]map[string]interface{} // template name -> payload mappings; nil when templates are off
+	templated map[string]bool                   // topics whose schemas are in their template
+}
+
+// OpenElasticsearch returns a target indexing events from a server into the indexes an
+// index name pattern gives, at most batchSize per bulk request. With templates, an index
+// template maps the indexes' fields from the topics' schemas.
+func OpenElasticsearch(es *elasticsearch.Client, index, server string, batchSize int, templates bool) (*Elasticsearch, error) {
+	if err := checkIndexPattern(index); err != nil {
+		return nil, err
+	}
+	e := &Elasticsearch{client: es, server: server, index: index, batchSize: batchSize, templated: map[string]bool{}}
+	if templates {
+		e.templates = map[string]map[string]interface{}{}
+	}
+	return e, nil
+}
+
+// elasticsearchCheckpoint is the document holding the last event indexed from a topic
+type elasticsearchCheckpoint struct {
+	Index       string    `json:"index"`
+	Topic       string    `json:"topic"`
+	Server      string    `json:"server"`
+	LastEventID string    `json:"lastEventId"`
+	Updated     time.Time `json:"updated"`
+}
+
+// checkpointID is the ID of a topic's checkpoint document; a topic has a checkpoint for
+// each index pattern it is indexed into
+func (e *Elasticsearch) checkpointID(topic string) string {
+	return e.index + "/" + topic
+}
+
+// Checkpoint implements Target
+func (e *Elasticsearch) Checkpoint(topic string) (string, error) {
+	var checkpoint elasticsearchCheckpoint
+	found, err := e.client.GetDocument(context.Background(), elasticsearchCheckpoints, e.checkpointID(topic), &checkpoint)
+	if err != nil || !found {
+		return "", err
+	}
+	if err := checkServer(checkpoint.Server, e.server); err != nil {
+		return "", err
+	}
+	return checkpoint.LastEventID, nil
+}
+
+// Reset implements Target
+func (e *Elasticsearch) Reset(topic string) error {
+	return e.client.DeleteDocument(context.Background(), elasticsearchCheckpoints, e.checkpointID(topic))
+}
+
+// eventDocument is the document of an event
+type eventDocument struct {
+	Topic string `json:"topic"`
+	client.Event
+}
+
+// Write implements Target. Documents are indexed before the checkpoint is saved, so a
+// page indexed just before a failure is indexed again, replacing the same documents.
+func (e *Elasticsearch) Write(topic *client.Topic, events []client.Event, checkpoint string) error {
+	ctx := context.Background()
+	if e.templates != nil && !e.templated[topic.Name] {
+		if err := e.putTemplate(ctx, topic); err != nil {
+			return err
+		}
+		e.templated[topic.Name] = true
+	}
+
+	for start := 0; start < len(events); start += e.batchSize {
+		batch := events[start:min(start+e.batchSize, len(events))]
+		items := make([]elasticsearch.BulkItem, len(batch))
+		for i, event := range batch {
+			items[i] = elasticsearch.BulkItem{Index: e.indexName(topic.Name, event), ID: event.ID, Document: eventDocument{Topic: topic.Name, Event: event}}
+		}
+		if err := e.client.Bulk(ctx, items); err != nil {
+			return err
+		}
+	}
+
+	return e.client.PutDocument(ctx, elasticsearchCheckpoints, e.checkpointID(topic.Name), elasticsearchCheckpoint{
+		Index:       e.index,
+		Topic:       topic.Name,
+		Server:      e.server,
+		LastEventID: checkpoint,
+		Updated:     time.Now().UTC(),
+	})
+}
+
+// Close implements Target
+func (e *Elasticsearch) Close() error {
+	return nil
+}
+
+var (
+	indexPlaceholders = regexp.MustCompile(`\{[^}]*\}`)
+	unsafeIndexChars  = regexp.MustCompile(`[\\/*?"<>| ,#:]+`)
+)
+
+func checkIndexPattern(pattern string) error {
+	for _, placeholder := range indexPlaceholders.FindAllString(pattern, -1) {
+		switch placeholder {
+		case "{topic}", "{type}", "{date}", "{month}":
+		default:
+			return fmt.Errorf("unknown placeholder %s in index name (expected {topic}, {type}, {date} or {month})", placeholder)
+		}
+	}
+	if pattern == "" || strings.HasPrefix(pattern, "_") || strings.HasPrefix(pattern, "-") {
+		return fmt.Errorf("invalid index name '%s'", pattern)
+	}
+	return nil
+}
+
+// indexName returns the index of an event: the pattern with its placeholders filled in,
+// lower-cased and with characters index names can't contain replaced by '-'
+func (e *Elasticsearch) indexName(topic string, event client.Event) string {
+	timestamp, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
+	}
+	timestamp = timestamp.UTC()
+	name := strings.NewReplacer(
+		"{topic}", topic,
+		"{type}", event.Type,
+		"{date}", timestamp.Format("2006.01.02"),
+		"{month}", timestamp.Format("2006.01"),
+	).Replace(e.index)
+	return unsafeIndexChars.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// putTemplate creates or updates the index template of a topic's indexes, adding the
+// fields of its schemas to those of topics already sharing the template
+func (e *Elasticsearch) putTemplate(ctx context.Context, topic *client.Topic) error {
+	pattern := strings.NewReplacer("{topic}", topic.Name, "{type}", "*", "{date}", "*", "{month}", "*").Replace(e.index)
+	pattern = unsafeIndexChars.ReplaceAllStringFunc(strings.ToLower(pattern), func(s string) string {
+		if s == "*" {
+			return s
+		}
+		return "-"
+	})
+	name := "es-sink-" + strings.Trim(unsafeIndexChars.ReplaceAllString(pattern, "-"), "-")
+
+	payload := e.templates[name]
+	if payload == nil {
+		payload = map[string]interface{}{}
+		e.templates[name] = payload
+	}
+	conflicts := map[string]bool{}
+	for _, schema := range topic.Schemas {
+		for field, property := range schemaMappings(schema.Properties) {
+			if existing, ok := payload[field]; ok && fmt.Sprint(existing) != fmt.Sprint(property) {
+				conflicts[field] = true
+			}
+			payload[field] = property
+		}
+	}
+	// Fields the schemas type differently are left to dynamic mapping
+	for field := range conflicts {
+		delete(payload, field)
+	}
+
+	keyword := map[string]interface{}{"type": "keyword"}
+	template := map[string]interface{}{
+		"index_patterns": []string{pattern},
+		"priority":       200,
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"id":            keyword,
+					"topic":         keyword,
+					"type":          keyword,
+					"timestamp":     map[string]interface{}{"type": "date"},
+					"correlationId": keyword,
+					"causationId":   keyword,
+					"partition":     map[string]interface{}{"type": "integer"},
+					"payload":       map[string]interface{}{"properties": payload},
+					"metadata":      map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+	}
+	if err := e.client.PutIndexTemplate(ctx, name, template); err != nil {
+		return fmt.Errorf("failed to create index template %s: %w", name, err)
+	}
+	return nil
+}
+
+// schemaMappings returns the field mappings of JSON schema properties: strings as text
+// with a keyword subfield (as Elasticsearch maps them itself), or as dates for the
+// date-time format, integers as longs, numbers as doubles, booleans as booleans and
+// objects by their own properties. Arrays are mapped by the type of their items.
+func schemaMappings(properties map[string]interface{}) map[string]interface{} {
+	mappings := map[string]interface{}{}
+	for name, value := range properties {
+		property, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if mapping := schemaMapping(property); mapping != nil {
+			mappings[name] = mapping
+		}
+	}
+	return mappings
+}
+
+func schemaMapping(property map[string]interface{}) map[string]interface{} {
+	fieldType, _ := property["type"].(string)
+	switch fieldType {
+	case "string":
+		if format, _ := property["format"].(string); format == "date-time" || format == "date" {
+			return map[string]interface{}{"type": "date"}
+		}
+		return map[string]interface{}{
+			"type":   "text",
+			"fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256}},
+		}
+	case "integer":
+		return map[string]interface{}{"type": "long"}
+	case "number":
+		return map[string]interface{}{"type": "double"}
+	case "boolean":
+		return map[string]interface{}{"type": "boolean"}
+	case "object":
+		if nested, ok := property["properties"].(map[string]interface{}); ok {
+			return map[string]interface{}{"properties": schemaMappings(nested)}
+		}
+		return map[string]interface{}{"type": "object"}
+	case "array":
+		if items, ok := property["items"].(map[string]interface{}); ok {
+			return schemaMapping(items)
+		}
+	}
+	return nil
+}
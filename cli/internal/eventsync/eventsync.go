@@ -0,0 +1,149 @@
+// Package eventsync copies a topic's events into a database, a table per event type with
+// a column per payload field, so event history can be queried locally. Each database
+// keeps its own checkpoint, written with the events, so a sync carries on where the
+// last one stopped without copying an event twice.
+package eventsync
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// Target is a database events are synced into
+type Target interface {
+	// Checkpoint returns the ID of the last event synced from a topic, "" if none
+	Checkpoint(topic string) (string, error)
+	// Write stores a page of a topic's events, and the ID of the last event read, in one
+	// transaction
+	Write(topic *client.Topic, events []client.Event, checkpoint string) error
+	// Reset forgets a topic's checkpoint, leaving its rows
+	Reset(topic string) error
+	Close() error
+}
+
+// Stats counts what a sync did
+type Stats struct {
+	Events int // events read
+	Rows   int // events written
+}
+
+// Sync copies the events of a topic published since the target's checkpoint, and
+// matching the filters, into the target until it has caught up
+func Sync(ctx context.Context, apiClient *client.Client, target Target, topic string, filters []*filter.Filter) (Stats, error) {
+	var stats Stats
+	info, err := apiClient.GetTopic(topic)
+	if err != nil {
+		return stats, err
+	}
+	since, err := target.Checkpoint(topic)
+	if err != nil {
+		return stats, err
+	}
+	err = apiClient.ScanEvents(topic, since, func(events []client.Event) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		stats.Events += len(events)
+		var rows []client.Event
+		for _, event := range events {
+			if filter.MatchAll(filters, event) {
+				rows = append(rows, event)
+			}
+		}
+		if err := target.Write(info, rows, events[len(events)-1].ID); err != nil {
+			return false, err
+		}
+		stats.Rows += len(rows)
+		return true, nil
+	})
+	return stats, err
+}
+
+// Event columns every table has; payload fields with these names are left in the
+// payload column
+const (
+	ColumnID        = "_id"
+	ColumnTopic     = "_topic"
+	ColumnTimestamp = "_timestamp"
+	ColumnPayload   = "_payload"
+)
+
+// Column is a payload field's column
+type Column struct {
+	Name string
+	Type string // the field's JSON schema type: string, integer, number, boolean, object or array; "" when unknown
+}
+
+var unsafeTableChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// Tables names an event type's table and lists its columns, from the topic's schemas
+type Tables struct {
+	prefix  string
+	schemas map[string]client.Schema
+}
+
+// NewTables creates the tables of a topic with schemas, their names starting with prefix
+func NewTables(prefix string, schemas []client.Schema) *Tables {
+	t := &Tables{prefix: prefix, schemas: map[string]client.Schema{}}
+	for _, schema := range schemas {
+		t.schemas[schema.EventType] = schema
+	}
+	return t
+}
+
+// Name returns the table of an event type: the prefix and the type, with characters
+// other than letters, digits and underscores replaced by underscores
+func (t *Tables) Name(eventType string) string {
+	return t.prefix + unsafeTableChars.ReplaceAllString(eventType, "_")
+}
+
+// Columns returns the columns of an event: those of its type's schema properties, in
+// name order, then those of any other payload fields
+func (t *Tables) Columns(event client.Event) []Column {
+	var columns []Column
+	seen := map[string]bool{ColumnID: true, ColumnTopic: true, ColumnTimestamp: true, ColumnPayload: true}
+	add := func(name, fieldType string) {
+		// Column names are case-insensitive in SQL
+		if key := strings.ToLower(name); !seen[key] {
+			seen[key] = true
+			columns = append(columns, Column{Name: name, Type: fieldType})
+		}
+	}
+	if schema, ok := t.schemas[event.Type]; ok {
+		for _, name := range sortedKeys(schema.Properties) {
+			fieldType := ""
+			if property, ok := schema.Properties[name].(map[string]interface{}); ok {
+				fieldType, _ = property["type"].(string)
+			}
+			add(name, fieldType)
+		}
+	}
+	for _, name := range sortedKeys(event.Payload) {
+		add(name, "")
+	}
+	return columns
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkServer returns an error if a checkpoint was made against another server, as its
+// event IDs mean nothing on this one
+func checkServer(checkpointServer, server string) error {
+	if checkpointServer != "" && checkpointServer != server {
+		return fmt.Errorf("it was synced from %s, not %s (use --reset to sync it from the start)", checkpointServer, server)
+	}
+	return nil
+}
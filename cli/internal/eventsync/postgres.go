@@ -0,0 +1,147 @@
+package eventsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/postgres"
+)
+
+const postgresCheckpoints = "es_sink_checkpoints"
+
+// Postgres is a PostgreSQL table events are synced into: a row per event, with the
+// payload as JSONB and indexed id, type and timestamp columns
+type Postgres struct {
+	conn      *postgres.Conn
+	server    string
+	table     string
+	batchSize int
+}
+
+// OpenPostgres connects to a PostgreSQL database to sync events from a server into a
+// table, creating the table and its indexes if need be, and inserting at most batchSize
+// rows per statement
+func OpenPostgres(ctx context.Context, cfg *postgres.Config, table, server string, batchSize int) (*Postgres, error) {
+	conn, err := postgres.Connect(ctx, cfg, 0)
+	if err != nil {
+		return nil, err
+	}
+	p := &Postgres{conn: conn, server: server, table: table, batchSize: batchSize}
+
+	name := qualifiedIdent(table)
+	// Indexes are created in the table's schema, so are named after the table alone
+	base := table[strings.LastIndex(table, ".")+1:]
+	index := func(column string) string {
+		return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s);\n", postgres.Ident(base+"_"+column+"_idx"), name, column)
+	}
+	schema := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  id TEXT PRIMARY KEY,
+  topic TEXT NOT NULL,
+  type TEXT NOT NULL,
+  timestamp TIMESTAMPTZ NOT NULL,
+  payload JSONB NOT NULL,
+  correlation_id TEXT,
+  causation_id TEXT,
+  metadata JSONB,
+  synced_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`, name) + index("type") + index("timestamp") + fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  sink_table TEXT NOT NULL,
+  topic TEXT NOT NULL,
+  server TEXT NOT NULL,
+  last_event_id TEXT NOT NULL,
+  updated TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (sink_table, topic)
+);`, postgresCheckpoints)
+	if err := conn.Exec(schema); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Checkpoint implements Target
+func (p *Postgres) Checkpoint(topic string) (string, error) {
+	rows, err := p.conn.Query(fmt.Sprintf("SELECT server, last_event_id FROM %s WHERE sink_table = %s AND topic = %s",
+		postgresCheckpoints, postgres.Literal(p.table), postgres.Literal(topic)))
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	if err := checkServer(rows[0]["server"], p.server); err != nil {
+		return "", err
+	}
+	return rows[0]["last_event_id"], nil
+}
+
+// Reset implements Target
+func (p *Postgres) Reset(topic string) error {
+	return p.conn.Exec(fmt.Sprintf("DELETE FROM %s WHERE sink_table = %s AND topic = %s",
+		postgresCheckpoints, postgres.Literal(p.table), postgres.Literal(topic)))
+}
+
+// Write implements Target. A row replaces the row of the same event ID, so syncing an
+// event again updates it.
+func (p *Postgres) Write(topic *client.Topic, events []client.Event, checkpoint string) error {
+	var sql strings.Builder
+	for start := 0; start < len(events); start += p.batchSize {
+		end := min(start+p.batchSize, len(events))
+		fmt.Fprintf(&sql, "INSERT INTO %s (id, topic, type, timestamp, payload, correlation_id, causation_id, metadata) VALUES\n", qualifiedIdent(p.table))
+		for i, event := range events[start:end] {
+			payload, err := json.Marshal(event.Payload)
+			if err != nil {
+				return err
+			}
+			metadata := "NULL"
+			if event.Metadata != nil {
+				data, err := json.Marshal(event.Metadata)
+				if err != nil {
+					return err
+				}
+				metadata = postgres.Literal(string(data))
+			}
+			if i > 0 {
+				sql.WriteString(",\n")
+			}
+			fmt.Fprintf(&sql, "  (%s, %s, %s, %s, %s, %s, %s, %s)",
+				postgres.Literal(event.ID), postgres.Literal(topic.Name), postgres.Literal(event.Type), postgres.Literal(event.Timestamp),
+				postgres.Literal(string(payload)), nullable(event.CorrelationID), nullable(event.CausationID), metadata)
+		}
+		sql.WriteString("\nON CONFLICT (id) DO UPDATE SET topic = EXCLUDED.topic, type = EXCLUDED.type, timestamp = EXCLUDED.timestamp, payload = EXCLUDED.payload, " +
+			"correlation_id = EXCLUDED.correlation_id, causation_id = EXCLUDED.causation_id, metadata = EXCLUDED.metadata, synced_at = now();\n")
+	}
+	fmt.Fprintf(&sql, "INSERT INTO %s (sink_table, topic, server, last_event_id, updated) VALUES (%s, %s, %s, %s, %s)\n"+
+		"ON CONFLICT (sink_table, topic) DO UPDATE SET server = EXCLUDED.server, last_event_id = EXCLUDED.last_event_id, updated = EXCLUDED.updated;",
+		postgresCheckpoints, postgres.Literal(p.table), postgres.Literal(topic.Name), postgres.Literal(p.server), postgres.Literal(checkpoint),
+		postgres.Literal(time.Now().UTC().Format(time.RFC3339Nano)))
+	// The statements of one query run in one transaction
+	return p.conn.Exec(sql.String())
+}
+
+// Close implements Target
+func (p *Postgres) Close() error {
+	return p.conn.Close()
+}
+
+// qualifiedIdent quotes a table name that may be qualified by its schema, e.g.
+// reporting.order_events
+func qualifiedIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = postgres.Ident(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func nullable(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return postgres.Literal(s)
+}
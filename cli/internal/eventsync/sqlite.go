@@ -0,0 +1,154 @@
+package eventsync
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/sqlite"
+)
+
+const sqliteCheckpoints = "_es_sync"
+
+// SQLite is a SQLite database events are synced into
+type SQLite struct {
+	db      *sqlite.DB
+	server  string
+	prefix  string
+	columns map[string]map[string]bool // table -> lower-cased column names, once read
+}
+
+// OpenSQLite opens, or creates, a SQLite database to sync events from a server into,
+// in tables whose names start with prefix
+func OpenSQLite(path, server, prefix string) (*SQLite, error) {
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (topic TEXT PRIMARY KEY, server TEXT NOT NULL, last_event_id TEXT NOT NULL, updated TEXT NOT NULL)", sqlite.Ident(sqliteCheckpoints))
+	if err := db.Exec(create); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLite{db: db, server: server, prefix: prefix, columns: map[string]map[string]bool{}}, nil
+}
+
+// Checkpoint implements Target
+func (s *SQLite) Checkpoint(topic string) (string, error) {
+	rows, err := s.db.Query(fmt.Sprintf("SELECT server, last_event_id FROM %s WHERE topic = %s", sqlite.Ident(sqliteCheckpoints), sqlite.Literal(topic)))
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	server, _ := rows[0]["server"].(string)
+	if err := checkServer(server, s.server); err != nil {
+		return "", err
+	}
+	lastEventID, _ := rows[0]["last_event_id"].(string)
+	return lastEventID, nil
+}
+
+// Reset implements Target
+func (s *SQLite) Reset(topic string) error {
+	return s.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE topic = %s", sqlite.Ident(sqliteCheckpoints), sqlite.Literal(topic)))
+}
+
+// Write implements Target. A row replaces the row of the same event, so syncing an
+// event again updates it.
+func (s *SQLite) Write(topic *client.Topic, events []client.Event, checkpoint string) error {
+	tables := NewTables(s.prefix, topic.Schemas)
+	var sql strings.Builder
+	sql.WriteString("BEGIN;\n")
+	added := map[string]map[string]bool{}
+	for _, event := range events {
+		table := tables.Name(event.Type)
+		existing, err := s.tableColumns(table)
+		if err != nil {
+			return err
+		}
+		if added[table] == nil {
+			added[table] = map[string]bool{}
+			if len(existing) == 0 {
+				fmt.Fprintf(&sql, "CREATE TABLE %s (%s TEXT NOT NULL, %s TEXT NOT NULL, %s TEXT, %s TEXT, PRIMARY KEY (%s, %s));\n",
+					sqlite.Ident(table), sqlite.Ident(ColumnTopic), sqlite.Ident(ColumnID), sqlite.Ident(ColumnTimestamp), sqlite.Ident(ColumnPayload),
+					sqlite.Ident(ColumnTopic), sqlite.Ident(ColumnID))
+			}
+		}
+
+		names := []string{sqlite.Ident(ColumnTopic), sqlite.Ident(ColumnID), sqlite.Ident(ColumnTimestamp), sqlite.Ident(ColumnPayload)}
+		values := []string{sqlite.Literal(topic.Name), sqlite.Literal(event.ID), sqlite.Literal(event.Timestamp), sqlite.Literal(event.Payload)}
+		for _, column := range tables.Columns(event) {
+			key := strings.ToLower(column.Name)
+			if !existing[key] && !added[table][key] {
+				fmt.Fprintf(&sql, "ALTER TABLE %s ADD COLUMN %s %s;\n", sqlite.Ident(table), sqlite.Ident(column.Name), sqliteType(column.Type))
+				added[table][key] = true
+			}
+			names = append(names, sqlite.Ident(column.Name))
+			values = append(values, sqlite.Literal(event.Payload[column.Name]))
+		}
+		fmt.Fprintf(&sql, "INSERT OR REPLACE INTO %s (%s) VALUES (%s);\n", sqlite.Ident(table), strings.Join(names, ", "), strings.Join(values, ", "))
+	}
+	fmt.Fprintf(&sql, "INSERT INTO %s (topic, server, last_event_id, updated) VALUES (%s, %s, %s, %s) ON CONFLICT(topic) DO UPDATE SET server = excluded.server, last_event_id = excluded.last_event_id, updated = excluded.updated;\n",
+		sqlite.Ident(sqliteCheckpoints), sqlite.Literal(topic.Name), sqlite.Literal(s.server), sqlite.Literal(checkpoint), sqlite.Literal(time.Now().UTC().Format(time.RFC3339Nano)))
+	sql.WriteString("COMMIT;")
+	if err := s.db.Exec(sql.String()); err != nil {
+		return err
+	}
+
+	for table, columns := range added {
+		if s.columns[table] == nil {
+			s.columns[table] = map[string]bool{}
+			for _, name := range []string{ColumnTopic, ColumnID, ColumnTimestamp, ColumnPayload} {
+				s.columns[table][name] = true
+			}
+		}
+		for column := range columns {
+			s.columns[table][column] = true
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the lower-cased columns of a table, none if it doesn't exist yet
+func (s *SQLite) tableColumns(table string) (map[string]bool, error) {
+	if columns, ok := s.columns[table]; ok {
+		return columns, nil
+	}
+	rows, err := s.db.Query(fmt.Sprintf("SELECT name FROM pragma_table_info(%s)", sqlite.Literal(table)))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	columns := map[string]bool{}
+	for _, row := range rows {
+		if name, ok := row["name"].(string); ok {
+			columns[strings.ToLower(name)] = true
+		}
+	}
+	s.columns[table] = columns
+	return columns, nil
+}
+
+// Close implements Target
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// sqliteType returns the column type of a JSON schema type. Fields of no known type get
+// no column type, so SQLite stores each value as it is.
+func sqliteType(fieldType string) string {
+	switch fieldType {
+	case "string", "object", "array":
+		return "TEXT"
+	case "integer", "boolean":
+		return "INTEGER"
+	case "number":
+		return "REAL"
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+// avroBlockSize is the number of records per block of an Avro file
+const avroBlockSize = 4096
+
+// avroWriter writes an Avro object container file of a record per event
+type avroWriter struct {
+	encoder *ocf.Encoder
+	columns []Column
+}
+
+func newAvroWriter(w io.Writer, columns []Column, codec string) (*avroWriter, error) {
+	schema, err := json.Marshal(AvroSchema(columns))
+	if err != nil {
+		return nil, err
+	}
+	// The full schema keeps each field's doc, naming the payload property it holds
+	encoder, err := ocf.NewEncoder(string(schema), w,
+		ocf.WithCodec(ocf.CodecName(codec)),
+		ocf.WithBlockLength(avroBlockSize),
+		ocf.WithSchemaMarshaler(ocf.FullSchemaMarshaler))
+	if err != nil {
+		return nil, err
+	}
+	return &avroWriter{encoder: encoder, columns: columns}, nil
+}
+
+// AvroSchema returns the Avro record schema of the columns. Nullable columns are unions
+// with null, and timestamps are longs with the timestamp-micros logical type.
+func AvroSchema(columns []Column) map[string]interface{} {
+	fields := make([]map[string]interface{}, len(columns))
+	for i, column := range columns {
+		var fieldType interface{}
+		switch column.Kind {
+		case Long:
+			fieldType = "long"
+		case Double:
+			fieldType = "double"
+		case Boolean:
+			fieldType = "boolean"
+		case Timestamp:
+			fieldType = map[string]string{"type": "long", "logicalType": "timestamp-micros"}
+		default:
+			fieldType = "string"
+		}
+		field := map[string]interface{}{"name": column.Name, "type": fieldType}
+		if !column.Required {
+			field["type"] = []interface{}{"null", fieldType}
+			field["default"] = nil
+		}
+		if column.Field != "" {
+			field["doc"] = "payload." + column.Field
+		}
+		fields[i] = field
+	}
+	return map[string]interface{}{
+		"type":      "record",
+		"name":      "Event",
+		"namespace": "eventstore",
+		"fields":    fields,
+	}
+}
+
+func (a *avroWriter) Write(events []client.Event) error {
+	for _, event := range events {
+		record := make(map[string]interface{}, len(a.columns))
+		for _, column := range a.columns {
+			value := column.Value(event)
+			// The encoder picks the timestamp-micros branch of a union for a time
+			if micros, ok := value.(int64); ok && column.Kind == Timestamp {
+				value = time.UnixMicro(micros).UTC()
+			}
+			record[column.Name] = value
+		}
+		if err := a.encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *avroWriter) Close() error {
+	return a.encoder.Close()
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+// readAvro decodes the records of an Avro file, with its codec and schema
+func readAvro(t *testing.T, data []byte) ([]map[string]interface{}, string, map[string]interface{}) {
+	decoder, err := ocf.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]interface{}
+	for decoder.HasNext() {
+		var record map[string]interface{}
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if err := decoder.Error(); err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(decoder.Metadata()["avro.schema"], &schema); err != nil {
+		t.Fatal(err)
+	}
+	return records, string(decoder.Metadata()["avro.codec"]), schema
+}
+
+func TestAvroRoundTrip(t *testing.T) {
+	columns := Columns(testSchemas, true)
+	for _, codec := range []string{"deflate", "null"} {
+		var buf bytes.Buffer
+		writer, err := NewWriter("avro", &buf, columns, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Write(testEvents); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		records, fileCodec, schema := readAvro(t, buf.Bytes())
+		if fileCodec != codec {
+			t.Errorf("avro.codec = %q, want %q", fileCodec, codec)
+		}
+		if len(records) != len(testRows) {
+			t.Fatalf("%s: read %d records, want %d", codec, len(records), len(testRows))
+		}
+		for i, row := range testRows {
+			for j, column := range columns {
+				want := row[j]
+				if micros, ok := want.(int64); ok {
+					want = time.UnixMicro(micros).UTC()
+				}
+				if got := records[i][column.Name]; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: record %d %s = %#v, want %#v", codec, i, column.Name, got, want)
+				}
+			}
+		}
+
+		// Fields keep the payload property they hold as their doc
+		fields := schema["fields"].([]interface{})
+		if field := fields[4].(map[string]interface{}); field["name"] != "customer_name" || field["doc"] != "payload.customer-name" {
+			t.Errorf("%s: schema field = %v, want customer_name with its property as doc", codec, field)
+		}
+	}
+}
+
+func TestAvroSchema(t *testing.T) {
+	schema := AvroSchema([]Column{
+		{Name: "id", Kind: String, Required: true},
+		{Name: "timestamp", Kind: Timestamp},
+		{Name: "qty", Kind: Long, Field: "qty"},
+		{Name: "price", Kind: Double, Field: "price"},
+		{Name: "paid", Kind: Boolean, Field: "paid"},
+		{Name: "address", Kind: JSON, Field: "address"},
+	})
+	data, _ := json.Marshal(schema)
+	want := `{"fields":[` +
+		`{"name":"id","type":"string"},` +
+		`{"default":null,"name":"timestamp","type":["null",{"logicalType":"timestamp-micros","type":"long"}]},` +
+		`{"default":null,"doc":"payload.qty","name":"qty","type":["null","long"]},` +
+		`{"default":null,"doc":"payload.price","name":"price","type":["null","double"]},` +
+		`{"default":null,"doc":"payload.paid","name":"paid","type":["null","boolean"]},` +
+		`{"default":null,"doc":"payload.address","name":"address","type":["null","string"]}` +
+		`],"name":"Event","namespace":"eventstore","type":"record"}`
+	if string(data) != want {
+		t.Errorf("AvroSchema = %s\nwant %s", data, want)
+	}
+}
+
+// TestAvroBlocks checks that records are written in blocks of avroBlockSize, which
+// Verify counts
+func TestAvroBlocks(t *testing.T) {
+	columns := Columns(nil, false)
+	events := make([]client.Event, avroBlockSize*2+1)
+	for i := range events {
+		events[i] = client.Event{ID: fmt.Sprintf("orders-%d", i+1), Type: "order.created", Payload: map[string]interface{}{"n": float64(i)}}
+	}
+	var buf bytes.Buffer
+	writer, err := NewWriter("avro", &buf, columns, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(events); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := countAvro(bufio.NewReader(bytes.NewReader(buf.Bytes())))
+	if err != nil || count != int64(len(events)) {
+		t.Errorf("countAvro = %d, %v, want %d", count, err, len(events))
+	}
+	records, _, _ := readAvro(t, buf.Bytes())
+	if len(records) != len(events) || records[len(events)-1]["id"] != events[len(events)-1].ID {
+		t.Errorf("read %d records, want %d", len(records), len(events))
+	}
+}
@@ -0,0 +1,159 @@
+package export
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Kind is the type of an exported column's values
+type Kind int
+
+const (
+	String Kind = iota
+	Long        // 64-bit integer
+	Double      // 64-bit float
+	Boolean
+	Timestamp // microseconds since the Unix epoch, UTC
+	JSON      // a JSON document, stored as a string
+)
+
+// Column is one field of an exported record. Every column except the event ID and type
+// may be null.
+type Column struct {
+	Name     string
+	Kind     Kind
+	Required bool
+	Field    string // payload property the column holds, or "" for the event's own fields
+}
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Columns derives the columns of an export from a topic's schemas: the event ID, type
+// and timestamp, then each top-level payload property declared by any of the schemas.
+// Properties are typed from their JSON schema types, and stored as JSON when the
+// schemas disagree or declare objects or arrays. The whole payload is added as a JSON
+// column with rawPayload, or when there are no schemas to derive columns from.
+func Columns(schemas []client.Schema, rawPayload bool) []Column {
+	columns := []Column{
+		{Name: "id", Kind: String, Required: true},
+		{Name: "type", Kind: String, Required: true},
+		{Name: "timestamp", Kind: Timestamp},
+	}
+	taken := map[string]bool{"id": true, "type": true, "timestamp": true, "payload": true}
+
+	kinds := map[string]Kind{}
+	for _, schema := range schemas {
+		for property, definition := range schema.Properties {
+			kind := propertyKind(definition)
+			if existing, ok := kinds[property]; ok && existing != kind {
+				if (existing == Long || existing == Double) && (kind == Long || kind == Double) {
+					kind = Double
+				} else {
+					kind = JSON
+				}
+			}
+			kinds[property] = kind
+		}
+	}
+	properties := make([]string, 0, len(kinds))
+	for property := range kinds {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	for _, property := range properties {
+		name := invalidNameChars.ReplaceAllString(property, "_")
+		if name == "" || name[0] >= '0' && name[0] <= '9' {
+			name = "_" + name
+		}
+		for taken[name] {
+			name = "payload_" + name
+		}
+		taken[name] = true
+		columns = append(columns, Column{Name: name, Kind: kinds[property], Field: property})
+	}
+
+	if rawPayload || len(properties) == 0 {
+		columns = append(columns, Column{Name: "payload", Kind: JSON})
+	}
+	return columns
+}
+
+// propertyKind maps a JSON schema property definition to a column kind
+func propertyKind(definition interface{}) Kind {
+	properties, _ := definition.(map[string]interface{})
+	switch properties["type"] {
+	case "string":
+		return String
+	case "integer":
+		return Long
+	case "number":
+		return Double
+	case "boolean":
+		return Boolean
+	default:
+		return JSON
+	}
+}
+
+// Value returns an event's value for a column: a string, int64, float64 or bool, or nil
+// for null. Payload values that don't fit the column's kind are null, as is a timestamp
+// that isn't RFC 3339; the raw payload column holds the whole payload as JSON.
+func (c Column) Value(event client.Event) interface{} {
+	if c.Field == "" {
+		switch c.Name {
+		case "id":
+			return event.ID
+		case "type":
+			return event.Type
+		case "timestamp":
+			t, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+			if err != nil {
+				return nil
+			}
+			return t.UnixMicro()
+		case "payload":
+			return encodeJSON(event.Payload)
+		}
+		return nil
+	}
+
+	value, ok := event.Payload[c.Field]
+	if !ok || value == nil {
+		return nil
+	}
+	switch c.Kind {
+	case String:
+		if s, ok := value.(string); ok {
+			return s
+		}
+	case Long:
+		if f, ok := value.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return int64(f)
+		}
+	case Double:
+		if f, ok := value.(float64); ok {
+			return f
+		}
+	case Boolean:
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	case JSON:
+		return encodeJSON(value)
+	}
+	return nil
+}
+
+func encodeJSON(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// testSchemas declare a property of every column kind. The schemas disagree on "ref".
+var testSchemas = []client.Schema{
+	{EventType: "order.created", Properties: map[string]interface{}{
+		"customer-name": map[string]interface{}{"type": "string"},
+		"qty":           map[string]interface{}{"type": "integer"},
+		"price":         map[string]interface{}{"type": "number"},
+		"paid":          map[string]interface{}{"type": "boolean"},
+		"address":       map[string]interface{}{"type": "object"},
+		"ref":           map[string]interface{}{"type": "string"},
+	}},
+	{EventType: "order.paid", Properties: map[string]interface{}{
+		"qty":  map[string]interface{}{"type": "number"},
+		"ref":  map[string]interface{}{"type": "integer"},
+		"type": map[string]interface{}{"type": "string"},
+	}},
+}
+
+// testEvents have every kind of value, then nulls: missing properties and timestamp,
+// JSON nulls, and values of the wrong type
+var testEvents = []client.Event{
+	{
+		ID: "orders-1", Type: "order.created", Timestamp: "2024-03-01T12:30:45.123456Z",
+		Payload: map[string]interface{}{
+			"customer-name": "Ada \"Countess\" Lovelace",
+			"qty":           float64(3),
+			"price":         9.75,
+			"paid":          true,
+			"address":       map[string]interface{}{"city": "London"},
+			"ref":           float64(42),
+			"type":          "retail",
+		},
+	},
+	{
+		ID: "orders-2", Type: "order.paid",
+		Payload: map[string]interface{}{
+			"customer-name": nil,
+			"qty":           2.5,
+			"paid":          false,
+		},
+	},
+	{
+		ID: "orders-3", Type: "order.paid", Timestamp: "not a time",
+		Payload: map[string]interface{}{
+			"customer-name": float64(7),
+			"qty":           "three",
+			"price":         "free",
+			"paid":          "yes",
+			"type":          []interface{}{"retail"},
+		},
+	},
+}
+
+// testRows are testEvents' values for the columns of testSchemas, with the raw payload
+var testRows = [][]interface{}{
+	{"orders-1", "order.created", int64(1709296245123456), "{\"city\":\"London\"}", "Ada \"Countess\" Lovelace", true, 9.75, 3.0, "42", "retail",
+		`{"address":{"city":"London"},"customer-name":"Ada \"Countess\" Lovelace","paid":true,"price":9.75,"qty":3,"ref":42,"type":"retail"}`},
+	{"orders-2", "order.paid", nil, nil, nil, false, nil, 2.5, nil, nil,
+		`{"customer-name":null,"paid":false,"qty":2.5}`},
+	{"orders-3", "order.paid", nil, nil, nil, nil, nil, nil, nil, nil,
+		`{"customer-name":7,"paid":"yes","price":"free","qty":"three","type":["retail"]}`},
+}
+
+func TestColumns(t *testing.T) {
+	want := []Column{
+		{Name: "id", Kind: String, Required: true},
+		{Name: "type", Kind: String, Required: true},
+		{Name: "timestamp", Kind: Timestamp},
+		{Name: "address", Kind: JSON, Field: "address"},
+		{Name: "customer_name", Kind: String, Field: "customer-name"},
+		{Name: "paid", Kind: Boolean, Field: "paid"},
+		{Name: "price", Kind: Double, Field: "price"},
+		{Name: "qty", Kind: Double, Field: "qty"},
+		{Name: "ref", Kind: JSON, Field: "ref"},
+		{Name: "payload_type", Kind: String, Field: "type"},
+		{Name: "payload", Kind: JSON},
+	}
+	if got := Columns(testSchemas, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns = %+v\nwant %+v", got, want)
+	}
+	if got := Columns(testSchemas, false); !reflect.DeepEqual(got, want[:len(want)-1]) {
+		t.Errorf("Columns without the raw payload = %+v", got)
+	}
+	if got := Columns(nil, false); len(got) != 4 || got[3].Name != "payload" {
+		t.Errorf("Columns without schemas = %+v, want the raw payload", got)
+	}
+
+	odd := []client.Schema{{Properties: map[string]interface{}{"2nd": nil, "é": nil}}}
+	var names []string
+	for _, column := range Columns(odd, false)[3:] {
+		names = append(names, column.Name)
+	}
+	if want := []string{"_2nd", "_"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Columns of odd names = %v, want %v", names, want)
+	}
+}
+
+func TestValue(t *testing.T) {
+	columns := Columns(testSchemas, true)
+	for i, event := range testEvents {
+		for j, column := range columns {
+			if got := column.Value(event); !reflect.DeepEqual(got, testRows[i][j]) {
+				t.Errorf("%s.Value(%s) = %#v, want %#v", column.Name, event.ID, got, testRows[i][j])
+			}
+		}
+	}
+
+	// A column of none of the event's own fields has no value
+	if got := (Column{Name: "other", Kind: JSON}).Value(testEvents[0]); got != nil {
+		t.Errorf("other.Value(orders-1) = %#v, want nil", got)
+	}
+}
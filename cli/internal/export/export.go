@@ -0,0 +1,124 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/compress"
+)
+
+// Formats lists the export formats
+var Formats = []string{"ndjson", "avro", "parquet"}
+
+// Result describes a finished export
+type Result struct {
+	Topic        string   `json:"topic"`
+	File         string   `json:"file"`
+	Format       string   `json:"format"`
+	Compression  string   `json:"compression,omitempty"`
+	Columns      []string `json:"columns,omitempty"` // record fields of Avro and Parquet exports
+	Filter       string   `json:"filter,omitempty"`  // only events matching this were exported
+	Masked       []string `json:"masked,omitempty"`  // fields exported as '***'
+	Events       int      `json:"events"`
+	FirstEventID string   `json:"firstEventId,omitempty"`
+	LastEventID  string   `json:"lastEventId,omitempty"`
+	Bytes        int64    `json:"bytes"`
+	Encrypted    bool     `json:"encrypted,omitempty"` // sealed with a passphrase or for recipients, see package seal
+	SHA256       string   `json:"sha256,omitempty"`    // of the file, also written to its manifest
+	Verified     bool     `json:"verified,omitempty"`  // re-read and checked with --verify
+}
+
+// Writer writes exported events to a file in one of the formats
+type Writer interface {
+	// Write adds events to the export
+	Write(events []client.Event) error
+	// Close finishes the export; it doesn't close the underlying writer
+	Close() error
+}
+
+// FormatOf guesses an export format from a file name's extension, after any compression
+// extension such as .gz, or returns ""
+func FormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(compress.Trim(path))) {
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	case ".avro":
+		return "avro"
+	case ".parquet":
+		return "parquet"
+	}
+	return ""
+}
+
+// Compression checks a format and compression codec, returning the codec the export will
+// use: for Avro "null" or "deflate" (the default), for Parquet "none" or "gzip" (the
+// default). NDJSON files are compressed whole as they're written, with "gzip" or "zstd",
+// and "none" (the default) is returned as "".
+func Compression(format, compression string) (string, error) {
+	var codecs []string
+	switch format {
+	case "ndjson":
+		return compress.Check(compression)
+	case "avro":
+		codecs = []string{"deflate", "null"}
+	case "parquet":
+		codecs = []string{"gzip", "none"}
+	default:
+		return "", fmt.Errorf("unknown export format '%s' (expected %s)", format, strings.Join(Formats, ", "))
+	}
+	if compression == "" {
+		return codecs[0], nil
+	}
+	for _, codec := range codecs {
+		if compression == codec {
+			return codec, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported %s compression '%s' (expected %s)", format, compression, strings.Join(codecs, " or "))
+}
+
+// NewWriter returns a writer of events in a format, with a compression codec checked by
+// Compression. columns are the fields of Avro and Parquet records; NDJSON writes events
+// as they are.
+func NewWriter(format string, w io.Writer, columns []Column, compression string) (Writer, error) {
+	codec, err := Compression(format, compression)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "avro":
+		return newAvroWriter(w, columns, codec)
+	case "parquet":
+		return newParquetWriter(w, columns, codec)
+	default:
+		stream, err := compress.NewWriter(w, codec)
+		if err != nil {
+			return nil, err
+		}
+		return &ndjsonWriter{encoder: json.NewEncoder(stream), stream: stream}, nil
+	}
+}
+
+// ndjsonWriter writes one event per line, as JSON, to a stream compressed with the
+// export's codec, if any
+type ndjsonWriter struct {
+	encoder *json.Encoder
+	stream  io.WriteCloser
+}
+
+func (n *ndjsonWriter) Write(events []client.Event) error {
+	for _, event := range events {
+		if err := n.encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.stream.Close()
+}
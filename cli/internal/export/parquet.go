@@ -0,0 +1,313 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// parquetRowGroupSize is the number of rows buffered for each row group of a Parquet file
+const parquetRowGroupSize = 65536
+
+// Parquet enum values (see parquet.thrift)
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+	parquetJSON            = 19
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetUncompressed = 0
+	parquetGzip         = 2
+
+	parquetDataPage = 0
+)
+
+// parquetWriter writes a Parquet file with one row group per parquetRowGroupSize rows
+// and one plain-encoded data page per column chunk
+type parquetWriter struct {
+	w         io.Writer
+	columns   []Column
+	codec     int32
+	offset    int64
+	rows      [][]interface{} // buffered values, by column
+	buffered  int
+	total     int64
+	rowGroups []parquetRowGroup
+}
+
+type parquetRowGroup struct {
+	rows   int64
+	bytes  int64
+	chunks []parquetChunk
+}
+
+type parquetChunk struct {
+	offset       int64
+	values       int64
+	uncompressed int64
+	compressed   int64
+}
+
+func newParquetWriter(w io.Writer, columns []Column, codec string) (*parquetWriter, error) {
+	p := &parquetWriter{w: w, columns: columns, rows: make([][]interface{}, len(columns)), codec: parquetUncompressed}
+	if codec == "gzip" {
+		p.codec = parquetGzip
+	}
+	if err := p.write([]byte("PAR1")); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parquetWriter) write(data []byte) error {
+	n, err := p.w.Write(data)
+	p.offset += int64(n)
+	return err
+}
+
+func (p *parquetWriter) Write(events []client.Event) error {
+	for _, event := range events {
+		for i, column := range p.columns {
+			p.rows[i] = append(p.rows[i], column.Value(event))
+		}
+		p.buffered++
+		if p.buffered == parquetRowGroupSize {
+			if err := p.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a row group
+func (p *parquetWriter) flush() error {
+	if p.buffered == 0 {
+		return nil
+	}
+	group := parquetRowGroup{rows: int64(p.buffered)}
+	for i, column := range p.columns {
+		chunk, err := p.writeChunk(column, p.rows[i])
+		if err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, *chunk)
+		group.bytes += chunk.uncompressed
+		p.rows[i] = p.rows[i][:0]
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.total += int64(p.buffered)
+	p.buffered = 0
+	return nil
+}
+
+// writeChunk writes a column's values as a single data page: the definition levels of an
+// optional column, then its non-null values in plain encoding
+func (p *parquetWriter) writeChunk(column Column, values []interface{}) (*parquetChunk, error) {
+	var page bytes.Buffer
+	if !column.Required {
+		levels := definitionLevels(values)
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(len(levels)))
+		page.Write(length[:])
+		page.Write(levels)
+	}
+	encodePlain(&page, values)
+
+	data := page.Bytes()
+	if p.codec == parquetGzip {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		data = compressed.Bytes()
+	}
+
+	var header thriftWriter
+	header.begin()
+	header.i32(1, parquetDataPage)
+	header.i32(2, int32(page.Len()))
+	header.i32(3, int32(len(data)))
+	header.structField(5)
+	header.i32(1, int32(len(values)))
+	header.i32(2, parquetPlain)
+	header.i32(3, parquetRLE)
+	header.i32(4, parquetRLE)
+	header.end()
+	header.end()
+
+	chunk := &parquetChunk{
+		offset:       p.offset,
+		values:       int64(len(values)),
+		uncompressed: int64(header.buf.Len() + page.Len()),
+		compressed:   int64(header.buf.Len() + len(data)),
+	}
+	if err := p.write(header.buf.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := p.write(data); err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// definitionLevels encodes whether each value is present (1) or null (0) in the
+// RLE/bit-packed hybrid encoding with a bit width of 1, as bit-packed groups of eight
+func definitionLevels(values []interface{}) []byte {
+	groups := (len(values) + 7) / 8
+	var buf bytes.Buffer
+	var header [binary.MaxVarintLen64]byte
+	buf.Write(header[:binary.PutUvarint(header[:], uint64(groups)<<1|1)])
+	packed := make([]byte, groups)
+	for i, value := range values {
+		if value != nil {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	buf.Write(packed)
+	return buf.Bytes()
+}
+
+// encodePlain writes the non-null values in Parquet's plain encoding
+func encodePlain(buf *bytes.Buffer, values []interface{}) {
+	var bits byte
+	var nbits int
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			var length [4]byte
+			binary.LittleEndian.PutUint32(length[:], uint32(len(v)))
+			buf.Write(length[:])
+			buf.WriteString(v)
+		case int64:
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(v))
+			buf.Write(b[:])
+		case float64:
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+			buf.Write(b[:])
+		case bool:
+			if v {
+				bits |= 1 << nbits
+			}
+			nbits++
+			if nbits == 8 {
+				buf.WriteByte(bits)
+				bits, nbits = 0, 0
+			}
+		}
+	}
+	if nbits > 0 {
+		buf.WriteByte(bits)
+	}
+}
+
+// parquetType returns a column's physical type and converted type (-1 for none)
+func parquetType(kind Kind) (int32, int32) {
+	switch kind {
+	case Long:
+		return parquetInt64, -1
+	case Double:
+		return parquetDouble, -1
+	case Boolean:
+		return parquetBoolean, -1
+	case Timestamp:
+		return parquetInt64, parquetTimestampMicros
+	case JSON:
+		return parquetByteArray, parquetJSON
+	default:
+		return parquetByteArray, parquetUTF8
+	}
+}
+
+// Close writes the last row group and the file metadata
+func (p *parquetWriter) Close() error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+
+	var meta thriftWriter
+	meta.begin()
+	meta.i32(1, 1)
+
+	meta.listField(2, thriftStruct, len(p.columns)+1)
+	meta.begin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(p.columns)))
+	meta.end()
+	for _, column := range p.columns {
+		physical, converted := parquetType(column.Kind)
+		repetition := int32(parquetOptional)
+		if column.Required {
+			repetition = parquetRequired
+		}
+		meta.begin()
+		meta.i32(1, physical)
+		meta.i32(3, repetition)
+		meta.binary(4, column.Name)
+		if converted >= 0 {
+			meta.i32(6, converted)
+		}
+		meta.end()
+	}
+
+	meta.i64(3, p.total)
+	meta.listField(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		meta.begin()
+		meta.listField(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			physical, _ := parquetType(p.columns[i].Kind)
+			meta.begin()
+			meta.i64(2, chunk.offset)
+			meta.structField(3)
+			meta.i32(1, physical)
+			meta.i32List(2, []int32{parquetPlain, parquetRLE})
+			meta.binaryList(3, []string{p.columns[i].Name})
+			meta.i32(4, p.codec)
+			meta.i64(5, chunk.values)
+			meta.i64(6, chunk.uncompressed)
+			meta.i64(7, chunk.compressed)
+			meta.i64(9, chunk.offset)
+			meta.end()
+			meta.end()
+		}
+		meta.i64(2, group.bytes)
+		meta.i64(3, group.rows)
+		meta.end()
+	}
+	meta.binary(6, "es (event store CLI)")
+	meta.end()
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(meta.buf.Len()))
+	if err := p.write(meta.buf.Bytes()); err != nil {
+		return err
+	}
+	if err := p.write(length[:]); err != nil {
+		return err
+	}
+	return p.write([]byte("PAR1"))
+}
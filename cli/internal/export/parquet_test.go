@@ -0,0 +1,372 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Field IDs and enum values of parquet.thrift, spelled out rather than taken from the
+// writer's constants
+const (
+	fileSchema    = 2 // FileMetaData
+	fileNumRows   = 3
+	fileRowGroups = 4
+
+	elementType       = 1 // SchemaElement
+	elementRepetition = 3
+	elementName       = 4
+	elementChildren   = 5
+	elementConverted  = 6
+
+	groupColumns = 1 // RowGroup
+	groupBytes   = 2
+	groupRows    = 3
+
+	chunkOffset = 2 // ColumnChunk
+	chunkMeta   = 3
+
+	metaType         = 1 // ColumnMetaData
+	metaPath         = 3
+	metaCodec        = 4
+	metaValues       = 5
+	metaUncompressed = 6
+	metaCompressed   = 7
+	metaPageOffset   = 9
+
+	pageType         = 1 // PageHeader
+	pageUncompressed = 2
+	pageCompressed   = 3
+	pageData         = 5
+	dataValues       = 1 // DataPageHeader
+)
+
+// thriftStructOf is a decoded compact protocol struct: int64 for integers, string for
+// binary, []interface{} for lists and thriftStructOf for structs, by field ID
+type thriftStructOf map[int16]interface{}
+
+// decodeThrift decodes a compact protocol value of a type
+func decodeThrift(r *thriftReader, fieldType byte) (interface{}, error) {
+	switch fieldType {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, n)
+		_, err = io.ReadFull(r.r, data)
+		return string(data), err
+	case thriftList:
+		header, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = binary.ReadUvarint(r.r); err != nil {
+				return nil, err
+			}
+		}
+		list := []interface{}{}
+		for i := uint64(0); i < size; i++ {
+			element, err := decodeThrift(r, header&0x0F)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, element)
+		}
+		return list, nil
+	case thriftStruct:
+		fields := thriftStructOf{}
+		var lastID int16
+		for {
+			id, fieldType, err := r.field(lastID)
+			if err != nil || fieldType == 0 {
+				return fields, err
+			}
+			if fields[id], err = decodeThrift(r, fieldType); err != nil {
+				return nil, err
+			}
+			lastID = id
+		}
+	}
+	return nil, fmt.Errorf("unknown thrift type %d", fieldType)
+}
+
+func (s thriftStructOf) int(id int16) int64 {
+	n, _ := s[id].(int64)
+	return n
+}
+
+func (s thriftStructOf) list(id int16) []interface{} {
+	list, _ := s[id].([]interface{})
+	return list
+}
+
+func (s thriftStructOf) child(id int16) thriftStructOf {
+	child, _ := s[id].(thriftStructOf)
+	return child
+}
+
+// parquetFile is a Parquet file read back: its metadata and each column's values, with
+// nil for null
+type parquetFile struct {
+	meta   thriftStructOf
+	values [][]interface{}
+}
+
+// readParquet decodes a Parquet file, checking its layout against its metadata
+func readParquet(t *testing.T, data []byte) parquetFile {
+	t.Helper()
+	if string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatal("file doesn't start and end with PAR1")
+	}
+	length := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := bytes.NewReader(data[len(data)-8-length : len(data)-8])
+	decoded, err := decodeThrift(&thriftReader{r: footer}, thriftStruct)
+	if err != nil || footer.Len() != 0 {
+		t.Fatalf("footer: %v, %d bytes left over", err, footer.Len())
+	}
+	file := parquetFile{meta: decoded.(thriftStructOf)}
+
+	schema := file.meta.list(fileSchema)
+	file.values = make([][]interface{}, len(schema)-1)
+	var rows int64
+	for _, g := range file.meta.list(fileRowGroups) {
+		group := g.(thriftStructOf)
+		rows += group.int(groupRows)
+		var chunkBytes int64
+		for i, c := range group.list(groupColumns) {
+			chunk := c.(thriftStructOf)
+			meta := chunk.child(chunkMeta)
+			element := schema[i+1].(thriftStructOf)
+			if meta.int(metaPageOffset) != chunk.int(chunkOffset) || !reflect.DeepEqual(meta.list(metaPath), []interface{}{element[elementName]}) {
+				t.Fatalf("column chunk %d: %v, for %v", i, chunk, element)
+			}
+
+			page := bytes.NewReader(data[meta.int(metaPageOffset):])
+			decoded, err := decodeThrift(&thriftReader{r: page}, thriftStruct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			header := decoded.(thriftStructOf)
+			headerSize := int64(len(data)) - meta.int(metaPageOffset) - int64(page.Len())
+			if header.int(pageType) != 0 || header.child(pageData).int(dataValues) != meta.int(metaValues) || meta.int(metaValues) != group.int(groupRows) {
+				t.Fatalf("page header %v of column chunk %v", header, meta)
+			}
+			if meta.int(metaCompressed) != headerSize+header.int(pageCompressed) || meta.int(metaUncompressed) != headerSize+header.int(pageUncompressed) {
+				t.Errorf("column %d: chunk sizes %d, %d, for a header of %d and page of %d, %d", i, meta.int(metaCompressed), meta.int(metaUncompressed), headerSize, header.int(pageCompressed), header.int(pageUncompressed))
+			}
+			chunkBytes += meta.int(metaUncompressed)
+
+			body := make([]byte, header.int(pageCompressed))
+			io.ReadFull(page, body)
+			switch meta.int(metaCodec) {
+			case 2: // GZIP
+				reader, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, _ = io.ReadAll(reader)
+			case 0: // UNCOMPRESSED
+			default:
+				t.Fatalf("codec %d", meta.int(metaCodec))
+			}
+			if int64(len(body)) != header.int(pageUncompressed) {
+				t.Fatalf("column %d: page of %d bytes, want %d", i, len(body), header.int(pageUncompressed))
+			}
+			if meta.int(metaType) != element.int(elementType) {
+				t.Errorf("column %d: chunk type %d, schema type %d", i, meta.int(metaType), element.int(elementType))
+			}
+			file.values[i] = append(file.values[i], decodePage(t, body, element, int(meta.int(metaValues)))...)
+		}
+		if group.int(groupBytes) != chunkBytes {
+			t.Errorf("row group total_byte_size = %d, want %d", group.int(groupBytes), chunkBytes)
+		}
+	}
+	if file.meta.int(fileNumRows) != rows {
+		t.Errorf("num_rows = %d, row groups have %d", file.meta.int(fileNumRows), rows)
+	}
+	return file
+}
+
+// decodePage decodes a data page's definition levels, for optional columns, and its
+// plain-encoded values
+func decodePage(t *testing.T, page []byte, element thriftStructOf, count int) []interface{} {
+	present := make([]bool, count)
+	for i := range present {
+		present[i] = true
+	}
+	if element.int(elementRepetition) == 1 { // OPTIONAL
+		length := binary.LittleEndian.Uint32(page)
+		levels := bytes.NewReader(page[4 : 4+length])
+		page = page[4+length:]
+		// The RLE/bit-packed hybrid encoding, with a bit width of 1
+		var decoded []bool
+		for levels.Len() > 0 {
+			header, _ := binary.ReadUvarint(levels)
+			if header&1 == 1 {
+				for group := uint64(0); group < header>>1; group++ {
+					b, _ := levels.ReadByte()
+					for bit := 0; bit < 8; bit++ {
+						decoded = append(decoded, b&(1<<bit) != 0)
+					}
+				}
+			} else {
+				value, _ := levels.ReadByte()
+				for run := uint64(0); run < header>>1; run++ {
+					decoded = append(decoded, value == 1)
+				}
+			}
+		}
+		if len(decoded) < count {
+			t.Fatalf("%d definition levels for %d values", len(decoded), count)
+		}
+		copy(present, decoded)
+	}
+
+	values := make([]interface{}, count)
+	var bit int
+	for i := range values {
+		if !present[i] {
+			continue
+		}
+		switch element.int(elementType) {
+		case 0: // BOOLEAN
+			values[i] = page[bit/8]&(1<<(bit%8)) != 0
+			bit++
+		case 2: // INT64
+			values[i] = int64(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case 5: // DOUBLE
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case 6: // BYTE_ARRAY
+			length := binary.LittleEndian.Uint32(page)
+			values[i] = string(page[4 : 4+length])
+			page = page[4+length:]
+		default:
+			t.Fatalf("type %d", element.int(elementType))
+		}
+	}
+	if bit > 0 {
+		page = page[(bit+7)/8:]
+	}
+	if len(page) != 0 {
+		t.Errorf("%d bytes left over in the page of %v", len(page), element)
+	}
+	return values
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	columns := Columns(testSchemas, true)
+	for _, codec := range []string{"gzip", "none"} {
+		var buf bytes.Buffer
+		writer, err := NewWriter("parquet", &buf, columns, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Write(testEvents); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		file := readParquet(t, buf.Bytes())
+
+		// The schema's root, then a column each: type, repetition and converted type
+		want := [][3]int64{
+			{6, 0, 0},  // id: BYTE_ARRAY, REQUIRED, UTF8
+			{6, 0, 0},  // type
+			{2, 1, 10}, // timestamp: INT64, OPTIONAL, TIMESTAMP_MICROS
+			{6, 1, 19}, // address: JSON
+			{6, 1, 0},  // customer_name: UTF8
+			{0, 1, -1}, // paid: BOOLEAN
+			{5, 1, -1}, // price: DOUBLE
+			{5, 1, -1}, // qty: DOUBLE, as the schemas disagree
+			{6, 1, 19}, // ref: JSON, as the schemas disagree
+			{6, 1, 0},  // payload_type
+			{6, 1, 19}, // payload
+		}
+		schema := file.meta.list(fileSchema)
+		if root := schema[0].(thriftStructOf); root[elementName] != "schema" || root.int(elementChildren) != int64(len(columns)) {
+			t.Errorf("%s: schema root = %v", codec, root)
+		}
+		for i, column := range columns {
+			element := schema[i+1].(thriftStructOf)
+			converted := int64(-1)
+			if _, ok := element[elementConverted]; ok {
+				converted = element.int(elementConverted)
+			}
+			got := [3]int64{element.int(elementType), element.int(elementRepetition), converted}
+			if element[elementName] != column.Name || got != want[i] {
+				t.Errorf("%s: schema element %v, want %s %v", codec, element, column.Name, want[i])
+			}
+		}
+
+		for i, row := range testRows {
+			for j, column := range columns {
+				if got := file.values[j][i]; !reflect.DeepEqual(got, row[j]) {
+					t.Errorf("%s: row %d %s = %#v, want %#v", codec, i, column.Name, got, row[j])
+				}
+			}
+		}
+	}
+}
+
+// TestParquetRowGroups checks a file of more rows than a row group holds, with more
+// than eight values to a column's definition levels
+func TestParquetRowGroups(t *testing.T) {
+	columns := []Column{
+		{Name: "id", Kind: String, Required: true},
+		{Name: "n", Kind: Long, Field: "n"},
+		{Name: "even", Kind: Boolean, Field: "even"},
+	}
+	events := make([]client.Event, parquetRowGroupSize+11)
+	for i := range events {
+		events[i] = client.Event{ID: fmt.Sprint(i), Payload: map[string]interface{}{"even": i%2 == 0}}
+		if i%3 != 0 {
+			events[i].Payload["n"] = float64(i)
+		}
+	}
+	var buf bytes.Buffer
+	writer, err := NewWriter("parquet", &buf, columns, "none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for start := 0; start < len(events); start += 1000 {
+		if err := writer.Write(events[start:min(start+1000, len(events))]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := readParquet(t, buf.Bytes())
+	if groups := len(file.meta.list(fileRowGroups)); groups != 2 {
+		t.Errorf("%d row groups, want 2", groups)
+	}
+	for i, event := range events {
+		var n interface{}
+		if i%3 != 0 {
+			n = int64(i)
+		}
+		if file.values[0][i] != event.ID || file.values[1][i] != n || file.values[2][i] != (i%2 == 0) {
+			t.Fatalf("row %d = %v, %v, %v", i, file.values[0][i], file.values[1][i], file.values[2][i])
+		}
+	}
+	count, err := countParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil || count != int64(len(events)) {
+		t.Errorf("countParquet = %d, %v, want %d", count, err, len(events))
+	}
+}
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Thrift compact protocol field types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs in the Thrift compact protocol, which Parquet uses for its
+// metadata. Fields must be written in increasing order of ID within each struct.
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16 // the last field ID written in each open struct
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := t.lastIDs[len(t.lastIDs)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.varint(int64(id))
+	}
+	t.lastIDs[len(t.lastIDs)-1] = id
+}
+
+func (t *thriftWriter) varint(n int64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutVarint(b[:], n)])
+}
+
+func (t *thriftWriter) uvarint(n uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutUvarint(b[:], n)])
+}
+
+// begin opens a struct: the top-level one, or a list element
+func (t *thriftWriter) begin() {
+	t.lastIDs = append(t.lastIDs, 0)
+}
+
+// end closes a struct with a stop field
+func (t *thriftWriter) end() {
+	t.buf.WriteByte(0)
+	t.lastIDs = t.lastIDs[:len(t.lastIDs)-1]
+}
+
+// structField opens a struct-valued field; close it with end
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.begin()
+}
+
+func (t *thriftWriter) i32(id int16, n int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(n))
+}
+
+func (t *thriftWriter) i64(id int16, n int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(n)
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.uvarint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+// listField starts a list field of size elements of a type; struct elements are then
+// written each between begin and end
+func (t *thriftWriter) listField(id int16, elementType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		t.buf.WriteByte(0xF0 | elementType)
+		t.uvarint(uint64(size))
+	}
+}
+
+func (t *thriftWriter) i32List(id int16, values []int32) {
+	t.listField(id, thriftI32, len(values))
+	for _, v := range values {
+		t.varint(int64(v))
+	}
+}
+
+func (t *thriftWriter) binaryList(id int16, values []string) {
+	t.listField(id, thriftBinary, len(values))
+	for _, v := range values {
+		t.uvarint(uint64(len(v)))
+		t.buf.WriteString(v)
+	}
+}
+
+// thriftReader decodes structs in the Thrift compact protocol, enough to read back the
+// metadata thriftWriter writes
+type thriftReader struct {
+	r *bytes.Reader
+}
+
+// field reads a field header of the current struct, given the last field ID read in it;
+// the type is 0 at the struct's end
+func (t *thriftReader) field(lastID int16) (int16, byte, error) {
+	header, err := t.r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	fieldType := header & 0x0F
+	if fieldType == 0 {
+		return 0, 0, nil
+	}
+	if delta := int16(header >> 4); delta != 0 {
+		return lastID + delta, fieldType, nil
+	}
+	id, err := binary.ReadVarint(t.r)
+	return int16(id), fieldType, err
+}
+
+func (t *thriftReader) varint() (int64, error) {
+	return binary.ReadVarint(t.r)
+}
+
+// skip reads past a value of a type
+func (t *thriftReader) skip(fieldType byte) error {
+	switch fieldType {
+	case thriftI32, thriftI64:
+		_, err := t.varint()
+		return err
+	case thriftBinary:
+		n, err := binary.ReadUvarint(t.r)
+		if err != nil {
+			return err
+		}
+		_, err = t.r.Seek(int64(n), io.SeekCurrent)
+		return err
+	case thriftList:
+		header, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = binary.ReadUvarint(t.r); err != nil {
+				return err
+			}
+		}
+		for i := uint64(0); i < size; i++ {
+			if err := t.skip(header & 0x0F); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftStruct:
+		var lastID int16
+		for {
+			id, fieldType, err := t.field(lastID)
+			if err != nil || fieldType == 0 {
+				return err
+			}
+			if err := t.skip(fieldType); err != nil {
+				return err
+			}
+			lastID = id
+		}
+	}
+	return fmt.Errorf("unsupported thrift type %d", fieldType)
+}
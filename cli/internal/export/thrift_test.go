@@ -0,0 +1,114 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestThriftWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *thriftWriter)
+		want  []byte
+	}{
+		{"short and long field deltas", func(w *thriftWriter) {
+			w.i32(1, 1)
+			w.binary(4, "ab")
+			w.i32(20, -1)
+		}, []byte{0x15, 0x02, 0x38, 0x02, 'a', 'b', 0x05, 0x28, 0x01, 0x00}},
+		{"i64", func(w *thriftWriter) {
+			w.i64(1, -2)
+			w.i64(2, 300)
+		}, []byte{0x16, 0x03, 0x16, 0xD8, 0x04, 0x00}},
+		{"nested struct", func(w *thriftWriter) {
+			w.structField(2)
+			w.i32(1, 7)
+			w.end()
+			w.i32(3, 0)
+		}, []byte{0x2C, 0x15, 0x0E, 0x00, 0x15, 0x00, 0x00}},
+		{"short list", func(w *thriftWriter) {
+			w.i32List(1, []int32{1, -1})
+		}, []byte{0x19, 0x25, 0x02, 0x01, 0x00}},
+		{"long list", func(w *thriftWriter) {
+			w.binaryList(1, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o"})
+		}, []byte{0x19, 0xF8, 0x0F,
+			0x01, 'a', 0x01, 'b', 0x01, 'c', 0x01, 'd', 0x01, 'e', 0x01, 'f', 0x01, 'g', 0x01, 'h',
+			0x01, 'i', 0x01, 'j', 0x01, 'k', 0x01, 'l', 0x01, 'm', 0x01, 'n', 0x01, 'o', 0x00}},
+		{"list of structs", func(w *thriftWriter) {
+			w.listField(1, thriftStruct, 2)
+			for _, s := range []string{"x", "y"} {
+				w.begin()
+				w.binary(1, s)
+				w.end()
+			}
+		}, []byte{0x19, 0x2C, 0x18, 0x01, 'x', 0x00, 0x18, 0x01, 'y', 0x00, 0x00}},
+	}
+	for _, test := range tests {
+		var w thriftWriter
+		w.begin()
+		test.write(&w)
+		w.end()
+		if got := w.buf.Bytes(); !bytes.Equal(got, test.want) {
+			t.Errorf("%s: wrote % x, want % x", test.name, got, test.want)
+		}
+	}
+}
+
+// TestThriftRoundTrip checks that what thriftWriter writes reads back, and that skip
+// reads past all of it
+func TestThriftRoundTrip(t *testing.T) {
+	long := make([]string, 20)
+	for i := range long {
+		long[i] = fmt.Sprint(i)
+	}
+	var w thriftWriter
+	w.begin()
+	w.i32(1, math.MinInt32)
+	w.i64(2, math.MaxInt64)
+	w.i64(3, math.MinInt64)
+	w.binary(17, "")
+	w.structField(18)
+	w.structField(40)
+	w.binaryList(1, long)
+	w.end()
+	w.end()
+	w.listField(19, thriftStruct, 1)
+	w.begin()
+	w.i32List(300, []int32{math.MaxInt32})
+	w.end()
+	w.end()
+	w.buf.WriteString("after")
+
+	r := &thriftReader{r: bytes.NewReader(w.buf.Bytes())}
+	got, err := decodeThrift(r, thriftStruct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strings := make([]interface{}, len(long))
+	for i, s := range long {
+		strings[i] = s
+	}
+	want := thriftStructOf{
+		1:  int64(math.MinInt32),
+		2:  int64(math.MaxInt64),
+		3:  int64(math.MinInt64),
+		17: "",
+		18: thriftStructOf{40: thriftStructOf{1: strings}},
+		19: []interface{}{thriftStructOf{300: []interface{}{int64(math.MaxInt32)}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read back %v\nwant %v", got, want)
+	}
+
+	r = &thriftReader{r: bytes.NewReader(w.buf.Bytes())}
+	if err := r.skip(thriftStruct); err != nil || r.r.Len() != len("after") {
+		t.Errorf("skip = %v, leaving %d bytes, want %d", err, r.r.Len(), len("after"))
+	}
+	r = &thriftReader{r: bytes.NewReader(w.buf.Bytes()[:w.buf.Len()-len("after")-3])}
+	if err := r.skip(thriftStruct); err == nil {
+		t.Error("skip of a truncated struct succeeded")
+	}
+}
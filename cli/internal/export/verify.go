@@ -0,0 +1,323 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/event-store/cli/internal/compress"
+	"github.com/event-store/cli/internal/seal"
+)
+
+// ManifestSuffix ends the name of the manifest written next to an export
+const ManifestSuffix = ".manifest.json"
+
+// ManifestName returns the name of the manifest of an export file
+func ManifestName(file string) string {
+	return file + ManifestSuffix
+}
+
+// WriteManifest writes the result of an export next to its file, naming the file
+// relative to the manifest, so the export can be verified wherever it's copied to
+func WriteManifest(result *Result) error {
+	manifest := *result
+	manifest.File = filepath.Base(result.File)
+	manifest.Verified = false
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(ManifestName(result.File), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write export manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest reads the manifest of an export, with its File resolved against the
+// manifest's directory
+func ReadManifest(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse export manifest %s: %w", path, err)
+	}
+	if result.File == "" || result.SHA256 == "" {
+		return nil, fmt.Errorf("export manifest %s has no file or checksum", path)
+	}
+	result.File = filepath.Join(filepath.Dir(path), filepath.Base(result.File))
+	return &result, nil
+}
+
+// Verify re-reads an export's file and checks it against the result of the export: its
+// size, SHA-256 checksum and number of events, decompressing an NDJSON file compressed
+// with gzip or zstd as it's read. The events of an encrypted export are
+// only counted when there are keys to decrypt it with; without them only its size and
+// checksum are checked. With keys that don't decrypt it, the error is seal.ErrNoKey.
+func Verify(result *Result, keys *seal.Keys) error {
+	file, err := os.Open(result.File)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if size != result.Bytes {
+		return fmt.Errorf("size is %d bytes, expected %d", size, result.Bytes)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != result.SHA256 {
+		return fmt.Errorf("checksum mismatch (SHA-256 %s, expected %s)", sum, result.SHA256)
+	}
+	if result.Encrypted && keys == nil {
+		return nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	var content io.Reader = file
+	if result.Encrypted {
+		if content, err = seal.NewReader(file, keys); err != nil {
+			return err
+		}
+	}
+	var events int64
+	switch result.Format {
+	case "avro":
+		events, err = countAvro(bufio.NewReader(content))
+	case "parquet":
+		if result.Encrypted {
+			events, err = countSealedParquet(content)
+		} else {
+			events, err = countParquet(file, size)
+		}
+	default:
+		events, err = countCompressedNDJSON(content)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read as %s: %w", result.Format, err)
+	}
+	if events != int64(result.Events) {
+		return fmt.Errorf("holds %d event(s), expected %d", events, result.Events)
+	}
+	return nil
+}
+
+// countCompressedNDJSON counts the events of an NDJSON file, decompressing it with the
+// codec it was compressed with, if any
+func countCompressedNDJSON(r io.Reader) (int64, error) {
+	content, err := compress.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close()
+	return countNDJSON(content)
+}
+
+// countNDJSON counts the events of an NDJSON file, checking each line is a JSON object
+func countNDJSON(r io.Reader) (int64, error) {
+	var count int64
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] != '{' || !json.Valid(line) {
+			return 0, fmt.Errorf("line %d is not a JSON object", count+1)
+		}
+		count++
+	}
+	return count, scanner.Err()
+}
+
+// countAvro counts the records of an Avro object container file from its block headers,
+// checking each block ends with the file's sync marker
+func countAvro(r *bufio.Reader) (int64, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil || string(magic[:]) != "Obj\x01" {
+		return 0, fmt.Errorf("not an Avro file")
+	}
+	// The header's metadata map, in blocks of entries
+	for {
+		entries, err := binary.ReadVarint(r)
+		if err != nil {
+			return 0, err
+		}
+		if entries == 0 {
+			break
+		}
+		if entries < 0 {
+			entries = -entries
+			if _, err := binary.ReadVarint(r); err != nil {
+				return 0, err
+			}
+		}
+		for i := int64(0); i < entries*2; i++ {
+			if err := skipAvroBytes(r); err != nil {
+				return 0, err
+			}
+		}
+	}
+	var sync [16]byte
+	if _, err := io.ReadFull(r, sync[:]); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for {
+		records, err := binary.ReadVarint(r)
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if err := skipAvroBytes(r); err != nil {
+			return 0, err
+		}
+		var marker [16]byte
+		if _, err := io.ReadFull(r, marker[:]); err != nil {
+			return 0, err
+		}
+		if marker != sync {
+			return 0, fmt.Errorf("block %d doesn't end with the sync marker", count)
+		}
+		count += records
+	}
+}
+
+// skipAvroBytes reads past a length-prefixed byte string
+func skipAvroBytes(r *bufio.Reader) error {
+	n, err := binary.ReadVarint(r)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("negative length")
+	}
+	_, err = r.Discard(int(n))
+	return err
+}
+
+// maxSealedFooter is the most of the end of a decrypted Parquet file kept to read its
+// footer from
+const maxSealedFooter = 16 << 20
+
+// countSealedParquet reads the number of rows from the footer of a decrypted Parquet
+// file, which is read through without keeping more than its start and its end
+func countSealedParquet(r io.Reader) (int64, error) {
+	var partial partialFile
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			partial.add(buf[:n])
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return countParquet(&partial, partial.size)
+}
+
+// partialFile is the first 4 bytes and up to the last maxSealedFooter bytes of a file
+// read through, which is all countParquet reads
+type partialFile struct {
+	head      []byte
+	tail      []byte
+	tailStart int64
+	size      int64
+}
+
+func (f *partialFile) add(p []byte) {
+	if len(f.head) < 4 {
+		f.head = append(f.head, p[:min(4-len(f.head), len(p))]...)
+	}
+	f.tail = append(f.tail, p...)
+	f.size += int64(len(p))
+	if excess := len(f.tail) - maxSealedFooter; excess > maxSealedFooter {
+		f.tail = append(f.tail[:0], f.tail[excess:]...)
+		f.tailStart += int64(excess)
+	}
+}
+
+func (f *partialFile) ReadAt(p []byte, off int64) (int, error) {
+	switch {
+	case off+int64(len(p)) <= int64(len(f.head)):
+		return copy(p, f.head[off:]), nil
+	case off >= f.tailStart && off+int64(len(p)) <= f.size:
+		return copy(p, f.tail[off-f.tailStart:]), nil
+	}
+	return 0, fmt.Errorf("footer is too large to check in an encrypted file")
+}
+
+// countParquet reads the number of rows from a Parquet file's footer
+func countParquet(file io.ReaderAt, size int64) (int64, error) {
+	var head [4]byte
+	var tail [8]byte
+	if size < 12 {
+		return 0, fmt.Errorf("not a Parquet file")
+	}
+	if _, err := file.ReadAt(head[:], 0); err != nil {
+		return 0, err
+	}
+	if _, err := file.ReadAt(tail[:], size-8); err != nil {
+		return 0, err
+	}
+	if string(head[:]) != "PAR1" || string(tail[4:]) != "PAR1" {
+		return 0, fmt.Errorf("not a Parquet file")
+	}
+	length := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if length > size-12 {
+		return 0, fmt.Errorf("footer length %d is past the start of the file", length)
+	}
+	footer := make([]byte, length)
+	if _, err := file.ReadAt(footer, size-8-length); err != nil {
+		return 0, err
+	}
+
+	// FileMetaData's num_rows is field 3
+	reader := thriftReader{r: bytes.NewReader(footer)}
+	var lastID int16
+	for {
+		id, fieldType, err := reader.field(lastID)
+		if err != nil {
+			return 0, err
+		}
+		if fieldType == 0 {
+			return 0, fmt.Errorf("footer has no row count")
+		}
+		if id == 3 && fieldType == thriftI64 {
+			return reader.varint()
+		}
+		if err := reader.skip(fieldType); err != nil {
+			return 0, err
+		}
+		lastID = id
+	}
+}
+
+// IsManifest reports whether a file name is that of an export manifest
+func IsManifest(name string) bool {
+	return strings.HasSuffix(name, ManifestSuffix)
+}
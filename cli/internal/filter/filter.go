@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// andPattern separates the filters in an expression for ParseAll
+var andPattern = regexp.MustCompile(`(?i)\s+AND\s+|\s*&&\s*`)
+
+// Filter matches events against a single 'field:value' expression
+type Filter struct {
+	Field string
+	Value string
+}
+
+// Parse parses a filter expression of the form 'field:value' or 'field.path:value'
+func Parse(expr string) (*Filter, error) {
+	parts := strings.SplitN(expr, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid filter '%s' (expected 'field:value')", expr)
+	}
+
+	field := strings.TrimSpace(parts[0])
+	if field == "" {
+		return nil, fmt.Errorf("invalid filter '%s' (field cannot be empty)", expr)
+	}
+
+	return &Filter{Field: field, Value: strings.TrimSpace(parts[1])}, nil
+}
+
+// ParseAll parses filters joined by AND or &&, e.g. 'type=order.shipped AND payload.orderId=42'.
+// Each filter may use ':' or '=' between its field and value.
+func ParseAll(expr string) ([]*Filter, error) {
+	var filters []*Filter
+	for _, term := range andPattern.Split(strings.TrimSpace(expr), -1) {
+		if i := strings.IndexAny(term, ":="); i >= 0 && term[i] == '=' {
+			term = term[:i] + ":" + term[i+1:]
+		}
+		f, err := Parse(term)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// String formats the filter as 'field:value'
+func (f *Filter) String() string {
+	return f.Field + ":" + f.Value
+}
+
+// Match reports whether an event matches the filter
+func (f *Filter) Match(event client.Event) bool {
+	value, ok := Value(event, f.Field)
+	return ok && value == f.Value
+}
+
+// Value returns an event's value for a filter field ('type', 'id', 'correlationId',
+// 'causationId', 'metadata.path', 'payload.path' or a bare payload path), formatted as
+// a string, and whether the event has the field
+func Value(event client.Event, field string) (string, bool) {
+	switch {
+	case field == "type":
+		return event.Type, true
+	case field == "id":
+		return event.ID, true
+	case field == "correlationId":
+		return event.CorrelationID, event.CorrelationID != ""
+	case field == "causationId":
+		return event.CausationID, event.CausationID != ""
+	case strings.HasPrefix(field, "metadata."):
+		return payloadField(event.Metadata, strings.TrimPrefix(field, "metadata."))
+	case strings.HasPrefix(field, "payload."):
+		// Extract payload field path (e.g., "payload.email" -> "email")
+		return payloadField(event.Payload, strings.TrimPrefix(field, "payload."))
+	default:
+		// Try as direct payload field
+		return payloadField(event.Payload, field)
+	}
+}
+
+// Apply returns the events matching the filter
+func (f *Filter) Apply(events []client.Event) []client.Event {
+	filtered := make([]client.Event, 0)
+	for _, event := range events {
+		if f.Match(event) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// MatchAll reports whether an event matches every filter
+func MatchAll(filters []*Filter, event client.Event) bool {
+	for _, f := range filters {
+		if !f.Match(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// payloadField looks up a payload field by its dotted path
+func payloadField(payload map[string]interface{}, path string) (string, bool) {
+	// Handle nested paths (e.g., "user.email")
+	parts := strings.Split(path, ".")
+	current := payload
+
+	for i, part := range parts {
+		val, ok := current[part]
+		if !ok {
+			return "", false
+		}
+
+		// If this is the last part, convert the value to a string
+		if i == len(parts)-1 {
+			return fmt.Sprintf("%v", val), true
+		}
+
+		// Navigate deeper into nested objects
+		if nested, ok := val.(map[string]interface{}); ok {
+			current = nested
+		} else {
+			return "", false
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,178 @@
+package filter
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr  string
+		want  Filter
+		error string
+	}{
+		{expr: "type:order.placed", want: Filter{Field: "type", Value: "order.placed"}},
+		{expr: " payload.user.email : a@b.c ", want: Filter{Field: "payload.user.email", Value: "a@b.c"}},
+		{expr: "time:12:30", want: Filter{Field: "time", Value: "12:30"}},
+		{expr: "status:", want: Filter{Field: "status", Value: ""}},
+		{expr: "status", error: "expected 'field:value'"},
+		{expr: " :x", error: "field cannot be empty"},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.expr)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Parse(%q) error = %v, want one containing %q", tt.expr, err, tt.error)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if *f != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.expr, *f, tt.want)
+		}
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	filters, err := ParseAll("type=order.shipped AND payload.orderId:42 && region=eu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Filter{{"type", "order.shipped"}, {"payload.orderId", "42"}, {"region", "eu"}}
+	if len(filters) != len(want) {
+		t.Fatalf("ParseAll returned %d filters, want %d", len(filters), len(want))
+	}
+	for i, f := range filters {
+		if *f != want[i] {
+			t.Errorf("filter %d = %+v, want %+v", i, *f, want[i])
+		}
+	}
+}
+
+func TestValue(t *testing.T) {
+	event := client.Event{
+		ID:            "orders-1",
+		Type:          "order.placed",
+		CorrelationID: "c-1",
+		Metadata:      map[string]interface{}{"source": "web"},
+		Payload:       map[string]interface{}{"total": 42.5, "user": map[string]interface{}{"email": "a@b.c"}},
+	}
+	tests := []struct {
+		field string
+		want  string
+		found bool
+	}{
+		{"type", "order.placed", true},
+		{"id", "orders-1", true},
+		{"correlationId", "c-1", true},
+		{"causationId", "", false},
+		{"metadata.source", "web", true},
+		{"payload.user.email", "a@b.c", true},
+		{"user.email", "a@b.c", true},
+		{"total", "42.5", true},
+		{"total.cents", "", false},
+		{"payload.missing", "", false},
+	}
+	for _, tt := range tests {
+		got, found := Value(event, tt.field)
+		if got != tt.want || found != tt.found {
+			t.Errorf("Value(%q) = %q, %v, want %q, %v", tt.field, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+// FuzzParse checks that Parse never panics, and that a parsed filter formats to an
+// expression that parses back to the same filter
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"type:order.placed", "payload.a.b:1", " x : y ", "a:b:c", ":", "", "é:\t\"{}\\"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, expr string) {
+		parsed, err := Parse(expr)
+		if err != nil {
+			return
+		}
+		parsed.Match(client.Event{Payload: map[string]interface{}{"a": map[string]interface{}{"b": 1}}})
+
+		again, err := Parse(parsed.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", parsed.String(), err)
+		}
+		if *again != *parsed {
+			t.Fatalf("Parse(%q) = %+v, want %+v", parsed.String(), *again, *parsed)
+		}
+	})
+}
+
+// FuzzParseAll checks that ParseAll never panics and only returns filters with a field
+func FuzzParseAll(f *testing.F) {
+	for _, seed := range []string{"type=a AND b:c", "a:b && c=d", "AND", "a=b=c", " && "} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, expr string) {
+		filters, err := ParseAll(expr)
+		if err != nil {
+			return
+		}
+		for _, parsed := range filters {
+			if parsed.Field == "" {
+				t.Fatalf("ParseAll(%q) returned a filter with no field", expr)
+			}
+		}
+	})
+}
+
+// segment is a payload key or value: a non-empty run of characters that have no meaning
+// in filter expressions
+type segment string
+
+func (segment) Generate(r *rand.Rand, size int) reflect.Value {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789_"
+	b := make([]byte, 1+r.Intn(8))
+	for i := range b {
+		b[i] = chars[r.Intn(len(chars))]
+	}
+	return reflect.ValueOf(segment(b))
+}
+
+// TestMatchProperty checks that a filter on a payload path matches an event holding the
+// filter's value at that path, and doesn't match one holding another value
+func TestMatchProperty(t *testing.T) {
+	property := func(path []segment, value segment) bool {
+		if len(path) == 0 {
+			return true
+		}
+		keys := make([]string, len(path))
+		for i, key := range path {
+			keys[i] = string(key)
+		}
+		payload := map[string]interface{}{}
+		current := payload
+		for _, key := range keys[:len(keys)-1] {
+			nested := map[string]interface{}{}
+			current[key] = nested
+			current = nested
+		}
+		current[keys[len(keys)-1]] = string(value)
+		event := client.Event{ID: "t-1", Type: "generated", Payload: payload}
+
+		field := "payload." + strings.Join(keys, ".")
+		match, err := Parse(field + ":" + string(value))
+		if err != nil || !match.Match(event) {
+			return false
+		}
+		mismatch, err := Parse(field + ":" + string(value) + "x")
+		return err == nil && !mismatch.Match(event)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
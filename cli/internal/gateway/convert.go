@@ -0,0 +1,120 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/event-store/cli/internal/client"
+	eventstorev1 "github.com/event-store/cli/internal/gen/eventstore/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// toConnectError converts an error returned by the API client into a Connect error
+func toConnectError(err error) error {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return connect.NewError(connect.CodeUnavailable, err)
+	}
+
+	code := connect.CodeUnknown
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound || strings.HasSuffix(apiErr.Code, "NOT_FOUND"):
+		code = connect.CodeNotFound
+	case apiErr.StatusCode == http.StatusConflict:
+		code = connect.CodeAlreadyExists
+	case apiErr.StatusCode == http.StatusUnauthorized:
+		code = connect.CodeUnauthenticated
+	case apiErr.StatusCode == http.StatusForbidden:
+		code = connect.CodePermissionDenied
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		code = connect.CodeResourceExhausted
+	case apiErr.StatusCode >= 400 && apiErr.StatusCode < 500:
+		code = connect.CodeInvalidArgument
+	case apiErr.StatusCode == http.StatusServiceUnavailable:
+		code = connect.CodeUnavailable
+	case apiErr.StatusCode >= 500:
+		code = connect.CodeInternal
+	}
+	return connect.NewError(code, err)
+}
+
+// toStruct converts a JSON object to a google.protobuf.Struct, nil for none
+func toStruct(value map[string]interface{}) (*structpb.Struct, error) {
+	if value == nil {
+		return nil, nil
+	}
+	s, err := structpb.NewStruct(value)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to convert to a Struct: %w", err))
+	}
+	return s, nil
+}
+
+func toTopic(t client.Topic) (*eventstorev1.Topic, error) {
+	topic := &eventstorev1.Topic{
+		Name:       t.Name,
+		Sequence:   int32(t.Sequence),
+		Schemas:    make([]*eventstorev1.Schema, 0, len(t.Schemas)),
+		Partitions: int32(t.Partitions),
+		Labels:     t.Labels,
+	}
+	for _, s := range t.Schemas {
+		properties, err := toStruct(s.Properties)
+		if err != nil {
+			return nil, err
+		}
+		topic.Schemas = append(topic.Schemas, &eventstorev1.Schema{EventType: s.EventType, Type: s.Type, Properties: properties, Required: s.Required})
+	}
+	if t.Owner != nil {
+		topic.Owner = &eventstorev1.Owner{Owner: t.Owner.Owner, Team: t.Owner.Team, Contact: t.Owner.Contact}
+	}
+	return topic, nil
+}
+
+func toEvent(e client.Event) (*eventstorev1.Event, error) {
+	payload, err := toStruct(e.Payload)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := toStruct(e.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	event := &eventstorev1.Event{
+		Id:            e.ID,
+		Timestamp:     e.Timestamp,
+		Type:          e.Type,
+		Payload:       payload,
+		CorrelationId: e.CorrelationID,
+		CausationId:   e.CausationID,
+		Metadata:      metadata,
+	}
+	if e.Partition != nil {
+		partition := int32(*e.Partition)
+		event.Partition = &partition
+	}
+	return event, nil
+}
+
+func toConsumer(c client.Consumer) *eventstorev1.Consumer {
+	return &eventstorev1.Consumer{Id: c.ID, Callback: c.Callback, Topics: c.Topics, Description: c.Description, Labels: c.Labels}
+}
+
+func fromPublishEvent(e *eventstorev1.PublishEvent) client.EventPublishRequest {
+	request := client.EventPublishRequest{
+		Topic:          e.Topic,
+		Type:           e.Type,
+		Payload:        e.Payload.AsMap(),
+		PublishAt:      e.PublishAt,
+		IdempotencyKey: e.IdempotencyKey,
+		CorrelationID:  e.CorrelationId,
+		CausationID:    e.CausationId,
+	}
+	if e.Metadata != nil {
+		request.Metadata = e.Metadata.AsMap()
+	}
+	return request
+}
@@ -0,0 +1,190 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/event-store/cli/internal/client"
+	eventstorev1 "github.com/event-store/cli/internal/gen/eventstore/v1"
+	"github.com/event-store/cli/internal/gen/eventstore/v1/eventstorev1connect"
+)
+
+// maxMessageSize bounds the size of a single request message, after decompression
+const maxMessageSize = 4 << 20
+
+// Gateway serves the event store API as eventstore.v1.EventStoreService, over the
+// Connect, gRPC and gRPC-Web protocols
+type Gateway struct {
+	client       *client.Client
+	pollInterval time.Duration
+	allowOrigin  string
+	path         string
+	handler      http.Handler
+}
+
+// New creates a gateway backed by apiClient. TailEvents polls the store every pollInterval;
+// allowOrigin, if set, enables CORS for browser clients.
+func New(apiClient *client.Client, pollInterval time.Duration, allowOrigin string) *Gateway {
+	g := &Gateway{
+		client:       apiClient,
+		pollInterval: pollInterval,
+		allowOrigin:  allowOrigin,
+	}
+	g.path, g.handler = eventstorev1connect.NewEventStoreServiceHandler(g, connect.WithReadMaxBytes(maxMessageSize))
+	return g
+}
+
+// Procedures returns the fully-qualified procedure paths served by the gateway
+func (g *Gateway) Procedures() []string {
+	methods := eventstorev1.File_eventstore_v1_eventstore_proto.Services().ByName("EventStoreService").Methods()
+	procedures := make([]string, 0, methods.Len())
+	for i := 0; i < methods.Len(); i++ {
+		procedures = append(procedures, g.path+string(methods.Get(i).Name()))
+	}
+	return procedures
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if g.allowOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", g.allowOrigin)
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Encoding, Accept-Encoding, Connect-Protocol-Version, Connect-Timeout-Ms, Connect-Content-Encoding, Connect-Accept-Encoding, Grpc-Timeout, X-Grpc-Web, X-User-Agent")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Type, Content-Encoding, Connect-Content-Encoding, Grpc-Status, Grpc-Message, Grpc-Status-Details-Bin")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	g.handler.ServeHTTP(w, r)
+}
+
+func (g *Gateway) ListTopics(ctx context.Context, req *connect.Request[eventstorev1.ListTopicsRequest]) (*connect.Response[eventstorev1.ListTopicsResponse], error) {
+	topics, err := g.client.GetTopics()
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	result := &eventstorev1.ListTopicsResponse{Topics: make([]*eventstorev1.Topic, 0, len(topics))}
+	for _, t := range topics {
+		topic, err := toTopic(t)
+		if err != nil {
+			return nil, err
+		}
+		result.Topics = append(result.Topics, topic)
+	}
+	return connect.NewResponse(result), nil
+}
+
+func (g *Gateway) GetTopic(ctx context.Context, req *connect.Request[eventstorev1.GetTopicRequest]) (*connect.Response[eventstorev1.Topic], error) {
+	if req.Msg.Name == "" {
+		return nil, invalidArgument("name is required")
+	}
+	t, err := g.client.GetTopic(req.Msg.Name)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	topic, err := toTopic(*t)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(topic), nil
+}
+
+func (g *Gateway) ListEvents(ctx context.Context, req *connect.Request[eventstorev1.ListEventsRequest]) (*connect.Response[eventstorev1.ListEventsResponse], error) {
+	if req.Msg.Topic == "" {
+		return nil, invalidArgument("topic is required")
+	}
+	events, err := g.client.GetEvents(req.Msg.Topic, &client.EventsQuery{
+		SinceEventID: req.Msg.SinceEventId,
+		Date:         req.Msg.Date,
+		Limit:        int(req.Msg.Limit),
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	result := &eventstorev1.ListEventsResponse{Events: make([]*eventstorev1.Event, 0, len(events))}
+	for _, e := range events {
+		event, err := toEvent(e)
+		if err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, event)
+	}
+	return connect.NewResponse(result), nil
+}
+
+func (g *Gateway) PublishEvents(ctx context.Context, req *connect.Request[eventstorev1.PublishEventsRequest]) (*connect.Response[eventstorev1.PublishEventsResponse], error) {
+	if len(req.Msg.Events) == 0 {
+		return nil, invalidArgument("at least one event is required")
+	}
+	events := make([]client.EventPublishRequest, 0, len(req.Msg.Events))
+	for _, e := range req.Msg.Events {
+		events = append(events, fromPublishEvent(e))
+	}
+	eventIDs, err := g.client.PublishEvents(events)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&eventstorev1.PublishEventsResponse{EventIds: eventIDs}), nil
+}
+
+func (g *Gateway) ListConsumers(ctx context.Context, req *connect.Request[eventstorev1.ListConsumersRequest]) (*connect.Response[eventstorev1.ListConsumersResponse], error) {
+	consumers, err := g.client.GetConsumers()
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	result := &eventstorev1.ListConsumersResponse{Consumers: make([]*eventstorev1.Consumer, 0, len(consumers))}
+	for _, c := range consumers {
+		result.Consumers = append(result.Consumers, toConsumer(c))
+	}
+	return connect.NewResponse(result), nil
+}
+
+func (g *Gateway) GetHealth(ctx context.Context, req *connect.Request[eventstorev1.GetHealthRequest]) (*connect.Response[eventstorev1.Health], error) {
+	health, err := g.client.GetHealth()
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&eventstorev1.Health{
+		Status:             health.Status,
+		Consumers:          int32(health.Consumers),
+		RunningDispatchers: health.RunningDispatchers,
+	}), nil
+}
+
+func (g *Gateway) TailEvents(ctx context.Context, req *connect.Request[eventstorev1.TailEventsRequest], stream *connect.ServerStream[eventstorev1.Event]) error {
+	if req.Msg.Topic == "" {
+		return invalidArgument("topic is required")
+	}
+
+	lastEventID := req.Msg.SinceEventId
+	for {
+		events, err := g.client.GetEvents(req.Msg.Topic, &client.EventsQuery{SinceEventID: lastEventID})
+		if err != nil {
+			return toConnectError(err)
+		}
+		for _, e := range events {
+			event, err := toEvent(e)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+			lastEventID = e.ID
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(g.pollInterval):
+		}
+	}
+}
+
+func invalidArgument(message string) error {
+	return connect.NewError(connect.CodeInvalidArgument, errors.New(message))
+}
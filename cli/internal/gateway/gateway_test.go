@@ -0,0 +1,220 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/event-store/cli/internal/client"
+	eventstorev1 "github.com/event-store/cli/internal/gen/eventstore/v1"
+	"github.com/event-store/cli/internal/gen/eventstore/v1/eventstorev1connect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// fakeStore serves the event store API the gateway calls, with the events of an
+// "orders" topic
+type fakeStore struct {
+	mu        sync.Mutex
+	events    []client.Event
+	published []client.EventPublishRequest
+}
+
+func (s *fakeStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	orders := client.Topic{Name: "orders", Sequence: len(s.events), Schemas: []client.Schema{{EventType: "order.created", Type: "object", Properties: map[string]interface{}{"id": map[string]interface{}{"type": "string"}}}}}
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/topics":
+		json.NewEncoder(w).Encode(client.TopicsResponse{Topics: []client.Topic{orders}})
+	case r.Method == "GET" && r.URL.Path == "/topics/orders":
+		json.NewEncoder(w).Encode(orders)
+	case r.Method == "GET" && r.URL.Path == "/topics/orders/events":
+		events := s.events
+		for i, e := range s.events {
+			if e.ID == r.URL.Query().Get("sinceEventId") {
+				events = s.events[i+1:]
+			}
+		}
+		json.NewEncoder(w).Encode(client.EventsResponse{Events: events})
+	case r.Method == "POST" && r.URL.Path == "/events":
+		var events []client.EventPublishRequest
+		json.NewDecoder(r.Body).Decode(&events)
+		var ids []string
+		for _, e := range events {
+			s.published = append(s.published, e)
+			id := fmt.Sprintf("%s-%d", e.Topic, len(s.events)+1)
+			s.events = append(s.events, client.Event{ID: id, Type: e.Type, Payload: e.Payload, Timestamp: "2024-03-01T12:00:00Z"})
+			ids = append(ids, id)
+		}
+		json.NewEncoder(w).Encode(client.EventPublishResponse{EventIDs: ids})
+	case r.Method == "GET" && r.URL.Path == "/health":
+		json.NewEncoder(w).Encode(client.Health{Status: "healthy", Consumers: 1, RunningDispatchers: []string{"orders"}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"error": "TOPIC_NOT_FOUND", "message": "not found"}`)
+	}
+}
+
+// startGateway serves a gateway backed by a fakeStore, returning a client of it
+func startGateway(t *testing.T, opts ...connect.ClientOption) (eventstorev1connect.EventStoreServiceClient, *fakeStore, *httptest.Server) {
+	store := &fakeStore{}
+	backend := httptest.NewServer(store)
+	t.Cleanup(backend.Close)
+	// Over HTTP/2, which gRPC needs
+	server := httptest.NewUnstartedServer(New(client.NewClient(backend.URL), 10*time.Millisecond, "http://localhost:8000"))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return eventstorev1connect.NewEventStoreServiceClient(server.Client(), server.URL, opts...), store, server
+}
+
+func TestGateway(t *testing.T) {
+	for name, opts := range map[string][]connect.ClientOption{
+		"connect":         nil,
+		"connect JSON":    {connect.WithProtoJSON()},
+		"connect gzipped": {connect.WithSendGzip()},
+		"gRPC":            {connect.WithGRPC(), connect.WithSendGzip()},
+		"gRPC-Web":        {connect.WithGRPCWeb()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gateway, store, _ := startGateway(t, opts...)
+			ctx := context.Background()
+
+			payload, _ := structpb.NewStruct(map[string]interface{}{"id": "42", "total": 9.5})
+			published, err := gateway.PublishEvents(ctx, connect.NewRequest(&eventstorev1.PublishEventsRequest{Events: []*eventstorev1.PublishEvent{
+				{Topic: "orders", Type: "order.created", Payload: payload, IdempotencyKey: "k1"},
+			}}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := published.Msg.EventIds; len(ids) != 1 || ids[0] != "orders-1" {
+				t.Errorf("PublishEvents = %v, want [orders-1]", ids)
+			}
+			if p := store.published[0]; p.Topic != "orders" || p.IdempotencyKey != "k1" || p.Payload["total"] != 9.5 {
+				t.Errorf("published %+v", p)
+			}
+
+			events, err := gateway.ListEvents(ctx, connect.NewRequest(&eventstorev1.ListEventsRequest{Topic: "orders"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events.Msg.Events) != 1 || events.Msg.Events[0].Id != "orders-1" || events.Msg.Events[0].Payload.AsMap()["id"] != "42" {
+				t.Errorf("ListEvents = %v", events.Msg.Events)
+			}
+
+			topics, err := gateway.ListTopics(ctx, connect.NewRequest(&eventstorev1.ListTopicsRequest{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(topics.Msg.Topics) != 1 || topics.Msg.Topics[0].Name != "orders" || topics.Msg.Topics[0].Schemas[0].EventType != "order.created" {
+				t.Errorf("ListTopics = %v", topics.Msg.Topics)
+			}
+
+			health, err := gateway.GetHealth(ctx, connect.NewRequest(&eventstorev1.GetHealthRequest{}))
+			if err != nil || health.Msg.Status != "healthy" || health.Msg.Consumers != 1 {
+				t.Errorf("GetHealth = %v, %v", health, err)
+			}
+		})
+	}
+}
+
+func TestGatewayErrors(t *testing.T) {
+	gateway, _, _ := startGateway(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want connect.Code
+	}{
+		{"missing argument", func() error {
+			_, err := gateway.GetTopic(ctx, connect.NewRequest(&eventstorev1.GetTopicRequest{}))
+			return err
+		}, connect.CodeInvalidArgument},
+		{"not found", func() error {
+			_, err := gateway.GetTopic(ctx, connect.NewRequest(&eventstorev1.GetTopicRequest{Name: "missing"}))
+			return err
+		}, connect.CodeNotFound},
+		{"message too large", func() error {
+			payload, _ := structpb.NewStruct(map[string]interface{}{"data": strings.Repeat("x", maxMessageSize)})
+			_, err := gateway.PublishEvents(ctx, connect.NewRequest(&eventstorev1.PublishEventsRequest{Events: []*eventstorev1.PublishEvent{{Topic: "orders", Payload: payload}}}))
+			return err
+		}, connect.CodeResourceExhausted},
+	}
+	for _, test := range tests {
+		if err := test.call(); connect.CodeOf(err) != test.want {
+			t.Errorf("%s: error %v, want %s", test.name, err, test.want)
+		}
+	}
+
+	// A compressed message is limited by its size once decompressed
+	compressed, _, _ := startGateway(t, connect.WithSendGzip())
+	payload, _ := structpb.NewStruct(map[string]interface{}{"data": strings.Repeat("x", maxMessageSize)})
+	_, err := compressed.PublishEvents(ctx, connect.NewRequest(&eventstorev1.PublishEventsRequest{Events: []*eventstorev1.PublishEvent{{Topic: "orders", Payload: payload}}}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("compressed message too large: error %v, want resource_exhausted", err)
+	}
+}
+
+func TestTailEvents(t *testing.T) {
+	gateway, store, _ := startGateway(t)
+	store.events = []client.Event{{ID: "orders-1", Type: "order.created"}, {ID: "orders-2", Type: "order.created"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := gateway.TailEvents(ctx, connect.NewRequest(&eventstorev1.TailEventsRequest{Topic: "orders", SinceEventId: "orders-1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received []string
+	for len(received) < 2 && stream.Receive() {
+		received = append(received, stream.Msg().Id)
+		if len(received) == 1 {
+			// Published after the stream started, and picked up by its next poll
+			store.mu.Lock()
+			store.events = append(store.events, client.Event{ID: "orders-3", Type: "order.created"})
+			store.mu.Unlock()
+		}
+	}
+	if strings.Join(received, ",") != "orders-2,orders-3" {
+		t.Errorf("TailEvents received %v, %v, want orders-2 then orders-3", received, stream.Err())
+	}
+	cancel()
+	stream.Close()
+
+	stream, _ = gateway.TailEvents(context.Background(), connect.NewRequest(&eventstorev1.TailEventsRequest{}))
+	if stream.Receive() || connect.CodeOf(stream.Err()) != connect.CodeInvalidArgument {
+		t.Errorf("TailEvents without a topic: error %v, want invalid_argument", stream.Err())
+	}
+}
+
+func TestGatewayHTTP(t *testing.T) {
+	_, _, server := startGateway(t)
+	req, _ := http.NewRequest(http.MethodOptions, server.URL+"/eventstore.v1.EventStoreService/ListTopics", nil)
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != "http://localhost:8000" {
+		t.Errorf("preflight = %d, %v", resp.StatusCode, resp.Header)
+	}
+
+	resp, err = server.Client().Post(server.URL+"/eventstore.v1.EventStoreService/Missing", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown procedure = %d, want 404", resp.StatusCode)
+	}
+}
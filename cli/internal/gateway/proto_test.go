@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	eventstorev1 "github.com/event-store/cli/internal/gen/eventstore/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	protoComment = regexp.MustCompile(`//[^\n]*`)
+	protoMessage = regexp.MustCompile(`message (\w+) \{([^}]*)\}`)
+	protoField   = regexp.MustCompile(`^(repeated |optional )?(map<[^>]+>|[\w.]+) (\w+) = (\d+);$`)
+	protoRPC     = regexp.MustCompile(`rpc (\w+)\((\w+)\) returns \((stream )?(\w+)\);`)
+)
+
+// fieldType describes a generated field as the proto declares it
+func fieldType(field protoreflect.FieldDescriptor) string {
+	var typ string
+	switch {
+	case field.IsMap():
+		return "map<" + fieldType(field.MapKey()) + ", " + fieldType(field.MapValue()) + ">"
+	case field.Message() != nil:
+		typ = string(field.Message().FullName())
+		typ = strings.TrimPrefix(typ, string(eventstorev1.File_eventstore_v1_eventstore_proto.Package())+".")
+	default:
+		typ = field.Kind().String()
+	}
+	switch {
+	case field.IsList():
+		return "repeated " + typ
+	case field.HasOptionalKeyword():
+		return "optional " + typ
+	}
+	return typ
+}
+
+// TestProtoInSync checks that the generated code is up to date with the proto: the same
+// procedures and messages, with the same fields
+func TestProtoInSync(t *testing.T) {
+	data, err := os.ReadFile("../../proto/eventstore/v1/eventstore.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto := protoComment.ReplaceAllString(string(data), "")
+	file := eventstorev1.File_eventstore_v1_eventstore_proto
+
+	methods := file.Services().ByName("EventStoreService").Methods()
+	rpcs := protoRPC.FindAllStringSubmatch(proto, -1)
+	if len(rpcs) != methods.Len() || len(New(nil, 0, "").Procedures()) != methods.Len() {
+		t.Errorf("proto has %d rpcs, generated service %d", len(rpcs), methods.Len())
+	}
+	for _, rpc := range rpcs {
+		method := methods.ByName(protoreflect.Name(rpc[1]))
+		if method == nil {
+			t.Errorf("rpc %s isn't generated", rpc[1])
+			continue
+		}
+		if string(method.Input().Name()) != rpc[2] || string(method.Output().Name()) != rpc[4] || method.IsStreamingServer() != (rpc[3] != "") {
+			t.Errorf("rpc %s is generated as %s(%s) returns %s", rpc[1], method.Name(), method.Input().Name(), method.Output().Name())
+		}
+	}
+
+	messages := protoMessage.FindAllStringSubmatch(proto, -1)
+	if len(messages) != file.Messages().Len() {
+		t.Errorf("proto has %d messages, generated code %d", len(messages), file.Messages().Len())
+	}
+	for _, match := range messages {
+		name := match[1]
+		message := file.Messages().ByName(protoreflect.Name(name))
+		if message == nil {
+			t.Errorf("message %s isn't generated", name)
+			continue
+		}
+		var count int
+		for _, line := range strings.Split(match[2], "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			parsed := protoField.FindStringSubmatch(line)
+			if parsed == nil {
+				t.Errorf("message %s: can't parse field %q", name, line)
+				continue
+			}
+			count++
+			field := message.Fields().ByName(protoreflect.Name(parsed[3]))
+			if field == nil {
+				t.Errorf("message %s: field %s isn't generated", name, parsed[3])
+				continue
+			}
+			if want := parsed[1] + parsed[2]; fieldType(field) != want || strconv.Itoa(int(field.Number())) != parsed[4] {
+				t.Errorf("message %s: field %s is generated as %s = %d, want %s = %s", name, parsed[3], fieldType(field), field.Number(), want, parsed[4])
+			}
+		}
+		if count != message.Fields().Len() {
+			t.Errorf("message %s has %d fields, generated %d", name, count, message.Fields().Len())
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: eventstore/v1/eventstore.proto
+
+package eventstorev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/event-store/cli/internal/gen/eventstore/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// EventStoreServiceName is the fully-qualified name of the EventStoreService service.
+	EventStoreServiceName = "eventstore.v1.EventStoreService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// EventStoreServiceListTopicsProcedure is the fully-qualified name of the EventStoreService's
+	// ListTopics RPC.
+	EventStoreServiceListTopicsProcedure = "/eventstore.v1.EventStoreService/ListTopics"
+	// EventStoreServiceGetTopicProcedure is the fully-qualified name of the EventStoreService's
+	// GetTopic RPC.
+	EventStoreServiceGetTopicProcedure = "/eventstore.v1.EventStoreService/GetTopic"
+	// EventStoreServiceListEventsProcedure is the fully-qualified name of the EventStoreService's
+	// ListEvents RPC.
+	EventStoreServiceListEventsProcedure = "/eventstore.v1.EventStoreService/ListEvents"
+	// EventStoreServicePublishEventsProcedure is the fully-qualified name of the EventStoreService's
+	// PublishEvents RPC.
+	EventStoreServicePublishEventsProcedure = "/eventstore.v1.EventStoreService/PublishEvents"
+	// EventStoreServiceListConsumersProcedure is the fully-qualified name of the EventStoreService's
+	// ListConsumers RPC.
+	EventStoreServiceListConsumersProcedure = "/eventstore.v1.EventStoreService/ListConsumers"
+	// EventStoreServiceGetHealthProcedure is the fully-qualified name of the EventStoreService's
+	// GetHealth RPC.
+	EventStoreServiceGetHealthProcedure = "/eventstore.v1.EventStoreService/GetHealth"
+	// EventStoreServiceTailEventsProcedure is the fully-qualified name of the EventStoreService's
+	// TailEvents RPC.
+	EventStoreServiceTailEventsProcedure = "/eventstore.v1.EventStoreService/TailEvents"
+)
+
+// EventStoreServiceClient is a client for the eventstore.v1.EventStoreService service.
+type EventStoreServiceClient interface {
+	ListTopics(context.Context, *connect.Request[v1.ListTopicsRequest]) (*connect.Response[v1.ListTopicsResponse], error)
+	GetTopic(context.Context, *connect.Request[v1.GetTopicRequest]) (*connect.Response[v1.Topic], error)
+	ListEvents(context.Context, *connect.Request[v1.ListEventsRequest]) (*connect.Response[v1.ListEventsResponse], error)
+	PublishEvents(context.Context, *connect.Request[v1.PublishEventsRequest]) (*connect.Response[v1.PublishEventsResponse], error)
+	ListConsumers(context.Context, *connect.Request[v1.ListConsumersRequest]) (*connect.Response[v1.ListConsumersResponse], error)
+	GetHealth(context.Context, *connect.Request[v1.GetHealthRequest]) (*connect.Response[v1.Health], error)
+	// Streams events appended to a topic, starting after since_event_id.
+	TailEvents(context.Context, *connect.Request[v1.TailEventsRequest]) (*connect.ServerStreamForClient[v1.Event], error)
+}
+
+// NewEventStoreServiceClient constructs a client for the eventstore.v1.EventStoreService service.
+// By default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped
+// responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewEventStoreServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) EventStoreServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	eventStoreServiceMethods := v1.File_eventstore_v1_eventstore_proto.Services().ByName("EventStoreService").Methods()
+	return &eventStoreServiceClient{
+		listTopics: connect.NewClient[v1.ListTopicsRequest, v1.ListTopicsResponse](
+			httpClient,
+			baseURL+EventStoreServiceListTopicsProcedure,
+			connect.WithSchema(eventStoreServiceMethods.ByName("ListTopics")),
+			connect.WithClientOptions(opts...),
+		),
+		getTopic: connect.NewClient[v1.GetTopicRequest, v1.Topic](
+			httpClient,
+			baseURL+EventStoreServiceGetTopicProcedure,
+			connect.WithSchema(eventStoreServiceMethods.ByName("GetTopic")),
+			connect.WithClientOptions(opts...),
+		),
+		listEvents: connect.NewClient[v1.ListEventsRequest, v1.ListEventsResponse](
+			httpClient,
+			baseURL+EventStoreServiceListEventsProcedure,
+			connect.WithSchema(eventStoreServiceMethods.ByName("ListEvents")),
+			connect.WithClientOptions(opts...),
+		),
+		publishEvents: connect.NewClient[v1.PublishEventsRequest, v1.PublishEventsResponse](
+			httpClient,
+			baseURL+EventStoreServicePublishEventsProcedure,
+			connect.WithSchema(eventStoreServiceMethods.ByName("PublishEvents")),
+			connect.WithClientOptions(opts...),
+		),
+		listConsumers: connect.NewClient[v1.ListConsumersRequest, v1.ListConsumersResponse](
+			httpClient,
+			baseURL+EventStoreServiceListConsumersProcedure,
+			connect.WithSchema(eventStoreServiceMethods.ByName("ListConsumers")),
+			connect.WithClientOptions(opts...),
+		),
+		getHealth: connect.NewClient[v1.GetHealthRequest, v1.Health](
+			httpClient,
+			baseURL+EventStoreServiceGetHealthProcedure,
+			connect.WithSchema(eventStoreServiceMethods.ByName("GetHealth")),
+			connect.WithClientOptions(opts...),
+		),
+		tailEvents: connect.NewClient[v1.TailEventsRequest, v1.Event](
+			httpClient,
+			baseURL+EventStoreServiceTailEventsProcedure,
+			connect.WithSchema(eventStoreServiceMethods.ByName("TailEvents")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// eventStoreServiceClient implements EventStoreServiceClient.
+type eventStoreServiceClient struct {
+	listTopics    *connect.Client[v1.ListTopicsRequest, v1.ListTopicsResponse]
+	getTopic      *connect.Client[v1.GetTopicRequest, v1.Topic]
+	listEvents    *connect.Client[v1.ListEventsRequest, v1.ListEventsResponse]
+	publishEvents *connect.Client[v1.PublishEventsRequest, v1.PublishEventsResponse]
+	listConsumers *connect.Client[v1.ListConsumersRequest, v1.ListConsumersResponse]
+	getHealth     *connect.Client[v1.GetHealthRequest, v1.Health]
+	tailEvents    *connect.Client[v1.TailEventsRequest, v1.Event]
+}
+
+// ListTopics calls eventstore.v1.EventStoreService.ListTopics.
+func (c *eventStoreServiceClient) ListTopics(ctx context.Context, req *connect.Request[v1.ListTopicsRequest]) (*connect.Response[v1.ListTopicsResponse], error) {
+	return c.listTopics.CallUnary(ctx, req)
+}
+
+// GetTopic calls eventstore.v1.EventStoreService.GetTopic.
+func (c *eventStoreServiceClient) GetTopic(ctx context.Context, req *connect.Request[v1.GetTopicRequest]) (*connect.Response[v1.Topic], error) {
+	return c.getTopic.CallUnary(ctx, req)
+}
+
+// ListEvents calls eventstore.v1.EventStoreService.ListEvents.
+func (c *eventStoreServiceClient) ListEvents(ctx context.Context, req *connect.Request[v1.ListEventsRequest]) (*connect.Response[v1.ListEventsResponse], error) {
+	return c.listEvents.CallUnary(ctx, req)
+}
+
+// PublishEvents calls eventstore.v1.EventStoreService.PublishEvents.
+func (c *eventStoreServiceClient) PublishEvents(ctx context.Context, req *connect.Request[v1.PublishEventsRequest]) (*connect.Response[v1.PublishEventsResponse], error) {
+	return c.publishEvents.CallUnary(ctx, req)
+}
+
+// ListConsumers calls eventstore.v1.EventStoreService.ListConsumers.
+func (c *eventStoreServiceClient) ListConsumers(ctx context.Context, req *connect.Request[v1.ListConsumersRequest]) (*connect.Response[v1.ListConsumersResponse], error) {
+	return c.listConsumers.CallUnary(ctx, req)
+}
+
+// GetHealth calls eventstore.v1.EventStoreService.GetHealth.
+func (c *eventStoreServiceClient) GetHealth(ctx context.Context, req *connect.Request[v1.GetHealthRequest]) (*connect.Response[v1.Health], error) {
+	return c.getHealth.CallUnary(ctx, req)
+}
+
+// TailEvents calls eventstore.v1.EventStoreService.TailEvents.
+func (c *eventStoreServiceClient) TailEvents(ctx context.Context, req *connect.Request[v1.TailEventsRequest]) (*connect.ServerStreamForClient[v1.Event], error) {
+	return c.tailEvents.CallServerStream(ctx, req)
+}
+
+// EventStoreServiceHandler is an implementation of the eventstore.v1.EventStoreService service.
+type EventStoreServiceHandler interface {
+	ListTopics(context.Context, *connect.Request[v1.ListTopicsRequest]) (*connect.Response[v1.ListTopicsResponse], error)
+	GetTopic(context.Context, *connect.Request[v1.GetTopicRequest]) (*connect.Response[v1.Topic], error)
+	ListEvents(context.Context, *connect.Request[v1.ListEventsRequest]) (*connect.Response[v1.ListEventsResponse], error)
+	PublishEvents(context.Context, *connect.Request[v1.PublishEventsRequest]) (*connect.Response[v1.PublishEventsResponse], error)
+	ListConsumers(context.Context, *connect.Request[v1.ListConsumersRequest]) (*connect.Response[v1.ListConsumersResponse], error)
+	GetHealth(context.Context, *connect.Request[v1.GetHealthRequest]) (*connect.Response[v1.Health], error)
+	// Streams events appended to a topic, starting after since_event_id.
+	TailEvents(context.Context, *connect.Request[v1.TailEventsRequest], *connect.ServerStream[v1.Event]) error
+}
+
+// NewEventStoreServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewEventStoreServiceHandler(svc EventStoreServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	eventStoreServiceMethods := v1.File_eventstore_v1_eventstore_proto.Services().ByName("EventStoreService").Methods()
+	eventStoreServiceListTopicsHandler := connect.NewUnaryHandler(
+		EventStoreServiceListTopicsProcedure,
+		svc.ListTopics,
+		connect.WithSchema(eventStoreServiceMethods.ByName("ListTopics")),
+		connect.WithHandlerOptions(opts...),
+	)
+	eventStoreServiceGetTopicHandler := connect.NewUnaryHandler(
+		EventStoreServiceGetTopicProcedure,
+		svc.GetTopic,
+		connect.WithSchema(eventStoreServiceMethods.ByName("GetTopic")),
+		connect.WithHandlerOptions(opts...),
+	)
+	eventStoreServiceListEventsHandler := connect.NewUnaryHandler(
+		EventStoreServiceListEventsProcedure,
+		svc.ListEvents,
+		connect.WithSchema(eventStoreServiceMethods.ByName("ListEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	eventStoreServicePublishEventsHandler := connect.NewUnaryHandler(
+		EventStoreServicePublishEventsProcedure,
+		svc.PublishEvents,
+		connect.WithSchema(eventStoreServiceMethods.ByName("PublishEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	eventStoreServiceListConsumersHandler := connect.NewUnaryHandler(
+		EventStoreServiceListConsumersProcedure,
+		svc.ListConsumers,
+		connect.WithSchema(eventStoreServiceMethods.ByName("ListConsumers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	eventStoreServiceGetHealthHandler := connect.NewUnaryHandler(
+		EventStoreServiceGetHealthProcedure,
+		svc.GetHealth,
+		connect.WithSchema(eventStoreServiceMethods.ByName("GetHealth")),
+		connect.WithHandlerOptions(opts...),
+	)
+	eventStoreServiceTailEventsHandler := connect.NewServerStreamHandler(
+		EventStoreServiceTailEventsProcedure,
+		svc.TailEvents,
+		connect.WithSchema(eventStoreServiceMethods.ByName("TailEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/eventstore.v1.EventStoreService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EventStoreServiceListTopicsProcedure:
+			eventStoreServiceListTopicsHandler.ServeHTTP(w, r)
+		case EventStoreServiceGetTopicProcedure:
+			eventStoreServiceGetTopicHandler.ServeHTTP(w, r)
+		case EventStoreServiceListEventsProcedure:
+			eventStoreServiceListEventsHandler.ServeHTTP(w, r)
+		case EventStoreServicePublishEventsProcedure:
+			eventStoreServicePublishEventsHandler.ServeHTTP(w, r)
+		case EventStoreServiceListConsumersProcedure:
+			eventStoreServiceListConsumersHandler.ServeHTTP(w, r)
+		case EventStoreServiceGetHealthProcedure:
+			eventStoreServiceGetHealthHandler.ServeHTTP(w, r)
+		case EventStoreServiceTailEventsProcedure:
+			eventStoreServiceTailEventsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedEventStoreServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedEventStoreServiceHandler struct{}
+
+func (UnimplementedEventStoreServiceHandler) ListTopics(context.Context, *connect.Request[v1.ListTopicsRequest]) (*connect.Response[v1.ListTopicsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("eventstore.v1.EventStoreService.ListTopics is not implemented"))
+}
+
+func (UnimplementedEventStoreServiceHandler) GetTopic(context.Context, *connect.Request[v1.GetTopicRequest]) (*connect.Response[v1.Topic], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("eventstore.v1.EventStoreService.GetTopic is not implemented"))
+}
+
+func (UnimplementedEventStoreServiceHandler) ListEvents(context.Context, *connect.Request[v1.ListEventsRequest]) (*connect.Response[v1.ListEventsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("eventstore.v1.EventStoreService.ListEvents is not implemented"))
+}
+
+func (UnimplementedEventStoreServiceHandler) PublishEvents(context.Context, *connect.Request[v1.PublishEventsRequest]) (*connect.Response[v1.PublishEventsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("eventstore.v1.EventStoreService.PublishEvents is not implemented"))
+}
+
+func (UnimplementedEventStoreServiceHandler) ListConsumers(context.Context, *connect.Request[v1.ListConsumersRequest]) (*connect.Response[v1.ListConsumersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("eventstore.v1.EventStoreService.ListConsumers is not implemented"))
+}
+
+func (UnimplementedEventStoreServiceHandler) GetHealth(context.Context, *connect.Request[v1.GetHealthRequest]) (*connect.Response[v1.Health], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("eventstore.v1.EventStoreService.GetHealth is not implemented"))
+}
+
+func (UnimplementedEventStoreServiceHandler) TailEvents(context.Context, *connect.Request[v1.TailEventsRequest], *connect.ServerStream[v1.Event]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("eventstore.v1.EventStoreService.TailEvents is not implemented"))
+}
@@ -0,0 +1,292 @@
+package generate
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Generator produces fake values conforming to JSON schemas. Values are chosen by
+// schema type and format, with faker-style values for well-known property names.
+// Generators created with the same seed produce the same values.
+type Generator struct {
+	rand *rand.Rand
+	base time.Time
+}
+
+// New creates a generator seeded with seed
+func New(seed int64) *Generator {
+	return &Generator{
+		rand: rand.New(rand.NewSource(seed)),
+		base: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Event generates an event of the schema's event type with a conforming payload
+func (g *Generator) Event(topic string, schema client.Schema) client.EventPublishRequest {
+	return client.EventPublishRequest{
+		Topic:   topic,
+		Type:    schema.EventType,
+		Payload: g.object(schema.Properties, schema.Required),
+	}
+}
+
+// Value generates a value for a JSON schema. name is the property the value is for,
+// used to pick realistic strings such as emails and names; it may be empty.
+func (g *Generator) Value(name string, schema map[string]interface{}) interface{} {
+	if value, ok := schema["const"]; ok {
+		return value
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[g.rand.Intn(len(values))]
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[key].([]interface{}); ok && len(options) > 0 {
+			if option, ok := options[g.rand.Intn(len(options))].(map[string]interface{}); ok {
+				return g.Value(name, option)
+			}
+		}
+	}
+
+	switch SchemaType(schema) {
+	case "object":
+		properties, _ := schema["properties"].(map[string]interface{})
+		return g.object(properties, stringList(schema["required"]))
+	case "array":
+		return g.array(name, schema)
+	case "integer":
+		return g.integer(schema)
+	case "number":
+		return g.number(schema)
+	case "boolean":
+		return g.rand.Intn(2) == 1
+	case "null":
+		return nil
+	default:
+		return g.string(name, schema)
+	}
+}
+
+// object generates every required property and roughly half of the optional ones
+func (g *Generator) object(properties map[string]interface{}, required []string) map[string]interface{} {
+	isRequired := make(map[string]bool, len(required))
+	for _, name := range required {
+		isRequired[name] = true
+	}
+
+	// Iterate in a fixed order so a seed always produces the same payload
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if !isRequired[name] && g.rand.Intn(2) == 0 {
+			continue
+		}
+		schema, _ := properties[name].(map[string]interface{})
+		result[name] = g.Value(name, schema)
+	}
+	return result
+}
+
+func (g *Generator) array(name string, schema map[string]interface{}) []interface{} {
+	minItems := intKeyword(schema, "minItems", 1)
+	maxItems := intKeyword(schema, "maxItems", minItems+3)
+	if maxItems < minItems {
+		maxItems = minItems
+	}
+
+	items, _ := schema["items"].(map[string]interface{})
+	n := minItems + g.rand.Intn(maxItems-minItems+1)
+	result := make([]interface{}, n)
+	for i := range result {
+		result[i] = g.Value(strings.TrimSuffix(name, "s"), items)
+	}
+	return result
+}
+
+func (g *Generator) integer(schema map[string]interface{}) int64 {
+	minimum, maximum := bounds(schema, 0, 1000)
+	low, high := int64(math.Ceil(minimum)), int64(math.Floor(maximum))
+	if high < low {
+		return low
+	}
+	return low + g.rand.Int63n(high-low+1)
+}
+
+func (g *Generator) number(schema map[string]interface{}) float64 {
+	minimum, maximum := bounds(schema, 0, 1000)
+	value := minimum + g.rand.Float64()*(maximum-minimum)
+	return math.Round(value*100) / 100
+}
+
+func (g *Generator) string(name string, schema map[string]interface{}) string {
+	value := g.formatted(name, schema)
+
+	minLength := intKeyword(schema, "minLength", 0)
+	maxLength := intKeyword(schema, "maxLength", 0)
+	for len(value) < minLength {
+		value += string(rune('a' + g.rand.Intn(26)))
+	}
+	if maxLength > 0 && len(value) > maxLength {
+		value = value[:maxLength]
+	}
+	return value
+}
+
+// formatted picks a string by the schema's format, falling back to the property name
+func (g *Generator) formatted(name string, schema map[string]interface{}) string {
+	format, _ := schema["format"].(string)
+	switch format {
+	case "date-time":
+		return g.timestamp().Format(time.RFC3339)
+	case "date":
+		return g.timestamp().Format("2006-01-02")
+	case "time":
+		return g.timestamp().Format("15:04:05")
+	case "email", "idn-email":
+		return g.email()
+	case "uuid":
+		return g.uuid()
+	case "uri", "url", "iri":
+		return fmt.Sprintf("https://%s/%s", g.pick(domains), g.pick(words))
+	case "hostname", "idn-hostname":
+		return g.pick(domains)
+	case "ipv4":
+		return fmt.Sprintf("%d.%d.%d.%d", 10, g.rand.Intn(256), g.rand.Intn(256), 1+g.rand.Intn(254))
+	case "ipv6":
+		return fmt.Sprintf("fd00::%x:%x", g.rand.Intn(0x10000), g.rand.Intn(0x10000))
+	}
+
+	lower := strings.ToLower(name)
+	switch {
+	case lower == "id" || strings.HasSuffix(lower, "id"):
+		return g.uuid()
+	case strings.Contains(lower, "email"):
+		return g.email()
+	case lower == "firstname" || lower == "first_name":
+		return g.pick(firstNames)
+	case lower == "lastname" || lower == "last_name" || lower == "surname":
+		return g.pick(lastNames)
+	case strings.Contains(lower, "name"):
+		return g.pick(firstNames) + " " + g.pick(lastNames)
+	case strings.Contains(lower, "phone"):
+		return fmt.Sprintf("+1-555-%03d-%04d", g.rand.Intn(1000), g.rand.Intn(10000))
+	case strings.Contains(lower, "city"):
+		return g.pick(cities)
+	case strings.Contains(lower, "country"):
+		return g.pick(countries)
+	case strings.Contains(lower, "currency"):
+		return g.pick(currencies)
+	case strings.Contains(lower, "status"):
+		return g.pick(statuses)
+	case strings.Contains(lower, "url") || strings.Contains(lower, "website"):
+		return fmt.Sprintf("https://%s/%s", g.pick(domains), g.pick(words))
+	case strings.HasSuffix(lower, "at") || strings.Contains(lower, "date") || strings.Contains(lower, "time"):
+		return g.timestamp().Format(time.RFC3339)
+	default:
+		return g.pick(words) + "-" + g.pick(words)
+	}
+}
+
+func (g *Generator) timestamp() time.Time {
+	return g.base.Add(time.Duration(g.rand.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+func (g *Generator) email() string {
+	return fmt.Sprintf("%s.%s@%s", strings.ToLower(g.pick(firstNames)), strings.ToLower(g.pick(lastNames)), g.pick(domains))
+}
+
+func (g *Generator) uuid() string {
+	b := make([]byte, 16)
+	g.rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rand.Intn(len(values))]
+}
+
+// SchemaType returns the schema's type, taking the first non-null type of a type list
+func SchemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, option := range t {
+			if s, ok := option.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return "string"
+}
+
+// bounds returns the inclusive range allowed by minimum/maximum and their exclusive forms
+func bounds(schema map[string]interface{}, defaultMin, defaultMax float64) (float64, float64) {
+	minimum, hasMin := floatKeyword(schema, "minimum")
+	if exclusive, ok := floatKeyword(schema, "exclusiveMinimum"); ok {
+		minimum, hasMin = exclusive+1, true
+	}
+	maximum, hasMax := floatKeyword(schema, "maximum")
+	if exclusive, ok := floatKeyword(schema, "exclusiveMaximum"); ok {
+		maximum, hasMax = exclusive-1, true
+	}
+
+	switch {
+	case !hasMin && !hasMax:
+		return defaultMin, defaultMax
+	case !hasMin:
+		return math.Min(defaultMin, maximum), maximum
+	case !hasMax:
+		return minimum, minimum + (defaultMax - defaultMin)
+	}
+	return minimum, maximum
+}
+
+func floatKeyword(schema map[string]interface{}, key string) (float64, bool) {
+	value, ok := schema[key].(float64)
+	return value, ok
+}
+
+func intKeyword(schema map[string]interface{}, key string, fallback int) int {
+	if value, ok := floatKeyword(schema, key); ok {
+		return int(value)
+	}
+	return fallback
+}
+
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+var (
+	firstNames = []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank", "Grace", "Heidi", "Ivan", "Judy", "Mallory", "Niaj", "Olivia", "Peggy", "Rupert", "Sybil", "Trent", "Victor", "Walter"}
+	lastNames  = []string{"Anderson", "Brown", "Chen", "Davis", "Evans", "Garcia", "Khan", "Lee", "Martin", "Nguyen", "Okafor", "Patel", "Rossi", "Smith", "Tanaka", "Williams"}
+	domains    = []string{"example.com", "example.org", "example.net", "test.example"}
+	cities     = []string{"Amsterdam", "Berlin", "Cape Town", "Lisbon", "London", "Melbourne", "Nairobi", "New York", "Paris", "Sydney", "Tokyo", "Toronto"}
+	countries  = []string{"AU", "CA", "DE", "FR", "GB", "JP", "KE", "NL", "NZ", "PT", "US", "ZA"}
+	currencies = []string{"AUD", "CAD", "EUR", "GBP", "JPY", "USD", "ZAR"}
+	statuses   = []string{"active", "pending", "completed", "cancelled"}
+	words      = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa"}
+)
@@ -0,0 +1,51 @@
+package generate
+
+import (
+	"github.com/event-store/cli/internal/client"
+)
+
+// Skeleton returns a payload of an event type to be filled in by hand: every property of
+// its schema, those of nested objects too, set to its constant, default or first allowed
+// value, or else to an empty value of its type, such as "", 0 or its minimum, or []
+func Skeleton(schema client.Schema) map[string]interface{} {
+	return skeletonObject(schema.Properties)
+}
+
+func skeletonObject(properties map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(properties))
+	for name, property := range properties {
+		schema, _ := property.(map[string]interface{})
+		result[name] = skeletonValue(schema)
+	}
+	return result
+}
+
+func skeletonValue(schema map[string]interface{}) interface{} {
+	for _, key := range []string{"const", "default"} {
+		if value, ok := schema[key]; ok {
+			return value
+		}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[0]
+	}
+
+	switch SchemaType(schema) {
+	case "object":
+		properties, _ := schema["properties"].(map[string]interface{})
+		return skeletonObject(properties)
+	case "array":
+		return []interface{}{}
+	case "integer", "number":
+		if minimum, ok := floatKeyword(schema, "minimum"); ok {
+			return minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	case "null":
+		return nil
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,124 @@
+// Package guard checks events before they are published for mistakes the event store
+// would let into a topic's history, or only reject part way through a large import:
+// oversized payloads, event types the topic has no schema for and, strictly, payload
+// fields the type's schema doesn't declare.
+package guard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Options configures the checks
+type Options struct {
+	MaxPayloadBytes int  // largest payload, encoded as JSON; 0 for no limit
+	Strict          bool // also check for payload fields the schema doesn't declare
+}
+
+// Checker checks events against their topics' schemas, fetching each topic once
+type Checker struct {
+	apiClient *client.Client
+	options   Options
+	topics    map[string]*client.Topic // nil for topics that don't exist
+}
+
+// NewChecker creates a checker of events to be published with a client
+func NewChecker(apiClient *client.Client, options Options) *Checker {
+	return &Checker{apiClient: apiClient, options: options, topics: map[string]*client.Topic{}}
+}
+
+// Check returns a problem per event found wrong, indexed into events. It fails only when
+// a topic can't be fetched.
+func (c *Checker) Check(events []client.EventPublishRequest) ([]client.EventFailure, error) {
+	var problems []client.EventFailure
+	for i, event := range events {
+		problem, err := c.check(event)
+		if err != nil {
+			return nil, err
+		}
+		if problem != "" {
+			problems = append(problems, client.EventFailure{Index: i, Error: problem})
+		}
+	}
+	return problems, nil
+}
+
+// check returns what's wrong with an event, or ""
+func (c *Checker) check(event client.EventPublishRequest) (string, error) {
+	if limit := c.options.MaxPayloadBytes; limit > 0 {
+		encoded, err := json.Marshal(event.Payload)
+		if err != nil {
+			return "", err
+		}
+		if len(encoded) > limit {
+			return fmt.Sprintf("payload is %d bytes, over the limit of %d", len(encoded), limit), nil
+		}
+	}
+
+	topic, err := c.topic(event.Topic)
+	if err != nil {
+		return "", err
+	}
+	if topic == nil {
+		// The event store reports it
+		return "", nil
+	}
+	var schema *client.Schema
+	for i := range topic.Schemas {
+		if topic.Schemas[i].EventType == event.Type {
+			schema = &topic.Schemas[i]
+		}
+	}
+	if schema == nil {
+		return fmt.Sprintf("topic '%s' has no schema for type '%s'", event.Topic, event.Type), nil
+	}
+	if c.options.Strict {
+		if unknown := UnknownFields("", event.Payload, schema.Properties); len(unknown) > 0 {
+			return fmt.Sprintf("payload has fields not in the schema of '%s': %s", event.Type, strings.Join(unknown, ", ")), nil
+		}
+	}
+	return "", nil
+}
+
+// topic returns a topic, fetched the first time it's asked for, or nil if it doesn't exist
+func (c *Checker) topic(name string) (*client.Topic, error) {
+	if topic, ok := c.topics[name]; ok {
+		return topic, nil
+	}
+	topic, err := c.apiClient.GetTopic(name)
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		topic, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.topics[name] = topic
+	return topic, nil
+}
+
+// UnknownFields returns the dotted paths of the fields of a payload, or of objects in it
+// whose schema declares their properties, that aren't declared, sorted
+func UnknownFields(prefix string, payload map[string]interface{}, properties map[string]interface{}) []string {
+	var unknown []string
+	for name, value := range payload {
+		property, declared := properties[name]
+		if !declared {
+			unknown = append(unknown, prefix+name)
+			continue
+		}
+		nested, _ := property.(map[string]interface{})
+		nestedProperties, _ := nested["properties"].(map[string]interface{})
+		if object, ok := value.(map[string]interface{}); ok && nestedProperties != nil {
+			unknown = append(unknown, UnknownFields(prefix+name+".", object, nestedProperties)...)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,94 @@
+// Package history reconstructs an event-sourced aggregate from a topic: the events whose
+// aggregate ID field has a given value, in the order they were published, and the
+// aggregate's state folded from them by a reducer.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/transform"
+)
+
+// History is an aggregate's events, and its state when it was folded by a reducer
+type History struct {
+	Topic       string         `json:"topic"`
+	IDField     string         `json:"idField"`
+	AggregateID string         `json:"aggregateId"`
+	Events      []client.Event `json:"events"`
+	State       interface{}    `json:"state,omitempty"` // the folded state object, nil when not folded
+}
+
+// Collector gathers the events of one aggregate
+type Collector struct {
+	filter  *filter.Filter
+	history *History
+}
+
+// New creates a collector for the events of a topic whose idField (a filter field such
+// as 'payload.orderId') is id
+func New(topic, idField, id string) *Collector {
+	return &Collector{
+		filter:  &filter.Filter{Field: idField, Value: id},
+		history: &History{Topic: topic, IDField: idField, AggregateID: id, Events: []client.Event{}},
+	}
+}
+
+// Add adds the events of the aggregate among a page of the topic's events
+func (c *Collector) Add(events []client.Event) {
+	c.history.Events = append(c.history.Events, c.filter.Apply(events)...)
+}
+
+// History returns the events collected
+func (c *Collector) History() *History {
+	return c.history
+}
+
+// Reducer folds events into an aggregate's state with a jq expression or Go template
+// (see the transform package), given an object with the state so far as 'state' and
+// the event as 'event'. The state is what it returns; no output leaves it unchanged.
+type Reducer struct {
+	transformer transform.Transformer
+}
+
+// NewReducer creates a reducer for an expression
+func NewReducer(expr string) (*Reducer, error) {
+	transformer, err := transform.New(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Reducer{transformer: transformer}, nil
+}
+
+// Fold sets the history's state to the result of folding its events, oldest first,
+// starting from initial
+func (r *Reducer) Fold(h *History, initial map[string]interface{}) error {
+	state := initial
+	for _, event := range h.Events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		var input map[string]interface{}
+		if err := json.Unmarshal(data, &input); err != nil {
+			return err
+		}
+
+		next, keep, err := r.transformer.Transform(map[string]interface{}{"state": state, "event": input})
+		if err != nil {
+			return fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		if keep {
+			state = next
+		}
+	}
+	h.State = state
+	return nil
+}
+
+// Close releases the reducer's resources
+func (r *Reducer) Close() error {
+	return r.transformer.Close()
+}
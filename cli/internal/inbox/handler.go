@@ -0,0 +1,158 @@
+package inbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// maxBodySize is the largest webhook body accepted
+const maxBodySize = 10 << 20
+
+// Route maps an HTTP path to a provider and the topic its webhooks are published to
+type Route struct {
+	Path     string
+	Provider Provider
+	Topic    string
+	Secret   string
+}
+
+// ParseRoute parses a route specification of the form "/path=provider:topic"
+func ParseRoute(spec string) (*Route, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid route: %s (expected '/path=provider:topic')", spec)
+	}
+
+	path := strings.TrimSpace(parts[0])
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	target := strings.SplitN(strings.TrimSpace(parts[1]), ":", 2)
+	if len(target) != 2 || target[0] == "" || target[1] == "" {
+		return nil, fmt.Errorf("invalid route: %s (expected '/path=provider:topic')", spec)
+	}
+
+	provider, ok := Lookup(target[0])
+	if !ok {
+		return nil, fmt.Errorf("unknown provider '%s' (available: %s)", target[0], strings.Join(ProviderNames(), ", "))
+	}
+
+	return &Route{Path: path, Provider: provider, Topic: target[1]}, nil
+}
+
+// Publisher publishes events to the event store
+type Publisher interface {
+	PublishEvents(events []client.EventPublishRequest) ([]string, error)
+}
+
+// Result describes the outcome of handling a single webhook
+type Result struct {
+	Route     *Route
+	EventType string
+	EventIDs  []string
+	Err       error
+}
+
+// Handler serves inbox routes, publishing verified webhooks as events
+type Handler struct {
+	routes    map[string]*Route
+	publisher Publisher
+	onResult  func(Result)
+}
+
+// NewHandler creates a handler for routes; onResult, if set, is called after each
+// webhook. Each route must have a path of its own.
+func NewHandler(routes []*Route, publisher Publisher, onResult func(Result)) (*Handler, error) {
+	byPath := make(map[string]*Route, len(routes))
+	for _, route := range routes {
+		if _, ok := byPath[route.Path]; ok {
+			return nil, fmt.Errorf("more than one route for path %s", route.Path)
+		}
+		byPath[route.Path] = route
+	}
+	return &Handler{routes: byPath, publisher: publisher, onResult: onResult}, nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, ok := h.routes[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	status, result := h.handle(route, r)
+	if h.onResult != nil {
+		h.onResult(result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if result.Err != nil {
+		json.NewEncoder(w).Encode(map[string]string{"error": result.Err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "eventIds": result.EventIDs})
+}
+
+// handle verifies, classifies and publishes a webhook, returning the HTTP status to reply with
+func (h *Handler) handle(route *Route, r *http.Request) (int, Result) {
+	result := Result{Route: route}
+
+	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		result.Err = fmt.Errorf("request body is larger than %d bytes", tooLarge.Limit)
+		return http.StatusRequestEntityTooLarge, result
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("failed to read request body: %w", err)
+		return http.StatusBadRequest, result
+	}
+	defer r.Body.Close()
+
+	if route.Secret != "" {
+		if err := route.Provider.Verify(r, body, route.Secret); err != nil {
+			result.Err = fmt.Errorf("signature verification failed: %w", err)
+			return http.StatusUnauthorized, result
+		}
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		result.Err = fmt.Errorf("invalid JSON: %w", err)
+		return http.StatusBadRequest, result
+	}
+
+	eventType, err := route.Provider.EventType(r, payload)
+	if err != nil {
+		result.Err = err
+		return http.StatusBadRequest, result
+	}
+	result.EventType = eventType
+
+	eventIDs, err := h.publisher.PublishEvents([]client.EventPublishRequest{{
+		Topic:   route.Topic,
+		Type:    eventType,
+		Payload: payload,
+	}})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to publish event: %w", err)
+		return http.StatusBadGateway, result
+	}
+	result.EventIDs = eventIDs
+
+	return http.StatusOK, result
+}
@@ -0,0 +1,93 @@
+package inbox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// recorder is a publisher recording the events it is given
+type recorder struct {
+	events []client.EventPublishRequest
+}
+
+func (p *recorder) PublishEvents(events []client.EventPublishRequest) ([]string, error) {
+	p.events = append(p.events, events...)
+	return []string{"hooks-1"}, nil
+}
+
+func newTestHandler(t *testing.T, secret string) (*Handler, *recorder) {
+	route, err := ParseRoute("/github=github:hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	route.Secret = secret
+	publisher := &recorder{}
+	handler, err := NewHandler([]*Route{route}, publisher, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handler, publisher
+}
+
+func TestHandler(t *testing.T) {
+	body := `{"zen":"Keep it logically awesome."}`
+	signed := "sha256=" + signHMAC("secret", []byte(body))
+	tests := []struct {
+		name      string
+		path      string
+		signature string
+		body      string
+		want      int
+	}{
+		{"signed", "/github", signed, body, http.StatusOK},
+		{"unsigned", "/github", "", body, http.StatusUnauthorized},
+		{"tampered", "/github", signed, strings.Replace(body, "awesome", "awful", 1), http.StatusUnauthorized},
+		{"unknown path", "/stripe", signed, body, http.StatusNotFound},
+		{"too large", "/github", signed, `{"pad":"` + strings.Repeat("x", maxBodySize) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		handler, publisher := newTestHandler(t, "secret")
+		r := httptest.NewRequest("POST", test.path, strings.NewReader(test.body))
+		r.Header.Set("X-GitHub-Event", "ping")
+		if test.signature != "" {
+			r.Header.Set("X-Hub-Signature-256", test.signature)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != test.want {
+			t.Errorf("%s: status = %d, want %d (%s)", test.name, w.Code, test.want, w.Body)
+		}
+		if published := len(publisher.events) == 1; published != (test.want == http.StatusOK) {
+			t.Errorf("%s: published %d events", test.name, len(publisher.events))
+		}
+	}
+}
+
+func TestHandlerUnsigned(t *testing.T) {
+	handler, publisher := newTestHandler(t, "")
+	r := httptest.NewRequest("POST", "/github", strings.NewReader(`{}`))
+	r.Header.Set("X-GitHub-Event", "ping")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || len(publisher.events) != 1 || publisher.events[0].Type != "github.ping" {
+		t.Errorf("status = %d, published %v", w.Code, publisher.events)
+	}
+}
+
+func TestNewHandlerDuplicatePaths(t *testing.T) {
+	var routes []*Route
+	for _, spec := range []string{"/hooks=github:github-events", "hooks=stripe:payments"} {
+		route, err := ParseRoute(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		routes = append(routes, route)
+	}
+	if _, err := NewHandler(routes, &recorder{}, nil); err == nil || !strings.Contains(err.Error(), "/hooks") {
+		t.Errorf("NewHandler = %v, want an error naming /hooks", err)
+	}
+}
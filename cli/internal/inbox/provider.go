@@ -0,0 +1,164 @@
+package inbox
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider verifies and classifies webhooks sent by a third-party service
+type Provider interface {
+	// Name returns the identifier used to refer to the provider in routes
+	Name() string
+	// Verify checks the request signature against the shared secret
+	Verify(r *http.Request, body []byte, secret string) error
+	// EventType derives the event type to publish for a verified webhook
+	EventType(r *http.Request, payload map[string]interface{}) (string, error)
+}
+
+var providers = map[string]Provider{}
+
+// Register makes a provider available to inbox routes
+func Register(p Provider) {
+	providers[p.Name()] = p
+}
+
+// Lookup returns the provider registered under name
+func Lookup(name string) (Provider, bool) {
+	p, ok := providers[name]
+	return p, ok
+}
+
+// ProviderNames returns the names of all registered providers
+func ProviderNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(githubProvider{})
+	Register(stripeProvider{})
+	Register(genericProvider{})
+}
+
+// githubProvider handles GitHub webhooks signed with X-Hub-Signature-256
+type githubProvider struct{}
+
+func (githubProvider) Name() string { return "github" }
+
+func (githubProvider) Verify(r *http.Request, body []byte, secret string) error {
+	signature := r.Header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		return fmt.Errorf("missing X-Hub-Signature-256 header")
+	}
+	expected := "sha256=" + signHMAC(secret, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func (githubProvider) EventType(r *http.Request, payload map[string]interface{}) (string, error) {
+	event := r.Header.Get("X-GitHub-Event")
+	if event == "" {
+		return "", fmt.Errorf("missing X-GitHub-Event header")
+	}
+	return "github." + event, nil
+}
+
+// stripeTolerance is the maximum accepted age of a Stripe signature timestamp
+const stripeTolerance = 5 * time.Minute
+
+// stripeProvider handles Stripe webhooks signed with Stripe-Signature
+type stripeProvider struct{}
+
+func (stripeProvider) Name() string { return "stripe" }
+
+func (stripeProvider) Verify(r *http.Request, body []byte, secret string) error {
+	header := r.Header.Get("Stripe-Signature")
+	if header == "" {
+		return fmt.Errorf("missing Stripe-Signature header")
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("malformed Stripe-Signature header")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp: %s", timestamp)
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return fmt.Errorf("signature timestamp outside tolerance")
+	}
+
+	expected := signHMAC(secret, []byte(timestamp+"."+string(body)))
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature mismatch")
+}
+
+func (stripeProvider) EventType(r *http.Request, payload map[string]interface{}) (string, error) {
+	eventType, ok := payload["type"].(string)
+	if !ok || eventType == "" {
+		return "", fmt.Errorf("payload has no 'type' field")
+	}
+	return "stripe." + eventType, nil
+}
+
+// genericProvider accepts any JSON webhook, optionally signed with X-Signature (hex HMAC-SHA256)
+type genericProvider struct{}
+
+func (genericProvider) Name() string { return "generic" }
+
+func (genericProvider) Verify(r *http.Request, body []byte, secret string) error {
+	signature := strings.TrimPrefix(r.Header.Get("X-Signature"), "sha256=")
+	if signature == "" {
+		return fmt.Errorf("missing X-Signature header")
+	}
+	if !hmac.Equal([]byte(signature), []byte(signHMAC(secret, body))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func (genericProvider) EventType(r *http.Request, payload map[string]interface{}) (string, error) {
+	if eventType := r.Header.Get("X-Event-Type"); eventType != "" {
+		return eventType, nil
+	}
+	return "webhook.received", nil
+}
+
+// signHMAC returns the hex encoded HMAC-SHA256 of data
+func signHMAC(secret string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
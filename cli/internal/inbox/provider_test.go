@@ -0,0 +1,112 @@
+package inbox
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// githubBody and githubSignature are the example in GitHub's webhook documentation,
+// signed with githubSecret
+const (
+	githubSecret    = "It's a Secret to Everybody"
+	githubBody      = "Hello, World!"
+	githubSignature = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+)
+
+// stripeSigned returns a Stripe-Signature header for a body signed at a time
+func stripeSigned(secret, body string, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerify(t *testing.T) {
+	const stripeBody = `{"type":"invoice.paid"}`
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		provider string
+		header   string
+		value    string
+		body     string
+		secret   string
+		wantErr  string
+	}{
+		{"github valid", "github", "X-Hub-Signature-256", githubSignature, githubBody, githubSecret, ""},
+		{"github tampered body", "github", "X-Hub-Signature-256", githubSignature, githubBody + " ", githubSecret, "signature mismatch"},
+		{"github wrong secret", "github", "X-Hub-Signature-256", githubSignature, githubBody, "wrong", "signature mismatch"},
+		{"github missing", "github", "", "", githubBody, githubSecret, "missing X-Hub-Signature-256"},
+		{"github without prefix", "github", "X-Hub-Signature-256", strings.TrimPrefix(githubSignature, "sha256="), githubBody, githubSecret, "signature mismatch"},
+
+		{"stripe valid", "stripe", "Stripe-Signature", stripeSigned("whsec_test", stripeBody, now), stripeBody, "whsec_test", ""},
+		{"stripe one of several", "stripe", "Stripe-Signature", stripeSigned("old", stripeBody, now) + ",v1=" + strings.Split(stripeSigned("whsec_test", stripeBody, now), "v1=")[1], stripeBody, "whsec_test", ""},
+		{"stripe tampered body", "stripe", "Stripe-Signature", stripeSigned("whsec_test", stripeBody, now), `{"type":"invoice.void"}`, "whsec_test", "signature mismatch"},
+		{"stripe wrong secret", "stripe", "Stripe-Signature", stripeSigned("whsec_test", stripeBody, now), stripeBody, "whsec_other", "signature mismatch"},
+		{"stripe malformed", "stripe", "Stripe-Signature", "v1=abc", stripeBody, "whsec_test", "malformed Stripe-Signature"},
+		{"stripe bad timestamp", "stripe", "Stripe-Signature", "t=soon,v1=abc", stripeBody, "whsec_test", "invalid signature timestamp"},
+		{"stripe expired", "stripe", "Stripe-Signature", stripeSigned("whsec_test", stripeBody, now.Add(-6*time.Minute)), stripeBody, "whsec_test", "outside tolerance"},
+		{"stripe future", "stripe", "Stripe-Signature", stripeSigned("whsec_test", stripeBody, now.Add(6*time.Minute)), stripeBody, "whsec_test", "outside tolerance"},
+		// A valid signature from 2023 is still refused, however it was obtained
+		{"stripe replayed", "stripe", "Stripe-Signature", "t=1700000000,v1=d085648b633f667bc2bbe0ae61b315d03eb679fe556605862de839d5e1982458", stripeBody, "whsec_test", "outside tolerance"},
+
+		{"generic valid", "generic", "X-Signature", strings.TrimPrefix(githubSignature, "sha256="), githubBody, githubSecret, ""},
+		{"generic valid with prefix", "generic", "X-Signature", githubSignature, githubBody, githubSecret, ""},
+		{"generic tampered body", "generic", "X-Signature", githubSignature, "Hello, World?", githubSecret, "signature mismatch"},
+		{"generic wrong secret", "generic", "X-Signature", githubSignature, githubBody, "wrong", "signature mismatch"},
+		{"generic malformed", "generic", "X-Signature", "sha256=not-hex", githubBody, githubSecret, "signature mismatch"},
+		{"generic missing", "generic", "X-Signature", "sha256=", githubBody, githubSecret, "missing X-Signature"},
+	}
+	for _, test := range tests {
+		provider, ok := Lookup(test.provider)
+		if !ok {
+			t.Fatalf("no provider %s", test.provider)
+		}
+		r := httptest.NewRequest("POST", "/hook", strings.NewReader(test.body))
+		if test.header != "" {
+			r.Header.Set(test.header, test.value)
+		}
+		err := provider.Verify(r, []byte(test.body), test.secret)
+		switch {
+		case test.wantErr == "" && err != nil:
+			t.Errorf("%s: Verify = %v, want success", test.name, err)
+		case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+			t.Errorf("%s: Verify = %v, want %q", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestEventType(t *testing.T) {
+	tests := []struct {
+		provider string
+		header   string
+		value    string
+		payload  map[string]interface{}
+		want     string
+	}{
+		{"github", "X-GitHub-Event", "push", nil, "github.push"},
+		{"github", "", "", nil, ""},
+		{"stripe", "", "", map[string]interface{}{"type": "invoice.paid"}, "stripe.invoice.paid"},
+		{"stripe", "", "", map[string]interface{}{"type": 7}, ""},
+		{"generic", "X-Event-Type", "order.created", nil, "order.created"},
+		{"generic", "", "", nil, "webhook.received"},
+	}
+	for _, test := range tests {
+		provider, _ := Lookup(test.provider)
+		r := httptest.NewRequest("POST", "/hook", nil)
+		if test.header != "" {
+			r.Header.Set(test.header, test.value)
+		}
+		got, err := provider.EventType(r, test.payload)
+		if got != test.want || (err != nil) != (test.want == "") {
+			t.Errorf("%s EventType(%s: %q, %v) = %q, %v, want %q", test.provider, test.header, test.value, test.payload, got, err, test.want)
+		}
+	}
+}
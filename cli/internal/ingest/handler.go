@@ -0,0 +1,215 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/inbox"
+)
+
+// maxBodySize is the largest webhook body accepted
+const maxBodySize = 10 << 20
+
+// Result describes the outcome of handling a single webhook
+type Result struct {
+	Path      string
+	Rule      *Rule // nil when no rule matched
+	EventType string
+	EventIDs  []string
+	Ignored   string // why a webhook was accepted without publishing an event
+	Err       error
+}
+
+// Handler serves the paths of a rules file, publishing webhooks as events
+type Handler struct {
+	rules     map[string][]*Rule
+	publisher inbox.Publisher
+	onResult  func(Result)
+}
+
+// NewHandler creates a handler for the rules of a file; onResult, if set, is called after
+// each webhook
+func NewHandler(file *File, publisher inbox.Publisher, onResult func(Result)) *Handler {
+	byPath := map[string][]*Rule{}
+	for _, rule := range file.Rules {
+		byPath[rule.Path] = append(byPath[rule.Path], rule)
+	}
+	return &Handler{rules: byPath, publisher: publisher, onResult: onResult}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rules, ok := h.rules[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	status, result := h.handle(rules, r)
+	if h.onResult != nil {
+		h.onResult(result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	switch {
+	case result.Err != nil:
+		json.NewEncoder(w).Encode(map[string]string{"error": result.Err.Error()})
+	case result.Ignored != "":
+		json.NewEncoder(w).Encode(map[string]string{"status": "ignored", "reason": result.Ignored})
+	default:
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "eventIds": result.EventIDs})
+	}
+}
+
+// handle verifies a webhook, picks its rule and publishes it, returning the HTTP status
+// to reply with
+func (h *Handler) handle(rules []*Rule, r *http.Request) (int, Result) {
+	result := Result{Path: r.URL.Path}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to read request body: %w", err)
+		return http.StatusBadRequest, result
+	}
+	defer r.Body.Close()
+
+	// Every rule of a path verifies signatures the same way
+	if rules[0].Signed() {
+		if err := rules[0].provider.Verify(r, body, rules[0].secret); err != nil {
+			result.Err = fmt.Errorf("signature verification failed: %w", err)
+			return http.StatusUnauthorized, result
+		}
+	}
+
+	payload, err := parseBody(r, body)
+	if err != nil {
+		result.Err = err
+		return http.StatusBadRequest, result
+	}
+	// Filters and type paths see the body as an event's payload. Its 'type' and 'id'
+	// fields stand in for the event's, so 'when: type=invoice.paid' matches the body.
+	event := client.Event{Payload: payload}
+	event.Type, _ = filter.Value(event, "payload.type")
+	event.ID, _ = filter.Value(event, "payload.id")
+
+	var rule *Rule
+	for _, candidate := range rules {
+		if filter.MatchAll(candidate.filters, event) {
+			rule = candidate
+			break
+		}
+	}
+	if rule == nil {
+		result.Ignored = "no rule matched"
+		return http.StatusOK, result
+	}
+	result.Rule = rule
+
+	eventType, err := rule.eventType(r, event)
+	if err != nil {
+		result.Err = err
+		return http.StatusBadRequest, result
+	}
+	result.EventType = eventType
+
+	if rule.transform != nil {
+		transformed, keep, err := rule.transform.Transform(payload)
+		if err != nil {
+			result.Err = err
+			return http.StatusBadRequest, result
+		}
+		if !keep {
+			result.Ignored = "dropped by the payload transform"
+			return http.StatusOK, result
+		}
+		payload = transformed
+	}
+
+	eventIDs, err := h.publisher.PublishEvents([]client.EventPublishRequest{{
+		Topic:   rule.Topic,
+		Type:    eventType,
+		Payload: payload,
+	}})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to publish event: %w", err)
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests {
+			// The event store refused the event itself, e.g. against the topic's schema
+			return http.StatusUnprocessableEntity, result
+		}
+		return http.StatusBadGateway, result
+	}
+	result.EventIDs = eventIDs
+
+	return http.StatusOK, result
+}
+
+// parseBody parses a JSON object, or a form-encoded body into an object of strings (and
+// arrays of strings for repeated fields). Senders label JSON as a form often enough that
+// a body starting with '{' is always read as JSON.
+func parseBody(r *http.Request, body []byte) (map[string]interface{}, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" && !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid form body: %w", err)
+		}
+		payload := make(map[string]interface{}, len(values))
+		for name, list := range values {
+			if len(list) == 1 {
+				payload[name] = list[0]
+				continue
+			}
+			items := make([]interface{}, len(list))
+			for i, item := range list {
+				items[i] = item
+			}
+			payload[name] = items
+		}
+		return payload, nil
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil || payload == nil {
+		return nil, fmt.Errorf("body is not a JSON object")
+	}
+	return payload, nil
+}
+
+// eventType returns the type of the event a webhook is published as: from the body field
+// or header the rule names, falling back to the rule's type and then its provider's
+func (r *Rule) eventType(req *http.Request, event client.Event) (string, error) {
+	var value string
+	switch {
+	case r.TypePath != "":
+		value, _ = filter.Value(event, "payload."+r.TypePath)
+	case r.TypeHeader != "":
+		value = req.Header.Get(r.TypeHeader)
+	}
+	switch {
+	case value != "":
+		return r.TypePrefix + value, nil
+	case r.Type != "":
+		return r.Type, nil
+	case r.provider != nil:
+		return r.provider.EventType(req, event.Payload)
+	case r.TypePath != "":
+		return "", fmt.Errorf("body has no %s field to take the event type from", r.TypePath)
+	default:
+		return "", fmt.Errorf("request has no %s header to take the event type from", r.TypeHeader)
+	}
+}
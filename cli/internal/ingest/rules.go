@@ -0,0 +1,141 @@
+// Package ingest turns inbound HTTP webhooks into events by configurable rules: a rule
+// maps a request path to a topic, takes the event type from the body, a header or the
+// rule, and can rewrite the body into the payload with a transform.
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/inbox"
+	"github.com/event-store/cli/internal/transform"
+	"go.yaml.in/yaml/v3"
+)
+
+// File is an ingest rules file
+type File struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Rule maps webhooks posted to a path to events on a topic. The rules of a path are
+// tried in order, and the first whose When matches the body is used.
+type Rule struct {
+	Name       string `yaml:"name"`
+	Path       string `yaml:"path"`
+	Topic      string `yaml:"topic"`
+	When       string `yaml:"when"`        // filter on body fields, e.g. 'action=opened AND repository.private=false'
+	Type       string `yaml:"type"`        // event type, or the fallback when type-path or type-header is missing
+	TypePath   string `yaml:"type-path"`   // dotted path of a body field giving the event type
+	TypeHeader string `yaml:"type-header"` // header giving the event type
+	TypePrefix string `yaml:"type-prefix"` // prepended to a type from type-path or type-header
+	Payload    string `yaml:"payload"`     // jq expression or Go template rewriting the body
+	Provider   string `yaml:"provider"`    // inbox provider verifying signatures: github, stripe or generic
+	SecretEnv  string `yaml:"secret-env"`  // environment variable holding the signing secret
+
+	filters   []*filter.Filter
+	transform transform.Transformer
+	provider  inbox.Provider
+	secret    string
+}
+
+// Load reads and validates a rules file, starting the rules' transforms. Close releases
+// them.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules %s: %w", path, err)
+	}
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("rules %s has no rules", path)
+	}
+
+	signing := map[string]*Rule{}
+	for i, rule := range file.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if err := rule.prepare(); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s: %s: %w", path, rule.Name, err)
+		}
+		// A webhook is verified before a rule is chosen, so a path has one way to sign
+		if first, ok := signing[rule.Path]; !ok {
+			signing[rule.Path] = rule
+		} else if first.Provider != rule.Provider || first.SecretEnv != rule.SecretEnv {
+			file.Close()
+			return nil, fmt.Errorf("%s: %s: rules for %s must share the provider and secret-env of %s", path, rule.Name, rule.Path, first.Name)
+		}
+	}
+	return &file, nil
+}
+
+// prepare checks a rule and sets up its filters, transform and signature verification
+func (r *Rule) prepare() error {
+	if r.Path == "" || r.Topic == "" {
+		return fmt.Errorf("needs a path and a topic")
+	}
+	if !strings.HasPrefix(r.Path, "/") {
+		r.Path = "/" + r.Path
+	}
+	if r.Type == "" && r.TypePath == "" && r.TypeHeader == "" && r.Provider == "" {
+		return fmt.Errorf("needs a type, type-path, type-header or provider to give events a type")
+	}
+	if r.TypePath != "" && r.TypeHeader != "" {
+		return fmt.Errorf("can have type-path or type-header, not both")
+	}
+
+	if r.When != "" {
+		filters, err := filter.ParseAll(r.When)
+		if err != nil {
+			return fmt.Errorf("invalid when: %w", err)
+		}
+		r.filters = filters
+	}
+
+	if r.Provider != "" {
+		provider, ok := inbox.Lookup(r.Provider)
+		if !ok {
+			return fmt.Errorf("unknown provider '%s' (available: %s)", r.Provider, strings.Join(inbox.ProviderNames(), ", "))
+		}
+		r.provider = provider
+	}
+	if r.SecretEnv != "" {
+		if r.provider == nil {
+			return fmt.Errorf("secret-env needs a provider to verify signatures with")
+		}
+		if r.secret = os.Getenv(r.SecretEnv); r.secret == "" {
+			return fmt.Errorf("environment variable %s is not set", r.SecretEnv)
+		}
+	}
+
+	if r.Payload != "" {
+		t, err := transform.New(r.Payload)
+		if err != nil {
+			return err
+		}
+		r.transform = t
+	}
+	return nil
+}
+
+// Signed reports whether the rule verifies webhook signatures
+func (r *Rule) Signed() bool {
+	return r.secret != ""
+}
+
+// Close releases the rules' transforms
+func (f *File) Close() error {
+	for _, rule := range f.Rules {
+		if rule.transform != nil {
+			rule.transform.Close()
+		}
+	}
+	return nil
+}
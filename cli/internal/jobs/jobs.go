@@ -0,0 +1,251 @@
+// Package jobs keeps the progress of long-running commands, such as exports and
+// replays, in ~/.es/jobs, so one stopped by a crash or Ctrl+C can be resumed from its
+// last checkpoint. Each job is a JSON file holding the command line that started it and
+// the command's own checkpoint, and is removed once the command completes.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/event-store/cli/internal/config"
+	"github.com/event-store/cli/internal/hooks"
+)
+
+// Statuses of a job
+const (
+	Running     = "running"
+	Interrupted = "interrupted"
+	Failed      = "failed"
+)
+
+// Job is a long-running command and how far it got
+type Job struct {
+	ID      string    `json:"id"`
+	Kind    string    `json:"kind"`
+	Args    []string  `json:"args"`
+	Dir     string    `json:"dir"`
+	Server  string    `json:"server"`
+	Status  string    `json:"status"`
+	PID     int       `json:"pid,omitempty"`
+	Started time.Time `json:"started"`
+	Updated time.Time `json:"updated"`
+	// Progress is the number of events done, of Total if known
+	Progress   int             `json:"progress"`
+	Total      int             `json:"total,omitempty"`
+	Checkpoint json.RawMessage `json:"checkpoint,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// Dir returns the jobs directory, ~/.es/jobs
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "jobs"), nil
+}
+
+// New records a running job of a kind for a command line run in a directory against a
+// server
+func New(kind string, args []string, dir, server string, total int) (*Job, error) {
+	var suffix [4]byte
+	rand.Read(suffix[:])
+	now := time.Now().UTC()
+	job := &Job{
+		ID:      kind + "-" + hex.EncodeToString(suffix[:]),
+		Kind:    kind,
+		Args:    args,
+		Dir:     dir,
+		Server:  server,
+		Status:  Running,
+		PID:     os.Getpid(),
+		Started: now,
+		Updated: now,
+		Total:   total,
+	}
+	if err := job.save(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Load reads a job by ID
+func Load(id string) (*Job, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	job, err := read(filepath.Join(dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("job '%s' not found", id)
+	}
+	return job, err
+}
+
+// List returns every job, the most recently started first
+func List() ([]*Job, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jobs: %w", err)
+	}
+
+	var jobs []*Job
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		job, err := read(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].Started.After(jobs[j].Started)
+	})
+	return jobs, nil
+}
+
+// Remove deletes a job
+func Remove(id string) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("job '%s' not found", id)
+	}
+	return err
+}
+
+// read reads a job file. A job left running by a process that no longer exists, such as
+// one that crashed, is interrupted.
+func read(path string) (*Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("invalid job %s: %w", filepath.Base(path), err)
+	}
+	if job.Status == Running && !job.Alive() {
+		job.Status = Interrupted
+	}
+	return &job, nil
+}
+
+// Alive reports whether the process running the job still exists
+func (j *Job) Alive() bool {
+	if j.PID == 0 {
+		return false
+	}
+	process, err := os.FindProcess(j.PID)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// Resume marks an interrupted or failed job as running again in this process, and reads
+// its checkpoint into checkpoint
+func (j *Job) Resume(checkpoint interface{}) error {
+	if len(j.Checkpoint) > 0 && checkpoint != nil {
+		if err := json.Unmarshal(j.Checkpoint, checkpoint); err != nil {
+			return fmt.Errorf("invalid checkpoint of job '%s': %w", j.ID, err)
+		}
+	}
+	j.Status = Running
+	j.PID = os.Getpid()
+	j.Error = ""
+	return j.save()
+}
+
+// Save records the job's checkpoint and progress. A nil job records nothing, so commands
+// can checkpoint whether or not they run as a job.
+func (j *Job) Save(checkpoint interface{}, progress int) error {
+	if j == nil {
+		return nil
+	}
+	if checkpoint != nil {
+		data, err := json.Marshal(checkpoint)
+		if err != nil {
+			return err
+		}
+		j.Checkpoint = data
+	}
+	j.Progress = progress
+	return j.save()
+}
+
+// Finish ends the job: it's removed once completed, when err is nil, and otherwise kept
+// to be resumed, as interrupted when err is context.Canceled, from Ctrl+C, and as failed
+// with the error otherwise. It returns err, so a command can return through it, or the
+// error removing a completed job.
+func (j *Job) Finish(err error) error {
+	if j == nil {
+		return err
+	}
+	if err == nil {
+		return Remove(j.ID)
+	}
+	j.Status, j.Error = Failed, err.Error()
+	if errors.Is(err, context.Canceled) {
+		j.Status, j.Error = Interrupted, ""
+	}
+	j.PID = 0
+	if saveErr := j.save(); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", saveErr)
+	}
+	return err
+}
+
+// Command returns the job's command line, without the values of flags holding secrets
+func (j *Job) Command() string {
+	return "es " + strings.Join(hooks.RedactArgs(j.Args), " ")
+}
+
+// save writes the job's file, replacing it whole so a crash can't leave it half written
+func (j *Job) save() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create jobs directory: %w", err)
+	}
+	j.Updated = time.Now().UTC()
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, j.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,559 @@
+// Package kafka is a small Kafka client covering what 'es bridge kafka' needs: topic
+// metadata, producing record batches and fetching from partitions at an offset, over
+// plaintext or TLS, with optional SASL/PLAIN authentication. Consumer groups,
+// transactions and compression codecs other than gzip are not supported.
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Config configures a client
+type Config struct {
+	Brokers  []string // bootstrap brokers, host:port
+	ClientID string
+	TLS      *tls.Config // nil for plaintext
+	Username string      // SASL/PLAIN username, or "" for no authentication
+	Password string
+	Timeout  time.Duration // per request; 30s if zero
+}
+
+// Client talks to the brokers of a Kafka cluster. Metadata is fetched on first use and
+// refreshed after errors that mean it may be stale. A Client is safe for concurrent use.
+type Client struct {
+	config Config
+
+	mu       sync.Mutex
+	conns    map[int32]*conn // by broker ID; -1 is the bootstrap broker
+	brokers  map[int32]string
+	leaders  map[string][]partitionLeader // topic -> partitions
+	nextPart map[string]int               // round-robin position for messages without keys
+}
+
+type partitionLeader struct {
+	id     int32
+	leader int32
+}
+
+// conn is a connection to one broker, carrying one request at a time
+type conn struct {
+	mu            sync.Mutex
+	net           net.Conn
+	correlationID int32
+}
+
+// New returns a client for a cluster. No connection is made until the first request.
+func New(config Config) (*Client, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("no Kafka brokers given")
+	}
+	if config.ClientID == "" {
+		config.ClientID = "es"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &Client{config: config, conns: map[int32]*conn{}, nextPart: map[string]int{}}, nil
+}
+
+// Close closes the client's connections
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, conn := range c.conns {
+		conn.net.Close()
+		delete(c.conns, id)
+	}
+	return nil
+}
+
+// reset forgets the cluster metadata and closes every connection, after an error that
+// means either may be stale
+func (c *Client) reset() {
+	c.Close()
+	c.mu.Lock()
+	c.leaders = nil
+	c.mu.Unlock()
+}
+
+// dial connects to a broker, with TLS and SASL as configured
+func (c *Client) dial(ctx context.Context, addr string) (*conn, error) {
+	dialer := &net.Dialer{Timeout: c.config.Timeout}
+	var netConn net.Conn
+	var err error
+	if c.config.TLS != nil {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: c.config.TLS}).DialContext(ctx, "tcp", addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kafka: %w", err)
+	}
+	conn := &conn{net: netConn}
+	if c.config.Username != "" {
+		if err := c.authenticate(ctx, conn); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// authenticate runs a SASL/PLAIN exchange on a new connection
+func (c *Client) authenticate(ctx context.Context, conn *conn) error {
+	var handshake encoder
+	handshake.string("PLAIN")
+	resp, err := c.roundTrip(ctx, conn, apiSaslHandshake, 1, handshake.buf)
+	if err != nil {
+		return err
+	}
+	d := decoder{buf: resp}
+	if err := errorCode(d.int16()); err != nil {
+		return fmt.Errorf("SASL handshake: %w", err)
+	}
+
+	var auth encoder
+	auth.bytes([]byte("\x00" + c.config.Username + "\x00" + c.config.Password))
+	resp, err = c.roundTrip(ctx, conn, apiSaslAuthenticate, 0, auth.buf)
+	if err != nil {
+		return err
+	}
+	d = decoder{buf: resp}
+	code := d.int16()
+	message := d.string()
+	if code != 0 {
+		if message != "" {
+			return fmt.Errorf("kafka: SASL authentication failed: %s", message)
+		}
+		return fmt.Errorf("SASL authentication: %w", Error{Code: code})
+	}
+	return d.err
+}
+
+// roundTrip sends a request on a connection and returns the response body
+func (c *Client) roundTrip(ctx context.Context, conn *conn, apiKey, version int16, body []byte) ([]byte, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	deadline := time.Now().Add(c.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.net.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.net.SetDeadline(time.Now()) })
+	defer stop()
+
+	conn.correlationID++
+	var req encoder
+	req.int32(0) // size, filled in below
+	req.int16(apiKey)
+	req.int16(version)
+	req.int32(conn.correlationID)
+	req.nullableString(c.config.ClientID)
+	req.buf = append(req.buf, body...)
+	binary.BigEndian.PutUint32(req.buf, uint32(len(req.buf)-4))
+	if _, err := conn.net.Write(req.buf); err != nil {
+		return nil, c.connError(ctx, err)
+	}
+
+	var header [8]byte
+	if _, err := io.ReadFull(conn.net, header[:]); err != nil {
+		return nil, c.connError(ctx, err)
+	}
+	size := int32(binary.BigEndian.Uint32(header[:]))
+	if size < 4 {
+		return nil, fmt.Errorf("kafka: invalid response size %d", size)
+	}
+	if id := int32(binary.BigEndian.Uint32(header[4:])); id != conn.correlationID {
+		return nil, fmt.Errorf("kafka: response out of order (correlation ID %d, expected %d)", id, conn.correlationID)
+	}
+	resp := make([]byte, size-4)
+	if _, err := io.ReadFull(conn.net, resp); err != nil {
+		return nil, c.connError(ctx, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) connError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("kafka: %w", err)
+}
+
+// request sends a request to a broker (-1 for any bootstrap broker), connecting first if
+// needed. Connection errors and stale-metadata errors reset the client.
+func (c *Client) request(ctx context.Context, broker int32, apiKey, version int16, body []byte) ([]byte, error) {
+	conn, err := c.connection(ctx, broker)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.roundTrip(ctx, conn, apiKey, version, body)
+	if err != nil {
+		c.reset()
+	}
+	return resp, err
+}
+
+func (c *Client) connection(ctx context.Context, broker int32) (*conn, error) {
+	c.mu.Lock()
+	if conn, ok := c.conns[broker]; ok {
+		c.mu.Unlock()
+		return conn, nil
+	}
+	addrs := c.config.Brokers
+	if broker >= 0 {
+		addr, ok := c.brokers[broker]
+		if !ok {
+			c.mu.Unlock()
+			return nil, fmt.Errorf("kafka: unknown broker %d", broker)
+		}
+		addrs = []string{addr}
+	}
+	c.mu.Unlock()
+
+	var err error
+	for _, addr := range addrs {
+		var conn *conn
+		if conn, err = c.dial(ctx, addr); err == nil {
+			c.mu.Lock()
+			c.conns[broker] = conn
+			c.mu.Unlock()
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// Partitions returns the partition IDs of a topic
+func (c *Client) Partitions(ctx context.Context, topic string) ([]int32, error) {
+	leaders, err := c.partitionLeaders(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	partitions := make([]int32, len(leaders))
+	for i, p := range leaders {
+		partitions[i] = p.id
+	}
+	return partitions, nil
+}
+
+// partitionLeaders returns a topic's partitions and their leaders, fetching metadata
+// when the client has none for the topic
+func (c *Client) partitionLeaders(ctx context.Context, topic string) ([]partitionLeader, error) {
+	c.mu.Lock()
+	leaders, ok := c.leaders[topic]
+	c.mu.Unlock()
+	if ok {
+		return leaders, nil
+	}
+
+	var req encoder
+	req.arrayLength(1)
+	req.string(topic)
+	resp, err := c.request(ctx, -1, apiMetadata, 1, req.buf)
+	if err != nil {
+		return nil, err
+	}
+
+	d := decoder{buf: resp}
+	brokers := map[int32]string{}
+	for i, n := 0, d.arrayLength(); i < n; i++ {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller ID
+	var topicErr error
+	for i, n := 0, d.arrayLength(); i < n; i++ {
+		code := d.int16()
+		name := d.string()
+		d.int8() // internal
+		var partitions []partitionLeader
+		for j, m := 0, d.arrayLength(); j < m; j++ {
+			d.int16() // partition error, e.g. a replica being unavailable
+			p := partitionLeader{id: d.int32(), leader: d.int32()}
+			for k, r := 0, d.arrayLength(); k < r; k++ {
+				d.int32()
+			}
+			for k, r := 0, d.arrayLength(); k < r; k++ {
+				d.int32()
+			}
+			partitions = append(partitions, p)
+		}
+		if name == topic {
+			topicErr = errorCode(code)
+			leaders = partitions
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if topicErr != nil {
+		return nil, fmt.Errorf("topic '%s': %w", topic, topicErr)
+	}
+	if len(leaders) == 0 {
+		return nil, fmt.Errorf("topic '%s': %w", topic, Error{Code: errUnknownTopicPartition})
+	}
+
+	c.mu.Lock()
+	c.brokers = brokers
+	if c.leaders == nil {
+		c.leaders = map[string][]partitionLeader{}
+	}
+	c.leaders[topic] = leaders
+	c.mu.Unlock()
+	return leaders, nil
+}
+
+func (c *Client) leader(ctx context.Context, topic string, partition int32) (int32, error) {
+	leaders, err := c.partitionLeaders(ctx, topic)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range leaders {
+		if p.id == partition {
+			if p.leader < 0 {
+				return 0, fmt.Errorf("topic '%s' partition %d: %w", topic, partition, Error{Code: errLeaderNotAvailable})
+			}
+			return p.leader, nil
+		}
+	}
+	return 0, fmt.Errorf("topic '%s' partition %d: %w", topic, partition, Error{Code: errUnknownTopicPartition})
+}
+
+// partitionError wraps a broker's error for a partition, resetting the client when the
+// error means its metadata is stale
+func (c *Client) partitionError(topic string, partition int32, code int16) error {
+	err := Error{Code: code}
+	if err.Retriable() {
+		c.reset()
+	}
+	return fmt.Errorf("topic '%s' partition %d: %w", topic, partition, err)
+}
+
+// Produce writes messages to a topic and waits for every in-sync replica to have them.
+// Each message goes to the partition chosen by hashing its key as the Java client does,
+// so messages with the same key land in the same partition whichever client produced
+// them. Messages without keys go to the partitions in turn, a call at a time.
+func (c *Client) Produce(ctx context.Context, topic string, messages []Message) error {
+	leaders, err := c.partitionLeaders(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	byPartition := map[int32][]Message{}
+	c.mu.Lock()
+	next := c.nextPart[topic]
+	c.nextPart[topic] = next + 1
+	c.mu.Unlock()
+	for _, message := range messages {
+		var partition int32
+		if message.Key != nil {
+			partition = partitionFor(message.Key, len(leaders))
+		} else {
+			partition = leaders[next%len(leaders)].id
+		}
+		byPartition[partition] = append(byPartition[partition], message)
+	}
+
+	// Partitions are produced to one at a time, so a failure leaves the later ones
+	// unwritten and the whole call can be retried
+	for partition, batch := range byPartition {
+		if err := c.producePartition(ctx, topic, partition, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) producePartition(ctx context.Context, topic string, partition int32, messages []Message) error {
+	leader, err := c.leader(ctx, topic, partition)
+	if err != nil {
+		return err
+	}
+
+	var req encoder
+	req.nullableString("") // transactional ID
+	req.int16(-1)          // acks: all in-sync replicas
+	req.int32(int32(c.config.Timeout / time.Millisecond))
+	req.arrayLength(1)
+	req.string(topic)
+	req.arrayLength(1)
+	req.int32(partition)
+	req.bytes(encodeBatch(messages, time.Now()))
+	resp, err := c.request(ctx, leader, apiProduce, 3, req.buf)
+	if err != nil {
+		return err
+	}
+
+	d := decoder{buf: resp}
+	for i, n := 0, d.arrayLength(); i < n; i++ {
+		d.string()
+		for j, m := 0, d.arrayLength(); j < m; j++ {
+			id := d.int32()
+			code := d.int16()
+			d.int64() // base offset
+			d.int64() // log append time
+			if code != 0 && d.err == nil {
+				return c.partitionError(topic, id, code)
+			}
+		}
+	}
+	return d.err
+}
+
+// Offset returns the earliest offset of a partition, or with latest the offset the next
+// message will be written at
+func (c *Client) Offset(ctx context.Context, topic string, partition int32, latest bool) (int64, error) {
+	leader, err := c.leader(ctx, topic, partition)
+	if err != nil {
+		return 0, err
+	}
+	timestamp := int64(-2)
+	if latest {
+		timestamp = -1
+	}
+
+	var req encoder
+	req.int32(-1) // replica ID
+	req.arrayLength(1)
+	req.string(topic)
+	req.arrayLength(1)
+	req.int32(partition)
+	req.int64(timestamp)
+	resp, err := c.request(ctx, leader, apiListOffsets, 1, req.buf)
+	if err != nil {
+		return 0, err
+	}
+
+	d := decoder{buf: resp}
+	offset := int64(-1)
+	for i, n := 0, d.arrayLength(); i < n; i++ {
+		d.string()
+		for j, m := 0, d.arrayLength(); j < m; j++ {
+			d.int32()
+			code := d.int16()
+			d.int64() // timestamp
+			offset = d.int64()
+			if code != 0 && d.err == nil {
+				return 0, c.partitionError(topic, partition, code)
+			}
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("kafka: no offset returned for topic '%s' partition %d", topic, partition)
+	}
+	return offset, nil
+}
+
+// Fetch returns a partition's messages from offset, waiting up to maxWait for at least
+// one, and reading up to maxBytes. It returns no messages when offset is the end of the
+// partition and maxWait passes.
+func (c *Client) Fetch(ctx context.Context, topic string, partition int32, offset int64, maxWait time.Duration, maxBytes int32) ([]Message, error) {
+	leader, err := c.leader(ctx, topic, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	var req encoder
+	req.int32(-1) // replica ID
+	req.int32(int32(maxWait / time.Millisecond))
+	req.int32(1) // min bytes
+	req.int32(maxBytes)
+	req.int8(0) // isolation level: read uncommitted
+	req.arrayLength(1)
+	req.string(topic)
+	req.arrayLength(1)
+	req.int32(partition)
+	req.int64(offset)
+	req.int32(maxBytes)
+	resp, err := c.request(ctx, leader, apiFetch, 4, req.buf)
+	if err != nil {
+		return nil, err
+	}
+
+	d := decoder{buf: resp}
+	d.int32() // throttle time
+	var records []byte
+	for i, n := 0, d.arrayLength(); i < n; i++ {
+		d.string()
+		for j, m := 0, d.arrayLength(); j < m; j++ {
+			d.int32()
+			code := d.int16()
+			d.int64() // high watermark
+			d.int64() // last stable offset
+			for k, a := 0, d.arrayLength(); k < a; k++ {
+				d.int64()
+				d.int64()
+			}
+			records = d.bytes()
+			if code != 0 && d.err == nil {
+				return nil, c.partitionError(topic, partition, code)
+			}
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return decodeBatches(topic, partition, records, offset)
+}
+
+// IsOffsetOutOfRange reports whether an error is the broker rejecting an offset that is
+// before the start or after the end of a partition
+func IsOffsetOutOfRange(err error) bool {
+	var kafkaErr Error
+	return errors.As(err, &kafkaErr) && kafkaErr.Code == errOffsetOutOfRange
+}
+
+// partitionFor returns the partition the Java client's default partitioner puts a
+// message with key in, of a topic with the given number of partitions
+func partitionFor(key []byte, partitions int) int32 {
+	return int32(int(murmur2(key)&0x7fffffff) % partitions)
+}
+
+// murmur2 is the hash the Java client's default partitioner applies to keys
+func murmur2(data []byte) int32 {
+	const m = 0x5bd1e995
+	const r = 24
+	length := len(data)
+	h := uint32(0x9747b28c) ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
@@ -0,0 +1,204 @@
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression codecs, the low bits of a record batch's attributes
+const (
+	compressionNone   = 0
+	compressionGzip   = 1
+	compressionSnappy = 2
+	compressionLZ4    = 3
+	compressionZstd   = 4
+)
+
+// xerialHeader starts snappy-compressed records written by the Java client, which
+// frames them as snappy-java's stream format does: the header, then blocks each
+// preceded by their big-endian length
+var xerialHeader = []byte{0x82, 'S', 'N', 'A', 'P', 'P', 'Y', 0}
+
+// zstdDecoder is shared by every fetch: decoders are expensive to create and safe for
+// concurrent DecodeAll calls
+var zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+	return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+})
+
+// decompress returns the records of a batch compressed with codec
+func decompress(codec int16, data []byte) ([]byte, error) {
+	switch codec {
+	case compressionNone:
+		return data, nil
+	case compressionGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(reader)
+	case compressionSnappy:
+		return decodeSnappy(data)
+	case compressionLZ4:
+		return decodeLZ4(data)
+	case compressionZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return decoder.DecodeAll(data, nil)
+	}
+	return nil, fmt.Errorf("unknown compression codec %d", codec)
+}
+
+// decodeSnappy decodes snappy-compressed records, whether a single block, as librdkafka
+// writes them, or xerial-framed, as the Java client does
+func decodeSnappy(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, xerialHeader) {
+		return s2.Decode(nil, data)
+	}
+	if len(data) < 16 {
+		return nil, errors.New("snappy: truncated xerial header")
+	}
+	var out []byte
+	for rest := data[16:]; len(rest) > 0; {
+		if len(rest) < 4 {
+			return nil, errors.New("snappy: truncated xerial block")
+		}
+		n := binary.BigEndian.Uint32(rest)
+		if uint64(n) > uint64(len(rest)-4) {
+			return nil, errors.New("snappy: truncated xerial block")
+		}
+		block, err := s2.Decode(nil, rest[4:4+n])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, block...)
+		rest = rest[4+n:]
+	}
+	return out, nil
+}
+
+// LZ4 frame format flags, from the FLG byte of a frame's descriptor
+const (
+	lz4Magic           = 0x184D2204
+	lz4BlockChecksum   = 0x10
+	lz4ContentSize     = 0x08
+	lz4ContentChecksum = 0x04
+	lz4DictID          = 0x01
+)
+
+// decodeLZ4 decodes records compressed as an LZ4 frame. Blocks are decoded into one
+// buffer, so frames with linked blocks, whose matches reach back into earlier blocks,
+// decode as well as those with independent ones. Checksums aren't verified: the batch's
+// CRC already covers the compressed records.
+func decodeLZ4(data []byte) ([]byte, error) {
+	if len(data) < 7 || binary.LittleEndian.Uint32(data) != lz4Magic {
+		return nil, errors.New("lz4: not an LZ4 frame")
+	}
+	flags := data[4]
+	if flags>>6 != 1 {
+		return nil, fmt.Errorf("lz4: unsupported frame version %d", flags>>6)
+	}
+	if flags&lz4DictID != 0 {
+		return nil, errors.New("lz4: frames with a dictionary are not supported")
+	}
+	i := 6 // magic, FLG and BD
+	if flags&lz4ContentSize != 0 {
+		i += 8
+	}
+	i++ // header checksum
+
+	var out []byte
+	for {
+		if i+4 > len(data) {
+			return nil, errors.New("lz4: truncated frame")
+		}
+		size := binary.LittleEndian.Uint32(data[i:])
+		i += 4
+		if size == 0 {
+			break
+		}
+		n := int(size &^ (1 << 31))
+		if n > len(data)-i {
+			return nil, errors.New("lz4: truncated block")
+		}
+		block := data[i : i+n]
+		i += n
+		if flags&lz4BlockChecksum != 0 {
+			i += 4
+		}
+		if size&(1<<31) != 0 {
+			out = append(out, block...)
+			continue
+		}
+		var err error
+		if out, err = decodeLZ4Block(out, block); err != nil {
+			return nil, err
+		}
+	}
+	if flags&lz4ContentChecksum != 0 && i+4 > len(data) {
+		return nil, errors.New("lz4: truncated frame")
+	}
+	return out, nil
+}
+
+// decodeLZ4Block appends a decoded LZ4 block to out, whose contents earlier matches may
+// refer back to
+func decodeLZ4Block(out, block []byte) ([]byte, error) {
+	corrupt := errors.New("lz4: corrupt block")
+	length := func(i, n int) (int, int, bool) {
+		if n != 15 {
+			return i, n, true
+		}
+		for {
+			if i >= len(block) {
+				return i, 0, false
+			}
+			b := block[i]
+			i++
+			n += int(b)
+			if b != 255 {
+				return i, n, true
+			}
+		}
+	}
+
+	for i := 0; i < len(block); {
+		token := block[i]
+		i++
+		var literals, match int
+		var ok bool
+		i, literals, ok = length(i, int(token>>4))
+		if !ok || literals > len(block)-i {
+			return nil, corrupt
+		}
+		out = append(out, block[i:i+literals]...)
+		i += literals
+		if i == len(block) {
+			break // the last sequence is only literals
+		}
+
+		if i+2 > len(block) {
+			return nil, corrupt
+		}
+		offset := int(binary.LittleEndian.Uint16(block[i:]))
+		i += 2
+		i, match, ok = length(i, int(token&15))
+		if !ok || offset == 0 || offset > len(out) {
+			return nil, corrupt
+		}
+		// Matches may overlap what they copy, so copy a byte at a time
+		for start, end := len(out)-offset, len(out)-offset+match+4; start < end; start++ {
+			out = append(out, out[start])
+		}
+	}
+	return out, nil
+}
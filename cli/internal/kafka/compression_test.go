@@ -0,0 +1,152 @@
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// LZ4 frames written by the lz4 command-line tool (v1.9.4)
+func TestDecodeLZ4(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame string
+		want  string
+	}{
+		{
+			name:  "default frame",
+			frame: "04224d186440a7130000006f68656c6c6f2006000a802c206b61666b612100000000892447d5",
+			want:  "hello hello hello hello hello hello, kafka!",
+		},
+		{
+			name:  "block checksums, no content checksum",
+			frame: "04224d187040ad130000006f68656c6c6f2006000a802c206b61666b6121f2422afc00000000",
+			want:  "hello hello hello hello hello hello, kafka!",
+		},
+		{
+			name:  "literals only",
+			frame: "04224d186440a70300008061626300000000ff53d132",
+			want:  "abc",
+		},
+	}
+	for _, tt := range tests {
+		frame, _ := hex.DecodeString(tt.frame)
+		got, err := decodeLZ4(frame)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: decodeLZ4 = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+// TestDecodeLZ4LinkedBlocks decodes a frame written by 'lz4 -BD -B4', whose second block
+// repeats text only found in the first
+func TestDecodeLZ4LinkedBlocks(t *testing.T) {
+	frame, err := os.ReadFile("testdata/linked-blocks.lz4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unique strings.Builder
+	for i := 0; i < 60; i++ {
+		fmt.Fprintf(&unique, "event-%04d,", i)
+	}
+	want := strings.Repeat("x", 65000) + unique.String() + unique.String() + "tail"
+
+	got, err := decodeLZ4(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("decodeLZ4 returned %d bytes that don't match the %d expected", len(got), len(want))
+	}
+}
+
+func TestDecodeLZ4Errors(t *testing.T) {
+	valid, _ := hex.DecodeString("04224d186440a7130000006f68656c6c6f2006000a802c206b61666b612100000000892447d5")
+	tests := []struct {
+		name  string
+		frame []byte
+		error string
+	}{
+		{"not a frame", []byte("hello, kafka"), "not an LZ4 frame"},
+		{"truncated", valid[:20], "truncated block"},
+		{"no end mark", valid[:len(valid)-8], "truncated frame"},
+		{"dictionary", append([]byte{0x04, 0x22, 0x4d, 0x18, 0x65}, valid[5:]...), "dictionary"},
+		// A literal 'a', then a match 5 bytes back
+		{"bad offset", append(bytes.Clone(valid[:7]), 4, 0, 0, 0, 0x10, 'a', 5, 0, 0, 0, 0, 0), "corrupt block"},
+	}
+	for _, tt := range tests {
+		if _, err := decodeLZ4(tt.frame); err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.error)
+		}
+	}
+}
+
+// TestDecompressedBatches decodes batches whose records are compressed with each codec
+func TestDecompressedBatches(t *testing.T) {
+	records := encodeBatch(goldenMessages, batchTime)[61:]
+	// The default frame header, an uncompressed block, the end mark and a content
+	// checksum, which isn't checked
+	lz4Records, _ := hex.DecodeString("04224d186440a7")
+	lz4Records = binary.LittleEndian.AppendUint32(lz4Records, uint32(len(records))|1<<31)
+	lz4Records = append(append(lz4Records, records...), 0, 0, 0, 0, 0, 0, 0, 0)
+
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	writer.Write(records)
+	writer.Close()
+
+	snappy := s2.EncodeSnappy(nil, records)
+	xerial := append(append(bytes.Clone(xerialHeader), 0, 0, 0, 1, 0, 0, 0, 1), binary.BigEndian.AppendUint32(nil, uint32(len(snappy)))...)
+	xerial = append(xerial, snappy...)
+
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zstandard := encoder.EncodeAll(records, nil)
+
+	want, err := decodeBatches("orders", 0, encodeBatch(goldenMessages, batchTime), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		codec   int16
+		records []byte
+	}{
+		{"gzip", compressionGzip, gzipped.Bytes()},
+		{"snappy", compressionSnappy, snappy},
+		{"xerial snappy", compressionSnappy, xerial},
+		{"lz4", compressionLZ4, lz4Records},
+		{"zstd", compressionZstd, zstandard},
+	}
+	for _, tt := range tests {
+		got, err := decodeBatches("orders", 0, frameBatch(tt.codec, 2, batchTime, tt.records), 0)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: decodeBatches =\n%+v\nwant\n%+v", tt.name, got, want)
+		}
+	}
+}
+
+// FuzzDecodeLZ4 checks that decoding never panics on arbitrary frames
+func FuzzDecodeLZ4(f *testing.F) {
+	valid, _ := hex.DecodeString("04224d186440a7130000006f68656c6c6f2006000a802c206b61666b612100000000892447d5")
+	f.Add(valid)
+	f.Add(valid[:20])
+	f.Fuzz(func(t *testing.T, frame []byte) {
+		decodeLZ4(frame)
+	})
+}
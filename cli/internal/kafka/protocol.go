@@ -0,0 +1,235 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// API keys of the requests the client makes
+const (
+	apiProduce          = 0
+	apiFetch            = 1
+	apiListOffsets      = 2
+	apiMetadata         = 3
+	apiSaslHandshake    = 17
+	apiSaslAuthenticate = 36
+)
+
+// encoder builds a request body in Kafka's big-endian wire format
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int8(n int8) {
+	e.buf = append(e.buf, byte(n))
+}
+
+func (e *encoder) int16(n int16) {
+	e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+}
+
+func (e *encoder) int32(n int32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+}
+
+func (e *encoder) int64(n int64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(n))
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// nullableString writes "" as null
+func (e *encoder) nullableString(s string) {
+	if s == "" {
+		e.int16(-1)
+		return
+	}
+	e.string(s)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) arrayLength(n int) {
+	e.int32(int32(n))
+}
+
+// varint and varlong are the zig-zag integers of record batches
+func (e *encoder) varint(n int64) {
+	e.buf = binary.AppendVarint(e.buf, n)
+}
+
+// varBytes writes a varint length then the bytes; nil is written as null
+func (e *encoder) varBytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+var errShortResponse = errors.New("kafka: response too short")
+
+// decoder reads a response body. The first read past the end sets err and every later
+// read returns zero values, so responses can be decoded without checking each field.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = errShortResponse
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) int8() int8 {
+	if b := d.take(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.take(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.take(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+// bytes returns nil for null
+func (d *decoder) bytes() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// arrayLength returns 0 for a null array
+func (d *decoder) arrayLength() int {
+	n := d.int32()
+	if n < 0 {
+		return 0
+	}
+	if int(n) > len(d.buf) {
+		d.err = errShortResponse
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	n, size := binary.Varint(d.buf)
+	if size <= 0 {
+		d.err = errShortResponse
+		return 0
+	}
+	d.buf = d.buf[size:]
+	return n
+}
+
+// varBytes returns nil for null
+func (d *decoder) varBytes() []byte {
+	n := d.varint()
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// Error is an error code returned by a broker
+type Error struct {
+	Code int16
+}
+
+// Error codes the client handles
+const (
+	errOffsetOutOfRange      = 1
+	errUnknownTopicPartition = 3
+	errLeaderNotAvailable    = 5
+	errNotLeader             = 6
+)
+
+var errorNames = map[int16]string{
+	1:  "offset out of range",
+	2:  "corrupt message",
+	3:  "unknown topic or partition",
+	5:  "leader not available",
+	6:  "not leader for partition",
+	7:  "request timed out",
+	10: "message too large",
+	17: "invalid topic",
+	19: "not enough replicas",
+	20: "not enough replicas after append",
+	29: "topic authorization failed",
+	31: "cluster authorization failed",
+	33: "unsupported SASL mechanism",
+	34: "illegal SASL state",
+	35: "unsupported version",
+	58: "SASL authentication failed",
+	87: "invalid record",
+}
+
+func (e Error) Error() string {
+	if name, ok := errorNames[e.Code]; ok {
+		return "kafka: " + name
+	}
+	return fmt.Sprintf("kafka: error code %d", e.Code)
+}
+
+// Retriable reports whether the request may succeed once the client has refreshed its
+// view of the cluster
+func (e Error) Retriable() bool {
+	switch e.Code {
+	case errUnknownTopicPartition, errLeaderNotAvailable, errNotLeader, 7, 19, 20:
+		return true
+	}
+	return false
+}
+
+func errorCode(code int16) error {
+	if code == 0 {
+		return nil
+	}
+	return Error{Code: code}
+}
@@ -0,0 +1,156 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"time"
+)
+
+// Record batch attributes
+const (
+	compressionMask = 0x07
+	controlBatch    = 0x20
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Header is a record header
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is a record of a Kafka topic partition
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+	Timestamp time.Time
+}
+
+// encodeBatch encodes messages as an uncompressed record batch (magic 2), timestamped
+// with now
+func encodeBatch(messages []Message, now time.Time) []byte {
+	var records encoder
+	for i, message := range messages {
+		var record encoder
+		record.int8(0) // attributes
+		record.varint(0)
+		record.varint(int64(i))
+		record.varBytes(message.Key)
+		record.varBytes(message.Value)
+		record.varint(int64(len(message.Headers)))
+		for _, header := range message.Headers {
+			record.varBytes([]byte(header.Key))
+			record.varBytes(header.Value)
+		}
+		records.varint(int64(len(record.buf)))
+		records.buf = append(records.buf, record.buf...)
+	}
+	return frameBatch(compressionNone, len(messages), now, records.buf)
+}
+
+// frameBatch wraps a batch's records, compressed with codec, in the batch header
+func frameBatch(codec int16, count int, now time.Time, records []byte) []byte {
+	millis := now.UnixMilli()
+
+	// The CRC covers everything from the attributes to the end of the batch
+	var body encoder
+	body.int16(codec) // attributes
+	body.int32(int32(count - 1))
+	body.int64(millis) // first timestamp
+	body.int64(millis) // max timestamp
+	body.int64(-1)     // producer ID
+	body.int16(-1)     // producer epoch
+	body.int32(-1)     // base sequence
+	body.arrayLength(count)
+	body.buf = append(body.buf, records...)
+
+	var batch encoder
+	batch.int64(0)                                // base offset
+	batch.int32(int32(4 + 1 + 4 + len(body.buf))) // length: leader epoch, magic, CRC and body
+	batch.int32(-1)                               // partition leader epoch
+	batch.int8(2)                                 // magic
+	batch.buf = binary.BigEndian.AppendUint32(batch.buf, crc32.Checksum(body.buf, castagnoli))
+	batch.buf = append(batch.buf, body.buf...)
+	return batch.buf
+}
+
+// decodeBatches decodes the record batches of a fetch response, returning the messages
+// at or after offset. A batch cut short at the end of the response is ignored, as the
+// broker only sends whole batches up to the requested size.
+func decodeBatches(topic string, partition int32, data []byte, offset int64) ([]Message, error) {
+	var messages []Message
+	for len(data) >= 17 {
+		baseOffset := int64(binary.BigEndian.Uint64(data))
+		length := int(int32(binary.BigEndian.Uint32(data[8:])))
+		if length <= 0 || 12+length > len(data) {
+			break
+		}
+		batch := data[12 : 12+length]
+		data = data[12+length:]
+
+		if len(batch) < 5 {
+			return nil, fmt.Errorf("kafka: corrupt record batch")
+		}
+		if magic := batch[4]; magic != 2 {
+			return nil, fmt.Errorf("kafka: unsupported record format (magic %d)", magic)
+		}
+		if len(batch) < 49 {
+			return nil, fmt.Errorf("kafka: corrupt record batch")
+		}
+		if crc := binary.BigEndian.Uint32(batch[5:]); crc != crc32.Checksum(batch[9:], castagnoli) {
+			return nil, fmt.Errorf("kafka: record batch at offset %d failed its CRC check", baseOffset)
+		}
+
+		d := decoder{buf: batch[9:]}
+		attributes := d.int16()
+		d.int32() // last offset delta
+		firstTimestamp := d.int64()
+		d.int64() // max timestamp
+		d.int64() // producer ID
+		d.int16() // producer epoch
+		d.int32() // base sequence
+		count := int(d.int32())
+		if attributes&controlBatch != 0 {
+			continue
+		}
+
+		records, err := decompress(attributes&compressionMask, d.buf)
+		if err != nil {
+			return nil, fmt.Errorf("kafka: record batch at offset %d: %w", baseOffset, err)
+		}
+
+		d = decoder{buf: records}
+		for i := 0; i < count; i++ {
+			recordLength := d.varint()
+			r := decoder{buf: d.take(int(recordLength))}
+			r.int8() // attributes
+			timestampDelta := r.varint()
+			offsetDelta := r.varint()
+			message := Message{
+				Topic:     topic,
+				Partition: partition,
+				Offset:    baseOffset + offsetDelta,
+				Key:       r.varBytes(),
+				Value:     r.varBytes(),
+				Timestamp: time.UnixMilli(firstTimestamp + timestampDelta),
+			}
+			headers := int(r.varint())
+			for j := 0; j < headers && r.err == nil; j++ {
+				message.Headers = append(message.Headers, Header{Key: string(r.varBytes()), Value: r.varBytes()})
+			}
+			if r.err != nil || d.err != nil {
+				return nil, fmt.Errorf("kafka: corrupt record at offset %d", message.Offset)
+			}
+			if message.Offset >= offset {
+				messages = append(messages, message)
+			}
+		}
+	}
+	return messages, nil
+}
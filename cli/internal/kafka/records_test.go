@@ -0,0 +1,139 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var batchTime = time.UnixMilli(1700000000000)
+
+var goldenMessages = []Message{
+	{Key: []byte("k1"), Value: []byte(`{"a":1}`), Headers: []Header{{Key: "es-type", Value: []byte("order.placed")}}},
+	{Value: []byte("v")},
+}
+
+// goldenBatch is goldenMessages encoded as a record batch, field by field
+var goldenBatch = strings.Join([]string{
+	"0000000000000000", // base offset
+	"0000005e",         // length: 94 bytes from here
+	"ffffffff",         // partition leader epoch
+	"02",               // magic
+	"221efa4b",         // CRC-32C of the rest
+	"0000",             // attributes: no compression
+	"00000001",         // last offset delta
+	"0000018bcfe56800", // first timestamp: 1700000000000
+	"0000018bcfe56800", // max timestamp
+	"ffffffffffffffff", // producer ID
+	"ffff",             // producer epoch
+	"ffffffff",         // base sequence
+	"00000002",         // records
+	// Record 0: length 36, attributes, timestamp delta 0, offset delta 0, key "k1",
+	// value `{"a":1}`, one header "es-type": "order.placed"
+	"48", "00", "00", "00", "04", "6b31", "0e", "7b2261223a317d",
+	"02", "0e", "65732d74797065", "18", "6f726465722e706c61636564",
+	// Record 1: length 7, attributes, timestamp delta 0, offset delta 1, null key,
+	// value "v", no headers
+	"0e", "00", "00", "02", "01", "02", "76", "00",
+}, "")
+
+func TestEncodeBatch(t *testing.T) {
+	got := hex.EncodeToString(encodeBatch(goldenMessages, batchTime))
+	if got != goldenBatch {
+		t.Errorf("encodeBatch =\n%s\nwant\n%s", got, goldenBatch)
+	}
+}
+
+func TestDecodeBatches(t *testing.T) {
+	data, _ := hex.DecodeString(goldenBatch)
+	// A batch at offset 40, and the start of another cut short by the fetch size
+	copy(data, []byte{0, 0, 0, 0, 0, 0, 0, 40})
+	data = append(data, data[:30]...)
+
+	messages, err := decodeBatches("orders", 3, data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Message{
+		{Topic: "orders", Partition: 3, Offset: 40, Key: []byte("k1"), Value: []byte(`{"a":1}`),
+			Headers: []Header{{Key: "es-type", Value: []byte("order.placed")}}, Timestamp: batchTime},
+		{Topic: "orders", Partition: 3, Offset: 41, Value: []byte("v"), Timestamp: batchTime},
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("decodeBatches =\n%+v\nwant\n%+v", messages, want)
+	}
+
+	// Messages before the requested offset are dropped
+	messages, err = decodeBatches("orders", 3, data, 41)
+	if err != nil || len(messages) != 1 || messages[0].Offset != 41 {
+		t.Errorf("decodeBatches from 41 = %+v, %v", messages, err)
+	}
+}
+
+func TestDecodeBatchesErrors(t *testing.T) {
+	batch, _ := hex.DecodeString(goldenBatch)
+	tests := []struct {
+		name   string
+		modify func(b []byte)
+		error  string
+	}{
+		{"bad CRC", func(b []byte) { b[len(b)-1] ^= 1 }, "failed its CRC check"},
+		{"old format", func(b []byte) { b[16] = 1 }, "unsupported record format (magic 1)"},
+	}
+	for _, tt := range tests {
+		b := bytes.Clone(batch)
+		tt.modify(b)
+		if _, err := decodeBatches("orders", 0, b, 0); err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.error)
+		}
+	}
+
+	records := encodeBatch(goldenMessages, batchTime)[61:]
+	if _, err := decodeBatches("orders", 0, frameBatch(7, 2, batchTime, records), 0); err == nil || !strings.Contains(err.Error(), "unknown compression codec 7") {
+		t.Errorf("codec 7: error = %v", err)
+	}
+}
+
+// TestMurmur2 checks the hash against the Java client's own test cases
+// (org.apache.kafka.common.utils.UtilsTest.testMurmur2)
+func TestMurmur2(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	}
+	for _, tt := range tests {
+		if got := murmur2([]byte(tt.key)); got != tt.want {
+			t.Errorf("murmur2(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestPartitionFor(t *testing.T) {
+	// murmur2("foobar") is -790332482, which is 1357151166 once positive
+	for partitions, want := range map[int]int32{1: 0, 3: 0, 7: 1357151166 % 7, 12: 1357151166 % 12} {
+		if got := partitionFor([]byte("foobar"), partitions); got != want {
+			t.Errorf("partitionFor(foobar, %d) = %d, want %d", partitions, got, want)
+		}
+	}
+}
+
+// FuzzDecodeBatches checks that decoding never panics on arbitrary fetch responses
+func FuzzDecodeBatches(f *testing.F) {
+	golden, _ := hex.DecodeString(goldenBatch)
+	f.Add(golden)
+	f.Add(golden[:40])
+	f.Add(append(bytes.Clone(golden), golden...))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		decodeBatches("orders", 0, data, 0)
+	})
+}
@@ -0,0 +1,38 @@
+// Package labels checks the key=value labels attached to resources, such as
+// team=payments, so large installations can slice them by ownership
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxLength is the longest a label's name, prefix-less key or value can be
+const maxLength = 63
+
+var (
+	namePattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+	prefixPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+)
+
+// Check checks a label. As in Kubernetes, a key is a name with an optional DNS
+// subdomain prefix, e.g. 'team' or 'example.com/tier'. Names and values are up to 63
+// letters, digits, '-', '_' and '.', starting and ending with a letter or digit; values
+// may be empty.
+func Check(key, value string) error {
+	name := key
+	if prefix, rest, ok := strings.Cut(key, "/"); ok {
+		if len(prefix) > 253 || !prefixPattern.MatchString(prefix) {
+			return fmt.Errorf("invalid label key '%s' (the prefix must be a DNS subdomain such as example.com)", key)
+		}
+		name = rest
+	}
+	if len(name) > maxLength || !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid label key '%s' (expected up to %d letters, digits, '-', '_' and '.', starting and ending with a letter or digit)", key, maxLength)
+	}
+	if value != "" && (len(value) > maxLength || !namePattern.MatchString(value)) {
+		return fmt.Errorf("invalid value '%s' for label '%s' (expected up to %d letters, digits, '-', '_' and '.', starting and ending with a letter or digit)", value, key, maxLength)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package labels
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Requirement is one condition of a selector on a resource's labels
+type Requirement struct {
+	Key    string
+	Value  string
+	Negate bool // the label must not have the value, or must be missing if Exists
+	Exists bool // only the label's presence is checked
+}
+
+// Selector selects resources by their labels: all of its requirements must hold
+type Selector []Requirement
+
+// ParseSelector parses a selector of comma-separated requirements, as in kubectl:
+// 'key=value' (or 'key==value'), 'key!=value', 'key' for a label that is set and '!key'
+// for one that isn't. An empty selector selects everything.
+func ParseSelector(text string) (Selector, error) {
+	var selector Selector
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var r Requirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			r = Requirement{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value), Negate: true}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			r = Requirement{Key: strings.TrimSpace(key), Value: strings.TrimSpace(strings.TrimPrefix(value, "="))}
+		case strings.HasPrefix(part, "!"):
+			r = Requirement{Key: strings.TrimSpace(part[1:]), Negate: true, Exists: true}
+		default:
+			r = Requirement{Key: part, Exists: true}
+		}
+		if r.Key == "" {
+			return nil, fmt.Errorf("invalid selector '%s' (expected 'key=value', 'key!=value', 'key' or '!key')", part)
+		}
+		if err := Check(r.Key, r.Value); err != nil {
+			return nil, fmt.Errorf("invalid selector '%s': %w", part, err)
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// Matches reports whether labels meet all of the selector's requirements
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		value, ok := labels[r.Key]
+		var holds bool
+		if r.Exists {
+			holds = ok
+		} else {
+			holds = ok && value == r.Value
+		}
+		if holds == r.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// String writes the selector in the syntax ParseSelector reads
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		switch {
+		case r.Exists && r.Negate:
+			parts[i] = "!" + r.Key
+		case r.Exists:
+			parts[i] = r.Key
+		case r.Negate:
+			parts[i] = r.Key + "!=" + r.Value
+		default:
+			parts[i] = r.Key + "=" + r.Value
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Parse parses labels given as 'key=value' flags, such as --label team=payments. A key
+// may only be given once.
+func Parse(entries []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, ok := strings.Cut(entry, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label '%s' (expected key=value)", entry)
+		}
+		if err := Check(key, value); err != nil {
+			return nil, err
+		}
+		if _, ok := parsed[key]; ok {
+			return nil, fmt.Errorf("label '%s' is given more than once", key)
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
+
+// Format writes labels as 'key=value' pairs sorted by key and separated by commas
+func Format(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
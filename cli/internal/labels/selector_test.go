@@ -0,0 +1,146 @@
+package labels
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		text  string
+		want  Selector
+		error string
+	}{
+		{text: "", want: nil},
+		{text: " , ,", want: nil},
+		{text: "team=payments", want: Selector{{Key: "team", Value: "payments"}}},
+		{text: "team==payments", want: Selector{{Key: "team", Value: "payments"}}},
+		{text: "env!=prod", want: Selector{{Key: "env", Value: "prod", Negate: true}}},
+		{text: "team", want: Selector{{Key: "team", Exists: true}}},
+		{text: "!deprecated", want: Selector{{Key: "deprecated", Negate: true, Exists: true}}},
+		{text: "example.com/tier=gold", want: Selector{{Key: "example.com/tier", Value: "gold"}}},
+		{text: "team=", want: Selector{{Key: "team"}}},
+		{text: "env!=", want: Selector{{Key: "env", Negate: true}}},
+		{text: " team = payments , ! deprecated ,env != prod ", want: Selector{
+			{Key: "team", Value: "payments"},
+			{Key: "deprecated", Negate: true, Exists: true},
+			{Key: "env", Value: "prod", Negate: true},
+		}},
+		// Requirements on the same key must all hold, as in kubectl
+		{text: "env=prod,env!=staging", want: Selector{{Key: "env", Value: "prod"}, {Key: "env", Value: "staging", Negate: true}}},
+		{text: "=payments", error: "invalid selector '=payments' (expected"},
+		{text: "!=prod", error: "invalid selector '!=prod' (expected"},
+		{text: "!", error: "invalid selector '!' (expected"},
+		{text: "team,==x", error: "invalid selector '==x' (expected"},
+		{text: "my team=payments", error: "invalid label key 'my team'"},
+		{text: "-team", error: "invalid label key '-team'"},
+		{text: "!team=x", error: "invalid label key '!team'"},
+		{text: "Example.com/tier=gold", error: "the prefix must be a DNS subdomain"},
+		{text: strings.Repeat("k", 64), error: "invalid label key"},
+		{text: "team=pay ments", error: "invalid value 'pay ments' for label 'team'"},
+		{text: "team===payments", error: "invalid value '=payments'"},
+		{text: "env!==prod", error: "invalid value '=prod'"},
+		{text: "team=" + strings.Repeat("v", 64), error: "invalid value"},
+	}
+	for _, tt := range tests {
+		got, err := ParseSelector(tt.text)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("ParseSelector(%q) error = %v, want one containing %q", tt.text, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSelector(%q) = %+v, %v, want %+v", tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	labels := map[string]string{"team": "payments", "env": "prod", "empty": ""}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"team=payments", true},
+		{"team=billing", false},
+		{"team!=billing", true},
+		{"missing!=x", true},
+		{"env!=prod", false},
+		{"empty=", true},
+		{"missing=", false},
+		{"team", true},
+		{"missing", false},
+		{"!missing", true},
+		{"!team", false},
+		{"team=payments,env=prod,!missing", true},
+		{"team=payments,env=staging", false},
+	}
+	for _, tt := range tests {
+		selector, err := ParseSelector(tt.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := selector.Matches(labels); got != tt.want {
+			t.Errorf("ParseSelector(%q).Matches(%v) = %v, want %v", tt.selector, labels, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		entries []string
+		want    map[string]string
+		error   string
+	}{
+		{entries: nil, want: map[string]string{}},
+		{entries: []string{"team=payments", " env = prod "}, want: map[string]string{"team": "payments", "env": "prod"}},
+		{entries: []string{"example.com/tier=gold"}, want: map[string]string{"example.com/tier": "gold"}},
+		{entries: []string{"deprecated="}, want: map[string]string{"deprecated": ""}},
+		{entries: []string{"url=a=b"}, error: "invalid value 'a=b' for label 'url'"},
+		{entries: []string{"team"}, error: "invalid label 'team' (expected key=value)"},
+		{entries: []string{"=payments"}, error: "invalid label '=payments' (expected key=value)"},
+		{entries: []string{" =payments"}, error: "invalid label ' =payments' (expected key=value)"},
+		{entries: []string{"my team=payments"}, error: "invalid label key 'my team'"},
+		{entries: []string{"team=payments_"}, error: "invalid value 'payments_'"},
+		{entries: []string{"team=payments", "team=billing"}, error: "label 'team' is given more than once"},
+		{entries: []string{"team=payments", " team =payments"}, error: "label 'team' is given more than once"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.entries)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Parse(%q) error = %v, want one containing %q", tt.entries, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %v, %v, want %v", tt.entries, got, err, tt.want)
+		}
+	}
+}
+
+// FuzzParseSelector checks that ParseSelector never panics, and that a selector it
+// parses reads back the same once written with String
+func FuzzParseSelector(f *testing.F) {
+	for _, seed := range []string{"", "team=payments", "team==payments,env!=prod", "team,!deprecated", "example.com/tier=gold", " a = b ,", "!=", "a!==b", "a===b", "!a=b"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		selector, err := ParseSelector(text)
+		if err != nil {
+			return
+		}
+		for _, r := range selector {
+			if err := Check(r.Key, r.Value); err != nil || (r.Exists && r.Value != "") {
+				t.Fatalf("ParseSelector(%q) = %+v, with invalid requirement %+v", text, selector, r)
+			}
+		}
+		again, err := ParseSelector(selector.String())
+		if err != nil || !reflect.DeepEqual(again, selector) {
+			t.Fatalf("ParseSelector(%q) = %+v, but %q reads back as %+v, %v", text, selector, selector.String(), again, err)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// Package ledger records the idempotency keys of published events, per server, so a
+// publish repeated against a server that doesn't deduplicate by key can skip the events
+// already published. Keys are kept for a week.
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/event-store/cli/internal/config"
+)
+
+// Retention is how long a key is remembered after its events were published
+const Retention = 7 * 24 * time.Hour
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Record is what was published under a key
+type Record struct {
+	EventIDs  []string  `json:"eventIds"`
+	Published time.Time `json:"published"`
+}
+
+// Ledger is the idempotency keys published to a server
+type Ledger struct {
+	Server string            `json:"server"`
+	Keys   map[string]Record `json:"keys"`
+
+	path string
+}
+
+// Open reads the ledger of a server from ~/.es/ledger/<server host>.json, dropping keys
+// older than Retention
+func Open(server string) (*Ledger, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	l := &Ledger{
+		Server: server,
+		Keys:   map[string]Record{},
+		path:   filepath.Join(dir, "ledger", unsafePathChars.ReplaceAllString(host, "_")+".json"),
+	}
+
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("invalid idempotency ledger %s: %w", l.path, err)
+	}
+	if l.Keys == nil {
+		l.Keys = map[string]Record{}
+	}
+	cutoff := time.Now().Add(-Retention)
+	for key, record := range l.Keys {
+		if record.Published.Before(cutoff) {
+			delete(l.Keys, key)
+		}
+	}
+	return l, nil
+}
+
+// Lookup returns the IDs of the events published under a key
+func (l *Ledger) Lookup(key string) ([]string, bool) {
+	record, ok := l.Keys[key]
+	return record.EventIDs, ok
+}
+
+// Add records the IDs of the events published under a key
+func (l *Ledger) Add(key string, eventIDs []string) {
+	l.Keys[key] = Record{EventIDs: eventIDs, Published: time.Now().UTC()}
+}
+
+// Save writes the ledger, replacing the file in one step so a crash never leaves a
+// partial file
+func (l *Ledger) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to write idempotency ledger: %w", err)
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write idempotency ledger: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write idempotency ledger: %w", err)
+	}
+	return nil
+}
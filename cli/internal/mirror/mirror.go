@@ -0,0 +1,303 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/copier"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/transform"
+)
+
+// maxBackoff is the longest a mirror waits between syncs after repeated failures
+const maxBackoff = time.Minute
+
+// Checkpoint records how far each topic has been mirrored, so a restarted mirror carries
+// on where it stopped
+type Checkpoint struct {
+	Source      string            `json:"source"`
+	Destination string            `json:"destination"`
+	Topics      map[string]string `json:"topics"` // topic -> last source event ID mirrored
+	Updated     time.Time         `json:"updated"`
+}
+
+// LoadCheckpoint reads a checkpoint file, returning an empty checkpoint if it does not
+// exist yet
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Checkpoint{Topics: map[string]string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Topics == nil {
+		checkpoint.Topics = map[string]string{}
+	}
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint file, replacing it in one step so a crash never leaves a
+// partial file
+func (c *Checkpoint) Save(path string) error {
+	c.Updated = time.Now().UTC()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// TopicStatus is the progress of one mirrored topic
+type TopicStatus struct {
+	Topic          string    `json:"topic"`
+	LastEventID    string    `json:"lastEventId,omitempty"` // last source event mirrored
+	SourceSequence int64     `json:"sourceSequence"`
+	Lag            int64     `json:"lag"`      // source events not mirrored yet
+	Mirrored       int       `json:"mirrored"` // events published since the mirror started
+	LastSync       time.Time `json:"lastSync,omitempty"`
+	LastError      string    `json:"lastError,omitempty"`
+}
+
+// Mirror continuously replicates topics from one event store to another. Events are
+// republished to topics of the same name, which are created with the source topic's
+// schemas when missing. Delivery is at least once: a batch published just before a crash
+// can be published again on restart.
+type Mirror struct {
+	Source         *client.Client
+	Destination    *client.Client
+	Topics         []string // topics to mirror, or every source topic when empty
+	Checkpoint     *Checkpoint
+	CheckpointPath string
+	BatchSize      int
+	TypeMap        map[string]string     // source event type -> destination event type
+	Transform      transform.Transformer // applied to payloads; may drop events
+	// Report, if set, is called after each topic sync that published events or failed
+	Report func(status TopicStatus, published int, err error)
+
+	mu       sync.Mutex
+	statuses map[string]*TopicStatus
+	created  map[string]bool // destination topics known to exist
+}
+
+// Run syncs every interval until ctx is done. After a failed sync it waits longer each
+// time, up to a minute, so an unreachable server is not hammered.
+func (m *Mirror) Run(ctx context.Context, interval time.Duration) error {
+	wait := interval
+	for {
+		if err := m.Sync(ctx); err != nil {
+			wait *= 2
+			if wait > maxBackoff {
+				wait = maxBackoff
+			}
+		} else {
+			wait = interval
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Sync mirrors the new events of every topic once, returning the first error. A failing
+// topic does not stop the others.
+func (m *Mirror) Sync(ctx context.Context) error {
+	topics := m.Topics
+	if len(topics) == 0 {
+		sourceTopics, err := m.Source.GetTopics()
+		if err != nil {
+			m.report(TopicStatus{Topic: "*"}, 0, err)
+			return err
+		}
+		for _, topic := range sourceTopics {
+			topics = append(topics, topic.Name)
+		}
+	}
+
+	var firstErr error
+	for _, topic := range topics {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := m.syncTopic(ctx, topic); err != nil && firstErr == nil && !errors.Is(err, context.Canceled) {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m *Mirror) syncTopic(ctx context.Context, name string) error {
+	status := m.status(name)
+	fail := func(err error) error {
+		m.mu.Lock()
+		status.LastError = err.Error()
+		snapshot := *status
+		m.mu.Unlock()
+		m.report(snapshot, 0, err)
+		return err
+	}
+
+	source, err := m.Source.GetTopic(name)
+	if err != nil {
+		return fail(err)
+	}
+	if err := m.ensureDestination(source); err != nil {
+		return fail(err)
+	}
+
+	after := int64(0)
+	if id, err := eventid.Parse(m.lastEventID(name)); err == nil {
+		after = id.Sequence
+	}
+
+	published := 0
+	if int64(source.Sequence) > after {
+		result, err := copier.Copy(ctx, copier.Options{
+			Source:      m.Source,
+			Destination: m.Destination,
+			SourceTopic: name,
+			DestTopic:   name,
+			After:       after,
+			Through:     int64(source.Sequence),
+			BatchSize:   m.BatchSize,
+			TypeMap:     m.TypeMap,
+			Transform:   m.Transform,
+			Checkpoint: func(lastEventID string) error {
+				return m.advance(name, lastEventID)
+			},
+		}, nil)
+		if result != nil {
+			published = result.Copied
+			if result.LastEventID != "" {
+				if saveErr := m.advance(name, result.LastEventID); err == nil {
+					err = saveErr
+				}
+			}
+		}
+		if err != nil {
+			m.mu.Lock()
+			status.Mirrored += published
+			m.mu.Unlock()
+			return fail(err)
+		}
+	}
+
+	m.mu.Lock()
+	status.Mirrored += published
+	status.SourceSequence = int64(source.Sequence)
+	status.LastEventID = m.Checkpoint.Topics[name]
+	status.Lag = status.SourceSequence
+	if id, err := eventid.Parse(status.LastEventID); err == nil {
+		status.Lag -= id.Sequence
+	}
+	status.LastSync = time.Now()
+	status.LastError = ""
+	snapshot := *status
+	m.mu.Unlock()
+
+	if published > 0 {
+		m.report(snapshot, published, nil)
+	}
+	return nil
+}
+
+// ensureDestination creates the destination topic with the source topic's schemas when
+// it does not exist
+func (m *Mirror) ensureDestination(source *client.Topic) error {
+	if m.created[source.Name] {
+		return nil
+	}
+	_, err := m.Destination.GetTopic(source.Name)
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		err = m.Destination.CreateTopic(source.Name, source.Schemas)
+	}
+	if err != nil {
+		return fmt.Errorf("destination topic '%s': %w", source.Name, err)
+	}
+
+	m.mu.Lock()
+	if m.created == nil {
+		m.created = map[string]bool{}
+	}
+	m.created[source.Name] = true
+	m.mu.Unlock()
+	return nil
+}
+
+// advance records a topic's last mirrored event in the checkpoint file
+func (m *Mirror) advance(topic, lastEventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Checkpoint.Topics[topic] = lastEventID
+	m.statuses[topic].LastEventID = lastEventID
+	return m.Checkpoint.Save(m.CheckpointPath)
+}
+
+func (m *Mirror) lastEventID(topic string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Checkpoint.Topics[topic]
+}
+
+func (m *Mirror) status(topic string) *TopicStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.statuses == nil {
+		m.statuses = map[string]*TopicStatus{}
+	}
+	status, ok := m.statuses[topic]
+	if !ok {
+		status = &TopicStatus{Topic: topic, LastEventID: m.Checkpoint.Topics[topic]}
+		m.statuses[topic] = status
+	}
+	return status
+}
+
+func (m *Mirror) report(status TopicStatus, published int, err error) {
+	if m.Report != nil {
+		m.Report(status, published, err)
+	}
+}
+
+// Statuses returns the progress of every topic synced so far, by topic name
+func (m *Mirror) Statuses() []TopicStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	statuses := make([]TopicStatus, 0, len(m.statuses))
+	for _, status := range m.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Topic < statuses[j].Topic })
+	return statuses
+}
+
+// ServeHTTP serves the topics' progress as JSON
+func (m *Mirror) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"topics": m.Statuses()})
+}
@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/event-store/cli/internal/eventid"
+)
+
+// ConsumerDelivery is how a consumer is keeping up with one of its topics
+type ConsumerDelivery struct {
+	Time         string   `json:"time"` // RFC 3339; when the consumer's position was polled
+	Consumer     string   `json:"consumer"`
+	Topic        string   `json:"topic"`
+	LastEventID  string   `json:"lastEventId,omitempty"`
+	Lag          *int64   `json:"lag"`                    // nil as in ConsumerTopicStatus
+	Rate         *float64 `json:"rate"`                   // events delivered per second, nil until positions have been seen twice
+	LastDelivery string   `json:"lastDelivery,omitempty"` // RFC 3339; "" until a delivery has been seen
+}
+
+// positionSample is a consumer's position in a topic when it was polled
+type positionSample struct {
+	at        time.Time
+	delivered int64
+}
+
+// DeliveryTracker follows consumers' positions in their topics from poll to poll, to
+// tell how fast events are being delivered to each and when one last was
+type DeliveryTracker struct {
+	window  time.Duration
+	samples map[string][]positionSample // by consumer and topic
+	moved   map[string]time.Time        // when each position was last seen to advance
+}
+
+// NewDeliveryTracker creates a tracker that measures delivery rates over window
+func NewDeliveryTracker(window time.Duration) *DeliveryTracker {
+	return &DeliveryTracker{
+		window:  window,
+		samples: map[string][]positionSample{},
+		moved:   map[string]time.Time{},
+	}
+}
+
+// Observe records the consumers' positions in a status polled at a time and returns how
+// each consumer is keeping up with each of its topics, in the status's order. logged is
+// the time each consumer was last delivered an event of each topic, by consumer ID and
+// topic, from servers that keep a delivery log; otherwise it's when a position was last
+// seen to advance.
+func (t *DeliveryTracker) Observe(at time.Time, status *Status, logged map[string]map[string]time.Time) []ConsumerDelivery {
+	var deliveries []ConsumerDelivery
+	for _, consumer := range status.Consumers {
+		for _, position := range consumer.Topics {
+			delivery := ConsumerDelivery{
+				Time:        at.UTC().Format(time.RFC3339),
+				Consumer:    consumer.ID,
+				Topic:       position.Topic,
+				LastEventID: position.LastEventID,
+				Lag:         position.Lag,
+			}
+			key := consumer.ID + "\x00" + position.Topic
+
+			var delivered int64 = -1
+			if position.LastEventID == "" {
+				delivered = 0
+			} else if id, err := eventid.Parse(position.LastEventID); err == nil {
+				delivered = id.Sequence
+			}
+			if delivered >= 0 {
+				samples := t.samples[key]
+				if n := len(samples); n > 0 && delivered != samples[n-1].delivered {
+					if delivered < samples[n-1].delivered {
+						// The consumer was moved back, such as by a replay: start again
+						samples = nil
+					} else {
+						t.moved[key] = at
+					}
+				}
+				samples = append(samples, positionSample{at: at, delivered: delivered})
+				// Keep the samples in the window, and the one before it to measure from
+				for len(samples) > 2 && !samples[1].at.After(at.Add(-t.window)) {
+					samples = samples[1:]
+				}
+				t.samples[key] = samples
+
+				if first := samples[0]; len(samples) > 1 && at.After(first.at) {
+					rate := float64(delivered-first.delivered) / at.Sub(first.at).Seconds()
+					delivery.Rate = &rate
+				}
+			}
+
+			if last, ok := logged[consumer.ID][position.Topic]; ok {
+				delivery.LastDelivery = last.UTC().Format(time.RFC3339)
+			} else if moved, ok := t.moved[key]; ok {
+				delivery.LastDelivery = moved.UTC().Format(time.RFC3339)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries
+}
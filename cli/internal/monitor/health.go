@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// HealthyStatus is the status reported by a healthy event store
+const HealthyStatus = "healthy"
+
+// HealthCheck is the outcome of one health poll
+type HealthCheck struct {
+	Time        time.Time `json:"time"`
+	Status      string    `json:"status"` // server status, or "unreachable"
+	Consumers   int       `json:"consumers"`
+	Dispatchers int       `json:"dispatchers"`
+	Degraded    bool      `json:"degraded"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// HealthThresholds configures when a check counts as degraded
+type HealthThresholds struct {
+	MinDispatchers int // degraded below this many running dispatchers (0 = no minimum)
+}
+
+// HealthMonitor evaluates successive health polls and reports status transitions
+type HealthMonitor struct {
+	thresholds HealthThresholds
+	last       *HealthCheck
+}
+
+// NewHealthMonitor creates a monitor with the given thresholds
+func NewHealthMonitor(thresholds HealthThresholds) *HealthMonitor {
+	return &HealthMonitor{thresholds: thresholds}
+}
+
+// Observe evaluates a poll result, where err is the error from fetching health, and
+// returns the check together with whether it is a transition worth reporting: the first
+// check, a change of status or degradation, or a change in consumer or dispatcher count
+func (m *HealthMonitor) Observe(at time.Time, health *client.Health, err error) (HealthCheck, bool) {
+	check := HealthCheck{Time: at}
+	switch {
+	case err != nil:
+		check.Status = "unreachable"
+		check.Degraded = true
+		check.Reason = err.Error()
+	default:
+		check.Status = health.Status
+		check.Consumers = health.Consumers
+		check.Dispatchers = len(health.RunningDispatchers)
+		check.Reason = m.degradation(check)
+		check.Degraded = check.Reason != ""
+	}
+
+	last := m.last
+	m.last = &check
+	if last == nil {
+		return check, true
+	}
+	changed := check.Status != last.Status ||
+		check.Degraded != last.Degraded ||
+		check.Consumers != last.Consumers ||
+		check.Dispatchers != last.Dispatchers
+	return check, changed
+}
+
+// degradation returns why a reachable server counts as degraded, or "" if it doesn't
+func (m *HealthMonitor) degradation(check HealthCheck) string {
+	if check.Status != HealthyStatus {
+		return fmt.Sprintf("status is '%s'", check.Status)
+	}
+	if m.last != nil && m.last.Status != "unreachable" && check.Dispatchers < m.last.Dispatchers {
+		return fmt.Sprintf("running dispatchers dropped from %d to %d", m.last.Dispatchers, check.Dispatchers)
+	}
+	if check.Dispatchers < m.thresholds.MinDispatchers {
+		return fmt.Sprintf("%d running dispatcher(s), below the minimum of %d", check.Dispatchers, m.thresholds.MinDispatchers)
+	}
+	return ""
+}
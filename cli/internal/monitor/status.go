@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"sort"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+)
+
+// Status is an overview of an event store: its health, topics and how far behind each
+// consumer is
+type Status struct {
+	Server        string           `json:"server"`
+	ServerVersion string           `json:"serverVersion,omitempty"` // "" when the server does not report it
+	Health        string           `json:"health"`
+	Dispatchers   []string         `json:"runningDispatchers"`
+	Events        int64            `json:"events"` // sum of the topic sequences
+	Lag           int64            `json:"lag"`    // events not yet delivered, summed over consumers
+	Topics        []TopicStatus    `json:"topics"`
+	Consumers     []ConsumerStatus `json:"consumers"`
+}
+
+// TopicStatus summarises one topic
+type TopicStatus struct {
+	Name      string `json:"name"`
+	Sequence  int64  `json:"sequence"`
+	Consumers int    `json:"consumers"`
+	MaxLag    int64  `json:"maxLag"`
+}
+
+// ConsumerStatus summarises one consumer and its position in each of its topics
+type ConsumerStatus struct {
+	ID       string                `json:"id"`
+	Callback string                `json:"callback"`
+	Lag      int64                 `json:"lag"`
+	Topics   []ConsumerTopicStatus `json:"topics"`
+}
+
+// ConsumerTopicStatus is a consumer's position in one topic. Lag is nil when the topic
+// does not exist or the position cannot be parsed.
+type ConsumerTopicStatus struct {
+	Topic       string `json:"topic"`
+	LastEventID string `json:"lastEventId,omitempty"`
+	Lag         *int64 `json:"lag"`
+}
+
+// BuildStatus combines health, topics and consumers into a status overview. A consumer's
+// lag in a topic is the number of events after its last delivered event.
+func BuildStatus(server string, health *client.Health, topics []client.Topic, consumers []client.Consumer) *Status {
+	status := &Status{
+		Server:      server,
+		Health:      health.Status,
+		Dispatchers: health.RunningDispatchers,
+	}
+
+	topicIndex := make(map[string]int, len(topics))
+	for _, topic := range topics {
+		topicIndex[topic.Name] = len(status.Topics)
+		status.Topics = append(status.Topics, TopicStatus{Name: topic.Name, Sequence: int64(topic.Sequence)})
+		status.Events += int64(topic.Sequence)
+	}
+
+	for _, consumer := range consumers {
+		consumerStatus := ConsumerStatus{ID: consumer.ID, Callback: consumer.Callback}
+
+		names := make([]string, 0, len(consumer.Topics))
+		for name := range consumer.Topics {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			position := ConsumerTopicStatus{Topic: name, LastEventID: consumer.Topics[name]}
+			if position.LastEventID == "null" {
+				position.LastEventID = ""
+			}
+			if i, ok := topicIndex[name]; ok {
+				topic := &status.Topics[i]
+				topic.Consumers++
+
+				var delivered int64
+				if position.LastEventID != "" {
+					if id, err := eventid.Parse(position.LastEventID); err == nil {
+						delivered = id.Sequence
+					} else {
+						delivered = -1
+					}
+				}
+				if delivered >= 0 {
+					lag := topic.Sequence - delivered
+					if lag < 0 {
+						lag = 0
+					}
+					position.Lag = &lag
+					consumerStatus.Lag += lag
+					if lag > topic.MaxLag {
+						topic.MaxLag = lag
+					}
+				}
+			}
+			consumerStatus.Topics = append(consumerStatus.Topics, position)
+		}
+
+		status.Lag += consumerStatus.Lag
+		status.Consumers = append(status.Consumers, consumerStatus)
+	}
+
+	sort.Slice(status.Topics, func(i, j int) bool { return status.Topics[i].Name < status.Topics[j].Name })
+	sort.SliceStable(status.Consumers, func(i, j int) bool { return status.Consumers[i].Lag > status.Consumers[j].Lag })
+	return status
+}
+
+// ConsumerLags returns each consumer's lag, summed over its topics as in BuildStatus,
+// by consumer ID
+func ConsumerLags(topics []client.Topic, consumers []client.Consumer) map[string]int64 {
+	status := BuildStatus("", &client.Health{}, topics, consumers)
+	lags := make(map[string]int64, len(status.Consumers))
+	for _, consumer := range status.Consumers {
+		lags[consumer.ID] = consumer.Lag
+	}
+	return lags
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// The title and message are passed to osascript and PowerShell through the environment,
+// so they never need quoting for AppleScript or PowerShell
+const (
+	titleEnv   = "ES_NOTIFY_TITLE"
+	messageEnv = "ES_NOTIFY_MESSAGE"
+)
+
+const appleScript = `display notification (system attribute "` + messageEnv + `") with title (system attribute "` + titleEnv + `")`
+
+const powerShellScript = `Add-Type -AssemblyName System.Windows.Forms
+$icon = New-Object System.Windows.Forms.NotifyIcon
+$icon.Icon = [System.Drawing.SystemIcons]::Information
+$icon.Visible = $true
+$icon.ShowBalloonTip(5000, $env:` + titleEnv + `, $env:` + messageEnv + `, 'Info')
+Start-Sleep -Seconds 6
+$icon.Dispose()`
+
+// Check returns an error if desktop notifications cannot be sent on this system
+func Check() error {
+	name, _ := command("", "")
+	if _, err := exec.LookPath(name); err != nil {
+		if name == "notify-send" {
+			return fmt.Errorf("desktop notifications need '%s' (install libnotify, e.g. the libnotify-bin package)", name)
+		}
+		return fmt.Errorf("desktop notifications need '%s' (%v)", name, err)
+	}
+	return nil
+}
+
+// Send shows a desktop notification using the system's own tooling: osascript on macOS,
+// a PowerShell balloon tip on Windows and notify-send elsewhere
+func Send(title, message string) error {
+	name, args := command(title, message)
+	notification := exec.Command(name, args...)
+	notification.Env = append(os.Environ(), titleEnv+"="+title, messageEnv+"="+message)
+
+	if runtime.GOOS == "windows" {
+		// The balloon tip is only shown while PowerShell keeps the icon alive, so don't
+		// hold up the caller waiting for it
+		if err := notification.Start(); err != nil {
+			return fmt.Errorf("failed to send notification: %w", err)
+		}
+		go notification.Wait()
+		return nil
+	}
+
+	if out, err := notification.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to send notification: %w: %s", err, out)
+	}
+	return nil
+}
+
+// command returns the program and arguments that show a notification
+func command(title, message string) (string, []string) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "osascript", []string{"-e", appleScript}
+	case "windows":
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", powerShellScript}
+	default:
+		return "notify-send", []string{"--app-name=es", "--", title, message}
+	}
+}
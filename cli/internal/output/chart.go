@@ -0,0 +1,226 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/stats"
+)
+
+// sparkBlocks are the eighth-height blocks a sparkline is drawn with, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a line of block characters scaled to the largest value, one
+// character per value. Zeros are drawn as spaces so gaps stand out.
+func Sparkline(values []int64) string {
+	var peak int64
+	for _, value := range values {
+		if value > peak {
+			peak = value
+		}
+	}
+
+	var b strings.Builder
+	for _, value := range values {
+		if value <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int((value*int64(len(sparkBlocks)) - 1) / peak)
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// Bar draws value as a horizontal bar up to width characters long, scaled to peak
+func Bar(value, peak int64, width int) string {
+	if value <= 0 || peak <= 0 {
+		return ""
+	}
+	n := int(value * int64(width) / peak)
+	if n == 0 {
+		n = 1
+	}
+	return strings.Repeat("█", n)
+}
+
+// Chart styles of PrintVolumeChart
+const (
+	ChartBar  = "bar"
+	ChartLine = "line"
+)
+
+// brailleDots are the bits of the dots of a braille character, by column and by row from
+// the top
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// PrintVolumeChart draws a series of event counts height lines tall, as bars of block
+// characters (ChartBar) or a line of braille dots (ChartLine), with the counts up the
+// side and the times along the bottom. Bars are a character wide and a line's points
+// half a character, and adjacent buckets are added together as needed to fit the
+// terminal, unless --wide.
+func PrintVolumeChart(series *stats.Series, style string, height int) {
+	values := make([]int64, len(series.Buckets))
+	for i, bucket := range series.Buckets {
+		values[i] = bucket.Events
+	}
+	interval, _ := time.ParseDuration(series.Interval)
+	since, _ := time.Parse(time.RFC3339, series.Since)
+	until, _ := time.Parse(time.RFC3339, series.Until)
+	if series.Events == 0 {
+		printText(fmt.Sprintf("No events in '%s' from %s to %s", series.Topic, chartTime(since, interval), chartTime(until, interval)))
+		return
+	}
+
+	var peak int64
+	peakAt := 0
+	for i, value := range values {
+		if value > peak {
+			peak, peakAt = value, i
+		}
+	}
+	label := len(strconv.FormatInt(peak, 10))
+	perChar := 1
+	if style == ChartLine {
+		perChar = 2
+	}
+	merged := 1
+	if width := terminalWidth(); !wide {
+		if width == 0 {
+			width = 80
+		}
+		if columns := (width - label - 2) * perChar; columns > 0 && len(values) > columns {
+			merged = (len(values) + columns - 1) / columns
+		}
+	}
+	if merged > 1 {
+		var sums []int64
+		for i := 0; i < len(values); i += merged {
+			var sum int64
+			for _, value := range values[i:min(i+merged, len(values))] {
+				sum += value
+			}
+			sums = append(sums, sum)
+		}
+		values = sums
+		peak = 0
+		for _, value := range values {
+			peak = max(peak, value)
+		}
+		label = len(strconv.FormatInt(peak, 10))
+	}
+
+	var rows [][]rune
+	if style == ChartLine {
+		rows = lineRows(values, peak, height)
+	} else {
+		rows = barRows(values, peak, height)
+	}
+
+	var lines []string
+	for i, row := range rows {
+		tick, axis := "", "│"
+		switch {
+		case i == 0:
+			tick = strconv.FormatInt(peak, 10)
+		case height >= 4 && i == height/2:
+			if middle := peak * int64(height-i) / int64(height); middle > 0 && middle < peak {
+				tick = strconv.FormatInt(middle, 10)
+			}
+		}
+		if tick != "" {
+			axis = "┤"
+		}
+		lines = append(lines, fmt.Sprintf("%*s %s%s", label, tick, axis, string(row)))
+	}
+	columns := len(rows[0])
+	lines = append(lines, fmt.Sprintf("%*s └%s", label, "0", strings.Repeat("─", columns)))
+	first := chartTime(since, interval)
+	last := chartTime(since.Add(time.Duration(len(series.Buckets)-1)*interval), interval)
+	times := strings.Repeat(" ", label+2) + first
+	if gap := columns - len(first) - len(last); gap >= 2 {
+		times += strings.Repeat(" ", gap) + last
+	}
+	lines = append(lines, times)
+
+	printText(fmt.Sprintf("%s: %d events from %s to %s, per %s", series.Topic, series.Events, chartTime(since, interval), chartTime(until, interval), series.Interval))
+	printBlock(strings.Join(lines, "\n"))
+	summary := fmt.Sprintf("Peak %d at %s, mean %.1f per %s", series.Buckets[peakAt].Events, chartTime(since.Add(time.Duration(peakAt)*interval), interval), float64(series.Events)/float64(len(series.Buckets)), series.Interval)
+	if merged > 1 {
+		unit := "bar"
+		if style == ChartLine {
+			unit = "point"
+		}
+		summary += fmt.Sprintf("; each %s is %d intervals", unit, merged)
+	}
+	printText(summary)
+}
+
+// barRows draws values as bars of eighth-height blocks, height rows tall, top row first
+func barRows(values []int64, peak int64, height int) [][]rune {
+	rows := make([][]rune, height)
+	for i := range rows {
+		rows[i] = []rune(strings.Repeat(" ", len(values)))
+	}
+	for x, value := range values {
+		if value <= 0 {
+			continue
+		}
+		eighths := int((value*int64(height*8) + peak - 1) / peak)
+		for i := range rows {
+			fill := eighths - (height-1-i)*8
+			switch {
+			case fill >= 8:
+				rows[i][x] = sparkBlocks[len(sparkBlocks)-1]
+			case fill > 0:
+				rows[i][x] = sparkBlocks[fill-1]
+			}
+		}
+	}
+	return rows
+}
+
+// lineRows draws values as a line of braille dots, two values to a character, height
+// rows tall, top row first
+func lineRows(values []int64, peak int64, height int) [][]rune {
+	dots := height * 4
+	columns := (len(values) + 1) / 2
+	rows := make([][]rune, height)
+	for i := range rows {
+		rows[i] = make([]rune, columns)
+	}
+	set := func(x, y int) {
+		// y counts dots up from the bottom
+		row := dots - 1 - y
+		rows[row/4][x/2] |= brailleDots[x%2][row%4]
+	}
+	previous := -1
+	for x, value := range values {
+		y := int((value*int64(dots-1) + peak/2) / peak)
+		low, high := y, y
+		if previous >= 0 {
+			low, high = min(y, previous), max(y, previous)
+		}
+		for dot := low; dot <= high; dot++ {
+			set(x, dot)
+		}
+		previous = y
+	}
+	for _, row := range rows {
+		for i := range row {
+			row[i] += 0x2800
+		}
+	}
+	return rows
+}
+
+// chartTime formats a time on a chart: the date for daily or longer intervals, and the
+// date and time otherwise
+func chartTime(t time.Time, interval time.Duration) string {
+	if interval >= 24*time.Hour && interval%(24*time.Hour) == 0 {
+		return t.UTC().Format("2006-01-02")
+	}
+	return t.UTC().Format("2006-01-02 15:04")
+}
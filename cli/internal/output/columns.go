@@ -0,0 +1,249 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// eventFieldHeaders are the headers of the event fields that aren't payload paths
+var eventFieldHeaders = map[string]string{
+	"topic":     "Topic",
+	"id":        "ID",
+	"timestamp": "Timestamp",
+	"type":      "Type",
+	"partition": "Partition",
+	"payload":   "Payload",
+	"metadata":  "Metadata",
+}
+
+// EventColumn is an event field selected for output: topic (from the event's ID), id,
+// timestamp, type, partition, payload, metadata, or a path into the payload or metadata
+// such as payload.customer.id
+type EventColumn struct {
+	Field string
+}
+
+// ParseEventColumns parses the fields selected with --fields
+func ParseEventColumns(fields []string) ([]EventColumn, error) {
+	columns := make([]EventColumn, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		_, ok := eventFieldHeaders[field]
+		if !ok && ((!strings.HasPrefix(field, "payload.") && !strings.HasPrefix(field, "metadata.")) || strings.HasSuffix(field, ".")) {
+			return nil, fmt.Errorf("invalid field '%s' (expected topic, id, timestamp, type, partition, payload, metadata, payload.<path> or metadata.<path>)", field)
+		}
+		columns = append(columns, EventColumn{Field: field})
+	}
+	return columns, nil
+}
+
+// Header returns the column's header: the field's name, or the path for payload and
+// metadata paths
+func (c EventColumn) Header() string {
+	if header, ok := eventFieldHeaders[c.Field]; ok {
+		return header
+	}
+	return c.Field
+}
+
+// Value returns the event's value for the column and whether the event has it
+func (c EventColumn) Value(event client.Event) (interface{}, bool) {
+	switch c.Field {
+	case "topic":
+		topic := TopicOf(event)
+		return topic, topic != ""
+	case "id":
+		return event.ID, true
+	case "timestamp":
+		return event.Timestamp, true
+	case "type":
+		return event.Type, true
+	case "partition":
+		if event.Partition == nil {
+			return nil, false
+		}
+		return *event.Partition, true
+	case "payload":
+		return event.Payload, true
+	case "metadata":
+		return event.Metadata, event.Metadata != nil
+	}
+
+	var current interface{} = event.Payload
+	path := strings.TrimPrefix(c.Field, "payload.")
+	if strings.HasPrefix(c.Field, "metadata.") {
+		current, path = event.Metadata, strings.TrimPrefix(c.Field, "metadata.")
+	}
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// TopicOf returns the topic of an event, from its ID, or "" if the ID isn't valid
+func TopicOf(event client.Event) string {
+	id, err := eventid.Parse(event.ID)
+	if err != nil {
+		return ""
+	}
+	return id.Topic
+}
+
+// Text returns the event's value for the column as text: strings as they are, other
+// values as compact JSON and missing values as ""
+func (c EventColumn) Text(event client.Event) string {
+	value, ok := c.Value(event)
+	if !ok {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// Project returns an event with only the columns' fields, nested as in the event
+// (payload paths become nested objects). Missing fields are left out.
+func Project(event client.Event, columns []EventColumn) map[string]interface{} {
+	projected := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		value, ok := column.Value(event)
+		if !ok {
+			continue
+		}
+		path := strings.Split(column.Field, ".")
+		target := projected
+		for _, part := range path[:len(path)-1] {
+			next, ok := target[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				target[part] = next
+			}
+			target = next
+		}
+		target[path[len(path)-1]] = value
+	}
+	return projected
+}
+
+// PrintEventColumns prints events in table format with only the selected columns
+func PrintEventColumns(events []client.Event, columns []EventColumn) {
+	if len(events) == 0 {
+		fmt.Println("No events found")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	header := make(table.Row, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header()
+	}
+	t.AppendHeader(header)
+	texts := [][]string{eventColumnHeaders(columns)}
+	for _, event := range events {
+		row := make(table.Row, len(columns))
+		for i, column := range columns {
+			row[i] = column.Text(event)
+		}
+		t.AppendRow(row)
+		texts = append(texts, eventColumnTexts(event, columns))
+	}
+	t.SetStyle(getTableStyle())
+	fitColumns(t, texts)
+	render(t)
+}
+
+// PrintEventColumnsCSV prints events as CSV with only the selected columns
+func PrintEventColumnsCSV(events []client.Event, columns []EventColumn) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write(eventColumnHeaders(columns)); err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := writer.Write(eventColumnTexts(event, columns)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintEventColumnsJSON prints events as JSON with only the selected fields
+func PrintEventColumnsJSON(events []client.Event, columns []EventColumn) error {
+	projected := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		projected[i] = Project(event, columns)
+	}
+	return PrintJSON(map[string]interface{}{
+		"events": projected,
+	})
+}
+
+func eventColumnHeaders(columns []EventColumn) []string {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header()
+	}
+	return headers
+}
+
+func eventColumnTexts(event client.Event, columns []EventColumn) []string {
+	texts := make([]string, len(columns))
+	for i, column := range columns {
+		texts[i] = column.Text(event)
+	}
+	return texts
+}
+
+// SortEvents orders events by a field, given as for --fields with an optional ':asc' or
+// ':desc', e.g. 'timestamp:desc' or 'payload.amount'. Numbers sort numerically and
+// events without the field sort last; events with equal values keep their order.
+func SortEvents(events []client.Event, spec string) error {
+	name, descending, err := ParseSortSpec(spec)
+	if err != nil {
+		return err
+	}
+	columns, err := ParseEventColumns([]string{name})
+	if err != nil {
+		return err
+	}
+	column := columns[0]
+
+	sort.SliceStable(events, func(i, j int) bool {
+		a, okA := column.Value(events[i])
+		b, okB := column.Value(events[j])
+		if !okA || !okB {
+			return okA && !okB
+		}
+		x, numA := a.(float64)
+		y, numB := b.(float64)
+		if numA && numB {
+			if descending {
+				return x > y
+			}
+			return x < y
+		}
+		return lessCells(column.Text(events[i]), column.Text(events[j]), false, descending)
+	})
+	return nil
+}
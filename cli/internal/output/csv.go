@@ -7,38 +7,33 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/event-store/cli/internal/aggregate"
+	"github.com/event-store/cli/internal/archive"
+	"github.com/event-store/cli/internal/assert"
+	"github.com/event-store/cli/internal/bench"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/consumers"
+	"github.com/event-store/cli/internal/contract"
+	"github.com/event-store/cli/internal/copier"
+	"github.com/event-store/cli/internal/drift"
+	"github.com/event-store/cli/internal/export"
+	"github.com/event-store/cli/internal/monitor"
+	"github.com/event-store/cli/internal/owners"
+	"github.com/event-store/cli/internal/replay"
+	"github.com/event-store/cli/internal/seed"
+	"github.com/event-store/cli/internal/spec"
+	"github.com/event-store/cli/internal/spool"
+	"github.com/event-store/cli/internal/stats"
+	"github.com/event-store/cli/internal/trace"
+	"github.com/event-store/cli/internal/verify"
+	"github.com/event-store/cli/internal/watch"
 )
 
-// PrintTopicsListCSV prints a list of topics in CSV format
-func PrintTopicsListCSV(topics []client.Topic) error {
-	writer := csv.NewWriter(os.Stdout)
-	defer writer.Flush()
-
-	// Write header
-	if err := writer.Write([]string{"Name", "Sequence", "Schema Count"}); err != nil {
-		return err
-	}
-
-	// Write rows
-	for _, topic := range topics {
-		row := []string{
-			topic.Name,
-			strconv.Itoa(topic.Sequence),
-			strconv.Itoa(len(topic.Schemas)),
-		}
-		if err := writer.Write(row); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // PrintTopicDetailsCSV prints topic details in CSV format
-// For single topic, we'll output it as a single row with all information
-func PrintTopicDetailsCSV(topic *client.Topic) error {
+// Each topic is output as a single row with all information
+func PrintTopicDetailsCSV(topics ...*client.Topic) error {
 	writer := csv.NewWriter(os.Stdout)
 	defer writer.Flush()
 
@@ -47,58 +42,25 @@ func PrintTopicDetailsCSV(topic *client.Topic) error {
 		return err
 	}
 
-	// Format schemas as JSON array
-	schemasJSON, err := json.Marshal(topic.Schemas)
-	schemasStr := string(schemasJSON)
-	if err != nil {
-		schemasStr = fmt.Sprintf("%v", topic.Schemas)
-	}
-
-	// Write row
-	row := []string{
-		topic.Name,
-		strconv.Itoa(topic.Sequence),
-		strconv.Itoa(len(topic.Schemas)),
-		schemasStr,
-	}
-	return writer.Write(row)
-}
-
-// PrintConsumersListCSV prints a list of consumers in CSV format
-func PrintConsumersListCSV(consumers []client.Consumer) error {
-	writer := csv.NewWriter(os.Stdout)
-	defer writer.Flush()
-
-	// Write header
-	if err := writer.Write([]string{"ID", "Callback URL", "Topics"}); err != nil {
-		return err
-	}
-
-	// Write rows
-	for _, consumer := range consumers {
-		topicsStr := ""
-		if len(consumer.Topics) > 0 {
-			topics := make([]string, 0, len(consumer.Topics))
-			for topic, eventID := range consumer.Topics {
-				if eventID == "" || eventID == "null" {
-					topics = append(topics, topic)
-				} else {
-					topics = append(topics, fmt.Sprintf("%s:%s", topic, eventID))
-				}
-			}
-			topicsStr = strings.Join(topics, "; ")
+	for _, topic := range topics {
+		// Format schemas as JSON array
+		schemasJSON, err := json.Marshal(topic.Schemas)
+		schemasStr := string(schemasJSON)
+		if err != nil {
+			schemasStr = fmt.Sprintf("%v", topic.Schemas)
 		}
 
+		// Write row
 		row := []string{
-			consumer.ID,
-			consumer.Callback,
-			topicsStr,
+			topic.Name,
+			strconv.Itoa(topic.Sequence),
+			strconv.Itoa(len(topic.Schemas)),
+			schemasStr,
 		}
 		if err := writer.Write(row); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
@@ -133,8 +95,14 @@ func PrintEventsListCSV(events []client.Event) error {
 	writer := csv.NewWriter(os.Stdout)
 	defer writer.Flush()
 
+	partitioned := hasPartitions(events)
+
 	// Write header
-	if err := writer.Write([]string{"ID", "Timestamp", "Type", "Payload"}); err != nil {
+	header := []string{"ID", "Timestamp", "Type", "Payload"}
+	if partitioned {
+		header = []string{"ID", "Partition", "Timestamp", "Type", "Payload"}
+	}
+	if err := writer.Write(header); err != nil {
 		return err
 	}
 
@@ -153,6 +121,9 @@ func PrintEventsListCSV(events []client.Event) error {
 			event.Type,
 			payloadStr,
 		}
+		if partitioned {
+			row = []string{event.ID, partitionLabel(event), event.Timestamp, event.Type, payloadStr}
+		}
 		if err := writer.Write(row); err != nil {
 			return err
 		}
@@ -196,12 +167,19 @@ func PrintMessageCSV(message string) error {
 	return writer.Write([]string{message})
 }
 
-// PrintErrorCSV prints an error in CSV format (single column)
-func PrintErrorCSV(err error) error {
+// PrintErrorCSV prints an error in CSV format
+func PrintErrorCSV(report ErrorReport) error {
 	writer := csv.NewWriter(os.Stdout)
 	defer writer.Flush()
 
-	return writer.Write([]string{fmt.Sprintf("Error: %s", err.Error())})
+	status := ""
+	if report.HTTPStatus != nil {
+		status = strconv.Itoa(*report.HTTPStatus)
+	}
+	if err := writer.Write([]string{"Error", "Code", "HTTP Status", "Hint"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{report.Error, report.Code, status, report.Hint})
 }
 
 // PrintConsumerIDCSV prints a consumer ID in CSV format
@@ -249,3 +227,666 @@ func PrintEventPublishResponseCSV(eventIDs []string) error {
 
 	return nil
 }
+
+// PrintBenchReportCSV prints a benchmark report as CSV
+func PrintBenchReportCSV(report *bench.Report) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Name", "Duration Seconds", "Requests", "Succeeded", "Failed", "Error Rate", "Throughput", "Min Ms", "Mean Ms", "P50 Ms", "P90 Ms", "P95 Ms", "P99 Ms", "Max Ms"}); err != nil {
+		return err
+	}
+
+	l := report.Latency
+	return writer.Write([]string{
+		report.Name,
+		strconv.FormatFloat(report.Seconds, 'f', 2, 64),
+		strconv.Itoa(report.Sent),
+		strconv.Itoa(report.Succeeded),
+		strconv.Itoa(report.Failed),
+		strconv.FormatFloat(report.ErrorRate, 'f', 4, 64),
+		strconv.FormatFloat(report.Throughput, 'f', 2, 64),
+		strconv.FormatFloat(l.Min, 'f', 2, 64),
+		strconv.FormatFloat(l.Mean, 'f', 2, 64),
+		strconv.FormatFloat(l.P50, 'f', 2, 64),
+		strconv.FormatFloat(l.P90, 'f', 2, 64),
+		strconv.FormatFloat(l.P95, 'f', 2, 64),
+		strconv.FormatFloat(l.P99, 'f', 2, 64),
+		strconv.FormatFloat(l.Max, 'f', 2, 64),
+	})
+}
+
+// PrintWatchChangesCSV prints each added, modified or deleted row with a leading Change
+// column, writing the header first if writeHeader is set
+func PrintWatchChangesCSV(header []string, changes []watch.Change, writeHeader bool) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if writeHeader {
+		if err := writer.Write(append([]string{"Change", "Time"}, header...)); err != nil {
+			return err
+		}
+	}
+
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	for _, change := range changes {
+		if change.Kind == "" {
+			continue
+		}
+		if err := writer.Write(append([]string{change.Kind, timestamp}, change.Row.Cells...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintConsumerMonitorCSV prints the rows of a listing of consumer deliveries with a
+// leading Time column, writing the header first if writeHeader is set
+func PrintConsumerMonitorCSV(at time.Time, l *Listing, writeHeader bool) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if writeHeader {
+		if err := writer.Write(append([]string{"Time"}, l.Header()...)); err != nil {
+			return err
+		}
+	}
+
+	timestamp := at.UTC().Format(time.RFC3339)
+	for _, row := range l.Rows() {
+		if err := writer.Write(append([]string{timestamp}, row.Cells...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintRetentionCSV prints a topic's retention limits in CSV format, with 0 for no limit
+func PrintRetentionCSV(retention *client.Retention) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Topic", "Max Age Seconds", "Max Events"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{
+		retention.Topic,
+		strconv.FormatInt(retention.MaxAgeSeconds, 10),
+		strconv.FormatInt(retention.MaxEvents, 10),
+	})
+}
+
+// PrintTruncateResultCSV prints the outcome of a truncation in CSV format
+func PrintTruncateResultCSV(result *client.TruncateResult) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Topic", "Deleted", "First Event ID", "Dry Run"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{
+		result.Topic,
+		strconv.FormatInt(result.Deleted, 10),
+		result.FirstEventID,
+		strconv.FormatBool(result.DryRun),
+	})
+}
+
+// PrintTierManifestCSV prints the segments of a topic's cold tier in CSV format
+func PrintTierManifestCSV(manifest *archive.TierManifest) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Topic", "Location", "File", "First Event", "Last Event", "Events", "From", "To", "SHA256"}); err != nil {
+		return err
+	}
+
+	for _, segment := range manifest.Segments {
+		row := []string{
+			manifest.Topic,
+			manifest.Location,
+			segment.File,
+			segment.FirstEventID,
+			segment.LastEventID,
+			strconv.Itoa(segment.Count),
+			segment.FirstTimestamp,
+			segment.LastTimestamp,
+			segment.SHA256,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrintHealthCheckCSV prints a health status transition as a CSV row, writing the header
+// first if writeHeader is set
+func PrintHealthCheckCSV(check monitor.HealthCheck, writeHeader bool) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if writeHeader {
+		if err := writer.Write([]string{"Time", "Status", "Consumers", "Dispatchers", "Degraded", "Reason"}); err != nil {
+			return err
+		}
+	}
+
+	return writer.Write([]string{
+		check.Time.Format(time.RFC3339),
+		check.Status,
+		strconv.Itoa(check.Consumers),
+		strconv.Itoa(check.Dispatchers),
+		strconv.FormatBool(check.Degraded),
+		check.Reason,
+	})
+}
+
+// PrintStatusCSV prints the summary of a status overview as a single CSV row
+func PrintStatusCSV(status *monitor.Status) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Server", "Server Version", "Health", "Running Dispatchers", "Topics", "Events", "Consumers", "Consumer Lag"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{
+		status.Server,
+		status.ServerVersion,
+		status.Health,
+		strings.Join(status.Dispatchers, "; "),
+		strconv.Itoa(len(status.Topics)),
+		strconv.FormatInt(status.Events, 10),
+		strconv.Itoa(len(status.Consumers)),
+		strconv.FormatInt(status.Lag, 10),
+	})
+}
+
+// PrintVersionCSV prints the CLI version and, when serverInfo is not nil, the server's
+// version and features as CSV
+func PrintVersionCSV(cliVersion, server string, serverInfo *client.ServerInfo) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if serverInfo == nil {
+		if err := writer.Write([]string{"CLI Version"}); err != nil {
+			return err
+		}
+		return writer.Write([]string{cliVersion})
+	}
+
+	if err := writer.Write([]string{"CLI Version", "Server", "Server Version", "API Version", "Features"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{cliVersion, server, serverInfo.Version, serverInfo.APIVersion, strings.Join(serverInfo.Features, "; ")})
+}
+
+// PrintReplayResultCSV prints the outcome of a consumer replay as CSV
+func PrintReplayResultCSV(result *replay.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Consumer", "Topic", "First Event ID", "Last Event ID", "Delivered", "Deliveries", "Duration Seconds", "Server Side"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{
+		result.ConsumerID,
+		result.Topic,
+		result.FirstEventID,
+		result.LastEventID,
+		strconv.Itoa(result.Delivered),
+		strconv.Itoa(result.Deliveries),
+		fmt.Sprintf("%.2f", result.Seconds),
+		strconv.FormatBool(result.ServerSide),
+	})
+}
+
+// PrintTestResultsCSV prints test outcomes as CSV, one row per test
+func PrintTestResultsCSV(results []spec.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Spec", "Name", "Passed", "Duration Seconds", "Failures"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := writer.Write([]string{
+			result.Spec,
+			result.Name,
+			strconv.FormatBool(result.Passed),
+			fmt.Sprintf("%.2f", result.Seconds),
+			strings.Join(result.Failures, "; "),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintContractReportCSV prints contract verification as CSV, one row per example
+func PrintContractReportCSV(report *contract.Report) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Contract", "Topic", "Type", "Example", "Passed", "Problems"}); err != nil {
+		return err
+	}
+	for _, result := range report.Results {
+		if err := writer.Write([]string{
+			result.Contract,
+			result.Topic,
+			result.Type,
+			result.Example,
+			strconv.FormatBool(result.Passed),
+			strings.Join(result.Problems, "; "),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintCopyResultCSV prints the outcome of a topic-to-topic copy as CSV
+func PrintCopyResultCSV(result *copier.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Source Topic", "Destination Topic", "Destination Server", "First Event ID", "Last Event ID", "Read", "Copied", "Skipped", "Duration Seconds", "Dry Run"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{
+		result.SourceTopic,
+		result.DestTopic,
+		result.DestServer,
+		result.FirstEventID,
+		result.LastEventID,
+		strconv.Itoa(result.Read),
+		strconv.Itoa(result.Copied),
+		strconv.Itoa(result.Skipped),
+		fmt.Sprintf("%.2f", result.Seconds),
+		strconv.FormatBool(result.DryRun),
+	})
+}
+
+// PrintConsumerDeletionsCSV prints the outcome of a bulk consumer delete as CSV, a row
+// per consumer
+func PrintConsumerDeletionsCSV(result *consumers.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"ID", "Callback", "Status", "Error"}); err != nil {
+		return err
+	}
+	for _, deletion := range result.Deletions {
+		if err := writer.Write([]string{deletion.ID, deletion.Callback, deletion.Status, deletion.Error}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintConsumerPingsCSV prints the outcome of probing consumers' callbacks as CSV
+func PrintConsumerPingsCSV(pings []consumers.Ping) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"ID", "Callback", "Reachable", "OK", "HTTP Status", "Latency Ms", "TLS Version", "Certificate Subject", "Certificate Issuer", "Certificate Expires", "Error"}); err != nil {
+		return err
+	}
+	for _, ping := range pings {
+		status, latency := "", ""
+		if ping.Reachable {
+			status = strconv.Itoa(ping.Status)
+			latency = fmt.Sprintf("%.1f", ping.LatencyMs)
+		}
+		version, subject, issuer, expires := "", "", "", ""
+		if ping.TLS != nil {
+			version, subject, issuer = ping.TLS.Version, ping.TLS.Subject, ping.TLS.Issuer
+			if !ping.TLS.NotAfter.IsZero() {
+				expires = ping.TLS.NotAfter.Format(time.RFC3339)
+			}
+		}
+		row := []string{ping.ID, ping.Callback, strconv.FormatBool(ping.Reachable), strconv.FormatBool(ping.OK), status, latency, version, subject, issuer, expires, ping.Error}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintDLQEntriesCSV prints the entries of a dead-letter queue as CSV
+func PrintDLQEntriesCSV(entries []client.DLQEntry) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Entry ID", "Consumer ID", "Topic", "Event ID", "Type", "Attempts", "Last Status", "First Failed", "Last Failed", "Last Error", "Payload"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		payload, err := json.Marshal(entry.Event.Payload)
+		if err != nil {
+			payload = []byte(fmt.Sprintf("%v", entry.Event.Payload))
+		}
+		status := ""
+		if entry.LastStatus != 0 {
+			status = strconv.Itoa(entry.LastStatus)
+		}
+		row := []string{entry.ID, entry.ConsumerID, entry.Topic, entry.Event.ID, entry.Event.Type, strconv.Itoa(entry.Attempts), status, entry.FirstFailedAt, entry.LastFailedAt, entry.LastError, string(payload)}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintRetryResultCSV prints the outcome of retrying dead-lettered events as CSV, a row
+// per entry
+func PrintRetryResultCSV(result *consumers.RetryResult) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Entry ID", "Event ID", "Status", "Error"}); err != nil {
+		return err
+	}
+	for _, outcome := range result.Outcomes {
+		if err := writer.Write([]string{outcome.EntryID, outcome.EventID, outcome.Status, outcome.Error}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliveryHeader is the CSV header of delivery attempts
+var deliveryHeader = []string{"ID", "Consumer ID", "Event ID", "Attempt", "Success", "Status", "Latency Ms", "Timestamp", "Error"}
+
+func deliveryRow(delivery client.Delivery) []string {
+	status := ""
+	if delivery.Status != 0 {
+		status = strconv.Itoa(delivery.Status)
+	}
+	return []string{
+		delivery.ID,
+		delivery.ConsumerID,
+		delivery.EventID,
+		strconv.Itoa(delivery.Attempt),
+		strconv.FormatBool(delivery.Success),
+		status,
+		fmt.Sprintf("%.1f", delivery.LatencyMs),
+		delivery.Timestamp,
+		delivery.Error,
+	}
+}
+
+// PrintDeliveriesCSV prints delivery attempts as CSV
+func PrintDeliveriesCSV(deliveries []client.Delivery) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write(deliveryHeader); err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := writer.Write(deliveryRow(delivery)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintAssertResultCSV prints the outcome of an assertion as CSV
+func PrintAssertResultCSV(result *assert.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Topic", "Filter", "Count", "Matched", "Passed", "Matched Event IDs", "Last Event ID", "Duration Seconds"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{
+		result.Topic,
+		result.Filter,
+		result.Count,
+		strconv.Itoa(result.Matched),
+		strconv.FormatBool(result.Passed),
+		strings.Join(result.MatchedIDs, " "),
+		result.LastEventID,
+		fmt.Sprintf("%.2f", result.Seconds),
+	})
+}
+
+// PrintAssertOutcomeCSV prints the outcome of an assertion on a topic or consumer as CSV
+func PrintAssertOutcomeCSV(outcome *assert.Outcome) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Assertion", "Subject", "Expected", "Actual", "Passed", "Checks", "Duration Seconds"}); err != nil {
+		return err
+	}
+	return writer.Write([]string{
+		outcome.Assertion,
+		outcome.Subject,
+		outcome.Expected,
+		outcome.Actual,
+		strconv.FormatBool(outcome.Passed),
+		strconv.Itoa(outcome.Checks),
+		fmt.Sprintf("%.2f", outcome.Seconds),
+	})
+}
+
+// PrintTopicStatsCSV prints a topic's statistics as a single CSV row, with the per-type
+// and per-day counts as 'name=count' lists
+func PrintTopicStatsCSV(stats *client.TopicStats) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Topic", "Events", "First Event ID", "Last Event ID", "First Timestamp", "Last Timestamp", "Payload Min", "Payload Mean", "Payload P50", "Payload P90", "Payload P99", "Payload Max", "Payload Total", "Types", "Per Day", "Source"}); err != nil {
+		return err
+	}
+	types := make([]string, len(stats.PerType))
+	for i, count := range stats.PerType {
+		types[i] = fmt.Sprintf("%s=%d", count.Type, count.Events)
+	}
+	days := make([]string, len(stats.PerDay))
+	for i, day := range stats.PerDay {
+		days[i] = fmt.Sprintf("%s=%d", day.Date, day.Events)
+	}
+	sizes := stats.PayloadBytes
+	return writer.Write([]string{
+		stats.Topic,
+		strconv.FormatInt(stats.Events, 10),
+		stats.FirstEventID,
+		stats.LastEventID,
+		stats.FirstTimestamp,
+		stats.LastTimestamp,
+		strconv.Itoa(sizes.Min),
+		fmt.Sprintf("%.1f", sizes.Mean),
+		strconv.Itoa(sizes.P50),
+		strconv.Itoa(sizes.P90),
+		strconv.Itoa(sizes.P99),
+		strconv.Itoa(sizes.Max),
+		strconv.FormatInt(sizes.Total, 10),
+		strings.Join(types, "; "),
+		strings.Join(days, "; "),
+		stats.Source,
+	})
+}
+
+// PrintVolumeSeriesCSV prints a topic's event counts per time bucket as CSV, a row per
+// bucket
+func PrintVolumeSeriesCSV(series *stats.Series) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Start", "Events"}); err != nil {
+		return err
+	}
+	for _, bucket := range series.Buckets {
+		if err := writer.Write([]string{bucket.Start, strconv.FormatInt(bucket.Events, 10)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintAggregateResultCSV prints aggregated event counts as CSV, one row per group (and
+// per time bucket with events, when bucketed by time)
+func PrintAggregateResultCSV(result *aggregate.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	header := append([]string{}, result.GroupBy...)
+	if result.Interval != "" {
+		header = append([]string{"Bucket"}, header...)
+	}
+	if err := writer.Write(append(header, "Events")); err != nil {
+		return err
+	}
+	for _, series := range result.Series {
+		if result.Interval == "" {
+			if err := writer.Write(append(append([]string{}, series.Key...), strconv.FormatInt(series.Count, 10))); err != nil {
+				return err
+			}
+			continue
+		}
+		for i, bucket := range result.Buckets {
+			if series.Counts[i] == 0 {
+				continue
+			}
+			row := append([]string{bucket}, series.Key...)
+			if err := writer.Write(append(row, strconv.FormatInt(series.Counts[i], 10))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PrintExportResultCSV prints what event exports wrote as CSV, a row per file
+func PrintExportResultCSV(results ...*export.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Topic", "File", "Format", "Compression", "Columns", "Events", "First Event ID", "Last Event ID", "Bytes", "SHA-256", "Verified", "Filter", "Masked", "Encrypted"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		err := writer.Write([]string{
+			result.Topic,
+			result.File,
+			result.Format,
+			result.Compression,
+			strings.Join(result.Columns, ";"),
+			strconv.Itoa(result.Events),
+			result.FirstEventID,
+			result.LastEventID,
+			strconv.FormatInt(result.Bytes, 10),
+			result.SHA256,
+			strconv.FormatBool(result.Verified),
+			result.Filter,
+			strings.Join(result.Masked, ";"),
+			strconv.FormatBool(result.Encrypted),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintSpoolEntriesCSV prints spooled events waiting to be published as CSV
+func PrintSpoolEntriesCSV(entries []*spool.Entry) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Entry ID", "Publish At", "Server", "Topics", "Events", "Created"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		row := []string{entry.ID, entry.PublishAt.Format(time.RFC3339), entry.Server, strings.Join(entry.Topics(), ";"), strconv.Itoa(len(entry.Events)), entry.Created.Format(time.RFC3339)}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintTraceCSV prints the events of a trace as CSV, in tree order with their depth
+func PrintTraceCSV(roots []*trace.Node) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Depth", "Topic", "ID", "Timestamp", "Type", "Causation ID"}); err != nil {
+		return err
+	}
+	var err error
+	trace.Walk(roots, func(node *trace.Node, last []bool) {
+		if err == nil {
+			err = writer.Write([]string{strconv.Itoa(len(last) - 1), node.Topic, node.Event.ID, node.Event.Timestamp, node.Event.Type, node.Event.CausationID})
+		}
+	})
+	return err
+}
+
+// PrintDriftCSV prints the differences between two event stores as CSV
+func PrintDriftCSV(report *drift.Report) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Kind", "Name", "Difference"}); err != nil {
+		return err
+	}
+	for _, diff := range report.Differences {
+		if err := writer.Write([]string{diff.Kind, diff.Name, diff.Detail}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintVerifyResultCSV prints the anomalies found verifying a topic's events as CSV
+func PrintVerifyResultCSV(result *verify.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Kind", "Event ID", "Detail"}); err != nil {
+		return err
+	}
+	for _, anomaly := range result.Anomalies {
+		if err := writer.Write([]string{anomaly.Kind, anomaly.EventID, anomaly.Detail}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintSeedResultCSV prints what applying or resetting fixtures did as CSV
+func PrintSeedResultCSV(result *seed.Result) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Kind", "Name", "Action"}); err != nil {
+		return err
+	}
+	for _, action := range result.Actions {
+		if err := writer.Write([]string{action.Kind, action.Name, action.Action}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintOwnersReportCSV prints who owns each topic as CSV
+func PrintOwnersReportCSV(report *owners.Report) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Topic", "Team", "Owner", "Contact", "Owned"}); err != nil {
+		return err
+	}
+	for _, entry := range report.Topics {
+		if err := writer.Write([]string{entry.Topic, entry.Team, entry.Owner, entry.Contact, strconv.FormatBool(entry.Owned)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package output
+
+// ErrorReport is an error as printed by every command: the message, a stable code for
+// scripts to check, the HTTP status when the server answered, and a hint on what to do
+type ErrorReport struct {
+	Error      string `json:"error"`
+	Code       string `json:"code"`
+	HTTPStatus *int   `json:"httpStatus"`
+	Hint       string `json:"hint"`
+}
@@ -3,36 +3,28 @@ package output
 import (
 	"encoding/json"
 	"os"
+	"time"
 
+	"github.com/event-store/cli/internal/archive"
+	"github.com/event-store/cli/internal/bench"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/monitor"
+	"github.com/event-store/cli/internal/watch"
 )
 
 // PrintJSON prints data as JSON
 func PrintJSON(data interface{}) error {
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
 	return encoder.Encode(data)
 }
 
-// PrintTopicsListJSON prints a list of topics as JSON
-func PrintTopicsListJSON(topics []client.Topic) error {
-	return PrintJSON(map[string]interface{}{
-		"topics": topics,
-	})
-}
-
 // PrintTopicDetailsJSON prints topic details as JSON
 func PrintTopicDetailsJSON(topic *client.Topic) error {
 	return PrintJSON(topic)
 }
 
-// PrintConsumersListJSON prints a list of consumers as JSON
-func PrintConsumersListJSON(consumers []client.Consumer) error {
-	return PrintJSON(map[string]interface{}{
-		"consumers": consumers,
-	})
-}
-
 // PrintConsumerDetailsJSON prints consumer details as JSON
 func PrintConsumerDetailsJSON(consumer *client.Consumer) error {
 	return PrintJSON(consumer)
@@ -46,10 +38,8 @@ func PrintMessageJSON(message string) error {
 }
 
 // PrintErrorJSON prints an error as JSON
-func PrintErrorJSON(err error) error {
-	return PrintJSON(map[string]string{
-		"error": err.Error(),
-	})
+func PrintErrorJSON(report ErrorReport) error {
+	return PrintJSON(report)
 }
 
 // PrintConsumerIDJSON prints a consumer ID as JSON
@@ -82,3 +72,67 @@ func PrintEventPublishResponseJSON(eventIDs []string) error {
 		"eventIds": eventIDs,
 	})
 }
+
+// PrintBenchReportJSON prints a benchmark report as JSON
+func PrintBenchReportJSON(report *bench.Report) error {
+	return PrintJSON(report)
+}
+
+// PrintWatchChangesJSON prints each added, modified or deleted row as a JSON object on its
+// own line, in the style of 'kubectl get -w -o json'
+func PrintWatchChangesJSON(changes []watch.Change) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for _, change := range changes {
+		if change.Kind == "" {
+			continue
+		}
+		if err := encoder.Encode(map[string]interface{}{
+			"type":   change.Kind,
+			"time":   time.Now().UTC().Format(time.RFC3339),
+			"object": change.Row.Object,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintTierManifestJSON prints a topic's cold tier manifest as JSON
+func PrintTierManifestJSON(manifest *archive.TierManifest) error {
+	return PrintJSON(manifest)
+}
+
+// PrintHealthCheckJSON prints a health status transition as a JSON object on one line
+func PrintHealthCheckJSON(check monitor.HealthCheck) error {
+	return json.NewEncoder(os.Stdout).Encode(check)
+}
+
+// PrintConsumerMonitorJSON prints the consumer deliveries of a listing in row order, one
+// JSON object per line
+func PrintConsumerMonitorJSON(l *Listing) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for _, object := range l.Objects() {
+		if err := encoder.Encode(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintStatusJSON prints a status overview as JSON
+func PrintStatusJSON(status *monitor.Status) error {
+	return PrintJSON(status)
+}
+
+// PrintVersionJSON prints the CLI version and, when serverInfo is not nil, the server's
+// version and features as JSON
+func PrintVersionJSON(cliVersion, server string, serverInfo *client.ServerInfo) error {
+	data := map[string]interface{}{
+		"cliVersion": cliVersion,
+	}
+	if serverInfo != nil {
+		data["server"] = server
+		data["serverInfo"] = serverInfo
+	}
+	return PrintJSON(data)
+}
@@ -0,0 +1,274 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/event-store/cli/internal/watch"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Column is a column of a list command's table and CSV output
+type Column struct {
+	Name    string // used with --columns and --sort-by, e.g. "sequence"
+	Header  string
+	Numeric bool // sort numerically; cells that aren't numbers sort last
+	Hidden  bool // only shown when selected with --columns
+}
+
+// Listing is the output of a list command: a row per resource with a cell per column.
+// Commands declare their columns and add rows; selecting and sorting columns, and
+// printing, are the same for every command.
+type Listing struct {
+	columns  []Column
+	selected []int // indexes of the columns shown, in order
+	rows     []watch.Row
+}
+
+// NewListing creates a listing showing the columns that aren't hidden
+func NewListing(columns []Column) *Listing {
+	l := &Listing{columns: columns}
+	for i, column := range columns {
+		if !column.Hidden {
+			l.selected = append(l.selected, i)
+		}
+	}
+	return l
+}
+
+// Add adds a row for a resource, identified by key, with a cell for every column
+func (l *Listing) Add(key string, object interface{}, cells ...string) {
+	l.rows = append(l.rows, watch.Row{Key: key, Cells: cells, Object: object})
+}
+
+// Select shows only the named columns, in the order given. No names leaves the default
+// columns.
+func (l *Listing) Select(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	selected := make([]int, 0, len(names))
+	for _, name := range names {
+		i, err := l.column(name)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, i)
+	}
+	l.selected = selected
+	return nil
+}
+
+// Sort orders the rows by a column, given as 'name', 'name:asc' or 'name:desc'. Rows
+// with equal cells keep their order.
+func (l *Listing) Sort(spec string) error {
+	if spec == "" {
+		return nil
+	}
+	name, descending, err := ParseSortSpec(spec)
+	if err != nil {
+		return err
+	}
+	i, err := l.column(name)
+	if err != nil {
+		return err
+	}
+	numeric := l.columns[i].Numeric
+	sort.SliceStable(l.rows, func(a, b int) bool {
+		return lessCells(l.rows[a].Cells[i], l.rows[b].Cells[i], numeric, descending)
+	})
+	return nil
+}
+
+// ParseSortSpec parses a --sort-by value: 'name', 'name:asc' or 'name:desc'
+func ParseSortSpec(spec string) (name string, descending bool, err error) {
+	name, order, hasOrder := strings.Cut(spec, ":")
+	switch strings.ToLower(order) {
+	case "asc":
+	case "desc":
+		descending = true
+	default:
+		if hasOrder {
+			return "", false, fmt.Errorf("invalid sort order '%s' (expected asc or desc)", order)
+		}
+	}
+	return strings.TrimSpace(name), descending, nil
+}
+
+// lessCells compares two cells for sorting; numbers compare numerically when numeric
+// is set, and cells that aren't numbers sort last either way
+func lessCells(a, b string, numeric, descending bool) bool {
+	if numeric {
+		x, errX := strconv.ParseFloat(a, 64)
+		y, errY := strconv.ParseFloat(b, 64)
+		switch {
+		case errX != nil || errY != nil:
+			return errX == nil && errY != nil
+		case descending:
+			return x > y
+		default:
+			return x < y
+		}
+	}
+	if descending {
+		return a > b
+	}
+	return a < b
+}
+
+// column returns the index of a named column
+func (l *Listing) column(name string) (int, error) {
+	names := make([]string, len(l.columns))
+	for i, column := range l.columns {
+		if strings.EqualFold(column.Name, name) {
+			return i, nil
+		}
+		names[i] = column.Name
+	}
+	return 0, fmt.Errorf("unknown column '%s' (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Header returns the headers of the shown columns
+func (l *Listing) Header() []string {
+	header := make([]string, len(l.selected))
+	for i, column := range l.selected {
+		header[i] = l.columns[column].Header
+	}
+	return header
+}
+
+// Rows returns the rows in order with only the shown cells
+func (l *Listing) Rows() []watch.Row {
+	rows := make([]watch.Row, len(l.rows))
+	for i, row := range l.rows {
+		cells := make([]string, len(l.selected))
+		for j, column := range l.selected {
+			cells[j] = row.Cells[column]
+		}
+		rows[i] = watch.Row{Key: row.Key, Cells: cells, Object: row.Object}
+	}
+	return rows
+}
+
+// Keys returns the rows' keys in order
+func (l *Listing) Keys() []string {
+	keys := make([]string, len(l.rows))
+	for i, row := range l.rows {
+		keys[i] = row.Key
+	}
+	return keys
+}
+
+// Objects returns the resources the rows were built from, in row order
+func (l *Listing) Objects() []interface{} {
+	objects := make([]interface{}, len(l.rows))
+	for i, row := range l.rows {
+		objects[i] = row.Object
+	}
+	return objects
+}
+
+// ContextColumn names the context of each row of listings combined from several servers
+var ContextColumn = Column{Name: "context", Header: "Context"}
+
+// CombineListings combines the listings of the same columns from several contexts, one
+// per context, adding a first column naming each row's context. Rows are keyed by the
+// context and their key, e.g. prod/orders, and their resources gain a context field in
+// JSON.
+func CombineListings(columns []Column, contexts []string, listings []*Listing) *Listing {
+	combined := NewListing(append([]Column{ContextColumn}, columns...))
+	for i, l := range listings {
+		for _, row := range l.rows {
+			cells := append([]string{contexts[i]}, row.Cells...)
+			combined.Add(contexts[i]+"/"+row.Key, contextObject{context: contexts[i], object: row.Object}, cells...)
+		}
+	}
+	return combined
+}
+
+// contextObject is a resource from a context, whose JSON is the resource's with a
+// context field first
+type contextObject struct {
+	context string
+	object  interface{}
+}
+
+func (o contextObject) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(o.object)
+	if err != nil {
+		return nil, err
+	}
+	context, err := json.Marshal(o.context)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("{")) {
+		return []byte(fmt.Sprintf(`{"context":%s,"value":%s}`, context, data)), nil
+	}
+	if bytes.Equal(data, []byte("{}")) {
+		return []byte(fmt.Sprintf(`{"context":%s}`, context)), nil
+	}
+	return []byte(fmt.Sprintf(`{"context":%s,%s`, context, data[1:])), nil
+}
+
+// ColumnNames returns the names of a command's columns, for help text
+func ColumnNames(columns []Column) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// PrintListing prints a listing in table format
+func PrintListing(l *Listing) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	header := table.Row{}
+	for _, cell := range l.Header() {
+		header = append(header, cell)
+	}
+	t.AppendHeader(header)
+	texts := [][]string{l.Header()}
+	for _, row := range l.Rows() {
+		cells := table.Row{}
+		for _, cell := range row.Cells {
+			cells = append(cells, cell)
+		}
+		t.AppendRow(cells)
+		texts = append(texts, row.Cells)
+	}
+	t.SetStyle(getTableStyle())
+	fitColumns(t, texts)
+	render(t)
+}
+
+// PrintListingCSV prints a listing in CSV format
+func PrintListingCSV(l *Listing) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if err := writer.Write(l.Header()); err != nil {
+		return err
+	}
+	for _, row := range l.Rows() {
+		if err := writer.Write(row.Cells); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintListingJSON prints the resources of a listing in row order as JSON, under key.
+// Columns only apply to table and CSV output.
+func PrintListingJSON(key string, l *Listing) error {
+	return PrintJSON(map[string]interface{}{
+		key: l.Objects(),
+	})
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// markup is the format tables are rendered in rather than text, for --output markdown and
+// --output html, or "" for text
+var markup string
+
+// markdownSeparator matches the line under a Markdown table's header row
+var markdownSeparator = regexp.MustCompile(`^\|([ :]-+[ :]\|)+$`)
+
+// SetMarkup renders tables in a markup format, "markdown" or "html", rather than as text,
+// for pasting into wikis, pull requests and status pages. Tables in markup aren't fitted
+// to the terminal or colored.
+func SetMarkup(format string) {
+	markup = format
+}
+
+// IsMarkup reports whether an output format renders tables in markup
+func IsMarkup(format string) bool {
+	return format == "markdown" || format == "html"
+}
+
+// render prints a table to stdout in the output format
+func render(t table.Writer) {
+	switch markup {
+	case "markdown":
+		t.SetOutputMirror(nil)
+		fmt.Println(markdownTable(t.RenderMarkdown()))
+	case "html":
+		t.RenderHTML()
+	default:
+		t.Render()
+	}
+}
+
+// markdownTable gives a table rendered in Markdown without a header row, such as one of
+// an item's details, an empty one: Markdown tables only start at a header row
+func markdownTable(rendered string) string {
+	first, rest, _ := strings.Cut(rendered, "\n")
+	second, _, _ := strings.Cut(rest, "\n")
+	if rendered == "" || markdownSeparator.MatchString(second) {
+		return rendered
+	}
+	columns := strings.Count(first, "|") - strings.Count(first, `\|`) - 1
+	return "|" + strings.Repeat("  |", columns) + "\n|" + strings.Repeat(" --- |", columns) + "\n" + rendered
+}
+
+// printHeading prints the heading of a part of the output, such as a topic's schemas
+func printHeading(title string) {
+	switch markup {
+	case "markdown":
+		fmt.Printf("\n### %s\n\n", title)
+	case "html":
+		fmt.Printf("<h3>%s</h3>\n", html.EscapeString(title))
+	default:
+		fmt.Printf("\n%s:\n", title)
+	}
+}
+
+// printBlock prints text, such as a rendered payload, as it is: in a code block in
+// Markdown and a <pre> element in HTML
+func printBlock(text string) {
+	switch markup {
+	case "markdown":
+		fence := "```"
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		fmt.Printf("%s\n%s\n%s\n", fence, text, fence)
+	case "html":
+		fmt.Printf("<pre>%s</pre>\n", html.EscapeString(text))
+	default:
+		fmt.Println(text)
+	}
+}
+
+// printText prints a line of text, such as a message that there is nothing to show
+func printText(line string) {
+	if markup == "html" {
+		fmt.Printf("<p>%s</p>\n", html.EscapeString(line))
+		return
+	}
+	fmt.Println(line)
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// MaskValue replaces the value of a masked field
+const MaskValue = "***"
+
+// CheckMaskFields checks the fields to mask: dotted paths under payload or metadata
+func CheckMaskFields(fields []string) error {
+	for _, field := range fields {
+		path, ok := strings.CutPrefix(field, "payload.")
+		if !ok {
+			path, ok = strings.CutPrefix(field, "metadata.")
+		}
+		if !ok || path == "" || strings.HasSuffix(path, ".") {
+			return fmt.Errorf("invalid mask field '%s' (expected 'payload.<path>' or 'metadata.<path>')", field)
+		}
+	}
+	return nil
+}
+
+// MaskEvents returns events with the fields that they have replaced by MaskValue, leaving
+// the events given as they are
+func MaskEvents(events []client.Event, fields []string) []client.Event {
+	if len(fields) == 0 {
+		return events
+	}
+	masked := make([]client.Event, len(events))
+	for i, event := range events {
+		masked[i] = MaskEvent(event, fields)
+	}
+	return masked
+}
+
+// MaskEvent returns an event with the fields it has replaced by MaskValue. The objects
+// holding the fields are copied, so the event given is left as it is.
+func MaskEvent(event client.Event, fields []string) client.Event {
+	for _, field := range fields {
+		if path, ok := strings.CutPrefix(field, "payload."); ok {
+			event.Payload, _ = mask(event.Payload, strings.Split(path, "."))
+		} else if path, ok := strings.CutPrefix(field, "metadata."); ok {
+			event.Metadata, _ = mask(event.Metadata, strings.Split(path, "."))
+		}
+	}
+	return event
+}
+
+// mask returns a copy of an object with the value at a path replaced by MaskValue, and
+// whether it has the path; the object itself is returned if it hasn't
+func mask(object map[string]interface{}, path []string) (map[string]interface{}, bool) {
+	value, ok := object[path[0]]
+	if !ok {
+		return object, false
+	}
+	replacement := interface{}(MaskValue)
+	if len(path) > 1 {
+		nested, isObject := value.(map[string]interface{})
+		if !isObject {
+			return object, false
+		}
+		masked, found := mask(nested, path[1:])
+		if !found {
+			return object, false
+		}
+		replacement = masked
+	}
+	copied := maps.Clone(object)
+	copied[path[0]] = replacement
+	return copied, true
+}
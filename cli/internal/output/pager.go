@@ -0,0 +1,204 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// paging is set while stdout is piped through a pager, which is still a terminal as far
+// as colours are concerned
+var paging bool
+
+// pagerReplayLimit is how much output is kept to print again if the pager fails
+const pagerReplayLimit = 8 << 20
+
+// Pager pipes stdout through a pager program such as less. If the pager fails, such as
+// when $PAGER names a program that isn't installed, the output is printed without it.
+type Pager struct {
+	program string
+	cmd     *exec.Cmd
+	stdout  *os.File
+	writer  *os.File      // stdout while paging
+	copied  chan struct{} // closed once all that was written has gone to the pager
+	exited  chan struct{} // closed once the pager exits, with err its Wait error
+	err     error
+	done    chan struct{}
+
+	kept        bytes.Buffer // output sent to the pager, to print again if it fails
+	overflowed  bool         // more was sent than kept
+	replayed    bool         // the pager failed and output went to stdout instead
+	interrupted atomic.Bool
+}
+
+// PagerProgram returns the pager to use: $ES_PAGER, then $PAGER, then less
+func PagerProgram() string {
+	if program, ok := os.LookupEnv("ES_PAGER"); ok {
+		return program
+	}
+	if program, ok := os.LookupEnv("PAGER"); ok {
+		return program
+	}
+	return "less"
+}
+
+// StartPager redirects stdout through program until Close is called. Like git, the
+// program is run by the shell, so it can have arguments, quotes and pipes, and less is
+// run with -FRX so output that fits on one screen is printed as is. It returns nil when
+// stdout is not a terminal, when program is empty or "cat", or when the pager can't be
+// started, in which case output goes straight to stdout.
+func StartPager(program string) *Pager {
+	program = strings.TrimSpace(program)
+	if program == "" || program == "cat" || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil
+	}
+	p := startPager(program)
+	if p == nil {
+		return nil
+	}
+
+	// Ctrl+C goes to the pager too; leave it to tidy up the terminal before exiting
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupts:
+			p.interrupted.Store(true)
+			p.Close()
+			os.Exit(130)
+		case <-p.done:
+		}
+		signal.Stop(interrupts)
+	}()
+	return p
+}
+
+// pagerCommand returns the command running a pager program
+func pagerCommand(program string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		args := strings.Fields(program)
+		return exec.Command(args[0], args[1:]...)
+	}
+	return exec.Command("sh", "-c", program)
+}
+
+// startPager starts program with its output on stdout, and points stdout at it
+func startPager(program string) *Pager {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+	command := pagerCommand(program)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	command.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		command.Env = append(command.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		command.Env = append(command.Env, "LV=-c")
+	}
+	input, err := command.StdinPipe()
+	if err == nil {
+		err = command.Start()
+	}
+	if err != nil {
+		reader.Close()
+		writer.Close()
+		return nil
+	}
+
+	p := &Pager{
+		program: program,
+		cmd:     command,
+		stdout:  os.Stdout,
+		writer:  writer,
+		copied:  make(chan struct{}),
+		exited:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		p.err = command.Wait()
+		close(p.exited)
+	}()
+	go p.copy(reader, input)
+	os.Stdout = writer
+	paging = true
+	return p
+}
+
+// copy sends what is written to stdout on to the pager. Once the pager stops reading,
+// the rest is printed as is if it failed, or dropped if the user quit it.
+func (p *Pager) copy(reader *os.File, input io.WriteCloser) {
+	defer close(p.copied)
+	defer reader.Close()
+	buf := make([]byte, 32*1024)
+	gone := false
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			switch {
+			case p.replayed:
+				p.stdout.Write(buf[:n])
+			case !gone:
+				p.keep(buf[:n])
+				if _, err := input.Write(buf[:n]); err != nil {
+					gone = true
+					<-p.exited
+					p.replayIfFailed()
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	input.Close()
+}
+
+// keep holds on to output sent to the pager, up to pagerReplayLimit
+func (p *Pager) keep(data []byte) {
+	if p.kept.Len()+len(data) > pagerReplayLimit {
+		p.overflowed = true
+		return
+	}
+	p.kept.Write(data)
+}
+
+// replayIfFailed prints the output sent to a pager that failed, which may never have
+// shown it
+func (p *Pager) replayIfFailed() {
+	if p.replayed || p.err == nil || p.interrupted.Load() {
+		return
+	}
+	p.replayed = true
+	fmt.Fprintf(os.Stderr, "Warning: pager '%s' failed (%v); printing without it\n", p.program, p.err)
+	if p.overflowed {
+		fmt.Fprintln(os.Stderr, "Warning: the start of the output was lost")
+		return
+	}
+	p.stdout.Write(p.kept.Bytes())
+}
+
+// Close restores stdout and waits for the user to quit the pager
+func (p *Pager) Close() {
+	if p == nil || !paging {
+		return
+	}
+	paging = false
+	os.Stdout = p.stdout
+	p.writer.Close()
+	<-p.copied
+	<-p.exited
+	p.replayIfFailed()
+	close(p.done)
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// page prints lines through a pager program, with stdout a file, and returns what
+// reached the file
+func page(t *testing.T, program string, lines int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdout")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = file, file
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	p := startPager(program)
+	if p == nil {
+		t.Fatalf("startPager(%q) = nil", program)
+	}
+	for i := 1; i <= lines; i++ {
+		fmt.Printf("line %d\n", i)
+	}
+	p.Close()
+	if os.Stdout != file {
+		t.Error("Close didn't restore stdout")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func lines(from, to int) string {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func TestPagerShell(t *testing.T) {
+	// Run by the shell, with quotes and a pipe
+	if got := page(t, `sed 's/line/row/' | tr 'r' 'R'`, 3); got != "Row 1\nRow 2\nRow 3\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestPagerFailsAtOnce(t *testing.T) {
+	got := page(t, "exit 127", 5000)
+	if !strings.HasPrefix(got, "Warning: pager 'exit 127' failed (exit status 127); printing without it\n") {
+		t.Errorf("output starts %q", got[:min(len(got), 100)])
+	}
+	if !strings.HasSuffix(got, lines(1, 5000)) || strings.Count(got, "line 1\n") != 1 {
+		t.Errorf("output doesn't have every line once: %d bytes", len(got))
+	}
+}
+
+func TestPagerFailsPartWay(t *testing.T) {
+	// It shows the first line, then fails; everything is printed again after it
+	got := page(t, "head -n 1; exit 2", 5000)
+	want := "line 1\nWarning: pager 'head -n 1; exit 2' failed (exit status 2); printing without it\n" + lines(1, 5000)
+	if got != want {
+		t.Errorf("output is %d bytes, starting %q", len(got), got[:min(len(got), 120)])
+	}
+}
+
+func TestPagerQuit(t *testing.T) {
+	// The user quits after the first lines: the rest isn't printed
+	if got := page(t, "head -n 2", 5000); got != lines(1, 2) {
+		t.Errorf("output is %d bytes, starting %q", len(got), got[:min(len(got), 120)])
+	}
+}
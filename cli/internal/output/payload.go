@@ -0,0 +1,200 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// PayloadOptions says how payloads are rendered for reading in a terminal
+type PayloadOptions struct {
+	// Flatten renders a line per value, keyed by its dotted path, e.g. "address.city",
+	// rather than as nested JSON
+	Flatten bool
+	// Depth is the number of levels of nesting shown, deeper objects and arrays being
+	// collapsed to a summary such as "{…3 keys}"; 0 shows every level
+	Depth int
+}
+
+// Colors of the parts of a rendered payload
+var (
+	keyColors     = text.Colors{text.FgCyan}
+	stringColors  = text.Colors{text.FgGreen}
+	numberColors  = text.Colors{text.FgYellow}
+	literalColors = text.Colors{text.FgMagenta}
+	summaryColors = text.Colors{text.Faint}
+)
+
+// RenderPayload renders a payload, or other JSON value, as indented JSON or with
+// options.Flatten as dotted keys, with syntax highlighting when colors are enabled (not
+// with NO_COLOR, or when output isn't to a terminal)
+func RenderPayload(value interface{}, options PayloadOptions) string {
+	r := payloadRenderer{options: options, colors: shouldUseColors()}
+	if options.Flatten {
+		r.flatten("", value, 1)
+		return strings.TrimSuffix(r.b.String(), "\n")
+	}
+	r.value(value, 1, "")
+	return r.b.String()
+}
+
+type payloadRenderer struct {
+	options PayloadOptions
+	colors  bool
+	b       strings.Builder
+}
+
+func (r *payloadRenderer) paint(colors text.Colors, s string) string {
+	if !r.colors {
+		return s
+	}
+	return colors.Sprint(s)
+}
+
+// collapsed reports whether objects and arrays at a level of nesting are summarized
+func (r *payloadRenderer) collapsed(level int) bool {
+	return r.options.Depth > 0 && level > r.options.Depth
+}
+
+// value renders a value as JSON, nested ones indented under indent
+func (r *payloadRenderer) value(value interface{}, level int, indent string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			r.b.WriteString("{}")
+			return
+		}
+		if r.collapsed(level) {
+			r.b.WriteString(r.summary(v))
+			return
+		}
+		r.b.WriteString("{\n")
+		for i, key := range sortedKeys(v) {
+			r.b.WriteString(indent + "  " + r.paint(keyColors, quote(key)) + ": ")
+			r.value(v[key], level+1, indent+"  ")
+			if i < len(v)-1 {
+				r.b.WriteString(",")
+			}
+			r.b.WriteString("\n")
+		}
+		r.b.WriteString(indent + "}")
+	case []interface{}:
+		if len(v) == 0 {
+			r.b.WriteString("[]")
+			return
+		}
+		if r.collapsed(level) {
+			r.b.WriteString(r.summary(v))
+			return
+		}
+		r.b.WriteString("[\n")
+		for i, item := range v {
+			r.b.WriteString(indent + "  ")
+			r.value(item, level+1, indent+"  ")
+			if i < len(v)-1 {
+				r.b.WriteString(",")
+			}
+			r.b.WriteString("\n")
+		}
+		r.b.WriteString(indent + "]")
+	default:
+		r.b.WriteString(r.scalar(v))
+	}
+}
+
+// flatten renders a line per scalar value, or empty or collapsed object or array,
+// under its dotted path
+func (r *payloadRenderer) flatten(path string, value interface{}, level int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 && !r.collapsed(level) {
+			for _, key := range sortedKeys(v) {
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				r.flatten(child, v[key], level+1)
+			}
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 && !r.collapsed(level) {
+			for i, item := range v {
+				r.flatten(fmt.Sprintf("%s[%d]", path, i), item, level+1)
+			}
+			return
+		}
+	}
+
+	rendered := ""
+	switch v := value.(type) {
+	case map[string]interface{}:
+		rendered = "{}"
+		if len(v) > 0 {
+			rendered = r.summary(v)
+		}
+	case []interface{}:
+		rendered = "[]"
+		if len(v) > 0 {
+			rendered = r.summary(v)
+		}
+	default:
+		rendered = r.scalar(v)
+	}
+	if path == "" {
+		// A payload with nothing in it
+		r.b.WriteString(rendered + "\n")
+		return
+	}
+	fmt.Fprintf(&r.b, "%s = %s\n", r.paint(keyColors, path), rendered)
+}
+
+// summary is what a collapsed object or array is shown as
+func (r *payloadRenderer) summary(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return r.paint(summaryColors, fmt.Sprintf("{…%d %s}", len(v), plural(len(v), "key", "keys")))
+	case []interface{}:
+		return r.paint(summaryColors, fmt.Sprintf("[…%d %s]", len(v), plural(len(v), "item", "items")))
+	}
+	return ""
+}
+
+func (r *payloadRenderer) scalar(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	switch value.(type) {
+	case string:
+		return r.paint(stringColors, string(data))
+	case bool, nil:
+		return r.paint(literalColors, string(data))
+	default:
+		return r.paint(numberColors, string(data))
+	}
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
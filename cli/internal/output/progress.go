@@ -0,0 +1,81 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressWidth is the number of characters in a progress bar
+const progressWidth = 30
+
+// Progress draws a progress bar on stderr, redrawn in place. It draws nothing when
+// stderr is not a terminal, so redirected output stays clean, or while output is paged.
+type Progress struct {
+	mu      sync.Mutex
+	label   string
+	total   int
+	done    int
+	started time.Time
+	enabled bool
+}
+
+// NewProgress starts a progress bar counting up to total, or a counter without a bar
+// when total is negative, for an unknown number
+func NewProgress(label string, total int) *Progress {
+	p := &Progress{
+		label:   label,
+		total:   total,
+		started: time.Now(),
+		enabled: !paging && term.IsTerminal(int(os.Stderr.Fd())),
+	}
+	p.draw()
+	return p
+}
+
+// Add advances the progress bar by n
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	p.draw()
+}
+
+// Finish ends the progress bar's line
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.enabled {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+func (p *Progress) draw() {
+	if !p.enabled {
+		return
+	}
+	rate := 0.0
+	if elapsed := time.Since(p.started).Seconds(); elapsed > 0 {
+		rate = float64(p.done) / elapsed
+	}
+	if p.total < 0 {
+		fmt.Fprintf(os.Stderr, "\r%s %d (%.1f/s)", p.label, p.done, rate)
+		return
+	}
+
+	fraction := 1.0
+	if p.total > 0 {
+		fraction = float64(p.done) / float64(p.total)
+	}
+	filled := int(fraction * progressWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+	if filled > 0 && filled < progressWidth {
+		bar = bar[:filled-1] + ">" + bar[filled:]
+	}
+
+	fmt.Fprintf(os.Stderr, "\r%s [%s] %d/%d (%.1f/s)", p.label, bar, p.done, p.total, rate)
+}
@@ -0,0 +1,317 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/search"
+	"github.com/event-store/cli/internal/tail"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// EventStream prints events one at a time as they arrive: a line per event in table
+// format, one JSON object per line in JSON format, and a CSV row (after a header) in
+// CSV format. With columns, only those fields are printed.
+type EventStream struct {
+	format  string
+	columns []EventColumn
+	topic   bool
+	csv     *csv.Writer
+}
+
+// NewEventStream creates an event stream for an output format, printing only the given
+// columns if there are any
+func NewEventStream(format string, columns []EventColumn) *EventStream {
+	return &EventStream{format: format, columns: columns}
+}
+
+// WithTopic prints each event's topic before its other fields, for streams of events
+// from several topics
+func (s *EventStream) WithTopic() *EventStream {
+	s.topic = true
+	if len(s.columns) > 0 && s.columns[0].Field != "topic" {
+		s.columns = append([]EventColumn{{Field: "topic"}}, s.columns...)
+	}
+	return s
+}
+
+// Write prints an event
+func (s *EventStream) Write(event client.Event) error {
+	if len(s.columns) > 0 {
+		return s.writeColumns(event)
+	}
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		payload = []byte(fmt.Sprintf("%v", event.Payload))
+	}
+
+	switch s.format {
+	case "json":
+		var value interface{} = event
+		if s.topic {
+			value = struct {
+				Topic string `json:"topic"`
+				client.Event
+			}{TopicOf(event), event}
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	case "csv":
+		header := []string{"ID", "Timestamp", "Type", "Payload"}
+		row := []string{event.ID, event.Timestamp, event.Type, string(payload)}
+		if s.topic {
+			header, row = append([]string{"Topic"}, header...), append([]string{TopicOf(event)}, row...)
+		}
+		if s.csv == nil {
+			s.csv = csv.NewWriter(os.Stdout)
+			if err := s.csv.Write(header); err != nil {
+				return err
+			}
+		}
+		if err := s.csv.Write(row); err != nil {
+			return err
+		}
+		s.csv.Flush()
+		return s.csv.Error()
+	default:
+		eventType := event.Type
+		if shouldUseColors() {
+			eventType = text.Bold.Sprint(event.Type)
+		}
+		line := fmt.Sprintf("[%s] %s %s", event.Timestamp, event.ID, eventType)
+		if s.topic {
+			line = fmt.Sprintf("[%s] %s %s %s", event.Timestamp, TopicOf(event), event.ID, eventType)
+		}
+		_, err := fmt.Fprintf(os.Stdout, "%s %s\n", line, payload)
+		return err
+	}
+}
+
+// writeColumns prints an event's selected columns. In table format, payload paths are
+// printed as 'path=value' and other fields as they are.
+func (s *EventStream) writeColumns(event client.Event) error {
+	switch s.format {
+	case "json":
+		data, err := json.Marshal(Project(event, s.columns))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	case "csv":
+		if s.csv == nil {
+			s.csv = csv.NewWriter(os.Stdout)
+			if err := s.csv.Write(eventColumnHeaders(s.columns)); err != nil {
+				return err
+			}
+		}
+		if err := s.csv.Write(eventColumnTexts(event, s.columns)); err != nil {
+			return err
+		}
+		s.csv.Flush()
+		return s.csv.Error()
+	default:
+		parts := make([]string, len(s.columns))
+		for i, column := range s.columns {
+			parts[i] = column.Text(event)
+			if strings.HasPrefix(column.Field, "payload.") {
+				parts[i] = column.Field + "=" + parts[i]
+			}
+		}
+		_, err := fmt.Fprintln(os.Stdout, strings.Join(parts, " "))
+		return err
+	}
+}
+
+// DeliveryStream prints delivery attempts one at a time as they are followed, in the
+// same formats as EventStream
+type DeliveryStream struct {
+	format string
+	csv    *csv.Writer
+}
+
+// NewDeliveryStream creates a delivery stream for an output format
+func NewDeliveryStream(format string) *DeliveryStream {
+	return &DeliveryStream{format: format}
+}
+
+// Write prints a delivery attempt
+func (s *DeliveryStream) Write(delivery client.Delivery) error {
+	switch s.format {
+	case "json":
+		data, err := json.Marshal(delivery)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	case "csv":
+		if s.csv == nil {
+			s.csv = csv.NewWriter(os.Stdout)
+			if err := s.csv.Write(deliveryHeader); err != nil {
+				return err
+			}
+		}
+		if err := s.csv.Write(deliveryRow(delivery)); err != nil {
+			return err
+		}
+		s.csv.Flush()
+		return s.csv.Error()
+	default:
+		outcome := "OK"
+		if !delivery.Success {
+			outcome = "FAILED"
+		}
+		if shouldUseColors() {
+			if delivery.Success {
+				outcome = text.FgGreen.Sprint(outcome)
+			} else {
+				outcome = text.FgRed.Sprint(outcome)
+			}
+		}
+		line := fmt.Sprintf("[%s] %s attempt %d %s %s %.1fms", delivery.Timestamp, delivery.EventID, delivery.Attempt,
+			outcome, formatDeliveryStatus(delivery.Status), delivery.LatencyMs)
+		if delivery.Error != "" {
+			line += " " + delivery.Error
+		}
+		_, err := fmt.Fprintln(os.Stdout, line)
+		return err
+	}
+}
+
+// PrintTailSummary prints how a tail ended to stderr
+func PrintTailSummary(summary *tail.Summary) {
+	reason := map[string]string{
+		tail.StoppedUntil:       "--until matched",
+		tail.StoppedMaxEvents:   "--max-events reached",
+		tail.StoppedMaxDuration: "--max-duration reached",
+		tail.StoppedInterrupted: "interrupted",
+	}[summary.StoppedBy]
+
+	events := fmt.Sprintf("%d event(s)", summary.Events)
+	if summary.Events > 0 {
+		events += fmt.Sprintf(" (%s to %s)", summary.FirstEventID, summary.LastEventID)
+	}
+	fmt.Fprintf(os.Stderr, "Stopped: %s; %s in %.1fs\n", reason, events, summary.Seconds)
+}
+
+// PrintTailSummaryJSON prints how a tail ended to stderr as JSON
+func PrintTailSummaryJSON(summary *tail.Summary) error {
+	return json.NewEncoder(os.Stderr).Encode(summary)
+}
+
+// snippetContext is how many bytes of a long matched value are shown around its first match
+const snippetContext = 40
+
+// SearchStream prints search hits one at a time as they are found: the event and its
+// matching fields with the matches highlighted in table format, one JSON object per line
+// in JSON format, and a CSV row per matching field in CSV format
+type SearchStream struct {
+	format string
+	csv    *csv.Writer
+}
+
+// NewSearchStream creates a search stream for an output format
+func NewSearchStream(format string) *SearchStream {
+	return &SearchStream{format: format}
+}
+
+// Write prints an event and its matching fields
+func (s *SearchStream) Write(event client.Event, matches []search.Match) error {
+	switch s.format {
+	case "json":
+		data, err := json.Marshal(struct {
+			client.Event
+			Matches []search.Match `json:"matches"`
+		}{event, matches})
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	case "csv":
+		if s.csv == nil {
+			s.csv = csv.NewWriter(os.Stdout)
+			if err := s.csv.Write([]string{"ID", "Timestamp", "Type", "Field", "Value"}); err != nil {
+				return err
+			}
+		}
+		for _, match := range matches {
+			if err := s.csv.Write([]string{event.ID, event.Timestamp, event.Type, match.Field, match.Value}); err != nil {
+				return err
+			}
+		}
+		s.csv.Flush()
+		return s.csv.Error()
+	default:
+		line := fmt.Sprintf("[%s] %s %s", event.Timestamp, event.ID, event.Type)
+		if shouldUseColors() {
+			line = fmt.Sprintf("[%s] %s %s", event.Timestamp, event.ID, text.Bold.Sprint(event.Type))
+		}
+		fmt.Fprintln(os.Stdout, line)
+		for _, match := range matches {
+			if _, err := fmt.Fprintf(os.Stdout, "    %s: %s\n", match.Field, highlight(match.Value, match.Spans)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// highlight marks the matched spans of a value when colors are enabled, shortening long
+// values to the text around the first match
+func highlight(value string, spans [][2]int) string {
+	start, end := 0, len(value)
+	if end > 3*snippetContext && len(spans) > 0 {
+		start = spans[0][0] - snippetContext
+		if start < 0 {
+			start = 0
+		}
+		end = spans[0][1] + snippetContext
+		if end > len(value) {
+			end = len(value)
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	colors := shouldUseColors()
+	position := start
+	for _, span := range spans {
+		if span[0] < position || span[1] > end {
+			continue
+		}
+		b.WriteString(value[position:span[0]])
+		if colors {
+			b.WriteString(text.Colors{text.BgYellow, text.FgBlack}.Sprint(value[span[0]:span[1]]))
+		} else {
+			b.WriteString(value[span[0]:span[1]])
+		}
+		position = span[1]
+	}
+	b.WriteString(value[position:end])
+	if end < len(value) {
+		b.WriteString("...")
+	}
+	return strings.ToValidUTF8(b.String(), "")
+}
+
+// PrintSearchSummary prints how many events a search matched to stderr
+func PrintSearchSummary(summary *search.Summary) {
+	fmt.Fprintf(os.Stderr, "%d of %d event(s) matched in %.1fs\n", summary.Matched, summary.Scanned, summary.Seconds)
+}
+
+// PrintSearchSummaryJSON prints how many events a search matched to stderr as JSON
+func PrintSearchSummaryJSON(summary *search.Summary) error {
+	return json.NewEncoder(os.Stderr).Encode(summary)
+}
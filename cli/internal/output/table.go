@@ -3,12 +3,36 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/event-store/cli/internal/aggregate"
+	"github.com/event-store/cli/internal/archive"
+	"github.com/event-store/cli/internal/assert"
+	"github.com/event-store/cli/internal/bench"
 	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/consumers"
+	"github.com/event-store/cli/internal/contract"
+	"github.com/event-store/cli/internal/copier"
+	"github.com/event-store/cli/internal/drift"
+	"github.com/event-store/cli/internal/export"
+	"github.com/event-store/cli/internal/history"
+	"github.com/event-store/cli/internal/labels"
+	"github.com/event-store/cli/internal/monitor"
+	"github.com/event-store/cli/internal/owners"
+	"github.com/event-store/cli/internal/replay"
+	"github.com/event-store/cli/internal/seed"
+	"github.com/event-store/cli/internal/spec"
+	"github.com/event-store/cli/internal/spool"
+	"github.com/event-store/cli/internal/trace"
+	"github.com/event-store/cli/internal/verify"
+	"github.com/event-store/cli/internal/watch"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
 	"golang.org/x/term"
 )
 
@@ -19,8 +43,13 @@ func shouldUseColors() bool {
 		return false
 	}
 
-	// Check if stdout is a terminal
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
+	// Markdown and HTML are pasted elsewhere, where escape codes would be noise
+	if markup != "" {
+		return false
+	}
+
+	// Check if stdout is a terminal, or a pager showing one
+	if !paging && !term.IsTerminal(int(os.Stdout.Fd())) {
 		return false
 	}
 
@@ -35,24 +64,6 @@ func getTableStyle() table.Style {
 	return table.StyleDefault
 }
 
-// PrintTopicsList prints a list of topics in table format
-func PrintTopicsList(topics []client.Topic) {
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"Name", "Sequence", "Schema Count"})
-
-	for _, topic := range topics {
-		t.AppendRow(table.Row{
-			topic.Name,
-			strconv.Itoa(topic.Sequence),
-			strconv.Itoa(len(topic.Schemas)),
-		})
-	}
-
-	t.SetStyle(getTableStyle())
-	t.Render()
-}
-
 // PrintTopicDetails prints detailed topic information in table format
 func PrintTopicDetails(topic *client.Topic) {
 	t := table.NewWriter()
@@ -63,11 +74,24 @@ func PrintTopicDetails(topic *client.Topic) {
 	t.AppendRow(table.Row{"Name", topic.Name})
 	t.AppendRow(table.Row{"Sequence", strconv.Itoa(topic.Sequence)})
 	t.AppendRow(table.Row{"Schema Count", strconv.Itoa(len(topic.Schemas))})
-	t.Render()
+	if topic.Partitions > 0 {
+		t.AppendRow(table.Row{"Partitions", strconv.Itoa(topic.Partitions)})
+	}
+	if len(topic.Labels) > 0 {
+		t.AppendRow(table.Row{"Labels", labels.Format(topic.Labels)})
+	}
+	if topic.Owner != nil {
+		for _, row := range []table.Row{{"Owner", topic.Owner.Owner}, {"Team", topic.Owner.Team}, {"Contact", topic.Owner.Contact}} {
+			if row[1] != "" {
+				t.AppendRow(row)
+			}
+		}
+	}
+	render(t)
 
 	// Schemas
 	if len(topic.Schemas) > 0 {
-		fmt.Println("\nSchemas:")
+		printHeading("Schemas")
 		schemaTable := table.NewWriter()
 		schemaTable.SetOutputMirror(os.Stdout)
 		schemaTable.AppendHeader(table.Row{"Event Type", "Type", "Required Fields"})
@@ -87,41 +111,27 @@ func PrintTopicDetails(topic *client.Topic) {
 		}
 
 		schemaTable.SetStyle(getTableStyle())
-		schemaTable.Render()
+		render(schemaTable)
 	}
 }
 
-// PrintConsumersList prints a list of consumers in table format
-func PrintConsumersList(consumers []client.Consumer) {
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"ID", "Callback URL", "Topics"})
+// FormatConsumerTopics formats a consumer's topics as 'topic' or 'topic:lastEventId',
+// sorted by topic so the result is stable
+func FormatConsumerTopics(consumer client.Consumer) string {
+	if len(consumer.Topics) == 0 {
+		return "none"
+	}
 
-	for _, consumer := range consumers {
-		topicsStr := ""
-		if len(consumer.Topics) > 0 {
-			topics := make([]string, 0, len(consumer.Topics))
-			for topic, eventID := range consumer.Topics {
-				if eventID == "" || eventID == "null" {
-					topics = append(topics, topic)
-				} else {
-					topics = append(topics, fmt.Sprintf("%s:%s", topic, eventID))
-				}
-			}
-			topicsStr = strings.Join(topics, ", ")
+	topics := make([]string, 0, len(consumer.Topics))
+	for topic, eventID := range consumer.Topics {
+		if eventID == "" || eventID == "null" {
+			topics = append(topics, topic)
 		} else {
-			topicsStr = "none"
+			topics = append(topics, fmt.Sprintf("%s:%s", topic, eventID))
 		}
-
-		t.AppendRow(table.Row{
-			consumer.ID,
-			consumer.Callback,
-			topicsStr,
-		})
 	}
-
-	t.SetStyle(getTableStyle())
-	t.Render()
+	sort.Strings(topics)
+	return strings.Join(topics, ", ")
 }
 
 // PrintConsumerDetails prints detailed consumer information in table format
@@ -132,11 +142,17 @@ func PrintConsumerDetails(consumer *client.Consumer) {
 
 	t.AppendRow(table.Row{"ID", consumer.ID})
 	t.AppendRow(table.Row{"Callback URL", consumer.Callback})
-	t.Render()
+	if consumer.Description != "" {
+		t.AppendRow(table.Row{"Description", consumer.Description})
+	}
+	if len(consumer.Labels) > 0 {
+		t.AppendRow(table.Row{"Labels", labels.Format(consumer.Labels)})
+	}
+	render(t)
 
 	// Topics mapping
 	if len(consumer.Topics) > 0 {
-		fmt.Println("\nTopics:")
+		printHeading("Topics")
 		topicsTable := table.NewWriter()
 		topicsTable.SetOutputMirror(os.Stdout)
 		topicsTable.AppendHeader(table.Row{"Topic", "Last Event ID"})
@@ -149,7 +165,7 @@ func PrintConsumerDetails(consumer *client.Consumer) {
 		}
 
 		topicsTable.SetStyle(getTableStyle())
-		topicsTable.Render()
+		render(topicsTable)
 	}
 }
 
@@ -158,23 +174,56 @@ func PrintMessage(message string) {
 	fmt.Println(message)
 }
 
-// PrintError prints an error message
-func PrintError(err error) {
-	fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+// PrintIDs prints identifiers one per line, for --quiet output that can be piped into
+// other commands
+func PrintIDs(ids []string) {
+	for _, id := range ids {
+		fmt.Println(id)
+	}
 }
 
-// PrintEventsList prints a list of events in table format
-func PrintEventsList(events []client.Event) {
-	if len(events) == 0 {
-		fmt.Println("No events found")
-		return
+// PrintDiff prints the lines of a unified diff to w, removed lines in red, added lines
+// in green and hunk headers in cyan when colors are enabled
+func PrintDiff(w io.Writer, lines []string) {
+	colors := shouldUseColors()
+	for _, line := range lines {
+		if colors {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				line = text.FgCyan.Sprint(line)
+			case strings.HasPrefix(line, "-"):
+				line = text.FgRed.Sprint(line)
+			case strings.HasPrefix(line, "+"):
+				line = text.FgGreen.Sprint(line)
+			}
+		}
+		fmt.Fprintln(w, line)
 	}
+}
 
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"ID", "Timestamp", "Type", "Payload"})
+// EventIDs returns the IDs of events, in order
+func EventIDs(events []client.Event) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
 
-	for _, event := range events {
+// PrintError prints an error message
+func PrintError(report ErrorReport) {
+	fmt.Fprintf(os.Stderr, "Error: %s\n", report.Error)
+	if report.Hint != "" {
+		fmt.Fprintf(os.Stderr, "Hint: %s\n", report.Hint)
+	}
+}
+
+// PrintEventsList prints a list of events in table format. Payloads are shown as compact
+// JSON, wrapped to fit the terminal, or truncated when output isn't to one or is in
+// markup; --wide shows them whole.
+func PrintEventsList(events []client.Event) {
+	fitted := terminalWidth() > 0
+	printEventsTable(events, true, func(event client.Event) string {
 		// Format payload as compact JSON
 		payloadJSON, err := json.Marshal(event.Payload)
 		payloadStr := string(payloadJSON)
@@ -182,24 +231,77 @@ func PrintEventsList(events []client.Event) {
 			payloadStr = fmt.Sprintf("%v", event.Payload)
 		}
 		// Truncate long payloads
-		if len(payloadStr) > 100 {
+		if !wide && !fitted && len(payloadStr) > 100 {
 			payloadStr = payloadStr[:97] + "..."
 		}
+		return payloadStr
+	})
+}
+
+// PrintExpandedEventsList prints a list of events in table format with their whole
+// payloads, rendered as by RenderPayload
+func PrintExpandedEventsList(events []client.Event, options PayloadOptions) {
+	printEventsTable(events, false, func(event client.Event) string {
+		return RenderPayload(event.Payload, options)
+	})
+}
+
+// printEventsTable prints events in a table, with their payloads as formatted by payload
+// and wrapped, rather than cut short, to fit the terminal if wrap is set
+func printEventsTable(events []client.Event, wrap bool, payload func(client.Event) string) {
+	if len(events) == 0 {
+		printText("No events found")
+		return
+	}
+
+	partitioned := hasPartitions(events)
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	if partitioned {
+		t.AppendHeader(table.Row{"ID", "Partition", "Timestamp", "Type", "Payload"})
+	} else {
+		t.AppendHeader(table.Row{"ID", "Timestamp", "Type", "Payload"})
+	}
 
+	texts := [][]string{{"ID", "Timestamp", "Type", "Payload"}}
+	if partitioned {
+		texts[0] = []string{"ID", "Partition", "Timestamp", "Type", "Payload"}
+	}
+	for _, event := range events {
+		payloadStr := payload(event)
+		if partitioned {
+			t.AppendRow(table.Row{
+				event.ID,
+				partitionLabel(event),
+				event.Timestamp,
+				event.Type,
+				payloadStr,
+			})
+			texts = append(texts, []string{event.ID, partitionLabel(event), event.Timestamp, event.Type, payloadStr})
+			continue
+		}
 		t.AppendRow(table.Row{
 			event.ID,
 			event.Timestamp,
 			event.Type,
 			payloadStr,
 		})
+		texts = append(texts, []string{event.ID, event.Timestamp, event.Type, payloadStr})
 	}
 
 	t.SetStyle(getTableStyle())
-	t.Render()
+	if wrap {
+		fitColumns(t, texts, len(texts[0])-1)
+	} else {
+		fitColumns(t, texts)
+	}
+	render(t)
 }
 
-// PrintEventDetails prints detailed event information without truncation
-func PrintEventDetails(event *client.Event) {
+// PrintEventDetails prints detailed event information without truncation, its payload
+// and metadata rendered as by RenderPayload
+func PrintEventDetails(event *client.Event, options PayloadOptions) {
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
 	t.SetStyle(getTableStyle())
@@ -208,15 +310,20 @@ func PrintEventDetails(event *client.Event) {
 	t.AppendRow(table.Row{"ID", event.ID})
 	t.AppendRow(table.Row{"Timestamp", event.Timestamp})
 	t.AppendRow(table.Row{"Type", event.Type})
-	t.Render()
+	if event.CorrelationID != "" {
+		t.AppendRow(table.Row{"Correlation ID", event.CorrelationID})
+	}
+	if event.CausationID != "" {
+		t.AppendRow(table.Row{"Causation ID", event.CausationID})
+	}
+	render(t)
 
 	// Payload (full, without truncation)
-	fmt.Println("\nPayload:")
-	payloadJSON, err := json.MarshalIndent(event.Payload, "", "  ")
-	if err != nil {
-		fmt.Printf("%v\n", event.Payload)
-	} else {
-		fmt.Println(string(payloadJSON))
+	printHeading("Payload")
+	printBlock(RenderPayload(event.Payload, options))
+	if len(event.Metadata) > 0 {
+		printHeading("Metadata")
+		printBlock(RenderPayload(event.Metadata, options))
 	}
 }
 
@@ -236,7 +343,7 @@ func PrintHealth(health *client.Health) {
 	}
 	t.AppendRow(table.Row{"Running Dispatchers", dispatchersStr})
 
-	t.Render()
+	render(t)
 }
 
 // PrintEventPublishResponse prints event publish response in table format
@@ -251,3 +358,1243 @@ func PrintEventPublishResponse(eventIDs []string) {
 		fmt.Printf("  - %s\n", id)
 	}
 }
+
+// PrintEventPublishDeduped prints the IDs of a publish in table format, counting and
+// marking the events skipped as already published apart from those published now
+func PrintEventPublishDeduped(eventIDs []string, skipped []bool) {
+	alreadyPublished := 0
+	for _, s := range skipped {
+		if s {
+			alreadyPublished++
+		}
+	}
+	if alreadyPublished == len(eventIDs) {
+		fmt.Printf("No events published; %d event(s) were already published:\n", alreadyPublished)
+	} else {
+		fmt.Printf("Published %d event(s), skipped %d already published:\n", len(eventIDs)-alreadyPublished, alreadyPublished)
+	}
+	for i, id := range eventIDs {
+		if skipped[i] {
+			fmt.Printf("  - %s (already published)\n", id)
+		} else {
+			fmt.Printf("  - %s\n", id)
+		}
+	}
+}
+
+// PrintBenchReport prints a benchmark report in table format
+func PrintBenchReport(report *bench.Report) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+
+	for _, property := range report.Extra {
+		t.AppendRow(table.Row{property.Name, property.Value})
+	}
+	t.AppendRow(table.Row{"Duration", fmt.Sprintf("%.2fs", report.Seconds)})
+	t.AppendRow(table.Row{"Requests", strconv.Itoa(report.Sent)})
+	t.AppendRow(table.Row{"Succeeded", strconv.Itoa(report.Succeeded)})
+	t.AppendRow(table.Row{"Failed", strconv.Itoa(report.Failed)})
+	t.AppendRow(table.Row{"Error Rate", fmt.Sprintf("%.2f%%", report.ErrorRate*100)})
+	t.AppendRow(table.Row{"Throughput", fmt.Sprintf("%.1f req/s", report.Throughput)})
+	render(t)
+
+	printHeading("Latency")
+	latencyTable := table.NewWriter()
+	latencyTable.SetOutputMirror(os.Stdout)
+	latencyTable.AppendHeader(table.Row{"Min", "Mean", "P50", "P90", "P95", "P99", "Max"})
+	l := report.Latency
+	latencyTable.AppendRow(table.Row{
+		fmt.Sprintf("%.2fms", l.Min),
+		fmt.Sprintf("%.2fms", l.Mean),
+		fmt.Sprintf("%.2fms", l.P50),
+		fmt.Sprintf("%.2fms", l.P90),
+		fmt.Sprintf("%.2fms", l.P95),
+		fmt.Sprintf("%.2fms", l.P99),
+		fmt.Sprintf("%.2fms", l.Max),
+	})
+	latencyTable.SetStyle(getTableStyle())
+	render(latencyTable)
+
+	if len(report.Errors) > 0 {
+		printHeading("Errors")
+		errorsTable := table.NewWriter()
+		errorsTable.SetOutputMirror(os.Stdout)
+		errorsTable.AppendHeader(table.Row{"Error", "Count"})
+		messages := make([]string, 0, len(report.Errors))
+		for message := range report.Errors {
+			messages = append(messages, message)
+		}
+		sort.Strings(messages)
+		for _, message := range messages {
+			errorsTable.AppendRow(table.Row{message, strconv.Itoa(report.Errors[message])})
+		}
+		errorsTable.SetStyle(getTableStyle())
+		render(errorsTable)
+	}
+}
+
+// PrintClientStats prints the client's own request statistics as a footer
+func PrintClientStats(w io.Writer, stats client.MetricsSnapshot) {
+	if stats.Requests() == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nClient Requests:")
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Endpoint", "Requests", "Errors", "Failures", "Mean"})
+	for _, e := range stats.Endpoints {
+		mean := time.Duration(0)
+		if e.Requests > 0 {
+			mean = e.Total / time.Duration(e.Requests)
+		}
+		t.AppendRow(table.Row{e.Endpoint, e.Requests, e.Errors, e.Failures, formatMillis(mean)})
+	}
+	t.SetStyle(getTableStyle())
+	t.Render()
+
+	fmt.Fprintln(w, "\nConnections & Timings:")
+	timings := table.NewWriter()
+	timings.SetOutputMirror(w)
+	timings.AppendRow(table.Row{"New Connections", strconv.Itoa(stats.NewConns)})
+	timings.AppendRow(table.Row{"Reused Connections", fmt.Sprintf("%d (%d idle)", stats.ReusedConns, stats.IdleConns)})
+	for _, phase := range stats.Phases() {
+		if phase.Stats.Count == 0 {
+			continue
+		}
+		timings.AppendRow(table.Row{
+			phase.Label,
+			fmt.Sprintf("mean %s, max %s (%d)", formatMillis(phase.Stats.Mean()), formatMillis(phase.Stats.Max), phase.Stats.Count),
+		})
+	}
+	timings.SetStyle(getTableStyle())
+	timings.Render()
+}
+
+func formatMillis(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
+
+// hasPartitions reports whether any event carries a partition ID
+func hasPartitions(events []client.Event) bool {
+	for _, event := range events {
+		if event.Partition != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// partitionLabel returns the event's partition ID, or "-" for unpartitioned events
+func partitionLabel(event client.Event) string {
+	if event.Partition == nil {
+		return "-"
+	}
+	return strconv.Itoa(*event.Partition)
+}
+
+// PrintWatchTable clears the terminal if clear is set and prints a watched table. The
+// first column marks added (+), modified (~) and deleted (-) rows, which are also
+// coloured when colours are enabled, with modified cells highlighted.
+func PrintWatchTable(title string, header []string, changes []watch.Change, clear bool) {
+	if clear {
+		fmt.Print("\033[H\033[2J")
+	}
+	fmt.Println(title)
+	fmt.Println()
+
+	colors := shouldUseColors()
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+
+	headerRow := table.Row{""}
+	for _, column := range header {
+		headerRow = append(headerRow, column)
+	}
+	t.AppendHeader(headerRow)
+
+	for _, change := range changes {
+		marker, rowColor := "", text.Colors(nil)
+		switch change.Kind {
+		case watch.Added:
+			marker, rowColor = "+", text.Colors{text.FgGreen}
+		case watch.Modified:
+			marker = "~"
+		case watch.Deleted:
+			marker, rowColor = "-", text.Colors{text.FgRed, text.CrossedOut}
+		}
+
+		changed := make(map[int]bool, len(change.Columns))
+		for _, i := range change.Columns {
+			changed[i] = true
+		}
+
+		row := table.Row{marker}
+		for i, cell := range change.Row.Cells {
+			switch {
+			case !colors:
+				row = append(row, cell)
+			case changed[i]:
+				row = append(row, text.Colors{text.FgYellow, text.Bold}.Sprint(cell))
+			case rowColor != nil:
+				row = append(row, rowColor.Sprint(cell))
+			default:
+				row = append(row, cell)
+			}
+		}
+		t.AppendRow(row)
+	}
+
+	t.SetStyle(getTableStyle())
+	render(t)
+}
+
+// PrintConsumerMonitor clears the terminal if clear is set and prints a listing of
+// consumer deliveries under a title, with a footer. The first column marks the rows
+// whose keys are alerting (!), which are also red when colours are enabled.
+func PrintConsumerMonitor(title string, l *Listing, alerting map[string]bool, footer string, clear bool) {
+	if clear {
+		fmt.Print("\033[H\033[2J")
+	}
+	fmt.Println(title)
+	fmt.Println()
+
+	colors := shouldUseColors()
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+
+	headerRow := table.Row{""}
+	for _, column := range l.Header() {
+		headerRow = append(headerRow, column)
+	}
+	t.AppendHeader(headerRow)
+	texts := [][]string{append([]string{""}, l.Header()...)}
+
+	for _, r := range l.Rows() {
+		marker := ""
+		if alerting[r.Key] {
+			marker = "!"
+		}
+		row := table.Row{marker}
+		for _, cell := range r.Cells {
+			if colors && alerting[r.Key] {
+				row = append(row, text.FgRed.Sprint(cell))
+			} else {
+				row = append(row, cell)
+			}
+		}
+		t.AppendRow(row)
+		texts = append(texts, append([]string{marker}, r.Cells...))
+	}
+
+	t.SetStyle(getTableStyle())
+	fitColumns(t, texts)
+	render(t)
+	if footer != "" {
+		fmt.Println(footer)
+	}
+}
+
+// PrintRetention prints a topic's retention limits in table format
+func PrintRetention(retention *client.Retention) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Topic", retention.Topic})
+	t.AppendRow(table.Row{"Max Age", FormatRetentionAge(retention.MaxAgeSeconds)})
+	t.AppendRow(table.Row{"Max Events", FormatRetentionEvents(retention.MaxEvents)})
+	render(t)
+}
+
+// FormatRetentionAge formats a retention age in whole days or weeks where it can, or
+// "unlimited" for 0
+func FormatRetentionAge(seconds int64) string {
+	const day = 24 * 60 * 60
+	switch {
+	case seconds == 0:
+		return "unlimited"
+	case seconds%(7*day) == 0:
+		return fmt.Sprintf("%dw", seconds/(7*day))
+	case seconds%day == 0:
+		return fmt.Sprintf("%dd", seconds/day)
+	default:
+		return (time.Duration(seconds) * time.Second).String()
+	}
+}
+
+// FormatRetentionEvents formats a retention event count, or "unlimited" for 0
+func FormatRetentionEvents(events int64) string {
+	if events == 0 {
+		return "unlimited"
+	}
+	return strconv.FormatInt(events, 10)
+}
+
+// PrintTruncateResult prints how many events a truncation deleted, or would delete
+func PrintTruncateResult(result *client.TruncateResult) {
+	verb := "Deleted"
+	if result.DryRun {
+		verb = "Would delete"
+	}
+	fmt.Printf("%s %d event(s) of '%s'\n", verb, result.Deleted, result.Topic)
+	if result.FirstEventID != "" {
+		fmt.Printf("Oldest remaining event: %s\n", result.FirstEventID)
+	}
+}
+
+// PrintTierManifest prints a topic's cold tier and its segments in table format
+func PrintTierManifest(manifest *archive.TierManifest) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Topic", manifest.Topic})
+	t.AppendRow(table.Row{"Location", manifest.Location})
+	t.AppendRow(table.Row{"Segments", strconv.Itoa(len(manifest.Segments))})
+	t.AppendRow(table.Row{"Events", strconv.Itoa(manifest.Count())})
+	render(t)
+
+	if len(manifest.Segments) == 0 {
+		return
+	}
+
+	printHeading("Segments")
+	segmentTable := table.NewWriter()
+	segmentTable.SetOutputMirror(os.Stdout)
+	segmentTable.AppendHeader(table.Row{"File", "First Event", "Last Event", "Events", "From", "To"})
+	for _, segment := range manifest.Segments {
+		segmentTable.AppendRow(table.Row{
+			segment.File,
+			segment.FirstEventID,
+			segment.LastEventID,
+			strconv.Itoa(segment.Count),
+			segment.FirstTimestamp,
+			segment.LastTimestamp,
+		})
+	}
+	segmentTable.SetStyle(getTableStyle())
+	render(segmentTable)
+}
+
+// PrintHealthCheck prints a health status transition as a timestamped line
+func PrintHealthCheck(check monitor.HealthCheck) {
+	line := fmt.Sprintf("[%s] %s (consumers: %d, dispatchers: %d)", check.Time.Format(time.RFC3339), check.Status, check.Consumers, check.Dispatchers)
+	if check.Degraded {
+		line = fmt.Sprintf("[%s] DEGRADED: %s (consumers: %d, dispatchers: %d)", check.Time.Format(time.RFC3339), check.Reason, check.Consumers, check.Dispatchers)
+		if shouldUseColors() {
+			line = text.Colors{text.FgRed, text.Bold}.Sprint(line)
+		}
+	}
+	fmt.Println(line)
+}
+
+// PrintStatus prints a status overview in table format: a summary followed by the topics
+// and the consumers, most lagging first
+func PrintStatus(status *monitor.Status) {
+	version := status.ServerVersion
+	if version == "" {
+		version = "not reported"
+	}
+	dispatchers := "None"
+	if len(status.Dispatchers) > 0 {
+		dispatchers = strings.Join(status.Dispatchers, ", ")
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Server", status.Server})
+	t.AppendRow(table.Row{"Server Version", version})
+	t.AppendRow(table.Row{"Health", status.Health})
+	t.AppendRow(table.Row{"Running Dispatchers", dispatchers})
+	t.AppendRow(table.Row{"Topics", strconv.Itoa(len(status.Topics))})
+	t.AppendRow(table.Row{"Events", strconv.FormatInt(status.Events, 10)})
+	t.AppendRow(table.Row{"Consumers", strconv.Itoa(len(status.Consumers))})
+	t.AppendRow(table.Row{"Consumer Lag", strconv.FormatInt(status.Lag, 10)})
+	render(t)
+
+	if len(status.Topics) > 0 {
+		printHeading("Topics")
+		topicsTable := table.NewWriter()
+		topicsTable.SetOutputMirror(os.Stdout)
+		topicsTable.AppendHeader(table.Row{"Topic", "Sequence", "Consumers", "Max Lag"})
+		for _, topic := range status.Topics {
+			topicsTable.AppendRow(table.Row{topic.Name, topic.Sequence, topic.Consumers, topic.MaxLag})
+		}
+		topicsTable.SetStyle(getTableStyle())
+		render(topicsTable)
+	}
+
+	if len(status.Consumers) > 0 {
+		printHeading("Consumers")
+		consumersTable := table.NewWriter()
+		consumersTable.SetOutputMirror(os.Stdout)
+		consumersTable.AppendHeader(table.Row{"ID", "Callback URL", "Lag", "Positions"})
+		for _, consumer := range status.Consumers {
+			consumersTable.AppendRow(table.Row{consumer.ID, consumer.Callback, consumer.Lag, formatPositions(consumer.Topics)})
+		}
+		consumersTable.SetStyle(getTableStyle())
+		render(consumersTable)
+	}
+}
+
+// formatPositions formats a consumer's topic positions as 'topic: lag', or 'topic: ?'
+// when the lag is unknown
+func formatPositions(positions []monitor.ConsumerTopicStatus) string {
+	parts := make([]string, 0, len(positions))
+	for _, position := range positions {
+		lag := "?"
+		if position.Lag != nil {
+			lag = strconv.FormatInt(*position.Lag, 10)
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", position.Topic, lag))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PrintVersion prints the CLI version and, when serverInfo is not nil, the server's
+// version and features in table format
+func PrintVersion(cliVersion, server string, serverInfo *client.ServerInfo) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"CLI Version", cliVersion})
+	if serverInfo != nil {
+		version, apiVersion, features := "not reported", "not reported", "none"
+		if serverInfo.Reported {
+			version = serverInfo.Version
+			if serverInfo.APIVersion != "" {
+				apiVersion = serverInfo.APIVersion
+			}
+			if len(serverInfo.Features) > 0 {
+				features = strings.Join(serverInfo.Features, ", ")
+			}
+		}
+		t.AppendRow(table.Row{"Server", server})
+		t.AppendRow(table.Row{"Server Version", version})
+		t.AppendRow(table.Row{"API Version", apiVersion})
+		t.AppendRow(table.Row{"Features", features})
+	}
+	render(t)
+}
+
+// PrintReplayResult prints the outcome of a consumer replay in table format
+func PrintReplayResult(result *replay.Result) {
+	if result.Delivered == 0 && !result.ServerSide {
+		fmt.Println("No events to replay")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Consumer", result.ConsumerID})
+	t.AppendRow(table.Row{"Topic", result.Topic})
+	if result.ServerSide {
+		t.AppendRow(table.Row{"Replayed By", "server"})
+		t.AppendRow(table.Row{"Events", strconv.Itoa(result.Delivered)})
+	} else {
+		t.AppendRow(table.Row{"Events", fmt.Sprintf("%d (%s to %s)", result.Delivered, result.FirstEventID, result.LastEventID)})
+		t.AppendRow(table.Row{"Deliveries", strconv.Itoa(result.Deliveries)})
+		t.AppendRow(table.Row{"Duration", fmt.Sprintf("%.2fs", result.Seconds)})
+	}
+	render(t)
+}
+
+// PrintTestResult prints a test's outcome as a PASS or FAIL line followed by its failures
+func PrintTestResult(result spec.Result) {
+	status := "PASS"
+	if !result.Passed {
+		status = "FAIL"
+	}
+	if shouldUseColors() {
+		if result.Passed {
+			status = text.FgGreen.Sprint(status)
+		} else {
+			status = text.Colors{text.FgRed, text.Bold}.Sprint(status)
+		}
+	}
+	fmt.Printf("%s  %s: %s (%.2fs)\n", status, result.Spec, result.Name, result.Seconds)
+	for _, failure := range result.Failures {
+		fmt.Printf("      %s\n", failure)
+	}
+}
+
+// PrintContractReport prints a PASS or FAIL line per contract example, followed by its
+// problems, and a summary
+func PrintContractReport(report *contract.Report) {
+	for _, result := range report.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		if shouldUseColors() {
+			if result.Passed {
+				status = text.FgGreen.Sprint(status)
+			} else {
+				status = text.Colors{text.FgRed, text.Bold}.Sprint(status)
+			}
+		}
+		fmt.Printf("%s  %s: %s %s (%s)\n", status, result.Contract, result.Topic, result.Type, result.Example)
+		for _, problem := range result.Problems {
+			fmt.Printf("      %s\n", problem)
+		}
+	}
+	fmt.Printf("\n%d passed, %d failed\n", report.Passed, report.Failed)
+}
+
+// PrintCopyResult prints the outcome of a topic-to-topic copy in table format
+func PrintCopyResult(result *copier.Result) {
+	if result.Read == 0 {
+		fmt.Println("No events to copy")
+		return
+	}
+
+	destination := result.DestTopic
+	if result.DestServer != "" {
+		destination += " on " + result.DestServer
+	}
+	copied := strconv.Itoa(result.Copied)
+	if result.DryRun {
+		copied += " (dry run, nothing published)"
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Source", result.SourceTopic})
+	t.AppendRow(table.Row{"Destination", destination})
+	t.AppendRow(table.Row{"Read", fmt.Sprintf("%d (%s to %s)", result.Read, result.FirstEventID, result.LastEventID)})
+	t.AppendRow(table.Row{"Copied", copied})
+	t.AppendRow(table.Row{"Skipped", strconv.Itoa(result.Skipped)})
+	t.AppendRow(table.Row{"Duration", fmt.Sprintf("%.2fs", result.Seconds)})
+	render(t)
+}
+
+// PrintConsumerDeletions prints the consumers a bulk delete removed, or would remove,
+// and a summary line
+func PrintConsumerDeletions(result *consumers.Result) {
+	if len(result.Deletions) == 0 {
+		fmt.Println("No consumers matched")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"ID", "Callback", "Status", "Error"})
+	for _, deletion := range result.Deletions {
+		status := deletion.Status
+		if deletion.Status == consumers.Failed && shouldUseColors() {
+			status = text.FgRed.Sprint(status)
+		}
+		t.AppendRow(table.Row{deletion.ID, deletion.Callback, status, deletion.Error})
+	}
+	render(t)
+
+	if result.DryRun {
+		fmt.Printf("Dry run: %d consumer(s) would be deleted\n", len(result.Deletions))
+		return
+	}
+	fmt.Printf("%d consumer(s) deleted, %d failed\n", result.Deleted, result.Failed)
+}
+
+// PrintConsumerPings prints the outcome of probing consumers' callbacks
+func PrintConsumerPings(pings []consumers.Ping) {
+	if len(pings) == 0 {
+		fmt.Println("No consumers to ping")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"ID", "Callback", "Result", "Latency", "TLS", "Error"})
+	for _, ping := range pings {
+		var result string
+		switch {
+		case !ping.Reachable:
+			result = "UNREACHABLE"
+		case !ping.OK:
+			result = fmt.Sprintf("HTTP %d", ping.Status)
+		default:
+			result = fmt.Sprintf("OK (%d)", ping.Status)
+		}
+		if shouldUseColors() {
+			if ping.OK {
+				result = text.FgGreen.Sprint(result)
+			} else {
+				result = text.FgRed.Sprint(result)
+			}
+		}
+		latency := ""
+		if ping.Reachable {
+			latency = fmt.Sprintf("%.1fms", ping.LatencyMs)
+		}
+		t.AppendRow(table.Row{ping.ID, ping.Callback, result, latency, formatTLS(ping.TLS), ping.Error})
+	}
+	render(t)
+}
+
+// formatTLS describes a callback's TLS connection, e.g. "TLS 1.3, example.com (Let's
+// Encrypt), expires 2027-01-05 (81 days)"
+func formatTLS(info *consumers.TLSInfo) string {
+	if info == nil {
+		return ""
+	}
+	if info.Subject == "" {
+		return info.Version
+	}
+	return fmt.Sprintf("%s, %s (%s), expires %s (%d days)",
+		info.Version, info.Subject, info.Issuer, info.NotAfter.Format("2006-01-02"), info.DaysLeft)
+}
+
+// PrintDLQEntries prints the entries of a dead-letter queue in table format
+func PrintDLQEntries(entries []client.DLQEntry) {
+	if len(entries) == 0 {
+		fmt.Println("Dead-letter queue is empty")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Entry", "Event", "Type", "Attempts", "Last Status", "Last Failed", "Last Error"})
+	for _, entry := range entries {
+		t.AppendRow(table.Row{entry.ID, entry.Event.ID, entry.Event.Type, entry.Attempts, formatDeliveryStatus(entry.LastStatus), entry.LastFailedAt, entry.LastError})
+	}
+	render(t)
+	fmt.Printf("%d dead-lettered event(s)\n", len(entries))
+}
+
+// PrintDLQEntry prints an entry of a dead-letter queue with its event's payload
+func PrintDLQEntry(entry *client.DLQEntry) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Entry", entry.ID})
+	t.AppendRow(table.Row{"Consumer", entry.ConsumerID})
+	t.AppendRow(table.Row{"Topic", entry.Topic})
+	t.AppendRow(table.Row{"Event", entry.Event.ID})
+	t.AppendRow(table.Row{"Type", entry.Event.Type})
+	t.AppendRow(table.Row{"Timestamp", entry.Event.Timestamp})
+	t.AppendRow(table.Row{"Attempts", strconv.Itoa(entry.Attempts)})
+	t.AppendRow(table.Row{"First Failed", entry.FirstFailedAt})
+	t.AppendRow(table.Row{"Last Failed", entry.LastFailedAt})
+	t.AppendRow(table.Row{"Last Status", formatDeliveryStatus(entry.LastStatus)})
+	t.AppendRow(table.Row{"Last Error", entry.LastError})
+	render(t)
+
+	printHeading("Payload")
+	payloadJSON, err := json.MarshalIndent(entry.Event.Payload, "", "  ")
+	if err != nil {
+		fmt.Printf("%v\n", entry.Event.Payload)
+	} else {
+		fmt.Println(string(payloadJSON))
+	}
+}
+
+// formatDeliveryStatus shows a callback's HTTP status, or that there was no response
+func formatDeliveryStatus(status int) string {
+	if status == 0 {
+		return "no response"
+	}
+	return strconv.Itoa(status)
+}
+
+// PrintRetryResult prints the outcome of retrying dead-lettered events
+func PrintRetryResult(result *consumers.RetryResult) {
+	if len(result.Outcomes) == 0 {
+		fmt.Println("No entries to retry")
+		return
+	}
+
+	failures := table.NewWriter()
+	failures.SetOutputMirror(os.Stdout)
+	failures.SetStyle(getTableStyle())
+	failures.AppendHeader(table.Row{"Entry", "Event", "Error"})
+	for _, outcome := range result.Outcomes {
+		if outcome.Status == consumers.RetryFailed {
+			failures.AppendRow(table.Row{outcome.EntryID, outcome.EventID, outcome.Error})
+		}
+	}
+	if failures.Length() > 0 {
+		render(failures)
+	}
+	fmt.Printf("%d retried, %d failed in %.1fs\n", result.Retried, result.Failed, result.Seconds)
+}
+
+// PrintDeliveries prints delivery attempts in table format
+func PrintDeliveries(deliveries []client.Delivery) {
+	if len(deliveries) == 0 {
+		fmt.Println("No deliveries found")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Timestamp", "Event", "Attempt", "Outcome", "Status", "Latency", "Error"})
+	for _, delivery := range deliveries {
+		outcome := "OK"
+		if !delivery.Success {
+			outcome = "FAILED"
+			if shouldUseColors() {
+				outcome = text.FgRed.Sprint(outcome)
+			}
+		}
+		t.AppendRow(table.Row{delivery.Timestamp, delivery.EventID, delivery.Attempt, outcome,
+			formatDeliveryStatus(delivery.Status), fmt.Sprintf("%.1fms", delivery.LatencyMs), delivery.Error})
+	}
+	render(t)
+}
+
+// PrintAssertResult prints the outcome of an assertion as a PASS or FAIL line
+func PrintAssertResult(result *assert.Result) {
+	status := "PASS"
+	if !result.Passed {
+		status = "FAIL"
+	}
+	if shouldUseColors() {
+		if result.Passed {
+			status = text.FgGreen.Sprint(status)
+		} else {
+			status = text.Colors{text.FgRed, text.Bold}.Sprint(status)
+		}
+	}
+	filter := result.Filter
+	if filter == "" {
+		filter = "any event"
+	}
+	fmt.Printf("%s  %d event(s) on %s matched %s (expected %s)\n", status, result.Matched, result.Topic, filter, result.Count)
+	if len(result.MatchedIDs) > 0 {
+		ids := strings.Join(result.MatchedIDs, ", ")
+		if result.Matched > len(result.MatchedIDs) {
+			ids += ", ..."
+		}
+		fmt.Printf("      matched: %s\n", ids)
+	}
+}
+
+// PrintAssertOutcome prints the outcome of an assertion on a topic or consumer as a PASS
+// or FAIL line
+func PrintAssertOutcome(outcome *assert.Outcome) {
+	status := "PASS"
+	if !outcome.Passed {
+		status = "FAIL"
+	}
+	if shouldUseColors() {
+		if outcome.Passed {
+			status = text.FgGreen.Sprint(status)
+		} else {
+			status = text.Colors{text.FgRed, text.Bold}.Sprint(status)
+		}
+	}
+	fmt.Printf("%s  %s: %s (expected %s)\n", status, outcome.Subject, outcome.Actual, outcome.Expected)
+}
+
+// PrintTopicStats prints a topic's statistics in table format. With chart, events per
+// day are drawn as a sparkline and the event types as bars instead of being listed.
+func PrintTopicStats(stats *client.TopicStats, chart bool) {
+	if stats.Events == 0 {
+		fmt.Printf("Topic '%s' has no events\n", stats.Topic)
+		return
+	}
+
+	sizes := stats.PayloadBytes
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Topic", stats.Topic})
+	t.AppendRow(table.Row{"Events", strconv.FormatInt(stats.Events, 10)})
+	t.AppendRow(table.Row{"First Event", fmt.Sprintf("%s (%s)", stats.FirstEventID, stats.FirstTimestamp)})
+	t.AppendRow(table.Row{"Last Event", fmt.Sprintf("%s (%s)", stats.LastEventID, stats.LastTimestamp)})
+	t.AppendRow(table.Row{"Event Types", strconv.Itoa(len(stats.PerType))})
+	t.AppendRow(table.Row{"Payload Bytes", fmt.Sprintf("min %d, mean %.0f, p50 %d, p90 %d, p99 %d, max %d", sizes.Min, sizes.Mean, sizes.P50, sizes.P90, sizes.P99, sizes.Max)})
+	t.AppendRow(table.Row{"Total Payload", formatBytes(sizes.Total)})
+	render(t)
+
+	if chart {
+		printTopicStatsCharts(stats)
+		return
+	}
+
+	printHeading("Events by Type")
+	typesTable := table.NewWriter()
+	typesTable.SetOutputMirror(os.Stdout)
+	typesTable.AppendHeader(table.Row{"Type", "Events", "Share"})
+	for _, count := range stats.PerType {
+		typesTable.AppendRow(table.Row{count.Type, count.Events, fmt.Sprintf("%.1f%%", 100*float64(count.Events)/float64(stats.Events))})
+	}
+	typesTable.SetStyle(getTableStyle())
+	render(typesTable)
+
+	printHeading("Payload Sizes")
+	sizesTable := table.NewWriter()
+	sizesTable.SetOutputMirror(os.Stdout)
+	sizesTable.AppendHeader(table.Row{"Size", "Events"})
+	for _, bucket := range sizes.Buckets {
+		sizesTable.AppendRow(table.Row{formatSizeBucket(bucket), bucket.Events})
+	}
+	sizesTable.SetStyle(getTableStyle())
+	render(sizesTable)
+
+	if len(stats.PerDay) > 0 {
+		printHeading("Events per Day")
+		daysTable := table.NewWriter()
+		daysTable.SetOutputMirror(os.Stdout)
+		daysTable.AppendHeader(table.Row{"Date", "Events"})
+		for _, day := range stats.PerDay {
+			daysTable.AppendRow(table.Row{day.Date, day.Events})
+		}
+		daysTable.SetStyle(getTableStyle())
+		render(daysTable)
+	}
+}
+
+func printTopicStatsCharts(stats *client.TopicStats) {
+	if len(stats.PerDay) > 0 {
+		counts := make([]int64, len(stats.PerDay))
+		var peak client.DayCount
+		for i, day := range stats.PerDay {
+			counts[i] = day.Events
+			if day.Events > peak.Events {
+				peak = day
+			}
+		}
+		fmt.Printf("\nEvents per day, %s to %s (peak %d on %s):\n", stats.PerDay[0].Date, stats.PerDay[len(stats.PerDay)-1].Date, peak.Events, peak.Date)
+		fmt.Printf("  %s\n", Sparkline(counts))
+	}
+
+	width := 0
+	for _, count := range stats.PerType {
+		if len(count.Type) > width {
+			width = len(count.Type)
+		}
+	}
+	printHeading("Events by type")
+	for _, count := range stats.PerType {
+		fmt.Printf("  %-*s %8d %s\n", width, count.Type, count.Events, Bar(count.Events, stats.PerType[0].Events, 40))
+	}
+
+	var peak int64
+	for _, bucket := range stats.PayloadBytes.Buckets {
+		if bucket.Events > peak {
+			peak = bucket.Events
+		}
+	}
+	printHeading("Payload sizes")
+	for _, bucket := range stats.PayloadBytes.Buckets {
+		fmt.Printf("  %-13s %8d %s\n", formatSizeBucket(bucket), bucket.Events, Bar(bucket.Events, peak, 40))
+	}
+}
+
+// formatSizeBucket describes a payload size bucket, e.g. "1KB-4KB" or ">= 64KB"
+func formatSizeBucket(bucket client.SizeBucket) string {
+	if bucket.Max == 0 {
+		return ">= " + formatBytes(int64(bucket.Min))
+	}
+	return fmt.Sprintf("%s-%s", formatBytes(int64(bucket.Min)), formatBytes(int64(bucket.Max)))
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "512B" or "1.5MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value := float64(n) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit {
+			return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + suffix
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1fTB", value)
+}
+
+// pivotColumns is the most group columns a pivot table has; smaller groups are added
+// up in an "Other" column
+const pivotColumns = 10
+
+// PrintAggregateResult prints aggregated event counts in table format. Counts by time
+// bucket are pivoted with a row per bucket and a column per group, as are counts by two
+// fields (a row per value of the first, a column per value of the second). Other counts
+// are listed with their share of the events.
+func PrintAggregateResult(result *aggregate.Result) {
+	if result.Events == 0 {
+		fmt.Println("No events to aggregate")
+		return
+	}
+
+	switch {
+	case result.Interval != "":
+		labels := make([]string, len(result.Series))
+		for i, series := range result.Series {
+			labels[i] = strings.Join(series.Key, " / ")
+		}
+		rows := make([][]int64, len(result.Buckets))
+		for i := range result.Buckets {
+			rows[i] = make([]int64, len(result.Series))
+			for j, series := range result.Series {
+				rows[i][j] = series.Counts[i]
+			}
+		}
+		printPivot("Bucket", result.Buckets, labels, rows)
+	case len(result.GroupBy) == 2:
+		var rowLabels, columnLabels []string
+		rowIndex := make(map[string]int)
+		columnTotals := make(map[string]int64)
+		for _, series := range result.Series {
+			if _, ok := rowIndex[series.Key[0]]; !ok {
+				rowIndex[series.Key[0]] = len(rowLabels)
+				rowLabels = append(rowLabels, series.Key[0])
+			}
+			if _, ok := columnTotals[series.Key[1]]; !ok {
+				columnLabels = append(columnLabels, series.Key[1])
+			}
+			columnTotals[series.Key[1]] += series.Count
+		}
+		sort.SliceStable(columnLabels, func(i, j int) bool {
+			return columnTotals[columnLabels[i]] > columnTotals[columnLabels[j]]
+		})
+		columnIndex := make(map[string]int, len(columnLabels))
+		for i, label := range columnLabels {
+			columnIndex[label] = i
+		}
+		rows := make([][]int64, len(rowLabels))
+		for i := range rows {
+			rows[i] = make([]int64, len(columnLabels))
+		}
+		for _, series := range result.Series {
+			rows[rowIndex[series.Key[0]]][columnIndex[series.Key[1]]] = series.Count
+		}
+		printPivot(result.GroupBy[0]+" \\ "+result.GroupBy[1], rowLabels, columnLabels, rows)
+	default:
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		header := table.Row{}
+		for _, field := range result.GroupBy {
+			header = append(header, field)
+		}
+		t.AppendHeader(append(header, "Events", "Share"))
+		for _, series := range result.Series {
+			row := table.Row{}
+			for _, value := range series.Key {
+				row = append(row, value)
+			}
+			t.AppendRow(append(row, series.Count, fmt.Sprintf("%.1f%%", 100*float64(series.Count)/float64(result.Events))))
+		}
+		t.AppendFooter(append(make(table.Row, len(result.GroupBy)-1), "Total", result.Events, ""))
+		t.SetStyle(getTableStyle())
+		render(t)
+	}
+}
+
+// printPivot prints counts with a row per row label and a column per column label (the
+// largest first), folding columns beyond pivotColumns into "Other" and adding totals
+func printPivot(corner string, rowLabels, columnLabels []string, rows [][]int64) {
+	shown := len(columnLabels)
+	if shown > pivotColumns {
+		shown = pivotColumns - 1
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	header := table.Row{corner}
+	for _, label := range columnLabels[:shown] {
+		header = append(header, label)
+	}
+	if shown < len(columnLabels) {
+		header = append(header, fmt.Sprintf("Other (%d)", len(columnLabels)-shown))
+	}
+	t.AppendHeader(append(header, "Total"))
+
+	totals := make([]int64, shown+2)
+	for i, label := range rowLabels {
+		row := table.Row{label}
+		var other, total int64
+		for j, count := range rows[i] {
+			if j < shown {
+				row = append(row, count)
+				totals[j] += count
+			} else {
+				other += count
+			}
+			total += count
+		}
+		if shown < len(columnLabels) {
+			row = append(row, other)
+			totals[shown] += other
+		}
+		totals[len(totals)-1] += total
+		t.AppendRow(append(row, total))
+	}
+
+	footer := table.Row{"Total"}
+	for j := 0; j < shown; j++ {
+		footer = append(footer, totals[j])
+	}
+	if shown < len(columnLabels) {
+		footer = append(footer, totals[shown])
+	}
+	t.AppendFooter(append(footer, totals[len(totals)-1]))
+	t.SetStyle(getTableStyle())
+	render(t)
+}
+
+// PrintExportResult prints what an event export wrote
+func PrintExportResult(result *export.Result) {
+	format := result.Format
+	if result.Compression != "" {
+		format += " (" + result.Compression + ")"
+	}
+	events := strconv.Itoa(result.Events)
+	if result.Events > 0 {
+		events += fmt.Sprintf(" (%s to %s)", result.FirstEventID, result.LastEventID)
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Topic", result.Topic})
+	t.AppendRow(table.Row{"File", result.File})
+	t.AppendRow(table.Row{"Format", format})
+	if len(result.Columns) > 0 {
+		t.AppendRow(table.Row{"Columns", strings.Join(result.Columns, ", ")})
+	}
+	if result.Filter != "" {
+		t.AppendRow(table.Row{"Filter", result.Filter})
+	}
+	if len(result.Masked) > 0 {
+		t.AppendRow(table.Row{"Masked", strings.Join(result.Masked, ", ")})
+	}
+	t.AppendRow(table.Row{"Events", events})
+	if result.Encrypted {
+		t.AppendRow(table.Row{"Encrypted", "yes"})
+	}
+	t.AppendRow(table.Row{"Size", formatBytes(result.Bytes)})
+	t.AppendRow(table.Row{"SHA-256", result.SHA256})
+	if result.Verified {
+		t.AppendRow(table.Row{"Verified", "yes"})
+	}
+	render(t)
+}
+
+// PrintExportResults prints the results of exporting several topics, a row per file
+func PrintExportResults(results []*export.Result) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Topic", "File", "Format", "Events", "Size", "Verified"})
+	events := 0
+	for _, result := range results {
+		format := result.Format
+		if result.Compression != "" {
+			format += " (" + result.Compression + ")"
+		}
+		verified := ""
+		if result.Verified {
+			verified = "yes"
+		}
+		t.AppendRow(table.Row{result.Topic, result.File, format, result.Events, formatBytes(result.Bytes), verified})
+		events += result.Events
+	}
+	render(t)
+	fmt.Printf("Exported %d event(s) from %d topic(s)\n", events, len(results))
+}
+
+// PrintSpoolEntries prints spooled events waiting to be published, soonest due first
+func PrintSpoolEntries(entries []*spool.Entry) {
+	if len(entries) == 0 {
+		fmt.Println("Spool is empty")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Entry", "Publish At", "Server", "Topics", "Events"})
+	total := 0
+	for _, entry := range entries {
+		t.AppendRow(table.Row{entry.ID, entry.PublishAt.Format(time.RFC3339), entry.Server, strings.Join(entry.Topics(), ", "), len(entry.Events)})
+		total += len(entry.Events)
+	}
+	render(t)
+	fmt.Printf("%d spooled event(s)\n", total)
+}
+
+// PrintSpooled prints the spool entries a publish scheduled its events in
+func PrintSpooled(entries []*spool.Entry) {
+	for _, entry := range entries {
+		fmt.Printf("Spooled %d event(s) for %s as %s\n", len(entry.Events), entry.PublishAt.Format(time.RFC3339), entry.ID)
+	}
+	fmt.Println("The server can't schedule events; run 'es spool flush --follow' to publish them when due")
+}
+
+// PrintPublishFailures prints to stderr why each failed event of a published batch
+// failed, numbering the events from first+1 for a part of a larger input. Consecutive
+// events that failed with the same error, such as a whole request of a large batch, are
+// printed as a range.
+func PrintPublishFailures(events []client.EventPublishRequest, first int, failures []client.EventFailure) {
+	for i := 0; i < len(failures); i++ {
+		failure := failures[i]
+		last := i
+		for last+1 < len(failures) && failures[last+1].Index == failures[last].Index+1 && failures[last+1].Error == failure.Error {
+			last++
+		}
+		if last > i {
+			fmt.Fprintf(os.Stderr, "  events %d-%d: %s\n", first+failure.Index+1, first+failures[last].Index+1, failure.Error)
+			i = last
+			continue
+		}
+
+		event := fmt.Sprintf("event %d", first+failure.Index+1)
+		if failure.Index >= 0 && failure.Index < len(events) {
+			event += fmt.Sprintf(" (%s, %s)", events[failure.Index].Topic, events[failure.Index].Type)
+		}
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", event, failure.Error)
+	}
+}
+
+// PrintTrace prints the events of a trace as a tree, each event below the event that
+// caused it
+func PrintTrace(roots []*trace.Node) {
+	if len(roots) == 0 {
+		fmt.Println("No events found")
+		return
+	}
+	trace.Walk(roots, func(node *trace.Node, last []bool) {
+		var prefix strings.Builder
+		for i := 1; i < len(last); i++ {
+			switch {
+			case i < len(last)-1 && last[i]:
+				prefix.WriteString("   ")
+			case i < len(last)-1:
+				prefix.WriteString("│  ")
+			case last[i]:
+				prefix.WriteString("└─ ")
+			default:
+				prefix.WriteString("├─ ")
+			}
+		}
+		line := fmt.Sprintf("%s%s  %s  %s  (%s)", prefix.String(), node.Event.ID, node.Event.Type, node.Event.Timestamp, node.Topic)
+		if node.Orphan {
+			line += fmt.Sprintf("  caused by %s, not found", node.Event.CausationID)
+		}
+		fmt.Println(line)
+	})
+}
+
+// PrintAggregateHistory prints an aggregate's events in table format, followed by its
+// state when it was folded
+func PrintAggregateHistory(h *history.History) {
+	fmt.Printf("Aggregate %s = %s in %s: %d event(s)\n\n", h.IDField, h.AggregateID, h.Topic, len(h.Events))
+	if len(h.Events) > 0 {
+		PrintEventsList(h.Events)
+	}
+	if h.State == nil {
+		return
+	}
+	printHeading("State")
+	stateJSON, err := json.MarshalIndent(h.State, "", "  ")
+	if err != nil {
+		fmt.Printf("%v\n", h.State)
+		return
+	}
+	fmt.Println(string(stateJSON))
+}
+
+// PrintDrift prints the differences between two event stores in table format
+func PrintDrift(report *drift.Report) {
+	if len(report.Differences) == 0 {
+		fmt.Printf("No differences between %s and %s\n", report.From, report.To)
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Kind", "Name", "Difference"})
+	for _, diff := range report.Differences {
+		t.AppendRow(table.Row{diff.Kind, diff.Name, diff.Detail})
+	}
+	render(t)
+	fmt.Printf("%d difference(s) between %s and %s\n", len(report.Differences), report.From, report.To)
+}
+
+// PrintVerifyResult prints the outcome of verifying a topic's events in table format: a
+// summary, then the anomalies found
+func PrintVerifyResult(result *verify.Result) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendRow(table.Row{"Topic", result.Topic})
+	t.AppendRow(table.Row{"Sequence", result.Sequence})
+	t.AppendRow(table.Row{"Events Read", result.Events})
+	if result.FirstEventID != "" {
+		t.AppendRow(table.Row{"First Event", result.FirstEventID})
+		t.AppendRow(table.Row{"Last Event", result.LastEventID})
+	}
+	t.AppendRow(table.Row{"Missing Events", result.Missing})
+	render(t)
+
+	if result.Count() == 0 {
+		printText(fmt.Sprintf("No anomalies: sequences are contiguous and timestamps never go back in %s", result.Topic))
+		return
+	}
+	printHeading("Anomalies")
+	t = table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Kind", "Event ID", "Detail"})
+	for _, anomaly := range result.Anomalies {
+		t.AppendRow(table.Row{anomaly.Kind, anomaly.EventID, anomaly.Detail})
+	}
+	render(t)
+	if result.Omitted > 0 {
+		printText(fmt.Sprintf("... and %d more anomalies", result.Omitted))
+	}
+	printText(result.Summary())
+}
+
+// PrintSeedResult prints what applying or resetting fixtures did in table format
+func PrintSeedResult(result *seed.Result) {
+	if len(result.Actions) == 0 {
+		fmt.Printf("Nothing to do on %s\n", result.Server)
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Kind", "Name", "Action"})
+	for _, action := range result.Actions {
+		t.AppendRow(table.Row{action.Kind, action.Name, action.Action})
+	}
+	render(t)
+}
+
+// PrintOwnersReport prints who owns each topic in table format, flagging the topics
+// with no owner
+func PrintOwnersReport(report *owners.Report) {
+	if len(report.Topics) == 0 {
+		printText("No topics")
+		return
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(getTableStyle())
+	t.AppendHeader(table.Row{"Topic", "Team", "Owner", "Contact"})
+	for _, entry := range report.Topics {
+		if !entry.Owned {
+			t.AppendRow(table.Row{entry.Topic, "-", "(no owner)", entry.Contact})
+			continue
+		}
+		t.AppendRow(table.Row{entry.Topic, entry.Team, entry.Owner, entry.Contact})
+	}
+	render(t)
+	if report.Unowned > 0 && report.Unowned < len(report.Topics) {
+		printText(fmt.Sprintf("%d of %d topic(s) have no owner", report.Unowned, len(report.Topics)))
+	}
+}
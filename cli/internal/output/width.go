@@ -0,0 +1,147 @@
+package output
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"golang.org/x/term"
+)
+
+// wide turns off fitting tables to the terminal, for --wide
+var wide bool
+
+const (
+	// minColumnWidth is the narrowest a column is made to fit a table to the terminal
+	minColumnWidth = 10
+	// maxWrappedLines is the most lines a wrapped cell takes before it is cut short
+	maxWrappedLines = 3
+)
+
+// SetWide turns fitting tables to the terminal's width off, so cells are shown whole
+func SetWide(on bool) {
+	wide = on
+}
+
+// terminalWidth returns the width of the terminal output is shown on, or 0 when it isn't
+// going to one or is in markup. $COLUMNS overrides it.
+func terminalWidth() int {
+	if markup != "" {
+		return 0
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	files := []*os.File{os.Stdout}
+	if paging {
+		// The pager shows output on the terminal stdout was, which stderr still is
+		files = append(files, os.Stderr)
+	}
+	for _, file := range files {
+		if width, _, err := term.GetSize(int(file.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	return 0
+}
+
+// fitColumns narrows the columns of a table whose rows are wider than the terminal,
+// widest first, so that they fit: cells of the columns in wrap are wrapped onto up to
+// maxWrappedLines lines, and the others' lines are cut short, ending with "…". rows are
+// the table's cells, header first. Tables are left as they are with --wide, or when
+// output isn't to a terminal.
+func fitColumns(t table.Writer, rows [][]string, wrap ...int) {
+	width := terminalWidth()
+	if wide || width == 0 || len(rows) == 0 {
+		return
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				break
+			}
+			for _, line := range strings.Split(cell, "\n") {
+				widths[i] = max(widths[i], text.RuneWidthWithoutEscSequences(line))
+			}
+		}
+	}
+
+	style := t.Style()
+	padding := text.RuneWidthWithoutEscSequences(style.Box.PaddingLeft + style.Box.PaddingRight)
+	overhead := len(widths) * padding
+	if style.Options.SeparateColumns {
+		overhead += (len(widths) - 1) * text.RuneWidthWithoutEscSequences(style.Box.MiddleSeparator)
+	}
+	if style.Options.DrawBorder {
+		overhead += text.RuneWidthWithoutEscSequences(style.Box.Left + style.Box.Right)
+	}
+	available := width - overhead
+
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	if total <= available {
+		return
+	}
+
+	// Find the widest a column may be for the table to fit, taking the columns from the
+	// narrowest up: those that fit in an equal share of what's left keep their width
+	sorted := append([]int(nil), widths...)
+	sort.Ints(sorted)
+	limit, left := minColumnWidth, available
+	for i, w := range sorted {
+		share := left / (len(sorted) - i)
+		if w > share {
+			limit = max(share, minColumnWidth)
+			break
+		}
+		left -= w
+	}
+
+	wrapped := map[int]bool{}
+	for _, i := range wrap {
+		wrapped[i] = true
+	}
+	var configs []table.ColumnConfig
+	for i, w := range widths {
+		if w <= limit {
+			continue
+		}
+		enforcer := cutLines
+		if wrapped[i] {
+			enforcer = wrapLines
+		}
+		configs = append(configs, table.ColumnConfig{Number: i + 1, WidthMax: limit, WidthMaxEnforcer: enforcer})
+	}
+	t.SetColumnConfigs(configs)
+}
+
+// cutLines cuts each line of a cell longer than width short, ending it with "…"
+func cutLines(cell string, width int) string {
+	lines := strings.Split(cell, "\n")
+	for i, line := range lines {
+		if text.RuneWidthWithoutEscSequences(line) > width {
+			lines[i] = text.Trim(line, width-1) + "…"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLines wraps a cell onto lines of width, preferring to break between words, and cuts
+// it short after maxWrappedLines lines, ending with "…"
+func wrapLines(cell string, width int) string {
+	lines := strings.Split(text.WrapSoft(cell, width), "\n")
+	if len(lines) <= maxWrappedLines {
+		return strings.Join(lines, "\n")
+	}
+	lines = lines[:maxWrappedLines]
+	last := lines[maxWrappedLines-1]
+	lines[maxWrappedLines-1] = text.Trim(last, min(text.RuneWidthWithoutEscSequences(last), width-1)) + "…"
+	return strings.Join(lines, "\n")
+}
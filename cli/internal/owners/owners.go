@@ -0,0 +1,68 @@
+// Package owners reports who owns each topic, for governance in larger organisations:
+// which teams and people own which topics, and which topics have no owner at all
+package owners
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Entry is a topic and who owns it
+type Entry struct {
+	Topic   string `json:"topic"`
+	Owner   string `json:"owner,omitempty"`
+	Team    string `json:"team,omitempty"`
+	Contact string `json:"contact,omitempty"`
+	Owned   bool   `json:"owned"` // it has an owner or a team
+}
+
+// Report lists topics by owner: sorted by team, then owner, then name, with the topics
+// that have no owner last
+type Report struct {
+	Topics  []Entry `json:"topics"`
+	Unowned int     `json:"unowned"`
+}
+
+// Options select the topics of a report
+type Options struct {
+	Owner   string // only topics of this owner, ignoring case
+	Team    string // only topics of this team, ignoring case
+	Unowned bool   // only topics with no owner
+}
+
+// Build reports who owns the topics selected by the options
+func Build(topics []client.Topic, options Options) *Report {
+	report := &Report{Topics: []Entry{}}
+	for _, topic := range topics {
+		entry := Entry{Topic: topic.Name, Owned: topic.Owner.Owned()}
+		if topic.Owner != nil {
+			entry.Owner, entry.Team, entry.Contact = topic.Owner.Owner, topic.Owner.Team, topic.Owner.Contact
+		}
+		if options.Unowned && entry.Owned ||
+			options.Owner != "" && !strings.EqualFold(entry.Owner, options.Owner) ||
+			options.Team != "" && !strings.EqualFold(entry.Team, options.Team) {
+			continue
+		}
+		if !entry.Owned {
+			report.Unowned++
+		}
+		report.Topics = append(report.Topics, entry)
+	}
+
+	sort.SliceStable(report.Topics, func(i, j int) bool {
+		a, b := report.Topics[i], report.Topics[j]
+		if a.Owned != b.Owned {
+			return a.Owned
+		}
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Topic < b.Topic
+	})
+	return report
+}
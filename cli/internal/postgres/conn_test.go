@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+func TestParseDSN(t *testing.T) {
+	t.Setenv("PGPASSFILE", filepath.Join(t.TempDir(), "none"))
+	tests := []struct {
+		dsn      string
+		host     string
+		port     uint16
+		user     string
+		password string
+		database string
+	}{
+		{"postgres://es:secret@db:5433/reports?sslmode=disable", "db", 5433, "es", "secret", "reports"},
+		{"host=db port=5433 user=es password=secret dbname=reports sslmode=disable", "db", 5433, "es", "secret", "reports"},
+		{"postgres://es:p%40ss@db/reports?sslmode=disable", "db", 5432, "es", "p@ss", "reports"},
+	}
+	for _, tt := range tests {
+		cfg, err := ParseDSN(tt.dsn)
+		if err != nil {
+			t.Errorf("ParseDSN(%q) error = %v", tt.dsn, err)
+			continue
+		}
+		if cfg.Host != tt.host || cfg.Port != tt.port || cfg.User != tt.user || cfg.Password != tt.password || cfg.Database != tt.database {
+			t.Errorf("ParseDSN(%q) = %s:%d %s/%s %s", tt.dsn, cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database)
+		}
+		if cfg.TLSConfig != nil {
+			t.Errorf("ParseDSN(%q) uses TLS with sslmode=disable", tt.dsn)
+		}
+		if cfg.RuntimeParams["application_name"] != "es" {
+			t.Errorf("ParseDSN(%q) application_name = %q", tt.dsn, cfg.RuntimeParams["application_name"])
+		}
+	}
+}
+
+func TestParseDSNEnvironment(t *testing.T) {
+	t.Setenv("PGPASSFILE", filepath.Join(t.TempDir(), "none"))
+	t.Setenv("PGHOST", "env-db")
+	t.Setenv("PGUSER", "env-user")
+	t.Setenv("PGDATABASE", "env-reports")
+	t.Setenv("PGSSLMODE", "disable")
+	t.Setenv("PGAPPNAME", "reporting")
+
+	cfg, err := ParseDSN("dbname=reports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "env-db" || cfg.User != "env-user" || cfg.Database != "reports" {
+		t.Errorf("ParseDSN = %s %s %s, want the host and user from the environment", cfg.Host, cfg.User, cfg.Database)
+	}
+	if cfg.RuntimeParams["application_name"] != "reporting" {
+		t.Errorf("application_name = %q, want the one from PGAPPNAME", cfg.RuntimeParams["application_name"])
+	}
+}
+
+func TestParseDSNRootCert(t *testing.T) {
+	t.Setenv("PGPASSFILE", filepath.Join(t.TempDir(), "none"))
+	// A self-signed certificate, standing in for a private CA
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(ca, []byte(testCA), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ParseDSN("host=db user=es sslmode=verify-full sslrootcert=" + ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TLSConfig == nil || cfg.TLSConfig.RootCAs == nil || cfg.TLSConfig.ServerName != "db" || len(cfg.Fallbacks) != 0 {
+		t.Errorf("verify-full with sslrootcert: TLS config = %+v, fallbacks = %d", cfg.TLSConfig, len(cfg.Fallbacks))
+	}
+
+	if _, err := ParseDSN("host=db sslmode=verify-full sslrootcert=" + filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("ParseDSN with a missing sslrootcert succeeded")
+	}
+}
+
+func TestParseDSNErrors(t *testing.T) {
+	for _, dsn := range []string{"postgres://db:notaport/reports", "host=db sslmode=sometimes", "host"} {
+		if _, err := ParseDSN(dsn); err == nil || !strings.HasPrefix(err.Error(), "invalid DSN") {
+			t.Errorf("ParseDSN(%q) error = %v", dsn, err)
+		}
+	}
+}
+
+// TestQuery runs statements against a server that answers with two results, the last
+// with a NULL
+func TestQuery(t *testing.T) {
+	t.Setenv("PGPASSFILE", filepath.Join(t.TempDir(), "none"))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	queries := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		backend := pgproto3.NewBackend(conn, conn)
+		if _, err := backend.ReceiveStartupMessage(); err != nil {
+			return
+		}
+		backend.Send(&pgproto3.AuthenticationOk{})
+		backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		backend.Flush()
+		message, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		queries <- message.(*pgproto3.Query).String
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("CREATE TABLE")})
+		backend.Send(&pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{{Name: []byte("topic")}, {Name: []byte("last_event")}}})
+		backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte("orders"), []byte("orders-7")}})
+		backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte("payments"), nil}})
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 2")})
+		backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		backend.Flush()
+		backend.Receive() // Terminate
+	}()
+
+	cfg, err := ParseDSN("postgres://es@" + listener.Addr().String() + "/reports?sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := Connect(context.Background(), cfg, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sql := "CREATE TABLE t (x INT); SELECT topic, last_event FROM checkpoints"
+	rows, err := conn.Query(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-queries; got != sql {
+		t.Errorf("server received %q", got)
+	}
+	want := []map[string]string{{"topic": "orders", "last_event": "orders-7"}, {"topic": "payments"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Query = %v, want %v", rows, want)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got := Ident(`order "events"`); got != `"order ""events"""` {
+		t.Errorf("Ident = %s", got)
+	}
+	if got := Literal("O'Brien"); got != "'O''Brien'" {
+		t.Errorf("Literal = %s", got)
+	}
+}
+
+const testCA = `-----BEGIN CERTIFICATE-----
+MIIBgTCCASegAwIBAgIUdDWkwnzrEOedIqVWVbmShUohhiUwCgYIKoZIzj0EAwIw
+FTETMBEGA1UEAwwKZXMtdGVzdC1jYTAgFw0yNjEwMTYxMjQxMDRaGA8yMTI2MDky
+MjEyNDEwNFowFTETMBEGA1UEAwwKZXMtdGVzdC1jYTBZMBMGByqGSM49AgEGCCqG
+SM49AwEHA0IABNfys4G6PjKl3tNvxH1ZDS6JQ0dQIzzJlajB8wwUDLJXfP7CIk8p
+ZSH8yjyuBRdx8jUMiVFY+G2XEYMC7v/0+AajUzBRMB0GA1UdDgQWBBQjnngf5IWT
+auXmdZhpV6qA3pofaDAfBgNVHSMEGDAWgBQjnngf5IWTauXmdZhpV6qA3pofaDAP
+BgNVHRMBAf8EBTADAQH/MAoGCCqGSM49BAMCA0gAMEUCIQCQZn6I29/aO3Lk+8iq
+M2WVMChK880A4oRUZOjvMfg9aQIgetNBlL0+11NuWU5+6qfhgtQFb/G1MUvuhWN1
+JNCV5UQ=
+-----END CERTIFICATE-----
+`
@@ -0,0 +1,60 @@
+package projection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Checkpoint records the last event projected from each topic
+type Checkpoint struct {
+	Projection string            `json:"projection"`
+	Server     string            `json:"server"`
+	Topics     map[string]string `json:"topics"` // topic -> last event ID projected
+	Updated    time.Time         `json:"updated"`
+}
+
+// LoadCheckpoint reads a projection's checkpoint, returning an empty checkpoint if it
+// does not exist yet. A checkpoint made against another server is an error, as its
+// event IDs mean nothing there.
+func LoadCheckpoint(def *Definition, server string) (*Checkpoint, error) {
+	checkpoint := Checkpoint{Projection: def.Name, Server: server}
+	data, err := os.ReadFile(def.Checkpoint)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to parse checkpoint %s: %w", def.Checkpoint, err)
+		}
+		if checkpoint.Server != "" && checkpoint.Server != server {
+			return nil, fmt.Errorf("checkpoint %s is for %s, not %s (remove it, or use --reset, to project from the start)", def.Checkpoint, checkpoint.Server, server)
+		}
+		checkpoint.Server = server
+	}
+	if checkpoint.Topics == nil {
+		checkpoint.Topics = map[string]string{}
+	}
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint file, replacing it in one step so a crash never leaves a
+// partial file
+func (c *Checkpoint) Save(path string) error {
+	c.Updated = time.Now().UTC()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package projection builds read models from the event store: a projection reads the
+// events of some topics, keeps those matching its filter, turns each into a record with
+// a transform, and writes the records to a sink (stdout, a file, a SQLite table or a
+// webhook), checkpointing how far it has got so a later run carries on from there.
+package projection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+	"github.com/event-store/cli/internal/transform"
+	"go.yaml.in/yaml/v3"
+)
+
+// Definition is a projection's config file
+type Definition struct {
+	Name       string   `yaml:"name"`
+	Topics     []string `yaml:"topics"`
+	Filter     string   `yaml:"filter,omitempty"`    // e.g. 'type=order.placed AND payload.region=eu'
+	Transform  string   `yaml:"transform,omitempty"` // jq expression or Go template turning an event into a record
+	Sink       Sink     `yaml:"sink"`
+	Checkpoint string   `yaml:"checkpoint,omitempty"` // default: <file>.checkpoint.json next to the file
+}
+
+// Sink says where a projection's records go
+type Sink struct {
+	Type     string            `yaml:"type"`               // stdout, file, sqlite or webhook
+	Path     string            `yaml:"path,omitempty"`     // file: NDJSON file records are appended to
+	Database string            `yaml:"database,omitempty"` // sqlite: database file
+	Table    string            `yaml:"table,omitempty"`    // sqlite: table (default: the projection's name)
+	Key      string            `yaml:"key,omitempty"`      // sqlite: record field whose rows are replaced rather than added
+	URL      string            `yaml:"url,omitempty"`      // webhook: URL records are POSTed to
+	Headers  map[string]string `yaml:"headers,omitempty"`  // webhook: extra request headers
+}
+
+// Load reads and checks a projection's config file
+func Load(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read projection: %w", err)
+	}
+	var def Definition
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse projection %s: %w", path, err)
+	}
+	if def.Name == "" {
+		def.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if def.Checkpoint == "" {
+		def.Checkpoint = strings.TrimSuffix(path, filepath.Ext(path)) + ".checkpoint.json"
+	} else if !filepath.IsAbs(def.Checkpoint) {
+		def.Checkpoint = filepath.Join(filepath.Dir(path), def.Checkpoint)
+	}
+	if err := def.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	// Sink files are relative to the config file
+	if def.Sink.Path != "" && !filepath.IsAbs(def.Sink.Path) {
+		def.Sink.Path = filepath.Join(filepath.Dir(path), def.Sink.Path)
+	}
+	if def.Sink.Database != "" && !filepath.IsAbs(def.Sink.Database) {
+		def.Sink.Database = filepath.Join(filepath.Dir(path), def.Sink.Database)
+	}
+	return &def, nil
+}
+
+// Validate checks a definition
+func (d *Definition) Validate() error {
+	if len(d.Topics) == 0 {
+		return fmt.Errorf("a projection needs at least one topic")
+	}
+	if d.Filter != "" {
+		if _, err := filter.ParseAll(d.Filter); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+	switch d.Sink.Type {
+	case "stdout":
+	case "file":
+		if d.Sink.Path == "" {
+			return fmt.Errorf("a file sink needs a path")
+		}
+	case "sqlite":
+		if d.Sink.Database == "" {
+			return fmt.Errorf("a sqlite sink needs a database")
+		}
+	case "webhook":
+		if !strings.HasPrefix(d.Sink.URL, "http://") && !strings.HasPrefix(d.Sink.URL, "https://") {
+			return fmt.Errorf("a webhook sink needs an http:// or https:// url")
+		}
+	case "":
+		return fmt.Errorf("a projection needs a sink type: stdout, file, sqlite or webhook")
+	default:
+		return fmt.Errorf("unknown sink type '%s' (expected stdout, file, sqlite or webhook)", d.Sink.Type)
+	}
+	return nil
+}
+
+// Save writes a definition as a config file
+func (d *Definition) Save(path string) error {
+	data, err := yaml.Marshal(d)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write projection: %w", err)
+	}
+	return nil
+}
+
+// Stats counts what a run did
+type Stats struct {
+	Events  int // events read
+	Records int // records written
+}
+
+// Projection is a loaded projection, ready to run
+type Projection struct {
+	def       *Definition
+	filters   []*filter.Filter
+	transform transform.Transformer
+	sink      writer
+}
+
+// Open prepares a projection's filter, transform and sink. Close releases them.
+func Open(def *Definition) (*Projection, error) {
+	p := &Projection{def: def}
+	if def.Filter != "" {
+		filters, err := filter.ParseAll(def.Filter)
+		if err != nil {
+			return nil, err
+		}
+		p.filters = filters
+	}
+	if def.Transform != "" {
+		t, err := transform.New(def.Transform)
+		if err != nil {
+			return nil, err
+		}
+		p.transform = t
+	}
+	sink, err := openSink(def)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	p.sink = sink
+	return p, nil
+}
+
+// Run projects the events published since the checkpoint, topic by topic, until it has
+// caught up, saving the checkpoint after each page of events whose records were written
+func (p *Projection) Run(ctx context.Context, apiClient *client.Client, checkpoint *Checkpoint) (Stats, error) {
+	var stats Stats
+	for _, topic := range p.def.Topics {
+		err := apiClient.ScanEvents(topic, checkpoint.Topics[topic], func(events []client.Event) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			var records []map[string]interface{}
+			for _, event := range events {
+				stats.Events++
+				if !filter.MatchAll(p.filters, event) {
+					continue
+				}
+				record, keep, err := p.project(topic, event)
+				if err != nil {
+					return false, err
+				}
+				if keep {
+					records = append(records, record)
+				}
+			}
+			if len(records) > 0 {
+				if err := p.sink.Write(records); err != nil {
+					return false, err
+				}
+				stats.Records += len(records)
+			}
+			checkpoint.Topics[topic] = events[len(events)-1].ID
+			return true, checkpoint.Save(p.def.Checkpoint)
+		})
+		if err != nil {
+			return stats, fmt.Errorf("topic '%s': %w", topic, err)
+		}
+	}
+	return stats, nil
+}
+
+// project turns an event into a record: the event with its topic, or what the transform
+// makes of that
+func (p *Projection) project(topic string, event client.Event) (map[string]interface{}, bool, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, false, err
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, false, err
+	}
+	record["topic"] = topic
+	if p.transform == nil {
+		return record, true, nil
+	}
+	record, keep, err := p.transform.Transform(record)
+	if err != nil {
+		return nil, false, fmt.Errorf("event %s: %w", event.ID, err)
+	}
+	return record, keep, nil
+}
+
+// Close releases the projection's transform and sink
+func (p *Projection) Close() error {
+	if p.transform != nil {
+		p.transform.Close()
+	}
+	if p.sink != nil {
+		return p.sink.Close()
+	}
+	return nil
+}
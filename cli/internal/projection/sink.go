@@ -0,0 +1,213 @@
+package projection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/sqlite"
+)
+
+// writer writes records to a sink
+type writer interface {
+	Write(records []map[string]interface{}) error
+	Close() error
+}
+
+var unsafeTableChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+func openSink(def *Definition) (writer, error) {
+	switch def.Sink.Type {
+	case "stdout":
+		return &streamSink{out: bufio.NewWriter(os.Stdout)}, nil
+	case "file":
+		file, err := os.OpenFile(def.Sink.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open sink: %w", err)
+		}
+		return &streamSink{out: bufio.NewWriter(file), file: file}, nil
+	case "sqlite":
+		table := def.Sink.Table
+		if table == "" {
+			table = unsafeTableChars.ReplaceAllString(def.Name, "_")
+		}
+		return openSQLiteSink(def.Sink.Database, table, def.Sink.Key)
+	case "webhook":
+		return &webhookSink{url: def.Sink.URL, headers: def.Sink.Headers, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	}
+	return nil, fmt.Errorf("unknown sink type '%s'", def.Sink.Type)
+}
+
+// streamSink writes records as NDJSON to stdout or a file
+type streamSink struct {
+	out  *bufio.Writer
+	file *os.File
+}
+
+func (s *streamSink) Write(records []map[string]interface{}) error {
+	encoder := json.NewEncoder(s.out)
+	encoder.SetEscapeHTML(false)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	if err := s.out.Flush(); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	if s.file != nil {
+		return s.file.Sync()
+	}
+	return nil
+}
+
+func (s *streamSink) Close() error {
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+// sqliteSink writes records as rows of a table, a column per record field, adding
+// columns as new fields appear. With a key, a record replaces the row with the same key.
+type sqliteSink struct {
+	db      *sqlite.DB
+	table   string
+	key     string
+	columns map[string]bool
+}
+
+func openSQLiteSink(database, table, key string) (*sqliteSink, error) {
+	db, err := sqlite.Open(database)
+	if err != nil {
+		return nil, err
+	}
+	s := &sqliteSink{db: db, table: table, key: key, columns: map[string]bool{}}
+
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (_projected_at TEXT)", sqlite.Ident(table))
+	if key != "" {
+		create = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s PRIMARY KEY, _projected_at TEXT)", sqlite.Ident(table), sqlite.Ident(key))
+	}
+	if err := db.Exec(create); err != nil {
+		db.Close()
+		return nil, err
+	}
+	rows, err := db.Query(fmt.Sprintf("SELECT name FROM pragma_table_info(%s)", sqlite.Literal(table)))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	for _, row := range rows {
+		if name, ok := row["name"].(string); ok {
+			s.columns[name] = true
+		}
+	}
+	if key != "" && !s.columns[key] {
+		db.Close()
+		return nil, fmt.Errorf("table %s has no key column %s", table, key)
+	}
+	return s, nil
+}
+
+func (s *sqliteSink) Write(records []map[string]interface{}) error {
+	var sql strings.Builder
+	sql.WriteString("BEGIN;\n")
+	added := map[string]bool{}
+	now := sqlite.Literal(time.Now().UTC().Format(time.RFC3339Nano))
+	for _, record := range records {
+		if s.key != "" {
+			if value, ok := record[s.key]; !ok || value == nil {
+				return fmt.Errorf("record has no '%s' key field: %s", s.key, compact(record))
+			}
+		}
+		fields := make([]string, 0, len(record))
+		for field := range record {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		columns := []string{sqlite.Ident("_projected_at")}
+		values := []string{now}
+		var updates []string
+		for _, field := range fields {
+			if !s.columns[field] && !added[field] {
+				fmt.Fprintf(&sql, "ALTER TABLE %s ADD COLUMN %s;\n", sqlite.Ident(s.table), sqlite.Ident(field))
+				added[field] = true
+			}
+			columns = append(columns, sqlite.Ident(field))
+			values = append(values, sqlite.Literal(record[field]))
+			if field != s.key {
+				updates = append(updates, fmt.Sprintf("%s = excluded.%s", sqlite.Ident(field), sqlite.Ident(field)))
+			}
+		}
+		fmt.Fprintf(&sql, "INSERT INTO %s (%s) VALUES (%s)", sqlite.Ident(s.table), strings.Join(columns, ", "), strings.Join(values, ", "))
+		if s.key != "" {
+			updates = append(updates, `"_projected_at" = excluded."_projected_at"`)
+			fmt.Fprintf(&sql, " ON CONFLICT(%s) DO UPDATE SET %s", sqlite.Ident(s.key), strings.Join(updates, ", "))
+		}
+		sql.WriteString(";\n")
+	}
+	sql.WriteString("COMMIT;")
+	if err := s.db.Exec(sql.String()); err != nil {
+		return err
+	}
+	for field := range added {
+		s.columns[field] = true
+	}
+	return nil
+}
+
+func (s *sqliteSink) Close() error {
+	return s.db.Close()
+}
+
+// webhookSink POSTs each record as JSON to a URL
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (s *webhookSink) Write(records []map[string]interface{}) error {
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for name, value := range s.headers {
+			req.Header.Set(name, os.ExpandEnv(value))
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("webhook failed: %w", err)
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	return nil
+}
+
+func compact(record map[string]interface{}) string {
+	data, _ := json.Marshal(record)
+	return string(data)
+}
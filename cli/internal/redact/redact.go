@@ -0,0 +1,122 @@
+// Package redact erases personal data from a topic's events, such as for a GDPR erasure
+// request: the events matching filters are found and reported, then the event store
+// removes or masks their fields, and what was done is recorded for audit.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// BatchSize is the most events redacted per request
+const BatchSize = 500
+
+// Match is an event with fields to redact
+type Match struct {
+	EventID   string   `json:"eventId"`
+	Type      string   `json:"type"`
+	Timestamp string   `json:"timestamp"`
+	Fields    []string `json:"fields"` // of the fields to redact, those the event has
+}
+
+// Record is what a redaction did, or with DryRun would do, kept for audit
+type Record struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user,omitempty"`
+	Server   string    `json:"server"`
+	Topic    string    `json:"topic"`
+	Filter   string    `json:"filter"`
+	Fields   []string  `json:"fields"`
+	Mask     string    `json:"mask,omitempty"` // empty when the fields are removed
+	DryRun   bool      `json:"dryRun"`
+	Redacted int       `json:"redacted"`
+	Events   []Match   `json:"events"`
+}
+
+// CheckFields checks the fields to redact: dotted paths under payload or metadata
+func CheckFields(fields []string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to redact")
+	}
+	for _, field := range fields {
+		path, ok := strings.CutPrefix(field, "payload.")
+		if !ok {
+			path, ok = strings.CutPrefix(field, "metadata.")
+		}
+		if !ok || path == "" {
+			return fmt.Errorf("invalid field '%s' (expected 'payload.<path>' or 'metadata.<path>')", field)
+		}
+	}
+	return nil
+}
+
+// Find returns the events of a topic matching the filters that have any of the fields
+func Find(apiClient *client.Client, topic string, filters []*filter.Filter, fields []string) ([]Match, error) {
+	matches := []Match{}
+	err := apiClient.ScanEvents(topic, "", func(events []client.Event) (bool, error) {
+		for _, event := range events {
+			if !filter.MatchAll(filters, event) {
+				continue
+			}
+			var present []string
+			for _, field := range fields {
+				if _, ok := filter.Value(event, field); ok {
+					present = append(present, field)
+				}
+			}
+			if len(present) > 0 {
+				matches = append(matches, Match{EventID: event.ID, Type: event.Type, Timestamp: event.Timestamp, Fields: present})
+			}
+		}
+		return true, nil
+	})
+	return matches, err
+}
+
+// Apply has the event store redact the fields of the matched events, in batches,
+// returning the number of events redacted, up to a failed batch. Checkpoint, if not nil,
+// is called after each batch with the number redacted so far and the batch's last event.
+func Apply(apiClient *client.Client, topic string, matches []Match, fields []string, mask string, checkpoint func(redacted int, lastEventID string) error) (int, error) {
+	redacted := 0
+	for start := 0; start < len(matches); start += BatchSize {
+		batch := matches[start:min(start+BatchSize, len(matches))]
+		ids := make([]string, len(batch))
+		for i, match := range batch {
+			ids[i] = match.EventID
+		}
+		result, err := apiClient.RedactEvents(topic, client.RedactRequest{EventIDs: ids, Fields: fields, Mask: mask})
+		if err != nil {
+			return redacted, err
+		}
+		redacted += result.Redacted
+		if checkpoint != nil {
+			if err := checkpoint(redacted, batch[len(batch)-1].EventID); err != nil {
+				return redacted, err
+			}
+		}
+	}
+	return redacted, nil
+}
+
+// AppendAudit appends a record to an audit file as a line of JSON
+func AppendAudit(path string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit file: %w", err)
+	}
+	return file.Close()
+}
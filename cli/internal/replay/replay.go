@@ -0,0 +1,108 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/event-store/cli/internal/bench"
+	"github.com/event-store/cli/internal/client"
+)
+
+// deliveryTimeout matches how long the event store waits for a consumer's callback
+const deliveryTimeout = 30 * time.Second
+
+// Options configures a replay of events to a consumer's callback
+type Options struct {
+	ConsumerID string
+	Callback   string
+	Events     []client.Event
+	BatchSize  int     // events per delivery
+	Rate       float64 // events per second, 0 for unlimited
+}
+
+// Result summarises a replay
+type Result struct {
+	ConsumerID   string  `json:"consumerId"`
+	Topic        string  `json:"topic"`
+	FirstEventID string  `json:"firstEventId,omitempty"`
+	LastEventID  string  `json:"lastEventId,omitempty"` // last event delivered
+	Delivered    int     `json:"delivered"`
+	Deliveries   int     `json:"deliveries"`
+	Seconds      float64 `json:"durationSeconds"`
+	ServerSide   bool    `json:"serverSide"`
+}
+
+// Deliver POSTs the events to the callback in batches, in the same format the event
+// store uses, calling progress after each successful delivery. It stops at the first
+// failed delivery or when ctx is done; the result records how far it got.
+func Deliver(ctx context.Context, opts Options, progress func(delivered int)) (*Result, error) {
+	result := &Result{ConsumerID: opts.ConsumerID}
+	if len(opts.Events) > 0 {
+		result.FirstEventID = opts.Events[0].ID
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	batches := (len(opts.Events) + batchSize - 1) / batchSize
+
+	httpClient := &http.Client{Timeout: deliveryTimeout}
+	started := time.Now()
+	defer func() { result.Seconds = time.Since(started).Seconds() }()
+
+	scheduleCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan int)
+	go bench.Schedule(scheduleCtx, opts.Rate/float64(batchSize), batches, jobs)
+
+	for batch := range jobs {
+		start := batch * batchSize
+		end := start + batchSize
+		if end > len(opts.Events) {
+			end = len(opts.Events)
+		}
+		events := opts.Events[start:end]
+
+		if err := post(ctx, httpClient, opts.Callback, client.DeliveryPayload{ConsumerID: opts.ConsumerID, Events: events}); err != nil {
+			return result, fmt.Errorf("delivery of %s failed: %w", events[0].ID, err)
+		}
+		result.Delivered += len(events)
+		result.Deliveries++
+		result.LastEventID = events[len(events)-1].ID
+		if progress != nil {
+			progress(len(events))
+		}
+	}
+	return result, ctx.Err()
+}
+
+func post(ctx context.Context, httpClient *http.Client, callback string, payload client.DeliveryPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callback, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid callback URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
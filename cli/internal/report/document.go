@@ -0,0 +1,152 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// Document is a report laid out as a title, facts about it and sections of paragraphs
+// and tables, to render in a format
+type Document struct {
+	Title    string
+	Facts    []Fact
+	Sections []Section
+}
+
+// Fact is a named value about a document, such as its server
+type Fact struct {
+	Name  string
+	Value string
+}
+
+// Section is a headed part of a document: its paragraphs, then its tables
+type Section struct {
+	Title      string
+	Paragraphs []string
+	Tables     []Table
+}
+
+// Table is a table of a section, with an optional title
+type Table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// Formats are the formats documents render in
+var Formats = []string{"md", "html", "pdf"}
+
+// numberPattern matches cells that are numbers, which are aligned right
+var numberPattern = regexp.MustCompile(`^[+-]?[0-9][0-9.]*%?$`)
+
+// Render writes a document in a format: "md", "html" or "pdf"
+func (d *Document) Render(w io.Writer, format string) error {
+	switch format {
+	case "md":
+		return d.markdown(w)
+	case "html":
+		return d.html(w)
+	case "pdf":
+		return d.pdf(w)
+	}
+	return fmt.Errorf("unknown report format '%s' (expected %s)", format, strings.Join(Formats, ", "))
+}
+
+func (d *Document) markdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", d.Title)
+	for _, fact := range d.Facts {
+		fmt.Fprintf(&b, "- **%s:** %s\n", fact.Name, fact.Value)
+	}
+	for _, section := range d.Sections {
+		fmt.Fprintf(&b, "\n## %s\n", section.Title)
+		for _, paragraph := range section.Paragraphs {
+			fmt.Fprintf(&b, "\n%s\n", paragraph)
+		}
+		for _, t := range section.Tables {
+			if t.Title != "" {
+				fmt.Fprintf(&b, "\n### %s\n", t.Title)
+			}
+			fmt.Fprintf(&b, "\n%s\n", t.writer().RenderMarkdown())
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// htmlStyle is the stylesheet of HTML reports
+const htmlStyle = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 0.5em 0 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; }
+th { background: #f4f4f4; text-align: left; }
+dt { font-weight: bold; float: left; clear: left; width: 7em; }
+dd { margin-left: 7em; }`
+
+func (d *Document) html(w io.Writer) error {
+	var b strings.Builder
+	title := html.EscapeString(d.Title)
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n", title, htmlStyle)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<dl>\n", title)
+	for _, fact := range d.Facts {
+		fmt.Fprintf(&b, "  <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(fact.Name), html.EscapeString(fact.Value))
+	}
+	b.WriteString("</dl>\n")
+	for _, section := range d.Sections {
+		fmt.Fprintf(&b, "<h2>%s</h2>\n", html.EscapeString(section.Title))
+		for _, paragraph := range section.Paragraphs {
+			fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(paragraph))
+		}
+		for _, t := range section.Tables {
+			if t.Title != "" {
+				fmt.Fprintf(&b, "<h3>%s</h3>\n", html.EscapeString(t.Title))
+			}
+			fmt.Fprintf(&b, "%s\n", t.writer().RenderHTML())
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writer returns a table writer of the table, its columns of numbers aligned right and
+// its header as it is, as it may name topics
+func (t Table) writer() table.Writer {
+	style := table.StyleDefault
+	style.Format.Header = text.FormatDefault
+	tw := table.NewWriter()
+	tw.SetStyle(style)
+	header := make(table.Row, len(t.Header))
+	for i, cell := range t.Header {
+		header[i] = cell
+	}
+	tw.AppendHeader(header)
+	for _, row := range t.Rows {
+		cells := make(table.Row, len(row))
+		for i, cell := range row {
+			cells[i] = cell
+		}
+		tw.AppendRow(cells)
+	}
+
+	var configs []table.ColumnConfig
+	for i := range t.Header {
+		numeric := len(t.Rows) > 0
+		for _, row := range t.Rows {
+			if i < len(row) && row[i] != "" && !numberPattern.MatchString(row[i]) {
+				numeric = false
+				break
+			}
+		}
+		if numeric {
+			configs = append(configs, table.ColumnConfig{Number: i + 1, Align: text.AlignRight})
+		}
+	}
+	tw.SetColumnConfigs(configs)
+	return tw
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/event-store/cli/internal/config"
+)
+
+// Retention is how long a snapshot is kept for the trends of later reports
+const Retention = 90 * 24 * time.Hour
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Snapshot is the health of an event store and its consumers' lag when a report was made
+type Snapshot struct {
+	Time        time.Time                   `json:"time"`
+	Health      string                      `json:"health"`
+	Consumers   int                         `json:"consumers"`
+	Dispatchers []string                    `json:"runningDispatchers"`
+	Lags        map[string]map[string]int64 `json:"lags"` // by consumer ID, then topic
+}
+
+// History is the snapshots of the reports made of a server, oldest first
+type History struct {
+	Server    string     `json:"server"`
+	Snapshots []Snapshot `json:"snapshots"`
+
+	path string
+}
+
+// OpenHistory reads the snapshots of a server's reports from ~/.es/reports/<server
+// host>.json, dropping those older than Retention
+func OpenHistory(server string) (*History, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	h := &History{
+		Server: server,
+		path:   filepath.Join(dir, "reports", unsafePathChars.ReplaceAllString(host, "_")+".json"),
+	}
+
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report history: %w", err)
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("invalid report history %s: %w", h.path, err)
+	}
+	cutoff := time.Now().Add(-Retention)
+	kept := h.Snapshots[:0]
+	for _, snapshot := range h.Snapshots {
+		if !snapshot.Time.Before(cutoff) {
+			kept = append(kept, snapshot)
+		}
+	}
+	h.Snapshots = kept
+	sort.SliceStable(h.Snapshots, func(i, j int) bool { return h.Snapshots[i].Time.Before(h.Snapshots[j].Time) })
+	return h, nil
+}
+
+// Between returns the snapshots taken at or after since and, unless until is zero,
+// before until
+func (h *History) Between(since, until time.Time) []Snapshot {
+	var snapshots []Snapshot
+	for _, snapshot := range h.Snapshots {
+		if snapshot.Time.Before(since) || (!until.IsZero() && !snapshot.Time.Before(until)) {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// Add records a snapshot
+func (h *History) Add(snapshot Snapshot) {
+	h.Snapshots = append(h.Snapshots, snapshot)
+}
+
+// Save writes the history, replacing the file in one step so a crash never leaves a
+// partial file
+func (h *History) Save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return fmt.Errorf("failed to write report history: %w", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write report history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write report history: %w", err)
+	}
+	return nil
+}
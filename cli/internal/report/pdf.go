@@ -0,0 +1,169 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDF page layout, in points: A4 pages with the text in a fixed-width font, so tables
+// line up as they do in a terminal
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	pageMargin = 40
+	// charWidth is the width of a character of the Courier fonts, as a fraction of the
+	// font size
+	charWidth = 0.6
+)
+
+// pdfLine is a line of text of a PDF document, in a font ("F1" for Courier or "F2" for
+// Courier-Bold) and size, with space above it
+type pdfLine struct {
+	font  string
+	size  float64
+	space float64
+	text  string
+}
+
+// pdf writes the document as a PDF, laid out as text: tables are drawn as they are in
+// the terminal, and lines too long for the page are wrapped
+func (d *Document) pdf(w io.Writer) error {
+	var lines []pdfLine
+	add := func(font string, size, space float64, s string) {
+		width := int((pageWidth - 2*pageMargin) / (charWidth * size))
+		for i, line := range wrapText(s, width) {
+			if i > 0 {
+				space = 0
+			}
+			lines = append(lines, pdfLine{font: font, size: size, space: space, text: line})
+		}
+	}
+
+	add("F2", 16, 0, d.Title)
+	for i, fact := range d.Facts {
+		space := 0.0
+		if i == 0 {
+			space = 8
+		}
+		add("F1", 9, space, fmt.Sprintf("%-10s %s", fact.Name+":", fact.Value))
+	}
+	for _, section := range d.Sections {
+		add("F2", 12, 16, section.Title)
+		for _, paragraph := range section.Paragraphs {
+			add("F1", 9, 6, paragraph)
+		}
+		for _, t := range section.Tables {
+			space := 8.0
+			if t.Title != "" {
+				add("F2", 10, 8, t.Title)
+				space = 4
+			}
+			for _, line := range strings.Split(t.writer().Render(), "\n") {
+				add("F1", 8, space, line)
+				space = 0
+			}
+		}
+	}
+	return writePDF(w, d.Title, lines)
+}
+
+// wrapText breaks text into lines of at most width characters, between words where it
+// can
+func wrapText(s string, width int) []string {
+	var lines []string
+	for {
+		runes := []rune(s)
+		if len(runes) <= width {
+			return append(lines, s)
+		}
+		cut := width
+		if space := strings.LastIndex(string(runes[:width]), " "); space > 0 && !strings.HasPrefix(s, "+") && !strings.HasPrefix(s, "|") {
+			cut = len([]rune(s[:space]))
+		}
+		lines = append(lines, string(runes[:cut]))
+		s = strings.TrimLeft(string(runes[cut:]), " ")
+	}
+}
+
+// writePDF writes lines of text as a PDF document, starting new pages as they fill
+func writePDF(w io.Writer, title string, lines []pdfLine) error {
+	var pages []*bytes.Buffer
+	var page *bytes.Buffer
+	y := 0.0
+	for _, line := range lines {
+		height := line.space + line.size*1.25
+		if page == nil || y-height < pageMargin {
+			page = &bytes.Buffer{}
+			pages = append(pages, page)
+			y = pageHeight - pageMargin
+			height = line.size * 1.25
+		}
+		y -= height
+		fmt.Fprintf(page, "BT /%s %g Tf %d %.2f Td (%s) Tj ET\n", line.font, line.size, pageMargin, y, pdfString(line.text))
+	}
+
+	// Objects 1 to 5 are the catalog, the page tree, the fonts and the document's
+	// information; each page is then a page object followed by its content stream
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Title (%s) /Producer (es) >>", pdfString(title)),
+	)
+	for i, content := range pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 7+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pdfString encodes text for a PDF string in WinAnsiEncoding, escaping what has to be
+// and replacing characters the encoding doesn't have with '?'
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r == '…':
+			b.WriteString("\\205")
+		case r == '–':
+			b.WriteString("\\226")
+		case r == '—':
+			b.WriteString("\\227")
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,151 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// goldenPDF is writePDF's output for a page with a bold title and a line of text, both
+// with characters that have to be escaped
+const goldenPDF = "%PDF-1.4\n" +
+	"1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+	"2 0 obj\n<< /Type /Pages /Kids [6 0 R] /Count 1 >>\nendobj\n" +
+	"3 0 obj\n<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>\nendobj\n" +
+	"4 0 obj\n<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>\nendobj\n" +
+	"5 0 obj\n<< /Title (Lag \\(a\\) \\\\ b) /Producer (es) >>\nendobj\n" +
+	"6 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents 7 0 R >>\nendobj\n" +
+	"7 0 obj\n<< /Length 101 >>\nstream\n" +
+	"BT /F2 16 Tf 40 782.00 Td (Lag \\(a\\) \\\\ b) Tj ET\n" +
+	"BT /F1 9 Tf 40 762.75 Td (caf\\351 \\226 5\\205) Tj ET\n" +
+	"endstream\nendobj\n" +
+	"xref\n0 8\n" +
+	"0000000000 65535 f \n" +
+	"0000000009 00000 n \n" +
+	"0000000058 00000 n \n" +
+	"0000000115 00000 n \n" +
+	"0000000210 00000 n \n" +
+	"0000000310 00000 n \n" +
+	"0000000370 00000 n \n" +
+	"0000000506 00000 n \n" +
+	"trailer\n<< /Size 8 /Root 1 0 R /Info 5 0 R >>\n" +
+	"startxref\n657\n%%EOF\n"
+
+func TestWritePDF(t *testing.T) {
+	var b bytes.Buffer
+	err := writePDF(&b, `Lag (a) \ b`, []pdfLine{
+		{font: "F2", size: 16, text: `Lag (a) \ b`},
+		{font: "F1", size: 9, space: 8, text: "café – 5…"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != goldenPDF {
+		t.Errorf("writePDF wrote\n%s\nwant\n%s", b.String(), goldenPDF)
+	}
+	checkPDF(t, b.Bytes())
+}
+
+// TestPDFPages checks that a document too long for a page is laid out over several, and
+// that the cross-reference table still finds every object
+func TestPDFPages(t *testing.T) {
+	table := Table{Title: "Topics (all)", Header: []string{"Topic", "Events"}}
+	for i := 0; i < 150; i++ {
+		table.Rows = append(table.Rows, []string{fmt.Sprintf("topic-%03d", i), strconv.Itoa(i * 10)})
+	}
+	d := &Document{
+		Title:    "Weekly report",
+		Facts:    []Fact{{Name: "Server", Value: `http://localhost:8000\`}},
+		Sections: []Section{{Title: "Topics", Paragraphs: []string{strings.Repeat("A long paragraph (wrapped) ", 20)}, Tables: []Table{table}}},
+	}
+	var b bytes.Buffer
+	if err := d.Render(&b, "pdf"); err != nil {
+		t.Fatal(err)
+	}
+	objects := checkPDF(t, b.Bytes())
+
+	pages := regexp.MustCompile(`/Type /Page /Parent`).FindAll(b.Bytes(), -1)
+	if len(pages) < 3 || len(objects) != 5+2*len(pages) {
+		t.Fatalf("%d pages in %d objects, want at least 3 pages each of two objects", len(pages), len(objects))
+	}
+	kids := make([]string, len(pages))
+	for i := range kids {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	if tree := fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)); !strings.Contains(objects[1], tree) {
+		t.Errorf("page tree = %s, want %s", objects[1], tree)
+	}
+	for _, want := range []string{`(Weekly report)`, `(Server:    http://localhost:8000\\)`, `(A long paragraph \(wrapped\)`, `(Topics \(all\))`, `topic-149`} {
+		if !bytes.Contains(b.Bytes(), []byte(want)) {
+			t.Errorf("PDF has no %s", want)
+		}
+	}
+}
+
+// checkPDF checks a PDF's structure: that startxref gives the cross-reference table, its
+// entries the offsets of the objects in order, the trailer their count and stream
+// dictionaries their lengths. It returns the objects' contents.
+func checkPDF(t *testing.T, data []byte) []string {
+	t.Helper()
+	trailer := regexp.MustCompile(`trailer\n<< /Size (\d+) /Root 1 0 R /Info 5 0 R >>\nstartxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if trailer == nil {
+		t.Fatalf("PDF ends %q, want a trailer", data[max(0, len(data)-100):])
+	}
+	size, _ := strconv.Atoi(string(trailer[1]))
+	xref, _ := strconv.Atoi(string(trailer[2]))
+	if xref >= len(data) || !bytes.HasPrefix(data[xref:], []byte(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", size))) {
+		t.Fatalf("startxref %d doesn't give a cross-reference table of %d entries", xref, size)
+	}
+
+	entries := data[xref+len(fmt.Sprintf("xref\n0 %d\n", size))+20:]
+	objects := make([]string, size-1)
+	for i := range objects {
+		entry := string(entries[20*i : 20*(i+1)])
+		if !strings.HasSuffix(entry, " 00000 n \n") {
+			t.Fatalf("cross-reference entry %d = %q", i+1, entry)
+		}
+		offset, _ := strconv.Atoi(entry[:10])
+		header := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(data[offset:], []byte(header)) {
+			t.Fatalf("object %d is at %d, but %q is there", i+1, offset, data[offset:min(offset+20, len(data))])
+		}
+		end := bytes.Index(data[offset:], []byte("\nendobj\n"))
+		objects[i] = string(data[offset+len(header) : offset+end])
+	}
+
+	stream := regexp.MustCompile(`(?s)^<< /Length (\d+) >>\nstream\n(.*)endstream$`)
+	for i, object := range objects {
+		if m := stream.FindStringSubmatch(object); m != nil {
+			if length, _ := strconv.Atoi(m[1]); length != len(m[2]) {
+				t.Errorf("object %d has /Length %d, but its stream is %d bytes", i+1, length, len(m[2]))
+			}
+		}
+	}
+	return objects
+}
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"plain text 123", "plain text 123"},
+		{"(parenthesised)", `\(parenthesised\)`},
+		{"unbalanced ) (", `unbalanced \) \(`},
+		{`back\slash\`, `back\\slash\\`},
+		{`\(`, `\\\(`},
+		{"café ñ ÿ", `caf\351 \361 \377`},
+		{" ", `\240`},
+		{"1…2–3—4", `1\2052\2263\2274`},
+		{"tab\tnewline\n", "tab?newline?"},
+		{"\x7f€日本", "????"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := pdfString(tt.s); got != tt.want {
+			t.Errorf("pdfString(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+// Package report builds operational reports of an event store for a period: its topics'
+// event volumes and types, its consumers' lag and its health, with trends from the
+// snapshots recorded by earlier reports. Reports render as Markdown, HTML or PDF.
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Report is an operational report of an event store
+type Report struct {
+	Server    string
+	Generated time.Time
+	Since     time.Time
+	Until     time.Time            // zero when the period runs to Generated
+	Topics    []*client.TopicStats // the events of each topic in the period
+	Consumers []ConsumerLag        // most lagging first
+	Health    []Snapshot           // at each earlier report of the period and now, oldest first
+}
+
+// ConsumerLag is a consumer's lag in the report's topics over the period
+type ConsumerLag struct {
+	ID     string
+	Topics []string
+	Lags   []LagPoint // oldest first, the last being now
+}
+
+// LagPoint is a consumer's lag at a snapshot
+type LagPoint struct {
+	Time time.Time
+	Lag  int64
+}
+
+// New creates a report of the events of topics in a period, with the consumers' lag in
+// those topics and health taken from the snapshots of earlier reports in the period and
+// current, the snapshot made now
+func New(server string, since, until time.Time, topics []*client.TopicStats, snapshots []Snapshot, current Snapshot) *Report {
+	r := &Report{
+		Server:    server,
+		Generated: current.Time,
+		Since:     since,
+		Until:     until,
+		Topics:    topics,
+		Health:    append(append([]Snapshot(nil), snapshots...), current),
+	}
+
+	reported := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		reported[topic.Topic] = true
+	}
+	consumers := map[string]*ConsumerLag{}
+	var ids []string
+	for _, snapshot := range r.Health {
+		for id, lags := range snapshot.Lags {
+			var lag int64
+			var names []string
+			for topic, topicLag := range lags {
+				if reported[topic] {
+					lag += topicLag
+					names = append(names, topic)
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+			consumer, ok := consumers[id]
+			if !ok {
+				consumer = &ConsumerLag{ID: id}
+				consumers[id] = consumer
+				ids = append(ids, id)
+			}
+			sort.Strings(names)
+			consumer.Topics = names
+			consumer.Lags = append(consumer.Lags, LagPoint{Time: snapshot.Time, Lag: lag})
+		}
+	}
+	for _, id := range ids {
+		// Consumers deleted since an earlier report aren't reported
+		consumer := consumers[id]
+		if _, ok := current.Lags[id]; ok {
+			r.Consumers = append(r.Consumers, *consumer)
+		}
+	}
+	sort.SliceStable(r.Consumers, func(i, j int) bool {
+		a, b := r.Consumers[i], r.Consumers[j]
+		if a.Lag() != b.Lag() {
+			return a.Lag() > b.Lag()
+		}
+		return a.ID < b.ID
+	})
+	return r
+}
+
+// Lag returns the consumer's lag now
+func (c ConsumerLag) Lag() int64 {
+	if len(c.Lags) == 0 {
+		return 0
+	}
+	return c.Lags[len(c.Lags)-1].Lag
+}
+
+// end returns when the report's period ends
+func (r *Report) end() time.Time {
+	if r.Until.IsZero() {
+		return r.Generated
+	}
+	return r.Until
+}
+
+// days returns the UTC days of the report's period, oldest first
+func (r *Report) days() []string {
+	var days []string
+	last := r.end().Add(-time.Nanosecond).UTC().Format("2006-01-02")
+	for t := r.Since.UTC().Truncate(24 * time.Hour); ; t = t.AddDate(0, 0, 1) {
+		day := t.Format("2006-01-02")
+		days = append(days, day)
+		if day >= last {
+			return days
+		}
+	}
+}
+
+// Document lays the report out for rendering
+func (r *Report) Document() *Document {
+	doc := &Document{
+		Title: "Event Store Report",
+		Facts: []Fact{
+			{"Server", r.Server},
+			{"Period", fmt.Sprintf("%s to %s", formatTime(r.Since), formatTime(r.end()))},
+			{"Generated", formatTime(r.Generated)},
+		},
+	}
+	doc.Sections = append(doc.Sections, r.summary(), r.volumes(), r.types(), r.lag(), r.health())
+	return doc
+}
+
+func (r *Report) summary() Section {
+	days := float64(len(r.days()))
+	if span := r.end().Sub(r.Since).Hours() / 24; span > 0 && span < days {
+		days = span
+	}
+
+	volumes := Table{Header: []string{"Topic", "Events", "Per Day", "Busiest Day", "Event Types"}}
+	var total int64
+	for _, topic := range r.Topics {
+		busiest := "-"
+		var most int64
+		for _, day := range topic.PerDay {
+			if day.Events > most {
+				busiest, most = fmt.Sprintf("%s (%d)", day.Date, day.Events), day.Events
+			}
+		}
+		volumes.Rows = append(volumes.Rows, []string{
+			topic.Topic,
+			strconv.FormatInt(topic.Events, 10),
+			formatRate(float64(topic.Events) / days),
+			busiest,
+			strconv.Itoa(len(topic.PerType)),
+		})
+		total += topic.Events
+	}
+	if len(r.Topics) > 1 {
+		volumes.Rows = append(volumes.Rows, []string{"Total", strconv.FormatInt(total, 10), formatRate(float64(total) / days), "", ""})
+	}
+
+	var lag int64
+	for _, consumer := range r.Consumers {
+		lag += consumer.Lag()
+	}
+	current := r.Health[len(r.Health)-1]
+	return Section{
+		Title: "Summary",
+		Paragraphs: []string{fmt.Sprintf("%d %s published to %d %s. %d %s with %d %s not yet delivered. Health is %s.",
+			total, plural(total, "event was", "events were"), len(r.Topics), plural(int64(len(r.Topics)), "topic", "topics"),
+			len(r.Consumers), plural(int64(len(r.Consumers)), "consumer", "consumers"), lag, plural(lag, "event", "events"),
+			current.Health)},
+		Tables: []Table{volumes},
+	}
+}
+
+func (r *Report) volumes() Section {
+	perDay := Table{Header: []string{"Date"}}
+	counts := make([]map[string]int64, len(r.Topics))
+	for i, topic := range r.Topics {
+		perDay.Header = append(perDay.Header, topic.Topic)
+		counts[i] = make(map[string]int64, len(topic.PerDay))
+		for _, day := range topic.PerDay {
+			counts[i][day.Date] = day.Events
+		}
+	}
+	if len(r.Topics) > 1 {
+		perDay.Header = append(perDay.Header, "Total")
+	}
+	for _, day := range r.days() {
+		row := []string{day}
+		var total int64
+		for i := range r.Topics {
+			row = append(row, strconv.FormatInt(counts[i][day], 10))
+			total += counts[i][day]
+		}
+		if len(r.Topics) > 1 {
+			row = append(row, strconv.FormatInt(total, 10))
+		}
+		perDay.Rows = append(perDay.Rows, row)
+	}
+	return Section{
+		Title:      "Event Volumes",
+		Paragraphs: []string{"Events published each day (UTC) of the period."},
+		Tables:     []Table{perDay},
+	}
+}
+
+func (r *Report) types() Section {
+	section := Section{Title: "Event Types"}
+	for _, topic := range r.Topics {
+		types := Table{Title: topic.Topic, Header: []string{"Type", "Events", "Share"}}
+		for _, count := range topic.PerType {
+			types.Rows = append(types.Rows, []string{
+				count.Type,
+				strconv.FormatInt(count.Events, 10),
+				fmt.Sprintf("%.1f%%", 100*float64(count.Events)/float64(topic.Events)),
+			})
+		}
+		if len(types.Rows) == 0 {
+			section.Paragraphs = append(section.Paragraphs, fmt.Sprintf("No events were published to %s.", topic.Topic))
+			continue
+		}
+		section.Tables = append(section.Tables, types)
+	}
+	return section
+}
+
+func (r *Report) lag() Section {
+	section := Section{Title: "Consumer Lag"}
+	section.Paragraphs = append(section.Paragraphs, "A consumer's lag is the number of events of the reported topics not yet delivered to it. "+
+		"Its trend is its lag at each earlier report of the period, oldest first, and now.")
+	if len(r.Health) == 1 {
+		section.Paragraphs = append(section.Paragraphs, "There were no earlier reports of this server in the period, so there are no trends yet: each report records its snapshot for those after it.")
+	}
+	if len(r.Consumers) == 0 {
+		section.Paragraphs = append(section.Paragraphs, "No consumers are subscribed to the reported topics.")
+		return section
+	}
+
+	lags := Table{Header: []string{"Consumer", "Topics", "Lag", "Change", "Trend"}}
+	for _, consumer := range r.Consumers {
+		trend := make([]string, len(consumer.Lags))
+		for i, point := range consumer.Lags {
+			trend[i] = strconv.FormatInt(point.Lag, 10)
+		}
+		change := consumer.Lag() - consumer.Lags[0].Lag
+		lags.Rows = append(lags.Rows, []string{
+			consumer.ID,
+			strings.Join(consumer.Topics, ", "),
+			strconv.FormatInt(consumer.Lag(), 10),
+			fmt.Sprintf("%+d", change),
+			strings.Join(trend, ", "),
+		})
+	}
+	section.Tables = append(section.Tables, lags)
+	return section
+}
+
+func (r *Report) health() Section {
+	health := Table{Header: []string{"Time", "Status", "Consumers", "Running Dispatchers"}}
+	for i, snapshot := range r.Health {
+		at := formatTime(snapshot.Time)
+		if i == len(r.Health)-1 {
+			at += " (now)"
+		}
+		dispatchers := "None"
+		if len(snapshot.Dispatchers) > 0 {
+			dispatchers = strings.Join(snapshot.Dispatchers, ", ")
+		}
+		health.Rows = append(health.Rows, []string{at, snapshot.Health, strconv.Itoa(snapshot.Consumers), dispatchers})
+	}
+	return Section{
+		Title:      "Health",
+		Paragraphs: []string{"The event store's health at each report of the period."},
+		Tables:     []Table{health},
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// formatRate formats a number of events per day, with a decimal place when it's small
+func formatRate(rate float64) string {
+	if rate < 10 {
+		return strconv.FormatFloat(math.Round(rate*10)/10, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(math.Round(rate), 'f', 0, 64)
+}
+
+func plural(n int64, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,209 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// shutdownTimeout bounds how long servers may take to drain on shutdown
+const shutdownTimeout = 5 * time.Second
+
+// Group runs labeled goroutines that share a context. The context is cancelled when
+// any goroutine fails, when the parent is cancelled, or when the process receives
+// SIGINT or SIGTERM, so every long-running command stops the same way.
+type Group struct {
+	group *errgroup.Group
+	ctx   context.Context
+	stop  context.CancelFunc
+
+	mu       sync.Mutex
+	running  map[string]int
+	closers  []func()
+	handlers map[string]http.Handler
+}
+
+// New creates a group whose context is also cancelled by SIGINT and SIGTERM
+func New(parent context.Context) *Group {
+	signalCtx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	group, ctx := errgroup.WithContext(signalCtx)
+	return &Group{
+		group:   group,
+		ctx:     ctx,
+		stop:    stop,
+		running: make(map[string]int),
+	}
+}
+
+// Context returns the group's context
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs fn in a goroutine carrying the pprof label "task" so it can be identified in
+// goroutine dumps. The first non-nil error cancels the group.
+func (g *Group) Go(label string, fn func(ctx context.Context) error) {
+	g.track(label, 1)
+	g.group.Go(func() error {
+		defer g.track(label, -1)
+
+		var err error
+		pprof.Do(g.ctx, pprof.Labels("task", label), func(ctx context.Context) {
+			err = fn(ctx)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		return nil
+	})
+}
+
+// Stop cancels the group's context, asking every goroutine to finish without error
+func (g *Group) Stop() {
+	g.stop()
+}
+
+// Wait blocks until every goroutine has returned and returns the first error.
+// Background services such as the debug endpoint are stopped afterwards.
+func (g *Group) Wait() error {
+	err := g.group.Wait()
+	g.stop()
+
+	g.mu.Lock()
+	closers := g.closers
+	g.closers = nil
+	g.mu.Unlock()
+	for _, closer := range closers {
+		closer()
+	}
+
+	return err
+}
+
+// Running returns the labels of goroutines that have not yet returned, with counts
+func (g *Group) Running() map[string]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	running := make(map[string]int, len(g.running))
+	for label, n := range g.running {
+		running[label] = n
+	}
+	return running
+}
+
+func (g *Group) track(label string, delta int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.running[label] += delta
+	if g.running[label] <= 0 {
+		delete(g.running, label)
+	}
+}
+
+// Serve runs server under label until the group's context is cancelled, then shuts it
+// down gracefully. Both the server and its shutdown are owned by the group, so neither
+// goroutine outlives the command.
+func (g *Group) Serve(label string, server *http.Server) {
+	// Derive request contexts from the group so long-lived handlers such as streams end
+	// when the group stops rather than holding up shutdown
+	if server.BaseContext == nil {
+		server.BaseContext = func(net.Listener) context.Context { return g.ctx }
+	}
+
+	g.Go(label, func(ctx context.Context) error {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+	g.Go(label+"-shutdown", func(ctx context.Context) error {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			server.Close()
+		}
+		return nil
+	})
+}
+
+// HandleDebug adds a handler to the debug endpoint. It must be called before ServeDebug.
+func (g *Group) HandleDebug(pattern string, handler http.Handler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.handlers == nil {
+		g.handlers = make(map[string]http.Handler)
+	}
+	g.handlers[pattern] = handler
+}
+
+// ServeDebug serves goroutine dumps, and any handlers added with HandleDebug, on addr
+// until Wait returns:
+//
+//	/debug/goroutines        all goroutine stacks with their task labels
+//	/debug/goroutines?debug=2  full stacks in panic format
+//	/debug/tasks             labels of the group's running goroutines
+func (g *Group) ServeDebug(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start debug endpoint: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/goroutines", func(w http.ResponseWriter, r *http.Request) {
+		debug := 1
+		if r.URL.Query().Get("debug") == "2" {
+			debug = 2
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		pprof.Lookup("goroutine").WriteTo(w, debug)
+	})
+	mux.HandleFunc("/debug/tasks", func(w http.ResponseWriter, r *http.Request) {
+		running := g.Running()
+		labels := make([]string, 0, len(running))
+		for label := range running {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, label := range labels {
+			fmt.Fprintf(w, "%s\t%d\n", label, running[label])
+		}
+	})
+	g.mu.Lock()
+	for pattern, handler := range g.handlers {
+		mux.Handle(pattern, handler)
+	}
+	g.mu.Unlock()
+
+	// The debug server runs outside the errgroup so it never keeps Wait from returning
+	server := &http.Server{Handler: mux}
+	done := make(chan struct{})
+	go pprof.Do(g.ctx, pprof.Labels("task", "debug-server"), func(ctx context.Context) {
+		defer close(done)
+		server.Serve(listener)
+	})
+
+	g.mu.Lock()
+	g.closers = append(g.closers, func() {
+		server.Close()
+		<-done
+	})
+	g.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,50 @@
+package seal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// ReadPassphrase reads a passphrase from a file: its first line
+func ReadPassphrase(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+	}
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(line) == 0 {
+		return nil, fmt.Errorf("passphrase file '%s' is empty", path)
+	}
+	return line, nil
+}
+
+// ParseRecipient parses an age public key: "age1..." for X25519, or "age1pq1..." for
+// the post-quantum hybrid
+func ParseRecipient(s string) (age.Recipient, error) {
+	recipients, err := age.ParseRecipients(strings.NewReader(s))
+	if err != nil || len(recipients) != 1 {
+		return nil, fmt.Errorf("invalid recipient '%s' (expected an age public key, age1...)", s)
+	}
+	return recipients[0], nil
+}
+
+// ReadIdentities reads age identities, "AGE-SECRET-KEY-1...", from a file of one per
+// line, as written by age-keygen; blank lines and lines starting with '#' are ignored
+func ReadIdentities(path string) ([]age.Identity, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identity file: %w", err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("identity file '%s': %w", path, err)
+	}
+	return identities, nil
+}
@@ -0,0 +1,101 @@
+// Package seal encrypts whole files, such as exports of events holding personal data, so
+// they can be stored where others might read them. Files are age files
+// (https://age-encryption.org/v1), encrypted with a passphrase or for age recipients
+// ("age1...") and opened with the matching identities ("AGE-SECRET-KEY-1..."), so
+// 'es backup decrypt' and the age tool both decrypt them. age encrypts the contents in
+// authenticated chunks, so a file that's changed or cut short fails to decrypt.
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+)
+
+// ErrNoKey is returned opening a file that none of the keys given opens
+var ErrNoKey = errors.New("none of the passphrases or identities given decrypts the file")
+
+// Keys are what a file is sealed for, or opened with
+type Keys struct {
+	Passphrases [][]byte
+	Recipients  []age.Recipient // to seal files for
+	Identities  []age.Identity  // to open files with
+}
+
+// NewWriter writes the header of a file sealed with keys' passphrase or for its
+// recipients to w, and returns a writer that encrypts what is written to it into w. age
+// seals a file with one passphrase and nothing else, or for any number of recipients.
+// Close must be called to write the last chunk; it doesn't close w.
+func NewWriter(w io.Writer, keys *Keys) (io.WriteCloser, error) {
+	var recipients []age.Recipient
+	switch {
+	case len(keys.Passphrases) > 1 || len(keys.Passphrases) == 1 && len(keys.Recipients) > 0:
+		return nil, fmt.Errorf("a file is encrypted with one passphrase, or for recipients, not both")
+	case len(keys.Passphrases) == 1:
+		recipient, err := age.NewScryptRecipient(string(keys.Passphrases[0]))
+		if err != nil {
+			return nil, err
+		}
+		recipients = []age.Recipient{recipient}
+	case len(keys.Recipients) > 0:
+		recipients = keys.Recipients
+	default:
+		return nil, fmt.Errorf("no passphrase or recipient to encrypt for")
+	}
+	return age.Encrypt(w, recipients...)
+}
+
+// NewReader reads the header of a sealed file from r, unwraps its key with keys'
+// passphrases or identities, and returns a reader of its decrypted contents. Reads fail
+// if the file was changed or cut short.
+func NewReader(r io.Reader, keys *Keys) (io.Reader, error) {
+	identities := append([]age.Identity{}, keys.Identities...)
+	for _, passphrase := range keys.Passphrases {
+		identity, err := age.NewScryptIdentity(string(passphrase))
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	if len(identities) == 0 {
+		return nil, ErrNoKey
+	}
+
+	plain, err := age.Decrypt(r, identities...)
+	var noMatch *age.NoIdentityMatchError
+	switch {
+	case errors.As(err, &noMatch):
+		return nil, fmt.Errorf("%w (it was encrypted for %s)", ErrNoKey, describeStanzas(noMatch.StanzaTypes))
+	case err != nil:
+		return nil, fmt.Errorf("not an encrypted file: %w", err)
+	}
+	return &reader{r: plain}, nil
+}
+
+// describeStanzas says what a file was encrypted for, from the types of its header's
+// stanzas
+func describeStanzas(types []string) string {
+	recipients := 0
+	for _, t := range types {
+		if t == "scrypt" {
+			return "a passphrase"
+		}
+		recipients++
+	}
+	return fmt.Sprintf("%d recipient(s)", recipients)
+}
+
+// reader reports a file that fails to decrypt part way as changed or cut short
+type reader struct {
+	r io.Reader
+}
+
+func (s *reader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("encrypted file is cut short or corrupt: %w", err)
+	}
+	return n, err
+}
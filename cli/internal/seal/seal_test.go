@@ -0,0 +1,208 @@
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+// seal encrypts plain for keys
+func seal(t *testing.T, plain []byte, keys *Keys) []byte {
+	t.Helper()
+	var sealed bytes.Buffer
+	w, err := NewWriter(&sealed, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return sealed.Bytes()
+}
+
+// open decrypts sealed with keys
+func open(sealed []byte, keys *Keys) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(sealed), keys)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func newIdentity(t *testing.T) *age.X25519Identity {
+	t.Helper()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return identity
+}
+
+// plain is larger than age's 64 KiB chunks, so files have several
+var plain = bytes.Repeat([]byte(`{"id":"customers-1","payload":{"email":"a@example.com"}}`+"\n"), 3000)
+
+func TestRoundTrip(t *testing.T) {
+	alice, bob := newIdentity(t), newIdentity(t)
+	passphrase := &Keys{Passphrases: [][]byte{[]byte("correct horse battery staple")}}
+	recipient := &Keys{Recipients: []age.Recipient{alice.Recipient()}, Identities: []age.Identity{alice}}
+	tests := []struct {
+		name  string
+		seal  *Keys
+		open  *Keys
+		plain []byte
+	}{
+		{"passphrase", passphrase, passphrase, plain},
+		{"recipient", &Keys{Recipients: []age.Recipient{alice.Recipient()}}, &Keys{Identities: []age.Identity{alice}}, plain},
+		{"second recipient", &Keys{Recipients: []age.Recipient{alice.Recipient(), bob.Recipient()}}, &Keys{Identities: []age.Identity{bob}}, plain},
+		{"empty file", recipient, recipient, nil},
+		{"exactly one chunk", recipient, recipient, bytes.Repeat([]byte("x"), 64*1024)},
+	}
+	for _, tt := range tests {
+		got, err := open(seal(t, tt.plain, tt.seal), tt.open)
+		if err != nil || !bytes.Equal(got, tt.plain) {
+			t.Errorf("%s: opened %d bytes, %v, want %d", tt.name, len(got), err, len(tt.plain))
+		}
+	}
+}
+
+// TestAgeFiles checks that sealed files are age files, and age files open
+func TestAgeFiles(t *testing.T) {
+	identity := newIdentity(t)
+	sealed := seal(t, plain, &Keys{Recipients: []age.Recipient{identity.Recipient()}})
+	if !bytes.HasPrefix(sealed, []byte("age-encryption.org/v1\n")) {
+		t.Fatalf("sealed file starts %q", sealed[:30])
+	}
+	r, err := age.Decrypt(bytes.NewReader(sealed), identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(r); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("age.Decrypt read %d bytes, %v", len(got), err)
+	}
+
+	var encrypted bytes.Buffer
+	w, err := age.Encrypt(&encrypted, identity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(plain)
+	w.Close()
+	if got, err := open(encrypted.Bytes(), &Keys{Identities: []age.Identity{identity}}); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("opening an age.Encrypt file read %d bytes, %v", len(got), err)
+	}
+}
+
+func TestWrongKeys(t *testing.T) {
+	alice, bob := newIdentity(t), newIdentity(t)
+	tests := []struct {
+		name  string
+		seal  *Keys
+		open  *Keys
+		error string
+	}{
+		{"wrong passphrase", &Keys{Passphrases: [][]byte{[]byte("right")}}, &Keys{Passphrases: [][]byte{[]byte("wrong")}}, "it was encrypted for a passphrase"},
+		{"passphrase for recipients", &Keys{Recipients: []age.Recipient{alice.Recipient(), bob.Recipient()}}, &Keys{Passphrases: [][]byte{[]byte("right")}}, "it was encrypted for 2 recipient(s)"},
+		{"other identity", &Keys{Recipients: []age.Recipient{alice.Recipient()}}, &Keys{Identities: []age.Identity{bob}}, "it was encrypted for 1 recipient(s)"},
+		{"no keys", &Keys{Recipients: []age.Recipient{alice.Recipient()}}, &Keys{}, "none of the passphrases"},
+	}
+	for _, tt := range tests {
+		_, err := open(seal(t, []byte("secret"), tt.seal), tt.open)
+		if !errors.Is(err, ErrNoKey) || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: error = %v, want ErrNoKey containing %q", tt.name, err, tt.error)
+		}
+	}
+}
+
+func TestNewWriterKeys(t *testing.T) {
+	identity := newIdentity(t)
+	tests := []struct {
+		name string
+		keys *Keys
+	}{
+		{"nothing", &Keys{}},
+		{"passphrase and recipient", &Keys{Passphrases: [][]byte{[]byte("p")}, Recipients: []age.Recipient{identity.Recipient()}}},
+		{"two passphrases", &Keys{Passphrases: [][]byte{[]byte("p"), []byte("q")}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewWriter(io.Discard, tt.keys); err == nil {
+			t.Errorf("%s: NewWriter succeeded", tt.name)
+		}
+	}
+}
+
+// TestTruncated cuts a sealed file short in its header, at a chunk boundary and part way
+// through a chunk
+func TestTruncated(t *testing.T) {
+	identity := newIdentity(t)
+	keys := &Keys{Recipients: []age.Recipient{identity.Recipient()}, Identities: []age.Identity{identity}}
+	sealed := seal(t, plain, keys)
+	header := bytes.Index(sealed, []byte("\n---"))
+	payload := bytes.IndexByte(sealed[header+1:], '\n') + header + 2
+	// The payload is a 16-byte nonce, then chunks of 64 KiB and a 16-byte tag
+	boundary := payload + 16 + 64*1024 + 16
+
+	for _, n := range []int{0, header / 2, payload + 8, boundary, boundary + 100, len(sealed) - 1} {
+		got, err := open(sealed[:n], keys)
+		if err == nil {
+			t.Errorf("opening the first %d of %d bytes read %d bytes and no error", n, len(sealed), len(got))
+		}
+	}
+}
+
+// TestTampered changes a byte of the header, a chunk and the last chunk
+func TestTampered(t *testing.T) {
+	identity := newIdentity(t)
+	keys := &Keys{Recipients: []age.Recipient{identity.Recipient()}, Identities: []age.Identity{identity}}
+	sealed := seal(t, plain, &Keys{Recipients: keys.Recipients})
+
+	for _, i := range []int{30, len(sealed) / 2, len(sealed) - 5} {
+		tampered := bytes.Clone(sealed)
+		tampered[i] ^= 0x01
+		if got, err := open(tampered, keys); err == nil {
+			t.Errorf("opening with byte %d changed read %d bytes and no error", i, len(got))
+		}
+	}
+	if _, err := open(append(bytes.Clone(sealed), 0), keys); err == nil {
+		t.Error("opening with a byte added succeeded")
+	}
+}
+
+func TestNotSealed(t *testing.T) {
+	_, err := open([]byte(`{"id":"customers-1"}`+"\n"), &Keys{Passphrases: [][]byte{[]byte("p")}})
+	if err == nil || !strings.Contains(err.Error(), "not an encrypted file") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	if _, err := ParseRecipient("age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"); err != nil {
+		t.Error(err)
+	}
+	for _, s := range []string{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8q", "ssh-ed25519 AAAA", "", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p\nage1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"} {
+		if _, err := ParseRecipient(s); err == nil {
+			t.Errorf("ParseRecipient(%q) succeeded", s)
+		}
+	}
+
+	identity := newIdentity(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key.txt")
+	os.WriteFile(path, []byte("# created: 2026-10-16\n# public key: "+identity.Recipient().String()+"\n"+identity.String()+"\n"), 0o600)
+	identities, err := ReadIdentities(path)
+	if err != nil || len(identities) != 1 || identities[0].(*age.X25519Identity).String() != identity.String() {
+		t.Errorf("ReadIdentities = %v, %v", identities, err)
+	}
+	os.WriteFile(path, []byte("# nothing\n"), 0o600)
+	if _, err := ReadIdentities(path); err == nil {
+		t.Error("ReadIdentities of a file with no identities succeeded")
+	}
+}
@@ -0,0 +1,153 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Options configures a search
+type Options struct {
+	Query      string
+	Regex      bool     // Query is a regular expression rather than a substring
+	IgnoreCase bool     // match regardless of case
+	Fields     []string // only search these fields and what they contain (default: the whole payload)
+}
+
+// Match is a field whose value matched, with the byte ranges of the matches in Value
+type Match struct {
+	Field string   `json:"field"`
+	Value string   `json:"value"`
+	Spans [][2]int `json:"-"`
+}
+
+// Summary describes a finished search
+type Summary struct {
+	Topic   string  `json:"topic"`
+	Scanned int     `json:"scanned"`
+	Matched int     `json:"matched"` // events with at least one match
+	Seconds float64 `json:"durationSeconds"`
+}
+
+// Matcher finds a query in event fields
+type Matcher struct {
+	re     *regexp.Regexp
+	fields []string
+}
+
+// New creates a matcher
+func New(opts Options) (*Matcher, error) {
+	if opts.Query == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+	pattern := opts.Query
+	if !opts.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return &Matcher{re: re, fields: opts.Fields}, nil
+}
+
+// Search returns the event's matching fields in path order, or nothing if it doesn't match
+func (m *Matcher) Search(event client.Event) []Match {
+	var matches []Match
+	visit := func(path, value string) {
+		spans := m.re.FindAllStringIndex(value, -1)
+		if len(spans) == 0 {
+			return
+		}
+		match := Match{Field: path, Value: value}
+		for _, span := range spans {
+			if span[0] == span[1] {
+				continue
+			}
+			match.Spans = append(match.Spans, [2]int{span[0], span[1]})
+		}
+		if len(match.Spans) > 0 {
+			matches = append(matches, match)
+		}
+	}
+
+	if len(m.fields) == 0 {
+		walk("payload", event.Payload, visit)
+		return matches
+	}
+	for _, field := range m.fields {
+		switch field {
+		case "type":
+			visit(field, event.Type)
+		case "id":
+			visit(field, event.ID)
+		default:
+			path := strings.TrimPrefix(field, "payload.")
+			if value, ok := lookup(event.Payload, path); ok {
+				walk("payload."+path, value, visit)
+			}
+		}
+	}
+	return matches
+}
+
+// walk calls visit with the path and text of every scalar value in value
+func walk(path string, value interface{}, visit func(path, value string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walk(path+"."+key, v[key], visit)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walk(path+"."+strconv.Itoa(i), item, visit)
+		}
+	case string:
+		visit(path, v)
+	case nil:
+		visit(path, "null")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%v", v))
+		}
+		visit(path, string(data))
+	}
+}
+
+// lookup finds a payload value by its dotted path; array elements are addressed by index
+func lookup(payload map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = payload
+	for _, part := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			current = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
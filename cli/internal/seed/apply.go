@@ -0,0 +1,327 @@
+package seed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+)
+
+// publishBatchSize is the number of events published per request
+const publishBatchSize = 100
+
+// Kinds of resources actions are on
+const (
+	KindTopic    = "topic"
+	KindConsumer = "consumer"
+	KindEvents   = "events"
+)
+
+// Action is what applying or resetting fixtures did to a resource, or found already done
+type Action struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"` // the topic, or the consumer's callback
+	Action string `json:"action"`
+}
+
+// Result lists what applying or resetting fixtures did, in order
+type Result struct {
+	Server  string   `json:"server"`
+	Files   []string `json:"files"`
+	Actions []Action `json:"actions"`
+}
+
+func (r *Result) add(kind, name, format string, args ...interface{}) {
+	r.Actions = append(r.Actions, Action{Kind: kind, Name: name, Action: fmt.Sprintf(format, args...)})
+}
+
+// Apply provisions fixtures so the event store has them, in dependency order: it
+// creates their topics, or adds schemas missing from existing topics, then registers
+// their consumers, then publishes their events. It can be run again: resources that
+// already match are left alone and events already in a topic, with the same type and
+// payload, aren't published again. A consumer whose topics differ from its fixture's
+// is registered again, as consumers can't be changed.
+func Apply(apiClient *client.Client, server string, fixtures *Fixtures) (*Result, error) {
+	result := &Result{Server: server, Files: fixtures.Files, Actions: []Action{}}
+	if err := applyTopics(apiClient, fixtures, result); err != nil {
+		return result, err
+	}
+	if err := applyConsumers(apiClient, fixtures, result); err != nil {
+		return result, err
+	}
+	return result, applyEvents(apiClient, fixtures, result)
+}
+
+func applyTopics(apiClient *client.Client, fixtures *Fixtures, result *Result) error {
+	if len(fixtures.Topics) == 0 {
+		return nil
+	}
+	topics, err := apiClient.GetTopics()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]client.Topic, len(topics))
+	for _, topic := range topics {
+		existing[topic.Name] = topic
+	}
+
+	for _, topic := range fixtures.Topics {
+		current, ok := existing[topic.Name]
+		if !ok {
+			if err := apiClient.CreateTopic(topic.Name, topic.Schemas); err != nil {
+				return fmt.Errorf("failed to create topic '%s': %w", topic.Name, err)
+			}
+			result.add(KindTopic, topic.Name, "created with %d schema(s)", len(topic.Schemas))
+			continue
+		}
+		if changed := changedSchemas(current.Schemas, topic.Schemas); len(changed) > 0 {
+			if err := apiClient.UpdateTopicSchemas(topic.Name, topic.Schemas); err != nil {
+				return fmt.Errorf("failed to update the schemas of topic '%s': %w", topic.Name, err)
+			}
+			result.add(KindTopic, topic.Name, "schemas updated: %s", strings.Join(changed, ", "))
+		} else {
+			result.add(KindTopic, topic.Name, "unchanged")
+		}
+	}
+	return nil
+}
+
+// changedSchemas returns the event types of the fixture's schemas that the topic doesn't
+// have, or has with other fields
+func changedSchemas(current, fixture []client.Schema) []string {
+	byType := make(map[string]client.Schema, len(current))
+	for _, schema := range current {
+		byType[schema.EventType] = schema
+	}
+	var changed []string
+	for _, schema := range fixture {
+		existing, ok := byType[schema.EventType]
+		if !ok || canonical(existing.Properties) != canonical(schema.Properties) || canonical(sorted(existing.Required)) != canonical(sorted(schema.Required)) {
+			changed = append(changed, schema.EventType)
+		}
+	}
+	return changed
+}
+
+func applyConsumers(apiClient *client.Client, fixtures *Fixtures, result *Result) error {
+	if len(fixtures.Consumers) == 0 {
+		return nil
+	}
+	consumers, err := apiClient.GetConsumers()
+	if err != nil {
+		return err
+	}
+	byCallback := map[string][]client.Consumer{}
+	for _, consumer := range consumers {
+		byCallback[consumer.Callback] = append(byCallback[consumer.Callback], consumer)
+	}
+
+	for _, fixture := range fixtures.Consumers {
+		existing := byCallback[fixture.Callback]
+		if len(existing) == 1 && sameTopics(existing[0], fixture.Topics) {
+			result.add(KindConsumer, fixture.Callback, "unchanged (%s)", existing[0].ID)
+			continue
+		}
+		for _, consumer := range existing {
+			if err := apiClient.DeleteConsumer(consumer.ID); err != nil {
+				return fmt.Errorf("failed to delete consumer %s: %w", consumer.ID, err)
+			}
+		}
+		topics := make(map[string]string, len(fixture.Topics))
+		for _, topic := range fixture.Topics {
+			topics[topic] = ""
+		}
+		id, err := apiClient.RegisterConsumer(fixture.Callback, topics)
+		if err != nil {
+			return fmt.Errorf("failed to register the consumer with callback %s: %w", fixture.Callback, err)
+		}
+		if len(existing) > 0 {
+			result.add(KindConsumer, fixture.Callback, "registered again for %s (%s)", strings.Join(fixture.Topics, ", "), id)
+		} else {
+			result.add(KindConsumer, fixture.Callback, "registered (%s)", id)
+		}
+	}
+	return nil
+}
+
+func sameTopics(consumer client.Consumer, topics []string) bool {
+	if len(consumer.Topics) != len(topics) {
+		return false
+	}
+	for _, topic := range topics {
+		if _, ok := consumer.Topics[topic]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func applyEvents(apiClient *client.Client, fixtures *Fixtures, result *Result) error {
+	for _, topic := range eventTopics(fixtures) {
+		var events []Event
+		for _, event := range fixtures.Events {
+			if event.Topic == topic {
+				events = append(events, event)
+			}
+		}
+
+		// Count the events already published, by type and payload, to skip as many
+		present, err := publishedEvents(apiClient, topic)
+		if err != nil {
+			return err
+		}
+		var missing []client.EventPublishRequest
+		for _, event := range events {
+			key := eventKey(event.Type, event.Payload)
+			if present[key] > 0 {
+				present[key]--
+				continue
+			}
+			missing = append(missing, client.EventPublishRequest{Topic: topic, Type: event.Type, Payload: event.Payload})
+		}
+
+		for start := 0; start < len(missing); start += publishBatchSize {
+			if _, err := apiClient.PublishEvents(missing[start:min(start+publishBatchSize, len(missing))]); err != nil {
+				return fmt.Errorf("failed to publish the events of '%s' after %d of %d: %w", topic, start, len(missing), err)
+			}
+		}
+		switch already := len(events) - len(missing); {
+		case len(missing) == 0:
+			result.add(KindEvents, topic, "all %d already published", len(events))
+		case already > 0:
+			result.add(KindEvents, topic, "published %d (%d already published)", len(missing), already)
+		default:
+			result.add(KindEvents, topic, "published %d", len(missing))
+		}
+	}
+	return nil
+}
+
+// publishedEvents counts a topic's events by type and payload; a topic that doesn't
+// exist yet has none
+func publishedEvents(apiClient *client.Client, topic string) (map[string]int, error) {
+	present := map[string]int{}
+	if _, err := apiClient.GetTopic(topic); err != nil {
+		// Publishing reports a topic that doesn't exist
+		return present, nil
+	}
+	err := apiClient.ScanEvents(topic, "", func(events []client.Event) (bool, error) {
+		for _, event := range events {
+			present[eventKey(event.Type, event.Payload)]++
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the events of '%s': %w", topic, err)
+	}
+	return present, nil
+}
+
+// eventTopics returns the topics of the fixtures' events, in the order they first appear
+func eventTopics(fixtures *Fixtures) []string {
+	var topics []string
+	seen := map[string]bool{}
+	for _, event := range fixtures.Events {
+		if !seen[event.Topic] {
+			seen[event.Topic] = true
+			topics = append(topics, event.Topic)
+		}
+	}
+	return topics
+}
+
+// Reset deletes what the fixtures provisioned, so they can be applied afresh: the
+// events of their topics and their consumers. Event stores can't delete topics, so
+// topics are kept with their schemas. Deleting events needs the 'topic-truncate'
+// feature, which is experimental: the event store server doesn't implement it yet.
+func Reset(apiClient *client.Client, server string, fixtures *Fixtures) (*Result, error) {
+	result := &Result{Server: server, Files: fixtures.Files, Actions: []Action{}}
+
+	topics := eventTopics(fixtures)
+	for _, topic := range fixtures.Topics {
+		if !contains(topics, topic.Name) {
+			topics = append(topics, topic.Name)
+		}
+	}
+	for _, name := range topics {
+		// A topic that doesn't exist yet has no events to delete
+		topic, err := apiClient.RefreshTopic(name)
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to get topic '%s': %w", name, err)
+		}
+		if topic.Sequence == 0 {
+			continue
+		}
+		if !apiClient.Supports(client.FeatureTopicTruncate) {
+			return result, fmt.Errorf("the event store does not support deleting events (feature '%s'), so topic '%s' can't be reset", client.FeatureTopicTruncate, name)
+		}
+		// Every event before the next one the topic will have
+		next := eventid.ID{Topic: name, Sequence: int64(topic.Sequence) + 1}.String()
+		truncated, err := apiClient.TruncateTopic(name, client.TruncateRequest{BeforeEventID: next})
+		if err != nil {
+			return result, fmt.Errorf("failed to delete the events of '%s': %w", name, err)
+		}
+		result.add(KindEvents, name, "deleted %d", truncated.Deleted)
+	}
+
+	if len(fixtures.Consumers) > 0 {
+		consumers, err := apiClient.GetConsumers()
+		if err != nil {
+			return result, err
+		}
+		callbacks := map[string]bool{}
+		for _, fixture := range fixtures.Consumers {
+			callbacks[fixture.Callback] = true
+		}
+		for _, consumer := range consumers {
+			if !callbacks[consumer.Callback] {
+				continue
+			}
+			if err := apiClient.DeleteConsumer(consumer.ID); err != nil {
+				return result, fmt.Errorf("failed to delete consumer %s: %w", consumer.ID, err)
+			}
+			result.add(KindConsumer, consumer.Callback, "deleted (%s)", consumer.ID)
+		}
+	}
+	return result, nil
+}
+
+func eventKey(eventType string, payload map[string]interface{}) string {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	return eventType + "\x00" + canonical(payload)
+}
+
+// canonical returns JSON for a value with object keys sorted, for comparisons
+func canonical(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func sorted(values []string) []string {
+	values = append([]string(nil), values...)
+	sort.Strings(values)
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package seed
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// TestReset checks that Reset deletes the events of the topics that have some, skips
+// topics that don't exist yet, and stops at any other failure to get a topic
+func TestReset(t *testing.T) {
+	var truncated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/version":
+			io.WriteString(w, `{"version": "1.0.0", "features": ["topic-truncate"]}`)
+		case "/topics/orders":
+			json.NewEncoder(w).Encode(client.Topic{Name: "orders", Sequence: 2})
+		case "/topics/empty":
+			json.NewEncoder(w).Encode(client.Topic{Name: "empty"})
+		case "/topics/orders/truncate":
+			var req client.TruncateRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			truncated = append(truncated, req.BeforeEventID)
+			io.WriteString(w, `{"deleted": 2}`)
+		case "/topics/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"error": "FORBIDDEN", "message": "not allowed"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error": "TOPIC_NOT_FOUND", "message": "no such topic"}`)
+		}
+	}))
+	defer server.Close()
+
+	fixtures := &Fixtures{Topics: []TopicSchemas{{Name: "missing"}, {Name: "empty"}, {Name: "orders"}}}
+	result, err := Reset(client.NewClient(server.URL), server.URL, fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(truncated) != 1 || truncated[0] != "orders-3" {
+		t.Errorf("truncated before %v, want orders-3", truncated)
+	}
+	if len(result.Actions) != 1 || result.Actions[0].Kind != KindEvents || result.Actions[0].Name != "orders" {
+		t.Errorf("Reset actions = %+v, want the events of orders deleted", result.Actions)
+	}
+
+	fixtures.Topics = append(fixtures.Topics, TopicSchemas{Name: "forbidden"})
+	if _, err := Reset(client.NewClient(server.URL), server.URL, fixtures); err == nil || !strings.Contains(err.Error(), "failed to get topic 'forbidden'") {
+		t.Errorf("Reset with a topic it can't get = %v, want an error", err)
+	}
+}
@@ -0,0 +1,188 @@
+// Package seed provisions an event store from a directory of declarative fixture files:
+// the topics with their schemas, the consumers and the events a development or CI store
+// should start with
+package seed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/event-store/cli/internal/client"
+	"go.yaml.in/yaml/v3"
+)
+
+// File is a fixture file. A directory's files are read in name order, so prefixes such
+// as 01-topics.yaml order the events they publish.
+type File struct {
+	Topics    []Topic    `yaml:"topics"`
+	Consumers []Consumer `yaml:"consumers"`
+	Events    []Event    `yaml:"events"`
+}
+
+// Topic is a topic to create, with its schemas inline or in a JSON file like those of
+// 'es topic create --schemas-file', relative to the fixture file
+type Topic struct {
+	Name        string                   `yaml:"name"`
+	Schemas     []map[string]interface{} `yaml:"schemas"`
+	SchemasFile string                   `yaml:"schemasFile"`
+}
+
+// Consumer is a consumer to register, receiving the events of its topics from the start
+type Consumer struct {
+	Callback string   `yaml:"callback"`
+	Topics   []string `yaml:"topics"`
+}
+
+// Event is an event to publish
+type Event struct {
+	Topic   string                 `yaml:"topic"`
+	Type    string                 `yaml:"type"`
+	Payload map[string]interface{} `yaml:"payload"`
+}
+
+// Fixtures are the fixture files of a directory combined, in file order
+type Fixtures struct {
+	Files     []string
+	Topics    []TopicSchemas
+	Consumers []Consumer
+	Events    []Event
+}
+
+// TopicSchemas is a topic of the fixtures with its schemas loaded
+type TopicSchemas struct {
+	Name    string
+	Schemas []client.Schema
+}
+
+// Load reads the fixture files, *.yaml, *.yml and *.json, of a directory, or a single
+// fixture file, and checks them
+func Load(path string) (*Fixtures, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixtures: %w", err)
+		}
+		paths = nil
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					paths = append(paths, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+		sort.Strings(paths)
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no fixture files (*.yaml, *.yml, *.json) in %s", path)
+		}
+	}
+
+	fixtures := &Fixtures{}
+	topics := map[string]string{} // topic -> file declaring it
+	for _, file := range paths {
+		if err := fixtures.add(file, topics); err != nil {
+			return nil, err
+		}
+	}
+	// A consumer is identified by its callback, as its ID is generated by the server
+	callbacks := map[string]bool{}
+	for _, consumer := range fixtures.Consumers {
+		if callbacks[consumer.Callback] {
+			return nil, fmt.Errorf("the consumer with callback %s is declared more than once", consumer.Callback)
+		}
+		callbacks[consumer.Callback] = true
+	}
+	return fixtures, nil
+}
+
+// add reads a fixture file into the fixtures
+func (f *Fixtures) add(path string, declared map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	// An empty file is no fixtures
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	f.Files = append(f.Files, path)
+
+	for i, topic := range file.Topics {
+		if topic.Name == "" {
+			return fmt.Errorf("%s: topic %d needs a name", path, i+1)
+		}
+		if other, ok := declared[topic.Name]; ok {
+			return fmt.Errorf("%s: topic '%s' is already declared in %s", path, topic.Name, other)
+		}
+		declared[topic.Name] = path
+		schemas, err := topic.load(filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("%s: topic '%s': %w", path, topic.Name, err)
+		}
+		f.Topics = append(f.Topics, TopicSchemas{Name: topic.Name, Schemas: schemas})
+	}
+	for i, consumer := range file.Consumers {
+		if consumer.Callback == "" || len(consumer.Topics) == 0 {
+			return fmt.Errorf("%s: consumer %d needs a callback and topics", path, i+1)
+		}
+		f.Consumers = append(f.Consumers, consumer)
+	}
+	for i, event := range file.Events {
+		if event.Topic == "" || event.Type == "" {
+			return fmt.Errorf("%s: event %d needs a topic and a type", path, i+1)
+		}
+		if event.Payload == nil {
+			event.Payload = map[string]interface{}{}
+		}
+		f.Events = append(f.Events, event)
+	}
+	return nil
+}
+
+// load returns a topic's schemas, from the fixture or its schemas file
+func (t Topic) load(dir string) ([]client.Schema, error) {
+	var data []byte
+	var err error
+	switch {
+	case t.SchemasFile != "" && t.Schemas != nil:
+		return nil, fmt.Errorf("has both schemas and schemasFile")
+	case t.SchemasFile != "":
+		path := t.SchemasFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read schemas: %w", err)
+		}
+	default:
+		if data, err = json.Marshal(t.Schemas); err != nil {
+			return nil, err
+		}
+	}
+
+	var schemas []client.Schema
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("invalid schemas: %w", err)
+	}
+	for i, schema := range schemas {
+		if schema.EventType == "" {
+			return nil, fmt.Errorf("schema %d needs an eventType", i+1)
+		}
+	}
+	return schemas, nil
+}
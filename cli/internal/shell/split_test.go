@@ -0,0 +1,56 @@
+package shell
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line  string
+		want  []string
+		error string
+	}{
+		{line: "", want: nil},
+		{line: "  \t ", want: nil},
+		{line: "topic list", want: []string{"topic", "list"}},
+		{line: "  event\tlist   orders  ", want: []string{"event", "list", "orders"}},
+		{line: `publish 'order created' "with spaces"`, want: []string{"publish", "order created", "with spaces"}},
+		{line: `'it''s'`, want: []string{"its"}},
+		{line: `pre'fix'"ed"post`, want: []string{"prefixedpost"}},
+		{line: `'a \" b' '\\'`, want: []string{`a \" b`, `\\`}},
+		{line: `"a \" b" "c \\ d" "e \n f"`, want: []string{`a " b`, `c \ d`, `e \n f`}},
+		{line: `"it's" 'say "hi"'`, want: []string{"it's", `say "hi"`}},
+		{line: `a\ b \'c\' \"d\" \\`, want: []string{"a b", "'c'", `"d"`, `\`}},
+		{line: `\#tag a\#b`, want: []string{"#tag", "a#b"}},
+		{line: `"" '' x`, want: []string{"", "", "x"}},
+		{line: `--payload ""`, want: []string{"--payload", ""}},
+		{line: `a""b`, want: []string{"ab"}},
+		{line: "topic list # all of them", want: []string{"topic", "list"}},
+		{line: "# a comment", want: nil},
+		{line: `a#b "#c" '#d'`, want: []string{"a#b", "#c", "#d"}},
+		{line: "héllo 'wörld'", want: []string{"héllo", "wörld"}},
+		{line: `"\\"`, want: []string{`\`}},
+		{line: `'unterminated`, error: "unterminated single quote"},
+		{line: `a 'b`, error: "unterminated single quote"},
+		{line: `"unterminated`, error: "unterminated double quote"},
+		{line: `"a\"`, error: "unterminated double quote"},
+		{line: `"a\\\"`, error: "unterminated double quote"},
+		{line: `trailing\`, error: "line ends with an escape"},
+		{line: `\`, error: "line ends with an escape"},
+		{line: `a\\\`, error: "line ends with an escape"},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.line)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Split(%q) = %q, %v, want an error containing %q", tt.line, got, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, %v, want %q", tt.line, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package spec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// pollInterval is how often expectations are checked while waiting for events
+const pollInterval = 250 * time.Millisecond
+
+// Result is the outcome of one test
+type Result struct {
+	Spec     string   `json:"spec"`
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Seconds  float64  `json:"durationSeconds"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Runner runs tests against an event store
+type Runner struct {
+	Client    *client.Client
+	ServerURL string // passed to exec actions as ES_SERVER_URL
+}
+
+// Run runs a test: it publishes the given events, notes where each expected topic ends,
+// performs the action and then waits for the expected events after that point
+func (r *Runner) Run(ctx context.Context, test Test) Result {
+	started := time.Now()
+	result := Result{Name: test.Name}
+	fail := func(format string, args ...interface{}) Result {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+		result.Seconds = time.Since(started).Seconds()
+		return result
+	}
+
+	if len(test.Given.Events) > 0 {
+		if _, err := r.Client.PublishEvents(test.Given.Events); err != nil {
+			return fail("given: %v", err)
+		}
+	}
+
+	starts := make([]string, len(test.Then))
+	for i, expectation := range test.Then {
+		topic, err := r.Client.RefreshTopic(expectation.Topic)
+		if err != nil {
+			return fail("then: %v", err)
+		}
+		if topic.Sequence > 0 {
+			starts[i] = eventid.ID{Topic: expectation.Topic, Sequence: int64(topic.Sequence)}.String()
+		}
+	}
+
+	if err := r.act(ctx, test.When); err != nil {
+		return fail("when: %v", err)
+	}
+
+	for i, expectation := range test.Then {
+		if err := r.await(ctx, expectation, starts[i]); err != nil {
+			result.Failures = append(result.Failures, "then: "+err.Error())
+		}
+	}
+	result.Passed = len(result.Failures) == 0
+	result.Seconds = time.Since(started).Seconds()
+	return result
+}
+
+// act performs the test's action, if it has one
+func (r *Runner) act(ctx context.Context, when When) error {
+	switch {
+	case when.Exec != "":
+		command := exec.CommandContext(ctx, "sh", "-c", when.Exec)
+		command.Env = append(os.Environ(), "ES_SERVER_URL="+r.ServerURL)
+		out, err := command.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("'%s' failed: %v\n%s", when.Exec, err, strings.TrimSpace(string(out)))
+		}
+	case when.Webhook != nil:
+		return callWebhook(ctx, when.Webhook)
+	}
+	return nil
+}
+
+func callWebhook(ctx context.Context, webhook *Webhook) error {
+	method := webhook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body *bytes.Reader
+	if webhook.Body != nil {
+		data, err := json.Marshal(webhook.Body)
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook body: %w", err)
+		}
+		body = bytes.NewReader(data)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), webhook.URL, body)
+	if err != nil {
+		return fmt.Errorf("invalid webhook: %w", err)
+	}
+	if webhook.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", req.Method, webhook.URL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned HTTP %d", req.Method, webhook.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// await polls the expectation's topic after since until enough matching events have
+// appeared or its time is up
+func (r *Runner) await(ctx context.Context, expectation Expectation, since string) error {
+	filters := expectation.filters()
+	deadline := time.Now().Add(time.Duration(expectation.Within))
+	seen := 0
+
+	for {
+		err := r.Client.ScanEvents(expectation.Topic, since, func(events []client.Event) (bool, error) {
+			for _, event := range events {
+				if filter.MatchAll(filters, event) {
+					seen++
+				}
+			}
+			since = events[len(events)-1].ID
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+		if seen >= expectation.Count {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("expected %s within %s, saw %d", expectation.String(), time.Duration(expectation.Within), seen)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultWithin is how long an expectation waits for its events when neither it nor
+// its file sets a timeout
+const defaultWithin = 10 * time.Second
+
+// File is a spec file: a list of given/when/then tests
+type File struct {
+	Timeout Duration `yaml:"timeout"` // default for every expectation's 'within'
+	Tests   []Test   `yaml:"tests"`
+}
+
+// Test seeds events, triggers an action and checks the events it should cause
+type Test struct {
+	Name  string        `yaml:"name"`
+	Given Given         `yaml:"given"`
+	When  When          `yaml:"when"`
+	Then  []Expectation `yaml:"then"`
+}
+
+// Given lists events published before the action
+type Given struct {
+	Events []client.EventPublishRequest `yaml:"events"`
+}
+
+// When is the action under test: a shell command or an HTTP request
+type When struct {
+	Exec    string   `yaml:"exec"`
+	Webhook *Webhook `yaml:"webhook"`
+}
+
+// Webhook is an HTTP request; the action fails unless it returns a 2xx status
+type Webhook struct {
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method"` // default POST
+	Headers map[string]string `yaml:"headers"`
+	Body    interface{}       `yaml:"body"` // sent as JSON
+}
+
+// Expectation is a number of events that must appear on a topic after the action
+type Expectation struct {
+	Topic  string                 `yaml:"topic"`
+	Type   string                 `yaml:"type"`
+	Match  map[string]interface{} `yaml:"match"` // payload field (dotted path) -> value
+	Count  int                    `yaml:"count"` // at least this many, default 1
+	Within Duration               `yaml:"within"`
+}
+
+// Duration is a time.Duration written as a string such as "30s" in YAML
+type Duration time.Duration
+
+// UnmarshalYAML parses a duration string
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: invalid duration '%s'", node.Line, node.Value)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Load reads and validates a spec file
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse spec %s: %w", path, err)
+	}
+	if len(file.Tests) == 0 {
+		return nil, fmt.Errorf("spec %s has no tests", path)
+	}
+
+	for i := range file.Tests {
+		test := &file.Tests[i]
+		if test.Name == "" {
+			test.Name = fmt.Sprintf("test %d", i+1)
+		}
+		if err := test.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, test.Name, err)
+		}
+		for j := range test.Then {
+			expectation := &test.Then[j]
+			if expectation.Count == 0 {
+				expectation.Count = 1
+			}
+			if expectation.Within == 0 {
+				expectation.Within = file.Timeout
+			}
+			if expectation.Within == 0 {
+				expectation.Within = Duration(defaultWithin)
+			}
+		}
+	}
+	return &file, nil
+}
+
+func (t *Test) validate() error {
+	for i, event := range t.Given.Events {
+		if event.Topic == "" || event.Type == "" {
+			return fmt.Errorf("given event %d needs a topic and a type", i+1)
+		}
+	}
+	if t.When.Exec != "" && t.When.Webhook != nil {
+		return fmt.Errorf("'when' can have exec or webhook, not both")
+	}
+	if t.When.Webhook != nil && t.When.Webhook.URL == "" {
+		return fmt.Errorf("'when.webhook' needs a url")
+	}
+	if len(t.Then) == 0 {
+		return fmt.Errorf("'then' has no expectations")
+	}
+	for i, expectation := range t.Then {
+		if expectation.Topic == "" {
+			return fmt.Errorf("expectation %d needs a topic", i+1)
+		}
+		if expectation.Count < 0 {
+			return fmt.Errorf("expectation %d has a negative count", i+1)
+		}
+	}
+	return nil
+}
+
+// filters converts the expectation's type and match into event filters
+func (e *Expectation) filters() []*filter.Filter {
+	var filters []*filter.Filter
+	if e.Type != "" {
+		filters = append(filters, &filter.Filter{Field: "type", Value: e.Type})
+	}
+	for field, value := range e.Match {
+		filters = append(filters, &filter.Filter{Field: "payload." + field, Value: fmt.Sprintf("%v", value)})
+	}
+	return filters
+}
+
+// String describes the expectation, e.g. "1 order.confirmed event(s) on orders"
+func (e *Expectation) String() string {
+	eventType := e.Type
+	if eventType == "" {
+		eventType = "any"
+	}
+	description := fmt.Sprintf("%d %s event(s) on %s", e.Count, eventType, e.Topic)
+	if len(e.Match) > 0 {
+		description += fmt.Sprintf(" matching %v", e.Match)
+	}
+	return description
+}
@@ -0,0 +1,182 @@
+// Package spool keeps events scheduled for later publishing when the event store can't
+// schedule them itself. Each scheduled batch is a JSON file in ~/.es/spool, named so the
+// files sort by when they are due, and is removed once it has been published.
+package spool
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/config"
+)
+
+// Entry is a batch of events to publish to a server at a time
+type Entry struct {
+	ID        string                       `json:"id"`
+	Server    string                       `json:"server"`
+	PublishAt time.Time                    `json:"publishAt"`
+	Created   time.Time                    `json:"created"`
+	Events    []client.EventPublishRequest `json:"events"`
+}
+
+// Topics returns the distinct topics of the entry's events, in order of appearance
+func (e *Entry) Topics() []string {
+	var topics []string
+	seen := map[string]bool{}
+	for _, event := range e.Events {
+		if !seen[event.Topic] {
+			seen[event.Topic] = true
+			topics = append(topics, event.Topic)
+		}
+	}
+	return topics
+}
+
+// Dir returns the spool directory, ~/.es/spool
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "spool"), nil
+}
+
+// Add spools events to publish to a server at a time
+func Add(server string, publishAt time.Time, events []client.EventPublishRequest) (*Entry, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create spool: %w", err)
+	}
+
+	var suffix [4]byte
+	rand.Read(suffix[:])
+	entry := &Entry{
+		ID:        publishAt.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix[:]),
+		Server:    server,
+		PublishAt: publishAt.UTC(),
+		Created:   time.Now().UTC(),
+		Events:    make([]client.EventPublishRequest, len(events)),
+	}
+	for i, event := range events {
+		event.PublishAt = ""
+		entry.Events[i] = event
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, entry.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write spool entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to write spool entry: %w", err)
+	}
+	return entry, nil
+}
+
+// List returns the spooled entries for a server, or for every server when server is "",
+// in the order they are due
+func List(server string) ([]*Entry, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool: %w", err)
+	}
+
+	var entries []*Entry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spool entry: %w", err)
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("invalid spool entry %s: %w", file.Name(), err)
+		}
+		if server == "" || entry.Server == server {
+			entries = append(entries, &entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].PublishAt.Before(entries[j].PublishAt)
+	})
+	return entries, nil
+}
+
+// Remove deletes a spooled entry
+func Remove(id string) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("spool entry '%s' not found", id)
+	}
+	return err
+}
+
+// Flush publishes the entries for a server, or for every server when server is "", that
+// are due at now, each to the server it was spooled for, and removes them. Entries are
+// published in the order they are due; one that fails to publish stays spooled and holds
+// back its server's later entries, and the first such error is returned. An entry
+// published just before a crash, before it is removed, is published again.
+func Flush(ctx context.Context, server string, now time.Time, clientFor func(server string) *client.Client) ([]*Entry, error) {
+	entries, err := List(server)
+	if err != nil {
+		return nil, err
+	}
+
+	var flushed []*Entry
+	var firstErr error
+	failed := map[string]bool{}
+	for _, entry := range entries {
+		if entry.PublishAt.After(now) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return flushed, err
+		}
+		if failed[entry.Server] {
+			continue
+		}
+		if _, err := clientFor(entry.Server).PublishEvents(entry.Events); err != nil {
+			failed[entry.Server] = true
+			if firstErr == nil {
+				firstErr = fmt.Errorf("spool entry %s: %w", entry.ID, err)
+			}
+			continue
+		}
+		if err := Remove(entry.ID); err != nil {
+			return flushed, err
+		}
+		flushed = append(flushed, entry)
+	}
+	return flushed, firstErr
+}
@@ -0,0 +1,176 @@
+// Package sqlite writes to and queries SQLite databases through the sqlite3 command-line
+// shell, run as a long-lived process fed SQL on its standard input.
+package sqlite
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DB is an open SQLite database
+type DB struct {
+	mu     sync.Mutex
+	path   string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr bytes.Buffer
+	marker string
+	exited bool
+}
+
+// Open opens, or creates, the SQLite database at path
+func Open(path string) (*DB, error) {
+	shell, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return nil, fmt.Errorf("SQLite databases need the sqlite3 command-line shell on the PATH")
+	}
+
+	var suffix [8]byte
+	rand.Read(suffix[:])
+	db := &DB{path: path, marker: "__es_done_" + hex.EncodeToString(suffix[:]) + "__"}
+	// -bail stops at the first failing statement, which Exec then reports
+	db.cmd = exec.Command(shell, "-bail", "-batch", path)
+	db.cmd.Stderr = &db.stderr
+	if db.stdin, err = db.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	stdout, err := db.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	db.stdout = bufio.NewReader(stdout)
+	if err := db.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start sqlite3: %w", err)
+	}
+	if err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Exec runs SQL statements, separated by semicolons
+func (db *DB) Exec(sql string) error {
+	_, err := db.run(sql)
+	return err
+}
+
+// Query runs a SELECT statement and returns its rows, each a map of column name to value
+func (db *DB) Query(sql string) ([]map[string]interface{}, error) {
+	out, err := db.run(".mode json\n" + sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+	var rows []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("unexpected sqlite3 output: %s", strings.TrimSpace(string(out)))
+	}
+	return rows, nil
+}
+
+// run feeds SQL to the shell and returns what it printed, up to the marker printed after
+// it
+func (db *DB) run(sql string) ([]byte, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	sql = strings.TrimSpace(sql)
+	if !strings.HasSuffix(sql, ";") {
+		sql += ";"
+	}
+	if _, err := fmt.Fprintf(db.stdin, "%s\n.print %s\n", sql, db.marker); err != nil {
+		return nil, db.failed()
+	}
+
+	var out []byte
+	for {
+		line, err := db.stdout.ReadBytes('\n')
+		if err != nil {
+			return nil, db.failed()
+		}
+		if string(bytes.TrimRight(line, "\r\n")) == db.marker {
+			return out, nil
+		}
+		out = append(out, line...)
+	}
+}
+
+// failed returns the error that stopped the shell
+func (db *DB) failed() error {
+	db.wait()
+	message := strings.TrimSpace(db.stderr.String())
+	if message == "" {
+		message = "sqlite3 stopped"
+	}
+	return fmt.Errorf("%s: %s", db.path, message)
+}
+
+// Close closes the database
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.stdin.Close()
+	return db.wait()
+}
+
+func (db *DB) wait() error {
+	if db.exited {
+		return nil
+	}
+	db.exited = true
+	return db.cmd.Wait()
+}
+
+// Ident quotes an identifier, such as a table or column name
+func Ident(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Literal formats a JSON value as an SQL literal: strings as text, numbers and booleans
+// as numbers, null as NULL, and objects and arrays as their JSON text
+func Literal(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return text(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case json.Number:
+		return v.String()
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return text(fmt.Sprintf("%v", v))
+		}
+		return text(string(data))
+	}
+}
+
+func text(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// MaxBuckets is the most time buckets a series can have
+const MaxBuckets = 10000
+
+// Series is a topic's event counts per time bucket over a period
+type Series struct {
+	Topic    string   `json:"topic"`
+	Interval string   `json:"interval"`
+	Since    string   `json:"since"` // the start of the first bucket
+	Until    string   `json:"until"` // the end of the period
+	Events   int64    `json:"events"`
+	Buckets  []Bucket `json:"buckets"` // oldest first, including those without events
+}
+
+// Bucket is the number of events with timestamps in an interval starting at Start
+type Bucket struct {
+	Start  string `json:"start"`
+	Events int64  `json:"events"`
+}
+
+// SeriesCollector counts a topic's events per time bucket one event at a time
+type SeriesCollector struct {
+	series   *Series
+	start    time.Time
+	until    time.Time
+	interval time.Duration
+}
+
+// NewSeriesCollector creates a collector of the events from since to until in buckets of
+// interval, aligned to the interval in UTC: 1h buckets start on the hour. It fails if the
+// period has more than MaxBuckets intervals.
+func NewSeriesCollector(topic string, since, until time.Time, interval time.Duration) (*SeriesCollector, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+	start := since.UTC().Truncate(interval)
+	count := int((until.Sub(start) + interval - 1) / interval)
+	if count > MaxBuckets {
+		return nil, fmt.Errorf("%s intervals over the period would make %d buckets (at most %d); use a longer interval or a shorter period", formatInterval(interval), count, MaxBuckets)
+	}
+
+	series := &Series{
+		Topic:    topic,
+		Interval: formatInterval(interval),
+		Since:    start.Format(time.RFC3339),
+		Until:    until.UTC().Format(time.RFC3339),
+		Buckets:  make([]Bucket, count),
+	}
+	for i := range series.Buckets {
+		series.Buckets[i].Start = start.Add(time.Duration(i) * interval).Format(time.RFC3339)
+	}
+	return &SeriesCollector{series: series, start: start, until: until, interval: interval}, nil
+}
+
+// Add counts an event in its bucket; events outside the period are ignored
+func (c *SeriesCollector) Add(event client.Event) {
+	t, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+	if err != nil || t.Before(c.start) || !t.Before(c.until) {
+		return
+	}
+	i := int(t.Sub(c.start) / c.interval)
+	if i >= len(c.series.Buckets) {
+		return
+	}
+	c.series.Buckets[i].Events++
+	c.series.Events++
+}
+
+// Series returns the counts of the events added so far
+func (c *SeriesCollector) Series() *Series {
+	return c.series
+}
+
+// formatInterval formats an interval without the zero units time.Duration shows, such as
+// 1h rather than 1h0m0s
+func formatInterval(interval time.Duration) string {
+	s := interval.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+package stats
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// bucketBounds are the upper bounds of the payload size buckets; the last bucket has none
+var bucketBounds = []int{256, 1024, 4096, 16384, 65536}
+
+// Collector accumulates a topic's statistics one event at a time
+type Collector struct {
+	topic  string
+	events int64
+	first  client.Event
+	last   client.Event
+	days   map[string]int64
+	types  map[string]int64
+	sizes  []int
+	total  int64
+}
+
+// NewCollector creates a collector for a topic's events
+func NewCollector(topic string) *Collector {
+	return &Collector{topic: topic, days: make(map[string]int64), types: make(map[string]int64)}
+}
+
+// Add counts an event; events are expected in topic order
+func (c *Collector) Add(event client.Event) {
+	if c.events == 0 {
+		c.first = event
+	}
+	c.last = event
+	c.events++
+
+	if day := Day(event.Timestamp); day != "" {
+		c.days[day]++
+	}
+	c.types[event.Type]++
+
+	size := 0
+	if data, err := json.Marshal(event.Payload); err == nil {
+		size = len(data)
+	}
+	c.sizes = append(c.sizes, size)
+	c.total += int64(size)
+}
+
+// Stats returns the statistics of the events added so far
+func (c *Collector) Stats() *client.TopicStats {
+	stats := &client.TopicStats{
+		Topic:   c.topic,
+		Events:  c.events,
+		PerDay:  []client.DayCount{},
+		PerType: []client.TypeCount{},
+		Source:  "scan",
+	}
+	if c.events == 0 {
+		stats.PayloadBytes.Buckets = []client.SizeBucket{}
+		return stats
+	}
+	stats.FirstEventID = c.first.ID
+	stats.LastEventID = c.last.ID
+	stats.FirstTimestamp = c.first.Timestamp
+	stats.LastTimestamp = c.last.Timestamp
+	stats.PerDay = c.perDay()
+
+	for eventType, events := range c.types {
+		stats.PerType = append(stats.PerType, client.TypeCount{Type: eventType, Events: events})
+	}
+	sort.Slice(stats.PerType, func(i, j int) bool {
+		if stats.PerType[i].Events != stats.PerType[j].Events {
+			return stats.PerType[i].Events > stats.PerType[j].Events
+		}
+		return stats.PerType[i].Type < stats.PerType[j].Type
+	})
+
+	stats.PayloadBytes = c.payloadSizes()
+	return stats
+}
+
+// perDay returns the event counts for every day from the earliest to the latest, so
+// quiet days show up as zeros
+func (c *Collector) perDay() []client.DayCount {
+	if len(c.days) == 0 {
+		return []client.DayCount{}
+	}
+	var first, last time.Time
+	for day := range c.days {
+		t, _ := time.Parse("2006-01-02", day)
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	var days []client.DayCount
+	for t := first; !t.After(last); t = t.AddDate(0, 0, 1) {
+		day := t.Format("2006-01-02")
+		days = append(days, client.DayCount{Date: day, Events: c.days[day]})
+	}
+	return days
+}
+
+func (c *Collector) payloadSizes() client.PayloadSizes {
+	sorted := append([]int(nil), c.sizes...)
+	sort.Ints(sorted)
+
+	sizes := client.PayloadSizes{
+		Total: c.total,
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		Mean:  float64(c.total) / float64(len(sorted)),
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		P99:   percentile(sorted, 99),
+	}
+
+	lower := 0
+	for i := 0; i <= len(bucketBounds); i++ {
+		upper := 0
+		if i < len(bucketBounds) {
+			upper = bucketBounds[i]
+		}
+		bucket := client.SizeBucket{Min: lower, Max: upper}
+		for _, size := range sorted {
+			if size >= lower && (upper == 0 || size < upper) {
+				bucket.Events++
+			}
+		}
+		sizes.Buckets = append(sizes.Buckets, bucket)
+		lower = upper
+	}
+	return sizes
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []int, p int) int {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Day returns the UTC date (YYYY-MM-DD) of an event timestamp, or "" if it can't be parsed
+func Day(timestamp string) string {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
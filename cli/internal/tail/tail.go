@@ -0,0 +1,216 @@
+package tail
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/filter"
+)
+
+// Reasons a tail stopped
+const (
+	StoppedUntil       = "until"
+	StoppedMaxEvents   = "max-events"
+	StoppedMaxDuration = "max-duration"
+	StoppedInterrupted = "interrupted"
+)
+
+// Options configures a tail of one or more topics
+type Options struct {
+	Topics       []string
+	Since        map[string]string // follow each topic's events after this event ID, from its start if it has none
+	Interval     time.Duration
+	Filters      []*filter.Filter            // only emit events matching all of these
+	TopicFilters map[string][]*filter.Filter // and a topic's events matching all of its own, such as its default filter
+	Decode       func(*client.Event)         // if set, called on each event before it's matched, such as to decrypt it
+	// With several topics, how long an event is held after it's read for events of other
+	// topics with earlier timestamps to be emitted before it
+	ReorderWindow time.Duration
+	// Stop conditions; zero values are unset
+	Until       []*filter.Filter // stop after emitting an event matching all of these
+	MaxEvents   int              // stop after emitting this many events
+	MaxDuration time.Duration    // stop after this long
+}
+
+// Summary describes a finished tail
+type Summary struct {
+	Topic        string   `json:"topic,omitempty"`  // the topic followed, or
+	Topics       []string `json:"topics,omitempty"` // the topics followed, of several
+	Events       int      `json:"events"`           // events emitted
+	FirstEventID string   `json:"firstEventId,omitempty"`
+	LastEventID  string   `json:"lastEventId,omitempty"`
+	Seconds      float64  `json:"durationSeconds"`
+	StoppedBy    string   `json:"stoppedBy"`
+}
+
+// Follow polls the topics every interval and calls emit for each new matching event
+// until a stop condition is met or ctx is done. The events of one topic are emitted in
+// order as they're read. The events of several are merged in timestamp order: each is
+// held for opts.ReorderWindow after it's read, so that events of other topics with
+// earlier timestamps, read late or stamped by a server whose clock is behind, can be
+// emitted before it. Events held when the tail stops are emitted first. Polling errors
+// are passed to onError and retried on the next poll.
+func Follow(ctx context.Context, apiClient *client.Client, opts Options, emit func(client.Event) error, onError func(error)) (*Summary, error) {
+	summary := &Summary{StoppedBy: StoppedInterrupted}
+	if len(opts.Topics) == 1 {
+		summary.Topic = opts.Topics[0]
+	} else {
+		summary.Topics = opts.Topics
+	}
+	started := time.Now()
+	defer func() { summary.Seconds = time.Since(started).Seconds() }()
+
+	var deadline <-chan time.Time
+	if opts.MaxDuration > 0 {
+		timer := time.NewTimer(opts.MaxDuration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	f := &follower{opts: opts, summary: summary, emit: emit, merge: len(opts.Topics) > 1}
+	since := make(map[string]string, len(opts.Topics))
+	for topic, id := range opts.Since {
+		since[topic] = id
+	}
+	// stop emits the events held and ends the tail, unless a stop condition ends it first
+	stop := func(reason string) (*Summary, error) {
+		if err := f.release(time.Time{}); err != nil || f.stopped != "" {
+			return f.finish(err)
+		}
+		summary.StoppedBy = reason
+		return summary, nil
+	}
+	for {
+		for _, topic := range opts.Topics {
+			if ctx.Err() != nil {
+				return stop(StoppedInterrupted)
+			}
+			err := f.poll(apiClient, topic, since)
+			if err != nil && ctx.Err() == nil && f.stopped == "" {
+				if f.merge {
+					err = fmt.Errorf("topic '%s': %w", topic, err)
+				}
+				onError(err)
+			}
+			if f.emitErr != nil || f.stopped != "" {
+				return f.finish(f.emitErr)
+			}
+		}
+		if err := f.release(time.Now()); err != nil || f.stopped != "" {
+			return f.finish(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return stop(StoppedInterrupted)
+		case <-deadline:
+			return stop(StoppedMaxDuration)
+		case <-ticker.C:
+		}
+	}
+}
+
+// held is an event read from one of several topics, waiting to be emitted in order
+type held struct {
+	event     client.Event
+	timestamp time.Time
+	due       time.Time // when it has been held for the reorder window
+}
+
+// follower emits the events of a tail, in order, and checks its stop conditions
+type follower struct {
+	opts    Options
+	summary *Summary
+	emit    func(client.Event) error
+	merge   bool   // hold events to merge several topics
+	held    []held // by timestamp, then the order they were read
+	stopped string // the stop condition met
+	emitErr error
+}
+
+// poll reads a topic's new events after its event ID in since, advancing it, and emits
+// or holds those that match, until a stop condition is met
+func (f *follower) poll(apiClient *client.Client, topic string, since map[string]string) error {
+	return apiClient.ScanEvents(topic, since[topic], func(events []client.Event) (bool, error) {
+		now := time.Now()
+		for _, event := range events {
+			since[topic] = event.ID
+			if f.opts.Decode != nil {
+				f.opts.Decode(&event)
+			}
+			if !filter.MatchAll(f.opts.Filters, event) || !filter.MatchAll(f.opts.TopicFilters[topic], event) {
+				continue
+			}
+			if f.merge {
+				f.hold(event, now)
+				continue
+			}
+			if !f.send(event) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// hold adds an event to those held, after the held events with the same or an earlier
+// timestamp. An event whose timestamp can't be parsed is ordered as if read now.
+func (f *follower) hold(event client.Event, now time.Time) {
+	timestamp, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+	if err != nil {
+		timestamp = now
+	}
+	i := sort.Search(len(f.held), func(i int) bool { return f.held[i].timestamp.After(timestamp) })
+	f.held = slices.Insert(f.held, i, held{event: event, timestamp: timestamp, due: now.Add(f.opts.ReorderWindow)})
+}
+
+// release emits the held events, in timestamp order, up to the first that isn't yet due
+// at now, which holds back those after it; a zero now releases them all
+func (f *follower) release(now time.Time) error {
+	n := 0
+	for n < len(f.held) && (now.IsZero() || !f.held[n].due.After(now)) {
+		ok := f.send(f.held[n].event)
+		n++
+		if !ok {
+			break
+		}
+	}
+	f.held = slices.Delete(f.held, 0, n)
+	return f.emitErr
+}
+
+// send emits an event, returning false if emitting failed or a stop condition is met
+func (f *follower) send(event client.Event) bool {
+	if f.emitErr = f.emit(event); f.emitErr != nil {
+		return false
+	}
+	summary := f.summary
+	if summary.FirstEventID == "" {
+		summary.FirstEventID = event.ID
+	}
+	summary.LastEventID = event.ID
+	summary.Events++
+
+	switch {
+	case len(f.opts.Until) > 0 && filter.MatchAll(f.opts.Until, event):
+		f.stopped = StoppedUntil
+	case f.opts.MaxEvents > 0 && summary.Events >= f.opts.MaxEvents:
+		f.stopped = StoppedMaxEvents
+	}
+	return f.stopped == ""
+}
+
+// finish ends the tail after an error emitting an event or a stop condition
+func (f *follower) finish(err error) (*Summary, error) {
+	if err != nil {
+		return f.summary, err
+	}
+	f.summary.StoppedBy = f.stopped
+	return f.summary, nil
+}
@@ -0,0 +1,140 @@
+package timerange
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayPattern matches durations in days or weeks, which time.ParseDuration doesn't accept
+var dayPattern = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// maxDays is the longest duration in days that a time.Duration can hold
+const maxDays = math.MaxInt64 / int64(24*time.Hour)
+
+// Range selects events by timestamp: at or after Since and before Until. Zero values
+// leave a bound open.
+type Range struct {
+	Since time.Time
+	Until time.Time
+}
+
+// Parse parses --since and --until values; either may be empty. See ParseTime.
+func Parse(since, until string, now time.Time) (Range, error) {
+	var r Range
+	var err error
+	if since != "" {
+		if r.Since, err = ParseTime(since, now); err != nil {
+			return r, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if until != "" {
+		if r.Until, err = ParseTime(until, now); err != nil {
+			return r, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if !r.Since.IsZero() && !r.Until.IsZero() && !r.Since.Before(r.Until) {
+		return r, fmt.Errorf("--since (%s) must be before --until (%s)", r.Since.Format(time.RFC3339), r.Until.Format(time.RFC3339))
+	}
+	return r, nil
+}
+
+// ParseTime parses a point in time: an RFC 3339 timestamp, a date (YYYY-MM-DD, the
+// start of the day in UTC), 'now', 'today' or 'yesterday' (the start of the UTC day), or
+// a duration before now such as '90m', '2h', '7d', '2w' or '2h ago'
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	today := now.UTC().Truncate(24 * time.Hour)
+	switch strings.ToLower(value) {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	ago := strings.TrimSpace(strings.TrimSuffix(value, "ago"))
+	if d, err := time.ParseDuration(ago); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if n, ok := days(ago); ok {
+		return now.AddDate(0, 0, -int(n)), nil
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not a timestamp, date (YYYY-MM-DD), 'today', 'yesterday' or duration such as '2h' or '7d'", value)
+}
+
+// ParseDuration parses a non-negative duration such as '90m', '12h', '30d' or '2w'
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+	if n, ok := days(value); ok {
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("'%s' is not a duration such as '12h', '30d' or '2w'", value)
+}
+
+// days parses a duration in days or weeks such as '30d' or '2w', returning false if it
+// isn't one or is longer than maxDays
+func days(value string) (int64, bool) {
+	m := dayPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	unit := int64(1)
+	if m[2] == "w" {
+		unit = 7
+	}
+	if err != nil || n > maxDays/unit {
+		return 0, false
+	}
+	return n * unit, true
+}
+
+// IsZero reports whether the range is unbounded
+func (r Range) IsZero() bool {
+	return r.Since.IsZero() && r.Until.IsZero()
+}
+
+// Contains reports whether an event timestamp is in the range. Timestamps that can't be
+// parsed are only in an unbounded range.
+func (r Range) Contains(timestamp string) bool {
+	if r.IsZero() {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return false
+	}
+	return (r.Since.IsZero() || !t.Before(r.Since)) && (r.Until.IsZero() || t.Before(r.Until))
+}
+
+// Past reports whether an event timestamp is at or after Until, so no later event of
+// the topic can be in the range
+func (r Range) Past(timestamp string) bool {
+	if r.Until.IsZero() {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	return err == nil && !t.Before(r.Until)
+}
+
+// Format formats a bound for a query parameter, or "" for an open bound
+func Format(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,180 @@
+package timerange
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// testNow is 01:30 in New York on the day after clocks went forward, 05:30 UTC, so the
+// local day before had 23 hours
+func testNow(t *testing.T) time.Time {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Date(2024, 3, 11, 1, 30, 0, 0, newYork)
+}
+
+func TestParseTime(t *testing.T) {
+	now := testNow(t)
+	utc := func(s string) time.Time {
+		parsed, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	tests := []struct {
+		value string
+		want  time.Time
+		error string
+	}{
+		{value: "now", want: now},
+		{value: "today", want: utc("2024-03-11T00:00:00Z")},
+		// The start of the UTC day before, 24 hours before today whatever now's zone
+		{value: "yesterday", want: utc("2024-03-10T00:00:00Z")},
+		{value: " Yesterday ", want: utc("2024-03-10T00:00:00Z")},
+		{value: "2024-03-01T12:00:00Z", want: utc("2024-03-01T12:00:00Z")},
+		{value: "2024-03-01T12:00:00+02:00", want: utc("2024-03-01T10:00:00Z")},
+		{value: "2024-03-01T12:00:00.123456789Z", want: utc("2024-03-01T12:00:00.123456789Z")},
+		{value: "2024-03-01", want: utc("2024-03-01T00:00:00Z")},
+		{value: "2h", want: now.Add(-2 * time.Hour)},
+		{value: "2h ago", want: now.Add(-2 * time.Hour)},
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "0s", want: now},
+		// Days are calendar days in now's zone, so the day before the change is 23 hours
+		{value: "1d", want: now.Add(-23 * time.Hour)},
+		{value: "7d ago", want: now.AddDate(0, 0, -7)},
+		{value: "2w", want: now.AddDate(0, 0, -14)},
+		{value: "2024-03-01T12:00:00", error: "is not a timestamp"},
+		{value: "2024-03-01 12:00:00Z", error: "is not a timestamp"},
+		{value: "2024-13-01", error: "is not a timestamp"},
+		{value: "-2h", error: "is not a timestamp"},
+		{value: "2x", error: "is not a timestamp"},
+		{value: "1.5d", error: "is not a timestamp"},
+		{value: "ago", error: "is not a timestamp"},
+		{value: "106752d", error: "is not a timestamp"},
+		{value: "99999999999999999999d", error: "is not a timestamp"},
+		{value: "tomorrow", error: "is not a timestamp"},
+		{value: "", error: "is not a timestamp"},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, now)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("ParseTime(%q) = %v, %v, want an error containing %q", tt.value, got, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	now := testNow(t)
+	tests := []struct {
+		since, until string
+		want         Range
+		error        string
+	}{
+		{want: Range{}},
+		{since: "2h", want: Range{Since: now.Add(-2 * time.Hour)}},
+		{until: "2024-03-01", want: Range{Until: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+		{since: "yesterday", until: "today", want: Range{Since: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)}},
+		{since: "today", until: "yesterday", error: "--since (2024-03-11T00:00:00Z) must be before --until (2024-03-10T00:00:00Z)"},
+		{since: "2024-03-01", until: "2024-03-01T00:00:00Z", error: "must be before --until"},
+		{since: "soon", error: "invalid --since: 'soon' is not"},
+		{since: "2h", until: "later", error: "invalid --until: 'later' is not"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.since, tt.until, now)
+		if tt.error != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Parse(%q, %q) error = %v, want one containing %q", tt.since, tt.until, err, tt.error)
+			}
+			continue
+		}
+		if err != nil || !got.Since.Equal(tt.want.Since) || !got.Until.Equal(tt.want.Until) {
+			t.Errorf("Parse(%q, %q) = %+v, %v, want %+v", tt.since, tt.until, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		error bool
+	}{
+		{value: "90m", want: 90 * time.Minute},
+		{value: " 12h ", want: 12 * time.Hour},
+		{value: "30d", want: 30 * 24 * time.Hour},
+		{value: "2w", want: 14 * 24 * time.Hour},
+		{value: "106751d", want: 106751 * 24 * time.Hour},
+		{value: "0", want: 0},
+		{value: "106752d", error: true},
+		{value: "15251w", error: true},
+		{value: "-1h", error: true},
+		{value: "1.5d", error: true},
+		{value: "d", error: true},
+		{value: "2h ago", error: true},
+		{value: "", error: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value)
+		if (err != nil) != tt.error || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v (error %v)", tt.value, got, err, tt.want, tt.error)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := Range{Since: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		timestamp      string
+		contains, past bool
+	}{
+		{"2024-02-29T23:59:59.999Z", false, false},
+		{"2024-03-01T00:00:00Z", true, false},
+		{"2024-03-01T20:00:00-05:00", false, true},
+		{"2024-03-02T00:00:00Z", false, true},
+		{"not a time", false, false},
+	}
+	for _, tt := range tests {
+		if got := r.Contains(tt.timestamp); got != tt.contains {
+			t.Errorf("Contains(%q) = %v, want %v", tt.timestamp, got, tt.contains)
+		}
+		if got := r.Past(tt.timestamp); got != tt.past {
+			t.Errorf("Past(%q) = %v, want %v", tt.timestamp, got, tt.past)
+		}
+	}
+	if !(Range{}).Contains("not a time") || (Range{}).Past("2024-03-02T00:00:00Z") {
+		t.Error("an unbounded range should contain every event and never be past")
+	}
+}
+
+// FuzzParseTime checks that ParseTime never panics, and that a time it parses reads
+// back unchanged once formatted as a query parameter
+func FuzzParseTime(f *testing.F) {
+	for _, seed := range []string{"now", "yesterday", "2024-03-01T12:00:00+02:00", "2024-03-01", "2h ago", "7d", "2w", "-1h", "99999999999d", "ago", ""} {
+		f.Add(seed)
+	}
+	now := time.Date(2024, 3, 11, 5, 30, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, value string) {
+		parsed, err := ParseTime(value, now)
+		if err != nil {
+			return
+		}
+		if parsed.IsZero() {
+			t.Fatalf("ParseTime(%q) = the zero time, which reads as an open bound", value)
+		}
+		again, err := ParseTime(Format(parsed), now)
+		if err != nil || !again.Equal(parsed) {
+			t.Fatalf("ParseTime(%q) = %v, but its format %q reads back as %v, %v", value, parsed, Format(parsed), again, err)
+		}
+	})
+}
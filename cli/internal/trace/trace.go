@@ -0,0 +1,99 @@
+// Package trace arranges the events of one flow, those sharing a correlation ID, into
+// the tree of what caused what, following each event's causation ID.
+package trace
+
+import (
+	"sort"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Node is an event of a trace and the events it caused
+type Node struct {
+	Topic    string       `json:"topic"`
+	Event    client.Event `json:"event"`
+	Children []*Node      `json:"caused,omitempty"`
+	// Orphan is set on roots whose cause, given by their causation ID, isn't among
+	// the traced events
+	Orphan bool `json:"orphan,omitempty"`
+}
+
+// Trace collects the events of a flow
+type Trace struct {
+	CorrelationID string
+	nodes         []*Node
+}
+
+// New creates a trace of the events with a correlation ID
+func New(correlationID string) *Trace {
+	return &Trace{CorrelationID: correlationID}
+}
+
+// Add adds an event from a topic if it has the trace's correlation ID, reporting whether
+// it did
+func (t *Trace) Add(topic string, event client.Event) bool {
+	if event.CorrelationID != t.CorrelationID {
+		return false
+	}
+	t.nodes = append(t.nodes, &Node{Topic: topic, Event: event})
+	return true
+}
+
+// Len returns the number of events in the trace
+func (t *Trace) Len() int {
+	return len(t.nodes)
+}
+
+// Roots returns the events no other traced event caused, each with the events it caused
+// below it, oldest first at every level
+func (t *Trace) Roots() []*Node {
+	sort.SliceStable(t.nodes, func(i, j int) bool {
+		return t.nodes[i].Event.Timestamp < t.nodes[j].Event.Timestamp
+	})
+
+	byID := make(map[string]*Node, len(t.nodes))
+	for _, node := range t.nodes {
+		node.Children = nil
+		node.Orphan = false
+		byID[node.Event.ID] = node
+	}
+
+	var roots []*Node
+	for _, node := range t.nodes {
+		parent, ok := byID[node.Event.CausationID]
+		if !ok || parent == node || causes(node, parent, byID) {
+			node.Orphan = node.Event.CausationID != "" && !ok
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots
+}
+
+// causes reports whether node is among the causes of another, which would make a cycle
+// of it becoming the other's child
+func causes(node, other *Node, byID map[string]*Node) bool {
+	seen := map[*Node]bool{}
+	for current := other; current != nil && !seen[current]; current = byID[current.Event.CausationID] {
+		if current == node {
+			return true
+		}
+		seen[current] = true
+	}
+	return false
+}
+
+// Walk calls fn for every node of a forest in tree order, with its depth and whether it
+// is the last of its siblings at each level down to it
+func Walk(roots []*Node, fn func(node *Node, last []bool)) {
+	var walk func(nodes []*Node, last []bool)
+	walk = func(nodes []*Node, last []bool) {
+		for i, node := range nodes {
+			path := append(append([]bool(nil), last...), i == len(nodes)-1)
+			fn(node, path)
+			walk(node.Children, path)
+		}
+	}
+	walk(roots, nil)
+}
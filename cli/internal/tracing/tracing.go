@@ -0,0 +1,149 @@
+// Package tracing records a span per API call with the OpenTelemetry SDK and exports
+// them to an OTLP/HTTP collector configured through the standard OTEL_* variables.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scope names the instrumentation the spans come from
+const scope = "github.com/event-store/cli"
+
+// shutdownTimeout bounds how long Shutdown waits to export the spans still queued
+const shutdownTimeout = 10 * time.Second
+
+// Tracer records spans and exports them in batches as they end. The batch span
+// processor holds a bounded queue (OTEL_BSP_MAX_QUEUE_SIZE, 2048 by default) and sends
+// it every OTEL_BSP_SCHEDULE_DELAY (5s by default) or when a batch is full, so
+// long-running commands don't hold their spans until they exit.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	parent   context.Context // carries the TRACEPARENT span, if any
+}
+
+// Span is a single timed operation
+type Span struct {
+	span trace.Span
+	ctx  context.Context
+}
+
+// FromEnv creates a tracer configured from the standard OTEL_* environment variables.
+// It returns nil when tracing is disabled or no OTLP endpoint is configured.
+func FromEnv() *Tracer {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil
+	}
+	if exporter := os.Getenv("OTEL_TRACES_EXPORTER"); exporter != "" && exporter != "otlp" {
+		return nil
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return nil
+	}
+
+	// Failed exports are dropped rather than logged over the command's output; those of
+	// the last batch are returned by Shutdown
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+	// The exporter reads the endpoint and headers from the environment itself
+	exporter, err := otlptracehttp.New(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: tracing is off: %v\n", err)
+		return nil
+	}
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the default service name
+	res, err := resource.Merge(
+		resource.NewSchemaless(attribute.String("service.name", "es-cli")),
+		resource.Environment(),
+	)
+	if err != nil {
+		res = resource.NewSchemaless(attribute.String("service.name", "es-cli"))
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	// Join an existing trace when invoked from an instrumented script or pipeline
+	parent := propagation.TraceContext{}.Extract(context.Background(),
+		propagation.MapCarrier{"traceparent": os.Getenv("TRACEPARENT")})
+
+	return &Tracer{provider: provider, tracer: provider.Tracer(scope), parent: parent}
+}
+
+// Start begins a client span. A nil tracer returns a nil span, which is safe to use.
+func (t *Tracer) Start(name string) *Span {
+	if t == nil {
+		return nil
+	}
+	ctx, span := t.tracer.Start(t.parent, name, trace.WithSpanKind(trace.SpanKindClient))
+	return &Span{span: span, ctx: ctx}
+}
+
+// SetAttribute records an attribute on the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	switch typed := value.(type) {
+	case int:
+		s.span.SetAttributes(attribute.Int(key, typed))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, typed))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, typed))
+	case string:
+		s.span.SetAttributes(attribute.String(key, typed))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(typed)))
+	}
+}
+
+// Traceparent returns the W3C traceparent header value identifying the span
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(s.ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// End completes the span, marking it failed if err is non-nil
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	if err != nil {
+		s.span.SetStatus(codes.Error, err.Error())
+	} else {
+		s.span.SetStatus(codes.Ok, "")
+	}
+	s.span.End()
+}
+
+// Shutdown exports the spans still queued and stops the tracer. Export failures are
+// reported but never fatal.
+func (t *Tracer) Shutdown() error {
+	if t == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	return nil
+}
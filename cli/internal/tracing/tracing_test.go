@@ -0,0 +1,202 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector is an OTLP/HTTP collector recording the requests it receives
+type collector struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*collectortrace.ExportTraceServiceRequest
+	headers  []http.Header
+	received chan struct{}
+}
+
+func newCollector(t *testing.T) *collector {
+	c := &collector{received: make(chan struct{}, 100)}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("export to %s as %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req collectortrace.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			t.Errorf("collector: %v", err)
+		}
+		c.mu.Lock()
+		c.requests = append(c.requests, &req)
+		c.headers = append(c.headers, r.Header.Clone())
+		c.mu.Unlock()
+		c.received <- struct{}{}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		data, _ := proto.Marshal(&collectortrace.ExportTraceServiceResponse{})
+		w.Write(data)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// spans returns the spans of every request received, with their resources' attributes
+func (c *collector) spans() ([]*tracepb.Span, []map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var spans []*tracepb.Span
+	var resources []map[string]string
+	for _, req := range c.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				if ss.Scope.GetName() != scope {
+					continue
+				}
+				for _, span := range ss.Spans {
+					spans = append(spans, span)
+					resources = append(resources, attributes(rs.Resource.GetAttributes()))
+				}
+			}
+		}
+	}
+	return spans, resources
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]string {
+	result := map[string]string{}
+	for _, kv := range kvs {
+		switch v := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			result[kv.Key] = v.StringValue
+		case *commonpb.AnyValue_IntValue:
+			result[kv.Key] = "int:" + strconv.FormatInt(v.IntValue, 10)
+		case *commonpb.AnyValue_BoolValue:
+			result[kv.Key] = "bool:" + strconv.FormatBool(v.BoolValue)
+		}
+	}
+	return result
+}
+
+func setEnv(t *testing.T, endpoint string) {
+	for _, key := range []string{"OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+		"OTEL_EXPORTER_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_TRACES_HEADERS", "OTEL_SERVICE_NAME", "TRACEPARENT"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", endpoint)
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=ci")
+}
+
+func TestExport(t *testing.T) {
+	c := newCollector(t)
+	setEnv(t, c.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-api-key=secret")
+	t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	tracer := FromEnv()
+	if tracer == nil {
+		t.Fatal("FromEnv returned nil with an endpoint configured")
+	}
+	span := tracer.Start("GET /topics/{topic}")
+	span.SetAttribute("eventstore.topic", "orders")
+	span.SetAttribute("http.response.status_code", 404)
+	span.SetAttribute("retried", true)
+	traceparent := span.Traceparent()
+	span.End(errors.New("topic not found"))
+	tracer.Start("GET /health").End(nil)
+	if err := tracer.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans, resources := c.spans()
+	if len(spans) != 2 {
+		t.Fatalf("collector received %d spans, want 2", len(spans))
+	}
+	if c.headers[0].Get("X-Api-Key") != "secret" {
+		t.Errorf("export headers = %v, want x-api-key from OTEL_EXPORTER_OTLP_HEADERS", c.headers[0])
+	}
+	if resources[0]["service.name"] != "es-cli" || resources[0]["deployment.environment"] != "ci" {
+		t.Errorf("resource attributes = %v", resources[0])
+	}
+
+	failed := spans[0]
+	if failed.Name != "GET /topics/{topic}" || failed.Kind != tracepb.Span_SPAN_KIND_CLIENT {
+		t.Errorf("span = %s, kind %v", failed.Name, failed.Kind)
+	}
+	if got := attributes(failed.Attributes); got["eventstore.topic"] != "orders" || got["http.response.status_code"] != "int:404" || got["retried"] != "bool:true" {
+		t.Errorf("span attributes = %v", got)
+	}
+	if failed.Status.GetCode() != tracepb.Status_STATUS_CODE_ERROR || failed.Status.GetMessage() != "topic not found" {
+		t.Errorf("status = %v", failed.Status)
+	}
+	if spans[1].Status.GetCode() != tracepb.Status_STATUS_CODE_OK {
+		t.Errorf("status of the span that succeeded = %v", spans[1].Status)
+	}
+
+	// Both spans continue the TRACEPARENT trace, and the traceparent sent to the server
+	// names the span
+	for _, span := range spans {
+		if hex.EncodeToString(span.TraceId) != "4bf92f3577b34da6a3ce929d0e0e4736" || hex.EncodeToString(span.ParentSpanId) != "00f067aa0ba902b7" {
+			t.Errorf("span %s has trace %x, parent %x", span.Name, span.TraceId, span.ParentSpanId)
+		}
+	}
+	if want := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + hex.EncodeToString(failed.SpanId) + "-01"; traceparent != want {
+		t.Errorf("Traceparent = %q, want %q", traceparent, want)
+	}
+}
+
+// TestExportInBatches checks that spans are exported while the tracer runs, not only
+// when it shuts down
+func TestExportInBatches(t *testing.T) {
+	c := newCollector(t)
+	setEnv(t, c.URL)
+	t.Setenv("OTEL_BSP_SCHEDULE_DELAY", "50")
+
+	tracer := FromEnv()
+	defer tracer.Shutdown()
+	tracer.Start("GET /topics").End(nil)
+	select {
+	case <-c.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no spans were exported before Shutdown")
+	}
+	if spans, _ := c.spans(); len(spans) != 1 {
+		t.Errorf("collector received %d spans, want 1", len(spans))
+	}
+}
+
+func TestFromEnvDisabled(t *testing.T) {
+	tests := map[string]string{
+		"OTEL_SDK_DISABLED":    "true",
+		"OTEL_TRACES_EXPORTER": "none",
+	}
+	for key, value := range tests {
+		setEnv(t, "http://localhost:4318")
+		t.Setenv(key, value)
+		if FromEnv() != nil {
+			t.Errorf("FromEnv with %s=%s returned a tracer", key, value)
+		}
+	}
+	setEnv(t, "")
+	if FromEnv() != nil {
+		t.Error("FromEnv without an endpoint returned a tracer")
+	}
+
+	// A nil tracer and its spans do nothing
+	var tracer *Tracer
+	span := tracer.Start("GET /topics")
+	span.SetAttribute("k", "v")
+	span.End(nil)
+	if span.Traceparent() != "" || tracer.Shutdown() != nil {
+		t.Error("a nil tracer did something")
+	}
+}
@@ -0,0 +1,168 @@
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// jqErrorKey marks a jq runtime error in a transformer's output
+const jqErrorKey = "__es_transform_error__"
+
+// Transformer rewrites event payloads
+type Transformer interface {
+	// Transform returns the new payload, or false to drop the event
+	Transform(payload map[string]interface{}) (map[string]interface{}, bool, error)
+	// Close releases the transformer's resources
+	Close() error
+}
+
+// New returns a transformer for an expression: a Go template when it contains '{{',
+// otherwise a jq expression, run by the jq executable
+func New(expr string) (Transformer, error) {
+	if strings.Contains(expr, "{{") {
+		return newTemplate(expr)
+	}
+	return newJQ(expr)
+}
+
+// templateTransformer renders a Go template with the payload as '.' and parses the
+// output as the new payload; empty output drops the event
+type templateTransformer struct {
+	tmpl *template.Template
+}
+
+func newTemplate(expr string) (*templateTransformer, error) {
+	tmpl, err := template.New("transform").Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+	}).Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform template: %w", err)
+	}
+	return &templateTransformer{tmpl: tmpl}, nil
+}
+
+func (t *templateTransformer) Transform(payload map[string]interface{}) (map[string]interface{}, bool, error) {
+	var out bytes.Buffer
+	if err := t.tmpl.Execute(&out, payload); err != nil {
+		return nil, false, fmt.Errorf("transform failed: %w", err)
+	}
+	if len(bytes.TrimSpace(out.Bytes())) == 0 {
+		return nil, false, nil
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		return nil, false, fmt.Errorf("transform did not produce a JSON object: %w\n%s", err, out.String())
+	}
+	return result, true, nil
+}
+
+func (t *templateTransformer) Close() error {
+	return nil
+}
+
+// jqTransformer feeds payloads one per line to a long-running jq process. The expression
+// is wrapped so every payload produces exactly one line: an array of the expression's
+// outputs, with runtime errors caught rather than skipping the line.
+type jqTransformer struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr bytes.Buffer
+}
+
+func newJQ(expr string) (*jqTransformer, error) {
+	path, err := exec.LookPath("jq")
+	if err != nil {
+		return nil, fmt.Errorf("jq transforms need jq on the PATH (or use a Go template: '{{ ... }}')")
+	}
+
+	// Compile the expression without running it, to report syntax errors up front
+	check := exec.Command(path, "-n", fmt.Sprintf("if false then (%s) else empty end", expr))
+	if out, err := check.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("invalid jq transform: %s", strings.TrimSpace(string(out)))
+	}
+
+	t := &jqTransformer{}
+	wrapped := fmt.Sprintf("[try (%s) catch {%q: .}]", expr, jqErrorKey)
+	t.cmd = exec.Command(path, "-c", "--unbuffered", wrapped)
+	t.cmd.Stderr = &t.stderr
+	if t.stdin, err = t.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	stdout, err := t.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	t.stdout = bufio.NewReader(stdout)
+	if err := t.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start jq: %w", err)
+	}
+	return t, nil
+}
+
+func (t *jqTransformer) Transform(payload map[string]interface{}) (map[string]interface{}, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
+		return nil, false, fmt.Errorf("jq stopped: %s", strings.TrimSpace(t.stderr.String()))
+	}
+	line, err := t.stdout.ReadBytes('\n')
+	if err != nil {
+		return nil, false, fmt.Errorf("jq stopped: %s", strings.TrimSpace(t.stderr.String()))
+	}
+
+	var outputs []interface{}
+	if err := json.Unmarshal(line, &outputs); err != nil {
+		return nil, false, fmt.Errorf("unexpected jq output: %s", line)
+	}
+	for _, output := range outputs {
+		if object, ok := output.(map[string]interface{}); ok && len(object) == 1 {
+			if message, failed := object[jqErrorKey]; failed {
+				return nil, false, fmt.Errorf("transform failed: %s", formatJSON(message))
+			}
+		}
+	}
+	switch len(outputs) {
+	case 0:
+		return nil, false, nil
+	case 1:
+	default:
+		return nil, false, fmt.Errorf("transform produced %d payloads for one event", len(outputs))
+	}
+
+	result, ok := outputs[0].(map[string]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("transform produced %s, not a JSON object", formatJSON(outputs[0]))
+	}
+	return result, true, nil
+}
+
+func (t *jqTransformer) Close() error {
+	t.stdin.Close()
+	return t.cmd.Wait()
+}
+
+func formatJSON(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,173 @@
+// Package verify checks that a topic's events are consistent, such as after a migration
+// or a restore: that their sequences are contiguous and in order, and that their
+// timestamps never go back
+package verify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+)
+
+// Kinds of anomalies
+const (
+	KindGap       = "gap"          // sequences missing between events, or before the first or after the last
+	KindDuplicate = "duplicate"    // an event with the same sequence as the one before
+	KindOrder     = "out-of-order" // an event with a lower sequence than the one before
+	KindTimestamp = "timestamp"    // an event with an earlier timestamp than the one before
+	KindInvalid   = "invalid"      // an event whose ID or timestamp can't be parsed, or of another topic
+)
+
+// MaxAnomalies is the most anomalies a result lists; the rest are only counted
+const MaxAnomalies = 1000
+
+// Anomaly is an inconsistency found at an event
+type Anomaly struct {
+	Kind    string `json:"kind"`
+	EventID string `json:"eventId"` // the event it was found at; "" for events missing at the end
+	Detail  string `json:"detail"`
+}
+
+// Result is the outcome of verifying a topic's events
+type Result struct {
+	Topic        string    `json:"topic"`
+	Sequence     int64     `json:"sequence"` // the topic's sequence when verification started
+	Events       int64     `json:"events"`   // events read
+	FirstEventID string    `json:"firstEventId,omitempty"`
+	LastEventID  string    `json:"lastEventId,omitempty"`
+	Missing      int64     `json:"missing"`   // events missing in gaps
+	Anomalies    []Anomaly `json:"anomalies"` // the first MaxAnomalies, in the order found
+	Omitted      int       `json:"omitted"`   // anomalies found after the first MaxAnomalies
+}
+
+// Count returns the number of anomalies found, including those omitted
+func (r *Result) Count() int {
+	return len(r.Anomalies) + r.Omitted
+}
+
+// Summary describes how many anomalies were found in the topic, e.g. "3 anomalies in orders"
+func (r *Result) Summary() string {
+	if r.Count() == 1 {
+		return fmt.Sprintf("1 anomaly in %s", r.Topic)
+	}
+	return fmt.Sprintf("%d anomalies in %s", r.Count(), r.Topic)
+}
+
+// Options are how strictly a topic is verified
+type Options struct {
+	// After is the sequence verification starts after: the events up to it aren't read
+	// and aren't expected
+	After int64
+	// AllowTruncated doesn't report the events missing before the first as a gap, as after
+	// truncation or retention removed the oldest events
+	AllowTruncated bool
+}
+
+// Verifier checks a topic's events one at a time, in the order they are read
+type Verifier struct {
+	result   *Result
+	options  Options
+	previous int64 // the highest sequence so far; options.After before the first event
+	lastTime time.Time
+	lastID   string
+	finished bool
+}
+
+// NewVerifier creates a verifier of the events of a topic whose sequence is sequence
+func NewVerifier(topic string, sequence int64, options Options) *Verifier {
+	return &Verifier{
+		result:   &Result{Topic: topic, Sequence: sequence, Anomalies: []Anomaly{}},
+		options:  options,
+		previous: options.After,
+	}
+}
+
+// Add checks an event against those before it
+func (v *Verifier) Add(event client.Event) {
+	first := v.result.Events == 0
+	v.result.Events++
+	if first {
+		v.result.FirstEventID = event.ID
+	}
+	v.result.LastEventID = event.ID
+
+	id, err := eventid.Parse(event.ID)
+	switch {
+	case err != nil:
+		v.report(KindInvalid, event.ID, err.Error())
+	case id.Topic != v.result.Topic:
+		v.report(KindInvalid, event.ID, fmt.Sprintf("event ID is not of topic '%s'", v.result.Topic))
+	case id.Sequence == v.previous && !first:
+		v.report(KindDuplicate, event.ID, fmt.Sprintf("sequence %d again", id.Sequence))
+	case id.Sequence <= v.previous && !first:
+		v.report(KindOrder, event.ID, fmt.Sprintf("sequence %d after %d", id.Sequence, v.previous))
+	default:
+		if missing := id.Sequence - v.previous - 1; missing > 0 {
+			switch {
+			case first && v.options.AllowTruncated:
+			case first && v.options.After == 0:
+				v.gap(event.ID, missing, fmt.Sprintf("%s before the first event (expected after truncation or retention)", sequenceRange(1, id.Sequence-1)))
+			default:
+				v.gap(event.ID, missing, fmt.Sprintf("%s before this event", sequenceRange(v.previous+1, id.Sequence-1)))
+			}
+		}
+		v.previous = id.Sequence
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+	if err != nil {
+		v.report(KindInvalid, event.ID, fmt.Sprintf("invalid timestamp '%s'", event.Timestamp))
+		return
+	}
+	if !v.lastTime.IsZero() && timestamp.Before(v.lastTime) {
+		v.report(KindTimestamp, event.ID, fmt.Sprintf("%s is %s before %s of %s", event.Timestamp, v.lastTime.Sub(timestamp), v.lastTime.Format(time.RFC3339Nano), v.lastID))
+	}
+	v.lastTime = timestamp
+	v.lastID = event.ID
+}
+
+// Result returns the outcome of verifying the events added so far, reporting the events
+// missing after the last up to the topic's sequence, or every event when none were added
+// unless truncation is allowed
+func (v *Verifier) Result() *Result {
+	if v.finished {
+		return v.result
+	}
+	v.finished = true
+	if v.result.Events == 0 && v.options.AllowTruncated {
+		return v.result
+	}
+	if missing := v.result.Sequence - v.previous; missing > 0 {
+		where := "after the last event"
+		if v.result.Events == 0 {
+			where = "with no events read"
+		}
+		v.gap("", missing, fmt.Sprintf("%s %s (the topic's sequence is %d)", sequenceRange(v.previous+1, v.result.Sequence), where, v.result.Sequence))
+	}
+	return v.result
+}
+
+// gap reports missing events
+func (v *Verifier) gap(eventID string, missing int64, detail string) {
+	v.result.Missing += missing
+	v.report(KindGap, eventID, detail)
+}
+
+// report adds an anomaly, or counts it once MaxAnomalies are listed
+func (v *Verifier) report(kind, eventID, detail string) {
+	if len(v.result.Anomalies) >= MaxAnomalies {
+		v.result.Omitted++
+		return
+	}
+	v.result.Anomalies = append(v.result.Anomalies, Anomaly{Kind: kind, EventID: eventID, Detail: detail})
+}
+
+// sequenceRange describes the missing sequences from one to another
+func sequenceRange(from, to int64) string {
+	if from == to {
+		return fmt.Sprintf("sequence %d missing", from)
+	}
+	return fmt.Sprintf("sequences %d-%d missing (%d events)", from, to, to-from+1)
+}
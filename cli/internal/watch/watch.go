@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"context"
+	"time"
+)
+
+// Change kinds, named after the watch event types of kubectl
+const (
+	Added    = "ADDED"
+	Modified = "MODIFIED"
+	Deleted  = "DELETED"
+)
+
+// Row is one line of a watched table, identified by Key across refreshes
+type Row struct {
+	Key    string
+	Cells  []string
+	Object interface{} // the resource the row was built from, for JSON output
+}
+
+// Change describes how a row differs from the previous refresh. Unchanged rows have an
+// empty Kind.
+type Change struct {
+	Kind    string
+	Row     Row
+	Columns []int // indexes of the cells that changed, for Modified rows
+}
+
+// Diff compares two refreshes and returns a change for every row in current order,
+// followed by rows that have been deleted
+func Diff(previous, current []Row) []Change {
+	before := make(map[string]Row, len(previous))
+	for _, row := range previous {
+		before[row.Key] = row
+	}
+
+	changes := make([]Change, 0, len(current))
+	seen := make(map[string]bool, len(current))
+	for _, row := range current {
+		seen[row.Key] = true
+		old, ok := before[row.Key]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Row: row})
+			continue
+		}
+
+		var columns []int
+		for i, cell := range row.Cells {
+			if i >= len(old.Cells) || old.Cells[i] != cell {
+				columns = append(columns, i)
+			}
+		}
+		if len(columns) > 0 {
+			changes = append(changes, Change{Kind: Modified, Row: row, Columns: columns})
+		} else {
+			changes = append(changes, Change{Row: row})
+		}
+	}
+
+	for _, row := range previous {
+		if !seen[row.Key] {
+			changes = append(changes, Change{Kind: Deleted, Row: row})
+		}
+	}
+	return changes
+}
+
+// Changed reports whether any row was added, modified or deleted
+func Changed(changes []Change) bool {
+	for _, change := range changes {
+		if change.Kind != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Loop calls refresh immediately and then every interval until ctx is done or refresh
+// returns an error
+func Loop(ctx context.Context, interval time.Duration, refresh func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := refresh(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,10 +2,27 @@ package main
 
 import (
 	"github.com/event-store/cli/cmd"
-	_ "github.com/event-store/cli/cmd/consumer" // Import to register consumer subcommands
-	_ "github.com/event-store/cli/cmd/event"    // Import to register event subcommands
-	_ "github.com/event-store/cli/cmd/health"   // Import to register health subcommands
-	_ "github.com/event-store/cli/cmd/topic"    // Import to register topic subcommands
+	_ "github.com/event-store/cli/cmd/aggregate"  // Import to register aggregate subcommands
+	_ "github.com/event-store/cli/cmd/archive"    // Import to register archive subcommands
+	_ "github.com/event-store/cli/cmd/assert"     // Import to register assert subcommands
+	_ "github.com/event-store/cli/cmd/audit"      // Import to register audit subcommands
+	_ "github.com/event-store/cli/cmd/auth"       // Import to register auth subcommands
+	_ "github.com/event-store/cli/cmd/backup"     // Import to register backup subcommands
+	_ "github.com/event-store/cli/cmd/bench"      // Import to register bench subcommands
+	_ "github.com/event-store/cli/cmd/bridge"     // Import to register bridge subcommands
+	_ "github.com/event-store/cli/cmd/cache"      // Import to register cache subcommands
+	_ "github.com/event-store/cli/cmd/consumer"   // Import to register consumer subcommands
+	_ "github.com/event-store/cli/cmd/contract"   // Import to register contract subcommands
+	_ "github.com/event-store/cli/cmd/diff"       // Import to register diff subcommands
+	_ "github.com/event-store/cli/cmd/event"      // Import to register event subcommands
+	_ "github.com/event-store/cli/cmd/health"     // Import to register health subcommands
+	_ "github.com/event-store/cli/cmd/job"        // Import to register job subcommands
+	_ "github.com/event-store/cli/cmd/projection" // Import to register projection subcommands
+	_ "github.com/event-store/cli/cmd/seed"       // Import to register seed subcommands
+	_ "github.com/event-store/cli/cmd/sink"       // Import to register sink subcommands
+	_ "github.com/event-store/cli/cmd/spool"      // Import to register spool subcommands
+	_ "github.com/event-store/cli/cmd/test"       // Import to register test subcommands
+	_ "github.com/event-store/cli/cmd/topic"      // Import to register topic subcommands
 )
 
 func main() {
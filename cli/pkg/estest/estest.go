@@ -0,0 +1,256 @@
+// Package estest runs an event store for Go integration tests: the server jar in a
+// temporary directory, a docker container, or a server already running, with a client
+// configured for it and helpers to create topics, publish events and receive their
+// deliveries.
+//
+//	func TestOrderShipping(t *testing.T) {
+//		store := estest.Start(t, estest.Options{})
+//		store.CreateTopic(t, "orders", estest.Schema{EventType: "order.placed", Type: "object"})
+//		receiver := store.NewReceiver(t, "orders")
+//		delivered := estest.PublishAndWaitForDelivery(t, receiver, estest.PublishRequest{
+//			Topic: "orders", Type: "order.placed", Payload: map[string]interface{}{"id": "42"},
+//		})
+//		...
+//	}
+//
+// Without options, Start uses the server at $ES_TEST_SERVER_URL, else runs the jar at
+// $ES_TEST_JAR, else the docker image $ES_TEST_IMAGE, and skips the test if none is set.
+package estest
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+// Client is the event store API client
+type Client = client.Client
+
+// Event is an event as read or delivered
+type Event = client.Event
+
+// PublishRequest is an event to publish
+type PublishRequest = client.EventPublishRequest
+
+// Schema is the JSON schema of an event type of a topic
+type Schema = client.Schema
+
+// Environment variables Start reads when its options don't say how to run the server
+const (
+	EnvServerURL = "ES_TEST_SERVER_URL"
+	EnvJar       = "ES_TEST_JAR"
+	EnvImage     = "ES_TEST_IMAGE"
+)
+
+// containerPort is the port the server listens on in a container
+const containerPort = 8000
+
+// Options configures how Start runs the event store
+type Options struct {
+	ServerURL string // an event store already running, shared with other tests
+	Jar       string // the server jar, run with java in a temporary directory
+	Image     string // a docker image of the server
+
+	// CallbackHost is the host the event store reaches receivers at; it defaults to
+	// localhost, or host.docker.internal for a container
+	CallbackHost string
+
+	StartTimeout    time.Duration // how long the server has to become healthy (default 60s)
+	DeliveryTimeout time.Duration // how long PublishAndWaitForDelivery waits (default 10s)
+	Env             []string      // extra environment of the server, e.g. RATE_LIMIT_PER_MINUTE=0
+}
+
+// Server is an event store started for a test
+type Server struct {
+	URL    string
+	client *Client
+	opts   Options
+}
+
+// Start returns an event store for the test, stopped and deleted when the test ends. A
+// shared server given by ServerURL is left running.
+func Start(t testing.TB, opts Options) *Server {
+	t.Helper()
+	if opts.ServerURL == "" && opts.Jar == "" && opts.Image == "" {
+		opts.ServerURL = os.Getenv(EnvServerURL)
+		opts.Jar = os.Getenv(EnvJar)
+		opts.Image = os.Getenv(EnvImage)
+	}
+	if opts.StartTimeout == 0 {
+		opts.StartTimeout = 60 * time.Second
+	}
+	if opts.DeliveryTimeout == 0 {
+		opts.DeliveryTimeout = 10 * time.Second
+	}
+
+	var url string
+	var logs func() string
+	switch {
+	case opts.ServerURL != "":
+		url = strings.TrimSuffix(opts.ServerURL, "/")
+		logs = func() string { return "" }
+	case opts.Jar != "":
+		url, logs = startJar(t, opts)
+	case opts.Image != "":
+		url, logs = startContainer(t, opts)
+		if opts.CallbackHost == "" {
+			opts.CallbackHost = "host.docker.internal"
+		}
+	default:
+		t.Skipf("no event store to test against: set %s, %s or %s", EnvServerURL, EnvJar, EnvImage)
+	}
+	if opts.CallbackHost == "" {
+		opts.CallbackHost = "localhost"
+	}
+
+	s := &Server{URL: url, client: client.NewClient(url), opts: opts}
+	if err := s.waitHealthy(opts.StartTimeout); err != nil {
+		t.Fatalf("event store at %s didn't start: %v%s", url, err, logs())
+	}
+	return s
+}
+
+// Client returns a client for the event store
+func (s *Server) Client() *Client {
+	return s.client
+}
+
+// CreateTopic creates a topic, failing the test if it can't be created
+func (s *Server) CreateTopic(t testing.TB, name string, schemas ...Schema) {
+	t.Helper()
+	if err := s.client.CreateTopic(name, schemas); err != nil {
+		t.Fatalf("failed to create topic '%s': %v", name, err)
+	}
+}
+
+// Publish publishes events in one request and returns their IDs, failing the test if
+// they can't be published
+func (s *Server) Publish(t testing.TB, events ...PublishRequest) []string {
+	t.Helper()
+	ids, err := s.client.PublishEvents(events)
+	if err != nil {
+		t.Fatalf("failed to publish %d event(s): %v", len(events), err)
+	}
+	return ids
+}
+
+func (s *Server) waitHealthy(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		health, err := s.client.GetHealth()
+		if err == nil && health.Status == "healthy" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if err == nil {
+				err = fmt.Errorf("status %s", health.Status)
+			}
+			return err
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// startJar runs the server jar on a free port, with its data in a temporary directory
+func startJar(t testing.TB, opts Options) (string, func() string) {
+	t.Helper()
+	port, err := freePort()
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	dir := t.TempDir()
+	output := &syncBuffer{}
+	process := exec.Command("java", "-jar", opts.Jar)
+	process.Env = append(os.Environ(),
+		fmt.Sprintf("PORT=%d", port),
+		"DATA_DIR="+dir+"/data",
+		"CONFIG_DIR="+dir+"/config",
+	)
+	process.Env = append(process.Env, opts.Env...)
+	process.Stdout = output
+	process.Stderr = output
+	if err := process.Start(); err != nil {
+		t.Fatalf("failed to run the event store jar %s: %v", opts.Jar, err)
+	}
+	t.Cleanup(func() {
+		process.Process.Kill()
+		process.Wait()
+	})
+	return fmt.Sprintf("http://localhost:%d", port), func() string { return "\n" + output.String() }
+}
+
+// startContainer runs the server image, publishing its port on a free port of localhost
+func startContainer(t testing.TB, opts Options) (string, func() string) {
+	t.Helper()
+	args := []string{"run", "-d",
+		"-p", fmt.Sprintf("127.0.0.1::%d", containerPort),
+		"--add-host", "host.docker.internal:host-gateway",
+		"-e", fmt.Sprintf("PORT=%d", containerPort),
+	}
+	for _, env := range opts.Env {
+		args = append(args, "-e", env)
+	}
+	id, err := docker(append(args, opts.Image)...)
+	if err != nil {
+		t.Fatalf("failed to run the event store image %s: %v", opts.Image, err)
+	}
+	t.Cleanup(func() { docker("rm", "-f", id) })
+
+	address, err := docker("port", id, fmt.Sprintf("%d/tcp", containerPort))
+	if err != nil {
+		t.Fatalf("failed to find the port of the event store container: %v", err)
+	}
+	// e.g. 127.0.0.1:49153, one line per address
+	address, _, _ = strings.Cut(address, "\n")
+	logs := func() string {
+		out, _ := docker("logs", id)
+		return "\n" + out
+	}
+	return "http://" + address, logs
+}
+
+func docker(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	command := exec.Command("docker", args...)
+	command.Stderr = &stderr
+	out, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// syncBuffer collects a process's output while it runs
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
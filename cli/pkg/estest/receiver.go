@@ -0,0 +1,143 @@
+package estest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/event-store/cli/internal/client"
+	"github.com/event-store/cli/internal/eventid"
+)
+
+// Receiver is a consumer of the event store whose callback is served by the test,
+// collecting the events delivered to it
+type Receiver struct {
+	ConsumerID string
+
+	server    *Server
+	mu        sync.Mutex
+	events    []Event
+	seen      map[string]bool
+	delivered chan struct{}
+}
+
+// NewReceiver registers a consumer of topics that receives their events published
+// from now on, served by the test. It is deleted when the test ends.
+func (s *Server) NewReceiver(t testing.TB, topics ...string) *Receiver {
+	t.Helper()
+	r := &Receiver{server: s, seen: map[string]bool{}, delivered: make(chan struct{}, 1)}
+
+	// A container reaches the callback through the host's network, not its loopback
+	address := "127.0.0.1:0"
+	if s.opts.CallbackHost != "localhost" {
+		address = ":0"
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatalf("failed to listen for deliveries: %v", err)
+	}
+	callbackServer := &httptest.Server{Listener: listener, Config: &http.Server{Handler: http.HandlerFunc(r.handle)}}
+	callbackServer.Start()
+	t.Cleanup(callbackServer.Close)
+
+	// Start after the current end of each topic so only new events are delivered
+	start := make(map[string]string, len(topics))
+	for _, name := range topics {
+		topic, err := s.client.GetTopic(name)
+		if err != nil {
+			t.Fatalf("failed to get topic '%s': %v", name, err)
+		}
+		start[name] = ""
+		if topic.Sequence > 0 {
+			start[name] = eventid.ID{Topic: name, Sequence: int64(topic.Sequence)}.String()
+		}
+	}
+	callback := fmt.Sprintf("http://%s:%d/", s.opts.CallbackHost, listener.Addr().(*net.TCPAddr).Port)
+	if r.ConsumerID, err = s.client.RegisterConsumer(callback, start); err != nil {
+		t.Fatalf("failed to register a consumer of %v: %v", topics, err)
+	}
+	t.Cleanup(func() { s.client.DeleteConsumer(r.ConsumerID) })
+	return r
+}
+
+func (r *Receiver) handle(w http.ResponseWriter, req *http.Request) {
+	var payload client.DeliveryPayload
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	for _, event := range payload.Events {
+		// Deliveries are at least once
+		if !r.seen[event.ID] {
+			r.seen[event.ID] = true
+			r.events = append(r.events, event)
+		}
+	}
+	r.mu.Unlock()
+	select {
+	case r.delivered <- struct{}{}:
+	default:
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// Events returns the events delivered so far, in the order they arrived
+func (r *Receiver) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// WaitFor waits until the events with the IDs have been delivered and returns them in
+// the order of the IDs, failing the test if they aren't delivered within the server's
+// DeliveryTimeout
+func (r *Receiver) WaitFor(t testing.TB, ids ...string) []Event {
+	t.Helper()
+	deadline := time.NewTimer(r.server.opts.DeliveryTimeout)
+	defer deadline.Stop()
+	for {
+		if events, missing := r.find(ids); missing == 0 {
+			return events
+		}
+		select {
+		case <-r.delivered:
+		case <-deadline.C:
+			_, missing := r.find(ids)
+			t.Fatalf("%d of %d event(s) not delivered to consumer %s within %s", missing, len(ids), r.ConsumerID, r.server.opts.DeliveryTimeout)
+			return nil
+		}
+	}
+}
+
+// find returns the delivered events with the IDs, and how many haven't been delivered
+func (r *Receiver) find(ids []string) ([]Event, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byID := make(map[string]Event, len(r.events))
+	for _, event := range r.events {
+		byID[event.ID] = event
+	}
+	events := make([]Event, 0, len(ids))
+	for _, id := range ids {
+		if event, ok := byID[id]; ok {
+			events = append(events, event)
+		}
+	}
+	return events, len(ids) - len(events)
+}
+
+// PublishAndWaitForDelivery publishes events and waits until they have been delivered
+// to the receiver, returning them as delivered, in order
+func PublishAndWaitForDelivery(t testing.TB, r *Receiver, events ...PublishRequest) []Event {
+	t.Helper()
+	return r.WaitFor(t, r.server.Publish(t, events...)...)
+}
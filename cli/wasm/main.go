@@ -0,0 +1,157 @@
+//go:build js && wasm
+
+// Command wasm exposes the event store client to JavaScript when compiled to WebAssembly.
+//
+// Build with:
+//
+//	GOOS=js GOARCH=wasm go build -o es-client.wasm ./wasm
+//
+// Loading the module defines globalThis.eventStore.newClient(baseURL), which returns an
+// object whose methods mirror the Go client and return Promises. Requests go through the
+// browser's fetch API.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/event-store/cli/internal/client"
+)
+
+func main() {
+	js.Global().Set("eventStore", js.ValueOf(map[string]interface{}{
+		"newClient": js.FuncOf(newClient),
+	}))
+
+	// Keep the Go runtime alive so the exported functions remain callable
+	select {}
+}
+
+// newClient creates a JavaScript wrapper around a client for the given base URL, or
+// returns an Error without one. A panic here would stop the Go runtime for good.
+func newClient(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 || args[0].Type() != js.TypeString || args[0].String() == "" {
+		return js.Global().Get("Error").New("eventStore.newClient(baseURL) requires a base URL")
+	}
+	apiClient := client.NewClient(args[0].String())
+
+	return js.ValueOf(map[string]interface{}{
+		"getTopics": async(func(args []js.Value) (interface{}, error) {
+			return apiClient.GetTopics()
+		}),
+		"getTopic": async(func(args []js.Value) (interface{}, error) {
+			return apiClient.GetTopic(stringArg(args, 0))
+		}),
+		"createTopic": async(func(args []js.Value) (interface{}, error) {
+			var schemas []client.Schema
+			if err := jsonArg(args, 1, &schemas); err != nil {
+				return nil, err
+			}
+			return nil, apiClient.CreateTopic(stringArg(args, 0), schemas)
+		}),
+		"updateTopicSchemas": async(func(args []js.Value) (interface{}, error) {
+			var schemas []client.Schema
+			if err := jsonArg(args, 1, &schemas); err != nil {
+				return nil, err
+			}
+			return nil, apiClient.UpdateTopicSchemas(stringArg(args, 0), schemas)
+		}),
+		"getEvents": async(func(args []js.Value) (interface{}, error) {
+			var query struct {
+				SinceEventID string `json:"sinceEventId"`
+				Date         string `json:"date"`
+				Limit        int    `json:"limit"`
+			}
+			if err := jsonArg(args, 1, &query); err != nil {
+				return nil, err
+			}
+			return apiClient.GetEvents(stringArg(args, 0), &client.EventsQuery{
+				SinceEventID: query.SinceEventID,
+				Date:         query.Date,
+				Limit:        query.Limit,
+			})
+		}),
+		"publishEvents": async(func(args []js.Value) (interface{}, error) {
+			var events []client.EventPublishRequest
+			if err := jsonArg(args, 0, &events); err != nil {
+				return nil, err
+			}
+			return apiClient.PublishEvents(events)
+		}),
+		"getConsumers": async(func(args []js.Value) (interface{}, error) {
+			return apiClient.GetConsumers()
+		}),
+		"registerConsumer": async(func(args []js.Value) (interface{}, error) {
+			var topics map[string]string
+			if err := jsonArg(args, 1, &topics); err != nil {
+				return nil, err
+			}
+			return apiClient.RegisterConsumer(stringArg(args, 0), topics)
+		}),
+		"deleteConsumer": async(func(args []js.Value) (interface{}, error) {
+			return nil, apiClient.DeleteConsumer(stringArg(args, 0))
+		}),
+		"getHealth": async(func(args []js.Value) (interface{}, error) {
+			return apiClient.GetHealth()
+		}),
+	})
+}
+
+// async wraps a blocking call in a JavaScript Promise. HTTP requests block on fetch, so
+// they must run on their own goroutine rather than the JavaScript event loop.
+func async(fn func(args []js.Value) (interface{}, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		handler := js.FuncOf(func(this js.Value, promiseArgs []js.Value) interface{} {
+			resolve, reject := promiseArgs[0], promiseArgs[1]
+			go func() {
+				result, err := fn(args)
+				if err != nil {
+					reject.Invoke(js.Global().Get("Error").New(err.Error()))
+					return
+				}
+				value, err := toJS(result)
+				if err != nil {
+					reject.Invoke(js.Global().Get("Error").New(err.Error()))
+					return
+				}
+				resolve.Invoke(value)
+			}()
+			return nil
+		})
+		defer handler.Release()
+		return js.Global().Get("Promise").New(handler)
+	})
+}
+
+// toJS converts a Go value to a JavaScript value via its JSON encoding
+func toJS(v interface{}) (js.Value, error) {
+	if v == nil {
+		return js.Undefined(), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return js.Undefined(), fmt.Errorf("failed to encode result: %w", err)
+	}
+	return js.Global().Get("JSON").Call("parse", string(data)), nil
+}
+
+// stringArg returns argument i as a string, or "" if absent
+func stringArg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return ""
+	}
+	return args[i].String()
+}
+
+// jsonArg decodes argument i into v via its JSON encoding; absent arguments leave v unchanged
+func jsonArg(args []js.Value, i int, v interface{}) error {
+	if i >= len(args) || args[i].IsUndefined() || args[i].IsNull() {
+		return nil
+	}
+	data := js.Global().Get("JSON").Call("stringify", args[i]).String()
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("invalid argument %d: %w", i, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+// Run with:
+//
+//	GOOS=js GOARCH=wasm go test -exec="$(go env GOROOT)/lib/wasm/go_js_wasm_exec" ./wasm
+
+func TestNewClientWithoutBaseURL(t *testing.T) {
+	errorType := js.Global().Get("Error")
+	tests := []struct {
+		name string
+		args []js.Value
+	}{
+		{"no arguments", nil},
+		{"empty base URL", []js.Value{js.ValueOf("")}},
+		{"number", []js.Value{js.ValueOf(8000)}},
+		{"undefined", []js.Value{js.Undefined()}},
+	}
+	for _, test := range tests {
+		result := newClient(js.Undefined(), test.args).(js.Value)
+		if !result.InstanceOf(errorType) || result.Get("message").String() != "eventStore.newClient(baseURL) requires a base URL" {
+			t.Errorf("newClient(%s) = %v, want an Error", test.name, result)
+		}
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	store := newClient(js.Undefined(), []js.Value{js.ValueOf("http://localhost:8000")}).(js.Value)
+	for _, method := range []string{"getTopics", "getTopic", "createTopic", "updateTopicSchemas", "getEvents", "publishEvents", "getConsumers", "registerConsumer", "deleteConsumer", "getHealth"} {
+		if store.Get(method).Type() != js.TypeFunction {
+			t.Errorf("client.%s is a %s, want a function", method, store.Get(method).Type())
+		}
+	}
+}
+
+// TestInvalidArgument checks that a method given an argument it can't decode returns a
+// rejected Promise
+func TestInvalidArgument(t *testing.T) {
+	store := newClient(js.Undefined(), []js.Value{js.ValueOf("http://localhost:8000")}).(js.Value)
+	promise := store.Call("publishEvents", js.ValueOf("not events"))
+
+	settled := make(chan js.Value, 1)
+	onResolve := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		settled <- js.ValueOf("resolved")
+		return nil
+	})
+	defer onResolve.Release()
+	onReject := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		settled <- args[0]
+		return nil
+	})
+	defer onReject.Release()
+	promise.Call("then", onResolve, onReject)
+
+	reason := <-settled
+	if !reason.InstanceOf(js.Global().Get("Error")) {
+		t.Fatalf("publishEvents(\"not events\") settled with %v, want an Error", reason)
+	}
+	if message := reason.Get("message").String(); !strings.HasPrefix(message, "invalid argument 0:") {
+		t.Errorf("publishEvents(\"not events\") rejected with %q, want an invalid argument", message)
+	}
+}
@@ -0,0 +1,163 @@
+#!/usr/bin/env bash
+# Test commands that work on many consumers or events: consumer delete --all, event
+# export and event tail
+
+set -euo pipefail
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+
+source "$SCRIPT_DIR/../helpers/cli.sh"
+source "$SCRIPT_DIR/../helpers/assertions.sh"
+
+# Helper to create a topic and publish three user.created events to it
+create_topic_with_events() {
+    local topic_name=$1
+    local schemas_file="$SCRIPT_DIR/../fixtures/schemas.json"
+
+    es_json topic create --name "$topic_name" --schemas-file "$schemas_file" > /dev/null
+    es_json event publish --json "[
+        {\"topic\":\"$topic_name\",\"type\":\"user.created\",\"payload\":{\"id\":\"1\",\"name\":\"Alice\",\"email\":\"alice@example.com\"}},
+        {\"topic\":\"$topic_name\",\"type\":\"user.created\",\"payload\":{\"id\":\"2\",\"name\":\"Bob\",\"email\":\"bob@example.com\"}},
+        {\"topic\":\"$topic_name\",\"type\":\"user.created\",\"payload\":{\"id\":\"3\",\"name\":\"Carol\",\"email\":\"carol@example.com\"}}
+    ]" > /dev/null
+}
+
+test_consumer_delete_all() {
+    local topic_name="test-topic-delete-all-$(date +%s)"
+    local schemas_file="$SCRIPT_DIR/../fixtures/schemas.json"
+
+    echo "  Creating topic and registering two consumers..."
+    es_json topic create --name "$topic_name" --schemas-file "$schemas_file" > /dev/null
+    es_json consumer register --callback "http://localhost:3001/webhook" --topics "$topic_name:null" > /dev/null
+    es_json consumer register --callback "http://localhost:3002/webhook" --topics "$topic_name:null" > /dev/null
+
+    local count=$(es_json consumer list | jq '.consumers | length')
+    if [ "$count" -lt 2 ]; then
+        echo "  Expected at least 2 consumers, got $count"
+        return 1
+    fi
+
+    echo "  Deleting every consumer with --dry-run..."
+    local dry_run_output=$(es_json consumer delete --all --dry-run)
+    if ! assert_json_contains "$dry_run_output" '.dryRun' "true"; then
+        return 1
+    fi
+    if ! assert_json_array_length "$dry_run_output" '.consumers' "$count"; then
+        return 1
+    fi
+    if ! assert_json_array_length "$dry_run_output" '[.consumers[] | select(.status == "would delete")]' "$count"; then
+        return 1
+    fi
+    if ! assert_json_array_length "$(es_json consumer list)" '.consumers' "$count"; then
+        return 1
+    fi
+
+    echo "  Deleting every consumer..."
+    local delete_output=$(es_json consumer delete --all)
+    if ! assert_json_contains "$delete_output" '.deleted' "$count"; then
+        return 1
+    fi
+    if ! assert_json_contains "$delete_output" '.failed' "0"; then
+        return 1
+    fi
+    if ! assert_json_array_length "$(es_json consumer list)" '.consumers' "0"; then
+        return 1
+    fi
+
+    echo "  ✓ Consumer delete --all test passed"
+    return 0
+}
+
+test_event_export() {
+    local topic_name="test-topic-export-$(date +%s)"
+    local out_dir=$(mktemp -d)
+    local out_file="$out_dir/$topic_name.ndjson"
+
+    echo "  Creating topic with events: $topic_name"
+    create_topic_with_events "$topic_name"
+
+    echo "  Exporting the topic..."
+    local export_output=$(es_json event export "$topic_name" --out "$out_file" --verify)
+
+    if ! assert_json_contains "$export_output" '.events' "3"; then
+        rm -rf "$out_dir"
+        return 1
+    fi
+    if ! assert_json_contains "$export_output" '.verified' "true"; then
+        rm -rf "$out_dir"
+        return 1
+    fi
+    if ! assert_json_contains "$export_output" '.lastEventId' "$topic_name-3"; then
+        rm -rf "$out_dir"
+        return 1
+    fi
+
+    local lines=$(wc -l < "$out_file" | tr -d ' ')
+    if [ "$lines" != "3" ]; then
+        echo "  Expected 3 exported events, got $lines"
+        rm -rf "$out_dir"
+        return 1
+    fi
+    if ! assert_json_contains "$(sed -n 2p "$out_file")" '.payload.name' "Bob"; then
+        rm -rf "$out_dir"
+        return 1
+    fi
+
+    local manifest="$out_file.manifest.json"
+    if [ ! -f "$manifest" ]; then
+        echo "  Manifest $manifest was not written"
+        rm -rf "$out_dir"
+        return 1
+    fi
+    local checksum=$(sha256sum "$out_file" | cut -d' ' -f1)
+    if ! assert_json_contains "$(cat "$manifest")" '.sha256' "$checksum"; then
+        rm -rf "$out_dir"
+        return 1
+    fi
+
+    rm -rf "$out_dir"
+    echo "  ✓ Event export test passed"
+    return 0
+}
+
+test_event_tail() {
+    local topic_name="test-topic-tail-$(date +%s)"
+    local summary_file=$(mktemp)
+
+    echo "  Creating topic with events: $topic_name"
+    create_topic_with_events "$topic_name"
+
+    echo "  Tailing the topic after its first event..."
+    local tail_output=$(es_json event tail "$topic_name" --from-event-id "$topic_name-1" \
+        --max-events 2 --max-duration 20s --interval 200ms 2> "$summary_file")
+    local summary=$(cat "$summary_file")
+    rm -f "$summary_file"
+
+    local lines=$(echo "$tail_output" | grep -c .)
+    if [ "$lines" != "2" ]; then
+        echo "  Expected 2 tailed events, got $lines"
+        echo "  Output: $tail_output"
+        return 1
+    fi
+    if ! assert_json_contains "$(echo "$tail_output" | sed -n 1p)" '.id' "$topic_name-2"; then
+        return 1
+    fi
+    if ! assert_json_contains "$(echo "$tail_output" | sed -n 2p)" '.payload.name' "Carol"; then
+        return 1
+    fi
+
+    if ! assert_json_contains "$summary" '.events' "2"; then
+        return 1
+    fi
+    if ! assert_json_contains "$summary" '.stoppedBy' "max-events"; then
+        return 1
+    fi
+
+    echo "  ✓ Event tail test passed"
+    return 0
+}
+
+# Run tests
+test_consumer_delete_all
+test_event_export
+test_event_tail
@@ -0,0 +1,154 @@
+#!/usr/bin/env bash
+# Test error reports and exit codes: usage, local, unreachable, 4xx and 5xx failures
+
+set -euo pipefail
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+
+source "$SCRIPT_DIR/../helpers/cli.sh"
+source "$SCRIPT_DIR/../helpers/assertions.sh"
+
+# Helper to check an error report printed with --output json: it has error, code,
+# httpStatus and hint keys, with the code and HTTP status given
+assert_error_report() {
+    local output="$1"
+    local code="$2"
+    local http_status="$3"
+
+    for key in error code httpStatus hint; do
+        if ! assert_json_has_key "$output" "has(\"$key\")"; then
+            return 1
+        fi
+    done
+    if [ -n "$code" ] && ! assert_json_contains "$output" '.code' "$code"; then
+        return 1
+    fi
+    if ! assert_json_contains "$output" '.httpStatus' "$http_status"; then
+        return 1
+    fi
+    return 0
+}
+
+test_usage_error() {
+    echo "  Running a command without its argument..."
+    local output status
+    output=$(es_json topic show 2>/dev/null) && status=0 || status=$?
+
+    if ! assert_exit_code "$status" 2; then
+        return 1
+    fi
+    if ! assert_error_report "$output" "usage" "null"; then
+        return 1
+    fi
+
+    echo "  ✓ Usage error test passed"
+    return 0
+}
+
+test_local_error() {
+    echo "  Creating a topic from a schemas file that doesn't exist..."
+    local output status
+    output=$(es_json topic create --name "test-topic-errors-$(date +%s)" --schemas-file /nonexistent/schemas.json 2>/dev/null) && status=0 || status=$?
+
+    if ! assert_exit_code "$status" 1; then
+        return 1
+    fi
+    if ! assert_error_report "$output" "error" "null"; then
+        return 1
+    fi
+
+    echo "  ✓ Local error test passed"
+    return 0
+}
+
+test_unreachable_error() {
+    echo "  Listing topics on a server that isn't running..."
+    local output status
+    output=$("$CLI_BIN" --server-url http://127.0.0.1:1 --output json topic list 2>/dev/null) && status=0 || status=$?
+
+    if ! assert_exit_code "$status" 3; then
+        return 1
+    fi
+    if ! assert_error_report "$output" "unreachable" "null"; then
+        return 1
+    fi
+
+    echo "  ✓ Unreachable error test passed"
+    return 0
+}
+
+test_rejected_error() {
+    echo "  Showing a topic that doesn't exist..."
+    local output status
+    output=$(es_json topic show "test-topic-missing-$(date +%s)" 2>/dev/null) && status=0 || status=$?
+
+    if ! assert_exit_code "$status" 4; then
+        return 1
+    fi
+    # The code is the server's own when it gives one, else not_found
+    if ! assert_error_report "$output" "" "404"; then
+        return 1
+    fi
+    if ! assert_json_has_key "$output" '.code | length > 0'; then
+        return 1
+    fi
+
+    echo "  ✓ Rejected error test passed"
+    return 0
+}
+
+test_server_error() {
+    if ! command -v python3 &> /dev/null; then
+        echo "  Skipping server error test: python3 is not installed"
+        return 0
+    fi
+
+    echo "  Listing topics on a server that fails every request..."
+    local port=18099
+    python3 - "$port" > /dev/null 2>&1 <<'EOF' &
+import sys
+from http.server import BaseHTTPRequestHandler, HTTPServer
+
+class Failing(BaseHTTPRequestHandler):
+    def do_GET(self):
+        self.send_response(500)
+        self.send_header("Content-Type", "application/json")
+        self.end_headers()
+        self.wfile.write(b'{"error":"Internal server error"}')
+
+HTTPServer(("127.0.0.1", int(sys.argv[1])), Failing).serve_forever()
+EOF
+    local pid=$!
+    local elapsed=0
+    while ! curl -s "http://127.0.0.1:$port/" > /dev/null 2>&1; do
+        sleep 0.5
+        elapsed=$((elapsed + 1))
+        if [ $elapsed -ge 10 ]; then
+            kill "$pid" 2>/dev/null || true
+            echo "  Failing server did not start"
+            return 1
+        fi
+    done
+
+    local output status
+    output=$("$CLI_BIN" --server-url "http://127.0.0.1:$port" --output json topic list 2>/dev/null) && status=0 || status=$?
+    kill "$pid" 2>/dev/null || true
+    wait "$pid" 2>/dev/null || true
+
+    if ! assert_exit_code "$status" 5; then
+        return 1
+    fi
+    if ! assert_error_report "$output" "" "500"; then
+        return 1
+    fi
+
+    echo "  ✓ Server error test passed"
+    return 0
+}
+
+# Run tests
+test_usage_error
+test_local_error
+test_unreachable_error
+test_rejected_error
+test_server_error